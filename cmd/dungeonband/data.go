@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+)
+
+// Exit codes for the data subcommand.
+const (
+	exitOK      = 0
+	exitInvalid = 1 // validate found problems
+	exitUsage   = 2 // bad arguments or unreadable data
+)
+
+const dataUsage = `usage: dungeonband data [-data dir] <verb>
+
+verbs:
+  list abilities|enemies|classes   print a table of IDs and key stats
+  show <id>                        print the resolved definition as JSON
+  validate                         check embedded data plus overrides`
+
+// runData implements "dungeonband data ...". It only reads game data and
+// never touches the terminal or telemetry. Returns the process exit code.
+func runData(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("data", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	dataDir := fs.String("data", "", "Directory of JSON overrides merged over the embedded data")
+	fs.Usage = func() { fmt.Fprintln(stderr, dataUsage) }
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	verb := fs.Arg(0)
+	if verb == "" {
+		fs.Usage()
+		return exitUsage
+	}
+
+	d, err := gamedata.LoadData(*dataDir)
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return exitUsage
+	}
+	for _, w := range d.Warnings {
+		fmt.Fprintln(stderr, "warning:", w)
+	}
+
+	switch verb {
+	case "list":
+		return dataList(d, fs.Arg(1), stdout, stderr)
+	case "show":
+		return dataShow(d, fs.Arg(1), stdout, stderr)
+	case "validate":
+		return dataValidate(d, stdout)
+	default:
+		fmt.Fprintf(stderr, "unknown verb %q\n", verb)
+		fs.Usage()
+		return exitUsage
+	}
+}
+
+// dataList prints a table of one kind of definition.
+func dataList(d *gamedata.Data, kind string, stdout, stderr io.Writer) int {
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	switch kind {
+	case "abilities":
+		fmt.Fprintln(tw, "ID\tNAME\tEFFECT\tTARGET\tPOWER\tMP")
+		for _, a := range d.Abilities {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%d\n", a.ID, a.Name, a.EffectType, a.TargetType, a.BasePower, a.MPCost)
+		}
+	case "enemies":
+		fmt.Fprintln(tw, "ID\tNAME\tHP\tATK\tDEF\tWEIGHT\tROW\tABILITIES")
+		for _, e := range d.Enemies {
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%d\t%s\t%s\n", e.ID, e.Name, e.HP, e.Attack, e.Defense,
+				e.SpawnWeight, e.StartingRow(), strings.Join(e.Abilities, ","))
+		}
+	case "classes":
+		fmt.Fprintln(tw, "ID\tNAME\tHP\tMP\tATK\tDEF\tMAG\tABILITIES")
+		for _, c := range d.Classes {
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%d\t%d\t%s\n", c.ID, c.Name, c.HP, c.MP, c.Attack, c.Defense,
+				c.Magic, strings.Join(c.Abilities, ","))
+		}
+	default:
+		fmt.Fprintf(stderr, "list: unknown kind %q (want abilities, enemies or classes)\n", kind)
+		return exitUsage
+	}
+	tw.Flush()
+	return exitOK
+}
+
+// dataShow pretty-prints every definition with the given ID.
+func dataShow(d *gamedata.Data, id string, stdout, stderr io.Writer) int {
+	if id == "" {
+		fmt.Fprintln(stderr, "show: missing id")
+		return exitUsage
+	}
+
+	var matches []any
+	var sources []string
+	for i := range d.Abilities {
+		if d.Abilities[i].ID == id {
+			matches = append(matches, d.Abilities[i])
+			sources = append(sources, d.Source(gamedata.AbilitiesFileName, id))
+		}
+	}
+	for i := range d.Enemies {
+		if d.Enemies[i].ID == id {
+			matches = append(matches, d.Enemies[i])
+			sources = append(sources, d.Source(gamedata.EnemiesFileName, id))
+		}
+	}
+	for i := range d.Classes {
+		if d.Classes[i].ID == id {
+			matches = append(matches, d.Classes[i])
+			sources = append(sources, d.Source(gamedata.ClassesFileName, id))
+		}
+	}
+
+	if len(matches) == 0 {
+		fmt.Fprintf(stderr, "show: no ability, enemy or class with id %q\n", id)
+		return exitUsage
+	}
+	for i, m := range matches {
+		out, err := json.MarshalIndent(m, "", "  ")
+		if err != nil {
+			fmt.Fprintln(stderr, "error:", err)
+			return exitUsage
+		}
+		fmt.Fprintf(stdout, "# %s\n%s\n", sources[i], out)
+	}
+	return exitOK
+}
+
+// dataValidate lists every validation issue. Returns exitInvalid if any.
+func dataValidate(d *gamedata.Data, stdout io.Writer) int {
+	issues := gamedata.ValidateAll(d)
+	for _, issue := range issues {
+		fmt.Fprintln(stdout, issue)
+	}
+	if len(issues) > 0 {
+		fmt.Fprintf(stdout, "%d problem(s) found\n", len(issues))
+		return exitInvalid
+	}
+	fmt.Fprintf(stdout, "OK: %d abilities, %d enemies, %d classes\n", len(d.Abilities), len(d.Enemies), len(d.Classes))
+	return exitOK
+}
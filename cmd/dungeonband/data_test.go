@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// runDataArgs runs the data subcommand and captures its output.
+func runDataArgs(args ...string) (code int, stdout, stderr string) {
+	var out, errOut bytes.Buffer
+	code = runData(args, &out, &errOut)
+	return code, out.String(), errOut.String()
+}
+
+func TestDataListTable(t *testing.T) {
+	code, out, _ := runDataArgs("list", "classes")
+	if code != exitOK {
+		t.Fatalf("exit code = %d, want %d", code, exitOK)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("got %d lines, want header plus 4 classes:\n%s", len(lines), out)
+	}
+	if want := "ID       NAME     HP  MP  ATK  DEF  MAG  ABILITIES"; lines[0] != want {
+		t.Errorf("header = %q, want %q", lines[0], want)
+	}
+	if want := "warrior  Warrior  30  0   8    6    0    attack,defend,power_attack,taunt"; lines[1] != want {
+		t.Errorf("first row = %q, want %q", lines[1], want)
+	}
+}
+
+func TestDataListUnknownKind(t *testing.T) {
+	code, _, errOut := runDataArgs("list", "potions")
+	if code != exitUsage || !strings.Contains(errOut, "potions") {
+		t.Errorf("exit code = %d, stderr = %q; want usage error", code, errOut)
+	}
+}
+
+func TestDataShow(t *testing.T) {
+	code, out, _ := runDataArgs("show", "fireball")
+	if code != exitOK {
+		t.Fatalf("exit code = %d, want %d", code, exitOK)
+	}
+	for _, want := range []string{"# abilities.json", `"id": "fireball"`, `"mpCost": 5`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	if code, _, _ := runDataArgs("show", "no_such_thing"); code != exitUsage {
+		t.Errorf("unknown id exit code = %d, want %d", code, exitUsage)
+	}
+}
+
+func TestDataValidateExitCodes(t *testing.T) {
+	if code, out, _ := runDataArgs("validate"); code != exitOK || !strings.HasPrefix(out, "OK:") {
+		t.Errorf("embedded data: exit code = %d, output = %q", code, out)
+	}
+
+	dir := t.TempDir()
+	override := `{"classes": [{"id": "warrior", "name": "Warrior", "symbol": "W", "hp": 30, "abilities": ["attack", "smite"]}]}`
+	if err := os.WriteFile(filepath.Join(dir, "classes.json"), []byte(override), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	code, out, _ := runDataArgs("-data", dir, "validate")
+	if code != exitInvalid {
+		t.Errorf("bad override: exit code = %d, want %d", code, exitInvalid)
+	}
+	if want := filepath.Join(dir, "classes.json") + `: warrior: unknown ability "smite"`; !strings.Contains(out, want) {
+		t.Errorf("output missing %q:\n%s", want, out)
+	}
+
+	if code, _, _ := runDataArgs("-data", dir, "show", "warrior"); code != exitOK {
+		t.Errorf("show with overrides: exit code = %d, want %d", code, exitOK)
+	}
+}
+
+func TestDataUsageErrors(t *testing.T) {
+	for _, args := range [][]string{{}, {"explode"}, {"-data", filepath.Join(t.TempDir(), "missing"), "-bogus"}} {
+		if code, _, _ := runDataArgs(args...); code != exitUsage {
+			t.Errorf("runData(%q) exit code = %d, want %d", args, code, exitUsage)
+		}
+	}
+}
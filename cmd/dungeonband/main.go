@@ -12,15 +12,57 @@ import (
 
 	"github.com/joho/godotenv"
 
+	"github.com/samdwyer/dungeonband/internal/audio"
 	"github.com/samdwyer/dungeonband/internal/game"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+	"github.com/samdwyer/dungeonband/internal/spectate"
 	"github.com/samdwyer/dungeonband/internal/telemetry"
+	"github.com/samdwyer/dungeonband/internal/ui"
+	"github.com/samdwyer/dungeonband/internal/world"
 )
 
 func main() {
+	// The data subcommand inspects embedded data without starting the game
+	if len(os.Args) > 1 && os.Args[1] == "data" {
+		os.Exit(runData(os.Args[2:], os.Stdout, os.Stderr))
+	}
+	// The watch subcommand shows someone else's game instead of starting one
+	if len(os.Args) > 1 && os.Args[1] == "watch" {
+		os.Exit(runWatch(os.Args[2:], os.Stderr))
+	}
+	// The seeds subcommand checks an exported seed log against its master seed
+	if len(os.Args) > 1 && os.Args[1] == "seeds" {
+		os.Exit(runSeeds(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	// Parse command-line flags
 	seedFlag := flag.Int64("seed", 0, "Random seed for reproducible dungeon generation (0 = auto)")
+	noDescendPrompt := flag.Bool("no-descend-prompt", false, "Descend immediately when stepping on stairs")
+	verboseTelemetry := flag.Bool("verbose-telemetry", false, "Record per-room and per-corridor dungeon generation events")
+	auditRolls := flag.Bool("audit-rolls", false, "Record every combat roll with its purpose in combat telemetry")
+	adaptive := flag.Bool("adaptive", false, "Adjust enemy counts to how well the party is doing")
+	focusKills := flag.Bool("focus-kills", false, "Start attack targeting on an enemy the ability can kill")
+	difficulty := flag.String("difficulty", "normal", "Starting supplies: easy, normal, hard or nightmare")
+	damageFormula := flag.String("damage-formula", "", "Override the difficulty's damage formula: additive or multiplicative")
+	noFlavor := flag.Bool("no-flavor", false, "Keep ambient flavor messages out of the log")
+	showInitials := flag.Bool("initials", false, "Draw party members in combat by the initial of their name instead of their class symbol")
+	autosave := flag.Bool("autosave", false, "Save the run each time the party descends or wins a fight")
+	resume := flag.Bool("continue", false, "Resume the run from the last autosave")
+	demo := flag.Bool("demo", false, "Let the party play itself until it dies or reaches floor 4")
+	spectateAddr := flag.String("spectate", "", "Stream the game to viewers on this TCP address, e.g. :7777 (watch with \"dungeonband watch host:port\")")
+	debug := flag.Bool("debug", false, "Show the debug overlay and add a seeds panel to the pause menu for checking the run's sub-seeds")
+	planActions := flag.Bool("plan-actions", false, "Choose the whole party's actions each round before any of them resolve")
+	hardcore := flag.Bool("hardcore", false, "No rewinding combat turns and no retrying lost encounters")
+	dataDir := flag.String("data", "", "Directory of JSON overrides merged over the embedded data (reload with Ctrl+R in -debug)")
+	enemyVisibility := flag.String("enemy-visibility", "sight", "Which enemies are shown: sight, room (only the party's room) or all (debug)")
+	screenshotSeed := flag.Int64("screenshot", 0, "Print the first frame for this seed as ANSI text and exit")
 	flag.Parse()
 
+	// Screenshot mode renders one frame off-screen, with no terminal or telemetry
+	if *screenshotSeed != 0 {
+		os.Exit(runScreenshot(*screenshotSeed, os.Stdout, os.Stderr))
+	}
+
 	// Load .env file for local development
 	// This makes HONEYCOMB_DUNGEONBAND_API_KEY available
 	if err := godotenv.Load(); err != nil {
@@ -37,6 +79,7 @@ func main() {
 	ctx := context.Background()
 
 	// Initialize telemetry
+	telemetry.SetVerbose(*verboseTelemetry)
 	shutdown, err := telemetry.Setup(ctx)
 	if err != nil {
 		log.Printf("Warning: telemetry setup failed: %v", err)
@@ -50,20 +93,219 @@ func main() {
 		}()
 	}
 
+	// The screen outlives individual runs so an abandoned run can return to
+	// the title screen without re-initializing the terminal
+	screen, err := ui.NewScreen()
+	if err != nil {
+		log.Fatalf("Failed to initialize screen: %v", err)
+	}
+	defer restoreOnPanic(screen)
+
 	// Create game config with seed
 	cfg := game.Config{
-		Seed: seed,
+		Seed:               seed,
+		SkipDescendConfirm: *noDescendPrompt,
+		AuditRolls:         *auditRolls,
+		AdaptiveDifficulty: *adaptive,
+		FocusKills:         *focusKills,
+		Difficulty:         *difficulty,
+		DamageFormula:      gamedata.DamageFormula(*damageFormula),
+		HideFlavor:         *noFlavor,
+		ShowInitials:       *showInitials,
+		Demo:               *demo,
+		Debug:              *debug,
+		PlanActions:        *planActions,
+		Hardcore:           *hardcore,
+		EnemyVisibility:    world.Visibility(*enemyVisibility),
+		DataDir:            *dataDir,
+		EnemyActionDelay:   game.DefaultEnemyActionDelay,
+	}
+
+	// Autosaves go to one fixed slot next to the profile
+	if *autosave || *resume {
+		savePath, err := game.AutosavePath()
+		if err != nil {
+			log.Fatalf("No autosave location: %v", err)
+		}
+		if *autosave {
+			cfg.AutosavePath = savePath
+		}
+		if *resume {
+			if err := resumeFrom(&cfg, savePath); err != nil {
+				log.Fatalf("Failed to load autosave: %v", err)
+			}
+		}
 	}
 
-	// Create and run game
-	g, err := game.New(cfg)
+	// The profile remembers whether to keep suggesting the tutorial
+	profilePath, err := game.ProfilePath()
 	if err != nil {
-		log.Fatalf("Failed to initialize game: %v", err)
+		log.Printf("Warning: no profile location: %v (tutorial progress won't be saved)", err)
 	}
 
-	if err := g.Run(ctx); err != nil {
+	display := game.NewTerminalDisplay(screen)
+	if *spectateAddr != "" {
+		server, err := spectate.Listen(*spectateAddr)
+		if err != nil {
+			screen.Close()
+			log.Fatalf("Failed to start spectator server: %v", err)
+		}
+		defer server.Close()
+		if display, err = game.NewSpectatorDisplay(display, server); err != nil {
+			screen.Close()
+			log.Fatalf("Failed to start spectator display: %v", err)
+		}
+	}
+
+	// Route SIGTERM through the normal quit path, on the title screen too
+	stopSignals := game.WatchSignals(display)
+	defer stopSignals()
+
+	run := runGames
+	if cfg.Demo {
+		run = runDemo
+	}
+	if err := run(ctx, screen, display, cfg, profilePath); err != nil {
+		screen.Close()
 		log.Fatalf("Game error: %v", err)
 	}
+	screen.Close()
+}
+
+// screenCloser is the part of the screen restoreOnPanic needs.
+type screenCloser interface {
+	Close()
+}
+
+// restoreOnPanic gives the terminal back if the game panics, so the panic
+// and its stack print to a usable shell instead of a raw-mode screen, then
+// re-panics with the original value. It must be deferred directly, since
+// recover only works there.
+func restoreOnPanic(screen screenCloser) {
+	if r := recover(); r != nil {
+		screen.Close()
+		panic(r)
+	}
+}
+
+// runGames plays runs on the shared screen until the player quits.
+// Abandoning a run or finishing the tutorial returns to the title screen.
+// Players who haven't finished the tutorial see the title screen first,
+// where it is suggested.
+func runGames(ctx context.Context, screen *ui.Screen, display game.Display, cfg game.Config, profilePath string) error {
+	profile := loadProfile(profilePath)
+	if player := audio.New(profile.Audio, game.BellRinger(display)); player != nil {
+		cfg.Audio = player
+	}
+	cfg.HideFlavor = cfg.HideFlavor || profile.HideFlavor
+	cfg.ShowInitials = cfg.ShowInitials || profile.ShowInitials
+
+	// Offer a fresh random seed rather than replaying the previous one
+	newSeed := func() int64 { return time.Now().UnixNano() }
+
+	showTitle := !profile.TutorialDone && cfg.Resume == nil
+	for {
+		if showTitle {
+			savePath, err := game.AutosavePath()
+			canContinue := err == nil && fileExists(savePath)
+			choice, seed := game.ShowTitle(screen, cfg.Seed, newSeed, !profile.TutorialDone, canContinue)
+			if choice == game.TitleQuit {
+				return nil
+			}
+			if choice == game.TitleContinue {
+				if err := resumeFrom(&cfg, savePath); err != nil {
+					log.Printf("Warning: failed to load autosave: %v", err)
+					continue
+				}
+				seed = cfg.Seed
+			}
+			if choice == game.TitlePartySetup {
+				cfg.Seed = seed
+				var quit bool
+				if cfg.PartyClasses, quit = game.ShowPartySetup(screen, cfg.PartyClasses, cfg.DataDir); quit {
+					return nil
+				}
+				continue
+			}
+			cfg.Seed = seed
+			cfg.Tutorial = choice == game.TitleTutorial
+		}
+
+		g, err := game.New(cfg, display)
+		if err != nil {
+			return fmt.Errorf("failed to initialize game: %w", err)
+		}
+		if err := g.Run(ctx); err != nil {
+			return err
+		}
+
+		if g.TutorialCompleted() && !profile.TutorialDone {
+			profile.TutorialDone = true
+		}
+		profile.AddAbilityUses(g.AbilityUses())
+		saveProfile(profilePath, profile)
+		cfg.Seed = newSeed()
+		cfg.Resume = nil
+		if g.NewRunRequested() {
+			showTitle = false
+			cfg.Tutorial = false
+			continue
+		}
+		if !g.Abandoned() && !g.TutorialCompleted() {
+			return nil
+		}
+		showTitle = true
+	}
+}
+
+// resumeFrom points cfg at the save at path, so the next run continues it.
+func resumeFrom(cfg *game.Config, path string) error {
+	save, err := game.LoadSave(path)
+	if err != nil {
+		return err
+	}
+	cfg.Resume = save
+	cfg.Seed = save.Seed
+	cfg.Difficulty = save.Difficulty
+	return nil
+}
+
+// fileExists reports whether there is a file at path.
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// runDemo plays a single self-playing run, skipping the title screen and
+// leaving the profile alone.
+func runDemo(ctx context.Context, _ *ui.Screen, display game.Display, cfg game.Config, _ string) error {
+	g, err := game.New(cfg, display)
+	if err != nil {
+		return fmt.Errorf("failed to initialize game: %w", err)
+	}
+	return g.Run(ctx)
+}
+
+// loadProfile reads the player profile, falling back to a fresh one.
+func loadProfile(path string) game.Profile {
+	if path == "" {
+		return game.Profile{}
+	}
+	profile, err := game.LoadProfile(path)
+	if err != nil {
+		log.Printf("Warning: failed to load profile: %v", err)
+	}
+	return profile
+}
+
+// saveProfile writes the player profile, logging failures.
+func saveProfile(path string, profile game.Profile) {
+	if path == "" {
+		return
+	}
+	if err := profile.Save(path); err != nil {
+		log.Printf("Warning: failed to save profile: %v", err)
+	}
 }
 
 // determineSeed returns the seed to use for random number generation.
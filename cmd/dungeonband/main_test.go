@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/samdwyer/dungeonband/internal/game"
+)
+
+// fakeScreen records whether it was closed.
+type fakeScreen struct {
+	closed bool
+}
+
+func (s *fakeScreen) Close() { s.closed = true }
+
+// panickingDisplay is a headless display whose input blows up mid-loop.
+type panickingDisplay struct {
+	game.NullDisplay
+}
+
+func (panickingDisplay) PollEvent() tcell.Event { panic("input exploded") }
+
+func TestRestoreOnPanicClosesScreenAndRepanics(t *testing.T) {
+	screen := &fakeScreen{}
+	g, err := game.New(game.Config{Seed: 1}, panickingDisplay{})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	var recovered any
+	func() {
+		defer func() { recovered = recover() }()
+		defer restoreOnPanic(screen)
+		g.Run(context.Background())
+	}()
+
+	if !screen.closed {
+		t.Error("screen was not closed after the panic")
+	}
+	if recovered != "input exploded" {
+		t.Errorf("re-panicked with %v, want the original panic value", recovered)
+	}
+}
+
+func TestRestoreOnPanicLeavesScreenOpenWithoutPanic(t *testing.T) {
+	screen := &fakeScreen{}
+	func() {
+		defer restoreOnPanic(screen)
+	}()
+	if screen.closed {
+		t.Error("screen closed without a panic; main closes it itself")
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/samdwyer/dungeonband/internal/game"
+	"github.com/samdwyer/dungeonband/internal/ui"
+	"github.com/samdwyer/dungeonband/internal/world"
+)
+
+// screenshotHeight leaves room below the map for messages.
+const screenshotHeight = world.DefaultHeight + 2
+
+// runScreenshot renders the first frame of a game with the given seed to an
+// off-screen buffer and writes it to w as ANSI-colored text. Telemetry is not
+// initialized. Returns the process exit code.
+func runScreenshot(seed int64, w, stderr io.Writer) int {
+	screen, err := ui.NewBufferScreen(world.DefaultWidth, screenshotHeight)
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+	defer screen.Close()
+
+	if err := game.Screenshot(context.Background(), game.Config{Seed: seed}, game.NewTerminalDisplay(screen)); err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+
+	frame, err := screen.Snapshot()
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+	fmt.Fprint(w, frame)
+	return 0
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/samdwyer/dungeonband/internal/world"
+)
+
+// ansiEscape matches SGR escape sequences.
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func TestScreenshotShowsPartyAndState(t *testing.T) {
+	var out, errOut bytes.Buffer
+	if code := runScreenshot(12345, &out, &errOut); code != 0 {
+		t.Fatalf("exit code = %d, stderr = %q", code, errOut.String())
+	}
+
+	if !strings.Contains(out.String(), "\x1b[") {
+		t.Error("screenshot should include ANSI color codes")
+	}
+
+	plain := ansiEscape.ReplaceAllString(out.String(), "")
+	lines := strings.Split(strings.TrimRight(plain, "\n"), "\n")
+	if len(lines) < world.DefaultHeight {
+		t.Fatalf("got %d lines, want at least the %d map rows", len(lines), world.DefaultHeight)
+	}
+	if !strings.HasPrefix(lines[0], "EXPLORE") {
+		t.Errorf("first line = %q, want the EXPLORE state indicator", lines[0])
+	}
+	if !strings.Contains(lines[0], "Seed:12345") {
+		t.Errorf("first line = %q, want the seed", lines[0])
+	}
+	if !strings.Contains(plain, "&") {
+		t.Error("screenshot should contain the party glyph '&'")
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/samdwyer/dungeonband/internal/game"
+	"github.com/samdwyer/dungeonband/internal/seed"
+)
+
+const seedsUsage = `usage: dungeonband seeds verify [file]
+
+Recomputes every sub-seed in a seed log exported from the pause menu (run
+with -debug) and checks it against the log's master seed. The file
+defaults to the last export.`
+
+// runSeeds implements "dungeonband seeds verify". Returns the process exit
+// code: exitInvalid if any sub-seed doesn't match its derivation.
+func runSeeds(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("seeds", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() { fmt.Fprintln(stderr, seedsUsage) }
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.Arg(0) != "verify" || fs.NArg() > 2 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	path := fs.Arg(1)
+	if path == "" {
+		var err error
+		if path, err = game.SeedLogPath(); err != nil {
+			fmt.Fprintln(stderr, "error:", err)
+			return exitUsage
+		}
+	}
+	log, err := seed.ReadLog(path)
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return exitUsage
+	}
+	if err := log.Verify(); err != nil {
+		fmt.Fprintln(stdout, "MISMATCH:", err)
+		return exitInvalid
+	}
+	fmt.Fprintf(stdout, "OK: %d sub-seeds match master seed %d\n", len(log.Uses), log.Master)
+	return exitOK
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/samdwyer/dungeonband/internal/seed"
+)
+
+// runSeedsArgs runs the seeds subcommand and captures its output.
+func runSeedsArgs(args ...string) (code int, stdout, stderr string) {
+	var out, errOut bytes.Buffer
+	code = runSeeds(args, &out, &errOut)
+	return code, out.String(), errOut.String()
+}
+
+func TestSeedsVerify(t *testing.T) {
+	log := seed.NewLog(42)
+	log.Rand(seed.Dungeon, 1)
+	log.Rand(seed.Combat, 1)
+	path := filepath.Join(t.TempDir(), "seeds.json")
+	if err := log.Write(path); err != nil {
+		t.Fatal(err)
+	}
+	if code, out, _ := runSeedsArgs("verify", path); code != exitOK || !strings.HasPrefix(out, "OK: 2 sub-seeds") {
+		t.Errorf("exit code = %d, output = %q; want OK for 2 sub-seeds", code, out)
+	}
+
+	log.Uses[1].Seed++
+	if err := log.Write(path); err != nil {
+		t.Fatal(err)
+	}
+	if code, out, _ := runSeedsArgs("verify", path); code != exitInvalid || !strings.Contains(out, "combat #1") {
+		t.Errorf("exit code = %d, output = %q; want a combat #1 mismatch", code, out)
+	}
+}
+
+func TestSeedsUsageErrors(t *testing.T) {
+	for _, args := range [][]string{nil, {"show"}, {"verify", filepath.Join(t.TempDir(), "missing.json")}} {
+		if code, _, _ := runSeedsArgs(args...); code != exitUsage {
+			t.Errorf("%q: exit code = %d, want %d", args, code, exitUsage)
+		}
+	}
+}
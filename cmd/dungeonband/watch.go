@@ -0,0 +1,42 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/samdwyer/dungeonband/internal/spectate"
+	"github.com/samdwyer/dungeonband/internal/ui"
+)
+
+const watchUsage = `usage: dungeonband watch host:port
+
+Watches a game started with -spectate, read-only. Press q to stop.`
+
+// runWatch implements "dungeonband watch host:port". It never starts a game
+// or telemetry. Returns the process exit code.
+func runWatch(args []string, stderr io.Writer) int {
+	if len(args) != 1 {
+		fmt.Fprintln(stderr, watchUsage)
+		return exitUsage
+	}
+
+	screen, err := ui.NewScreen()
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+	err = spectate.Watch(args[0], screen)
+	screen.Close()
+
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, io.EOF):
+		fmt.Fprintln(stderr, "The game has ended.")
+		return 0
+	default:
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+}
@@ -0,0 +1,152 @@
+// Package audio plays short sound cues for game events, either as terminal
+// bell patterns or by running a user-supplied command.
+package audio
+
+import (
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// Cue names.
+const (
+	CueHit     = "hit"
+	CueCrit    = "crit"
+	CueHeal    = "heal"
+	CueDeath   = "death"
+	CueVictory = "victory"
+	CueLevelUp = "level_up"
+)
+
+// DefaultBells is the bell pattern for each cue: '*' rings the bell and any
+// other character is a pause of the same length.
+var DefaultBells = map[string]string{
+	CueHit:     "*",
+	CueCrit:    "**",
+	CueHeal:    "*.*",
+	CueDeath:   "*..*..*",
+	CueVictory: "***.*",
+	CueLevelUp: "*.**",
+}
+
+// bellStep is how long each step of a bell pattern takes.
+const bellStep = 120 * time.Millisecond
+
+// DefaultDebounce is the shortest gap between two plays of the same cue.
+// Group attacks raise a burst of identical cues; it plays once.
+const DefaultDebounce = 150 * time.Millisecond
+
+// Settings configures cue playback. The zero value is silent.
+type Settings struct {
+	Enabled bool `json:"enabled"` // Off by default
+
+	// Command, if set, is run once per cue with the cue's sound as its only
+	// argument instead of ringing the bell, e.g. a script that plays a sample.
+	Command string `json:"command,omitempty"`
+
+	// Sounds maps cue names to the argument passed to Command. Cues without
+	// an entry pass their name.
+	Sounds map[string]string `json:"sounds,omitempty"`
+
+	// Bells overrides DefaultBells per cue. An empty pattern mutes the cue.
+	Bells map[string]string `json:"bells,omitempty"`
+
+	// DebounceMS overrides DefaultDebounce, in milliseconds.
+	DebounceMS int `json:"debounceMs,omitempty"`
+}
+
+// Player plays cues as configured. Playback never blocks the caller. A nil
+// Player is silent.
+type Player struct {
+	settings Settings
+	ring     func() error                        // Rings the bell once
+	start    func(name string, arg string) error // Starts the cue command
+	sleep    func(time.Duration)
+	now      func() time.Time
+
+	mu   sync.Mutex
+	last map[string]time.Time // When each cue last played
+}
+
+// New returns a player for the settings that rings the bell with ring, or
+// nil if audio is disabled. Bell patterns ring from a background goroutine,
+// so ring must be safe to call from one.
+func New(settings Settings, ring func() error) *Player {
+	if !settings.Enabled {
+		return nil
+	}
+	return &Player{
+		settings: settings,
+		ring:     ring,
+		start:    startCommand,
+		sleep:    time.Sleep,
+		now:      time.Now,
+		last:     make(map[string]time.Time),
+	}
+}
+
+// startCommand runs the command in the background and reaps it when done.
+func startCommand(name, arg string) error {
+	cmd := exec.Command(name, arg)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
+
+// PlayCue plays the named cue unless the same cue played within the
+// debounce window. Errors are dropped; a missing sound is never fatal.
+func (p *Player) PlayCue(name string) {
+	if p == nil || !p.claim(name) {
+		return
+	}
+	if p.settings.Command != "" {
+		sound := name
+		if s, ok := p.settings.Sounds[name]; ok {
+			sound = s
+		}
+		p.start(p.settings.Command, sound)
+		return
+	}
+	if pattern := p.bellPattern(name); pattern != "" && p.ring != nil {
+		go p.playBells(pattern)
+	}
+}
+
+// claim records the cue as playing now. Returns false if it played too
+// recently to play again.
+func (p *Player) claim(name string) bool {
+	debounce := DefaultDebounce
+	if p.settings.DebounceMS > 0 {
+		debounce = time.Duration(p.settings.DebounceMS) * time.Millisecond
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	if last, ok := p.last[name]; ok && now.Sub(last) < debounce {
+		return false
+	}
+	p.last[name] = now
+	return true
+}
+
+// bellPattern returns the cue's bell pattern, honoring overrides.
+func (p *Player) bellPattern(name string) string {
+	if pattern, ok := p.settings.Bells[name]; ok {
+		return pattern
+	}
+	return DefaultBells[name]
+}
+
+// playBells rings out a pattern one step at a time.
+func (p *Player) playBells(pattern string) {
+	for i, step := range pattern {
+		if i > 0 {
+			p.sleep(bellStep)
+		}
+		if step == '*' {
+			p.ring()
+		}
+	}
+}
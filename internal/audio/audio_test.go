@@ -0,0 +1,67 @@
+package audio
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// testPlayer returns a player on a fake clock that records what it runs
+// and rings synchronously.
+func testPlayer(settings Settings) (p *Player, clock *time.Time, played *[]string) {
+	settings.Enabled = true
+	played = &[]string{}
+	p = New(settings, func() error { *played = append(*played, "bell"); return nil })
+	now := time.Unix(0, 0)
+	clock = &now
+	p.now = func() time.Time { return *clock }
+	p.sleep = func(time.Duration) { *played = append(*played, "pause") }
+	p.start = func(name, arg string) error { *played = append(*played, name+" "+arg); return nil }
+	return p, clock, played
+}
+
+func TestDisabledPlayerIsSilent(t *testing.T) {
+	p := New(Settings{}, func() error { t.Error("bell rang while disabled"); return nil })
+	if p != nil {
+		t.Fatal("New should return nil when audio is disabled")
+	}
+	p.PlayCue(CueHit) // A nil player is safe to use
+}
+
+func TestCommandPlaysMappedSoundsWithDebounce(t *testing.T) {
+	p, clock, played := testPlayer(Settings{
+		Command: "play-sound",
+		Sounds:  map[string]string{CueHit: "hit.wav"},
+	})
+
+	p.PlayCue(CueHit)
+	p.PlayCue(CueHit) // Same instant: debounced
+	p.PlayCue(CueHeal)
+	*clock = clock.Add(DefaultDebounce)
+	p.PlayCue(CueHit)
+
+	want := []string{"play-sound hit.wav", "play-sound heal", "play-sound hit.wav"}
+	if !slices.Equal(*played, want) {
+		t.Errorf("played %q, want %q", *played, want)
+	}
+}
+
+func TestBellPatterns(t *testing.T) {
+	p, _, played := testPlayer(Settings{Bells: map[string]string{CueHit: ""}})
+
+	p.playBells(p.bellPattern(CueHeal))
+	if want := []string{"bell", "pause", "pause", "bell"}; !slices.Equal(*played, want) {
+		t.Errorf("heal played %q, want %q", *played, want)
+	}
+	if p.bellPattern(CueHit) != "" {
+		t.Error("an empty override should mute the cue")
+	}
+
+	seen := make(map[string]string)
+	for cue, pattern := range DefaultBells {
+		if other, ok := seen[pattern]; ok {
+			t.Errorf("%s and %s share the bell pattern %q", cue, other, pattern)
+		}
+		seen[pattern] = cue
+	}
+}
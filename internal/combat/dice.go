@@ -0,0 +1,84 @@
+package combat
+
+import (
+	"math/rand"
+	"slices"
+)
+
+// Roll is a single labelled random draw in [0, Bound).
+type Roll struct {
+	Purpose string
+	Bound   int
+	Result  int
+}
+
+// Dice is the single source of randomness for combat. Every draw is
+// labelled with its purpose so that, in audit mode, the rolls behind a
+// fight can be inspected afterwards.
+type Dice struct {
+	rng    *rand.Rand
+	audit  bool
+	rolls  []Roll
+	bounds []int // Bound of every roll made, so the dice can be rewound
+}
+
+// NewDice wraps rng. When audit is true every roll is recorded.
+func NewDice(rng *rand.Rand, audit bool) *Dice {
+	return &Dice{rng: rng, audit: audit}
+}
+
+// Roll returns a random int in [0, n) drawn for the given purpose.
+func (d *Dice) Roll(purpose string, n int) int {
+	result := d.rng.Intn(n)
+	d.bounds = append(d.bounds, n)
+	if d.audit {
+		d.rolls = append(d.rolls, Roll{Purpose: purpose, Bound: n, Result: result})
+	}
+	return result
+}
+
+// Perm returns a random permutation of [0, n). It draws the same sequence
+// as rand.Perm, so seeded fights replay identically with auditing on or off.
+func (d *Dice) Perm(purpose string, n int) []int {
+	m := make([]int, n)
+	for i := 0; i < n; i++ {
+		j := d.Roll(purpose, i+1)
+		m[i] = m[j]
+		m[j] = i
+	}
+	return m
+}
+
+// Drawn returns how many rolls the dice have made.
+func (d *Dice) Drawn() int {
+	return len(d.bounds)
+}
+
+// Rewind returns dice that have made only the first n of d's rolls, so the
+// rolls after them come out again. rng must be a fresh generator seeded as
+// d's was.
+func (d *Dice) Rewind(rng *rand.Rand, n int) *Dice {
+	r := NewDice(rng, d.audit)
+	for _, bound := range d.bounds[:n] {
+		r.rng.Intn(bound)
+	}
+	r.bounds = slices.Clone(d.bounds[:n])
+	undone := len(d.bounds) - n
+	r.rolls = slices.Clone(d.rolls[:max(len(d.rolls)-undone, 0)])
+	return r
+}
+
+// Auditing returns true if rolls are being recorded.
+func (d *Dice) Auditing() bool {
+	return d.audit
+}
+
+// Rolls returns the rolls recorded since the last Reset.
+func (d *Dice) Rolls() []Roll {
+	return d.rolls
+}
+
+// Reset clears the recorded rolls, typically at the start of a fight.
+func (d *Dice) Reset() {
+	d.rolls = nil
+}
@@ -0,0 +1,52 @@
+package combat
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestDicePermMatchesRandPerm(t *testing.T) {
+	want := rand.New(rand.NewSource(7)).Perm(5)
+	got := NewDice(rand.New(rand.NewSource(7)), true).Perm("shuffle", 5)
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Perm = %v, want %v (same sequence as rand.Perm)", got, want)
+	}
+}
+
+func TestDiceRecordsRollsOnlyWhenAuditing(t *testing.T) {
+	quiet := NewDice(rand.New(rand.NewSource(1)), false)
+	quiet.Roll("crit_chance", 100)
+	if len(quiet.Rolls()) != 0 {
+		t.Errorf("non-audit dice recorded %d rolls", len(quiet.Rolls()))
+	}
+
+	audit := NewDice(rand.New(rand.NewSource(1)), true)
+	result := audit.Roll("crit_chance", 100)
+	want := []Roll{{Purpose: "crit_chance", Bound: 100, Result: result}}
+	if !reflect.DeepEqual(audit.Rolls(), want) {
+		t.Errorf("Rolls = %+v, want %+v", audit.Rolls(), want)
+	}
+
+	audit.Reset()
+	if len(audit.Rolls()) != 0 {
+		t.Error("Reset should clear recorded rolls")
+	}
+}
+
+func TestDiceRewindRepeatsLaterRolls(t *testing.T) {
+	dice := NewDice(rand.New(rand.NewSource(3)), true)
+	dice.Roll("hit", 100)
+	mark := dice.Drawn()
+	first := []int{dice.Roll("hit", 100), dice.Roll("crit", 7), dice.Roll("hit", 100)}
+
+	rewound := dice.Rewind(rand.New(rand.NewSource(3)), mark)
+	again := []int{rewound.Roll("hit", 100), rewound.Roll("crit", 7), rewound.Roll("hit", 100)}
+	if !reflect.DeepEqual(again, first) {
+		t.Errorf("rolls after rewind = %v, want %v", again, first)
+	}
+	if n := len(rewound.Rolls()); n != 4 {
+		t.Errorf("rewound dice recorded %d rolls, want the 1 kept plus 3 new", n)
+	}
+}
@@ -0,0 +1,121 @@
+package combat
+
+import "github.com/samdwyer/dungeonband/internal/gamedata"
+
+// Difficulty is a heuristic estimate of how dangerous an encounter is,
+// taken before anyone acts.
+type Difficulty struct {
+	EnemyHP  int // Living enemies' total HP
+	EnemyDPS int // Damage the enemies deal per round with their best abilities
+	PartyHP  int // Living party members' total HP
+	PartyDPS int // Damage the party deals per round with its best abilities
+	// Ratio is the rounds the party needs to kill the enemies over the
+	// rounds the enemies need to kill the party. Above 1 the enemies win
+	// the race; 0 means the enemies can't hurt the party.
+	Ratio float64
+}
+
+// EstimateDifficulty compares the enemies' HP and damage output with the
+// party's. Each side's damage is every living combatant's best affordable
+// damage ability, per CalculateDamage, against a target with the other
+// side's average defense and resist. Area abilities count once per living
+// opponent. It changes nothing.
+func (r *EffectResolver) EstimateDifficulty(enemies, party []Combatant) Difficulty {
+	d := Difficulty{
+		EnemyHP:  totalHP(enemies),
+		EnemyDPS: r.roundDamage(enemies, party),
+		PartyHP:  totalHP(party),
+		PartyDPS: r.roundDamage(party, enemies),
+	}
+	switch {
+	case d.EnemyDPS == 0 || d.PartyHP == 0:
+		d.Ratio = 0
+	case d.PartyDPS == 0:
+		// The party can't win the race at all; report it as lopsided
+		// as the enemies' side allows
+		d.Ratio = float64(d.EnemyHP * d.EnemyDPS)
+	default:
+		d.Ratio = float64(d.EnemyHP*d.EnemyDPS) / float64(d.PartyHP*d.PartyDPS)
+	}
+	return d
+}
+
+// totalHP sums the HP of the living combatants.
+func totalHP(side []Combatant) int {
+	total := 0
+	for _, c := range side {
+		if c.IsAlive() {
+			total += c.GetHP()
+		}
+	}
+	return total
+}
+
+// roundDamage sums each living attacker's best damage against the average
+// living defender.
+func (r *EffectResolver) roundDamage(attackers, defenders []Combatant) int {
+	target, count := averageDefender(defenders)
+	if count == 0 || !r.hasAbilityData() {
+		return 0
+	}
+	total := 0
+	for _, attacker := range attackers {
+		if !attacker.IsAlive() {
+			continue
+		}
+		best := 0
+		for _, ability := range r.abilityRegistry.GetMultiple(attacker.GetAbilityIDs()) {
+			if ability.EffectType != gamedata.EffectDamage || !ability.IsOffensive() || !r.CanUse(ability, attacker) {
+				continue
+			}
+			damage := r.CalculateDamage(ability, attacker, target)
+			if ability.TargetType == gamedata.TargetAllEnemies {
+				damage *= count
+			}
+			best = max(best, damage)
+		}
+		total += best
+	}
+	return total
+}
+
+// averageDefender returns a stand-in with the living defenders' average HP,
+// defense and resist, and how many defenders are alive.
+func averageDefender(defenders []Combatant) (Combatant, int) {
+	var avg averageTarget
+	count := 0
+	for _, c := range defenders {
+		if !c.IsAlive() {
+			continue
+		}
+		avg.hp += c.GetHP()
+		avg.maxHP += c.GetMaxHP()
+		avg.defense += c.GetDefense()
+		avg.resist += c.GetResist()
+		count++
+	}
+	if count == 0 {
+		return nil, 0
+	}
+	avg.hp /= count
+	avg.maxHP /= count
+	avg.defense /= count
+	avg.resist /= count
+	return &avg, count
+}
+
+// averageTarget is the stand-in defender for damage estimates. It only
+// answers what CalculateDamage asks of a target; the embedded Combatant is
+// nil, so anything else panics.
+type averageTarget struct {
+	Combatant
+	hp, maxHP       int
+	defense, resist int
+}
+
+func (a *averageTarget) GetName() string { return "average target" }
+func (a *averageTarget) IsAlive() bool   { return a.hp > 0 }
+func (a *averageTarget) GetHP() int      { return a.hp }
+func (a *averageTarget) GetMaxHP() int   { return a.maxHP }
+func (a *averageTarget) GetDefense() int { return a.defense }
+func (a *averageTarget) GetResist() int  { return a.resist }
@@ -0,0 +1,45 @@
+package combat
+
+import (
+	"math"
+	"testing"
+
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+)
+
+func TestEstimateDifficulty(t *testing.T) {
+	resolver := NewEffectResolver(gamedata.MustLoadAbilityRegistry())
+
+	// Power attack costs MP the warrior doesn't have, so attack is the best:
+	// 5 + 8 - 2 = 11 against the goblins, 5 + 4 - 6 = 3 back from each goblin
+	warrior := newMockCombatant("Warrior", 30, 0, 8, 6, 0).knows("attack", "power_attack")
+	goblins := []Combatant{
+		newMockCombatant("Goblin", 10, 0, 4, 2, 0).knows("attack", "defend"),
+		newMockCombatant("Goblin", 10, 0, 4, 2, 0).knows("attack", "defend"),
+		newMockCombatant("Dead Goblin", 0, 0, 40, 0, 0).knows("attack"),
+	}
+
+	d := resolver.EstimateDifficulty(goblins, []Combatant{warrior})
+
+	want := Difficulty{EnemyHP: 20, EnemyDPS: 6, PartyHP: 30, PartyDPS: 11}
+	want.Ratio = float64(20*6) / float64(30*11)
+	if d.EnemyHP != want.EnemyHP || d.EnemyDPS != want.EnemyDPS || d.PartyHP != want.PartyHP || d.PartyDPS != want.PartyDPS {
+		t.Errorf("EstimateDifficulty = %+v, want %+v", d, want)
+	}
+	if math.Abs(d.Ratio-want.Ratio) > 1e-9 {
+		t.Errorf("Ratio = %v, want %v", d.Ratio, want.Ratio)
+	}
+	if warrior.GetHP() != 30 || goblins[0].GetHP() != 10 {
+		t.Error("estimating difficulty changed HP")
+	}
+}
+
+func TestEstimateDifficultyHarmlessEnemies(t *testing.T) {
+	resolver := NewEffectResolver(gamedata.MustLoadAbilityRegistry())
+	warrior := newMockCombatant("Warrior", 30, 0, 8, 6, 0).knows("attack")
+	dummy := newMockCombatant("Dummy", 50, 0, 0, 0, 0).knows("defend")
+
+	if d := resolver.EstimateDifficulty([]Combatant{dummy}, []Combatant{warrior}); d.EnemyDPS != 0 || d.Ratio != 0 {
+		t.Errorf("EstimateDifficulty = %+v, want no enemy damage and a zero ratio", d)
+	}
+}
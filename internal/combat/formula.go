@@ -0,0 +1,46 @@
+package combat
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+)
+
+// DamageFormula combines an ability's power with the attacker's offense
+// (its scaled Attack or Magic) and the target's defense (Defense or
+// Resist) into damage, before the minimum of 1 and row modifiers.
+type DamageFormula interface {
+	Damage(power, offense, defense int) int
+}
+
+// AdditiveFormula is the default: power plus offense minus defense.
+type AdditiveFormula struct{}
+
+// Damage implements DamageFormula.
+func (AdditiveFormula) Damage(power, offense, defense int) int {
+	return power + offense - defense
+}
+
+// MultiplicativeFormula scales power by 10% per point of offense, then
+// divides it by 1 plus 10% per point of defense, rounding to the nearest
+// point. Defense never reduces damage to nothing, and stronger attackers
+// get proportionally more from stronger abilities.
+type MultiplicativeFormula struct{}
+
+// Damage implements DamageFormula.
+func (MultiplicativeFormula) Damage(power, offense, defense int) int {
+	scaled := float64(power) * float64(max(10+offense, 0)) / float64(10+max(defense, 0))
+	return int(math.Round(scaled))
+}
+
+// FormulaFor returns the damage formula with the given name. "" is additive.
+func FormulaFor(name gamedata.DamageFormula) (DamageFormula, error) {
+	switch name {
+	case "", gamedata.FormulaAdditive:
+		return AdditiveFormula{}, nil
+	case gamedata.FormulaMultiplicative:
+		return MultiplicativeFormula{}, nil
+	}
+	return nil, fmt.Errorf("unknown damage formula %q (have %s, %s)", name, gamedata.FormulaAdditive, gamedata.FormulaMultiplicative)
+}
@@ -0,0 +1,61 @@
+package combat
+
+import (
+	"testing"
+
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+)
+
+func TestDamageFormulas(t *testing.T) {
+	registry := gamedata.MustLoadAbilityRegistry()
+	attack := registry.GetByID("attack")     // 5 power, physical
+	fireball := registry.GetByID("fireball") // 12 power, magical
+
+	tests := []struct {
+		formula  gamedata.DamageFormula
+		ability  *gamedata.AbilityDef
+		want     int
+		describe string
+	}{
+		{gamedata.FormulaAdditive, attack, 10, "5 + 8 attack - 3 defense"},
+		{gamedata.FormulaMultiplicative, attack, 7, "5 × 18/10 × 10/13 = 6.9"},
+		{gamedata.FormulaAdditive, fireball, 18, "12 + 10 magic - 4 resist"},
+		{gamedata.FormulaMultiplicative, fireball, 17, "12 × 20/10 × 10/14 = 17.1"},
+		{"", attack, 10, "the default is additive"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.formula)+"/"+tt.ability.ID, func(t *testing.T) {
+			formula, err := FormulaFor(tt.formula)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resolver := NewEffectResolver(registry)
+			resolver.SetDamageFormula(formula)
+			attacker := newMockCombatant("Hero", 30, 20, 8, 0, 10).knows("attack", "fireball")
+			target := newMockCombatant("Orc", 50, 0, 0, 3, 0)
+			target.resist = 4
+
+			result := resolver.Resolve(tt.ability, attacker, target)
+			if result.Damage != tt.want {
+				t.Errorf("damage = %d, want %d (%s)", result.Damage, tt.want, tt.describe)
+			}
+		})
+	}
+}
+
+func TestMultiplicativeFormulaKeepsMinimumDamage(t *testing.T) {
+	resolver := NewEffectResolver(gamedata.MustLoadAbilityRegistry())
+	resolver.SetDamageFormula(MultiplicativeFormula{})
+	attacker := newMockCombatant("Rat", 5, 0, 0, 0, 0).knows("attack")
+	wall := newMockCombatant("Golem", 50, 0, 0, 200, 0)
+
+	if got := resolver.Resolve(resolver.abilityRegistry.GetByID("attack"), attacker, wall).Damage; got != 1 {
+		t.Errorf("damage = %d, want the minimum of 1", got)
+	}
+}
+
+func TestFormulaForUnknownName(t *testing.T) {
+	if _, err := FormulaFor("exponential"); err == nil {
+		t.Error("FormulaFor(exponential) should fail")
+	}
+}
@@ -2,6 +2,8 @@
 package combat
 
 import (
+	"math"
+
 	"github.com/samdwyer/dungeonband/internal/gamedata"
 )
 
@@ -20,6 +22,7 @@ type Combatant interface {
 	GetAttack() int
 	GetDefense() int
 	GetMagic() int
+	GetResist() int
 
 	// Mutations
 	TakeDamage(amount int) int // Returns actual damage taken
@@ -37,11 +40,24 @@ type Combatant interface {
 	TickStatusEffects() []StatusTick // Process turn-based effects, returns what happened
 }
 
+// minStatusDuration is the shortest a status effect can last. Abilities with
+// a missing statusDuration would otherwise expire at the first tick.
+const minStatusDuration = 1
+
+// backRowPhysicalPercent is the share of physical damage back-row targets take.
+const backRowPhysicalPercent = 50
+
+// RowPositioned is implemented by combatants that can stand in the back row.
+type RowPositioned interface {
+	InBackRow() bool
+}
+
 // StatusEffect represents an active status effect on a combatant.
 type StatusEffect struct {
 	Type           gamedata.StatusEffectType
 	RemainingTurns int
 	Power          int // For DoT/HoT: damage/heal per turn
+	Refreshes      int // Times re-applied while still active (regen diminishing returns)
 }
 
 // StatusTick represents what happened when a status effect was processed.
@@ -60,38 +76,168 @@ type EffectResult struct {
 	Message     string                    // Human-readable description
 }
 
+// CastModifiers adjust one cast of an ability for its caster. They are
+// worked out per cast rather than written into the AbilityDef, which is
+// shared through the registry.
+type CastModifiers struct {
+	MPCostReduction int // MP knocked off the ability's cost (never below 0)
+	PowerBonus      int // Added to the ability's base power
+	DamagePercent   int // Scales the damage dealt; 0 leaves it as is
+	HealingPercent  int // Scales the healing done; 0 leaves it as is
+}
+
+// Environment adjusts every cast made in a fight, e.g. for the room it is
+// fought in. Only its DamagePercent and HealingPercent are used.
+type Environment interface {
+	CastModifiers(ability *gamedata.AbilityDef, user Combatant) CastModifiers
+}
+
+// Mastered is implemented by combatants whose abilities improve with use.
+type Mastered interface {
+	CastModifiers(abilityID string) CastModifiers
+}
+
+// castModifiers returns the user's modifiers for casting the ability.
+func castModifiers(ability *gamedata.AbilityDef, user Combatant) CastModifiers {
+	if m, ok := user.(Mastered); ok {
+		return m.CastModifiers(ability.ID)
+	}
+	return CastModifiers{}
+}
+
+// modifiers returns the modifiers for this cast: the user's own, plus the
+// environment's scaling.
+func (r *EffectResolver) modifiers(ability *gamedata.AbilityDef, user Combatant) CastModifiers {
+	mods := castModifiers(ability, user)
+	if r.environment != nil {
+		env := r.environment.CastModifiers(ability, user)
+		mods.DamagePercent, mods.HealingPercent = env.DamagePercent, env.HealingPercent
+	}
+	return mods
+}
+
+// scalePercent scales an amount by a percentage, rounding to the nearest
+// point. A percent of 0 leaves the amount as is.
+func scalePercent(amount, percent int) int {
+	if percent == 0 {
+		return amount
+	}
+	return int(math.Round(float64(amount) * float64(percent) / 100))
+}
+
+// MPCost returns what the ability costs the user to cast, after mastery.
+func MPCost(ability *gamedata.AbilityDef, user Combatant) int {
+	return max(ability.MPCost-castModifiers(ability, user).MPCostReduction, 0)
+}
+
+// BasicAttack stands in for the ability registry when ability data failed
+// to load, so every combatant still has something to do and combat can't
+// stall. It matches the "attack" entry in abilities.json.
+var BasicAttack = &gamedata.AbilityDef{
+	ID:          "attack",
+	Name:        "Attack",
+	Description: "A basic physical attack",
+	EffectType:  gamedata.EffectDamage,
+	TargetType:  gamedata.TargetSingleEnemy,
+	DamageType:  gamedata.DamagePhysical,
+	BasePower:   5,
+}
+
 // EffectResolver calculates and applies ability effects.
 type EffectResolver struct {
 	abilityRegistry *gamedata.AbilityRegistry
+	formula         DamageFormula
+	environment     Environment // The current fight's surroundings, if any
 }
 
-// NewEffectResolver creates a new effect resolver.
+// NewEffectResolver creates a new effect resolver using the additive
+// damage formula.
 func NewEffectResolver(abilityRegistry *gamedata.AbilityRegistry) *EffectResolver {
 	return &EffectResolver{
 		abilityRegistry: abilityRegistry,
+		formula:         AdditiveFormula{},
 	}
 }
 
-// Resolve applies an ability from the user to the target(s) and returns results.
-// For multi-target abilities, this should be called once per target.
+// SetEnvironment sets the surroundings that adjust every cast until it is
+// changed again. nil clears it.
+func (r *EffectResolver) SetEnvironment(env Environment) {
+	r.environment = env
+}
+
+// SetAbilityRegistry swaps the ability definitions the resolver looks up,
+// as when game data is reloaded.
+func (r *EffectResolver) SetAbilityRegistry(abilityRegistry *gamedata.AbilityRegistry) {
+	r.abilityRegistry = abilityRegistry
+}
+
+// SetDamageFormula changes how the resolver works out damage.
+func (r *EffectResolver) SetDamageFormula(formula DamageFormula) {
+	r.formula = formula
+}
+
+// Resolve applies an ability from the user to a single target and returns the result.
 func (r *EffectResolver) Resolve(ability *gamedata.AbilityDef, user Combatant, target Combatant) EffectResult {
+	if failed, ok := r.pay(ability, user); !ok {
+		return failed
+	}
+	return r.apply(ability, user, target)
+}
+
+// ResolveAll applies a multi-target ability to each target, paying its cost
+// once. If the ability can't be used, a single failed result is returned.
+func (r *EffectResolver) ResolveAll(ability *gamedata.AbilityDef, user Combatant, targets []Combatant) []EffectResult {
+	if failed, ok := r.pay(ability, user); !ok {
+		return []EffectResult{failed}
+	}
+	results := make([]EffectResult, 0, len(targets))
+	for _, target := range targets {
+		results = append(results, r.apply(ability, user, target))
+	}
+	return results
+}
+
+// pay checks that the user may use the ability and spends its MP cost.
+// Returns a failed result and false if the ability can't be used.
+func (r *EffectResolver) pay(ability *gamedata.AbilityDef, user Combatant) (EffectResult, bool) {
 	if ability == nil {
-		return EffectResult{Success: false, Message: "Invalid ability"}
+		return EffectResult{Success: false, Message: "Invalid ability"}, false
+	}
+
+	// Combatants can only use abilities they know
+	if !r.knows(user, ability) {
+		return EffectResult{
+			Success: false,
+			Message: user.GetName() + " doesn't know " + ability.Name + "!",
+		}, false
+	}
+
+	// Silenced combatants cannot cast MP abilities
+	if BlockedBySilence(ability, user) {
+		return EffectResult{
+			Success: false,
+			Message: user.GetName() + " is silenced!",
+		}, false
 	}
 
 	// Check MP cost
-	if ability.MPCost > 0 && user.GetMP() < ability.MPCost {
+	cost := MPCost(ability, user)
+	if cost > 0 && user.GetMP() < cost {
 		return EffectResult{
 			Success: false,
 			Message: user.GetName() + " doesn't have enough MP!",
-		}
+		}, false
 	}
 
 	// Spend MP
-	if ability.MPCost > 0 {
-		user.SpendMP(ability.MPCost)
+	if cost > 0 {
+		user.SpendMP(cost)
 	}
+	return EffectResult{}, true
+}
 
+// apply resolves the ability's effect on one target.
+func (r *EffectResolver) apply(ability *gamedata.AbilityDef, user Combatant, target Combatant) EffectResult {
 	switch ability.EffectType {
 	case gamedata.EffectDamage:
 		return r.resolveDamage(ability, user, target)
@@ -99,46 +245,50 @@ func (r *EffectResolver) Resolve(ability *gamedata.AbilityDef, user Combatant, t
 		return r.resolveHeal(ability, user, target)
 	case gamedata.EffectBuff, gamedata.EffectDebuff:
 		return r.resolveStatusEffect(ability, user, target)
+	case gamedata.EffectCleanse:
+		return r.resolveCleanse(ability, user, target)
 	default:
 		return EffectResult{Success: false, Message: "Unknown ability effect type"}
 	}
 }
 
-// CanUse checks if a combatant can use an ability (has enough MP).
+// CanUse checks if a combatant can use an ability (knows it and has enough MP).
 func (r *EffectResolver) CanUse(ability *gamedata.AbilityDef, user Combatant) bool {
-	if ability == nil {
+	if ability == nil || !r.knows(user, ability) {
 		return false
 	}
-	return user.GetMP() >= ability.MPCost
+	return user.GetMP() >= MPCost(ability, user)
 }
 
-// resolveDamage handles damage-type abilities.
-func (r *EffectResolver) resolveDamage(ability *gamedata.AbilityDef, user Combatant, target Combatant) EffectResult {
-	var damage int
+// knows reports whether the combatant may use the ability. Without ability
+// data everyone knows the basic attack, whatever their ability list says.
+func (r *EffectResolver) knows(c Combatant, ability *gamedata.AbilityDef) bool {
+	if !r.hasAbilityData() && ability == BasicAttack {
+		return true
+	}
+	return Knows(c, ability.ID)
+}
 
-	switch ability.DamageType {
-	case gamedata.DamagePhysical:
-		// Physical: basePower + attacker.Attack - target.Defense (min 1)
-		damage = ability.BasePower + user.GetAttack() - target.GetDefense()
-		if damage < 1 {
-			damage = 1
-		}
-	case gamedata.DamageMagical:
-		// Magical: basePower + attacker.Magic (min 1)
-		damage = ability.BasePower + user.GetMagic()
-		if damage < 1 {
-			damage = 1
-		}
-	case gamedata.DamageTrue:
-		// True: basePower (unmitigated)
-		damage = ability.BasePower
-	default:
-		// Fallback to physical calculation
-		damage = ability.BasePower + user.GetAttack() - target.GetDefense()
-		if damage < 1 {
-			damage = 1
+// hasAbilityData reports whether the resolver has any ability definitions.
+// A nil registry and an empty one both mean the data failed to load.
+func (r *EffectResolver) hasAbilityData() bool {
+	return r.abilityRegistry != nil && r.abilityRegistry.Count() > 0
+}
+
+// Knows returns true if the ability is in the combatant's ability list.
+func Knows(c Combatant, abilityID string) bool {
+	for _, id := range c.GetAbilityIDs() {
+		if id == abilityID {
+			return true
 		}
 	}
+	return false
+}
+
+// resolveDamage handles damage-type abilities.
+func (r *EffectResolver) resolveDamage(ability *gamedata.AbilityDef, user Combatant, target Combatant) EffectResult {
+	damage := r.scaledDamage(ability, user, target)
+	damage = applyRowModifier(ability, target, damage)
 
 	// Apply damage to target
 	actualDamage := target.TakeDamage(damage)
@@ -151,12 +301,7 @@ func (r *EffectResolver) resolveDamage(ability *gamedata.AbilityDef, user Combat
 	}
 
 	if ability.StatusEffect != "" && ability.StatusEffect != gamedata.StatusNone {
-		effect := StatusEffect{
-			Type:           ability.StatusEffect,
-			RemainingTurns: ability.StatusDuration,
-			Power:          ability.StatusPower,
-		}
-		target.AddStatusEffect(effect)
+		target.AddStatusEffect(statusFromAbility(ability, user))
 		result.StatusAdded = ability.StatusEffect
 	}
 
@@ -165,11 +310,7 @@ func (r *EffectResolver) resolveDamage(ability *gamedata.AbilityDef, user Combat
 
 // resolveHeal handles heal-type abilities.
 func (r *EffectResolver) resolveHeal(ability *gamedata.AbilityDef, user Combatant, target Combatant) EffectResult {
-	// Healing: basePower + caster.Magic
-	healAmount := ability.BasePower + user.GetMagic()
-	if healAmount < 1 {
-		healAmount = 1
-	}
+	healAmount := max(scalePercent(baseHealing(ability, user), r.modifiers(ability, user).HealingPercent), 1)
 
 	actualHealing := target.Heal(healAmount)
 
@@ -181,12 +322,7 @@ func (r *EffectResolver) resolveHeal(ability *gamedata.AbilityDef, user Combatan
 
 	// Check if heal also applies a status effect (e.g., regen)
 	if ability.StatusEffect != "" && ability.StatusEffect != gamedata.StatusNone {
-		effect := StatusEffect{
-			Type:           ability.StatusEffect,
-			RemainingTurns: ability.StatusDuration,
-			Power:          ability.StatusPower,
-		}
-		target.AddStatusEffect(effect)
+		target.AddStatusEffect(statusFromAbility(ability, user))
 		result.StatusAdded = ability.StatusEffect
 	}
 
@@ -202,12 +338,7 @@ func (r *EffectResolver) resolveStatusEffect(ability *gamedata.AbilityDef, user
 		}
 	}
 
-	effect := StatusEffect{
-		Type:           ability.StatusEffect,
-		RemainingTurns: ability.StatusDuration,
-		Power:          ability.StatusPower,
-	}
-	target.AddStatusEffect(effect)
+	target.AddStatusEffect(statusFromAbility(ability, user))
 
 	return EffectResult{
 		Success:     true,
@@ -216,25 +347,127 @@ func (r *EffectResolver) resolveStatusEffect(ability *gamedata.AbilityDef, user
 	}
 }
 
+// statusFromAbility builds the status effect an ability applies. Its power
+// is fixed here, so a poison keeps the strength of the caster who applied it.
+func statusFromAbility(ability *gamedata.AbilityDef, user Combatant) StatusEffect {
+	return StatusEffect{
+		Type:           ability.StatusEffect,
+		RemainingTurns: max(ability.StatusDuration, minStatusDuration),
+		Power:          ability.StatusPower + scaleStat(user.GetMagic(), ability.StatusMagicScale),
+	}
+}
+
+// resolveCleanse removes all negative status effects from the target.
+func (r *EffectResolver) resolveCleanse(ability *gamedata.AbilityDef, user Combatant, target Combatant) EffectResult {
+	var removed []gamedata.StatusEffectType
+	for _, e := range target.GetStatusEffects() {
+		if e.Type.IsNegative() {
+			removed = append(removed, e.Type)
+		}
+	}
+	for _, t := range removed {
+		target.RemoveStatusEffect(t)
+	}
+
+	message := user.GetName() + " uses " + ability.Name + " on " + target.GetName() + "!"
+	if len(removed) == 0 {
+		message += " Nothing to cleanse."
+	}
+	return EffectResult{
+		Success: true,
+		Message: message,
+	}
+}
+
 // CalculateDamage calculates damage without applying it (for AI/preview).
 func (r *EffectResolver) CalculateDamage(ability *gamedata.AbilityDef, user Combatant, target Combatant) int {
 	if ability == nil || ability.EffectType != gamedata.EffectDamage {
 		return 0
 	}
+	return applyRowModifier(ability, target, max(r.scaledDamage(ability, user, target), 1))
+}
+
+// scaledDamage returns the ability's base damage scaled by the cast's
+// modifiers, at least 1 unless it is true damage.
+func (r *EffectResolver) scaledDamage(ability *gamedata.AbilityDef, user Combatant, target Combatant) int {
+	damage := r.baseDamage(ability, user, target)
+	scaled := scalePercent(damage, r.modifiers(ability, user).DamagePercent)
+	if ability.DamageType == gamedata.DamageTrue {
+		return scaled
+	}
+	return max(scaled, 1)
+}
 
-	var damage int
+// baseDamage returns the ability's damage against the target before row
+// modifiers, as described in the gamedata package, using the resolver's
+// damage formula. Physical and magical damage is at least 1; true damage
+// is exactly the ability's power.
+func (r *EffectResolver) baseDamage(ability *gamedata.AbilityDef, user Combatant, target Combatant) int {
+	power := abilityPower(ability, user) + hpBonus(ability, user, target)
 	switch ability.DamageType {
-	case gamedata.DamagePhysical:
-		damage = ability.BasePower + user.GetAttack() - target.GetDefense()
 	case gamedata.DamageMagical:
-		damage = ability.BasePower + user.GetMagic()
+		return max(r.formula.Damage(power, scaleStat(user.GetMagic(), ability.MagicMultiplier()), target.GetResist()), 1)
 	case gamedata.DamageTrue:
-		damage = ability.BasePower
+		return power
 	default:
-		damage = ability.BasePower + user.GetAttack() - target.GetDefense()
+		// Physical, and the fallback for abilities without a damage type
+		return max(r.formula.Damage(power, scaleStat(effectiveAttack(user), ability.AttackMultiplier()), target.GetDefense()), 1)
 	}
-	if damage < 1 {
-		damage = 1
+}
+
+// hpBonus returns the damage an ability's HP scaling adds.
+func hpBonus(ability *gamedata.AbilityDef, user Combatant, target Combatant) int {
+	s := ability.HPScaling
+	if s == nil {
+		return 0
+	}
+	subject := user
+	if s.Of == gamedata.HPOfTarget {
+		subject = target
+	}
+	return s.Bonus(subject.GetHP(), subject.GetMaxHP())
+}
+
+// baseHealing returns how much the ability heals for (min 1).
+func baseHealing(ability *gamedata.AbilityDef, user Combatant) int {
+	return max(abilityPower(ability, user)+scaleStat(user.GetMagic(), ability.MagicMultiplier()), 1)
+}
+
+// abilityPower returns the ability's base power plus its per-level and
+// mastery bonuses for the user.
+func abilityPower(ability *gamedata.AbilityDef, user Combatant) int {
+	return ability.BasePower + castModifiers(ability, user).PowerBonus +
+		int(math.Round(ability.LevelScale*float64(casterLevel(user))))
+}
+
+// scaleStat returns the share of a stat an ability adds, rounded.
+func scaleStat(stat int, scale float64) int {
+	return int(math.Round(float64(stat) * scale))
+}
+
+// Leveled is implemented by combatants that have a level.
+type Leveled interface {
+	GetLevel() int
+}
+
+// casterLevel returns the combatant's level, or 1 if it has none.
+func casterLevel(c Combatant) int {
+	if l, ok := c.(Leveled); ok {
+		return l.GetLevel()
+	}
+	return 1
+}
+
+// applyRowModifier reduces physical damage against back-row targets (min 1).
+func applyRowModifier(ability *gamedata.AbilityDef, target Combatant, damage int) int {
+	if ability.DamageType != gamedata.DamagePhysical && ability.DamageType != "" {
+		return damage
+	}
+	if rp, ok := target.(RowPositioned); ok && rp.InBackRow() {
+		damage = damage * backRowPhysicalPercent / 100
+		if damage < 1 {
+			damage = 1
+		}
 	}
 	return damage
 }
@@ -244,9 +477,5 @@ func (r *EffectResolver) CalculateHealing(ability *gamedata.AbilityDef, user Com
 	if ability == nil || ability.EffectType != gamedata.EffectHeal {
 		return 0
 	}
-	healing := ability.BasePower + user.GetMagic()
-	if healing < 1 {
-		healing = 1
-	}
-	return healing
+	return baseHealing(ability, user)
 }
@@ -14,6 +14,7 @@ type mockCombatant struct {
 	attack        int
 	defense       int
 	magic         int
+	resist        int
 	abilityIDs    []string
 	statusEffects []StatusEffect
 }
@@ -42,8 +43,15 @@ func (m *mockCombatant) GetMaxMP() int           { return m.maxMP }
 func (m *mockCombatant) GetAttack() int          { return m.attack }
 func (m *mockCombatant) GetDefense() int         { return m.defense }
 func (m *mockCombatant) GetMagic() int           { return m.magic }
+func (m *mockCombatant) GetResist() int          { return m.resist }
 func (m *mockCombatant) GetAbilityIDs() []string { return m.abilityIDs }
 
+// knows gives the mock the listed abilities and returns it for chaining.
+func (m *mockCombatant) knows(ids ...string) *mockCombatant {
+	m.abilityIDs = append(m.abilityIDs, ids...)
+	return m
+}
+
 func (m *mockCombatant) TakeDamage(amount int) int {
 	if amount <= 0 {
 		return 0
@@ -93,14 +101,7 @@ func (m *mockCombatant) GetStatusEffects() []StatusEffect {
 }
 
 func (m *mockCombatant) AddStatusEffect(effect StatusEffect) {
-	// Replace existing effect of same type
-	for i, existing := range m.statusEffects {
-		if existing.Type == effect.Type {
-			m.statusEffects[i] = effect
-			return
-		}
-	}
-	m.statusEffects = append(m.statusEffects, effect)
+	m.statusEffects = AddOrRefreshStatus(m.statusEffects, effect)
 }
 
 func (m *mockCombatant) RemoveStatusEffect(effectType gamedata.StatusEffectType) {
@@ -123,7 +124,7 @@ func (m *mockCombatant) TickStatusEffects() []StatusTick {
 		case gamedata.StatusPoison:
 			tick.Amount = m.TakeDamage(effect.Power)
 		case gamedata.StatusRegen:
-			tick.Amount = m.Heal(effect.Power)
+			tick.Amount = m.Heal(RegenHealAmount(effect, m.maxHP))
 		}
 
 		effect.RemainingTurns--
@@ -146,7 +147,7 @@ func TestResolveDamagePhysical(t *testing.T) {
 	// Attacker: 8 attack, Target: 3 defense
 	// Attack ability: basePower 5
 	// Expected: 5 + 8 - 3 = 10 damage
-	attacker := newMockCombatant("Warrior", 30, 0, 8, 6, 0)
+	attacker := newMockCombatant("Warrior", 30, 0, 8, 6, 0).knows("attack")
 	target := newMockCombatant("Goblin", 15, 0, 2, 3, 0)
 
 	attack := registry.GetByID("attack")
@@ -167,6 +168,34 @@ func TestResolveDamagePhysical(t *testing.T) {
 	}
 }
 
+func TestAttackDownWeakensPhysicalDamage(t *testing.T) {
+	registry := gamedata.MustLoadAbilityRegistry()
+	resolver := NewEffectResolver(registry)
+
+	// Attack cut from 8 to 6: 5 + 6 - 3 = 8 damage
+	attacker := newMockCombatant("Warrior", 30, 0, 8, 6, 0).knows("attack")
+	attacker.AddStatusEffect(StatusEffect{Type: gamedata.StatusAttackDown, RemainingTurns: 3})
+	target := newMockCombatant("Goblin", 15, 0, 2, 3, 0)
+
+	result := resolver.Resolve(registry.GetByID("attack"), attacker, target)
+	if result.Damage != 8 {
+		t.Errorf("Expected 8 damage while attack_down, got %d", result.Damage)
+	}
+}
+
+func TestBasicAttackWithoutAbilityData(t *testing.T) {
+	resolver := NewEffectResolver(nil)
+
+	// An enemy whose rolled abilities left out "attack" can still swing
+	attacker := newMockCombatant("Spider", 30, 0, 8, 6, 0).knows("web")
+	target := newMockCombatant("Warrior", 15, 0, 2, 3, 0)
+
+	result := resolver.Resolve(BasicAttack, attacker, target)
+	if !result.Success || result.Damage != 10 {
+		t.Errorf("Resolve = %+v, want a 10 damage hit", result)
+	}
+}
+
 func TestResolveDamagePhysicalMinimum(t *testing.T) {
 	registry := gamedata.MustLoadAbilityRegistry()
 	resolver := NewEffectResolver(registry)
@@ -174,7 +203,7 @@ func TestResolveDamagePhysicalMinimum(t *testing.T) {
 	// Attacker: 2 attack, Target: 10 defense
 	// Attack ability: basePower 0
 	// Expected: 0 + 2 - 10 = -8 -> min 1 damage
-	attacker := newMockCombatant("Weak", 10, 0, 2, 0, 0)
+	attacker := newMockCombatant("Weak", 10, 0, 2, 0, 0).knows("attack")
 	target := newMockCombatant("Tank", 50, 0, 0, 10, 0)
 
 	attack := registry.GetByID("attack")
@@ -192,7 +221,7 @@ func TestResolveDamageMagical(t *testing.T) {
 	// Wizard: 10 magic
 	// Fireball: basePower 12, magical damage
 	// Expected: 12 + 10 = 22 damage (defense ignored)
-	wizard := newMockCombatant("Wizard", 15, 20, 2, 2, 10)
+	wizard := newMockCombatant("Wizard", 15, 20, 2, 2, 10).knows("fireball")
 	target := newMockCombatant("Armored Orc", 30, 0, 4, 8, 0) // High defense shouldn't matter
 
 	fireball := registry.GetByID("fireball")
@@ -221,7 +250,7 @@ func TestResolveHeal(t *testing.T) {
 	// Cleric: 8 magic
 	// Heal: basePower 10
 	// Expected: 10 + 8 = 18 healing
-	cleric := newMockCombatant("Cleric", 22, 15, 4, 4, 8)
+	cleric := newMockCombatant("Cleric", 22, 15, 4, 4, 8).knows("heal")
 	wounded := newMockCombatant("Wounded Warrior", 30, 0, 8, 6, 0)
 	wounded.hp = 10 // Simulate damage taken
 
@@ -249,7 +278,7 @@ func TestResolveHealCapped(t *testing.T) {
 	resolver := NewEffectResolver(registry)
 
 	// Healing should be capped at max HP
-	cleric := newMockCombatant("Cleric", 22, 15, 4, 4, 8)
+	cleric := newMockCombatant("Cleric", 22, 15, 4, 4, 8).knows("heal")
 	slightlyWounded := newMockCombatant("Warrior", 30, 0, 8, 6, 0)
 	slightlyWounded.hp = 28 // Only 2 HP missing
 
@@ -270,7 +299,7 @@ func TestResolveInsufficientMP(t *testing.T) {
 	resolver := NewEffectResolver(registry)
 
 	// Wizard with no MP tries to cast fireball
-	wizard := newMockCombatant("Wizard", 15, 0, 2, 2, 10) // 0 MP
+	wizard := newMockCombatant("Wizard", 15, 0, 2, 2, 10).knows("fireball") // 0 MP
 	target := newMockCombatant("Goblin", 10, 0, 2, 1, 0)
 
 	fireball := registry.GetByID("fireball")
@@ -288,7 +317,7 @@ func TestResolveMPCost(t *testing.T) {
 	registry := gamedata.MustLoadAbilityRegistry()
 	resolver := NewEffectResolver(registry)
 
-	wizard := newMockCombatant("Wizard", 15, 20, 2, 2, 10)
+	wizard := newMockCombatant("Wizard", 15, 20, 2, 2, 10).knows("fireball")
 	target := newMockCombatant("Goblin", 10, 0, 2, 1, 0)
 
 	fireball := registry.GetByID("fireball")
@@ -303,11 +332,50 @@ func TestResolveMPCost(t *testing.T) {
 	}
 }
 
+func TestResolveAllPaysCostOnce(t *testing.T) {
+	resolver := NewEffectResolver(nil)
+	quake := &gamedata.AbilityDef{
+		ID: "quake", Name: "Quake", EffectType: gamedata.EffectDamage,
+		TargetType: gamedata.TargetAllEnemies, DamageType: gamedata.DamageTrue, BasePower: 4, MPCost: 6,
+	}
+
+	wizard := newMockCombatant("Wizard", 15, 20, 2, 2, 10).knows("quake")
+	goblins := []Combatant{
+		newMockCombatant("Goblin", 10, 0, 2, 1, 0),
+		newMockCombatant("Goblin", 10, 0, 2, 1, 0),
+		newMockCombatant("Goblin", 10, 0, 2, 1, 0),
+	}
+
+	results := resolver.ResolveAll(quake, wizard, goblins)
+
+	if len(results) != len(goblins) {
+		t.Fatalf("got %d results, want one per target", len(results))
+	}
+	for i, g := range goblins {
+		if g.GetHP() != 6 {
+			t.Errorf("goblin %d HP = %d, want 6", i, g.GetHP())
+		}
+	}
+	if wizard.GetMP() != 14 {
+		t.Errorf("wizard MP = %d, want 14 (cost paid once)", wizard.GetMP())
+	}
+
+	// Without enough MP nothing is resolved
+	wizard.mp = 2
+	results = resolver.ResolveAll(quake, wizard, goblins)
+	if len(results) != 1 || results[0].Success {
+		t.Errorf("expected a single failed result, got %+v", results)
+	}
+	if goblins[0].GetHP() != 6 {
+		t.Error("a failed ResolveAll should not touch its targets")
+	}
+}
+
 func TestResolvePoisonStrike(t *testing.T) {
 	registry := gamedata.MustLoadAbilityRegistry()
 	resolver := NewEffectResolver(registry)
 
-	rogue := newMockCombatant("Rogue", 20, 5, 6, 3, 2)
+	rogue := newMockCombatant("Rogue", 20, 5, 6, 3, 2).knows("poison_strike")
 	target := newMockCombatant("Orc", 15, 0, 4, 2, 0)
 
 	poisonStrike := registry.GetByID("poison_strike")
@@ -342,11 +410,41 @@ func TestResolvePoisonStrike(t *testing.T) {
 	}
 }
 
+func TestPoisonScalesWithCasterMagic(t *testing.T) {
+	registry := gamedata.MustLoadAbilityRegistry()
+	resolver := NewEffectResolver(registry)
+	poisonStrike := registry.GetByID("poison_strike")
+	if poisonStrike == nil {
+		t.Fatal("poison_strike ability not found")
+	}
+
+	poisonFrom := func(magic int) int {
+		caster := newMockCombatant("Caster", 20, 5, 6, 3, magic).knows("poison_strike")
+		target := newMockCombatant("Orc", 100, 0, 4, 2, 0)
+		resolver.Resolve(poisonStrike, caster, target)
+		for _, e := range target.GetStatusEffects() {
+			if e.Type == gamedata.StatusPoison {
+				return e.Power
+			}
+		}
+		t.Fatalf("magic %d: target not poisoned", magic)
+		return 0
+	}
+
+	weak, strong := poisonFrom(0), poisonFrom(10)
+	if weak != poisonStrike.StatusPower {
+		t.Errorf("poison from a caster without magic = %d, want the base %d", weak, poisonStrike.StatusPower)
+	}
+	if want := poisonStrike.StatusPower + scaleStat(10, poisonStrike.StatusMagicScale); strong != want || strong <= weak {
+		t.Errorf("poison from a 10-magic caster = %d, want %d (stronger than %d)", strong, want, weak)
+	}
+}
+
 func TestResolveDefend(t *testing.T) {
 	registry := gamedata.MustLoadAbilityRegistry()
 	resolver := NewEffectResolver(registry)
 
-	warrior := newMockCombatant("Warrior", 30, 0, 8, 6, 0)
+	warrior := newMockCombatant("Warrior", 30, 0, 8, 6, 0).knows("defend")
 
 	defend := registry.GetByID("defend")
 	if defend == nil {
@@ -407,6 +505,38 @@ func TestStatusEffectTick(t *testing.T) {
 	}
 }
 
+func TestResolveDamageMagicalResist(t *testing.T) {
+	registry := gamedata.MustLoadAbilityRegistry()
+	resolver := NewEffectResolver(registry)
+	fireball := registry.GetByID("fireball")
+
+	tests := []struct {
+		name   string
+		resist int
+		want   int
+	}{
+		// Fireball: 12 base + 10 magic - resist, defense ignored
+		{"no resist", 0, 22},
+		{"resist subtracts", 5, 17},
+		{"floors at 1", 40, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wizard := newMockCombatant("Wizard", 15, 20, 2, 2, 10).knows("fireball")
+			target := newMockCombatant("Warded Orc", 30, 0, 4, 8, 0)
+			target.resist = tt.resist
+
+			if got := resolver.CalculateDamage(fireball, wizard, target); got != tt.want {
+				t.Errorf("preview = %d, want %d", got, tt.want)
+			}
+			result := resolver.Resolve(fireball, wizard, target)
+			if result.Damage != tt.want {
+				t.Errorf("damage = %d, want %d", result.Damage, tt.want)
+			}
+		})
+	}
+}
+
 func TestCalculateDamagePreview(t *testing.T) {
 	registry := gamedata.MustLoadAbilityRegistry()
 	resolver := NewEffectResolver(registry)
@@ -433,7 +563,7 @@ func TestCanUse(t *testing.T) {
 	registry := gamedata.MustLoadAbilityRegistry()
 	resolver := NewEffectResolver(registry)
 
-	wizard := newMockCombatant("Wizard", 15, 5, 2, 2, 10)
+	wizard := newMockCombatant("Wizard", 15, 5, 2, 2, 10).knows("fireball")
 	fireball := registry.GetByID("fireball")
 
 	// Should be able to use fireball (5 MP cost, have 5 MP)
@@ -449,3 +579,176 @@ func TestCanUse(t *testing.T) {
 		t.Error("Should not be able to use fireball with insufficient MP")
 	}
 }
+
+func TestResolveRejectsUnknownAbility(t *testing.T) {
+	registry := gamedata.MustLoadAbilityRegistry()
+	resolver := NewEffectResolver(registry)
+
+	warrior := newMockCombatant("Warrior", 30, 20, 8, 6, 0).knows("attack", "defend")
+	target := newMockCombatant("Goblin", 15, 0, 2, 3, 0)
+	fireball := registry.GetByID("fireball")
+
+	result := resolver.Resolve(fireball, warrior, target)
+
+	if result.Success {
+		t.Error("Resolving an ability the user doesn't know should fail")
+	}
+	if result.Message != "Warrior doesn't know Fireball!" {
+		t.Errorf("Unexpected message: %q", result.Message)
+	}
+	if target.GetHP() != 15 || warrior.GetMP() != 20 {
+		t.Error("A rejected ability should not deal damage or spend MP")
+	}
+	if resolver.CanUse(fireball, warrior) {
+		t.Error("CanUse should be false for an unknown ability")
+	}
+}
+
+// backRowCombatant is a mock combatant standing in the back row.
+type backRowCombatant struct {
+	*mockCombatant
+}
+
+func (b backRowCombatant) InBackRow() bool { return true }
+
+func TestBackRowTakesReducedPhysicalDamage(t *testing.T) {
+	registry := gamedata.MustLoadAbilityRegistry()
+	resolver := NewEffectResolver(registry)
+
+	// Bone Throw: basePower 5 + 8 attack - 1 defense = 12, halved to 6
+	attacker := newMockCombatant("Archer", 30, 0, 8, 0, 0).knows("bone_throw")
+	target := backRowCombatant{newMockCombatant("Skeleton", 20, 0, 0, 1, 0)}
+
+	result := resolver.Resolve(registry.GetByID("bone_throw"), attacker, target)
+	if result.Damage != 6 {
+		t.Errorf("Expected 6 damage against back row, got %d", result.Damage)
+	}
+
+	// Magical damage is not reduced: 12 + 10 = 22
+	wizard := newMockCombatant("Wizard", 15, 20, 2, 2, 10).knows("fireball")
+	target = backRowCombatant{newMockCombatant("Skeleton", 40, 0, 0, 1, 0)}
+	result = resolver.Resolve(registry.GetByID("fireball"), wizard, target)
+	if result.Damage != 22 {
+		t.Errorf("Expected 22 magical damage against back row, got %d", result.Damage)
+	}
+}
+
+func BenchmarkResolveDamage(b *testing.B) {
+	registry := gamedata.MustLoadAbilityRegistry()
+	resolver := NewEffectResolver(registry)
+	attack := registry.GetByID("attack")
+	attacker := newMockCombatant("Warrior", 30, 0, 8, 6, 0).knows("attack")
+	target := newMockCombatant("Goblin", 15, 0, 2, 3, 0)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		target.hp = target.maxHP
+		resolver.Resolve(attack, attacker, target)
+	}
+}
+
+// leveledCombatant is a mock with a level.
+type leveledCombatant struct {
+	*mockCombatant
+	level int
+}
+
+func (l *leveledCombatant) GetLevel() int { return l.level }
+
+func TestScaledAbilityMath(t *testing.T) {
+	scale := func(f float64) *float64 { return &f }
+	resolver := NewEffectResolver(nil)
+	tests := []struct {
+		name    string
+		ability gamedata.AbilityDef
+		level   int // 0 for a combatant without a level
+		want    int
+	}{
+		// Caster: 8 attack, 6 magic. Target: 3 defense.
+		{"physical defaults", gamedata.AbilityDef{EffectType: gamedata.EffectDamage, DamageType: gamedata.DamagePhysical, BasePower: 5}, 0, 5 + 8 - 3},
+		{"physical attackScale", gamedata.AbilityDef{EffectType: gamedata.EffectDamage, DamageType: gamedata.DamagePhysical, BasePower: 5, AttackScale: scale(1.5)}, 0, 5 + 12 - 3},
+		{"physical zero scale floors at 1", gamedata.AbilityDef{EffectType: gamedata.EffectDamage, DamageType: gamedata.DamagePhysical, BasePower: 1, AttackScale: scale(0)}, 0, 1},
+		{"magical magicScale rounds", gamedata.AbilityDef{EffectType: gamedata.EffectDamage, DamageType: gamedata.DamageMagical, BasePower: 10, MagicScale: scale(1.25)}, 0, 10 + 8}, // 7.5 rounds to 8
+		{"magical ignores attackScale", gamedata.AbilityDef{EffectType: gamedata.EffectDamage, DamageType: gamedata.DamageMagical, BasePower: 10, AttackScale: scale(3)}, 0, 10 + 6},
+		{"levelScale at level 10", gamedata.AbilityDef{EffectType: gamedata.EffectDamage, DamageType: gamedata.DamageMagical, BasePower: 10, LevelScale: 2}, 10, 10 + 20 + 6},
+		{"levelScale without a level", gamedata.AbilityDef{EffectType: gamedata.EffectDamage, DamageType: gamedata.DamageMagical, BasePower: 10, LevelScale: 2}, 0, 10 + 2 + 6},
+		{"true damage scales with level only", gamedata.AbilityDef{EffectType: gamedata.EffectDamage, DamageType: gamedata.DamageTrue, BasePower: 4, LevelScale: 0.5, MagicScale: scale(2)}, 4, 4 + 2},
+		{"heal magicScale and level", gamedata.AbilityDef{EffectType: gamedata.EffectHeal, BasePower: 5, MagicScale: scale(0.5), LevelScale: 1}, 3, 5 + 3 + 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ability := tt.ability
+			ability.Name = tt.name
+			newCaster := func() Combatant {
+				m := newMockCombatant("Caster", 30, 0, 8, 0, 6)
+				if tt.level > 0 {
+					return &leveledCombatant{m, tt.level}
+				}
+				return m
+			}
+			target := newMockCombatant("Target", 100, 0, 0, 3, 0)
+			target.hp = 50 // Room to heal
+
+			var preview int
+			if ability.EffectType == gamedata.EffectHeal {
+				preview = resolver.CalculateHealing(&ability, newCaster())
+			} else {
+				preview = resolver.CalculateDamage(&ability, newCaster(), target)
+			}
+			if preview != tt.want {
+				t.Errorf("preview = %d, want %d", preview, tt.want)
+			}
+
+			result := resolver.apply(&ability, newCaster(), target)
+			if got := result.Damage + result.Healing; got != tt.want {
+				t.Errorf("resolved = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHPScalingDamage(t *testing.T) {
+	resolver := NewEffectResolver(nil)
+	execute := &gamedata.AbilityDef{
+		Name: "Execute", EffectType: gamedata.EffectDamage, DamageType: gamedata.DamagePhysical, BasePower: 2,
+		HPScaling: &gamedata.HPScaling{Of: gamedata.HPOfTarget, Power: 10, Threshold: 0.3},
+	}
+	desperation := &gamedata.AbilityDef{
+		Name: "Desperation", EffectType: gamedata.EffectDamage, DamageType: gamedata.DamagePhysical, BasePower: 2,
+		HPScaling: &gamedata.HPScaling{Of: gamedata.HPOfUser, Power: 12},
+	}
+
+	tests := []struct {
+		name     string
+		ability  *gamedata.AbilityDef
+		userHP   int // Of 40
+		targetHP int // Of 50
+		defense  int
+		want     int
+	}{
+		// User: 6 attack. Base damage 2 + 6 - defense.
+		{"execute against a healthy target", execute, 40, 50, 3, 2 + 6 - 3},
+		{"execute just above the threshold", execute, 40, 16, 3, 2 + 6 - 3},
+		{"execute at the threshold", execute, 40, 15, 3, 2 + 10 + 6 - 3},
+		{"execute against a dying target", execute, 40, 1, 3, 2 + 10 + 6 - 3},
+		{"desperation at full HP", desperation, 40, 50, 3, 2 + 6 - 3},
+		{"desperation at quarter HP", desperation, 10, 50, 3, 2 + 9 + 6 - 3},
+		{"bonus still floors at 1", desperation, 40, 50, 30, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := newMockCombatant("User", 40, 0, 6, 0, 0)
+			user.hp = tt.userHP
+			target := newMockCombatant("Target", 50, 0, 0, tt.defense, 0)
+			target.hp = tt.targetHP
+
+			if got := resolver.CalculateDamage(tt.ability, user, target); got != tt.want {
+				t.Errorf("preview = %d, want %d", got, tt.want)
+			}
+			if got := resolver.apply(tt.ability, user, target).Damage; got != min(tt.want, tt.targetHP) {
+				t.Errorf("resolved = %d, want %d", got, min(tt.want, tt.targetHP))
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package combat
+
+import "github.com/samdwyer/dungeonband/internal/gamedata"
+
+const (
+	// regenHealCapPercent caps regen healing per turn as a percentage of MaxHP.
+	regenHealCapPercent = 10
+
+	// attackDownPercent is the share of its attack a combatant keeps while
+	// under attack_down.
+	attackDownPercent = 75
+)
+
+// AddOrRefreshStatus adds an effect to the list, replacing any existing effect
+// of the same type. Refreshing regen while it is still active halves the new
+// effect's potency for each early refresh, so it cannot be chained for
+// unlimited sustain. The refresh count resets once regen expires.
+func AddOrRefreshStatus(effects []StatusEffect, effect StatusEffect) []StatusEffect {
+	for i, existing := range effects {
+		if existing.Type != effect.Type {
+			continue
+		}
+		if effect.Type == gamedata.StatusRegen {
+			effect.Refreshes = existing.Refreshes + 1
+			effect.Power = diminishedPower(effect.Power, effect.Refreshes)
+		}
+		effects[i] = effect
+		return effects
+	}
+	return append(effects, effect)
+}
+
+// RegenHealAmount returns how much a regen effect heals this turn,
+// capped relative to the target's MaxHP.
+func RegenHealAmount(effect StatusEffect, maxHP int) int {
+	limit := max(1, maxHP*regenHealCapPercent/100)
+	return min(effect.Power, limit)
+}
+
+// diminishedPower halves power once per refresh, never dropping below 1.
+func diminishedPower(power, refreshes int) int {
+	if power <= 0 {
+		return power
+	}
+	for i := 0; i < refreshes && power > 1; i++ {
+		power /= 2
+	}
+	return max(1, power)
+}
+
+// HasStatus returns true if the combatant has an active effect of the given type.
+func HasStatus(c Combatant, effectType gamedata.StatusEffectType) bool {
+	for _, e := range c.GetStatusEffects() {
+		if e.Type == effectType {
+			return true
+		}
+	}
+	return false
+}
+
+// effectiveAttack returns the combatant's attack after status effects.
+func effectiveAttack(c Combatant) int {
+	attack := c.GetAttack()
+	if HasStatus(c, gamedata.StatusAttackDown) {
+		attack = attack * attackDownPercent / 100
+	}
+	return attack
+}
+
+// BlockedBySilence returns true if silence prevents the user from using the
+// ability. Silence only blocks abilities that cost MP.
+func BlockedBySilence(ability *gamedata.AbilityDef, user Combatant) bool {
+	return ability.MPCost > 0 && HasStatus(user, gamedata.StatusSilence)
+}
@@ -0,0 +1,128 @@
+package combat
+
+import (
+	"testing"
+
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+)
+
+func TestRegenRefreshDiminishes(t *testing.T) {
+	// 100 max HP keeps the per-turn cap (10) out of the way
+	target := newMockCombatant("Warrior", 100, 0, 0, 0, 0)
+	regen := StatusEffect{Type: gamedata.StatusRegen, RemainingTurns: 3, Power: 8}
+
+	var heals []int
+	for i := 0; i < 4; i++ {
+		target.hp = 50
+		target.AddStatusEffect(regen)
+		ticks := target.TickStatusEffects()
+		if len(ticks) != 1 {
+			t.Fatalf("refresh %d: expected 1 tick, got %d", i, len(ticks))
+		}
+		heals = append(heals, ticks[0].Amount)
+	}
+
+	want := []int{8, 4, 2, 1}
+	for i := range want {
+		if heals[i] != want[i] {
+			t.Errorf("refresh %d healed %d, want %d (all: %v)", i, heals[i], want[i], heals)
+		}
+	}
+}
+
+func TestRegenResetsAfterExpiring(t *testing.T) {
+	target := newMockCombatant("Warrior", 100, 0, 0, 0, 0)
+	target.hp = 50
+	regen := StatusEffect{Type: gamedata.StatusRegen, RemainingTurns: 1, Power: 8}
+
+	target.AddStatusEffect(regen)
+	target.TickStatusEffects() // Expires
+
+	target.AddStatusEffect(regen)
+	ticks := target.TickStatusEffects()
+	if ticks[0].Amount != 8 {
+		t.Errorf("regen applied after expiry healed %d, want full 8", ticks[0].Amount)
+	}
+}
+
+func TestRegenHealCap(t *testing.T) {
+	// 10% of 30 MaxHP = 3 per turn
+	regen := StatusEffect{Type: gamedata.StatusRegen, RemainingTurns: 3, Power: 8}
+	if got := RegenHealAmount(regen, 30); got != 3 {
+		t.Errorf("RegenHealAmount(power 8, maxHP 30) = %d, want 3", got)
+	}
+	// Tiny MaxHP still heals at least 1
+	if got := RegenHealAmount(regen, 5); got != 1 {
+		t.Errorf("RegenHealAmount(power 8, maxHP 5) = %d, want 1", got)
+	}
+	// Power below the cap is unchanged
+	if got := RegenHealAmount(regen, 200); got != 8 {
+		t.Errorf("RegenHealAmount(power 8, maxHP 200) = %d, want 8", got)
+	}
+}
+
+func TestRefreshingOtherStatusesKeepsPower(t *testing.T) {
+	target := newMockCombatant("Orc", 30, 0, 0, 0, 0)
+	poison := StatusEffect{Type: gamedata.StatusPoison, RemainingTurns: 3, Power: 2}
+
+	target.AddStatusEffect(poison)
+	target.AddStatusEffect(poison)
+
+	effects := target.GetStatusEffects()
+	if len(effects) != 1 || effects[0].Power != 2 {
+		t.Errorf("poison refresh should replace without diminishing, got %+v", effects)
+	}
+}
+
+func TestSilenceBlocksOnlyMPAbilities(t *testing.T) {
+	caster := newMockCombatant("Caster", 10, 10, 0, 0, 0)
+	caster.AddStatusEffect(StatusEffect{Type: gamedata.StatusSilence, RemainingTurns: 2})
+
+	spell := &gamedata.AbilityDef{Name: "Fireball", EffectType: gamedata.EffectDamage, MPCost: 5}
+	attack := &gamedata.AbilityDef{Name: "Attack", EffectType: gamedata.EffectDamage}
+
+	if !BlockedBySilence(spell, caster) {
+		t.Error("silence should block MP spells")
+	}
+	if BlockedBySilence(attack, caster) {
+		t.Error("silence should not block free abilities")
+	}
+}
+
+func TestCleanseRemovesNegativeStatuses(t *testing.T) {
+	r := NewEffectResolver(nil)
+	user := newMockCombatant("Cleric", 10, 10, 0, 0, 0).knows("cleanse")
+	target := newMockCombatant("Wizard", 10, 0, 0, 0, 0)
+	target.AddStatusEffect(StatusEffect{Type: gamedata.StatusSilence, RemainingTurns: 2})
+	target.AddStatusEffect(StatusEffect{Type: gamedata.StatusRegen, RemainingTurns: 2, Power: 2})
+
+	cleanse := &gamedata.AbilityDef{ID: "cleanse", Name: "Cleanse", EffectType: gamedata.EffectCleanse, MPCost: 3}
+	r.Resolve(cleanse, user, target)
+
+	if HasStatus(target, gamedata.StatusSilence) {
+		t.Error("cleanse should remove silence")
+	}
+	if !HasStatus(target, gamedata.StatusRegen) {
+		t.Error("cleanse should keep positive statuses")
+	}
+}
+
+func TestZeroDurationBuffLastsOneTurn(t *testing.T) {
+	r := NewEffectResolver(nil)
+	user := newMockCombatant("Warrior", 30, 0, 0, 0, 0).knows("defend")
+
+	// A content mistake: defend with its statusDuration left out
+	defend := &gamedata.AbilityDef{
+		ID: "defend", Name: "Defend", EffectType: gamedata.EffectBuff,
+		TargetType: gamedata.TargetSelf, StatusEffect: gamedata.StatusDefenseUp,
+	}
+	r.Resolve(defend, user, user)
+
+	if !HasStatus(user, gamedata.StatusDefenseUp) {
+		t.Fatal("a zero-duration buff should still apply")
+	}
+	user.TickStatusEffects()
+	if HasStatus(user, gamedata.StatusDefenseUp) {
+		t.Error("a zero-duration buff should expire after one turn")
+	}
+}
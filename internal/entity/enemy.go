@@ -58,6 +58,8 @@ type Enemy struct {
 	MaxHP     int                // Maximum hit points
 	MP        int                // Current mana points
 	MaxMP     int                // Maximum mana points
+	Row       gamedata.Row       // Combat formation row
+	Abilities []string           // Ability IDs rolled at spawn (nil uses the Def's)
 
 	activeStatusEffects []combat.StatusEffect
 }
@@ -76,6 +78,7 @@ func NewEnemy(enemyType EnemyType, x, y, roomIndex int) *Enemy {
 		MaxHP:               10,
 		MP:                  0,
 		MaxMP:               0,
+		Row:                 gamedata.RowFront,
 		activeStatusEffects: []combat.StatusEffect{},
 	}
 }
@@ -93,6 +96,7 @@ func NewEnemyFromDef(def *gamedata.EnemyDef, x, y, roomIndex int) *Enemy {
 		MaxHP:               def.HP,
 		MP:                  0, // Enemies don't use MP currently
 		MaxMP:               0,
+		Row:                 def.StartingRow(),
 		activeStatusEffects: []combat.StatusEffect{},
 	}
 }
@@ -137,6 +141,14 @@ func (e *Enemy) Defense() int {
 	return 1 // Default
 }
 
+// Resist returns the enemy's magic defense value.
+func (e *Enemy) Resist() int {
+	if e.Def != nil {
+		return e.Def.Resist
+	}
+	return 0 // Default
+}
+
 // ID returns the enemy's unique type identifier.
 func (e *Enemy) ID() string {
 	if e.Def != nil {
@@ -145,6 +157,16 @@ func (e *Enemy) ID() string {
 	return e.Type.String()
 }
 
+// InBackRow returns true if the enemy stands in the back row.
+func (e *Enemy) InBackRow() bool {
+	return e.Row == gamedata.RowBack
+}
+
+// IsBoss returns true if the enemy is a boss.
+func (e *Enemy) IsBoss() bool {
+	return e.Def != nil && e.Def.Boss
+}
+
 // =============================================================================
 // Combatant interface implementation
 // =============================================================================
@@ -176,6 +198,9 @@ func (e *Enemy) GetDefense() int { return e.Defense() }
 // GetMagic returns magic stat (enemies default to 0).
 func (e *Enemy) GetMagic() int { return 0 }
 
+// GetResist returns magic defense stat.
+func (e *Enemy) GetResist() int { return e.Resist() }
+
 // TakeDamage reduces HP and returns actual damage taken.
 func (e *Enemy) TakeDamage(amount int) int {
 	if amount <= 0 {
@@ -226,6 +251,9 @@ func (e *Enemy) RestoreMP(amount int) int {
 
 // GetAbilityIDs returns the list of ability IDs this enemy can use.
 func (e *Enemy) GetAbilityIDs() []string {
+	if e.Abilities != nil {
+		return e.Abilities
+	}
 	if e.Def != nil {
 		return e.Def.Abilities
 	}
@@ -237,15 +265,15 @@ func (e *Enemy) GetStatusEffects() []combat.StatusEffect {
 	return e.activeStatusEffects
 }
 
+// HasStatus returns true if the enemy has an active effect of the given type.
+func (e *Enemy) HasStatus(effectType gamedata.StatusEffectType) bool {
+	return combat.HasStatus(e, effectType)
+}
+
 // AddStatusEffect adds or replaces a status effect.
+// Early regen refreshes are weakened (see combat.AddOrRefreshStatus).
 func (e *Enemy) AddStatusEffect(effect combat.StatusEffect) {
-	for i, existing := range e.activeStatusEffects {
-		if existing.Type == effect.Type {
-			e.activeStatusEffects[i] = effect
-			return
-		}
-	}
-	e.activeStatusEffects = append(e.activeStatusEffects, effect)
+	e.activeStatusEffects = combat.AddOrRefreshStatus(e.activeStatusEffects, effect)
 }
 
 // RemoveStatusEffect removes a status effect by type.
@@ -258,6 +286,11 @@ func (e *Enemy) RemoveStatusEffect(effectType gamedata.StatusEffectType) {
 	}
 }
 
+// ClearStatusEffects removes every status effect.
+func (e *Enemy) ClearStatusEffects() {
+	e.activeStatusEffects = nil
+}
+
 // TickStatusEffects processes turn-based status effects.
 func (e *Enemy) TickStatusEffects() []combat.StatusTick {
 	var ticks []combat.StatusTick
@@ -270,7 +303,7 @@ func (e *Enemy) TickStatusEffects() []combat.StatusTick {
 		case gamedata.StatusPoison:
 			tick.Amount = e.TakeDamage(effect.Power)
 		case gamedata.StatusRegen:
-			tick.Amount = e.Heal(effect.Power)
+			tick.Amount = e.Heal(combat.RegenHealAmount(effect, e.MaxHP))
 		}
 
 		effect.RemainingTurns--
@@ -0,0 +1,45 @@
+package entity
+
+// Item identifies a consumable the party carries.
+type Item string
+
+// ItemEscapeRope returns the party to the floor's starting room.
+const ItemEscapeRope Item = "escape_rope"
+
+// StartingEscapeRopes is how many escape ropes a new party carries.
+const StartingEscapeRopes = 1
+
+// Name returns the item's display name.
+func (i Item) Name() string {
+	switch i {
+	case ItemEscapeRope:
+		return "Escape Rope"
+	default:
+		return string(i)
+	}
+}
+
+// AddItem gives the party count more of the item.
+func (p *Party) AddItem(item Item, count int) {
+	if count <= 0 {
+		return
+	}
+	if p.Items == nil {
+		p.Items = make(map[Item]int)
+	}
+	p.Items[item] += count
+}
+
+// ItemCount returns how many of the item the party carries.
+func (p *Party) ItemCount(item Item) int {
+	return p.Items[item]
+}
+
+// UseItem spends one of the item. Returns false if the party has none.
+func (p *Party) UseItem(item Item) bool {
+	if p.Items[item] <= 0 {
+		return false
+	}
+	p.Items[item]--
+	return true
+}
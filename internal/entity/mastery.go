@@ -0,0 +1,42 @@
+package entity
+
+import "github.com/samdwyer/dungeonband/internal/combat"
+
+// MasteryThresholds are the casts of an ability at which a member's
+// mastery of it reaches each tier: the 10th cast is the first at tier 1.
+var MasteryThresholds = []int{10, 50}
+
+// MasteryTier returns the mastery tier of the given cast of an ability,
+// counting from 1.
+func MasteryTier(cast int) int {
+	tier := 0
+	for _, threshold := range MasteryThresholds {
+		if cast >= threshold {
+			tier++
+		}
+	}
+	return tier
+}
+
+// Mastery returns the member's mastery tier for their next cast of the ability.
+func (m *Member) Mastery(abilityID string) int {
+	return MasteryTier(m.AbilityUses[abilityID] + 1)
+}
+
+// RecordUse counts a successful cast of the ability. Returns true when the
+// next cast reaches a new mastery tier.
+func (m *Member) RecordUse(abilityID string) bool {
+	if m.AbilityUses == nil {
+		m.AbilityUses = make(map[string]int)
+	}
+	before := m.Mastery(abilityID)
+	m.AbilityUses[abilityID]++
+	return m.Mastery(abilityID) > before
+}
+
+// CastModifiers returns the member's mastery bonuses for their next cast of
+// the ability: each tier takes 1 MP off its cost and adds 1 to its power.
+func (m *Member) CastModifiers(abilityID string) combat.CastModifiers {
+	tier := m.Mastery(abilityID)
+	return combat.CastModifiers{MPCostReduction: tier, PowerBonus: tier}
+}
@@ -16,6 +16,9 @@ const (
 	ClassCleric
 )
 
+// Classes lists every class in the order the party setup offers them.
+var Classes = []Class{ClassWarrior, ClassRogue, ClassWizard, ClassCleric}
+
 // String returns the class name.
 func (c Class) String() string {
 	switch c {
@@ -77,27 +80,34 @@ type Member struct {
 	Attack              int
 	Defense             int
 	Magic               int
+	Resist              int
 	AbilityIDs          []string
+	AbilityUses         map[string]int // Successful casts of each ability, for mastery
 	activeStatusEffects []combat.StatusEffect
 }
 
 // NewMember creates a new party member with the given name and class.
 // Stats are set to default values; use InitFromClassDef to load from data.
 func NewMember(name string, class Class) *Member {
-	return &Member{
+	m := &Member{
 		Name:                name,
 		Class:               class,
 		Symbol:              class.Symbol(),
-		HP:                  20, // Default stats
-		MaxHP:               20,
-		MP:                  10,
-		MaxMP:               10,
-		Attack:              5,
-		Defense:             3,
-		Magic:               3,
-		AbilityIDs:          []string{"attack", "defend"},
 		activeStatusEffects: []combat.StatusEffect{},
 	}
+	m.resetStats()
+	return m
+}
+
+// resetStats gives the member the default stats used without class data.
+func (m *Member) resetStats() {
+	m.HP, m.MaxHP = 20, 20
+	m.MP, m.MaxMP = 10, 10
+	m.Attack = 5
+	m.Defense = 3
+	m.Magic = 3
+	m.Resist = 0
+	m.AbilityIDs = []string{"attack", "defend"}
 }
 
 // InitFromClassDef initializes member stats from a class definition.
@@ -112,10 +122,24 @@ func (m *Member) InitFromClassDef(def *gamedata.ClassDef) {
 	m.Attack = def.Attack
 	m.Defense = def.Defense
 	m.Magic = def.Magic
+	m.Resist = def.Resist
 	m.AbilityIDs = make([]string, len(def.Abilities))
 	copy(m.AbilityIDs, def.Abilities)
 }
 
+// SetClass changes the member's class, resetting their stats and abilities
+// from def, or to the defaults when def is nil so nothing of the old class
+// is kept.
+func (m *Member) SetClass(class Class, def *gamedata.ClassDef) {
+	m.Class = class
+	m.Symbol = class.Symbol()
+	if def == nil {
+		m.resetStats()
+		return
+	}
+	m.InitFromClassDef(def)
+}
+
 // SetPosition updates the member's position.
 func (m *Member) SetPosition(x, y int) {
 	m.X = x
@@ -153,6 +177,9 @@ func (m *Member) GetDefense() int { return m.Defense }
 // GetMagic returns magic stat.
 func (m *Member) GetMagic() int { return m.Magic }
 
+// GetResist returns magic defense stat.
+func (m *Member) GetResist() int { return m.Resist }
+
 // TakeDamage reduces HP and returns actual damage taken.
 func (m *Member) TakeDamage(amount int) int {
 	if amount <= 0 {
@@ -211,16 +238,15 @@ func (m *Member) GetStatusEffects() []combat.StatusEffect {
 	return m.activeStatusEffects
 }
 
+// HasStatus returns true if the member has an active effect of the given type.
+func (m *Member) HasStatus(effectType gamedata.StatusEffectType) bool {
+	return combat.HasStatus(m, effectType)
+}
+
 // AddStatusEffect adds or replaces a status effect.
+// Early regen refreshes are weakened (see combat.AddOrRefreshStatus).
 func (m *Member) AddStatusEffect(effect combat.StatusEffect) {
-	// Replace existing effect of same type
-	for i, existing := range m.activeStatusEffects {
-		if existing.Type == effect.Type {
-			m.activeStatusEffects[i] = effect
-			return
-		}
-	}
-	m.activeStatusEffects = append(m.activeStatusEffects, effect)
+	m.activeStatusEffects = combat.AddOrRefreshStatus(m.activeStatusEffects, effect)
 }
 
 // RemoveStatusEffect removes a status effect by type.
@@ -233,6 +259,11 @@ func (m *Member) RemoveStatusEffect(effectType gamedata.StatusEffectType) {
 	}
 }
 
+// ClearStatusEffects removes every status effect.
+func (m *Member) ClearStatusEffects() {
+	m.activeStatusEffects = nil
+}
+
 // TickStatusEffects processes turn-based status effects.
 func (m *Member) TickStatusEffects() []combat.StatusTick {
 	var ticks []combat.StatusTick
@@ -245,7 +276,7 @@ func (m *Member) TickStatusEffects() []combat.StatusTick {
 		case gamedata.StatusPoison:
 			tick.Amount = m.TakeDamage(effect.Power)
 		case gamedata.StatusRegen:
-			tick.Amount = m.Heal(effect.Power)
+			tick.Amount = m.Heal(combat.RegenHealAmount(effect, m.MaxHP))
 		}
 
 		effect.RemainingTurns--
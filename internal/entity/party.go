@@ -3,6 +3,19 @@ package entity
 
 import "github.com/samdwyer/dungeonband/internal/gamedata"
 
+// TrailLength is the number of previously visited tiles remembered for the
+// follower trail drawn behind the party in explore mode.
+const TrailLength = 3
+
+// PartySize is how many members a new party has.
+const PartySize = 4
+
+// TrailPoint is a previously visited party position.
+type TrailPoint struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
 // Party represents the player's party of adventurers.
 // In explore mode, the party is displayed as a single symbol.
 // In combat mode, individual members are displayed.
@@ -10,6 +23,17 @@ type Party struct {
 	X, Y    int       // Current position in the dungeon (party center)
 	Symbol  rune      // Display symbol ('&' in explore mode)
 	Members []*Member // Individual party members
+
+	// Trail holds recently visited positions, most recent first.
+	// It is cosmetic only; collision and combat use X, Y.
+	Trail []TrailPoint
+
+	// Items counts the consumables the party carries.
+	Items map[Item]int
+
+	// Scout is a member detached to explore on their own, standing at
+	// their own X, Y. nil while the party is together.
+	Scout *Member
 }
 
 // NewParty creates a new party at the given position with default members.
@@ -24,6 +48,7 @@ func NewParty(x, y int) *Party {
 			NewMember("Zephyr", ClassWizard),
 			NewMember("Celeste", ClassCleric),
 		},
+		Items: map[Item]int{ItemEscapeRope: StartingEscapeRopes},
 	}
 }
 
@@ -42,10 +67,34 @@ func NewPartyWithClassData(x, y int, classRegistry *gamedata.ClassRegistry) *Par
 	return party
 }
 
-// Move updates the party position by the given delta.
+// Move updates the party position by the given delta and records the
+// previous position in the follower trail.
 func (p *Party) Move(dx, dy int) {
+	prev := TrailPoint{p.X, p.Y}
 	p.X += dx
 	p.Y += dy
+
+	// Stepping back onto the trail collapses it at that point so followers
+	// never stand on the lead.
+	trail := []TrailPoint{prev}
+	for _, pt := range p.Trail {
+		if pt.X == p.X && pt.Y == p.Y {
+			break
+		}
+		trail = append(trail, pt)
+	}
+	if len(trail) > TrailLength {
+		trail = trail[:TrailLength]
+	}
+	p.Trail = trail
+}
+
+// SetPosition places the party at a new position and clears the trail.
+// Use this for teleports and floor changes.
+func (p *Party) SetPosition(x, y int) {
+	p.X = x
+	p.Y = y
+	p.Trail = nil
 }
 
 // Position returns the current x, y coordinates.
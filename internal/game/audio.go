@@ -0,0 +1,47 @@
+package game
+
+import (
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/samdwyer/dungeonband/internal/audio"
+	"github.com/samdwyer/dungeonband/internal/combat"
+)
+
+// bellRequest is posted to the event loop to ring the bell once.
+type bellRequest struct{}
+
+// BellRinger returns a bell for audio.New that is safe to ring from the
+// player's background goroutine: it asks the game loop to ring the
+// display's bell rather than touching the screen while a frame is drawn.
+func BellRinger(display Display) func() error {
+	return func() error {
+		return display.PostEvent(tcell.NewEventInterrupt(bellRequest{}))
+	}
+}
+
+// AudioSink plays named sound cues (the audio.Cue* names) for game events.
+// audio.Player is the usual sink.
+type AudioSink interface {
+	PlayCue(name string)
+}
+
+// playCue sends a cue to the audio sink, if there is one.
+func (g *Game) playCue(name string) {
+	if g.audio != nil {
+		g.audio.PlayCue(name)
+	}
+}
+
+// playActionCues plays the cues for one resolved action as the stats
+// collector records it: the hit or heal, then a death if it killed.
+func (g *Game) playActionCues(result combat.EffectResult, killed bool) {
+	switch {
+	case result.Damage > 0:
+		g.playCue(audio.CueHit)
+	case result.Healing > 0:
+		g.playCue(audio.CueHeal)
+	}
+	if killed {
+		g.playCue(audio.CueDeath)
+	}
+}
@@ -0,0 +1,78 @@
+package game
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/samdwyer/dungeonband/internal/audio"
+	"github.com/samdwyer/dungeonband/internal/entity"
+)
+
+// recordingSink remembers every cue played, in order.
+type recordingSink struct {
+	cues []string
+}
+
+func (s *recordingSink) PlayCue(name string) { s.cues = append(s.cues, name) }
+
+func TestScriptedFightPlaysCues(t *testing.T) {
+	g := newTestGame(t)
+	sink := &recordingSink{}
+	g.audio = sink
+	ctx := context.Background()
+
+	goblin := entity.NewEnemy(entity.EnemyGoblin, g.party.X+1, g.party.Y, 0)
+	g.enemies = []*entity.Enemy{goblin}
+	g.transitionState(ctx, StateCombat, "test")
+
+	warrior, cleric := g.party.Members[0], g.party.Members[3]
+	warrior.TakeDamage(10)
+	g.executeCombatTurn(ctx, g.abilityRegistry.GetByID("heal"), cleric, warrior)
+	g.executeCombatTurn(ctx, g.abilityRegistry.GetByID("attack"), warrior, goblin)
+	g.checkCombatEnd()
+
+	want := []string{audio.CueHeal, audio.CueHit, audio.CueDeath, audio.CueVictory}
+	if !slices.Equal(sink.cues, want) {
+		t.Errorf("cues = %q, want %q", sink.cues, want)
+	}
+}
+
+func TestHeadlessGameIsSilent(t *testing.T) {
+	g, err := New(Config{Seed: 1, Audio: &recordingSink{}}, NullDisplay{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.audio != nil {
+		t.Error("a game on a NullDisplay should have no audio sink")
+	}
+}
+
+// beepCounter counts the bells rung on a display.
+type beepCounter struct {
+	Display
+	beeps int
+}
+
+func (d *beepCounter) Beep() error {
+	d.beeps++
+	return nil
+}
+
+func TestBellRingsFromTheGameLoop(t *testing.T) {
+	g, _ := newTestGameWithScreen(t)
+	display := &beepCounter{Display: g.display}
+	g.display = display
+
+	ring := BellRinger(display)
+	if err := ring(); err != nil {
+		t.Fatalf("ring: %v", err)
+	}
+	if display.beeps != 0 {
+		t.Fatal("the bell rang before the game loop handled it")
+	}
+	g.handleInput(context.Background())
+	if display.beeps != 1 {
+		t.Errorf("beeps = %d, want 1 once the loop handles the request", display.beeps)
+	}
+}
@@ -0,0 +1,88 @@
+package game
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+)
+
+// maxEncounterActions bounds runEncounter so a stalled fight can't hang.
+const maxEncounterActions = 500
+
+// benchRoom is an open room big enough for the formation and a few enemies.
+const benchRoom = `
+##############
+#............#
+#............#
+#............#
+#............#
+##############`
+
+// newBenchGame builds a headless game on the bench room.
+func newBenchGame(tb testing.TB) *Game {
+	tb.Helper()
+	g := newTestGame(tb)
+	g.dungeon = dungeonFromMap(benchRoom)
+	return g
+}
+
+// startEncounter resets the party and starts combat against a goblin, an orc
+// and a back-row skeleton standing next to the formation.
+func startEncounter(g *Game) {
+	g.party = entity.NewPartyWithClassData(5, 2, g.classRegistry)
+	g.enemies = []*entity.Enemy{
+		entity.NewEnemyFromDef(g.enemyRegistry.GetByID("goblin"), 6, 2, 0),
+		entity.NewEnemyFromDef(g.enemyRegistry.GetByID("orc"), 6, 3, 0),
+		entity.NewEnemyFromDef(g.enemyRegistry.GetByID("skeleton"), 8, 2, 0),
+	}
+	g.state = StateCombat
+	g.combatEnemies = g.enemies
+	g.initCombatState(context.Background())
+}
+
+// runEncounter plays the current encounter with every member attacking until
+// one side wins. Returns the final phase.
+func runEncounter(g *Game) CombatPhase {
+	ctx := context.Background()
+	enter := tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)
+
+	for i := 0; i < maxEncounterActions; i++ {
+		switch g.combatState.Phase {
+		case PhaseVictory, PhaseDefeat:
+			return g.combatState.Phase
+		case PhaseSelectTarget:
+			g.handleTargetSelectionKey(ctx, enter)
+		default:
+			g.handleCombatAbilitySelection(ctx, 0) // Attack
+		}
+	}
+	return g.combatState.Phase
+}
+
+func TestRunEncounterCompletes(t *testing.T) {
+	g := newBenchGame(t)
+	startEncounter(g)
+
+	phase := runEncounter(g)
+
+	if phase != PhaseVictory && phase != PhaseDefeat {
+		t.Fatalf("encounter ended in phase %v, want victory or defeat", phase)
+	}
+	if g.combatState.TurnCount == 0 {
+		t.Error("encounter finished without any turns")
+	}
+}
+
+func BenchmarkFullEncounter(b *testing.B) {
+	g := newBenchGame(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		startEncounter(g)
+		runEncounter(g)
+	}
+}
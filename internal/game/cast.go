@@ -0,0 +1,208 @@
+package game
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/samdwyer/dungeonband/internal/combat"
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+	"github.com/samdwyer/dungeonband/internal/telemetry"
+)
+
+// castStep is the choice the explore cast menu is waiting on.
+type castStep int
+
+const (
+	castPickMember castStep = iota
+	castPickAbility
+	castPickTarget
+)
+
+// castMenu is the open 'z' menu for casting abilities while exploring.
+type castMenu struct {
+	step    castStep
+	caster  *entity.Member
+	ability *gamedata.AbilityDef
+}
+
+// openCastMenu starts the explore cast menu, or explains why it can't.
+func (g *Game) openCastMenu() {
+	if len(g.castersOutOfCombat()) == 0 {
+		g.message = "Nobody can cast anything outside of combat."
+		return
+	}
+	g.casting = &castMenu{step: castPickMember}
+}
+
+// castersOutOfCombat returns living members with at least one ability they
+// can use while exploring.
+func (g *Game) castersOutOfCombat() []*entity.Member {
+	var casters []*entity.Member
+	for _, m := range g.party.Members {
+		if m.IsAlive() && len(g.outOfCombatAbilities(m)) > 0 {
+			casters = append(casters, m)
+		}
+	}
+	return casters
+}
+
+// outOfCombatAbilities returns the member's abilities usable while exploring.
+func (g *Game) outOfCombatAbilities(m *entity.Member) []*gamedata.AbilityDef {
+	var abilities []*gamedata.AbilityDef
+	for _, id := range m.GetAbilityIDs() {
+		if a := g.abilityRegistry.GetByID(id); a != nil && a.UsableOutOfCombat() {
+			abilities = append(abilities, a)
+		}
+	}
+	return abilities
+}
+
+// castPrompt returns the prompt for the cast menu's current step.
+func (g *Game) castPrompt() string {
+	var b strings.Builder
+	switch g.casting.step {
+	case castPickMember:
+		b.WriteString("Who casts?")
+		for i, m := range g.castersOutOfCombat() {
+			b.WriteString(" " + itoa(i+1) + " " + m.GetName())
+		}
+	case castPickAbility:
+		b.WriteString(g.casting.caster.GetName() + " casts:")
+		for i, a := range g.outOfCombatAbilities(g.casting.caster) {
+			b.WriteString(" " + itoa(i+1) + " " + a.Name + " (" + itoa(combat.MPCost(a, g.casting.caster)) + " MP")
+			if a.StatusEffect != "" && a.StatusDuration > 0 {
+				b.WriteString(", " + itoa(a.StatusDuration*exploreStepsPerTurn) + " steps")
+			}
+			b.WriteString(")")
+		}
+	case castPickTarget:
+		b.WriteString(g.casting.ability.Name + " whom?")
+		for i, m := range g.aliveMembers() {
+			b.WriteString(" " + itoa(i+1) + " " + m.GetName() + " " + itoa(m.GetHP()) + "/" + itoa(m.GetMaxHP()))
+		}
+	}
+	b.WriteString(" (Esc cancels)")
+	return b.String()
+}
+
+// handleCastKey resolves a key press while the cast menu is open. Number keys
+// pick from the current list; Escape closes the menu.
+func (g *Game) handleCastKey(ctx context.Context, ev *tcell.EventKey) {
+	if ev.Key() == tcell.KeyEscape {
+		g.casting = nil
+		return
+	}
+	if ev.Key() != tcell.KeyRune || ev.Rune() < '1' || ev.Rune() > '9' {
+		return
+	}
+	index := int(ev.Rune() - '1')
+
+	switch g.casting.step {
+	case castPickMember:
+		casters := g.castersOutOfCombat()
+		if index < len(casters) {
+			g.casting.caster = casters[index]
+			g.casting.step = castPickAbility
+		}
+	case castPickAbility:
+		abilities := g.outOfCombatAbilities(g.casting.caster)
+		if index >= len(abilities) {
+			return
+		}
+		ability := abilities[index]
+		switch ability.TargetType {
+		case gamedata.TargetSingleAlly:
+			g.casting.ability = ability
+			g.casting.step = castPickTarget
+		case gamedata.TargetAllAllies:
+			var targets []combat.Combatant
+			for _, m := range g.aliveMembers() {
+				targets = append(targets, m)
+			}
+			g.castOutOfCombat(ctx, g.casting.caster, ability, targets)
+		default:
+			g.castOutOfCombat(ctx, g.casting.caster, ability, []combat.Combatant{g.casting.caster})
+		}
+	case castPickTarget:
+		members := g.aliveMembers()
+		if index < len(members) {
+			g.castOutOfCombat(ctx, g.casting.caster, g.casting.ability, []combat.Combatant{members[index]})
+		}
+	}
+}
+
+// aliveMembers returns the living party members in party order.
+func (g *Game) aliveMembers() []*entity.Member {
+	var alive []*entity.Member
+	for _, m := range g.party.Members {
+		if m.IsAlive() {
+			alive = append(alive, m)
+		}
+	}
+	return alive
+}
+
+// castOutOfCombat resolves an ability cast while exploring, paying its normal
+// MP cost, closes the cast menu and reports the result below the map.
+func (g *Game) castOutOfCombat(ctx context.Context, caster *entity.Member, ability *gamedata.AbilityDef, targets []combat.Combatant) {
+	g.casting = nil
+
+	tracer := telemetry.Tracer("game")
+	_, span := tracer.Start(ctx, "game.cast")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("actor", caster.GetName()),
+		attribute.String("ability", ability.ID),
+		attribute.Int("target_count", len(targets)),
+	)
+
+	results := g.effectResolver.ResolveAll(ability, caster, targets)
+	succeeded := 0
+	totalHealing := 0
+	for _, result := range results {
+		if result.Success {
+			succeeded++
+			totalHealing += result.Healing
+		}
+	}
+	if len(results) != len(targets) || succeeded == 0 {
+		g.message = results[0].Message
+		span.SetAttributes(attribute.Bool("failed", true))
+		return
+	}
+
+	g.message = castMessage(caster, ability, targets, results) + g.noteUse(caster, ability)
+	span.SetAttributes(
+		attribute.Int("healing", totalHealing),
+		attribute.Int("failed_targets", len(results)-succeeded),
+	)
+}
+
+// castMessage reports a cast that worked on at least one target: who was
+// healed, and who it failed on when it didn't work on everyone.
+func castMessage(caster *entity.Member, ability *gamedata.AbilityDef, targets []combat.Combatant, results []combat.EffectResult) string {
+	message := caster.GetName() + " casts " + ability.Name + "!"
+	if len(targets) == 1 {
+		message = results[0].Message
+	}
+	var healed, failed []string
+	for i, result := range results {
+		switch {
+		case !result.Success:
+			failed = append(failed, targets[i].GetName())
+		case result.Healing > 0:
+			healed = append(healed, targets[i].GetName()+" heals "+itoa(result.Healing)+" HP")
+		}
+	}
+	if len(healed) > 0 {
+		message += " " + strings.Join(healed, ", ") + "!"
+	}
+	if len(failed) > 0 {
+		message += " It fails on " + strings.Join(failed, ", ") + "."
+	}
+	return message
+}
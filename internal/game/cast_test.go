@@ -0,0 +1,127 @@
+package game
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/samdwyer/dungeonband/internal/combat"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+)
+
+// press sends rune key presses through the normal input path.
+func press(g *Game, runes ...rune) {
+	for _, r := range runes {
+		g.handleKeyEvent(context.Background(), pressRune(r))
+	}
+}
+
+func TestCastMenuOffersOnlyOutOfCombatAbilities(t *testing.T) {
+	g := newTestGame(t)
+	cleric := g.party.Members[3]
+
+	// Everyone knows Defend, which can be pre-cast
+	if casters := g.castersOutOfCombat(); len(casters) != len(g.party.Members) {
+		t.Fatalf("got %d casters, want the whole party", len(casters))
+	}
+
+	var ids []string
+	for _, a := range g.outOfCombatAbilities(cleric) {
+		ids = append(ids, a.ID)
+	}
+	if got := strings.Join(ids, ","); got != "defend,heal,group_heal,cleanse" {
+		t.Errorf("cleric out-of-combat abilities = %s, want defend,heal,group_heal,cleanse", got)
+	}
+	for _, a := range g.outOfCombatAbilities(g.party.Members[0]) {
+		if a.ID == "taunt" {
+			t.Error("taunt isn't pre-castable and shouldn't be offered")
+		}
+	}
+}
+
+func TestCastHealOutOfCombatSpendsMP(t *testing.T) {
+	g := newTestGame(t)
+	warrior, cleric := g.party.Members[0], g.party.Members[3]
+	warrior.HP = 5
+	mp := cleric.MP
+
+	press(g, 'z', '4', '2') // Cleric; Heal
+	if g.casting == nil || g.casting.step != castPickTarget {
+		t.Fatal("heal should ask for a target")
+	}
+	press(g, '1') // Warrior
+
+	if g.casting != nil {
+		t.Error("cast menu should close after casting")
+	}
+	if cleric.MP != mp-4 {
+		t.Errorf("cleric MP = %d, want %d", cleric.MP, mp-4)
+	}
+	if warrior.HP <= 5 {
+		t.Errorf("warrior HP = %d, want healed above 5", warrior.HP)
+	}
+	if !strings.Contains(g.message, "heals") {
+		t.Errorf("message = %q, want the healing logged", g.message)
+	}
+}
+
+func TestCastOutOfCombatNeedsMP(t *testing.T) {
+	g := newTestGame(t)
+	cleric := g.party.Members[3]
+	cleric.MP = 1
+
+	press(g, 'z', '4', '3') // Group Heal needs no target pick
+
+	if cleric.MP != 1 {
+		t.Errorf("cleric MP = %d, want unchanged at 1", cleric.MP)
+	}
+	if !strings.Contains(g.message, "enough MP") {
+		t.Errorf("message = %q, want an MP warning", g.message)
+	}
+}
+
+func TestCastMessageReportsHitsAndMisses(t *testing.T) {
+	g := newTestGame(t)
+	warrior, rogue, cleric := g.party.Members[0], g.party.Members[1], g.party.Members[3]
+	groupHeal := g.abilityRegistry.GetByID("group_heal")
+	targets := []combat.Combatant{warrior, rogue}
+	results := []combat.EffectResult{
+		{Success: true, Healing: 6},
+		{Success: false, Message: "Shade can't be healed!"},
+	}
+
+	got := castMessage(cleric, groupHeal, targets, results)
+	want := "Celeste casts Group Heal! Aldric heals 6 HP! It fails on Shade."
+	if got != want {
+		t.Errorf("message = %q, want %q", got, want)
+	}
+}
+
+func TestPreCastBuffWearsOffWhileWalking(t *testing.T) {
+	g := newTestGame(t)
+	g.dungeon = dungeonFromMap(`
+##########
+#........#
+##########`)
+	g.party.SetPosition(1, 1)
+	warrior := g.party.Members[0]
+
+	press(g, 'z', '1')
+	if prompt := g.castPrompt(); !strings.Contains(prompt, "Defend (0 MP, 6 steps)") {
+		t.Errorf("prompt = %q, want Defend's duration in steps", prompt)
+	}
+	press(g, '1') // Defend targets the caster
+	if !warrior.HasStatus(gamedata.StatusDefenseUp) {
+		t.Fatal("pre-cast Defend should apply defense_up")
+	}
+
+	steps := 0
+	for warrior.HasStatus(gamedata.StatusDefenseUp) && steps < 20 {
+		g.party.SetPosition(1, 1)
+		g.tryMove(context.Background(), 1, 0)
+		steps++
+	}
+	if steps != 2*exploreStepsPerTurn {
+		t.Errorf("defense_up wore off after %d steps, want %d", steps, 2*exploreStepsPerTurn)
+	}
+}
@@ -2,13 +2,21 @@ package game
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
+	"github.com/samdwyer/dungeonband/internal/audio"
 	"github.com/samdwyer/dungeonband/internal/combat"
 	"github.com/samdwyer/dungeonband/internal/entity"
 	"github.com/samdwyer/dungeonband/internal/gamedata"
+	"github.com/samdwyer/dungeonband/internal/seed"
 	"github.com/samdwyer/dungeonband/internal/telemetry"
+	"github.com/samdwyer/dungeonband/internal/ui"
 )
 
 // CombatPhase represents the current phase of combat.
@@ -23,6 +31,8 @@ const (
 	PhaseVictory
 	// PhaseDefeat - all party members defeated
 	PhaseDefeat
+	// PhaseSelectTarget - player is choosing a target for the selected ability
+	PhaseSelectTarget
 )
 
 // String returns a human-readable phase name.
@@ -36,6 +46,8 @@ func (p CombatPhase) String() string {
 		return "victory"
 	case PhaseDefeat:
 		return "defeat"
+	case PhaseSelectTarget:
+		return "select_target"
 	default:
 		return "unknown"
 	}
@@ -43,6 +55,7 @@ func (p CombatPhase) String() string {
 
 // CombatState holds all state for an active combat encounter.
 type CombatState struct {
+	EncounterID       string // Stamped on every span of this fight
 	Phase             CombatPhase
 	Enemies           []*entity.Enemy
 	ActiveMemberIndex int                  // Which party member is acting (0-3)
@@ -50,6 +63,40 @@ type CombatState struct {
 	TurnCount         int                  // Total turns taken
 	LastMessage       string               // Message to display from last action
 	SelectedAbility   *gamedata.AbilityDef // Ability selected by current actor
+	TargetIndex       int                  // Enemy or party member index under the target cursor
+	TargetOnAllies    bool                 // Target cursor is on the party rather than the enemies
+	Positional        bool                 // Melee range and movement apply (false if no formation fit)
+	EnemyActions      []string             // What each enemy did this enemy phase, oldest first
+	Difficulty        combat.Difficulty    // Estimated when the fight started
+	AbilityPage       int                  // Page of the active member's abilities on the number keys
+	Round             int                  // Current round, counting from 1
+	Theme             *gamedata.ThemeDef   // Theme of the room the fight started in, if any
+
+	deathsResolved map[*entity.Enemy]bool              // Enemies whose on-death effects have fired
+	deathNotes     string                              // On-death effects set off by the latest action
+	threat         threatTable                         // Threat each member has built against each enemy
+	startHP        int                                 // Party HP when the fight started
+	startAlive     int                                 // Living members when the fight started
+	pendingRises   []*pendingRise                      // Undead waiting to rise, in the order they fell
+	rises          map[*entity.Enemy]int               // Times each undying enemy has risen this fight
+	themeRows      map[*entity.Enemy]gamedata.Row      // Rows the room's theme overrode, put back when the fight ends
+	partyQueue     []turnSlot                          // Party actions left this round after the active member's
+	queuedRound    int                                 // Round partyQueue was built for
+	planned        []plannedAction                     // Party actions chosen this round, in plan mode
+	cooldowns      map[combat.Combatant]map[string]int // Round each ability is ready again, by user
+}
+
+// encounterAttr returns the attribute tying a span to this encounter.
+func (cs *CombatState) encounterAttr() attribute.KeyValue {
+	return attribute.String("encounter.id", cs.EncounterID)
+}
+
+// actingEnemy returns the enemy whose turn it is, or nil if there is none.
+func (cs *CombatState) actingEnemy() *entity.Enemy {
+	if cs.ActiveEnemyIndex < 0 || cs.ActiveEnemyIndex >= len(cs.Enemies) {
+		return nil
+	}
+	return cs.Enemies[cs.ActiveEnemyIndex]
 }
 
 // NewCombatState creates a new combat state for an encounter.
@@ -60,7 +107,9 @@ func NewCombatState(enemies []*entity.Enemy) *CombatState {
 		ActiveMemberIndex: 0,
 		ActiveEnemyIndex:  0,
 		TurnCount:         0,
+		Round:             1,
 		LastMessage:       "Combat begins!",
+		threat:            make(threatTable),
 	}
 }
 
@@ -99,41 +148,127 @@ func (cs *CombatState) GetAliveEnemy(index int) *entity.Enemy {
 	return nil
 }
 
+// CanReach returns true if the ability can target the enemy from the party's position.
+// Back-row enemies are only reachable by ranged or magical abilities.
+func (cs *CombatState) CanReach(ability *gamedata.AbilityDef, enemy *entity.Enemy) bool {
+	return !enemy.InBackRow() || ability.ReachesBackRow()
+}
+
+// GetFirstReachableEnemy returns the first alive enemy the ability can reach, or nil.
+func (cs *CombatState) GetFirstReachableEnemy(ability *gamedata.AbilityDef) *entity.Enemy {
+	for _, e := range cs.Enemies {
+		if e.IsAlive() && cs.CanReach(ability, e) {
+			return e
+		}
+	}
+	return nil
+}
+
+// PromoteBackRow moves living back-row enemies to the front once no living
+// front-row enemies remain. Returns the promoted enemies.
+func (cs *CombatState) PromoteBackRow() []*entity.Enemy {
+	for _, e := range cs.Enemies {
+		if e.IsAlive() && !e.InBackRow() {
+			return nil
+		}
+	}
+
+	var promoted []*entity.Enemy
+	for _, e := range cs.Enemies {
+		if e.IsAlive() && e.InBackRow() {
+			e.Row = gamedata.RowFront
+			promoted = append(promoted, e)
+		}
+	}
+	return promoted
+}
+
 // =============================================================================
 // Combat Loop Methods on Game
 // =============================================================================
 
 // initCombatState initializes combat state when entering combat.
 func (g *Game) initCombatState(ctx context.Context) {
+	g.encounters++
+	encounterID := g.newEncounterID()
+	audit := g.dice != nil && g.dice.Auditing()
+	g.dice = combat.NewDice(g.stream(seed.Combat, g.encounters), audit)
+
 	tracer := telemetry.Tracer("combat")
 	_, span := tracer.Start(ctx, "combat.start")
 	span.SetAttributes(
+		attribute.String("encounter.id", encounterID),
 		attribute.Int("party_size", g.party.AliveMemberCount()),
 		attribute.Int("enemy_count", len(g.combatEnemies)),
+		attribute.String("encounter.kind", g.encounterKind()),
 	)
-	span.End()
+	if boss := g.combatBoss(); boss != nil {
+		span.SetAttributes(attribute.String("encounter.boss_id", boss.ID()))
+	}
 
 	g.combatState = NewCombatState(g.combatEnemies)
+	g.combatState.EncounterID = encounterID
+	g.lastTurn = nil
+	g.rewinds = rewindsPerEncounter
+	if g.cfg.Hardcore {
+		g.rewinds = 0
+	}
+	g.applyTheme(g.roomTheme(g.dungeon.RoomIndexAt(g.party.X, g.party.Y)))
+	g.combatState.Positional = g.placeFormation()
+	g.estimateDifficulty()
+	span.SetAttributes(attribute.Bool("formation.positional", g.combatState.Positional))
+	if theme := g.combatState.Theme; theme != nil {
+		span.SetAttributes(attribute.String("room.theme", theme.ID))
+	}
+	span.SetAttributes(difficultyAttrs(g.combatState.Difficulty)...)
+	span.End()
+	g.stats.startCombat()
+	g.startRecording()
+	g.dice.Reset()
 
-	// Find first alive member
-	g.combatState.ActiveMemberIndex = 0
-	for i, m := range g.party.Members {
-		if m.IsAlive() {
-			g.combatState.ActiveMemberIndex = i
-			break
+	// An encounter with no front row starts with the back row stepping up
+	g.combatState.PromoteBackRow()
+
+	// Every round of a fight carries to the groups nearby
+	g.makeNoise(g.party.X, g.party.Y, noiseCombatRound)
+	g.startPartyRound()
+}
+
+// newEncounterID derives the current encounter's ID from the run's seed and
+// how many encounters came before it, so a replayed run reuses the same IDs
+// without drawing from the game's RNG.
+func (g *Game) newEncounterID() string {
+	return fmt.Sprintf("%016x-%d", uint64(g.seed), g.encounters)
+}
+
+// combatBoss returns the first boss in the current encounter, or nil.
+func (g *Game) combatBoss() *entity.Enemy {
+	for _, e := range g.combatEnemies {
+		if e.IsBoss() {
+			return e
 		}
 	}
+	return nil
+}
+
+// encounterKind classifies the current encounter for telemetry ("boss" or "normal").
+func (g *Game) encounterKind() string {
+	if g.combatBoss() != nil {
+		return "boss"
+	}
+	return "normal"
 }
 
 // executeCombatTurn executes the current actor's turn with the selected ability.
 func (g *Game) executeCombatTurn(ctx context.Context, ability *gamedata.AbilityDef, user combat.Combatant, target combat.Combatant) {
-	if g.effectResolver == nil || ability == nil {
+	if ability == nil {
 		return
 	}
 
 	tracer := telemetry.Tracer("combat")
 	ctx, span := tracer.Start(ctx, "combat.turn")
 	span.SetAttributes(
+		g.combatState.encounterAttr(),
 		attribute.String("actor", user.GetName()),
 		attribute.String("ability", ability.ID),
 		attribute.String("target", target.GetName()),
@@ -142,7 +277,11 @@ func (g *Game) executeCombatTurn(ctx context.Context, ability *gamedata.AbilityD
 	defer span.End()
 
 	// Resolve the ability
+	wasAlive := target.IsAlive()
 	result := g.effectResolver.Resolve(ability, user, target)
+	g.noteAction(ability, user, target, result, wasAlive && !target.IsAlive())
+	g.playActionCues(result, wasAlive && !target.IsAlive())
+	g.recordThreat(user, target, result)
 
 	// Build message
 	if result.Success {
@@ -157,6 +296,8 @@ func (g *Game) executeCombatTurn(ctx context.Context, ability *gamedata.AbilityD
 		} else {
 			g.combatState.LastMessage = result.Message
 		}
+		g.combatState.LastMessage += g.noteUse(user, ability)
+		g.combatState.startCooldown(user, ability)
 		if result.StatusAdded != "" {
 			span.SetAttributes(attribute.String("status_applied", string(result.StatusAdded)))
 		}
@@ -164,38 +305,119 @@ func (g *Game) executeCombatTurn(ctx context.Context, ability *gamedata.AbilityD
 		g.combatState.LastMessage = result.Message
 		span.SetAttributes(attribute.Bool("failed", true))
 	}
+	g.resolveDeaths(ctx, user, ability)
 
 	g.combatState.TurnCount++
 }
 
-// advanceToNextPartyMember moves to the next alive party member, or to enemy phase.
-func (g *Game) advanceToNextPartyMember() {
-	// Find next alive member after current
-	for i := g.combatState.ActiveMemberIndex + 1; i < len(g.party.Members); i++ {
-		if g.party.Members[i].IsAlive() {
-			g.combatState.ActiveMemberIndex = i
-			return
+// executeGroupTurn resolves an all_enemies or all_allies ability against
+// every target, paying its cost once and combining the results into one message.
+func (g *Game) executeGroupTurn(ctx context.Context, ability *gamedata.AbilityDef, user combat.Combatant, targets []combat.Combatant) {
+	if ability == nil {
+		return
+	}
+
+	tracer := telemetry.Tracer("combat")
+	ctx, span := tracer.Start(ctx, "combat.turn")
+	span.SetAttributes(
+		g.combatState.encounterAttr(),
+		attribute.String("actor", user.GetName()),
+		attribute.String("ability", ability.ID),
+		attribute.String("target", string(ability.TargetType)),
+		attribute.Int("target_count", len(targets)),
+		attribute.Int("turn", g.combatState.TurnCount),
+	)
+	defer span.End()
+
+	wasAlive := make([]bool, len(targets))
+	for i, t := range targets {
+		wasAlive[i] = t.IsAlive()
+	}
+	results := g.effectResolver.ResolveAll(ability, user, targets)
+
+	// A single failed result means the ability couldn't be used at all
+	if len(results) != len(targets) {
+		g.combatState.LastMessage = results[0].Message
+		span.SetAttributes(attribute.Bool("failed", true))
+		g.combatState.TurnCount++
+		return
+	}
+
+	var parts []string
+	totalDamage, totalHealing := 0, 0
+	for i, result := range results {
+		target := targets[i]
+		g.noteAction(ability, user, target, result, wasAlive[i] && !target.IsAlive())
+		g.playActionCues(result, wasAlive[i] && !target.IsAlive())
+		g.recordThreat(user, target, result)
+		if result.Damage > 0 {
+			parts = append(parts, target.GetName()+" takes "+itoa(result.Damage)+" damage")
+			totalDamage += result.Damage
+		} else if result.Healing > 0 {
+			parts = append(parts, target.GetName()+" heals "+itoa(result.Healing)+" HP")
+			totalHealing += result.Healing
 		}
+		if result.StatusAdded != "" {
+			span.SetAttributes(attribute.String("status_applied", string(result.StatusAdded)))
+		}
+	}
+
+	message := user.GetName() + " uses " + ability.Name + "!"
+	if len(parts) > 0 {
+		message += " " + strings.Join(parts, ", ") + "!"
+	}
+	g.combatState.LastMessage = message + g.noteUse(user, ability)
+	g.combatState.startCooldown(user, ability)
+	if totalDamage > 0 {
+		span.SetAttributes(attribute.Int("damage", totalDamage))
+	}
+	if totalHealing > 0 {
+		span.SetAttributes(attribute.Int("healing", totalHealing))
+	}
+	g.resolveDeaths(ctx, user, ability)
+
+	g.combatState.TurnCount++
+}
+
+// advanceToNextPartyMember moves to the next party action this round, or
+// to the enemy phase.
+func (g *Game) advanceToNextPartyMember() {
+	g.combatState.AbilityPage = 0
+	if g.nextQueuedMember() {
+		return
 	}
 
-	// No more party members, switch to enemy turn
+	// No more party actions, switch to enemy turn
 	g.combatState.Phase = PhaseEnemyTurn
 	g.combatState.ActiveEnemyIndex = 0
 }
 
 // executeEnemyTurns executes all enemy turns in sequence.
 func (g *Game) executeEnemyTurns(ctx context.Context) {
-	for _, enemy := range g.combatState.Enemies {
-		if !enemy.IsAlive() {
+	g.combatState.EnemyActions = g.combatState.delayedEnemyActions()
+	var moves []*enemyMove
+	moving := make(map[*entity.Enemy]bool)
+	for _, slot := range roundOrder(g.combatState.Enemies, g.combatState.Round, g.combatState.slowAll()) {
+		i, enemy := slot.index, g.combatState.Enemies[slot.index]
+		if !enemy.IsAlive() || moving[enemy] {
+			// Killed earlier this round, or already closing in
 			continue
 		}
+		g.combatState.ActiveEnemyIndex = i
 
 		// Simple AI: pick a random ability and random alive party member
 		ability := g.selectEnemyAbility(enemy)
 		target := g.selectEnemyTarget(enemy, ability)
 
-		if ability != nil && target != nil {
+		if member, ok := target.(*entity.Member); ok && isMelee(ability) && !g.canMeleeReach(enemy, member) {
+			// Melee enemies close the distance before they can strike,
+			// moving together once everyone else has acted
+			moves = append(moves, &enemyMove{index: i, enemy: enemy, target: member})
+			moving[enemy] = true
+		} else if ability != nil && target != nil {
 			g.executeCombatTurn(ctx, ability, enemy, target)
+			g.combatState.EnemyActions = append(g.combatState.EnemyActions, g.combatState.LastMessage)
+			g.showEnemyAction()
 		}
 
 		// Check for party defeat after each enemy turn
@@ -206,26 +428,132 @@ func (g *Game) executeEnemyTurns(ctx context.Context) {
 		}
 	}
 
+	g.resolveEnemyMoves(ctx, moves)
+
+	// Status effects tick once per round
+	g.tickCombatStatuses(ctx)
+	if g.party.IsDefeated() {
+		g.combatState.Phase = PhaseDefeat
+		g.combatState.LastMessage = "Your party has been defeated!"
+		return
+	}
+
 	// All enemies done, check victory or start new round
-	if g.combatState.AliveEnemyCount() == 0 {
-		g.combatState.Phase = PhaseVictory
-		g.combatState.LastMessage = "Victory! All enemies defeated!"
+	if g.combatState.AliveEnemyCount() == 0 && len(g.combatState.pendingRises) == 0 {
+		g.declareVictory()
 	} else {
-		// Start new round with first alive party member
+		// Start a new round with the party's first action
+		g.raiseUndead(ctx)
+		g.advanceReinforcements(ctx)
+		g.makeNoise(g.party.X, g.party.Y, noiseCombatRound)
+		g.combatState.Round++
 		g.combatState.Phase = PhasePlayerTurn
-		for i, m := range g.party.Members {
-			if m.IsAlive() {
-				g.combatState.ActiveMemberIndex = i
-				break
+		if !g.startPartyRound() {
+			// Everyone left standing is too slow to act this round
+			g.combatState.LastMessage += " The party is too slow to act!"
+			g.combatState.Phase = PhaseEnemyTurn
+			g.executeEnemyTurns(ctx)
+		}
+	}
+}
+
+// showEnemyAction draws the enemy phase so far and holds it for the
+// configured delay, so each enemy action gets its own frame. The phase
+// resolves within one input event, so this is the only chance to draw it.
+func (g *Game) showEnemyAction() {
+	delay := g.cfg.EnemyActionDelay
+	if delay <= 0 {
+		return
+	}
+	g.render()
+	if g.sleep != nil {
+		g.sleep(delay)
+	} else {
+		time.Sleep(delay)
+	}
+}
+
+// tickCombatStatuses advances status effects on every living combatant at the
+// end of a round and appends notable ticks to the combat message.
+func (g *Game) tickCombatStatuses(ctx context.Context) {
+	var combatants []combat.Combatant
+	for _, m := range g.party.Members {
+		if m.IsAlive() {
+			combatants = append(combatants, m)
+		}
+	}
+	for _, e := range g.combatState.Enemies {
+		if e.IsAlive() {
+			combatants = append(combatants, e)
+		}
+	}
+
+	for _, c := range combatants {
+		if m, ok := c.(*entity.Member); ok && combat.HasStatus(m, gamedata.StatusSilence) {
+			g.stats.statsFor(m).TurnsSilenced++
+		}
+		for _, tick := range c.TickStatusEffects() {
+			g.noteTick(c, tick, !c.IsAlive())
+			switch {
+			case tick.Type == gamedata.StatusPoison && tick.Amount > 0:
+				g.combatState.LastMessage += " " + c.GetName() + " takes " + itoa(tick.Amount) + " poison damage."
+			case tick.Type == gamedata.StatusRegen && tick.Amount > 0:
+				g.combatState.LastMessage += " " + c.GetName() + " regenerates " + itoa(tick.Amount) + " HP."
+			case tick.Type == gamedata.StatusSilence && tick.Ended:
+				g.combatState.LastMessage += " " + c.GetName() + " can cast again."
+			case tick.Type == gamedata.StatusTaunt && tick.Ended:
+				g.combatState.LastMessage += " " + c.GetName() + " stops taunting."
 			}
 		}
 	}
+	g.resolveDeaths(ctx, nil, nil)
+}
+
+// abilitiesUnavailableMessage is shown in combat when ability data failed to load.
+const abilitiesUnavailableMessage = "Abilities unavailable — data failed to load."
+
+// abilitiesUnavailable reports whether ability data failed to load, leaving
+// the game with an empty registry.
+func (g *Game) abilitiesUnavailable() bool {
+	return g.abilityRegistry.Count() == 0
+}
+
+// combatAbilities returns the abilities the combatant can pick from in
+// combat, in menu order. Without ability data that is just the basic attack.
+func (g *Game) combatAbilities(c combat.Combatant) []*gamedata.AbilityDef {
+	if g.abilitiesUnavailable() {
+		return []*gamedata.AbilityDef{combat.BasicAttack}
+	}
+	return g.abilityRegistry.GetMultiple(c.GetAbilityIDs())
+}
+
+// flipAbilityPage puts the next page of the active member's abilities on
+// the number keys, wrapping back to the first.
+func (g *Game) flipAbilityPage() {
+	if g.combatState == nil || g.combatState.Phase != PhasePlayerTurn {
+		return
+	}
+	member := g.getActiveMember()
+	if member == nil {
+		return
+	}
+	pages := ui.AbilityPages(len(g.combatAbilities(member)))
+	g.combatState.AbilityPage = (g.combatState.AbilityPage + 1) % pages
+}
+
+// hotbarIndex returns the index into the active member's abilities of the
+// number key slot (0 for '1') on the current page.
+func (g *Game) hotbarIndex(slot int) int {
+	if g.combatState == nil {
+		return slot
+	}
+	return g.combatState.AbilityPage*ui.AbilitiesPerPage + slot
 }
 
 // selectEnemyAbility picks an ability for an enemy to use.
 func (g *Game) selectEnemyAbility(enemy *entity.Enemy) *gamedata.AbilityDef {
-	if g.abilityRegistry == nil {
-		return nil
+	if g.abilitiesUnavailable() {
+		return combat.BasicAttack
 	}
 
 	abilityIDs := enemy.GetAbilityIDs()
@@ -233,11 +561,27 @@ func (g *Game) selectEnemyAbility(enemy *entity.Enemy) *gamedata.AbilityDef {
 		return nil
 	}
 
+	// The shuffles below run over all the enemy's abilities, usable or not,
+	// so a fight draws the same dice whatever the enemy can afford
+	usable := g.usableBy(enemy)
+
+	// Back-row enemies, and those with nobody in melee range, prefer
+	// abilities that work from range
+	if enemy.InBackRow() || g.selectLowestHPMemberInReach(enemy) == nil {
+		offensive := g.abilityRegistry.Offensive()
+		for _, idx := range g.dice.Perm("enemy.ranged_ability", len(abilityIDs)) {
+			ability := g.abilityRegistry.GetByID(abilityIDs[idx])
+			if slices.Contains(usable, ability) && slices.Contains(offensive, ability) && ability.ReachesBackRow() && !g.debuffWasted(ability) {
+				return ability
+			}
+		}
+	}
+
 	// Simple AI: pick a random ability that the enemy can use
 	// Shuffle and find first usable
-	for _, idx := range g.rng.Perm(len(abilityIDs)) {
+	for _, idx := range g.dice.Perm("enemy.ability", len(abilityIDs)) {
 		ability := g.abilityRegistry.GetByID(abilityIDs[idx])
-		if ability != nil && enemy.GetMP() >= ability.MPCost {
+		if slices.Contains(usable, ability) && !g.debuffWasted(ability) {
 			return ability
 		}
 	}
@@ -257,22 +601,41 @@ func (g *Game) selectEnemyTarget(enemy *entity.Enemy, ability *gamedata.AbilityD
 		return enemy
 	case gamedata.TargetSingleEnemy, gamedata.TargetAllEnemies:
 		// For enemies, "enemy" means party members
-		// Pick random alive party member, preferring lowest HP
-		return g.selectLowestHPPartyMember()
+		// A taunting member draws every single-target attack
+		if ability.TargetType == gamedata.TargetSingleEnemy {
+			if m := g.taunter(); m != nil {
+				return m
+			}
+		}
+		// Status-only debuffs go to members that don't already have the status
+		if isStatusOnlyDebuff(ability) {
+			if m := g.selectLowestHPPartyMemberWithout(ability.StatusEffect); m != nil {
+				return m
+			}
+		}
+		// Melee attacks prefer whoever is already in reach
+		if isMelee(ability) {
+			if m := g.selectThreateningMember(enemy, true); m != nil {
+				return m
+			}
+		}
+		// Focus whoever has built the most threat, then the lowest HP
+		return g.selectThreateningMember(enemy, false)
 	case gamedata.TargetSingleAlly, gamedata.TargetAllAllies:
 		// For enemies, "ally" means other enemies
 		// Pick lowest HP ally (for healing)
 		return g.selectLowestHPEnemy()
 	default:
-		return g.selectLowestHPPartyMember()
+		return g.selectThreateningMember(enemy, false)
 	}
 }
 
-// selectLowestHPPartyMember returns the alive party member with lowest HP.
-func (g *Game) selectLowestHPPartyMember() *entity.Member {
+// selectLowestHPPartyMemberWithout returns the alive party member with lowest
+// HP that doesn't have the given status, or nil if all of them do.
+func (g *Game) selectLowestHPPartyMemberWithout(status gamedata.StatusEffectType) *entity.Member {
 	var lowest *entity.Member
 	for _, m := range g.party.Members {
-		if m.IsAlive() {
+		if m.IsAlive() && !combat.HasStatus(m, status) {
 			if lowest == nil || m.GetHP() < lowest.GetHP() {
 				lowest = m
 			}
@@ -281,6 +644,17 @@ func (g *Game) selectLowestHPPartyMember() *entity.Member {
 	return lowest
 }
 
+// isStatusOnlyDebuff returns true for debuffs whose only effect is a status.
+func isStatusOnlyDebuff(ability *gamedata.AbilityDef) bool {
+	return ability.EffectType == gamedata.EffectDebuff && ability.StatusEffect != gamedata.StatusNone
+}
+
+// debuffWasted returns true if a status-only debuff has no party member left
+// to afflict (e.g. everyone is already silenced).
+func (g *Game) debuffWasted(ability *gamedata.AbilityDef) bool {
+	return isStatusOnlyDebuff(ability) && g.selectLowestHPPartyMemberWithout(ability.StatusEffect) == nil
+}
+
 // selectLowestHPEnemy returns the alive enemy with lowest HP.
 func (g *Game) selectLowestHPEnemy() *entity.Enemy {
 	var lowest *entity.Enemy
@@ -302,23 +676,64 @@ func (g *Game) checkCombatEnd() bool {
 		return true
 	}
 	if g.combatState.AliveEnemyCount() == 0 {
-		g.combatState.Phase = PhaseVictory
-		g.combatState.LastMessage = "Victory! All enemies defeated!"
+		if len(g.combatState.pendingRises) > 0 {
+			// Nothing left to fight this round, but the fallen will stir
+			g.combatState.Phase = PhaseEnemyTurn
+			return false
+		}
+		g.declareVictory()
 		return true
 	}
 	return false
 }
 
+// declareVictory ends the fight in the party's favour and calls out the MVP.
+func (g *Game) declareVictory() {
+	g.combatState.Phase = PhaseVictory
+	g.combatState.LastMessage = "Victory! All enemies defeated! Run " + g.runStatus().String() + "."
+	g.playCue(audio.CueVictory)
+	if notes := strings.TrimSpace(g.combatState.deathNotes); notes != "" {
+		g.combatState.LastMessage = notes + " " + g.combatState.LastMessage
+	}
+	if line := g.stats.mvpLine(g.party); line != "" {
+		g.combatState.LastMessage += " " + line
+	}
+}
+
 // endCombat handles combat ending (victory or defeat).
 func (g *Game) endCombat(ctx context.Context, outcome string) {
+	if outcome == "victory" {
+		g.tuneDifficulty()
+	}
+
 	tracer := telemetry.Tracer("combat")
 	_, span := tracer.Start(ctx, "combat.end")
 	span.SetAttributes(
+		g.combatState.encounterAttr(),
 		attribute.String("outcome", outcome),
 		attribute.Int("turns_taken", g.combatState.TurnCount),
 		attribute.Int("party_hp_remaining", g.totalPartyHP()),
 	)
+	if mvp, _ := g.stats.mvp(g.party); mvp != nil {
+		span.SetAttributes(attribute.String("mvp", mvp.GetName()))
+	}
+	if g.adaptive {
+		span.SetAttributes(attribute.Int("difficulty_shift", g.difficultyShift))
+	}
+	danger := g.realizedDanger()
+	span.SetAttributes(
+		attribute.Float64("difficulty.ratio", danger.Predicted.Ratio),
+		attribute.Float64("danger.hp_lost_fraction", danger.HPLostFraction),
+		attribute.Int("danger.members_downed", danger.MembersDowned),
+	)
+	g.recordRolls(span)
 	span.End()
+	if summary := g.stats.summaryLine(g.combatState.Round); summary != "" {
+		g.message = summary + fullLogMarker
+	}
+	g.stats.recordEncounter(danger)
+	g.stats.finishCombat()
+	g.lastFight, g.recording = g.recording, nil
 
 	// Remove dead enemies from the dungeon
 	if outcome == "victory" {
@@ -326,6 +741,20 @@ func (g *Game) endCombat(ctx context.Context, outcome string) {
 	}
 }
 
+// recordRolls adds one span event per audited roll of the fight.
+func (g *Game) recordRolls(span trace.Span) {
+	if !g.dice.Auditing() {
+		return
+	}
+	for _, r := range g.dice.Rolls() {
+		span.AddEvent("combat.roll", trace.WithAttributes(
+			attribute.String("purpose", r.Purpose),
+			attribute.Int("bound", r.Bound),
+			attribute.Int("result", r.Result),
+		))
+	}
+}
+
 // totalPartyHP returns the sum of all party members' current HP.
 func (g *Game) totalPartyHP() int {
 	total := 0
@@ -341,6 +770,8 @@ func (g *Game) removeDeadEnemies() {
 	for _, e := range g.enemies {
 		if e.IsAlive() {
 			alive = append(alive, e)
+		} else {
+			g.leaveCorpse(e)
 		}
 	}
 	g.enemies = alive
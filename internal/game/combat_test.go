@@ -1,10 +1,19 @@
 package game
 
 import (
+	"context"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/gdamore/tcell/v2"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/samdwyer/dungeonband/internal/combat"
 	"github.com/samdwyer/dungeonband/internal/entity"
 	"github.com/samdwyer/dungeonband/internal/gamedata"
+	"github.com/samdwyer/dungeonband/internal/ui"
+	"github.com/samdwyer/dungeonband/internal/world"
 )
 
 func TestCombatPhaseString(t *testing.T) {
@@ -16,6 +25,7 @@ func TestCombatPhaseString(t *testing.T) {
 		{PhaseEnemyTurn, "enemy_turn"},
 		{PhaseVictory, "victory"},
 		{PhaseDefeat, "defeat"},
+		{PhaseSelectTarget, "select_target"},
 		{CombatPhase(99), "unknown"},
 	}
 
@@ -181,3 +191,530 @@ func TestItoa(t *testing.T) {
 		}
 	}
 }
+
+func TestAbilitySelectionWithNoAliveEnemies(t *testing.T) {
+	g := newTestGame(t)
+
+	goblin := entity.NewEnemy(entity.EnemyGoblin, 5, 5, 1)
+	goblin.TakeDamage(1000)
+	g.state = StateCombat
+	g.combatState = NewCombatState([]*entity.Enemy{goblin})
+
+	// Warrior's first ability is "attack" (single_enemy)
+	g.handleCombatAbilitySelection(context.Background(), 0)
+
+	if g.combatState.LastMessage != "No valid target!" {
+		t.Errorf("LastMessage = %q, want %q", g.combatState.LastMessage, "No valid target!")
+	}
+	if g.combatState.Phase != PhasePlayerTurn {
+		t.Errorf("Phase = %v, want PhasePlayerTurn", g.combatState.Phase)
+	}
+	if g.combatState.ActiveMemberIndex != 0 {
+		t.Errorf("ActiveMemberIndex = %d, want 0 (turn not consumed)", g.combatState.ActiveMemberIndex)
+	}
+	if g.combatState.TurnCount != 0 {
+		t.Errorf("TurnCount = %d, want 0", g.combatState.TurnCount)
+	}
+}
+
+func TestGroupHealWithFullHPParty(t *testing.T) {
+	g := newTestGame(t)
+
+	g.state = StateCombat
+	g.combatState = NewCombatState([]*entity.Enemy{entity.NewEnemy(entity.EnemyGoblin, 5, 5, 1)})
+	g.combatState.ActiveMemberIndex = 3 // Cleric
+	cleric := g.party.Members[3]
+	mpBefore := cleric.GetMP()
+
+	// Cleric abilities: attack, defend, heal, group_heal
+	g.handleCombatAbilitySelection(context.Background(), 3)
+
+	if g.combatState.LastMessage != "No valid target!" {
+		t.Errorf("LastMessage = %q, want %q", g.combatState.LastMessage, "No valid target!")
+	}
+	if cleric.GetMP() != mpBefore {
+		t.Errorf("MP spent on invalid heal: %d -> %d", mpBefore, cleric.GetMP())
+	}
+	if g.combatState.ActiveMemberIndex != 3 {
+		t.Errorf("ActiveMemberIndex = %d, want 3 (turn not consumed)", g.combatState.ActiveMemberIndex)
+	}
+}
+
+func TestBossEncounterTelemetry(t *testing.T) {
+	recorder := recordSpans(t)
+	g := newTestGame(t)
+
+	bossDef := &gamedata.EnemyDef{
+		ID:        "orc_warlord",
+		Name:      "Orc Warlord",
+		HP:        60,
+		Attack:    8,
+		Defense:   4,
+		Abilities: []string{"attack"},
+		Boss:      true,
+	}
+	boss := entity.NewEnemyFromDef(bossDef, g.party.X+1, g.party.Y, 0)
+	g.enemies = []*entity.Enemy{boss}
+
+	g.transitionState(context.Background(), StateCombat, "test")
+
+	span := findSpan(recorder, "combat.start")
+	if span == nil {
+		t.Fatal("combat.start span not recorded")
+	}
+	if got := spanAttr(span, "encounter.kind").AsString(); got != "boss" {
+		t.Errorf("encounter.kind = %q, want %q", got, "boss")
+	}
+	if got := spanAttr(span, "encounter.boss_id").AsString(); got != "orc_warlord" {
+		t.Errorf("encounter.boss_id = %q, want %q", got, "orc_warlord")
+	}
+}
+
+func TestNormalEncounterTelemetry(t *testing.T) {
+	recorder := recordSpans(t)
+	g := newTestGame(t)
+	g.enemies = []*entity.Enemy{entity.NewEnemy(entity.EnemyGoblin, g.party.X+1, g.party.Y, 0)}
+
+	g.transitionState(context.Background(), StateCombat, "test")
+
+	span := findSpan(recorder, "combat.start")
+	if span == nil {
+		t.Fatal("combat.start span not recorded")
+	}
+	if got := spanAttr(span, "encounter.kind").AsString(); got != "normal" {
+		t.Errorf("encounter.kind = %q, want %q", got, "normal")
+	}
+	if spanAttr(span, "encounter.boss_id").Type() != attribute.INVALID {
+		t.Error("encounter.boss_id should not be set for normal encounters")
+	}
+}
+
+func TestSilencedMemberCannotCastSpells(t *testing.T) {
+	g := newTestGame(t)
+	g.state = StateCombat
+	g.combatState = NewCombatState([]*entity.Enemy{entity.NewEnemy(entity.EnemyGoblin, 5, 5, 1)})
+	g.combatState.ActiveMemberIndex = 2 // Wizard
+	wizard := g.party.Members[2]
+	wizard.AddStatusEffect(combat.StatusEffect{Type: gamedata.StatusSilence, RemainingTurns: 2})
+	mp := wizard.GetMP()
+
+	// Wizard abilities: attack, defend, fireball
+	info := g.buildCombatInfo()
+	if fb := info.Abilities[2]; fb.CanUse || fb.Reason != "Silenced!" {
+		t.Errorf("fireball info = %+v, want greyed out as Silenced!", fb)
+	}
+
+	g.handleCombatAbilitySelection(context.Background(), 2)
+
+	if g.combatState.ActiveMemberIndex != 2 || g.combatState.Phase != PhasePlayerTurn {
+		t.Error("silenced cast should not spend the wizard's turn")
+	}
+	if wizard.GetMP() != mp {
+		t.Errorf("wizard MP = %d, want %d", wizard.GetMP(), mp)
+	}
+	if g.combatState.LastMessage != wizard.GetName()+" is silenced!" {
+		t.Errorf("LastMessage = %q", g.combatState.LastMessage)
+	}
+
+	// Attack costs no MP and is still allowed
+	if !info.Abilities[0].CanUse {
+		t.Error("attack should remain usable while silenced")
+	}
+}
+
+func TestSilenceWearsOffAtRoundEnd(t *testing.T) {
+	g := newTestGame(t)
+	g.state = StateCombat
+	g.combatState = NewCombatState([]*entity.Enemy{entity.NewEnemy(entity.EnemyGoblin, 5, 5, 1)})
+	wizard := g.party.Members[2]
+	wizard.AddStatusEffect(combat.StatusEffect{Type: gamedata.StatusSilence, RemainingTurns: 1})
+
+	g.tickCombatStatuses(context.Background())
+
+	if combat.HasStatus(wizard, gamedata.StatusSilence) {
+		t.Error("silence should expire after its last round")
+	}
+}
+
+func TestHexSkippedWhenPartyAllSilenced(t *testing.T) {
+	g := newTestGame(t)
+	hex := g.abilityRegistry.GetByID("hex")
+	if g.debuffWasted(hex) {
+		t.Fatal("hex should be useful against an unsilenced party")
+	}
+	for _, m := range g.party.Members {
+		m.AddStatusEffect(combat.StatusEffect{Type: gamedata.StatusSilence, RemainingTurns: 3})
+	}
+	if !g.debuffWasted(hex) {
+		t.Error("hex should be skipped when every member is silenced")
+	}
+}
+
+func TestMemberCannotUseAbilityOutsideTheirList(t *testing.T) {
+	g := newTestGame(t)
+	goblin := entity.NewEnemy(entity.EnemyGoblin, 5, 5, 1)
+	g.state = StateCombat
+	g.combatState = NewCombatState([]*entity.Enemy{goblin})
+	warrior := g.party.Members[0]
+
+	// A mis-indexed selection handing the warrior the wizard's fireball
+	g.executeCombatTurn(context.Background(), g.abilityRegistry.GetByID("fireball"), warrior, goblin)
+
+	if goblin.GetHP() != goblin.GetMaxHP() {
+		t.Errorf("goblin HP = %d, want untouched", goblin.GetHP())
+	}
+	if want := warrior.GetName() + " doesn't know Fireball!"; g.combatState.LastMessage != want {
+		t.Errorf("LastMessage = %q, want %q", g.combatState.LastMessage, want)
+	}
+}
+
+func TestAbilitySelectionDispatchesOnTargetType(t *testing.T) {
+	abilities := []gamedata.AbilityDef{
+		{ID: "brace", Name: "Brace", EffectType: gamedata.EffectBuff, TargetType: gamedata.TargetSelf,
+			StatusEffect: gamedata.StatusDefenseUp, StatusDuration: 2},
+		{ID: "strike", Name: "Strike", EffectType: gamedata.EffectDamage, TargetType: gamedata.TargetSingleEnemy,
+			DamageType: gamedata.DamagePhysical, BasePower: 5},
+		{ID: "mend", Name: "Mend", EffectType: gamedata.EffectHeal, TargetType: gamedata.TargetSingleAlly, BasePower: 5},
+		{ID: "quake", Name: "Quake", EffectType: gamedata.EffectDamage, TargetType: gamedata.TargetAllEnemies,
+			DamageType: gamedata.DamageTrue, BasePower: 3, MPCost: 4},
+		{ID: "rally", Name: "Rally", EffectType: gamedata.EffectHeal, TargetType: gamedata.TargetAllAllies, BasePower: 2, MPCost: 4},
+		{ID: "confuse", Name: "Confuse", EffectType: gamedata.EffectDebuff, TargetType: "everyone",
+			StatusEffect: gamedata.StatusAttackDown, StatusDuration: 2},
+	}
+
+	tests := []struct {
+		abilityID  string
+		wantPhase  CombatPhase
+		turnSpent  bool
+		wantMPCost int
+		check      func(t *testing.T, g *Game, goblins []*entity.Enemy)
+	}{
+		{
+			abilityID: "brace", wantPhase: PhasePlayerTurn, turnSpent: true,
+			check: func(t *testing.T, g *Game, _ []*entity.Enemy) {
+				if !combat.HasStatus(g.party.Members[0], gamedata.StatusDefenseUp) {
+					t.Error("self ability should apply to the caster")
+				}
+			},
+		},
+		{abilityID: "strike", wantPhase: PhaseSelectTarget},
+		{abilityID: "mend", wantPhase: PhaseSelectTarget},
+		{
+			abilityID: "quake", wantPhase: PhasePlayerTurn, turnSpent: true, wantMPCost: 4,
+			check: func(t *testing.T, _ *Game, goblins []*entity.Enemy) {
+				for i, gob := range goblins {
+					if gob.GetHP() != gob.GetMaxHP()-3 {
+						t.Errorf("goblin %d HP = %d, want %d", i, gob.GetHP(), gob.GetMaxHP()-3)
+					}
+				}
+			},
+		},
+		{
+			abilityID: "rally", wantPhase: PhasePlayerTurn, turnSpent: true, wantMPCost: 4,
+			check: func(t *testing.T, g *Game, _ []*entity.Enemy) {
+				for _, m := range g.party.Members {
+					if m.GetHP() == m.GetMaxHP()-5 {
+						t.Errorf("%s was not healed by an all_allies heal", m.GetName())
+					}
+				}
+			},
+		},
+		{
+			abilityID: "confuse", wantPhase: PhasePlayerTurn,
+			check: func(t *testing.T, g *Game, _ []*entity.Enemy) {
+				want := `Can't use Confuse: unknown targetType "everyone"`
+				if g.combatState.LastMessage != want {
+					t.Errorf("LastMessage = %q, want %q", g.combatState.LastMessage, want)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.abilityID, func(t *testing.T) {
+			g := newTestGame(t)
+			g.abilityRegistry = gamedata.NewAbilityRegistry(abilities)
+			g.effectResolver = combat.NewEffectResolver(g.abilityRegistry)
+
+			goblins := []*entity.Enemy{
+				entity.NewEnemy(entity.EnemyGoblin, 5, 5, 1),
+				entity.NewEnemy(entity.EnemyGoblin, 6, 5, 1),
+			}
+			g.state = StateCombat
+			g.combatState = NewCombatState(goblins)
+
+			caster := g.party.Members[0]
+			caster.MP, caster.MaxMP = 10, 10
+			caster.AbilityIDs = []string{tt.abilityID}
+			for _, m := range g.party.Members {
+				m.TakeDamage(5)
+			}
+
+			g.handleCombatAbilitySelection(context.Background(), 0)
+
+			if g.combatState.Phase != tt.wantPhase {
+				t.Errorf("Phase = %v, want %v", g.combatState.Phase, tt.wantPhase)
+			}
+			if spent := g.combatState.ActiveMemberIndex != 0; spent != tt.turnSpent {
+				t.Errorf("turn spent = %v, want %v", spent, tt.turnSpent)
+			}
+			if cost := 10 - caster.GetMP(); cost != tt.wantMPCost {
+				t.Errorf("MP spent = %d, want %d", cost, tt.wantMPCost)
+			}
+			if tt.check != nil {
+				tt.check(t, g, goblins)
+			}
+		})
+	}
+}
+
+func TestAuditedFightRecordsRolls(t *testing.T) {
+	recorder := recordSpans(t)
+	orc := entity.NewEnemyFromDef(gamedata.MustLoadEnemyRegistry().GetByID("orc"), 6, 2, 0)
+	g := startCombatOnMap(t, `
+############
+#..........#
+#..........#
+#..........#
+############`, 5, 2, orc)
+	g.dice = combat.NewDice(g.rng, true)
+
+	// One enemy round: the orc shuffles its three abilities once
+	g.executeEnemyTurns(context.Background())
+	g.endCombat(context.Background(), "abandoned")
+
+	span := findSpan(recorder, "combat.end")
+	if span == nil {
+		t.Fatal("combat.end span not recorded")
+	}
+	events := span.Events()
+	if len(events) != 3 {
+		t.Fatalf("got %d roll events, want 3", len(events))
+	}
+	for i, ev := range events {
+		attrs := make(map[string]attribute.Value)
+		for _, kv := range ev.Attributes {
+			attrs[string(kv.Key)] = kv.Value
+		}
+		bound := attrs["bound"].AsInt64()
+		if ev.Name != "combat.roll" || attrs["purpose"].AsString() != "enemy.ability" || bound != int64(i+1) {
+			t.Errorf("roll %d = %s %v, want combat.roll enemy.ability bound %d", i, ev.Name, attrs, i+1)
+		}
+		if r := attrs["result"].AsInt64(); r < 0 || r >= bound {
+			t.Errorf("roll %d result %d out of [0, %d)", i, r, bound)
+		}
+	}
+}
+
+func TestEnemyPhaseCombatInfo(t *testing.T) {
+	g, goblin := startOpenRoomCombat(t, 6, 2)
+	goblin.HP = 1000 // Survive the round
+
+	g.executeEnemyTurns(context.Background())
+	if len(g.combatState.EnemyActions) != 1 || !strings.Contains(g.combatState.EnemyActions[0], goblin.Name) {
+		t.Fatalf("EnemyActions = %q, want the goblin's action", g.combatState.EnemyActions)
+	}
+
+	g.combatState.Phase = PhaseEnemyTurn
+	info := g.buildCombatInfo()
+	if info.Phase != ui.PhaseEnemyTurn || info.ActingEnemy != goblin {
+		t.Errorf("info phase = %v acting = %v, want the enemy phase with the goblin acting", info.Phase, info.ActingEnemy)
+	}
+	if len(info.Abilities) != 0 {
+		t.Error("enemy phase info should carry no abilities")
+	}
+}
+
+// enemyPhaseFrames records the enemy-phase panel of each combat frame.
+type enemyPhaseFrames struct {
+	NullDisplay
+	frames []ui.CombatInfo
+}
+
+func (d *enemyPhaseFrames) RenderCombat(_ *world.Dungeon, _ *entity.Party, _ []*entity.Enemy, _ int64, info *ui.CombatInfo) {
+	if info != nil && info.Phase == ui.PhaseEnemyTurn {
+		d.frames = append(d.frames, *info)
+	}
+}
+
+func TestEnemyPhaseDrawsEachAction(t *testing.T) {
+	g, goblin := startOpenRoomCombat(t, 6, 2)
+	second := entity.NewEnemyFromDef(goblin.Def, 6, 3, 0)
+	for _, e := range []*entity.Enemy{goblin, second} {
+		e.HP = 1000
+	}
+	g.combatEnemies = append(g.combatEnemies, second)
+	g.combatState.Enemies = g.combatEnemies
+
+	display := &enemyPhaseFrames{}
+	g.display = display
+	g.cfg.EnemyActionDelay = time.Second
+	var waited time.Duration
+	g.sleep = func(d time.Duration) { waited += d }
+
+	g.combatState.Phase = PhaseEnemyTurn
+	g.executeEnemyTurns(context.Background())
+
+	if len(display.frames) != 2 || waited != 2*time.Second {
+		t.Fatalf("drew %d enemy-phase frames over %v, want one per action held 1s each", len(display.frames), waited)
+	}
+	for i, frame := range display.frames {
+		if len(frame.EnemyActions) != i+1 {
+			t.Errorf("frame %d shows %d actions, want %d", i, len(frame.EnemyActions), i+1)
+		}
+		if frame.ActingEnemy == nil {
+			t.Errorf("frame %d has no acting enemy", i)
+		}
+	}
+}
+
+// loseAbilityData leaves the game as New does when abilities.json fails to
+// load: an empty registry rather than a nil one.
+func loseAbilityData(g *Game) {
+	g.abilityRegistry = gamedata.NewAbilityRegistry(nil)
+	g.effectResolver = combat.NewEffectResolver(g.abilityRegistry)
+}
+
+func TestCombatWithoutAbilityDataFallsBackToAttack(t *testing.T) {
+	g, goblin := startOpenRoomCombat(t, 6, 2)
+	loseAbilityData(g)
+	goblin.HP = 1000
+	ctx := context.Background()
+
+	info := g.buildCombatInfo()
+	if len(info.Abilities) != 1 || info.Abilities[0].Name != "Attack" || !info.Abilities[0].CanUse {
+		t.Fatalf("Abilities = %+v, want a usable Attack", info.Abilities)
+	}
+	if !strings.Contains(info.Message, abilitiesUnavailableMessage) {
+		t.Errorf("Message = %q, want it to say abilities are unavailable", info.Message)
+	}
+
+	g.handleCombatAbilitySelection(ctx, 0)
+	if g.combatState.Phase != PhaseSelectTarget {
+		t.Fatalf("Phase = %v, want target selection", g.combatState.Phase)
+	}
+	g.confirmTarget(ctx)
+	if goblin.HP >= 1000 {
+		t.Error("fallback attack should damage the goblin")
+	}
+
+	if a := g.selectEnemyAbility(goblin); a != combat.BasicAttack {
+		t.Errorf("enemy ability = %v, want the basic attack", a)
+	}
+}
+
+func TestGameWithoutAbilityDataEntersAndLeavesCombat(t *testing.T) {
+	g, sim := newTestGameWithScreen(t)
+	loseAbilityData(g)
+	g.dungeon = dungeonFromMap(`
+############
+#..........#
+#..........#
+#..........#
+############`)
+	g.party.SetPosition(2, 2)
+	goblin := entity.NewEnemy(entity.EnemyGoblin, 8, 2, 0)
+	goblin.HP, goblin.MaxHP = 1000, 1000
+	g.enemies = []*entity.Enemy{goblin}
+	ctx := context.Background()
+	enter := tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)
+
+	press(g, 'm') // Character sheet names abilities by ID
+	g.render()
+	g.handleKeyEvent(ctx, enter)
+	press(g, 'z') // Nothing to cast
+	g.render()
+
+	press(g, 'c')
+	if g.state != StateCombat {
+		t.Fatalf("state = %v, want combat", g.state)
+	}
+	for range 3 {
+		press(g, '1')
+		g.handleKeyEvent(ctx, enter)
+		g.render()
+	}
+	if !strings.Contains(screenText(sim), abilitiesUnavailableMessage) {
+		t.Errorf("combat screen doesn't say abilities are unavailable:\n%s", screenText(sim))
+	}
+
+	g.handleKeyEvent(ctx, tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone))
+	if g.state != StateExplore {
+		t.Errorf("state = %v after fleeing, want explore", g.state)
+	}
+	g.render()
+}
+
+func TestEncounterSpansShareAnEncounterID(t *testing.T) {
+	recorder := recordSpans(t)
+	g := newTestGame(t)
+	ctx := context.Background()
+	attack := g.abilityRegistry.GetByID("attack")
+
+	for range 2 {
+		goblin := entity.NewEnemy(entity.EnemyGoblin, g.party.X+1, g.party.Y, 0)
+		g.enemies = []*entity.Enemy{goblin}
+		g.transitionState(ctx, StateCombat, "test")
+		g.executeCombatTurn(ctx, attack, g.party.Members[0], goblin)
+		g.endCombat(ctx, "fled")
+		g.transitionState(ctx, StateExplore, "test")
+	}
+
+	// Each encounter's spans, by encounter ID
+	spans := make(map[string][]string)
+	var order []string
+	for _, span := range recorder.Ended() {
+		switch span.Name() {
+		case "combat.start", "combat.turn", "combat.end":
+		default:
+			continue
+		}
+		id := spanAttr(span, "encounter.id").AsString()
+		if id == "" {
+			t.Errorf("%s has no encounter.id", span.Name())
+			continue
+		}
+		if _, ok := spans[id]; !ok {
+			order = append(order, id)
+		}
+		spans[id] = append(spans[id], span.Name())
+	}
+
+	if len(order) != 2 {
+		t.Fatalf("encounter IDs = %v, want 2 distinct", order)
+	}
+	for _, id := range order {
+		if got := strings.Join(spans[id], " "); got != "combat.start combat.turn combat.end" {
+			t.Errorf("encounter %s spans = %q, want start, turn and end", id, got)
+		}
+	}
+}
+
+func TestHotbarPagingSelectsTenthAbility(t *testing.T) {
+	g := newTestGame(t)
+	g.state = StateCombat
+	g.combatState = NewCombatState([]*entity.Enemy{entity.NewEnemy(entity.EnemyGoblin, 5, 5, 1)})
+	warrior := g.party.Members[0]
+	warrior.AbilityIDs = []string{
+		"attack", "fireball", "heal", "poison_strike", "power_attack", "group_heal",
+		"bite", "claw", "bone_throw", "defend", "hex", "cleanse",
+	}
+
+	press(g, '0')
+	if info := g.buildCombatInfo(); info.AbilityPage != 1 || len(info.Abilities) != 12 {
+		t.Fatalf("after '0': page %d of %d abilities, want page 1 of 12", info.AbilityPage, len(info.Abilities))
+	}
+	press(g, '1') // Defend, the 10th ability
+
+	if !combat.HasStatus(warrior, gamedata.StatusDefenseUp) {
+		t.Error("the first key on page 2 did not use Defend")
+	}
+	if g.combatState.ActiveMemberIndex != 1 || g.combatState.AbilityPage != 0 {
+		t.Errorf("next member %d on page %d, want member 1 on page 0", g.combatState.ActiveMemberIndex, g.combatState.AbilityPage)
+	}
+
+	g.handleKeyEvent(context.Background(), tcell.NewEventKey(tcell.KeyTab, 0, tcell.ModNone))
+	if g.combatState.AbilityPage != 0 {
+		t.Errorf("Tab with a single page of abilities moved to page %d", g.combatState.AbilityPage)
+	}
+}
@@ -1,8 +1,152 @@
 package game
 
+import (
+	"fmt"
+	"time"
+
+	"github.com/samdwyer/dungeonband/internal/combat"
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+	"github.com/samdwyer/dungeonband/internal/world"
+)
+
+// StartMode chooses which room the party starts each floor in.
+type StartMode int
+
+const (
+	// StartFirstRoom starts in the first room generated
+	StartFirstRoom StartMode = iota
+	// StartRandomRoom starts in any room but the one with the stairs
+	StartRandomRoom
+	// StartFarthestFromExit starts in the room farthest from the stairs
+	StartFarthestFromExit
+	// StartRoomIndex starts in the room numbered Config.StartRoom
+	StartRoomIndex
+)
+
 // Config holds game configuration options.
 type Config struct {
 	// Seed for random number generation. Used for reproducible dungeon generation.
 	// A seed of 0 means a random seed will be generated.
 	Seed int64
+
+	// SkipDescendConfirm descends immediately when stepping on stairs instead
+	// of asking "Descend? (y/n)" first.
+	SkipDescendConfirm bool
+
+	// AuditRolls records every combat roll with its purpose and attaches
+	// them to the combat.end span, for balancing.
+	AuditRolls bool
+
+	// AdaptiveDifficulty shrinks the spawn budget of upcoming rooms after a
+	// fight that leaves the party badly hurt, and grows it after an easy one.
+	// Off by default so a seed always produces the same floors.
+	AdaptiveDifficulty bool
+
+	// Tutorial plays the hand-authored tutorial floor instead of a generated
+	// dungeon. Reaching its stairs completes the tutorial and ends the run.
+	Tutorial bool
+
+	// Start picks the room the party starts each floor in. Enemies never
+	// spawn in the starting room.
+	Start StartMode
+
+	// FocusKills starts offensive target selection on the enemy the ability
+	// can kill outright, instead of the first reachable one.
+	FocusKills bool
+
+	// Difficulty names the difficulty.json entry that sets the party's
+	// starting supplies and statuses. "" means "normal".
+	Difficulty string
+
+	// DamageFormula overrides the difficulty's damage formula, e.g.
+	// "multiplicative". "" uses the difficulty's, which defaults to additive.
+	DamageFormula gamedata.DamageFormula
+
+	// HideFlavor keeps ambient messages and room flavor out of the log.
+	// They are still rolled, so hiding them doesn't change the run.
+	HideFlavor bool
+
+	// ShowInitials draws party members in the combat formation by the
+	// first letter of their name instead of their class symbol.
+	ShowInitials bool
+
+	// Demo lets the party play itself: it explores, fights and descends
+	// until it is wiped out or gets deep enough. Keys other than quit are
+	// ignored.
+	Demo bool
+
+	// Debug adds a seeds panel to the pause menu, listing the sub-seeds the
+	// run has drawn from its master seed and exporting them for checking.
+	// It also starts with the debug overlay (F3) on.
+	Debug bool
+
+	// PlanActions has the party choose every member's action for the round
+	// before any of them resolve. The actions then resolve in order, and
+	// the enemies act after them as usual.
+	PlanActions bool
+
+	// EnemyVisibility chooses which enemies the party sees: by sight (the
+	// default), only in its own room or corridor, or all of them. "all" is
+	// a debugging view; the party still only notices enemies in sight.
+	EnemyVisibility world.Visibility
+
+	// Hardcore turns off the mistake-recovery aids: rewinding a combat turn
+	// with 'u' and retrying a lost encounter. Without it each fight allows
+	// a few rewinds.
+	Hardcore bool
+
+	// DataDir is a directory of JSON overrides merged over the embedded
+	// abilities, enemies and classes, laid out as for "dungeonband data".
+	// In debug mode Ctrl+R reloads it mid-run. "" uses the embedded data.
+	DataDir string
+
+	// StartRoom is the room index used with StartRoomIndex. Floors with
+	// fewer rooms fall back to the first room.
+	StartRoom int
+
+	// PartyClasses sets the class of each party member, in order. Members
+	// past the end keep their default class; nil is the default lineup.
+	PartyClasses []entity.Class
+
+	// AutosavePath is where the run is saved each time the party descends
+	// or wins a fight. "" turns autosave off.
+	AutosavePath string
+
+	// Resume continues a saved run instead of starting a new one. Seed and
+	// Difficulty should match the save's. Retrying a resumed run starts
+	// again from the save.
+	Resume *Save
+
+	// Audio plays sound cues for combat events. nil is silent, and runs
+	// on a NullDisplay are always silent.
+	Audio AudioSink
+
+	// EnemyActionDelay is how long each enemy action stays on screen before
+	// the next one resolves, so the enemy-phase panel shows them one at a
+	// time. 0 resolves the whole enemy phase before the next frame.
+	EnemyActionDelay time.Duration
+}
+
+// DefaultEnemyActionDelay is the EnemyActionDelay for interactive play.
+const DefaultEnemyActionDelay = 400 * time.Millisecond
+
+// validate reports settings that can never work.
+func (c Config) validate() error {
+	if c.Start < StartFirstRoom || c.Start > StartRoomIndex {
+		return fmt.Errorf("unknown start mode %d", c.Start)
+	}
+	if c.Start == StartRoomIndex && c.StartRoom < 0 {
+		return fmt.Errorf("start room index %d is negative", c.StartRoom)
+	}
+	if _, err := combat.FormulaFor(c.DamageFormula); err != nil {
+		return err
+	}
+	if _, err := world.ParseVisibility(string(c.EnemyVisibility)); err != nil {
+		return err
+	}
+	if len(c.PartyClasses) > entity.PartySize {
+		return fmt.Errorf("%d party classes for a party of %d", len(c.PartyClasses), entity.PartySize)
+	}
+	return nil
 }
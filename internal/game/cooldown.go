@@ -0,0 +1,54 @@
+package game
+
+import (
+	"maps"
+
+	"github.com/samdwyer/dungeonband/internal/combat"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+)
+
+// startCooldown keeps the user from using the ability again until its
+// cooldown has passed: an ability with cooldown N used in round R is ready
+// again in round R+N+1.
+func (cs *CombatState) startCooldown(user combat.Combatant, ability *gamedata.AbilityDef) {
+	if ability.Cooldown <= 0 {
+		return
+	}
+	if cs.cooldowns == nil {
+		cs.cooldowns = make(map[combat.Combatant]map[string]int)
+	}
+	if cs.cooldowns[user] == nil {
+		cs.cooldowns[user] = make(map[string]int)
+	}
+	cs.cooldowns[user][ability.ID] = cs.Round + ability.Cooldown + 1
+}
+
+// onCooldown returns true if the combatant used the ability too recently
+// to use it again this round. A planned use counts, so a hasted member
+// can't plan the same ability twice in a round.
+func (cs *CombatState) onCooldown(c combat.Combatant, ability *gamedata.AbilityDef) bool {
+	if ability.Cooldown <= 0 {
+		return false
+	}
+	if cs.cooldowns[c][ability.ID] > cs.Round {
+		return true
+	}
+	for _, action := range cs.planned {
+		if action.member == c && action.ability.ID == ability.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// cloneCooldowns copies the cooldown table for a rewind snapshot.
+func cloneCooldowns(cooldowns map[combat.Combatant]map[string]int) map[combat.Combatant]map[string]int {
+	if cooldowns == nil {
+		return nil
+	}
+	c := make(map[combat.Combatant]map[string]int, len(cooldowns))
+	for user, ready := range cooldowns {
+		c[user] = maps.Clone(ready)
+	}
+	return c
+}
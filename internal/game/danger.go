@@ -0,0 +1,57 @@
+package game
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/samdwyer/dungeonband/internal/combat"
+)
+
+// encounterDanger is one fight's predicted difficulty next to how dangerous
+// it turned out to be, so the estimate can be charted against reality.
+type encounterDanger struct {
+	EncounterID    string
+	Predicted      combat.Difficulty
+	HPLostFraction float64 // Share of the party's starting HP lost
+	MembersDowned  int     // Members alive at the start and dead at the end
+}
+
+// estimateDifficulty predicts the current encounter's difficulty and
+// remembers the party's HP and headcount to measure the outcome against.
+func (g *Game) estimateDifficulty() {
+	cs := g.combatState
+	var enemies, party []combat.Combatant
+	for _, e := range cs.Enemies {
+		enemies = append(enemies, e)
+	}
+	for _, m := range g.party.Members {
+		party = append(party, m)
+	}
+	cs.Difficulty = g.effectResolver.EstimateDifficulty(enemies, party)
+	cs.startHP = g.totalPartyHP()
+	cs.startAlive = g.party.AliveMemberCount()
+}
+
+// realizedDanger measures how much the current encounter hurt the party.
+func (g *Game) realizedDanger() encounterDanger {
+	cs := g.combatState
+	danger := encounterDanger{
+		EncounterID:   cs.EncounterID,
+		Predicted:     cs.Difficulty,
+		MembersDowned: max(cs.startAlive-g.party.AliveMemberCount(), 0),
+	}
+	if cs.startHP > 0 {
+		danger.HPLostFraction = max(float64(cs.startHP-g.totalPartyHP())/float64(cs.startHP), 0)
+	}
+	return danger
+}
+
+// difficultyAttrs returns the span attributes for a difficulty estimate.
+func difficultyAttrs(d combat.Difficulty) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.Float64("difficulty.ratio", d.Ratio),
+		attribute.Int("difficulty.enemy_hp", d.EnemyHP),
+		attribute.Int("difficulty.enemy_dps", d.EnemyDPS),
+		attribute.Int("difficulty.party_hp", d.PartyHP),
+		attribute.Int("difficulty.party_dps", d.PartyDPS),
+	}
+}
@@ -0,0 +1,52 @@
+package game
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCombatSpansCarryPredictedAndRealizedDanger(t *testing.T) {
+	recorder := recordSpans(t)
+	g, _ := startOpenRoomCombat(t, 6, 2)
+	ctx := context.Background()
+
+	start := findSpan(recorder, "combat.start")
+	if start == nil {
+		t.Fatal("combat.start span not recorded")
+	}
+	predicted := g.combatState.Difficulty
+	if predicted.EnemyDPS == 0 || predicted.PartyDPS == 0 || predicted.Ratio <= 0 {
+		t.Fatalf("Difficulty = %+v, want both sides dealing damage", predicted)
+	}
+	if got := spanAttr(start, "difficulty.ratio").AsFloat64(); got != predicted.Ratio {
+		t.Errorf("combat.start difficulty.ratio = %v, want %v", got, predicted.Ratio)
+	}
+
+	// The fight costs the party a quarter of its HP and the wizard
+	total := g.totalPartyHP()
+	wizard := g.party.Members[2]
+	lost := wizard.GetHP()
+	wizard.TakeDamage(lost)
+	warrior := g.party.Members[0]
+	warrior.TakeDamage(total/4 - lost)
+	g.endCombat(ctx, "fled")
+
+	end := findSpan(recorder, "combat.end")
+	if end == nil {
+		t.Fatal("combat.end span not recorded")
+	}
+	if got := spanAttr(end, "danger.members_downed").AsInt64(); got != 1 {
+		t.Errorf("danger.members_downed = %d, want 1", got)
+	}
+	wantLost := float64(total-g.totalPartyHP()) / float64(total)
+	if got := spanAttr(end, "danger.hp_lost_fraction").AsFloat64(); got != wantLost {
+		t.Errorf("danger.hp_lost_fraction = %v, want %v", got, wantLost)
+	}
+
+	if len(g.stats.dangers) != 1 {
+		t.Fatalf("recorded %d encounter dangers, want 1", len(g.stats.dangers))
+	}
+	if d := g.stats.dangers[0]; d.Predicted != predicted || d.MembersDowned != 1 || d.HPLostFraction != wantLost {
+		t.Errorf("recorded danger = %+v", d)
+	}
+}
@@ -0,0 +1,138 @@
+package game
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/samdwyer/dungeonband/internal/combat"
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+	"github.com/samdwyer/dungeonband/internal/telemetry"
+)
+
+// resolveDeaths triggers the on-death effects of enemies killed since the
+// last pass, appending what happened to the combat message. killer is who
+// landed the blow with ability, both nil for deaths from status ticks.
+//
+// It runs after the killing blow is logged and before victory checks, so
+// split spawns and undead waiting to rise keep the fight going.
+func (g *Game) resolveDeaths(ctx context.Context, killer combat.Combatant, ability *gamedata.AbilityDef) {
+	if g.combatState.deathsResolved == nil {
+		g.combatState.deathsResolved = make(map[*entity.Enemy]bool)
+	}
+	g.combatState.deathNotes = ""
+
+	// Index loop: splits append to the enemy list as we go
+	for i := 0; i < len(g.combatState.Enemies); i++ {
+		enemy := g.combatState.Enemies[i]
+		if enemy.IsAlive() || g.combatState.deathsResolved[enemy] {
+			continue
+		}
+		g.combatState.deathsResolved[enemy] = true
+		if enemy.Def == nil {
+			continue
+		}
+		for _, effect := range enemy.Def.OnDeath {
+			g.triggerDeathEffect(ctx, enemy, effect, killer)
+		}
+		g.markUndying(ctx, enemy, ability)
+	}
+}
+
+// triggerDeathEffect applies a single on-death effect of a fallen enemy.
+func (g *Game) triggerDeathEffect(ctx context.Context, enemy *entity.Enemy, effect gamedata.DeathEffect, killer combat.Combatant) {
+	tracer := telemetry.Tracer("combat")
+	_, span := tracer.Start(ctx, "combat.on_death")
+	span.SetAttributes(
+		g.combatState.encounterAttr(),
+		attribute.String("enemy", enemy.ID()),
+		attribute.String("effect", string(effect.Type)),
+	)
+	defer span.End()
+
+	switch effect.Type {
+	case gamedata.DeathExplode:
+		var hits []string
+		for _, m := range g.party.Members {
+			if !m.IsAlive() {
+				continue
+			}
+			damage := m.TakeDamage(effect.Power)
+			result := combat.EffectResult{Success: true, Damage: damage}
+			g.noteAction(nil, enemy, m, result, !m.IsAlive())
+			g.playActionCues(result, !m.IsAlive())
+			hits = append(hits, m.GetName()+" takes "+itoa(damage))
+		}
+		note := enemy.GetName() + " explodes!"
+		if len(hits) > 0 {
+			note += " " + strings.Join(hits, ", ") + " damage!"
+		}
+		g.telegraphDeath(note)
+
+	case gamedata.DeathSplit:
+		def := g.enemyRegistry.GetByID(effect.Enemy)
+		if def == nil {
+			return
+		}
+		tiles := g.spawnTiles(enemy.X, enemy.Y, effect.Count)
+		if len(tiles) == 0 {
+			return
+		}
+		for _, p := range tiles {
+			spawn := entity.NewEnemyFromDef(def, p.x, p.y, enemy.RoomIndex)
+			spawn.HP = max(spawn.MaxHP/2, 1)
+			spawn.Abilities = def.RollAbilities(g.rng)
+			g.combatState.Enemies = append(g.combatState.Enemies, spawn)
+			g.enemies = append(g.enemies, spawn)
+			if g.recording != nil {
+				g.recording.unit(spawn, g.combatState.TurnCount)
+			}
+		}
+		g.combatEnemies = g.combatState.Enemies
+		span.SetAttributes(attribute.Int("spawned", len(tiles)))
+		g.telegraphDeath(enemy.GetName() + " splits into " + itoa(len(tiles)) + " " + def.Name + "s!")
+
+	case gamedata.DeathCurse:
+		member, ok := killer.(*entity.Member)
+		if !ok || !member.IsAlive() {
+			return
+		}
+		member.AddStatusEffect(combat.StatusEffect{
+			Type:           effect.Status,
+			RemainingTurns: effect.Duration,
+			Power:          effect.Power,
+		})
+		g.noteAction(nil, enemy, member, combat.EffectResult{Success: true, StatusAdded: effect.Status}, false)
+		span.SetAttributes(attribute.String("cursed", member.GetName()))
+		g.telegraphDeath("With its last breath, " + enemy.GetName() + " curses " +
+			member.GetName() + " with " + string(effect.Status) + "!")
+	}
+}
+
+// spawnTiles finds up to count free tiles nearest (x, y) for enemies split
+// from one that fell there, skipping tiles a party member stands on. It may
+// return fewer than count when the fight is crowded.
+func (g *Game) spawnTiles(x, y, count int) []position {
+	taken := map[position]bool{}
+	for _, m := range g.party.Members {
+		if m.IsAlive() {
+			taken[position{m.X, m.Y}] = true
+		}
+	}
+	var tiles []position
+	for _, p := range g.findLineFormation(x, y, count+len(taken)) {
+		if !taken[p] && len(tiles) < count {
+			tiles = append(tiles, p)
+		}
+	}
+	return tiles
+}
+
+// telegraphDeath appends an on-death effect to the combat message. The notes
+// are kept so a victory message can still show what the final kill set off.
+func (g *Game) telegraphDeath(note string) {
+	g.combatState.LastMessage += " " + note
+	g.combatState.deathNotes += " " + note
+}
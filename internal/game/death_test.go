@@ -0,0 +1,189 @@
+package game
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/samdwyer/dungeonband/internal/combat"
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+)
+
+// deathTestEnemies are scripted enemies with on-death effects. Each dies to
+// a single attack.
+var deathTestEnemies = []gamedata.EnemyDef{
+	{ID: "bomb", Name: "Bomb", Glyph: "b", HP: 1, Abilities: []string{"attack"},
+		OnDeath: []gamedata.DeathEffect{{Type: gamedata.DeathExplode, Power: 4}}},
+	{ID: "slime", Name: "Slime", Glyph: "s", HP: 1, Abilities: []string{"attack"},
+		OnDeath: []gamedata.DeathEffect{{Type: gamedata.DeathSplit, Enemy: "slimeling", Count: 2}}},
+	{ID: "slimeling", Name: "Slimeling", Glyph: "s", HP: 10, Abilities: []string{"attack"}},
+	{ID: "volatile_slime", Name: "Volatile Slime", Glyph: "v", HP: 1, Abilities: []string{"attack"},
+		OnDeath: []gamedata.DeathEffect{{Type: gamedata.DeathSplit, Enemy: "bomb", Count: 2}}},
+	{ID: "witch", Name: "Witch", Glyph: "w", HP: 1, Abilities: []string{"attack"},
+		OnDeath: []gamedata.DeathEffect{{Type: gamedata.DeathCurse, Status: gamedata.StatusPoison, Duration: 3, Power: 2}}},
+	{ID: "revenant", Name: "Revenant", Glyph: "r", HP: 10, Abilities: []string{"attack"},
+		Undying: &gamedata.Undying{Chance: 100, HPPercent: 50, Permakill: []string{"magical"}}},
+}
+
+// startDeathTestCombat starts a fight against one scripted enemy standing
+// next to the party's warrior.
+func startDeathTestCombat(t *testing.T, id string) (*Game, *entity.Enemy) {
+	t.Helper()
+	registry := gamedata.NewEnemyRegistry(deathTestEnemies)
+	enemy := entity.NewEnemyFromDef(registry.GetByID(id), 6, 2, 0)
+	g := startCombatOnMap(t, `
+############
+#..........#
+#..........#
+#..........#
+############`, 5, 2, enemy)
+	g.enemyRegistry = registry
+	g.enemies = []*entity.Enemy{enemy}
+	return g, enemy
+}
+
+// warriorAttacks has the warrior attack the target and advances combat.
+func warriorAttacks(g *Game, target *entity.Enemy) {
+	g.combatState.ActiveMemberIndex = 0
+	g.combatState.Phase = PhasePlayerTurn
+	g.performPlayerAction(context.Background(), g.abilityRegistry.GetByID("attack"), g.party.Members[0], target)
+}
+
+func TestExplodeDamagesWholeParty(t *testing.T) {
+	g, bomb := startDeathTestCombat(t, "bomb")
+	before := make([]int, len(g.party.Members))
+	for i, m := range g.party.Members {
+		before[i] = m.GetHP()
+	}
+
+	warriorAttacks(g, bomb)
+
+	for i, m := range g.party.Members {
+		if got := before[i] - m.GetHP(); got != 4 {
+			t.Errorf("%s took %d explosion damage, want 4", m.GetName(), got)
+		}
+	}
+	if !strings.Contains(g.combatState.LastMessage, "Bomb explodes!") {
+		t.Errorf("LastMessage = %q, want the explosion telegraphed", g.combatState.LastMessage)
+	}
+	if g.combatState.Phase != PhaseVictory {
+		t.Errorf("Phase = %v, want victory once the explosion resolves", g.combatState.Phase)
+	}
+}
+
+func TestSplitSpawnsHalfHPCopies(t *testing.T) {
+	g, slime := startDeathTestCombat(t, "slime")
+
+	warriorAttacks(g, slime)
+
+	if g.combatState.Phase == PhaseVictory {
+		t.Fatal("splitting should keep the fight going")
+	}
+	if got := g.combatState.AliveEnemyCount(); got != 2 {
+		t.Fatalf("alive enemies = %d, want 2 slimelings", got)
+	}
+	for _, e := range g.combatState.Enemies[1:] {
+		if e.ID() != "slimeling" || e.GetHP() != 5 {
+			t.Errorf("spawn = %s with %d HP, want slimeling with 5", e.ID(), e.GetHP())
+		}
+	}
+	if len(g.enemies) != 3 {
+		t.Errorf("dungeon enemies = %d, want the spawns added", len(g.enemies))
+	}
+	tiles := map[position]string{}
+	for _, m := range g.party.Members {
+		tiles[position{m.X, m.Y}] = m.GetName()
+	}
+	for _, e := range g.combatState.Enemies[1:] {
+		p := position{e.X, e.Y}
+		if other, ok := tiles[p]; ok {
+			t.Errorf("slimeling spawned on %s at (%d,%d)", other, e.X, e.Y)
+		}
+		if !g.dungeon.IsPassable(e.X, e.Y) {
+			t.Errorf("slimeling spawned in a wall at (%d,%d)", e.X, e.Y)
+		}
+		tiles[p] = e.GetName()
+	}
+	if !strings.Contains(g.combatState.LastMessage, "Slime splits into 2 Slimelings!") {
+		t.Errorf("LastMessage = %q", g.combatState.LastMessage)
+	}
+}
+
+func TestSplitsTakeEnemyTurns(t *testing.T) {
+	g, slime := startDeathTestCombat(t, "slime")
+	warriorAttacks(g, slime)
+	for _, m := range g.party.Members {
+		m.HP = m.MaxHP
+	}
+
+	g.executeEnemyTurns(context.Background())
+
+	hurt := 0
+	for _, m := range g.party.Members {
+		hurt += m.GetMaxHP() - m.GetHP()
+	}
+	if hurt == 0 {
+		t.Error("spawned slimelings should act in the enemy phase")
+	}
+}
+
+func TestSplitThenExplodeChain(t *testing.T) {
+	g, slime := startDeathTestCombat(t, "volatile_slime")
+	warriorAttacks(g, slime)
+
+	bombs := g.combatState.Enemies[1:]
+	if len(bombs) != 2 {
+		t.Fatalf("got %d spawns, want 2 bombs", len(bombs))
+	}
+	before := g.party.Members[1].GetHP()
+
+	warriorAttacks(g, bombs[0])
+	if got := before - g.party.Members[1].GetHP(); got != 4 {
+		t.Errorf("first bomb dealt %d, want 4", got)
+	}
+	if g.combatState.Phase == PhaseVictory {
+		t.Fatal("one bomb is still standing")
+	}
+
+	warriorAttacks(g, bombs[1])
+	if got := before - g.party.Members[1].GetHP(); got != 8 {
+		t.Errorf("both bombs dealt %d, want 8", got)
+	}
+	if g.combatState.Phase != PhaseVictory {
+		t.Errorf("Phase = %v, want victory", g.combatState.Phase)
+	}
+}
+
+func TestCurseAfflictsKiller(t *testing.T) {
+	g, witch := startDeathTestCombat(t, "witch")
+
+	warriorAttacks(g, witch)
+
+	warrior := g.party.Members[0]
+	if !combat.HasStatus(warrior, gamedata.StatusPoison) {
+		t.Error("the killer should be cursed with poison")
+	}
+	for _, m := range g.party.Members[1:] {
+		if combat.HasStatus(m, gamedata.StatusPoison) {
+			t.Errorf("%s was cursed but didn't land the killing blow", m.GetName())
+		}
+	}
+	want := "With its last breath, Witch curses " + warrior.GetName() + " with poison!"
+	if !strings.Contains(g.combatState.LastMessage, want) {
+		t.Errorf("LastMessage = %q, want it to contain %q", g.combatState.LastMessage, want)
+	}
+}
+
+func TestDeathEffectsFireOnce(t *testing.T) {
+	g, bomb := startDeathTestCombat(t, "bomb")
+	bomb.TakeDamage(bomb.GetHP())
+	hp := g.party.Members[0].GetHP()
+
+	g.resolveDeaths(context.Background(), nil, nil)
+	g.resolveDeaths(context.Background(), nil, nil)
+
+	if got := hp - g.party.Members[0].GetHP(); got != 4 {
+		t.Errorf("explosion dealt %d across two passes, want 4 once", got)
+	}
+}
@@ -0,0 +1,22 @@
+package game
+
+import "github.com/samdwyer/dungeonband/internal/ui"
+
+// debugInfo gathers the figures the debug overlay shows.
+func (g *Game) debugInfo() ui.DebugInfo {
+	info := ui.NewDebugInfo(g.dungeon, g.party, g.enemies)
+	if g.rngSource != nil {
+		info.Draws = g.rngSource.draws
+	}
+	info.Frame = g.frameTime
+	return info
+}
+
+// renderDebug draws the debug overlay over the frame, if it is shown.
+// F3 toggles it; debug mode starts with it on.
+func (g *Game) renderDebug() {
+	if !g.debugOverlay || g.displayTooSmall() {
+		return
+	}
+	g.display.ShowOverlay(Overlay{Kind: OverlayDebug, Debug: g.debugInfo()})
+}
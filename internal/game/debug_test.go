@@ -0,0 +1,33 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestF3TogglesDebugOverlay(t *testing.T) {
+	g, sim := newTestGameWithScreen(t)
+	coords := fmt.Sprintf("x:%d y:%d room:", g.party.X, g.party.Y)
+	f3 := tcell.NewEventKey(tcell.KeyF3, 0, tcell.ModNone)
+
+	g.render()
+	if strings.Contains(screenText(sim), coords) {
+		t.Fatal("debug overlay shown before it was turned on")
+	}
+
+	g.handleKeyEvent(context.Background(), f3)
+	g.render()
+	if !strings.Contains(screenText(sim), coords) {
+		t.Errorf("debug overlay missing %q after F3:\n%s", coords, screenText(sim))
+	}
+
+	g.handleKeyEvent(context.Background(), f3)
+	g.render()
+	if strings.Contains(screenText(sim), coords) {
+		t.Error("debug overlay still shown after a second F3")
+	}
+}
@@ -0,0 +1,309 @@
+package game
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+	"github.com/samdwyer/dungeonband/internal/telemetry"
+	"github.com/samdwyer/dungeonband/internal/world"
+)
+
+// demoStepDelay paces demo mode so someone watching can follow it.
+const demoStepDelay = 120 * time.Millisecond
+
+// demoFloors is how deep the party must get for the demo to count as won.
+const demoFloors = 3
+
+// demoStepLimit ends a demo that stops making progress.
+const demoStepLimit = 20000
+
+// autopilotHealFraction is the HP fraction under which the party AI heals.
+const autopilotHealFraction = 0.5
+
+// demoOutcome is how a demo run ended.
+type demoOutcome int
+
+const (
+	demoRunning demoOutcome = iota
+	demoVictory             // Descended past demoFloors
+	demoDefeat              // The whole party died
+	demoStalled             // Hit demoStepLimit
+)
+
+// String returns the outcome's telemetry name.
+func (o demoOutcome) String() string {
+	switch o {
+	case demoVictory:
+		return "victory"
+	case demoDefeat:
+		return "defeat"
+	case demoStalled:
+		return "stalled"
+	default:
+		return "running"
+	}
+}
+
+// demoTick is posted to the event loop to take the next autoplay step.
+type demoTick struct{}
+
+// autopilot plays the game in demo mode: it explores unvisited rooms, fights
+// whatever sees the party and takes the stairs once a floor is explored.
+type autopilot struct {
+	delay   time.Duration
+	steps   int
+	outcome demoOutcome
+}
+
+// scheduleDemoTick asks for the next autoplay step after the step delay.
+// Only one tick is ever pending, so a slow frame never queues a backlog.
+func (g *Game) scheduleDemoTick() {
+	time.AfterFunc(g.demo.delay, func() {
+		_ = g.display.PostEvent(tcell.NewEventInterrupt(demoTick{}))
+	})
+}
+
+// handleDemoKey lets a watcher stop the demo. Other keys are ignored so
+// the party stays under AI control.
+func (g *Game) handleDemoKey(ev *tcell.EventKey) {
+	switch {
+	case ev.Key() == tcell.KeyEscape, ev.Key() == tcell.KeyCtrlC:
+		g.running = false
+	case ev.Key() == tcell.KeyRune && (ev.Rune() == 'q' || ev.Rune() == 'Q'):
+		g.running = false
+	}
+}
+
+// demoStep takes one autoplay action and ends the run once the demo is won
+// or lost.
+func (g *Game) demoStep(ctx context.Context) {
+	a := g.demo
+	a.steps++
+	g.autoplay(ctx)
+
+	switch {
+	case g.floor > demoFloors:
+		a.outcome = demoVictory
+	case g.party.IsDefeated():
+		a.outcome = demoDefeat
+	case a.steps >= demoStepLimit:
+		a.outcome = demoStalled
+	default:
+		g.scheduleDemoTick()
+		return
+	}
+
+	tracer := telemetry.Tracer("game")
+	_, span := tracer.Start(ctx, "game.demo_end")
+	span.SetAttributes(
+		attribute.String("outcome", a.outcome.String()),
+		attribute.Int("floor", g.floor),
+		attribute.Int("steps", a.steps),
+	)
+	span.SetAttributes(g.runAttrs()...)
+	span.End()
+	g.running = false
+}
+
+// autoplay takes the single action a player would take next.
+func (g *Game) autoplay(ctx context.Context) {
+	switch {
+	case len(g.instructions) > 0:
+		g.dismissInstruction()
+	case g.paused:
+		g.paused = false
+	case g.confirmAbandon:
+		g.confirmAbandon = false
+	case g.pendingDescend:
+		g.handleDescendPrompt(ctx, tcell.NewEventKey(tcell.KeyRune, 'y', tcell.ModNone))
+	case g.casting != nil:
+		g.casting = nil
+	case g.shrine != nil:
+		g.autoplayShrine(ctx)
+	case g.items != nil:
+		g.items = nil
+	case g.state == StateCombat:
+		g.autoplayCombat(ctx)
+	default:
+		g.autoplayExplore(ctx)
+	}
+}
+
+// autoplayCombat plays the active member's turn, or closes a finished fight.
+func (g *Game) autoplayCombat(ctx context.Context) {
+	switch g.combatState.Phase {
+	case PhaseVictory, PhaseDefeat:
+		g.handleCombatEnd(ctx)
+		return
+	case PhaseSelectTarget:
+		g.cancelTargeting()
+	}
+
+	member := g.getActiveMember()
+	if member == nil {
+		return
+	}
+	for _, index := range g.combatPriorities(member) {
+		if g.tryCombatAbility(ctx, index) {
+			return
+		}
+	}
+
+	// Nothing would spend the turn, so pass it rather than stall
+	g.combatState.LastMessage = member.GetName() + " waits."
+	g.advanceToNextPartyMember()
+	if g.combatState.Phase == PhaseEnemyTurn {
+		g.executeEnemyTurns(ctx)
+	}
+}
+
+// combatPriorities orders the member's abilities, as indices into
+// combatAbilities, by what the party AI wants to do: heal the badly hurt,
+// cleanse the afflicted, then hit as hard as it can, then anything else.
+func (g *Game) combatPriorities(member *entity.Member) []int {
+	abilities := g.combatAbilities(member)
+	hurt := 0
+	for _, m := range g.aliveMembers() {
+		if float64(m.GetHP()) < autopilotHealFraction*float64(m.GetMaxHP()) {
+			hurt++
+		}
+	}
+	afflicted := g.afflictedMemberIndex() >= 0
+
+	rank := func(a *gamedata.AbilityDef) int {
+		switch {
+		case a.EffectType == gamedata.EffectHeal && a.TargetType == gamedata.TargetAllAllies && hurt > 1:
+			return 0
+		case a.EffectType == gamedata.EffectHeal && hurt > 0:
+			return 1
+		case a.EffectType == gamedata.EffectCleanse && afflicted:
+			return 2
+		case a.IsOffensive():
+			return 3
+		default:
+			return 4
+		}
+	}
+
+	order := make([]int, len(abilities))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(i, j int) int {
+		a, b := abilities[i], abilities[j]
+		if ri, rj := rank(a), rank(b); ri != rj {
+			return ri - rj
+		}
+		// Hardest hitter first among equals
+		return b.BasePower - a.BasePower
+	})
+	return order
+}
+
+// tryCombatAbility selects the active member's ability at index and aims it
+// at its default target, as pressing the number key and then Enter would.
+// Returns true if that spent the turn; otherwise the selection is undone.
+func (g *Game) tryCombatAbility(ctx context.Context, index int) bool {
+	turn := g.combatState.TurnCount
+	g.handleCombatAbilitySelection(ctx, index)
+	if g.combatState.Phase == PhaseSelectTarget {
+		g.confirmTarget(ctx)
+		if g.combatState.Phase == PhaseSelectTarget {
+			g.cancelTargeting()
+		}
+	}
+	return g.combatState.TurnCount != turn
+}
+
+// autoplayShrine takes the blessing the party needs most: a cure for any
+// ailment, then healing if anyone is badly hurt, otherwise MP.
+func (g *Game) autoplayShrine(ctx context.Context) {
+	blessing := blessingRestoreMP
+	switch {
+	case g.afflictedMemberIndex() >= 0:
+		blessing = blessingCleanse
+	case g.partyHPFraction() < autopilotHealFraction:
+		blessing = blessingRestoreHP
+	}
+	shrine := g.shrine.shrine
+	g.shrine = nil
+	g.useShrine(ctx, shrine, blessing)
+}
+
+// autoplayExplore fights anything in sight of the party that isn't shaken
+// from a fight the party fled, otherwise walks one step toward the nearest
+// unvisited room, or the stairs once every room has been visited.
+func (g *Game) autoplayExplore(ctx context.Context) {
+	for _, e := range g.enemies {
+		if e.IsAlive() && !g.isShaken(e) && g.notices(e) {
+			g.transitionState(ctx, StateCombat, "demo")
+			return
+		}
+	}
+
+	g.noteVisitedRooms()
+	for {
+		tx, ty, toStairs := g.demoDestination()
+		// Walk around the stairs until it's time to take them
+		blocked := func(x, y int) bool { return !toStairs && g.dungeon.IsStairs(x, y) }
+		if x, y, ok := g.dungeon.NextStepToward(g.party.X, g.party.Y, tx, ty, 0, blocked); ok {
+			g.tryMove(ctx, x-g.party.X, y-g.party.Y)
+			return
+		}
+		if toStairs {
+			return // Nowhere left to go
+		}
+		// Unreachable without crossing the stairs; skip it
+		g.visitedRooms[g.nearestUnvisitedRoom()] = true
+	}
+}
+
+// demoDestination returns where the party is heading: the center of the
+// nearest unvisited room, or the stairs.
+func (g *Game) demoDestination() (x, y int, toStairs bool) {
+	if i := g.nearestUnvisitedRoom(); i >= 0 {
+		x, y = g.dungeon.Rooms[i].Center()
+		return x, y, false
+	}
+	return g.dungeon.StairsX, g.dungeon.StairsY, true
+}
+
+// noteVisitedRooms marks the rooms the party stands in as visited,
+// starting a fresh set on each floor.
+func (g *Game) noteVisitedRooms() []int {
+	if g.visitedRooms == nil || g.visitedFloor != g.floor {
+		g.visitedFloor = g.floor
+		g.visitedRooms = make(map[int]bool)
+	}
+	var entered []int
+	for i, room := range g.dungeon.Rooms {
+		if room.Contains(g.party.X, g.party.Y) && !g.visitedRooms[i] {
+			g.visitedRooms[i] = true
+			entered = append(entered, i)
+		}
+	}
+	return entered
+}
+
+// nearestUnvisitedRoom returns the closest room the party hasn't entered,
+// or the autopilot has given up on, ignoring the stairs room. Returns -1
+// if there is none.
+func (g *Game) nearestUnvisitedRoom() int {
+	best, bestDist := -1, 0
+	for i, room := range g.dungeon.Rooms {
+		x, y := room.Center()
+		if g.visitedRooms[i] || g.dungeon.IsStairs(x, y) {
+			continue
+		}
+		if d := world.Distance(g.party.X, g.party.Y, x, y); best < 0 || d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
@@ -0,0 +1,82 @@
+package game
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// queueDisplay is a headless display with a working event queue, like a
+// terminal nobody is typing at. PollEvent reports the display closed if
+// nothing arrives for a while, so a stuck game can't hang the test.
+type queueDisplay struct {
+	NullDisplay
+	events chan tcell.Event
+}
+
+func newQueueDisplay() *queueDisplay {
+	return &queueDisplay{events: make(chan tcell.Event, 16)}
+}
+
+func (d *queueDisplay) PostEvent(ev tcell.Event) error {
+	select {
+	case d.events <- ev:
+		return nil
+	default:
+		return tcell.ErrEventQFull
+	}
+}
+
+func (d *queueDisplay) PollEvent() tcell.Event {
+	select {
+	case ev := <-d.events:
+		return ev
+	case <-time.After(5 * time.Second):
+		return nil
+	}
+}
+
+func TestDemoPlaysToAnOutcome(t *testing.T) {
+	for _, seed := range []int64{1, 42, 7777} {
+		g, err := New(Config{Seed: seed, Demo: true, SkipDescendConfirm: seed == 42}, newQueueDisplay())
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		g.demo.delay = 0
+		if err := g.Run(context.Background()); err != nil {
+			t.Fatalf("seed %d: Run: %v", seed, err)
+		}
+
+		switch g.demo.outcome {
+		case demoVictory:
+			if g.floor <= demoFloors {
+				t.Errorf("seed %d: won on floor %d", seed, g.floor)
+			}
+		case demoDefeat:
+			if !g.party.IsDefeated() {
+				t.Errorf("seed %d: lost with the party standing", seed)
+			}
+		default:
+			t.Errorf("seed %d: demo ended %s on floor %d after %d steps, want victory or defeat",
+				seed, g.demo.outcome, g.floor, g.demo.steps)
+		}
+	}
+}
+
+func TestDemoIgnoresKeysButQuit(t *testing.T) {
+	g := newTestGame(t)
+	g.demo = &autopilot{}
+	x, y := g.party.X, g.party.Y
+
+	g.handleKeyEvent(context.Background(), pressRune('l'))
+	g.handleKeyEvent(context.Background(), pressRune('c'))
+	if g.party.X != x || g.party.Y != y || g.state != StateExplore {
+		t.Error("keys should not move the party or start fights in demo mode")
+	}
+	g.handleKeyEvent(context.Background(), pressRune('q'))
+	if g.running {
+		t.Error("q should stop the demo")
+	}
+}
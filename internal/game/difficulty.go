@@ -0,0 +1,46 @@
+package game
+
+// Adaptive difficulty thresholds, as a fraction of the party's total max HP
+// left after a won fight.
+const (
+	struggleHPFraction = 0.35 // Below this, upcoming rooms get fewer enemies
+	breezeHPFraction   = 0.9  // Above this, upcoming rooms get more
+)
+
+// Bounds on how far the tuner can move the per-room enemy count.
+const (
+	minDifficultyShift = -2
+	maxDifficultyShift = 1
+)
+
+// tuneDifficulty nudges the spawn budget after a victory based on how much
+// of the party's HP is left. It does nothing unless adaptive difficulty is on.
+func (g *Game) tuneDifficulty() {
+	if !g.adaptive {
+		return
+	}
+	switch fraction := g.partyHPFraction(); {
+	case fraction < struggleHPFraction:
+		g.difficultyShift = max(g.difficultyShift-1, minDifficultyShift)
+	case fraction > breezeHPFraction:
+		g.difficultyShift = min(g.difficultyShift+1, maxDifficultyShift)
+	}
+}
+
+// spawnBudget applies the tuner's shift to a room's rolled enemy count,
+// always leaving at least one enemy.
+func (g *Game) spawnBudget(rolled int) int {
+	return max(rolled+g.difficultyShift, 1)
+}
+
+// partyHPFraction returns the party's current HP over its total max HP.
+func (g *Game) partyHPFraction() float64 {
+	maxHP := 0
+	for _, m := range g.party.Members {
+		maxHP += m.GetMaxHP()
+	}
+	if maxHP == 0 {
+		return 0
+	}
+	return float64(g.totalPartyHP()) / float64(maxHP)
+}
@@ -0,0 +1,82 @@
+package game
+
+import (
+	"context"
+	"testing"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+)
+
+// winFightWithHP ends a won fight with every member at the given HP.
+func winFightWithHP(g *Game, hp int) {
+	goblin := entity.NewEnemyFromDef(g.enemyRegistry.GetByID("goblin"), 5, 5, 1)
+	goblin.HP = 0
+	g.state = StateCombat
+	g.combatState = NewCombatState([]*entity.Enemy{goblin})
+	for _, m := range g.party.Members {
+		m.HP = min(hp, m.MaxHP)
+	}
+	g.endCombat(context.Background(), "victory")
+}
+
+// roomEnemyCounts spawns a floor's enemies from the given seed and counts
+// them per room.
+func roomEnemyCounts(g *Game, seed int64) map[int]int {
+	g.rng.Seed(seed)
+	g.enemies = nil
+	g.spawnEnemies()
+	counts := make(map[int]int)
+	for _, e := range g.enemies {
+		counts[e.RoomIndex]++
+	}
+	return counts
+}
+
+func TestNearWipeReducesNextRoomBudget(t *testing.T) {
+	g := newTestGame(t)
+	g.adaptive = true
+	if !hasRoomWith(roomEnemyCounts(g, 99), 3) {
+		t.Fatal("seed should roll a three-enemy room before tuning")
+	}
+
+	winFightWithHP(g, 1)
+	if g.difficultyShift != -1 {
+		t.Fatalf("difficultyShift = %d after a near-wipe, want -1", g.difficultyShift)
+	}
+
+	// Rooms roll 1-3 enemies; one fewer caps every room at two
+	for room, n := range roomEnemyCounts(g, 99) {
+		if n < 1 || n > 2 {
+			t.Errorf("room %d: %d enemies after a near-wipe, want 1 or 2", room, n)
+		}
+	}
+}
+
+// hasRoomWith reports whether any room has exactly n enemies.
+func hasRoomWith(counts map[int]int, n int) bool {
+	for _, c := range counts {
+		if c == n {
+			return true
+		}
+	}
+	return false
+}
+
+func TestEasyFightRaisesBudget(t *testing.T) {
+	g := newTestGame(t)
+	g.adaptive = true
+	for i := 0; i < 3; i++ {
+		winFightWithHP(g, 1000)
+	}
+	if g.difficultyShift != maxDifficultyShift {
+		t.Errorf("difficultyShift = %d after easy fights, want capped at %d", g.difficultyShift, maxDifficultyShift)
+	}
+}
+
+func TestDifficultyIsFixedByDefault(t *testing.T) {
+	g := newTestGame(t)
+	winFightWithHP(g, 1)
+	if g.difficultyShift != 0 {
+		t.Errorf("difficultyShift = %d with adaptive difficulty off, want 0", g.difficultyShift)
+	}
+}
@@ -0,0 +1,167 @@
+package game
+
+import (
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/ui"
+	"github.com/samdwyer/dungeonband/internal/world"
+)
+
+// Display is the frontend a game draws frames to and reads input from.
+// NewTerminalDisplay wraps the tcell renderer; NullDisplay draws nothing
+// for headless runs.
+type Display interface {
+	// RenderExplore draws an explore-mode frame.
+	RenderExplore(dungeon *world.Dungeon, party *entity.Party, enemies []*entity.Enemy, seed int64)
+	// RenderCombat draws a combat frame with the combat panel, if info is set.
+	RenderCombat(dungeon *world.Dungeon, party *entity.Party, enemies []*entity.Enemy, seed int64, info *ui.CombatInfo)
+	// ShowOverlay draws a prompt, message or panel over the last frame.
+	ShowOverlay(overlay Overlay)
+	// RenderTooSmall replaces the frame with a request to enlarge the
+	// display to at least width by height cells.
+	RenderTooSmall(width, height int)
+	// Size returns the display's dimensions in cells.
+	Size() (width, height int)
+	// SetTitle sets the window title where supported.
+	SetTitle(title string)
+	// SetEnemyVisibility sets the rule for which enemies are drawn.
+	SetEnemyVisibility(rule world.Visibility)
+	// SetShowInitials chooses whether the combat formation draws members
+	// by their initial instead of their class symbol.
+	SetShowInitials(show bool)
+
+	// PollEvent waits for the next input event. nil means the display closed.
+	PollEvent() tcell.Event
+	// PostEvent injects an event. It is safe to call from other goroutines.
+	PostEvent(ev tcell.Event) error
+	// Sync forces a complete redraw.
+	Sync()
+	// Suspend hands the terminal back to the shell until Resume.
+	Suspend() error
+	// Resume takes the terminal back after Suspend.
+	Resume() error
+	// Beep rings the terminal bell. Like drawing, it must only be called
+	// from the game loop; other goroutines use BellRinger.
+	Beep() error
+}
+
+// OverlayKind is how an overlay is drawn.
+type OverlayKind int
+
+const (
+	// OverlayPrompt is a boxed prompt centered on the map
+	OverlayPrompt OverlayKind = iota
+	// OverlayMessage is a line of text just below the map
+	OverlayMessage
+	// OverlayInstruction is a titled tutorial panel
+	OverlayInstruction
+	// OverlayChoice is a boxed menu of options centered on the map
+	OverlayChoice
+	// OverlayDebug is a line of debugging figures along the top row
+	OverlayDebug
+	// OverlayRunStatus is the turn count and play time at the end of the
+	// party HUD
+	OverlayRunStatus
+)
+
+// Overlay is drawn over the last frame.
+type Overlay struct {
+	Kind   OverlayKind
+	Title  string       // Instruction panels and menus only
+	Text   string       // For menus, the options as one line
+	Choice *ui.Choice   // Menus only
+	Debug  ui.DebugInfo // Debug overlay only
+	Run    ui.RunStatus // Run status only
+}
+
+// terminalDisplay draws to a tcell screen through the ui renderer.
+type terminalDisplay struct {
+	screen   *ui.Screen
+	renderer *ui.Renderer
+
+	// Size of the last map drawn, for placing overlays
+	mapWidth, mapHeight int
+}
+
+// NewTerminalDisplay returns a display drawing to the screen. The screen is
+// owned by the caller.
+func NewTerminalDisplay(screen *ui.Screen) Display {
+	return &terminalDisplay{screen: screen, renderer: ui.NewRenderer(screen)}
+}
+
+// RenderExplore draws an explore-mode frame.
+func (d *terminalDisplay) RenderExplore(dungeon *world.Dungeon, party *entity.Party, enemies []*entity.Enemy, seed int64) {
+	d.mapWidth, d.mapHeight = dungeon.Width, dungeon.Height
+	d.renderer.Render(dungeon, party, enemies, ui.StateExplore, seed)
+}
+
+// RenderCombat draws a combat frame.
+func (d *terminalDisplay) RenderCombat(dungeon *world.Dungeon, party *entity.Party, enemies []*entity.Enemy, seed int64, info *ui.CombatInfo) {
+	d.renderer.RenderWithCombat(dungeon, party, enemies, ui.StateCombat, seed, info)
+	d.mapWidth, d.mapHeight = d.renderer.MapSize()
+}
+
+// ShowOverlay draws the overlay relative to the last map drawn.
+func (d *terminalDisplay) ShowOverlay(overlay Overlay) {
+	switch overlay.Kind {
+	case OverlayPrompt:
+		d.renderer.RenderPrompt(overlay.Text, d.mapWidth, d.mapHeight)
+	case OverlayMessage:
+		d.renderer.RenderMessage(overlay.Text, d.mapHeight+1)
+		d.screen.Show()
+	case OverlayInstruction:
+		d.renderer.RenderInstruction(overlay.Title, overlay.Text, d.mapWidth, d.mapHeight)
+	case OverlayChoice:
+		d.renderer.RenderChoice(overlay.Choice, d.mapWidth, d.mapHeight)
+	case OverlayDebug:
+		d.renderer.RenderDebug(overlay.Debug)
+	case OverlayRunStatus:
+		d.renderer.RenderRunStatus(overlay.Run, d.mapHeight)
+	}
+}
+
+// RenderTooSmall draws the request to enlarge the terminal.
+func (d *terminalDisplay) RenderTooSmall(width, height int) {
+	d.renderer.RenderTooSmall(width, height)
+}
+
+// SetEnemyVisibility sets the rule for which enemies are drawn.
+func (d *terminalDisplay) SetEnemyVisibility(rule world.Visibility) {
+	d.renderer.SetEnemyVisibility(rule)
+}
+
+// SetShowInitials chooses how the combat formation draws members.
+func (d *terminalDisplay) SetShowInitials(show bool) {
+	d.renderer.SetShowInitials(show)
+}
+
+// Housekeeping and input pass straight through to the screen.
+func (d *terminalDisplay) Size() (int, int)               { return d.screen.Size() }
+func (d *terminalDisplay) SetTitle(title string)          { d.screen.SetTitle(title) }
+func (d *terminalDisplay) PollEvent() tcell.Event         { return d.screen.PollEvent() }
+func (d *terminalDisplay) PostEvent(ev tcell.Event) error { return d.screen.PostEvent(ev) }
+func (d *terminalDisplay) Sync()                          { d.screen.Sync() }
+func (d *terminalDisplay) Suspend() error                 { return d.screen.Suspend() }
+func (d *terminalDisplay) Resume() error                  { return d.screen.Resume() }
+func (d *terminalDisplay) Beep() error                    { return d.screen.Beep() }
+
+// NullDisplay draws nothing and has no input: PollEvent reports the display
+// as closed, which ends the game loop. Use it for headless runs.
+type NullDisplay struct{}
+
+func (NullDisplay) RenderExplore(*world.Dungeon, *entity.Party, []*entity.Enemy, int64) {}
+func (NullDisplay) RenderCombat(*world.Dungeon, *entity.Party, []*entity.Enemy, int64, *ui.CombatInfo) {
+}
+func (NullDisplay) ShowOverlay(Overlay)                 {}
+func (NullDisplay) RenderTooSmall(int, int)             {}
+func (NullDisplay) Size() (int, int)                    { return 0, 0 }
+func (NullDisplay) SetTitle(string)                     {}
+func (NullDisplay) SetEnemyVisibility(world.Visibility) {}
+func (NullDisplay) SetShowInitials(bool)                {}
+func (NullDisplay) PollEvent() tcell.Event              { return nil }
+func (NullDisplay) PostEvent(tcell.Event) error         { return nil }
+func (NullDisplay) Sync()                               {}
+func (NullDisplay) Suspend() error                      { return nil }
+func (NullDisplay) Resume() error                       { return nil }
+func (NullDisplay) Beep() error                         { return nil }
@@ -0,0 +1,96 @@
+package game
+
+import (
+	"context"
+	"testing"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/ui"
+	"github.com/samdwyer/dungeonband/internal/world"
+)
+
+// recordingDisplay is a headless display that remembers what it was asked
+// to draw.
+type recordingDisplay struct {
+	NullDisplay
+	frames   []string // "explore" or "combat", in order
+	combat   []*ui.CombatInfo
+	overlays []Overlay
+	title    string
+	initials bool
+}
+
+func (d *recordingDisplay) RenderExplore(*world.Dungeon, *entity.Party, []*entity.Enemy, int64) {
+	d.frames = append(d.frames, "explore")
+}
+
+func (d *recordingDisplay) RenderCombat(_ *world.Dungeon, _ *entity.Party, _ []*entity.Enemy, _ int64, info *ui.CombatInfo) {
+	d.frames = append(d.frames, "combat")
+	d.combat = append(d.combat, info)
+}
+
+func (d *recordingDisplay) ShowOverlay(overlay Overlay) {
+	d.overlays = append(d.overlays, overlay)
+}
+
+func (d *recordingDisplay) SetTitle(title string) {
+	d.title = title
+}
+
+func (d *recordingDisplay) SetShowInitials(show bool) {
+	d.initials = show
+}
+
+func TestRenderGoesThroughDisplay(t *testing.T) {
+	g := newTestGame(t)
+	rec := &recordingDisplay{}
+	g.display = rec
+
+	g.paused = true
+	g.render()
+	if len(rec.frames) != 1 || rec.frames[0] != "explore" {
+		t.Fatalf("frames = %v, want one explore frame", rec.frames)
+	}
+	if len(rec.overlays) != 1 || rec.overlays[0] != (Overlay{Kind: OverlayPrompt, Text: pausePrompt}) {
+		t.Errorf("overlays = %+v, want the pause prompt", rec.overlays)
+	}
+
+	g.paused = false
+	g.transitionState(context.Background(), StateCombat, "manual")
+	g.render()
+	if last := rec.frames[len(rec.frames)-1]; last != "combat" {
+		t.Fatalf("frame = %s, want combat", last)
+	}
+	if info := rec.combat[len(rec.combat)-1]; info == nil || info.ActiveMember == nil {
+		t.Error("combat frame should carry the combat panel info")
+	}
+	if rec.title != windowTitle(g.floor, g.seed, StateCombat) {
+		t.Errorf("title = %q, want the combat title", rec.title)
+	}
+}
+
+func TestHeadlessRunEndsWhenDisplayCloses(t *testing.T) {
+	g, err := New(Config{Seed: 3}, NullDisplay{})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := g.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if g.running {
+		t.Error("a closed display should end the game loop")
+	}
+	if g.dungeon == nil || g.party == nil {
+		t.Error("headless runs should still set up the floor")
+	}
+}
+
+func TestShowInitialsReachesTheDisplay(t *testing.T) {
+	rec := &recordingDisplay{}
+	if _, err := New(Config{Seed: 3, ShowInitials: true}, rec); err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if !rec.initials {
+		t.Error("ShowInitials should switch the display to member initials")
+	}
+}
@@ -0,0 +1,40 @@
+package game
+
+import (
+	"context"
+	"testing"
+
+	"github.com/samdwyer/dungeonband/internal/combat"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+)
+
+func TestPoisonTicksWhileExploring(t *testing.T) {
+	g := newTestGame(t)
+	g.dungeon = dungeonFromMap(`
+##########
+#........#
+##########`)
+	g.party.SetPosition(1, 1)
+	rogue := g.party.Members[1]
+	rogue.AddStatusEffect(combat.StatusEffect{Type: gamedata.StatusPoison, RemainingTurns: 5, Power: 2})
+	hp := rogue.GetHP()
+
+	for i := 0; i < 3*exploreStepsPerTurn; i++ {
+		g.party.SetPosition(1, 1)
+		g.tryMove(context.Background(), 1, 0)
+	}
+
+	if got := hp - rogue.GetHP(); got != 6 {
+		t.Errorf("rogue lost %d HP over 3 turns of steps, want 6", got)
+	}
+	if want := rogue.GetName() + " takes 2 poison damage."; g.message != want {
+		t.Errorf("message = %q, want %q", g.message, want)
+	}
+
+	// Walking into a wall doesn't tick
+	g.party.SetPosition(1, 1)
+	g.tryMove(context.Background(), 0, -1)
+	if got := hp - rogue.GetHP(); got != 6 {
+		t.Errorf("blocked move ticked poison: lost %d HP, want 6", got)
+	}
+}
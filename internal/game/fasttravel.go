@@ -0,0 +1,173 @@
+package game
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/telemetry"
+	"github.com/samdwyer/dungeonband/internal/ui"
+	"github.com/samdwyer/dungeonband/internal/world"
+)
+
+// roomsTitle heads the fast-travel menu.
+const roomsTitle = "Travel to which room?"
+
+// roomMenu lists the rooms the party can fast-travel to.
+type roomMenu struct {
+	rooms  []int // Room indices, in menu order
+	choice *ui.Choice
+}
+
+// openRoomMenu lists the rooms entered this floor that hold no living
+// enemies, other than the one the party stands in.
+func (g *Game) openRoomMenu() {
+	if g.scoutControl {
+		g.message = "The scout moves on foot. (s: control the party)"
+		return
+	}
+	g.noteVisitedRooms()
+	here := g.dungeon.RoomIndexAt(g.party.X, g.party.Y)
+	menu := &roomMenu{}
+	var labels []string
+	for i := range g.dungeon.Rooms {
+		if !g.visitedRooms[i] || i == here || g.roomOccupant(i) != nil {
+			continue
+		}
+		menu.rooms = append(menu.rooms, i)
+		labels = append(labels, g.roomLabel(i))
+	}
+	if len(labels) == 0 {
+		g.message = "There are no cleared rooms to travel to."
+		return
+	}
+	menu.choice = ui.NewChoice(roomsTitle, labels...)
+	g.rooms = menu
+}
+
+// roomLabel describes a room in the fast-travel menu, e.g.
+// "Room 3, 12 steps (stairs)".
+func (g *Game) roomLabel(i int) string {
+	room := g.dungeon.Rooms[i]
+	x, y := room.Center()
+	label := fmt.Sprintf("Room %d, %d steps", i+1, len(g.travelPath(x, y)))
+	if room.Contains(g.dungeon.StairsX, g.dungeon.StairsY) {
+		label += " (stairs)"
+	} else if room.Prefab != "" {
+		label += " (" + room.Prefab + ")"
+	}
+	return label
+}
+
+// handleRoomKey passes a key press to the open fast-travel menu.
+func (g *Game) handleRoomKey(ctx context.Context, ev *tcell.EventKey) {
+	chosen, done := g.rooms.choice.HandleKey(ev)
+	if !done {
+		return
+	}
+	rooms := g.rooms.rooms
+	g.rooms = nil
+	if chosen >= 0 {
+		g.fastTravel(ctx, rooms[chosen])
+	}
+}
+
+// fastTravel takes the party to the center of a room. A route that passes
+// a living enemy is refused. A route no enemy can see is walked at once;
+// otherwise the party walks it as queued travel, which stops if an enemy
+// comes into view. Returns false if the party didn't set off.
+func (g *Game) fastTravel(ctx context.Context, room int) bool {
+	x, y := g.dungeon.Rooms[room].Center()
+	path := g.travelPath(x, y)
+
+	tracer := telemetry.Tracer("game")
+	_, span := tracer.Start(ctx, "game.fast_travel")
+	defer span.End()
+	span.SetAttributes(
+		attribute.Int("room", room),
+		attribute.Int("steps", len(path)),
+	)
+
+	if len(path) == 0 {
+		g.message = "There's no way there."
+		span.SetAttributes(attribute.String("outcome", "no_path"))
+		return false
+	}
+	if e := g.routeBlocker(path); e != nil {
+		g.message = e.GetName() + " blocks the way to room " + itoa(room+1) + "."
+		span.SetAttributes(attribute.String("outcome", "blocked"))
+		return false
+	}
+	if g.routeWatched(path) {
+		g.startTravel(travelRoom, x, y)
+		span.SetAttributes(attribute.String("outcome", "walk"))
+		return true
+	}
+
+	span.SetAttributes(attribute.String("outcome", "instant"))
+	for _, step := range path {
+		px, py := g.party.X, g.party.Y
+		g.tryMove(ctx, step.X-px, step.Y-py)
+		// Anything that needs an answer ends the trip where it happened
+		if g.party.X == px && g.party.Y == py || g.modalOpen() || g.state != StateExplore || g.gameOver {
+			break
+		}
+	}
+	if g.message == "" && g.party.X == x && g.party.Y == y {
+		g.message = "The party arrives in room " + itoa(room+1) + "."
+	}
+	return true
+}
+
+// roomOccupant returns a living enemy in the room, or nil if it is clear.
+func (g *Game) roomOccupant(room int) *entity.Enemy {
+	for _, e := range g.enemies {
+		if e.IsAlive() && g.dungeon.Rooms[room].Contains(e.X, e.Y) {
+			return e
+		}
+	}
+	return nil
+}
+
+// routeBlocker returns a living enemy on or next to the path, or in a room
+// the path passes through. Returns nil if the route is clear.
+func (g *Game) routeBlocker(path []world.Point) *entity.Enemy {
+	crossed := make(map[int]bool)
+	for _, p := range path {
+		if i := g.dungeon.RoomIndexAt(p.X, p.Y); i >= 0 {
+			crossed[i] = true
+		}
+	}
+	for _, e := range g.enemies {
+		if !e.IsAlive() {
+			continue
+		}
+		if i := g.dungeon.RoomIndexAt(e.X, e.Y); i >= 0 && crossed[i] {
+			return e
+		}
+		for _, p := range path {
+			if world.Distance(p.X, p.Y, e.X, e.Y) <= 1 {
+				return e
+			}
+		}
+	}
+	return nil
+}
+
+// routeWatched reports whether a living enemy could see any tile of the path.
+func (g *Game) routeWatched(path []world.Point) bool {
+	for _, e := range g.enemies {
+		if !e.IsAlive() {
+			continue
+		}
+		for _, p := range path {
+			if g.dungeon.CanSee(e.X, e.Y, p.X, p.Y, world.SightRadius) {
+				return true
+			}
+		}
+	}
+	return false
+}
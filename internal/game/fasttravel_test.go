@@ -0,0 +1,90 @@
+package game
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/world"
+)
+
+// fastTravelLayout is two rooms joined by a corridor, with a nook off the
+// corridor's south side.
+const fastTravelLayout = `
+#################
+#....#####......#
+#..........#....#
+#....###.##.....#
+#################`
+
+// startFastTravelGame puts the party in the west room, having already
+// visited the east one.
+func startFastTravelGame(t *testing.T) *Game {
+	t.Helper()
+	g := newTestGame(t)
+	g.dungeon = dungeonFromMap(fastTravelLayout)
+	g.dungeon.Rooms = []world.Room{
+		{X: 1, Y: 1, Width: 4, Height: 3},
+		{X: 11, Y: 1, Width: 5, Height: 3},
+	}
+	g.dungeon.StairsX, g.dungeon.StairsY = -1, -1
+	g.enemies = nil
+	g.party.SetPosition(2, 2)
+	g.visitedFloor = g.floor
+	g.visitedRooms = map[int]bool{0: true, 1: true}
+	return g
+}
+
+func TestFastTravelToClearedRoom(t *testing.T) {
+	g := startFastTravelGame(t)
+
+	g.openRoomMenu()
+	if g.rooms == nil || len(g.rooms.rooms) != 1 || g.rooms.rooms[0] != 1 {
+		t.Fatalf("menu = %+v, want just the east room", g.rooms)
+	}
+	g.rooms = nil
+
+	if !g.fastTravel(context.Background(), 1) {
+		t.Fatalf("fast travel refused: %q", g.message)
+	}
+	x, y := g.dungeon.Rooms[1].Center()
+	if g.party.X != x || g.party.Y != y {
+		t.Errorf("party at (%d,%d), want the room center (%d,%d)", g.party.X, g.party.Y, x, y)
+	}
+	if g.travel != nil {
+		t.Error("an unwatched route should be crossed at once, not queued")
+	}
+}
+
+func TestFastTravelBlockedByLiveEnemy(t *testing.T) {
+	g := startFastTravelGame(t)
+	// Lurking in the nook beside the corridor
+	g.enemies = []*entity.Enemy{entity.NewEnemy(entity.EnemyGoblin, 8, 3, -1)}
+
+	if g.fastTravel(context.Background(), 1) {
+		t.Fatal("fast travel should be refused past a live enemy")
+	}
+	if g.party.X != 2 || g.party.Y != 2 {
+		t.Errorf("party moved to (%d,%d), want it to stay put", g.party.X, g.party.Y)
+	}
+	if !strings.Contains(g.message, "blocks the way") {
+		t.Errorf("message = %q, want the blocker named", g.message)
+	}
+
+	// Once the goblin falls the way is open again
+	g.enemies[0].HP = 0
+	if !g.fastTravel(context.Background(), 1) {
+		t.Errorf("fast travel refused after the goblin died: %q", g.message)
+	}
+}
+
+func TestFastTravelListsOnlyClearedRooms(t *testing.T) {
+	g := startFastTravelGame(t)
+	g.enemies = []*entity.Enemy{entity.NewEnemy(entity.EnemyGoblin, 14, 2, 1)}
+
+	g.openRoomMenu()
+	if g.rooms != nil {
+		t.Errorf("menu = %+v, want none with the only other room occupied", g.rooms.rooms)
+	}
+}
@@ -0,0 +1,119 @@
+package game
+
+import (
+	"log"
+
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+)
+
+// flavorCooldown is the fewest steps between two flavor messages, so the
+// log isn't spammed.
+const flavorCooldown = 15
+
+// ambientChance is the percent chance of an ambient message on each step
+// once the cooldown has passed.
+const ambientChance = 10
+
+// loadFlavor loads the flavor table, or returns nil and runs without
+// flavor if it is missing or invalid.
+func loadFlavor() *gamedata.FlavorFile {
+	flavor, err := gamedata.LoadFlavor()
+	if err == nil {
+		err = flavor.Validate()
+	}
+	if err != nil {
+		log.Printf("Warning: failed to load flavor: %v (no ambient messages)", err)
+		return nil
+	}
+	return flavor
+}
+
+// emitFlavor runs after every step. Entering a room for the first time may
+// fire a one-shot flavor event; otherwise, once the cooldown has passed, an
+// ambient message may drift in. Rolls come from the run RNG whether or not
+// flavor is hidden, so hiding it never changes the run. A message already
+// on screen wins over flavor.
+func (g *Game) emitFlavor(entered []int) {
+	if g.flavor == nil || g.tutorial {
+		return
+	}
+	g.flavorSteps++
+
+	text := ""
+	for _, room := range entered {
+		if e := g.rollFlavorEvent(room); e != nil {
+			text = e.Text
+			break
+		}
+	}
+	if text == "" && g.flavorSteps >= flavorCooldown {
+		text = g.rollAmbient()
+	}
+	if text == "" {
+		return
+	}
+	g.flavorSteps = 0
+	if !g.hideFlavor && g.message == "" {
+		g.message = text
+	}
+}
+
+// rollFlavorEvent rolls each event that hasn't fired yet and can fire in
+// the room, returning the first that hits, or nil.
+func (g *Game) rollFlavorEvent(room int) *gamedata.FlavorEvent {
+	for i := range g.flavor.Events {
+		e := &g.flavor.Events[i]
+		if g.flavorFired[e.ID] || !e.OnFloor(g.floor) {
+			continue
+		}
+		if e.BossFloor && (!g.bossOnFloor() || g.bossInRoom(room)) {
+			continue
+		}
+		if g.rng.Intn(100) < e.Chance {
+			if g.flavorFired == nil {
+				g.flavorFired = make(map[string]bool)
+			}
+			g.flavorFired[e.ID] = true
+			return e
+		}
+	}
+	return nil
+}
+
+// rollAmbient rolls for an ambient message and picks one for this floor by
+// weight. Returns "" if the roll misses or the floor has none.
+func (g *Game) rollAmbient() string {
+	if g.rng.Intn(100) >= ambientChance {
+		return ""
+	}
+	total := 0
+	for _, a := range g.flavor.Ambient {
+		if a.OnFloor(g.floor) {
+			total += a.Weight
+		}
+	}
+	if total == 0 {
+		return ""
+	}
+	n := g.rng.Intn(total)
+	for _, a := range g.flavor.Ambient {
+		if !a.OnFloor(g.floor) {
+			continue
+		}
+		if n < a.Weight {
+			return a.Text
+		}
+		n -= a.Weight
+	}
+	return ""
+}
+
+// bossOnFloor reports whether a living boss is somewhere on this floor.
+func (g *Game) bossOnFloor() bool {
+	for _, e := range g.enemies {
+		if e.IsAlive() && e.IsBoss() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package game
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+	"github.com/samdwyer/dungeonband/internal/world"
+)
+
+// flavorRoom is a long corridor of a room to pace back and forth in.
+const flavorRoom = `
+######################
+#....................#
+######################`
+
+// newFlavorGame builds a game with the embedded flavor table in a room the
+// party can pace.
+func newFlavorGame(t *testing.T) *Game {
+	t.Helper()
+	g := newTestGame(t)
+	g.dungeon = dungeonFromMap(flavorRoom)
+	g.enemies = nil
+	g.party.X, g.party.Y = 1, 1
+	g.flavor = loadFlavor()
+	if g.flavor == nil {
+		t.Fatal("embedded flavor table failed to load")
+	}
+	return g
+}
+
+// pace walks the party back and forth, returning the step numbers and
+// texts of the flavor messages that appeared.
+func pace(g *Game, steps int) (at []int, texts []string) {
+	dx := 1
+	for i := 0; i < steps; i++ {
+		if !g.dungeon.IsPassable(g.party.X+dx, g.party.Y) {
+			dx = -dx
+		}
+		g.tryMove(context.Background(), dx, 0)
+		if g.message != "" {
+			at = append(at, i)
+			texts = append(texts, g.message)
+		}
+	}
+	return at, texts
+}
+
+func TestAmbientFlavorIsDeterministicPerSeed(t *testing.T) {
+	_, first := pace(newFlavorGame(t), 600)
+	_, second := pace(newFlavorGame(t), 600)
+
+	if len(first) == 0 {
+		t.Fatal("600 steps produced no ambient messages")
+	}
+	if !slices.Equal(first, second) {
+		t.Errorf("same seed, different flavor:\n%q\n%q", first, second)
+	}
+}
+
+func TestAmbientFlavorIsRateLimited(t *testing.T) {
+	at, _ := pace(newFlavorGame(t), 600)
+	if len(at) < 2 {
+		t.Fatalf("600 steps produced %d ambient messages, want several", len(at))
+	}
+	for i := 1; i < len(at); i++ {
+		if gap := at[i] - at[i-1]; gap < flavorCooldown {
+			t.Errorf("flavor at steps %d and %d, want at least %d apart", at[i-1], at[i], flavorCooldown)
+		}
+	}
+}
+
+func TestHiddenFlavorStillRolls(t *testing.T) {
+	shown := newFlavorGame(t)
+	pace(shown, 300)
+	hidden := newFlavorGame(t)
+	hidden.hideFlavor = true
+
+	if _, texts := pace(hidden, 300); len(texts) != 0 {
+		t.Errorf("hidden flavor showed %q", texts)
+	}
+	if shown.rng.Int63() != hidden.rng.Int63() {
+		t.Error("hiding flavor changed the run's random draws")
+	}
+}
+
+func TestFlavorEventFiresOncePerRun(t *testing.T) {
+	g := newFlavorGame(t)
+	g.flavor = &gamedata.FlavorFile{Events: []gamedata.FlavorEvent{
+		{ID: "scratches", Text: "Scratch marks.", Chance: 100},
+	}}
+	g.dungeon.Rooms = []world.Room{
+		{X: 1, Y: 1, Width: 5, Height: 1},
+		{X: 10, Y: 1, Width: 5, Height: 1},
+	}
+
+	_, texts := pace(g, 40)
+
+	if !slices.Equal(texts, []string{"Scratch marks."}) {
+		t.Errorf("messages = %q, want the event exactly once", texts)
+	}
+}
@@ -0,0 +1,97 @@
+package game
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/telemetry"
+)
+
+// shakenTurns is how many explore turns enemies the party fled from stay
+// shaken and won't start another fight.
+const shakenTurns = 3
+
+// fleeSearch is how many steps from where the fight was the party looks
+// for a way out of the room.
+const fleeSearch = 30
+
+// fleeCombat breaks off the fight. The party falls back to the nearest
+// corridor out of the room and the enemies it left behind are shaken for
+// a few turns, so the fight doesn't start again where it ended.
+func (g *Game) fleeCombat(ctx context.Context) {
+	fighters := g.party
+	solo := g.waitingParty != nil && len(fighters.Members) == 1
+	to, moved := g.escapeTile()
+
+	tracer := telemetry.Tracer("game")
+	ctx, span := tracer.Start(ctx, "game.flee")
+	defer span.End()
+	span.SetAttributes(
+		attribute.Int("enemies", len(g.combatEnemies)),
+		attribute.Bool("repositioned", moved),
+	)
+
+	if g.shaken == nil {
+		g.shaken = make(map[*entity.Enemy]int)
+	}
+	for _, e := range g.combatEnemies {
+		if e.IsAlive() {
+			g.shaken[e] = shakenTurns
+		}
+	}
+	g.transitionState(ctx, StateExplore, "flee")
+
+	if !moved {
+		return
+	}
+	switch {
+	case solo && g.party.Scout != nil:
+		// The scout fled alone and is still out scouting
+		g.party.Scout.SetPosition(to.x, to.y)
+	case !solo:
+		g.party.SetPosition(to.x, to.y)
+	}
+}
+
+// escapeTile returns the nearest free tile in a corridor leading out of the
+// party's room. ok is false if the party isn't in a room or no way out is
+// within reach.
+func (g *Game) escapeTile() (position, bool) {
+	room := g.dungeon.RoomIndexAt(g.party.X, g.party.Y)
+	if room < 0 {
+		return position{}, false
+	}
+	occupied := make(map[position]bool)
+	for _, e := range g.enemies {
+		if e.IsAlive() {
+			occupied[position{e.X, e.Y}] = true
+		}
+	}
+	for _, p := range g.reachableTiles(g.party.X, g.party.Y, fleeSearch).order {
+		if occupied[p] || g.dungeon.IsStairs(p.x, p.y) {
+			continue
+		}
+		if g.dungeon.CorridorOpensInto(p.x, p.y, room) {
+			return p, true
+		}
+	}
+	return position{}, false
+}
+
+// isShaken returns true if the party recently fled from the enemy.
+func (g *Game) isShaken(e *entity.Enemy) bool {
+	return g.shaken[e] > 0
+}
+
+// calmShaken counts down each shaken enemy by one explore turn.
+func (g *Game) calmShaken() {
+	for e, turns := range g.shaken {
+		if turns <= 1 || !e.IsAlive() {
+			delete(g.shaken, e)
+		} else {
+			g.shaken[e] = turns - 1
+		}
+	}
+}
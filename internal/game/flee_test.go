@@ -0,0 +1,77 @@
+package game
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/world"
+)
+
+// startSmallRoomFight starts a fight in a small room with one corridor
+// leading east out of it.
+func startSmallRoomFight(t *testing.T) (*Game, *entity.Enemy) {
+	t.Helper()
+	g := newTestGame(t)
+	g.dungeon = dungeonFromMap(`
+############
+#....#######
+#.........##
+#....#######
+############`)
+	g.dungeon.Rooms = []world.Room{{X: 1, Y: 1, Width: 4, Height: 3}}
+	g.dungeon.Corridors = []world.Corridor{{
+		ID:    0,
+		Rooms: [2]int{0, 1}, // Room 1 is off the map
+		Tiles: []world.Point{{X: 5, Y: 2}, {X: 6, Y: 2}, {X: 7, Y: 2}, {X: 8, Y: 2}, {X: 9, Y: 2}},
+	}}
+	g.party.SetPosition(2, 2)
+	goblin := entity.NewEnemy(entity.EnemyGoblin, 4, 2, 0)
+	g.enemies = []*entity.Enemy{goblin}
+	g.transitionState(context.Background(), StateCombat, "test")
+	if len(g.combatEnemies) != 1 {
+		t.Fatalf("combat enemies = %d, want 1", len(g.combatEnemies))
+	}
+	return g, goblin
+}
+
+func TestFleeMovesPartyIntoExitCorridor(t *testing.T) {
+	g, goblin := startSmallRoomFight(t)
+
+	g.handleKeyEvent(context.Background(), tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone))
+
+	if g.state != StateExplore {
+		t.Fatalf("state = %v after fleeing, want explore", g.state)
+	}
+	x, y := g.party.Position()
+	if g.dungeon.CorridorAt(x, y) != 0 || !g.dungeon.IsPassable(x, y) {
+		t.Errorf("party fled to (%d,%d), want a tile in the exit corridor", x, y)
+	}
+	if x == goblin.X && y == goblin.Y {
+		t.Errorf("party fled onto the goblin at (%d,%d)", x, y)
+	}
+}
+
+func TestFleeDoesNotRetriggerCombat(t *testing.T) {
+	g, goblin := startSmallRoomFight(t)
+	g.handleKeyEvent(context.Background(), tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone))
+	if !g.dungeon.InSight(g.party.X, g.party.Y, goblin.X, goblin.Y) {
+		t.Fatal("test room should keep the goblin in sight of the exit")
+	}
+
+	g.autoplayExplore(context.Background())
+	if g.state != StateExplore {
+		t.Fatal("a shaken goblin started another fight straight after the party fled")
+	}
+
+	for range shakenTurns {
+		g.calmShaken()
+	}
+	g.party.SetPosition(2, 2)
+	g.autoplayExplore(context.Background())
+	if g.state != StateCombat {
+		t.Error("goblin should fight again once it's no longer shaken")
+	}
+}
@@ -0,0 +1,179 @@
+package game
+
+import (
+	"context"
+	"log"
+
+	"github.com/gdamore/tcell/v2"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+	"github.com/samdwyer/dungeonband/internal/seed"
+	"github.com/samdwyer/dungeonband/internal/telemetry"
+	"github.com/samdwyer/dungeonband/internal/world"
+	"github.com/samdwyer/dungeonband/pkg/dungeon"
+)
+
+// floorClearBonusPercent is the share of max HP and MP restored to each living
+// member when the party descends after defeating every enemy on the floor.
+const floorClearBonusPercent = 25
+
+// descendPrompt is shown when the party steps onto the stairs.
+const descendPrompt = "Descend? (y/n)"
+
+// generateDungeon replaces the current floor with a freshly generated one,
+// drawn from the floor's own dungeon stream so the layout depends only on
+// the seed and the floor number.
+func (g *Game) generateDungeon(ctx context.Context) {
+	rng, src := g.countedStream(seed.Dungeon, g.floor)
+	g.dungeonSource = src
+	g.dungeon = dungeon.Generate(ctx, dungeon.Options{
+		Rand:    rng,
+		Prefabs: g.prefabs,
+	})
+	g.investigations = nil
+}
+
+// loadPrefabs parses the embedded prefab rooms, skipping any that are
+// invalid. Floors are all plain rooms if none load.
+func loadPrefabs() []world.Prefab {
+	defs, err := gamedata.LoadPrefabs()
+	if err != nil {
+		log.Printf("Warning: failed to load prefabs: %v (using plain rooms)", err)
+		return nil
+	}
+	var prefabs []world.Prefab
+	for _, def := range defs {
+		p, err := world.ParsePrefab(def.ID, def.Layout)
+		if err != nil {
+			log.Printf("Warning: skipping prefab: %v", err)
+			continue
+		}
+		prefabs = append(prefabs, p)
+	}
+	return prefabs
+}
+
+// chooseStartRoom returns the index of the room the party starts this floor
+// in. The floor must have at least one room.
+func (g *Game) chooseStartRoom() int {
+	rooms := g.dungeon.Rooms
+	switch g.start {
+	case StartRandomRoom:
+		// The stairs are in the last room
+		if len(rooms) > 1 {
+			return g.rng.Intn(len(rooms) - 1)
+		}
+	case StartFarthestFromExit:
+		if g.dungeon.StairsX < 0 {
+			return 0
+		}
+		farthest, farthestDist := 0, -1
+		for i, room := range rooms {
+			x, y := room.Center()
+			dx, dy := x-g.dungeon.StairsX, y-g.dungeon.StairsY
+			if dist := dx*dx + dy*dy; dist > farthestDist {
+				farthest, farthestDist = i, dist
+			}
+		}
+		return farthest
+	case StartRoomIndex:
+		if g.startRoomIndex < len(rooms) {
+			return g.startRoomIndex
+		}
+		log.Printf("Warning: start room %d doesn't exist on a floor of %d rooms (starting in the first room)", g.startRoomIndex, len(rooms))
+	}
+	return 0
+}
+
+// handleStairs is called after the party moves onto the stairs.
+func (g *Game) handleStairs(ctx context.Context) {
+	if g.confirmDescend {
+		g.pendingDescend = true
+		return
+	}
+	g.descend(ctx)
+}
+
+// handleDescendPrompt resolves the "Descend? (y/n)" confirmation.
+// Any key other than 'y' keeps the party on the current floor.
+func (g *Game) handleDescendPrompt(ctx context.Context, ev *tcell.EventKey) {
+	g.pendingDescend = false
+	if ev.Key() == tcell.KeyRune && (ev.Rune() == 'y' || ev.Rune() == 'Y') {
+		g.descend(ctx)
+		return
+	}
+	g.message = "You remain on floor " + itoa(g.floor) + "."
+}
+
+// descend moves the party to a freshly generated floor.
+func (g *Game) descend(ctx context.Context) {
+	tracer := telemetry.Tracer("game")
+	ctx, span := tracer.Start(ctx, "game.descend")
+	defer span.End()
+
+	cleared := g.floorCleared()
+	bonus := false
+	if cleared {
+		bonus = g.applyFloorClearBonus()
+	}
+
+	// A scout out on their own catches up before the party goes down
+	g.party.Scout = nil
+	g.scoutControl = false
+
+	g.floor++
+	g.generateDungeon(ctx)
+	g.enemies = nil
+
+	if len(g.dungeon.Rooms) > 0 {
+		g.startRoom = g.chooseStartRoom()
+		g.party.SetPosition(g.dungeon.Rooms[g.startRoom].Center())
+		g.dungeon.ReserveAround(g.party.X, g.party.Y)
+		g.spawnEnemies()
+		g.placeShrine()
+	} else {
+		g.party.SetPosition(g.dungeon.Width/2, g.dungeon.Height/2)
+	}
+
+	if bonus {
+		g.message = "Floor cleared! The party recovers before descending to floor " + itoa(g.floor) + "."
+	} else {
+		g.message = "The party descends to floor " + itoa(g.floor) + "."
+	}
+	g.updateTitle()
+
+	span.SetAttributes(
+		attribute.Int("floor", g.floor),
+		attribute.Bool("floor_cleared", cleared),
+		attribute.Bool("bonus_applied", bonus),
+		attribute.Int("enemy_count", len(g.enemies)),
+	)
+	g.autosave(ctx, "descend")
+}
+
+// floorCleared returns true if no living enemies remain on the current floor.
+func (g *Game) floorCleared() bool {
+	for _, e := range g.enemies {
+		if e.IsAlive() {
+			return false
+		}
+	}
+	return true
+}
+
+// applyFloorClearBonus restores a share of HP and MP to living members.
+// Returns true if the bonus was applied.
+func (g *Game) applyFloorClearBonus() bool {
+	if !g.floorCleared() {
+		return false
+	}
+	for _, m := range g.party.Members {
+		if !m.IsAlive() {
+			continue
+		}
+		m.Heal(max(1, m.GetMaxHP()*floorClearBonusPercent/100))
+		m.RestoreMP(m.GetMaxMP() * floorClearBonusPercent / 100)
+	}
+	return true
+}
@@ -0,0 +1,214 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+	"github.com/samdwyer/dungeonband/internal/world"
+)
+
+func TestFloorClearBonusOnlyWhenNoEnemiesRemain(t *testing.T) {
+	ctx := context.Background()
+
+	// Enemy still alive: no bonus
+	g := newTestGame(t)
+	g.enemies = []*entity.Enemy{entity.NewEnemy(entity.EnemyGoblin, 5, 5, 1)}
+	warrior := g.party.Members[0]
+	warrior.TakeDamage(20)
+	hpBefore := warrior.GetHP()
+
+	g.descend(ctx)
+
+	if warrior.GetHP() != hpBefore {
+		t.Errorf("bonus applied with enemies remaining: HP %d -> %d", hpBefore, warrior.GetHP())
+	}
+	if g.floor != 2 {
+		t.Errorf("floor = %d, want 2", g.floor)
+	}
+
+	// All enemies dead: bonus applies
+	g = newTestGame(t)
+	dead := entity.NewEnemy(entity.EnemyGoblin, 5, 5, 1)
+	dead.TakeDamage(1000)
+	g.enemies = []*entity.Enemy{dead}
+	warrior = g.party.Members[0]
+	warrior.TakeDamage(20)
+	hpBefore = warrior.GetHP()
+
+	g.descend(ctx)
+
+	want := hpBefore + warrior.GetMaxHP()*floorClearBonusPercent/100
+	if warrior.GetHP() != want {
+		t.Errorf("HP after cleared descent = %d, want %d", warrior.GetHP(), want)
+	}
+}
+
+func TestDescendPrompt(t *testing.T) {
+	ctx := context.Background()
+	g := newTestGame(t)
+	g.confirmDescend = true
+
+	stairsX, stairsY := g.dungeon.StairsX, g.dungeon.StairsY
+	if stairsX < 0 {
+		t.Fatal("test dungeon has no stairs")
+	}
+
+	// Step onto the stairs from an adjacent tile
+	g.party.X, g.party.Y = stairsX-1, stairsY
+	g.tryMove(ctx, 1, 0)
+
+	if !g.pendingDescend {
+		t.Fatal("stepping on stairs should open the descend prompt")
+	}
+
+	// Declining keeps the party on the floor
+	g.handleKeyEvent(ctx, tcell.NewEventKey(tcell.KeyRune, 'n', tcell.ModNone))
+	if g.pendingDescend || g.floor != 1 {
+		t.Errorf("after 'n': pending=%v floor=%d, want false/1", g.pendingDescend, g.floor)
+	}
+
+	// Accepting descends
+	g.party.X, g.party.Y = stairsX-1, stairsY
+	g.tryMove(ctx, 1, 0)
+	g.handleKeyEvent(ctx, tcell.NewEventKey(tcell.KeyRune, 'y', tcell.ModNone))
+	if g.floor != 2 {
+		t.Errorf("after 'y': floor = %d, want 2", g.floor)
+	}
+}
+
+func TestEmbeddedPrefabsAllParse(t *testing.T) {
+	defs, err := gamedata.LoadPrefabs()
+	if err != nil {
+		t.Fatalf("LoadPrefabs: %v", err)
+	}
+	if got := len(loadPrefabs()); got != len(defs) || got == 0 {
+		t.Errorf("parsed %d of %d embedded prefabs", got, len(defs))
+	}
+}
+
+func TestPrefabRoomsSpawnOnMarkers(t *testing.T) {
+	g := newTestGame(t)
+	g.prefabs = loadPrefabs()
+
+	checked := 0
+	for seed := int64(1); seed <= 30; seed++ {
+		g.rng.Seed(seed)
+		g.enemies = nil
+		g.generateDungeon(context.Background())
+		g.spawnEnemies()
+
+		for _, e := range g.enemies {
+			room := g.dungeon.Rooms[e.RoomIndex]
+			if room.Prefab == "" {
+				continue
+			}
+			checked++
+			onMarker := false
+			for _, s := range room.Spawns {
+				onMarker = onMarker || (s.X == e.X && s.Y == e.Y)
+			}
+			if !onMarker {
+				t.Errorf("seed %d: %s at (%d,%d) is not on a spawn marker of %s", seed, e.GetName(), e.X, e.Y, room.Prefab)
+			}
+		}
+	}
+	if checked == 0 {
+		t.Error("no enemies spawned in prefab rooms across 30 seeds")
+	}
+}
+
+func TestConfiguredStartRoom(t *testing.T) {
+	g, err := New(Config{Seed: 42, Start: StartRoomIndex, StartRoom: 2}, NullDisplay{})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	g.setup(context.Background())
+
+	room := g.dungeon.Rooms[2]
+	if !room.Contains(g.party.X, g.party.Y) {
+		t.Errorf("party at (%d,%d), want inside room 2 %+v", g.party.X, g.party.Y, room)
+	}
+	for _, e := range g.enemies {
+		if e.RoomIndex == 2 {
+			t.Errorf("%s spawned in the starting room", e.Name)
+		}
+	}
+	if !slices.ContainsFunc(g.enemies, func(e *entity.Enemy) bool { return e.RoomIndex == 0 }) {
+		t.Error("room 0 is an ordinary room when the party starts elsewhere")
+	}
+}
+
+func TestStartFarthestFromExit(t *testing.T) {
+	g, err := New(Config{Seed: 42, Start: StartFarthestFromExit}, NullDisplay{})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	g.setup(context.Background())
+
+	dist := func(x, y int) int {
+		dx, dy := x-g.dungeon.StairsX, y-g.dungeon.StairsY
+		return dx*dx + dy*dy
+	}
+	start := dist(g.party.X, g.party.Y)
+	for i, room := range g.dungeon.Rooms {
+		if d := dist(room.Center()); d > start {
+			t.Errorf("room %d is farther from the stairs than the start", i)
+		}
+	}
+}
+
+func TestNegativeStartRoomRejected(t *testing.T) {
+	if _, err := New(Config{Start: StartRoomIndex, StartRoom: -1}, NullDisplay{}); err == nil {
+		t.Error("a negative start room should be rejected")
+	}
+}
+
+func TestSameSeedSpawnsSameEnemies(t *testing.T) {
+	spawns := func() []string {
+		g, err := New(Config{Seed: 9001}, NullDisplay{})
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		g.setup(context.Background())
+		var out []string
+		for _, e := range g.enemies {
+			out = append(out, fmt.Sprintf("%s@%d,%d%v", e.ID(), e.X, e.Y, e.GetAbilityIDs()))
+		}
+		return out
+	}
+
+	first, second := spawns(), spawns()
+	if len(first) == 0 {
+		t.Fatal("expected enemies on the first floor")
+	}
+	if !slices.Equal(first, second) {
+		t.Errorf("same seed spawned differently:\n%v\n%v", first, second)
+	}
+}
+
+func TestEnemiesNeverSpawnOnReservedTiles(t *testing.T) {
+	for seed := int64(1); seed <= 100; seed++ {
+		g, err := New(Config{Seed: seed}, NullDisplay{})
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		g.setup(context.Background())
+		for floor := 0; floor < 3; floor++ {
+			for _, e := range g.enemies {
+				if g.dungeon.IsReserved(e.X, e.Y) || g.dungeon.IsStairs(e.X, e.Y) {
+					t.Errorf("seed %d floor %d: %s spawned on reserved tile (%d,%d)", seed, g.floor, e.ID(), e.X, e.Y)
+				}
+				if world.Distance(e.X, e.Y, g.party.X, g.party.Y) <= 1 {
+					t.Errorf("seed %d floor %d: %s spawned next to the party", seed, g.floor, e.ID())
+				}
+			}
+			g.descend(context.Background())
+		}
+	}
+}
@@ -0,0 +1,313 @@
+package game
+
+import (
+	"log"
+
+	"github.com/samdwyer/dungeonband/internal/combat"
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+	"github.com/samdwyer/dungeonband/internal/ui"
+	"github.com/samdwyer/dungeonband/internal/world"
+)
+
+// meleeReach is how close (in tiles) an enemy must be to hit with a melee ability.
+const meleeReach = 1
+
+// position represents a coordinate pair.
+type position struct {
+	x, y int
+}
+
+// placeFormation spreads party members onto tiles around the party position
+// for combat, skipping tiles held by enemies in the encounter. Every member
+// gets a tile; when there are fewer free tiles than members, the extras stack
+// on the rearmost tile. Returns false if no tile at all could be found, in
+// which case members stay on the party position and range rules are off.
+func (g *Game) placeFormation() bool {
+	positions := g.findFormationPositions(g.party.X, g.party.Y, len(g.party.Members))
+	if len(positions) == 0 {
+		log.Printf("Warning: no formation tiles around (%d,%d); combat ignores positioning", g.party.X, g.party.Y)
+		for _, member := range g.party.Members {
+			member.SetPosition(g.party.X, g.party.Y)
+		}
+		return false
+	}
+
+	for i, member := range g.party.Members {
+		pos := positions[len(positions)-1]
+		if i < len(positions) {
+			pos = positions[i]
+		}
+		member.SetPosition(pos.x, pos.y)
+	}
+	return true
+}
+
+// formationTileFree returns true if a party member can stand on the tile.
+func (g *Game) formationTileFree(x, y int) bool {
+	if !g.dungeon.IsPassable(x, y) {
+		return false
+	}
+	for _, e := range g.combatEnemies {
+		if e.IsAlive() && e.X == x && e.Y == y {
+			return false
+		}
+	}
+	return true
+}
+
+// findFormationPositions finds valid tiles for party members around center.
+// Tries 2x2 formation first, falls back to line formation in corridors.
+// May return fewer positions than count when space is tight.
+func (g *Game) findFormationPositions(centerX, centerY, count int) []position {
+	// Priority order for 2x2 formation (relative to center):
+	// [0][1]  = NW, NE (front row - Warrior, Rogue)
+	// [2][3]  = SW, SE (back row - Wizard, Cleric)
+	offsets2x2 := []position{
+		{-1, 0}, {0, 0}, // Front row (same Y as party, left and center)
+		{-1, 1}, {0, 1}, // Back row (below party)
+	}
+
+	// Try 2x2 formation
+	positions := make([]position, 0, count)
+	for _, off := range offsets2x2 {
+		x, y := centerX+off.x, centerY+off.y
+		if g.formationTileFree(x, y) {
+			positions = append(positions, position{x, y})
+			if len(positions) >= count {
+				return positions
+			}
+		}
+	}
+
+	// Fall back to line formation, spreading out until everyone fits
+	return g.findLineFormation(centerX, centerY, count)
+}
+
+// findLineFormation walks outward from center over free tiles, nearest first,
+// so members line up along corridors without ending up behind walls.
+func (g *Game) findLineFormation(centerX, centerY, count int) []position {
+	positions := make([]position, 0, count)
+	start := position{centerX, centerY}
+	if !g.formationTileFree(start.x, start.y) {
+		return positions
+	}
+
+	visited := map[position]bool{start: true}
+	queue := []position{start}
+	directions := []position{{0, -1}, {0, 1}, {-1, 0}, {1, 0}}
+
+	for len(queue) > 0 && len(positions) < count {
+		cur := queue[0]
+		queue = queue[1:]
+		positions = append(positions, cur)
+
+		for _, dir := range directions {
+			next := position{cur.x + dir.x, cur.y + dir.y}
+			if !visited[next] && g.formationTileFree(next.x, next.y) {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return positions
+}
+
+// Enemy formation layout: how far the front line stands from the party's
+// formation, how many enemies stand side by side, and how many steps from
+// the party formation tiles are searched for.
+const (
+	enemyFormationGap    = 2
+	enemyFormationWidth  = 5
+	enemyFormationSearch = 12
+)
+
+// enemyFormationLanes are the offsets across a line in the order they fill,
+// from its middle outwards.
+var enemyFormationLanes = []int{0, 1, -1, 2, -2}
+
+// placeEnemyFormation lines the encounter's enemies up opposite the party,
+// on the side most of them came from: front-row enemies in the nearest
+// line, the back row in the lines behind, each line centred on the party.
+// Tiles must be passable, reachable from the party without crossing walls
+// and clear of the party's formation and of enemies outside the fight.
+// Enemies that find no line tile take the nearest free reachable tile,
+// and stay put if there is none. Must run before placeFormation.
+func (g *Game) placeEnemyFormation() {
+	if len(g.combatEnemies) == 0 {
+		return
+	}
+	px, py := g.party.X, g.party.Y
+	reach := g.reachableTiles(px, py, enemyFormationSearch)
+
+	taken := make(map[position]bool)
+	dir := g.enemySide()
+	firstLine := enemyFormationGap
+	for _, off := range []position{{-1, 0}, {0, 0}, {-1, 1}, {0, 1}} {
+		taken[position{px + off.x, py + off.y}] = true
+		firstLine = max(firstLine, off.x*dir.x+off.y*dir.y+enemyFormationGap)
+	}
+	inFight := make(map[*entity.Enemy]bool)
+	for _, e := range g.combatEnemies {
+		inFight[e] = true
+	}
+	for _, e := range g.enemies {
+		if e.IsAlive() && !inFight[e] {
+			taken[position{e.X, e.Y}] = true
+		}
+	}
+
+	var front, back []*entity.Enemy
+	for _, e := range g.combatEnemies {
+		if e.Row == gamedata.RowBack {
+			back = append(back, e)
+		} else {
+			front = append(front, e)
+		}
+	}
+
+	across := position{-dir.y, dir.x}
+	var unplaced []*entity.Enemy
+	line := firstLine
+	for _, group := range [][]*entity.Enemy{front, back} {
+		for len(group) > 0 && line < firstLine+enemyFormationSearch {
+			base := position{px + dir.x*line, py + dir.y*line}
+			for _, lane := range enemyFormationLanes[:enemyFormationWidth] {
+				pos := position{base.x + across.x*lane, base.y + across.y*lane}
+				if len(group) == 0 || !reach.has(pos) || taken[pos] {
+					continue
+				}
+				taken[pos] = true
+				group[0].X, group[0].Y = pos.x, pos.y
+				group = group[1:]
+			}
+			line++
+		}
+		unplaced = append(unplaced, group...)
+	}
+
+	for _, e := range unplaced {
+		for _, pos := range reach.order {
+			if !taken[pos] {
+				taken[pos] = true
+				e.X, e.Y = pos.x, pos.y
+				break
+			}
+		}
+	}
+}
+
+// enemySide returns the direction, along one axis, from the party to the
+// middle of the encounter's enemies. Enemies gathered right on the party
+// count as being to the east.
+func (g *Game) enemySide() position {
+	dx, dy := 0, 0
+	for _, e := range g.combatEnemies {
+		dx += e.X - g.party.X
+		dy += e.Y - g.party.Y
+	}
+	switch {
+	case dx == 0 && dy == 0:
+		return position{1, 0}
+	case dx*dx >= dy*dy:
+		return position{dx / max(dx, -dx), 0}
+	default:
+		return position{0, dy / max(dy, -dy)}
+	}
+}
+
+// tileSet is a set of tiles that remembers the order they were added in.
+type tileSet struct {
+	order []position
+	in    map[position]bool
+}
+
+// has returns true if the tile is in the set.
+func (s tileSet) has(p position) bool {
+	return s.in[p]
+}
+
+// reachableTiles returns the passable tiles within steps of (x, y), nearest
+// first, walking only through passable tiles.
+func (g *Game) reachableTiles(x, y, steps int) tileSet {
+	start := position{x, y}
+	set := tileSet{in: map[position]bool{start: true}}
+	frontier := []position{start}
+	directions := []position{{0, -1}, {0, 1}, {-1, 0}, {1, 0}}
+	for range steps {
+		var next []position
+		for _, cur := range frontier {
+			for _, dir := range directions {
+				p := position{cur.x + dir.x, cur.y + dir.y}
+				if !set.in[p] && g.dungeon.IsPassable(p.x, p.y) {
+					set.in[p] = true
+					set.order = append(set.order, p)
+					next = append(next, p)
+				}
+			}
+		}
+		frontier = next
+	}
+	return set
+}
+
+// isMelee returns true for offensive abilities that need the user adjacent
+// to its target.
+func isMelee(ability *gamedata.AbilityDef) bool {
+	return ability.IsOffensive() && !ability.ReachesBackRow()
+}
+
+// inMeleeRange returns true if the enemy is close enough to hit the member.
+func inMeleeRange(enemy *entity.Enemy, member *entity.Member) bool {
+	return world.Distance(enemy.X, enemy.Y, member.X, member.Y) <= meleeReach
+}
+
+// hasMeleeAbility returns true if the enemy has an ability that needs it
+// adjacent to its target.
+func (g *Game) hasMeleeAbility(enemy *entity.Enemy) bool {
+	if g.abilitiesUnavailable() {
+		return isMelee(combat.BasicAttack)
+	}
+	for _, id := range enemy.GetAbilityIDs() {
+		if ability := g.abilityRegistry.GetByID(id); ability != nil && isMelee(ability) {
+			return true
+		}
+	}
+	return false
+}
+
+// threatenedTiles returns the tiles the combat view tints as in reach of a
+// melee enemy. Without positioning every member is in reach, so none are.
+func (g *Game) threatenedTiles() map[world.Point]bool {
+	if g.combatState == nil || !g.combatState.Positional {
+		return nil
+	}
+	var melee []*entity.Enemy
+	for _, e := range g.combatState.Enemies {
+		if g.hasMeleeAbility(e) {
+			melee = append(melee, e)
+		}
+	}
+	return ui.ThreatenedTiles(g.dungeon, melee)
+}
+
+// canMeleeReach returns true if the enemy can hit the member with a melee
+// ability. Everyone is in reach when positioning is disabled.
+func (g *Game) canMeleeReach(enemy *entity.Enemy, member *entity.Member) bool {
+	return !g.combatState.Positional || inMeleeRange(enemy, member)
+}
+
+// selectLowestHPMemberInReach returns the alive party member with lowest HP
+// within melee range of the enemy, or nil if none are.
+func (g *Game) selectLowestHPMemberInReach(enemy *entity.Enemy) *entity.Member {
+	var lowest *entity.Member
+	for _, m := range g.party.Members {
+		if m.IsAlive() && g.canMeleeReach(enemy, m) {
+			if lowest == nil || m.GetHP() < lowest.GetHP() {
+				lowest = m
+			}
+		}
+	}
+	return lowest
+}
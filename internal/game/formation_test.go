@@ -0,0 +1,339 @@
+package game
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/world"
+)
+
+// startOpenRoomCombat starts combat in an open room with the party centered
+// at (5,2) against a goblin that only knows how to attack.
+func startOpenRoomCombat(t *testing.T, goblinX, goblinY int) (*Game, *entity.Enemy) {
+	t.Helper()
+	g := newTestGame(t)
+	g.dungeon = dungeonFromMap(`
+############
+#..........#
+#..........#
+#..........#
+#..........#
+############`)
+	g.party.X, g.party.Y = 5, 2
+
+	def := *g.enemyRegistry.GetByID("goblin")
+	def.Abilities = []string{"attack"}
+	goblin := entity.NewEnemyFromDef(&def, goblinX, goblinY, 0)
+
+	g.state = StateCombat
+	g.combatEnemies = []*entity.Enemy{goblin}
+	g.initCombatState(context.Background())
+	return g, goblin
+}
+
+func TestFormationPlacesMembersOnTiles(t *testing.T) {
+	g, _ := startOpenRoomCombat(t, 9, 3)
+
+	want := []position{{4, 2}, {5, 2}, {4, 3}, {5, 3}}
+	for i, m := range g.party.Members {
+		if m.X != want[i].x || m.Y != want[i].y {
+			t.Errorf("%s at (%d,%d), want (%d,%d)", m.Name, m.X, m.Y, want[i].x, want[i].y)
+		}
+	}
+}
+
+func TestMeleeEnemyApproachesBackRowTarget(t *testing.T) {
+	g, goblin := startOpenRoomCombat(t, 9, 3)
+	ctx := context.Background()
+	wizard := g.party.Members[2] // Back row at (4,3)
+	wizard.TakeDamage(10)        // Lowest HP, so the goblin goes for them
+	hp := wizard.GetHP()
+
+	g.executeEnemyTurns(ctx)
+
+	if wizard.GetHP() != hp {
+		t.Errorf("wizard HP = %d, want %d (goblin out of reach)", wizard.GetHP(), hp)
+	}
+	if goblin.X == 9 && goblin.Y == 3 {
+		t.Error("goblin should have moved toward the wizard")
+	}
+	if !strings.Contains(g.combatState.LastMessage, "moves toward") {
+		t.Errorf("LastMessage = %q, want a movement message", g.combatState.LastMessage)
+	}
+
+	// Over the next rounds any hit on the wizard must come from melee range
+	for round := 0; round < 10; round++ {
+		before, inReach := wizard.GetHP(), inMeleeRange(goblin, wizard)
+		g.executeEnemyTurns(ctx)
+		if wizard.GetHP() < before && !inReach {
+			t.Fatalf("goblin hit the wizard from (%d,%d) without being in reach", goblin.X, goblin.Y)
+		}
+	}
+}
+
+func TestMeleeEnemyAttacksMemberInReach(t *testing.T) {
+	g, _ := startOpenRoomCombat(t, 6, 2)
+	rogue := g.party.Members[1] // Front row at (5,2), adjacent to the goblin
+	hp := rogue.GetHP()
+
+	g.executeEnemyTurns(context.Background())
+
+	if rogue.GetHP() >= hp {
+		t.Errorf("rogue HP = %d, expected the adjacent goblin to hit", rogue.GetHP())
+	}
+}
+
+// startCombatOnMap starts combat on a hand-built map with the party at (x,y).
+func startCombatOnMap(t *testing.T, layout string, x, y int, enemies ...*entity.Enemy) *Game {
+	t.Helper()
+	g := newTestGame(t)
+	g.dungeon = dungeonFromMap(layout)
+	g.party.X, g.party.Y = x, y
+	g.state = StateCombat
+	g.combatEnemies = enemies
+	g.initCombatState(context.Background())
+	return g
+}
+
+// assertEveryMemberPlaced checks that all members stand on passable tiles.
+func assertEveryMemberPlaced(t *testing.T, g *Game) {
+	t.Helper()
+	for _, m := range g.party.Members {
+		if !g.dungeon.IsPassable(m.X, m.Y) {
+			t.Errorf("%s placed on impassable tile (%d,%d)", m.Name, m.X, m.Y)
+		}
+	}
+}
+
+// distinctTiles counts the tiles the party occupies.
+func distinctTiles(g *Game) int {
+	tiles := make(map[position]bool)
+	for _, m := range g.party.Members {
+		tiles[position{m.X, m.Y}] = true
+	}
+	return len(tiles)
+}
+
+func TestFormationDeadEndCorridor(t *testing.T) {
+	// 1-wide dead end: members line up behind the party
+	g := startCombatOnMap(t, `
+#######
+#.....#
+#######`, 1, 1)
+
+	assertEveryMemberPlaced(t, g)
+	if got := distinctTiles(g); got != 4 {
+		t.Errorf("members on %d tiles, want 4", got)
+	}
+	if !g.combatState.Positional {
+		t.Error("positioning should stay on when everyone fits")
+	}
+}
+
+func TestFormationStacksWhenTooFewTiles(t *testing.T) {
+	g := startCombatOnMap(t, `
+####
+#..#
+####`, 1, 1)
+
+	assertEveryMemberPlaced(t, g)
+	if got := distinctTiles(g); got != 2 {
+		t.Errorf("members on %d tiles, want 2", got)
+	}
+	// Extras stack on the rearmost tile
+	for _, m := range g.party.Members[1:] {
+		if m.X != 2 || m.Y != 1 {
+			t.Errorf("%s at (%d,%d), want stacked at (2,1)", m.Name, m.X, m.Y)
+		}
+	}
+}
+
+func TestFormationSingleTile(t *testing.T) {
+	g := startCombatOnMap(t, `
+###
+#.#
+###`, 1, 1)
+
+	assertEveryMemberPlaced(t, g)
+	if got := distinctTiles(g); got != 1 {
+		t.Errorf("members on %d tiles, want 1", got)
+	}
+	if !g.combatState.Positional {
+		t.Error("a stacked formation still supports positioning")
+	}
+}
+
+func TestFormationDoesNotCrossWalls(t *testing.T) {
+	// Two parallel corridors: nobody should land in the lower one
+	g := startCombatOnMap(t, `
+#######
+#..####
+#######
+#.....#
+#######`, 1, 1)
+
+	assertEveryMemberPlaced(t, g)
+	for _, m := range g.party.Members {
+		if m.Y != 1 {
+			t.Errorf("%s placed across the wall at (%d,%d)", m.Name, m.X, m.Y)
+		}
+	}
+}
+
+func TestFormationSkipsEnemyTiles(t *testing.T) {
+	goblin := entity.NewEnemy(entity.EnemyGoblin, 2, 1, 0)
+	g := startCombatOnMap(t, `
+######
+#....#
+######`, 1, 1, goblin)
+
+	assertEveryMemberPlaced(t, g)
+	for _, m := range g.party.Members {
+		if m.X == goblin.X && m.Y == goblin.Y {
+			t.Errorf("%s placed on the goblin's tile", m.Name)
+		}
+	}
+}
+
+func TestFormationFallsBackToNonPositional(t *testing.T) {
+	// Party somehow standing inside solid rock
+	g := startCombatOnMap(t, `
+###
+###
+###`, 1, 1)
+
+	if g.combatState.Positional {
+		t.Error("positioning should be disabled when no tile is free")
+	}
+	for _, m := range g.party.Members {
+		if m.X != 1 || m.Y != 1 {
+			t.Errorf("%s at (%d,%d), want the party position", m.Name, m.X, m.Y)
+		}
+	}
+}
+
+func TestNonPositionalCombatLetsMeleeHit(t *testing.T) {
+	g := startCombatOnMap(t, `
+###
+###
+###`, 1, 1)
+	goblin := entity.NewEnemy(entity.EnemyGoblin, 10, 10, 0)
+	g.combatState.Enemies = []*entity.Enemy{goblin}
+
+	if !g.canMeleeReach(goblin, g.party.Members[2]) {
+		t.Error("melee range should not apply without positioning")
+	}
+}
+
+// enterFormationCombat starts a fight from explore mode in a wide room with
+// the party at (5,3), so enemies get placed in formation.
+func enterFormationCombat(t *testing.T, enemies ...*entity.Enemy) *Game {
+	t.Helper()
+	g := newTestGame(t)
+	g.dungeon = dungeonFromMap(`
+####################
+#..................#
+#..................#
+#..................#
+#..................#
+#..................#
+####################`)
+	g.party.X, g.party.Y = 5, 3
+	g.enemies = enemies
+	g.transitionState(context.Background(), StateCombat, "manual")
+	return g
+}
+
+func TestEnemyFormationClustersOppositeParty(t *testing.T) {
+	skeletons := backRowSkeletons(t, 2)
+	enemies := []*entity.Enemy{
+		entity.NewEnemy(entity.EnemyGoblin, 11, 1, 0),
+		skeletons[0],
+		entity.NewEnemy(entity.EnemyOrc, 9, 5, 0),
+		skeletons[1],
+		entity.NewEnemy(entity.EnemyGoblin, 12, 5, 0),
+	}
+	g := enterFormationCombat(t, enemies...)
+
+	seen := make(map[position]bool)
+	minX, maxX, minY, maxY := 99, -1, 99, -1
+	for _, e := range enemies {
+		pos := position{e.X, e.Y}
+		if !g.dungeon.IsPassable(e.X, e.Y) {
+			t.Errorf("%s placed on impassable (%d,%d)", e.GetName(), e.X, e.Y)
+		}
+		if seen[pos] {
+			t.Errorf("two enemies share (%d,%d)", e.X, e.Y)
+		}
+		seen[pos] = true
+		for _, m := range g.party.Members {
+			if m.X == e.X && m.Y == e.Y {
+				t.Errorf("%s shares (%d,%d) with %s", e.GetName(), e.X, e.Y, m.Name)
+			}
+		}
+		minX, maxX = min(minX, e.X), max(maxX, e.X)
+		minY, maxY = min(minY, e.Y), max(maxY, e.Y)
+	}
+	if maxX-minX > 1 || maxY-minY > 4 {
+		t.Errorf("enemies spread over (%d,%d)-(%d,%d), want a two-line cluster", minX, minY, maxX, maxY)
+	}
+	if minX <= 5+1 {
+		t.Errorf("front line at x=%d, want it east of the party and out of melee reach", minX)
+	}
+	for _, s := range skeletons {
+		if s.X != maxX {
+			t.Errorf("back-row %s at x=%d, want the back line x=%d", s.GetName(), s.X, maxX)
+		}
+	}
+}
+
+func TestEnemyFormationLinesUpInCorridor(t *testing.T) {
+	g := newTestGame(t)
+	g.dungeon = dungeonFromMap(`
+###############
+#.............#
+###############`)
+	g.party.X, g.party.Y = 3, 1
+	enemies := []*entity.Enemy{
+		entity.NewEnemy(entity.EnemyGoblin, 12, 1, 0),
+		entity.NewEnemy(entity.EnemyGoblin, 13, 1, 0),
+		entity.NewEnemy(entity.EnemyGoblin, 10, 1, 0),
+	}
+	g.enemies = enemies
+	g.transitionState(context.Background(), StateCombat, "manual")
+
+	for i, e := range enemies {
+		if want := (position{5 + i, 1}); e.X != want.x || e.Y != want.y {
+			t.Errorf("goblin %d at (%d,%d), want (%d,%d)", i, e.X, e.Y, want.x, want.y)
+		}
+	}
+}
+
+// backRowSkeletons returns n back-row skeletons standing in a column at x=12.
+func backRowSkeletons(t *testing.T, n int) []*entity.Enemy {
+	t.Helper()
+	def := newTestGame(t).enemyRegistry.GetByID("skeleton")
+	var out []*entity.Enemy
+	for i := range n {
+		out = append(out, entity.NewEnemyFromDef(def, 12, 2+i, 0))
+	}
+	return out
+}
+
+func TestOnlyMeleeEnemiesThreatenTiles(t *testing.T) {
+	g, goblin := startOpenRoomCombat(t, 9, 3)
+	if !g.combatState.Positional {
+		t.Fatal("expected a positional fight")
+	}
+	threatened := g.threatenedTiles()
+	if !threatened[world.Point{X: 8, Y: 3}] || threatened[world.Point{X: 7, Y: 3}] {
+		t.Errorf("threatened %v, want the tiles next to the goblin at (9,3)", threatened)
+	}
+
+	goblin.Abilities = []string{"bone_throw"}
+	if threatened := g.threatenedTiles(); len(threatened) != 0 {
+		t.Errorf("a goblin with only a thrown attack threatens %v, want none", threatened)
+	}
+}
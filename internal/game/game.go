@@ -2,8 +2,12 @@ package game
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"math/rand"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"go.opentelemetry.io/otel/attribute"
@@ -11,15 +15,17 @@ import (
 	"github.com/samdwyer/dungeonband/internal/combat"
 	"github.com/samdwyer/dungeonband/internal/entity"
 	"github.com/samdwyer/dungeonband/internal/gamedata"
+	"github.com/samdwyer/dungeonband/internal/seed"
 	"github.com/samdwyer/dungeonband/internal/telemetry"
 	"github.com/samdwyer/dungeonband/internal/ui"
 	"github.com/samdwyer/dungeonband/internal/world"
+	combatapi "github.com/samdwyer/dungeonband/pkg/combat"
 )
 
 // Game holds the entire game state.
 type Game struct {
-	screen          *ui.Screen
-	renderer        *ui.Renderer
+	display         Display
+	audio           AudioSink // Sound cues, nil when silent
 	dungeon         *world.Dungeon
 	party           *entity.Party
 	enemies         []*entity.Enemy
@@ -27,75 +33,251 @@ type Game struct {
 	classRegistry   *gamedata.ClassRegistry
 	abilityRegistry *gamedata.AbilityRegistry
 	effectResolver  *combat.EffectResolver
+	prefabs         []world.Prefab // Hand-authored rooms for generated floors
 	state           State
 	running         bool
+	suspended       bool               // True while the terminal is handed back to the shell
+	paused          bool               // Pause menu is open
+	confirmAbandon  bool               // "Save before abandoning?" prompt is open
+	abandoned       bool               // Run was abandoned from the pause menu
+	gameOver        bool               // Party was defeated; the game-over prompt is open
+	newRun          bool               // Player asked for a new run from the game-over prompt
+	practice        bool               // A lost fight was retried, so the run is practice
+	encounterStart  *encounterSnapshot // State the current or last fight started from
+	lastTurn        *turnSnapshot      // The fight before the last member's action, for rewinding it
+	rewinds         int                // Turns the party can still rewind this fight
+	cfg             Config             // Configuration the run was created with, for retries
+	autosavePath    string             // Where the run is saved at floor changes and victories ("" = off)
 	rng             *rand.Rand
+	rngSource       *countingSource     // Source behind rng, counting draws for StateHash
+	dungeonSource   *countingSource     // Source behind the floor's dungeon stream, counting draws for saves
+	dice            *combat.Dice        // Labelled combat rolls, reseeded for each encounter
+	seeds           *seed.Log           // Sub-seeds drawn from the master seed so far
+	searches        int                 // Corpses searched this run, indexing the loot stream
+	seedsOpen       bool                // Seeds debug panel is open
+	debugOverlay    bool                // Debug overlay is shown
+	frameTime       time.Duration       // How long the last frame took to draw
+	clock           runClock            // Play time, stopped in menus
+	sleep           func(time.Duration) // Waits between enemy actions; nil uses time.Sleep
+	turns           int                 // Turns taken this run: steps and combat rounds
 	seed            int64
+	floor           int         // Current dungeon depth (1-based)
+	confirmDescend  bool        // Ask before taking the stairs
+	pendingDescend  bool        // "Descend? (y/n)" prompt is open
+	casting         *castMenu   // Explore cast menu, nil when closed
+	shrine          *shrineMenu // Shrine menu, nil when closed
+	items           *itemMenu   // Item menu, nil when closed
+	message         string      // Explore-mode message shown below the map
+	start           StartMode   // Which room each floor starts in
+	startRoomIndex  int         // Configured room for StartRoomIndex
+	startRoom       int         // Room the party started this floor in
+	entry           position    // Where the party started a floor without rooms
+
+	// Adaptive difficulty
+	adaptive        bool                    // Tune the spawn budget from post-combat party HP
+	difficulty      *gamedata.DifficultyDef // Starting supplies and statuses (nil: default start)
+	focusKills      bool                    // Preselect an enemy the ability can kill
+	difficultyShift int                     // Added to each room's rolled enemy count
+
+	// Demo mode
+	demo *autopilot // Plays the party when set
+
+	// Tutorial state
+	tutorial         bool                        // Playing the hand-authored tutorial floor
+	tutorialComplete bool                        // Party reached the tutorial's stairs
+	triggers         []*trigger                  // Scripted one-shot triggers on this floor
+	instructions     []*trigger                  // Instruction panels waiting to be dismissed
+	exploreSteps     int                         // Steps walked since statuses last ticked
+	visitedRooms     map[int]bool                // Rooms entered, or given up on, this floor
+	visitedFloor     int                         // Floor visitedRooms belongs to
+	travel           *travelPlan                 // Queued path being walked
+	resumable        *travelPlan                 // Interrupted trip 'r' picks back up
+	travelSeq        int                         // Numbers travel plans, to match their ticks
+	scoutControl     bool                        // Movement keys move the party's scout
+	waitingParty     *entity.Party               // Rest of the party while the scout fights alone
+	shaken           map[*entity.Enemy]int       // Fled-from enemies and their explore turns left
+	investigations   map[groupKey]*investigation // Groups heading for a noise, by enemyGroup
+
+	// Flavor
+	flavor      *gamedata.FlavorFile // Ambient messages and events (nil: none)
+	hideFlavor  bool                 // Roll flavor but don't show it
+	flavorSteps int                  // Steps since the last flavor message
+	flavorFired map[string]bool      // One-shot flavor events already seen this run
 
 	// Combat state
-	combatEnemies     []*entity.Enemy // Enemies in the current combat encounter
-	activeMemberIndex int             // Index of the party member whose turn it is
-	combatState       *CombatState    // Full combat state for turn-based combat
+	combatEnemies     []*entity.Enemy      // Enemies in the current combat encounter
+	activeMemberIndex int                  // Index of the party member whose turn it is
+	combatState       *CombatState         // Full combat state for turn-based combat
+	encounters        int                  // Encounters started this run, for encounter IDs
+	stats             *statsCollector      // Per-member combat and lifetime stats
+	recording         *combatRecording     // Event log of the current encounter
+	lastFight         *combatRecording     // Event log of the last finished encounter
+	replay            *replayViewer        // Open replay of lastFight
+	sheet             bool                 // Character sheet is open
+	rooms             *roomMenu            // Open fast-travel menu
+	themes            *gamedata.ThemesFile // Room themes; nil if they failed to load
+	resumedUses       map[string]int       // Party's ability uses in the resumed save
 }
 
-// New creates a new game instance with the given configuration.
-func New(cfg Config) (*Game, error) {
-	screen, err := ui.NewScreen()
+// New creates a new game instance with the given configuration, drawing to
+// an existing display. The display is owned by the caller and outlives the
+// game, so several runs can share one terminal session.
+func New(cfg Config, display Display) (*Game, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	difficulty, err := loadDifficulty(cfg.Difficulty)
 	if err != nil {
 		return nil, err
 	}
 
 	// Load enemy registry from embedded data
-	enemyRegistry, err := gamedata.LoadEnemyRegistry()
+	enemyRegistry, err := combatapi.DefaultEnemies()
 	if err != nil {
 		log.Printf("Warning: failed to load enemy registry: %v (using legacy spawning)", err)
 	}
 
 	// Load class registry
-	classRegistry, err := gamedata.LoadClassRegistry()
+	classRegistry, err := combatapi.DefaultClasses()
 	if err != nil {
 		log.Printf("Warning: failed to load class registry: %v (using default stats)", err)
 	}
 
-	// Load ability registry
-	abilityRegistry, err := gamedata.LoadAbilityRegistry()
+	// Load ability registry. Without ability data the game runs on an
+	// empty registry, so lookups come back empty instead of panicking
+	abilityRegistry, err := combatapi.DefaultAbilities()
 	if err != nil {
-		log.Printf("Warning: failed to load ability registry: %v", err)
+		log.Printf("Warning: failed to load ability registry: %v (combat falls back to a basic attack)", err)
+		abilityRegistry = gamedata.NewAbilityRegistry(nil)
+	}
+	if cfg.DataDir != "" {
+		r, err := loadRegistries(cfg.DataDir)
+		if err != nil {
+			return nil, err
+		}
+		abilityRegistry, classRegistry, enemyRegistry = r.abilities, r.classes, r.enemies
 	}
+	effectResolver := combatapi.NewEffectResolver(abilityRegistry)
+	formula := cfg.DamageFormula
+	if formula == "" && difficulty != nil {
+		formula = difficulty.DamageFormula
+	}
+	if f, err := combat.FormulaFor(formula); err == nil {
+		effectResolver.SetDamageFormula(f)
+	}
+
+	rngSource := newCountingSource(cfg.Seed)
+	rng := rand.New(rngSource)
+
+	display.SetEnemyVisibility(cfg.EnemyVisibility)
+	display.SetShowInitials(cfg.ShowInitials)
 
-	var effectResolver *combat.EffectResolver
-	if abilityRegistry != nil {
-		effectResolver = combat.NewEffectResolver(abilityRegistry)
+	sink := cfg.Audio
+	if _, headless := display.(NullDisplay); headless {
+		sink = nil
+	}
+
+	var demo *autopilot
+	if cfg.Demo {
+		demo = &autopilot{delay: demoStepDelay}
 	}
 
 	return &Game{
-		screen:          screen,
-		renderer:        ui.NewRenderer(screen),
+		cfg:             cfg,
+		demo:            demo,
+		display:         display,
+		audio:           sink,
 		enemyRegistry:   enemyRegistry,
 		classRegistry:   classRegistry,
 		abilityRegistry: abilityRegistry,
 		effectResolver:  effectResolver,
+		prefabs:         loadPrefabs(),
+		themes:          loadThemes(abilityRegistry),
+		flavor:          loadFlavor(),
+		hideFlavor:      cfg.HideFlavor,
+		debugOverlay:    cfg.Debug,
 		state:           StateExplore,
 		running:         true,
-		rng:             rand.New(rand.NewSource(cfg.Seed)),
+		rng:             rng,
+		rngSource:       rngSource,
+		dice:            combat.NewDice(rng, cfg.AuditRolls),
 		seed:            cfg.Seed,
+		seeds:           seed.NewLog(cfg.Seed),
+		floor:           1,
+		confirmDescend:  !cfg.SkipDescendConfirm,
+		tutorial:        cfg.Tutorial,
+		adaptive:        cfg.AdaptiveDifficulty,
+		difficulty:      difficulty,
+		focusKills:      cfg.FocusKills,
+		start:           cfg.Start,
+		startRoomIndex:  cfg.StartRoom,
+		stats:           newStatsCollector(),
+		autosavePath:    cfg.AutosavePath,
 	}, nil
 }
 
 // Run executes the main game loop.
 func (g *Game) Run(ctx context.Context) error {
+	g.setup(ctx)
+
+	// Demo mode is driven by ticks instead of key presses
+	if g.demo != nil {
+		g.scheduleDemoTick()
+	}
+
+	// Main game loop
+	for g.running {
+		g.render()
+
+		// Handle input (blocking)
+		g.handleInput(ctx)
+	}
+
+	return nil
+}
+
+// setup generates the first floor, creates the party and spawns enemies.
+func (g *Game) setup(ctx context.Context) {
 	tracer := telemetry.Tracer("game")
 
 	// Initialize game (traced)
 	ctx, initSpan := tracer.Start(ctx, "game.init")
 
+	if g.cfg.Resume != nil {
+		g.restore(g.cfg.Resume)
+		initSpan.SetAttributes(
+			attribute.Bool("resumed", true),
+			attribute.Int("floor", g.floor),
+			attribute.Int("enemy_count", len(g.enemies)),
+			attribute.Int64("seed", g.seed),
+		)
+		initSpan.End()
+		g.updateTitle()
+		return
+	}
+
+	if g.tutorial {
+		err := g.setupTutorial()
+		if err == nil {
+			initSpan.SetAttributes(
+				attribute.Bool("tutorial", true),
+				attribute.Int("enemy_count", len(g.enemies)),
+			)
+			initSpan.End()
+			g.updateTitle()
+			return
+		}
+		log.Printf("Warning: failed to load tutorial: %v (starting a normal run)", err)
+		g.tutorial = false
+	}
+
 	// Generate dungeon with the game's RNG for reproducibility
-	g.dungeon = world.NewDungeon(world.DefaultWidth, world.DefaultHeight, g.rng)
-	g.dungeon.Generate(ctx)
+	g.generateDungeon(ctx)
 
-	// Place party in first room's center
+	// Place party in the starting room's center
 	if len(g.dungeon.Rooms) > 0 {
-		startX, startY := g.dungeon.Rooms[0].Center()
+		g.startRoom = g.chooseStartRoom()
+		startX, startY := g.dungeon.Rooms[g.startRoom].Center()
 
 		// Create party with class data if available
 		if g.classRegistry != nil {
@@ -104,11 +286,14 @@ func (g *Game) Run(ctx context.Context) error {
 			g.party = entity.NewParty(startX, startY)
 		}
 
-		// Spawn enemies in rooms (skip room 0 - starting room)
+		// Spawn enemies in rooms, leaving the starting room empty
+		g.dungeon.ReserveAround(startX, startY)
 		g.spawnEnemies()
+		g.placeShrine()
 
 		initSpan.SetAttributes(
 			attribute.Int("dungeon.rooms", len(g.dungeon.Rooms)),
+			attribute.Int("party.start_room", g.startRoom),
 			attribute.Int("party.start_x", startX),
 			attribute.Int("party.start_y", startY),
 			attribute.Int("enemy_count", len(g.enemies)),
@@ -129,41 +314,236 @@ func (g *Game) Run(ctx context.Context) error {
 		)
 	}
 
+	g.applyPartyClasses()
+	g.applyDifficultyStart()
+	if g.difficulty != nil {
+		initSpan.SetAttributes(attribute.String("difficulty", g.difficulty.ID))
+	}
 	initSpan.End()
 
-	// Main game loop
-	for g.running {
-		// Render current state
-		if g.state == StateCombat {
-			combatInfo := g.buildCombatInfo()
-			g.renderer.RenderWithCombat(g.dungeon, g.party, g.enemies, ui.GameState(g.state), g.seed, combatInfo)
-		} else {
-			g.renderer.Render(g.dungeon, g.party, g.enemies, ui.GameState(g.state), g.seed)
+	g.updateTitle()
+}
+
+// render draws the current frame, including any open prompt or message.
+// A display too small for the floor shows a request to enlarge it instead.
+func (g *Game) render() {
+	start := time.Now()
+	defer func() { g.frameTime = time.Since(start) }()
+	defer g.renderDebug()
+
+	if g.displayTooSmall() {
+		g.display.RenderTooSmall(g.minDisplaySize())
+		return
+	}
+	if g.state == StateCombat {
+		g.display.RenderCombat(g.dungeon, g.party, g.enemies, g.seed, g.buildCombatInfo())
+		if g.paused {
+			g.display.ShowOverlay(Overlay{Kind: OverlayPrompt, Text: g.pauseMenuPrompt()})
+		} else if g.seedsOpen {
+			g.display.ShowOverlay(g.seedsOverlay())
+		} else if g.items != nil {
+			g.showChoice(g.items.choice)
 		}
+		g.renderInstruction()
+		return
+	}
 
-		// Handle input (blocking)
-		g.handleInput(ctx)
+	g.display.RenderExplore(g.dungeon, g.party, g.enemies, g.seed)
+	g.renderRunStatus()
+	if g.paused {
+		g.display.ShowOverlay(Overlay{Kind: OverlayPrompt, Text: g.pauseMenuPrompt()})
+	} else if g.confirmAbandon {
+		g.display.ShowOverlay(Overlay{Kind: OverlayPrompt, Text: abandonPrompt})
+	} else if g.seedsOpen {
+		g.display.ShowOverlay(g.seedsOverlay())
+	} else if g.gameOver {
+		g.display.ShowOverlay(Overlay{Kind: OverlayPrompt, Text: g.gameOverPrompt()})
+	} else if g.replay != nil {
+		g.display.ShowOverlay(g.replayOverlay())
+	} else if g.sheet {
+		g.display.ShowOverlay(g.characterSheetOverlay())
+	} else if g.pendingDescend {
+		g.display.ShowOverlay(Overlay{Kind: OverlayPrompt, Text: descendPrompt})
+	} else if g.shrine != nil {
+		g.showChoice(g.shrine.choice)
+	} else if g.items != nil {
+		g.showChoice(g.items.choice)
+	} else if g.rooms != nil {
+		g.showChoice(g.rooms.choice)
+	} else if g.casting != nil {
+		g.display.ShowOverlay(Overlay{Kind: OverlayPrompt, Text: g.castPrompt()})
+	} else if g.message != "" {
+		g.display.ShowOverlay(Overlay{Kind: OverlayMessage, Text: g.message})
+	}
+	g.renderInstruction()
+}
+
+// showChoice draws a menu over the current view.
+func (g *Game) showChoice(choice *ui.Choice) {
+	g.display.ShowOverlay(Overlay{Kind: OverlayChoice, Title: choice.Title, Text: choice.Summary(), Choice: choice})
+}
+
+// renderInstruction draws the open tutorial instruction panel, if any.
+func (g *Game) renderInstruction() {
+	if len(g.instructions) == 0 {
+		return
 	}
+	t := g.instructions[0]
+	g.display.ShowOverlay(Overlay{Kind: OverlayInstruction, Title: t.title, Text: t.text})
+}
 
-	// Cleanup
-	g.screen.Close()
+// Screenshot sets up a new game on the display and draws its first frame
+// without entering the input loop.
+func Screenshot(ctx context.Context, cfg Config, display Display) error {
+	g, err := New(cfg, display)
+	if err != nil {
+		return err
+	}
+	g.setup(ctx)
+	g.render()
 	return nil
 }
 
 // handleInput processes a single input event.
 func (g *Game) handleInput(ctx context.Context) {
-	ev := g.screen.PollEvent()
+	ev := g.display.PollEvent()
+
+	// The game is paused while the display is too small to show it
+	paused := g.displayTooSmall()
+	defer g.syncClock()
 
 	switch ev := ev.(type) {
 	case *tcell.EventKey:
-		g.handleKeyEvent(ctx, ev)
+		if !paused {
+			g.clock.start()
+			g.handleKeyEvent(ctx, ev)
+		} else if ev.Key() == tcell.KeyCtrlC {
+			g.running = false
+		}
 	case *tcell.EventResize:
-		g.screen.Sync()
+		g.display.Sync()
+	case *tcell.EventInterrupt:
+		switch data := ev.Data().(type) {
+		case shutdownRequest:
+			g.running = false
+		case demoTick:
+			if g.demo != nil && g.running {
+				if paused {
+					g.scheduleDemoTick()
+				} else {
+					g.demoStep(ctx)
+				}
+			}
+		case bellRequest:
+			_ = g.display.Beep()
+		case travelTick:
+			if g.travel != nil && g.travel.seq == data.seq && g.running {
+				if paused {
+					g.scheduleTravelTick()
+				} else {
+					g.travelStep(ctx)
+				}
+			}
+		}
+	case *tcell.EventMouse:
+		g.handleMouse(ev)
+	case nil:
+		// Display was closed
+		g.running = false
 	}
 }
 
 // handleKeyEvent processes keyboard input.
 func (g *Game) handleKeyEvent(ctx context.Context, ev *tcell.EventKey) {
+	// The autopilot has the controls in demo mode
+	if g.demo != nil {
+		g.handleDemoKey(ev)
+		return
+	}
+
+	// Any key stops queued movement
+	if g.travel != nil {
+		g.interruptTravel(ctx, "Travel stopped.")
+		return
+	}
+
+	// Any key dismisses an open instruction panel
+	if len(g.instructions) > 0 {
+		g.dismissInstruction()
+		return
+	}
+
+	// The game-over prompt captures the next key press
+	if g.gameOver {
+		g.handleGameOverKey(ctx, ev)
+		return
+	}
+
+	// The replay captures keys until it closes
+	if g.replay != nil {
+		g.handleReplayKey(ev)
+		return
+	}
+
+	// The seeds panel exports on 'e' and closes on any key
+	if g.seedsOpen {
+		g.handleSeedsKey(ctx, ev)
+		return
+	}
+
+	// Any key closes the character sheet
+	if g.sheet {
+		g.sheet = false
+		return
+	}
+
+	// The pause menu captures the next key press
+	if g.paused {
+		g.handlePauseMenu(ctx, ev)
+		return
+	}
+	if g.confirmAbandon {
+		g.handleAbandonPrompt(ctx, ev)
+		return
+	}
+
+	// The descend prompt captures the next key press
+	if g.pendingDescend {
+		g.handleDescendPrompt(ctx, ev)
+		return
+	}
+
+	// The shrine menu captures keys until it closes
+	if g.shrine != nil {
+		g.handleShrineKey(ctx, ev)
+		return
+	}
+
+	// The item menu captures keys until it closes
+	if g.items != nil {
+		g.handleItemKey(ctx, ev)
+		return
+	}
+
+	// The fast-travel menu captures keys until it closes
+	if g.rooms != nil {
+		g.handleRoomKey(ctx, ev)
+		return
+	}
+
+	// The cast menu captures the next key press
+	if g.casting != nil {
+		g.handleCastKey(ctx, ev)
+		return
+	}
+
+	// Target selection captures navigation and confirm/cancel keys
+	if g.state == StateCombat && g.combatState != nil && g.combatState.Phase == PhaseSelectTarget {
+		if g.handleTargetSelectionKey(ctx, ev) {
+			return
+		}
+	}
+
 	switch ev.Key() {
 	case tcell.KeyEscape:
 		if g.state == StateCombat {
@@ -171,8 +551,7 @@ func (g *Game) handleKeyEvent(ctx context.Context, ev *tcell.EventKey) {
 			if g.combatState != nil && (g.combatState.Phase == PhaseVictory || g.combatState.Phase == PhaseDefeat) {
 				g.handleCombatEnd(ctx)
 			} else {
-				// Exit combat mode (flee)
-				g.transitionState(ctx, StateExplore, "manual")
+				g.fleeCombat(ctx)
 			}
 		} else {
 			// Quit game from explore mode
@@ -182,26 +561,53 @@ func (g *Game) handleKeyEvent(ctx context.Context, ev *tcell.EventKey) {
 	case tcell.KeyCtrlC:
 		g.running = false
 
+	case tcell.KeyCtrlZ:
+		g.suspend(ctx)
+
+	case tcell.KeyCtrlR:
+		if g.cfg.Debug {
+			g.reloadData(ctx)
+		}
+
+	case tcell.KeyF3:
+		g.debugOverlay = !g.debugOverlay
+
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if g.state == StateCombat {
+			g.undoSelection()
+		}
+
+	case tcell.KeyTab:
+		if g.state == StateCombat {
+			g.flipAbilityPage()
+		}
+
 	case tcell.KeyUp:
 		if g.state == StateExplore {
-			g.tryMove(ctx, 0, -1)
+			g.moveControlled(ctx, 0, -1)
 		}
 	case tcell.KeyDown:
 		if g.state == StateExplore {
-			g.tryMove(ctx, 0, 1)
+			g.moveControlled(ctx, 0, 1)
 		}
 	case tcell.KeyLeft:
 		if g.state == StateExplore {
-			g.tryMove(ctx, -1, 0)
+			g.moveControlled(ctx, -1, 0)
 		}
 	case tcell.KeyRight:
 		if g.state == StateExplore {
-			g.tryMove(ctx, 1, 0)
+			g.moveControlled(ctx, 1, 0)
 		}
 
 	case tcell.KeyRune:
 		r := ev.Rune()
 
+		// A misclick can be taken back, even one that lost the fight
+		if (r == 'u' || r == 'U') && g.canRewind() {
+			g.rewindTurn(ctx)
+			return
+		}
+
 		// In combat victory/defeat, any key continues
 		if g.state == StateCombat && g.combatState != nil {
 			if g.combatState.Phase == PhaseVictory || g.combatState.Phase == PhaseDefeat {
@@ -210,34 +616,83 @@ func (g *Game) handleKeyEvent(ctx context.Context, ev *tcell.EventKey) {
 			}
 		}
 
-		// Handle number keys for ability selection in combat
+		// Handle number keys for ability selection in combat, with '0'
+		// flipping to the next page of abilities
 		if g.state == StateCombat && r >= '1' && r <= '9' {
-			g.handleCombatAbilitySelection(ctx, int(r-'1'))
+			g.handleCombatAbilitySelection(ctx, g.hotbarIndex(int(r-'1')))
+			return
+		}
+		if g.state == StateCombat && r == '0' {
+			g.flipAbilityPage()
+			return
+		}
+		if g.state == StateCombat && r == 'H' {
+			g.quickHeal(ctx)
 			return
 		}
 
 		switch r {
 		case 'q', 'Q':
 			g.running = false
+		case 'p', 'P':
+			g.paused = true
 		case 'c', 'C':
 			if g.state == StateExplore {
 				g.transitionState(ctx, StateCombat, "manual")
 			}
+		case 'z', 'Z':
+			if g.state == StateExplore {
+				g.openCastMenu()
+			}
+		case 'i', 'I':
+			g.openItemMenu()
+		case 'g', 'G':
+			if g.state == StateExplore {
+				g.searchCorpse(ctx)
+			}
+		case 'T':
+			if g.state == StateExplore {
+				g.travelToStairs()
+			}
+		case 'x', 'X':
+			if g.state == StateExplore {
+				g.autoExplore()
+			}
+		case 'r', 'R':
+			if g.state == StateExplore {
+				g.resumeTravel()
+			}
+		case 'v', 'V':
+			if g.state == StateExplore {
+				g.openReplay(ctx)
+			}
+		case 's', 'S':
+			if g.state == StateExplore {
+				g.toggleScout(ctx)
+			}
+		case 'm', 'M':
+			if g.state == StateExplore {
+				g.sheet = true
+			}
+		case 'f', 'F':
+			if g.state == StateExplore {
+				g.openRoomMenu()
+			}
 		case 'h':
 			if g.state == StateExplore {
-				g.tryMove(ctx, -1, 0)
+				g.moveControlled(ctx, -1, 0)
 			}
 		case 'j':
 			if g.state == StateExplore {
-				g.tryMove(ctx, 0, 1)
+				g.moveControlled(ctx, 0, 1)
 			}
 		case 'k':
 			if g.state == StateExplore {
-				g.tryMove(ctx, 0, -1)
+				g.moveControlled(ctx, 0, -1)
 			}
 		case 'l':
 			if g.state == StateExplore {
-				g.tryMove(ctx, 1, 0)
+				g.moveControlled(ctx, 1, 0)
 			}
 		}
 	}
@@ -251,45 +706,106 @@ func (g *Game) handleCombatAbilitySelection(ctx context.Context, abilityIndex in
 	}
 
 	activeMember := g.getActiveMember()
-	if activeMember == nil || g.abilityRegistry == nil {
+	if activeMember == nil {
 		return
 	}
 
-	abilityIDs := activeMember.GetAbilityIDs()
-	if abilityIndex >= len(abilityIDs) {
+	abilities := g.combatAbilities(activeMember)
+	if abilityIndex >= len(abilities) {
 		return // Invalid selection
 	}
+	ability := abilities[abilityIndex]
+
+	// Silence blocks MP abilities
+	if combat.BlockedBySilence(ability, activeMember) {
+		g.combatState.LastMessage = activeMember.GetName() + " is silenced!"
+		return
+	}
 
-	ability := g.abilityRegistry.GetByID(abilityIDs[abilityIndex])
-	if ability == nil {
+	if g.combatState.onCooldown(activeMember, ability) {
+		g.combatState.LastMessage = ability.Name + " isn't ready yet!"
 		return
 	}
 
-	// Check if can use (enough MP)
-	if activeMember.GetMP() < ability.MPCost {
+	// Check if can use (enough MP, less any planned for this round)
+	if g.overPlannedMP(activeMember, ability) {
 		g.combatState.LastMessage = "Not enough MP!"
 		return
 	}
 
-	// Select target based on ability type
-	var target combat.Combatant
-	if ability.IsOffensive() {
-		// Target first alive enemy
-		target = g.combatState.GetFirstAliveEnemy()
-	} else {
-		// Target self for defensive/healing abilities
-		target = activeMember
+	switch ability.TargetType {
+	case gamedata.TargetSelf:
+		if !g.hasValidTarget(ability, activeMember) {
+			g.combatState.LastMessage = "No valid target!"
+			return
+		}
+		g.performPlayerAction(ctx, ability, activeMember, activeMember)
+
+	case gamedata.TargetSingleEnemy, gamedata.TargetSingleAlly:
+		// Leave the turn unspent when there is nobody worth aiming at
+		if !g.hasValidTarget(ability, g.defaultTarget(ability, activeMember)) {
+			g.combatState.LastMessage = "No valid target!"
+			return
+		}
+		g.beginTargeting(ability)
+
+	case gamedata.TargetAllEnemies, gamedata.TargetAllAllies:
+		targets := g.groupTargets(ability)
+		if len(targets) == 0 || !g.hasValidTarget(ability, targets[0]) {
+			g.combatState.LastMessage = "No valid target!"
+			return
+		}
+		g.performPlayerAction(ctx, ability, activeMember, targets...)
+
+	default:
+		// Validation rejects these at load time; guard against bad overrides anyway
+		g.combatState.LastMessage = fmt.Sprintf("Can't use %s: unknown targetType %q", ability.Name, ability.TargetType)
 	}
+}
 
-	if target == nil {
-		return
+// defaultTarget returns the combatant a single-target ability would be aimed
+// at first: the first reachable enemy for offensive abilities, otherwise the caster.
+func (g *Game) defaultTarget(ability *gamedata.AbilityDef, activeMember *entity.Member) combat.Combatant {
+	if ability.IsOffensive() {
+		// Avoid wrapping a nil *Enemy in the interface
+		if enemy := g.combatState.GetFirstReachableEnemy(ability); enemy != nil {
+			return enemy
+		}
+		return nil
 	}
+	return activeMember
+}
 
-	// Execute the turn
-	g.executeCombatTurn(ctx, ability, activeMember, target)
+// groupTargets returns every combatant an all_enemies or all_allies ability
+// affects: reachable living enemies, or living party members.
+func (g *Game) groupTargets(ability *gamedata.AbilityDef) []combat.Combatant {
+	var targets []combat.Combatant
+	if ability.IsOffensive() {
+		for _, e := range g.combatState.Enemies {
+			if e.IsAlive() && g.combatState.CanReach(ability, e) {
+				targets = append(targets, e)
+			}
+		}
+		return targets
+	}
+	for _, m := range g.party.Members {
+		if m.IsAlive() {
+			targets = append(targets, m)
+		}
+	}
+	return targets
+}
 
-	// Check for combat end (victory)
-	if g.checkCombatEnd() {
+// performPlayerAction resolves the active member's ability against its
+// target(s) and advances combat. With Config.PlanActions set it only
+// queues the action until the whole party has chosen.
+func (g *Game) performPlayerAction(ctx context.Context, ability *gamedata.AbilityDef, activeMember *entity.Member, targets ...combat.Combatant) {
+	g.captureTurn()
+	if g.cfg.PlanActions {
+		g.planPlayerAction(ctx, ability, activeMember, targets)
+		return
+	}
+	if g.resolvePlayerAction(ctx, ability, activeMember, targets) {
 		return
 	}
 
@@ -302,6 +818,43 @@ func (g *Game) handleCombatAbilitySelection(ctx context.Context, abilityIndex in
 	}
 }
 
+// resolvePlayerAction resolves a member's ability against its target(s).
+// Returns true if that ended the fight.
+func (g *Game) resolvePlayerAction(ctx context.Context, ability *gamedata.AbilityDef, member *entity.Member, targets []combat.Combatant) bool {
+	if len(targets) == 1 {
+		g.executeCombatTurn(ctx, ability, member, targets[0])
+	} else {
+		g.executeGroupTurn(ctx, ability, member, targets)
+	}
+
+	// The back row steps up once the front row has fallen
+	if promoted := g.combatState.PromoteBackRow(); len(promoted) > 0 {
+		g.combatState.LastMessage += " The back row steps forward!"
+	}
+
+	return g.checkCombatEnd()
+}
+
+// hasValidTarget reports whether an ability would have any effect on its target.
+// Heals are only valid when someone they would reach is missing HP.
+func (g *Game) hasValidTarget(ability *gamedata.AbilityDef, target combat.Combatant) bool {
+	if target == nil || !target.IsAlive() {
+		return false
+	}
+	if ability.EffectType != gamedata.EffectHeal {
+		return true
+	}
+	if ability.TargetType == gamedata.TargetAllAllies || ability.TargetType == gamedata.TargetSingleAlly {
+		for _, m := range g.party.Members {
+			if m.IsAlive() && m.GetHP() < m.GetMaxHP() {
+				return true
+			}
+		}
+		return false
+	}
+	return target.GetHP() < target.GetMaxHP()
+}
+
 // tryMove attempts to move the party by the given delta.
 func (g *Game) tryMove(ctx context.Context, dx, dy int) {
 	newX := g.party.X + dx
@@ -309,14 +862,64 @@ func (g *Game) tryMove(ctx context.Context, dx, dy int) {
 
 	if g.dungeon.IsPassable(newX, newY) {
 		g.party.Move(dx, dy)
+		g.message = ""
+		g.walkTurn(g.party.X, g.party.Y)
+		g.rejoinScout()
+		entered := g.noteVisitedRooms()
+		g.tickExploreStatuses()
+		if g.party.IsDefeated() {
+			// Falling outside a fight leaves no encounter to retry
+			g.encounterStart = nil
+			g.endRun(ctx)
+			return
+		}
+		g.fireTileTriggers()
+		g.checkShrine()
+		g.noteCorpse()
+		g.emitFlavor(entered)
+		if g.dungeon.IsStairs(g.party.X, g.party.Y) {
+			if g.tutorial {
+				g.completeTutorial(ctx)
+				return
+			}
+			g.handleStairs(ctx)
+		}
 	}
 }
 
-// Close cleans up game resources.
-func (g *Game) Close() {
-	if g.screen != nil {
-		g.screen.Close()
+// exploreStepsPerTurn is how many steps the party walks per status tick
+// while exploring.
+const exploreStepsPerTurn = 3
+
+// tickExploreStatuses advances every living member's status effects by one
+// turn every exploreStepsPerTurn steps, so a poison from a fight keeps
+// ticking and a pre-cast buff wears off while the party explores.
+func (g *Game) tickExploreStatuses() {
+	g.message = ""
+	g.exploreSteps++
+	if g.exploreSteps < exploreStepsPerTurn {
+		return
+	}
+	g.exploreSteps = 0
+	g.calmShaken()
+
+	var notes []string
+	for _, m := range g.party.Members {
+		if !m.IsAlive() {
+			continue
+		}
+		for _, tick := range m.TickStatusEffects() {
+			switch {
+			case tick.Type == gamedata.StatusPoison && !m.IsAlive():
+				notes = append(notes, m.GetName()+" succumbs to poison!")
+			case tick.Type == gamedata.StatusPoison && tick.Amount > 0:
+				notes = append(notes, m.GetName()+" takes "+itoa(tick.Amount)+" poison damage.")
+			case tick.Type == gamedata.StatusRegen && tick.Amount > 0:
+				notes = append(notes, m.GetName()+" regenerates "+itoa(tick.Amount)+" HP.")
+			}
+		}
 	}
+	g.message = strings.Join(notes, " ")
 }
 
 // transitionState changes the game state and records telemetry.
@@ -342,33 +945,65 @@ func (g *Game) transitionState(ctx context.Context, newState State, trigger stri
 	}
 
 	g.state = newState
+	g.updateTitle()
 }
 
 // enterCombat sets up combat state.
 func (g *Game) enterCombat(ctx context.Context) {
-	// Find enemies in the same room as the party
-	partyRoomIndex := g.dungeon.RoomIndexAt(g.party.X, g.party.Y)
+	// Hardcore runs can't retry a lost fight
+	g.encounterStart = nil
+	if !g.cfg.Hardcore {
+		g.encounterStart = g.captureEncounter()
+	}
+	g.splitForCombat()
+
+	// Find enemies the party notices, including any lurking in a corridor
+	// off the party's room
 	g.combatEnemies = nil
 	for _, enemy := range g.enemies {
-		if enemy.RoomIndex == partyRoomIndex && enemy.IsAlive() {
+		if enemy.IsAlive() && g.notices(enemy) {
 			g.combatEnemies = append(g.combatEnemies, enemy)
 		}
 	}
 	g.activeMemberIndex = 0
+	g.placeEnemyFormation()
 
 	// Initialize full combat state with telemetry
 	g.initCombatState(ctx)
+	g.fireEvent(gamedata.TutorialEventCombatStart)
+}
+
+// notices returns true if the party can see the enemy by the configured
+// visibility rule. Revealing every enemy is only a view; the party still
+// notices just the ones in sight.
+func (g *Game) notices(e *entity.Enemy) bool {
+	rule := g.cfg.EnemyVisibility
+	if rule == world.VisibilityAll {
+		rule = world.VisibilitySight
+	}
+	return g.dungeon.Sees(rule, g.party.X, g.party.Y, e.X, e.Y)
 }
 
 // exitCombat cleans up combat state.
 func (g *Game) exitCombat() {
+	g.clearTheme()
+	g.reuniteAfterCombat()
 	g.combatEnemies = nil
 	g.activeMemberIndex = 0
+	g.lastTurn = nil
 }
 
 // getActiveMember returns the current active party member in combat.
+// CombatState.ActiveMemberIndex indexes party.Members directly.
 func (g *Game) getActiveMember() *entity.Member {
-	return g.party.GetAliveMember(g.activeMemberIndex)
+	if g.combatState == nil {
+		return nil
+	}
+	index := g.combatState.ActiveMemberIndex
+	if index < 0 || index >= len(g.party.Members) || !g.party.Members[index].IsAlive() {
+		return nil
+	}
+	return g.party.Members[index]
 }
 
 // buildCombatInfo creates the combat UI information for rendering.
@@ -377,48 +1012,117 @@ func (g *Game) buildCombatInfo() *ui.CombatInfo {
 		return nil
 	}
 
-	// Use combatState's active member index for consistency
-	activeMember := g.party.GetAliveMember(g.combatState.ActiveMemberIndex)
+	// The enemy phase shows what enemies are doing instead of abilities
+	if g.combatState.Phase == PhaseEnemyTurn {
+		return &ui.CombatInfo{
+			Phase:        ui.CombatPhase(g.combatState.Phase),
+			ActingEnemy:  g.combatState.actingEnemy(),
+			EnemyActions: g.combatState.EnemyActions,
+			Enemies:      g.combatState.Enemies,
+			Message:      g.combatState.LastMessage,
+			Threatened:   g.threatenedTiles(),
+		}
+	}
+
+	activeMember := g.getActiveMember()
 	if activeMember == nil {
 		return nil
 	}
 
 	// Build ability info list
 	var abilities []ui.AbilityInfo
-	if g.abilityRegistry != nil {
-		for _, abilityID := range activeMember.GetAbilityIDs() {
-			abilityDef := g.abilityRegistry.GetByID(abilityID)
-			if abilityDef != nil {
-				canUse := activeMember.GetMP() >= abilityDef.MPCost
-				abilities = append(abilities, ui.AbilityInfo{
-					Name:   abilityDef.Name,
-					MPCost: abilityDef.MPCost,
-					CanUse: canUse,
-				})
-			}
+	usable := g.usableBy(activeMember)
+	for _, abilityDef := range g.combatAbilities(activeMember) {
+		reason := ""
+		if combat.BlockedBySilence(abilityDef, activeMember) {
+			reason = "Silenced!"
+		} else if g.combatState.onCooldown(activeMember, abilityDef) {
+			reason = "Not ready"
 		}
+		abilities = append(abilities, ui.AbilityInfo{
+			Name:    abilityDef.Name,
+			MPCost:  combat.MPCost(abilityDef, activeMember),
+			CanUse:  slices.Contains(usable, abilityDef),
+			Reason:  reason,
+			Mastery: activeMember.Mastery(abilityDef.ID),
+		})
 	}
 
-	return &ui.CombatInfo{
+	info := &ui.CombatInfo{
+		Phase:        ui.CombatPhase(g.combatState.Phase),
 		ActiveMember: activeMember,
 		Abilities:    abilities,
+		AbilityPage:  g.combatState.AbilityPage,
 		Enemies:      g.combatState.Enemies,
 		Message:      g.combatState.LastMessage,
+		TurnOrder:    g.turnOrder(),
+		Environment:  g.combatState.environmentBanner(),
+		QuickHeal:    activeMember != nil && g.knowsHeal(activeMember),
+		Rewinds:      g.rewindsOffered(),
+		Threatened:   g.threatenedTiles(),
 	}
+	if g.abilitiesUnavailable() {
+		info.Message = abilitiesUnavailableMessage
+		if g.combatState.LastMessage != "" {
+			info.Message += " " + g.combatState.LastMessage
+		}
+	}
+
+	if g.combatState.Phase == PhaseSelectTarget && g.combatState.SelectedAbility != nil {
+		ability := g.combatState.SelectedAbility
+		info.TargetAbility = ability.Name
+		info.TargetRange = targetRange(ability)
+		if g.targetingEnemies() {
+			info.TargetEnemy = g.targetedEnemy()
+			info.UnreachableEnemies = make(map[*entity.Enemy]bool)
+			for _, e := range g.combatState.Enemies {
+				if e.IsAlive() && !g.combatState.CanReach(ability, e) {
+					info.UnreachableEnemies[e] = true
+				}
+			}
+		} else {
+			info.TargetAllies = g.allyTargetCandidates()
+			info.TargetMember = g.targetedMember()
+		}
+		if msg := g.targetError(); msg != "" {
+			info.InvalidTarget = true
+			info.Message = msg
+		}
+	}
+
+	return info
 }
 
 // spawnEnemies populates the dungeon with enemies.
-// Spawns 1-3 enemies per room, skipping room 0 (starting room).
+// Spawns 1-3 enemies per room, skipping the starting room.
 // Uses the enemy registry for weighted spawning if available.
 func (g *Game) spawnEnemies() {
-	for roomIndex := 1; roomIndex < len(g.dungeon.Rooms); roomIndex++ {
-		// 1-3 enemies per room
-		count := 1 + g.rng.Intn(3)
+	for roomIndex := range g.dungeon.Rooms {
+		if roomIndex == g.startRoom {
+			continue
+		}
+		// 1-3 enemies per room, shifted by adaptive difficulty
+		count := g.spawnBudget(1 + g.rng.Intn(3))
+
+		// Prefab rooms place enemies on their spawn markers
+		room := g.dungeon.Rooms[roomIndex]
+		var spawnOrder []int
+		if len(room.Spawns) > 0 {
+			spawnOrder = g.rng.Perm(len(room.Spawns))
+			count = min(count, len(spawnOrder))
+		}
 
 		for i := 0; i < count; i++ {
-			// Find a random position in the room
-			x, y := g.dungeon.RandomPointInRoom(roomIndex)
-			if x >= 0 && y >= 0 {
+			// Find a random open position in the room, skipping the enemy if
+			// the stairs, doorways and entry leave none
+			x, y, ok := 0, 0, false
+			if spawnOrder != nil {
+				spawn := room.Spawns[spawnOrder[i]]
+				x, y, ok = spawn.X, spawn.Y, !g.dungeon.IsReserved(spawn.X, spawn.Y)
+			} else {
+				x, y, ok = g.dungeon.OpenPointInRoom(roomIndex)
+			}
+			if ok {
 				var enemy *entity.Enemy
 
 				// Use registry if available, otherwise fall back to legacy spawning
@@ -426,6 +1130,7 @@ func (g *Game) spawnEnemies() {
 					def := g.enemyRegistry.SpawnRandom(g.rng)
 					if def != nil {
 						enemy = entity.NewEnemyFromDef(def, x, y, roomIndex)
+						enemy.Abilities = def.RollAbilities(g.rng)
 					}
 				}
 
@@ -455,9 +1160,14 @@ func (g *Game) handleCombatEnd(ctx context.Context) {
 	if g.combatState.Phase == PhaseVictory {
 		g.endCombat(ctx, "victory")
 		g.transitionState(ctx, StateExplore, "victory")
+		g.fireEvent(gamedata.TutorialEventVictory)
+		g.autosave(ctx, "victory")
 	} else if g.combatState.Phase == PhaseDefeat {
 		g.endCombat(ctx, "defeat")
-		// For now, just return to explore - could add game over screen later
 		g.transitionState(ctx, StateExplore, "defeat")
+		// A scout who falls alone leaves the rest of the party playing
+		if g.party.IsDefeated() {
+			g.endRun(ctx)
+		}
 	}
 }
@@ -0,0 +1,120 @@
+package game
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/samdwyer/dungeonband/internal/combat"
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+	"github.com/samdwyer/dungeonband/internal/ui"
+	"github.com/samdwyer/dungeonband/internal/world"
+)
+
+// newTestGame builds a game backed by a simulation screen so game logic can
+// be exercised without a real terminal.
+func newTestGame(t testing.TB) *Game {
+	t.Helper()
+	g, _ := newTestGameWithScreen(t)
+	return g
+}
+
+// newTestGameWithScreen is like newTestGame but also returns the simulation
+// screen for inspecting rendered output.
+func newTestGameWithScreen(t testing.TB) (*Game, tcell.SimulationScreen) {
+	t.Helper()
+
+	sim := tcell.NewSimulationScreen("UTF-8")
+	screen, err := ui.NewScreenFrom(sim)
+	if err != nil {
+		t.Fatalf("failed to create simulation screen: %v", err)
+	}
+	sim.SetSize(ui.MinScreenSize(world.DefaultWidth, world.DefaultHeight))
+	t.Cleanup(screen.Close)
+
+	abilityRegistry := gamedata.MustLoadAbilityRegistry()
+	classRegistry := gamedata.MustLoadClassRegistry()
+
+	seed := int64(12345)
+	g := &Game{
+		display:         NewTerminalDisplay(screen),
+		enemyRegistry:   gamedata.MustLoadEnemyRegistry(),
+		classRegistry:   classRegistry,
+		abilityRegistry: abilityRegistry,
+		effectResolver:  combat.NewEffectResolver(abilityRegistry),
+		state:           StateExplore,
+		running:         true,
+		rng:             rand.New(rand.NewSource(seed)),
+		seed:            seed,
+		floor:           1,
+		stats:           newStatsCollector(),
+	}
+
+	g.dice = combat.NewDice(g.rng, false)
+	g.dungeon = world.NewDungeon(world.DefaultWidth, world.DefaultHeight, g.rng)
+	g.dungeon.Generate(context.Background())
+	startX, startY := g.dungeon.Rooms[0].Center()
+	g.party = entity.NewPartyWithClassData(startX, startY, classRegistry)
+
+	return g, sim
+}
+
+// dungeonFromMap builds a dungeon from an ASCII layout using '#' and '.'.
+func dungeonFromMap(layout string) *world.Dungeon {
+	lines := strings.Split(strings.TrimSpace(layout), "\n")
+	d := world.NewDungeon(len(lines[0]), len(lines), nil)
+	for y, line := range lines {
+		for x, ch := range line {
+			d.Tiles[y][x] = world.Tile(ch)
+		}
+	}
+	return d
+}
+
+// cellAt returns the rune drawn at the given screen position.
+func cellAt(sim tcell.SimulationScreen, x, y int) rune {
+	cells, width, _ := sim.GetContents()
+	cell := cells[y*width+x]
+	if len(cell.Runes) == 0 {
+		return ' '
+	}
+	return cell.Runes[0]
+}
+
+// recordSpans installs a recording tracer provider for the duration of the test.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+// findSpan returns the first ended span with the given name, or nil.
+func findSpan(recorder *tracetest.SpanRecorder, name string) sdktrace.ReadOnlySpan {
+	for _, span := range recorder.Ended() {
+		if span.Name() == name {
+			return span
+		}
+	}
+	return nil
+}
+
+// spanAttr returns the value of a span attribute, or an empty value if missing.
+func spanAttr(span sdktrace.ReadOnlySpan, key string) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if string(kv.Key) == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
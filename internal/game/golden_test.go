@@ -0,0 +1,261 @@
+package game
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+)
+
+// update rewrites golden files from the current results instead of comparing.
+// Run "go test ./internal/game -run TestGoldenCombatOutcomes -update" after an
+// intentional balance change and review the diff.
+var update = flag.Bool("update", false, "rewrite golden files")
+
+// goldenSeed seeds every golden encounter, so outcomes move only when data
+// or combat code does.
+const goldenSeed = 20240601
+
+// goldenActionLimit ends a fight that goes nowhere; the golden file records
+// it as a stalemate.
+const goldenActionLimit = 500
+
+// goldenEncounter is a canonical fight against the standard party.
+type goldenEncounter struct {
+	name    string
+	enemies []string // Enemy IDs
+	// abilities overrides every enemy's ability list, for abilities no
+	// enemy in the data uses yet
+	abilities []string
+	// mp gives every enemy MP, which the data never does, so MP-costed
+	// abilities on enemy lists get used
+	mp int
+}
+
+// goldenEncounters cover a squad of each enemy type, then the mixes the
+// dungeon throws together. The party acts first and one-shots most single
+// enemies, so squads are big enough to hit back. Between the party's
+// rotation and the enemies, every ability ID is used at least once.
+var goldenEncounters = []goldenEncounter{
+	{name: "goblins", enemies: repeat("goblin", 8)},
+	{name: "orcs", enemies: repeat("orc", 5)},
+	{name: "skeletons", enemies: repeat("skeleton", 6)},
+	{name: "cultists", enemies: repeat("cultist", 6)},
+	{name: "spiders", enemies: repeat("spider", 6)},
+	{name: "training_dummies", enemies: repeat("training_dummy", 5)},
+	{name: "orc_warband", enemies: []string{"orc", "orc", "orc", "goblin", "goblin", "goblin"}},
+	{name: "bone_line", enemies: []string{"orc", "orc", "orc", "skeleton", "skeleton", "skeleton"}},
+	{name: "cult", enemies: []string{"goblin", "goblin", "goblin", "cultist", "cultist", "cultist"}},
+	// Nobody in the data has MP for power_attack, nor bites or claws
+	{name: "orc_veterans", enemies: repeat("orc", 5), mp: 9},
+	{name: "feral_goblins", enemies: repeat("goblin", 8), abilities: []string{"bite", "claw"}},
+	{name: "clawing_goblins", enemies: repeat("goblin", 6), abilities: []string{"claw"}},
+}
+
+// repeat returns n copies of an enemy ID.
+func repeat(id string, n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = id
+	}
+	return ids
+}
+
+// combatOutcome is what a golden file records about a fight.
+type combatOutcome struct {
+	Winner        string // "party", "enemies" or "stalemate"
+	Turns         int
+	PartyDamage   int // Dealt by the party, including their poison
+	EnemyDamage   int // Taken by the party
+	PartyHP       []string
+	AbilitiesUsed []string
+}
+
+// String renders the outcome one fact per line, so a changed outcome diffs
+// line by line.
+func (o combatOutcome) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "winner: %s\n", o.Winner)
+	fmt.Fprintf(&b, "turns: %d\n", o.Turns)
+	fmt.Fprintf(&b, "party_damage: %d\n", o.PartyDamage)
+	fmt.Fprintf(&b, "enemy_damage: %d\n", o.EnemyDamage)
+	for _, hp := range o.PartyHP {
+		fmt.Fprintf(&b, "party_hp: %s\n", hp)
+	}
+	fmt.Fprintf(&b, "abilities_used: %s\n", strings.Join(o.AbilitiesUsed, " "))
+	return b.String()
+}
+
+// runGoldenEncounter fights the encounter headlessly with the scripted
+// party policy and returns the outcome.
+func runGoldenEncounter(t *testing.T, enc goldenEncounter) combatOutcome {
+	t.Helper()
+	recorder := recordSpans(t)
+	g := newTestGame(t)
+	// The fight's dice come from the combat stream of the seed
+	g.seed = goldenSeed
+	g.rng = rand.New(rand.NewSource(goldenSeed))
+	g.dungeon = dungeonFromMap(`
+####################
+#..................#
+#..................#
+#..................#
+#..................#
+#..................#
+####################`)
+	g.party.X, g.party.Y = 5, 3
+
+	var enemies []*entity.Enemy
+	for i, id := range enc.enemies {
+		base := g.enemyRegistry.GetByID(id)
+		if base == nil {
+			t.Fatalf("%s: unknown enemy %q", enc.name, id)
+		}
+		def := *base
+		if enc.abilities != nil {
+			def.Abilities, def.AbilityPool = enc.abilities, nil
+		}
+		// Columns of up to five, starting just out of the party's reach
+		enemy := entity.NewEnemyFromDef(&def, 7+2*(i/5), 1+i%5, 0)
+		enemy.Abilities = def.RollAbilities(g.rng)
+		enemy.MP, enemy.MaxMP = enc.mp, enc.mp
+		enemies = append(enemies, enemy)
+	}
+	g.state = StateCombat
+	g.combatEnemies = enemies
+	g.initCombatState(context.Background())
+
+	outcome := combatOutcome{Winner: "stalemate"}
+	policy := newRotationPolicy()
+	for range goldenActionLimit {
+		if g.combatState.Phase == PhaseVictory {
+			outcome.Winner = "party"
+			break
+		}
+		if g.combatState.Phase == PhaseDefeat {
+			outcome.Winner = "enemies"
+			break
+		}
+		if !policy.act(g) {
+			t.Fatalf("%s: %s has nothing they can do", enc.name, g.getActiveMember().GetName())
+		}
+	}
+
+	outcome.Turns = g.combatState.TurnCount
+	for _, m := range g.party.Members {
+		s := g.stats.statsFor(m)
+		outcome.PartyDamage += s.DamageDealt
+		outcome.EnemyDamage += s.DamageTaken
+		outcome.PartyHP = append(outcome.PartyHP, fmt.Sprintf("%s %d/%d", m.GetName(), m.GetHP(), m.GetMaxHP()))
+	}
+	used := make(map[string]bool)
+	for _, span := range recorder.Ended() {
+		if span.Name() == "combat.turn" {
+			used[spanAttr(span, "ability").AsString()] = true
+		}
+	}
+	for id := range used {
+		outcome.AbilitiesUsed = append(outcome.AbilitiesUsed, id)
+	}
+	sort.Strings(outcome.AbilitiesUsed)
+	return outcome
+}
+
+// rotationPolicy plays the party by having each member cycle through their
+// ability list in order, skipping any ability that would not spend the turn
+// (no MP, no valid target). Dumb but fixed, and it exercises every ability
+// the party knows.
+type rotationPolicy struct {
+	next map[*entity.Member]int // Index of each member's next ability
+}
+
+func newRotationPolicy() *rotationPolicy {
+	return &rotationPolicy{next: make(map[*entity.Member]int)}
+}
+
+// act takes the active member's turn. Returns false if no ability would
+// spend it.
+func (p *rotationPolicy) act(g *Game) bool {
+	member := g.getActiveMember()
+	abilities := g.combatAbilities(member)
+	for i := range abilities {
+		index := (p.next[member] + i) % len(abilities)
+		if g.tryCombatAbility(context.Background(), index) {
+			p.next[member] = index + 1
+			return true
+		}
+	}
+	return false
+}
+
+func TestGoldenCombatOutcomes(t *testing.T) {
+	used := make(map[string]bool)
+	ran := 0
+	for _, enc := range goldenEncounters {
+		t.Run(enc.name, func(t *testing.T) {
+			ran++
+			outcome := runGoldenEncounter(t, enc)
+			for _, id := range outcome.AbilitiesUsed {
+				used[id] = true
+			}
+			got := outcome.String()
+
+			path := filepath.Join("testdata", "golden", enc.name+".golden")
+			if *update {
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("%v (run with -update to create it)", err)
+			}
+			if diff := diffLines(string(want), got); diff != "" {
+				t.Errorf("outcome changed (run with -update if intended):\n%s", diff)
+			}
+		})
+	}
+
+	// The suite is only a balance net if it touches every ability. Skip
+	// the check when -run picked out some encounters.
+	if ran < len(goldenEncounters) {
+		return
+	}
+	for _, a := range gamedata.MustLoadAbilityRegistry().All() {
+		if !used[a.ID] {
+			t.Errorf("no golden encounter uses ability %q; add one", a.ID)
+		}
+	}
+}
+
+// diffLines lists the lines that differ between two texts, or "" if none do.
+func diffLines(want, got string) string {
+	wantLines := strings.Split(strings.TrimRight(want, "\n"), "\n")
+	gotLines := strings.Split(strings.TrimRight(got, "\n"), "\n")
+	var b strings.Builder
+	for i := range max(len(wantLines), len(gotLines)) {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			fmt.Fprintf(&b, "- %s\n+ %s\n", w, g)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,145 @@
+package game
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"math/rand"
+	"strings"
+
+	"github.com/samdwyer/dungeonband/internal/combat"
+)
+
+// countingSource wraps a rand source and counts the values drawn from it,
+// so StateHash can include the RNG's position without disturbing it.
+type countingSource struct {
+	src   rand.Source64
+	draws uint64
+}
+
+// newCountingSource seeds a source the same way rand.NewSource does.
+func newCountingSource(seed int64) *countingSource {
+	return &countingSource{src: rand.NewSource(seed).(rand.Source64)}
+}
+
+func (s *countingSource) Int63() int64 {
+	s.draws++
+	return s.src.Int63()
+}
+
+func (s *countingSource) Uint64() uint64 {
+	s.draws++
+	return s.src.Uint64()
+}
+
+func (s *countingSource) Seed(seed int64) {
+	s.draws = 0
+	s.src.Seed(seed)
+}
+
+// skip advances the source by n draws, discarding them, so a resumed run
+// continues from the position it was saved at.
+func (s *countingSource) skip(n uint64) {
+	for range n {
+		s.Uint64()
+	}
+}
+
+// StateHash folds the game's state into a stable 64-bit hash: the mode and
+// floor, the dungeon's tiles, shrines and corpses, every member's and enemy's stats,
+// statuses and position, the enemies' rolled abilities, the party's items, the
+// combat turn, the encounter and corpse search counts, and how far the RNG and
+// the floor's dungeon stream have advanced.
+// Two games given the same seed and input hash the same after every step,
+// so comparing hashes at checkpoints pinpoints where a replay or a loaded
+// save drifts. It only reads the game.
+func (g *Game) StateHash() uint64 {
+	h := stateHasher{fnv.New64a()}
+	h.ints(g.seed, int64(g.state), int64(g.floor), int64(g.encounters), int64(g.searches))
+	if g.rngSource != nil {
+		h.ints(int64(g.rngSource.draws))
+	}
+	if g.dungeonSource != nil {
+		h.ints(int64(g.dungeonSource.draws))
+	}
+
+	if d := g.dungeon; d != nil {
+		h.ints(int64(d.Width), int64(d.Height), int64(d.StairsX), int64(d.StairsY))
+		for _, row := range d.Tiles {
+			for _, tile := range row {
+				h.ints(int64(tile))
+			}
+		}
+		for _, s := range d.Shrines {
+			h.ints(int64(s.X), int64(s.Y), boolInt(s.Used))
+		}
+		for _, c := range d.Corpses {
+			h.ints(int64(c.X), int64(c.Y), boolInt(c.Looted))
+			h.str(c.EnemyID)
+		}
+	}
+
+	if g.party != nil {
+		h.ints(int64(g.party.X), int64(g.party.Y), int64(g.exploreSteps), int64(g.flavorSteps))
+		for _, m := range g.party.Members {
+			h.str(m.Name)
+			h.ints(int64(m.X), int64(m.Y), int64(m.HP), int64(m.MaxHP), int64(m.MP), int64(m.MaxMP),
+				int64(m.Attack), int64(m.Defense), int64(m.Magic), int64(m.Resist))
+			h.statuses(m.GetStatusEffects())
+		}
+		for _, item := range partyItems {
+			h.ints(int64(g.party.ItemCount(item)))
+		}
+	}
+
+	h.ints(int64(len(g.enemies)))
+	for _, e := range g.enemies {
+		h.str(e.ID())
+		h.ints(int64(e.X), int64(e.Y), int64(e.RoomIndex), int64(e.HP), int64(e.MaxHP), int64(e.MP), int64(e.MaxMP))
+		h.str(string(e.Row))
+		h.str(strings.Join(e.GetAbilityIDs(), ","))
+		h.statuses(e.GetStatusEffects())
+	}
+
+	if cs := g.combatState; cs != nil && g.state == StateCombat {
+		h.ints(int64(cs.Phase), int64(cs.ActiveMemberIndex), int64(cs.ActiveEnemyIndex), int64(cs.TurnCount))
+	}
+	return h.Sum64()
+}
+
+// stateHasher writes values into a hash in a fixed binary form.
+type stateHasher struct {
+	hash.Hash64
+}
+
+// ints writes each value as 8 little-endian bytes.
+func (h stateHasher) ints(values ...int64) {
+	var buf [8]byte
+	for _, v := range values {
+		binary.LittleEndian.PutUint64(buf[:], uint64(v))
+		h.Write(buf[:])
+	}
+}
+
+// str writes a length-prefixed string, so adjacent strings can't run together.
+func (h stateHasher) str(s string) {
+	h.ints(int64(len(s)))
+	h.Write([]byte(s))
+}
+
+// statuses writes a combatant's status effects in order.
+func (h stateHasher) statuses(effects []combat.StatusEffect) {
+	h.ints(int64(len(effects)))
+	for _, e := range effects {
+		h.str(string(e.Type))
+		h.ints(int64(e.RemainingTurns), int64(e.Power), int64(e.Refreshes))
+	}
+}
+
+// boolInt returns 1 for true and 0 for false.
+func boolInt(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
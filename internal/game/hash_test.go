@@ -0,0 +1,57 @@
+package game
+
+import (
+	"context"
+	"testing"
+)
+
+// hashedGame sets up a headless game on the seed.
+func hashedGame(t *testing.T, seed int64) *Game {
+	t.Helper()
+	g, err := New(Config{Seed: seed}, NullDisplay{})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	g.setup(context.Background())
+	return g
+}
+
+func TestStateHashMatchesForSameSeed(t *testing.T) {
+	a, b := hashedGame(t, 31337), hashedGame(t, 31337)
+	if a.StateHash() != b.StateHash() {
+		t.Fatal("identically set-up games should hash the same")
+	}
+	if a.StateHash() != a.StateHash() {
+		t.Fatal("hashing should not change the game")
+	}
+	if a.StateHash() == hashedGame(t, 31338).StateHash() {
+		t.Error("games on different seeds should hash differently")
+	}
+}
+
+func TestStateHashDivergesAfterMutation(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(g *Game)
+	}{
+		{"party moved", func(g *Game) { g.party.X++ }},
+		{"member hurt", func(g *Game) { g.party.Members[1].TakeDamage(1) }},
+		{"enemy hurt", func(g *Game) { g.enemies[0].HP-- }},
+		{"rng drawn", func(g *Game) { g.rng.Intn(6) }},
+		{"dungeon stream drawn", func(g *Game) { g.dungeonSource.Uint64() }},
+		{"corpse searched", func(g *Game) { g.searches++ }},
+		{"encounter fought", func(g *Game) { g.encounters++ }},
+		{"tile changed", func(g *Game) { g.dungeon.Tiles[0][0] = '.' }},
+		{"state changed", func(g *Game) { g.state = StateCombat }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := hashedGame(t, 31337)
+			before := g.StateHash()
+			tt.mutate(g)
+			if g.StateHash() == before {
+				t.Error("hash should change")
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package game
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/telemetry"
+	"github.com/samdwyer/dungeonband/internal/ui"
+)
+
+// itemsTitle heads the item menu.
+const itemsTitle = "Use which item?"
+
+// partyItems lists the items in menu order.
+var partyItems = []entity.Item{entity.ItemEscapeRope}
+
+// itemMenu is the open item prompt, with the item behind each option.
+type itemMenu struct {
+	items  []entity.Item
+	choice *ui.Choice
+}
+
+// openItemMenu lists the items the party carries. Items are used out of
+// combat or on a member's turn.
+func (g *Game) openItemMenu() {
+	if g.state == StateCombat && (g.combatState == nil || g.combatState.Phase != PhasePlayerTurn) {
+		return
+	}
+	menu := &itemMenu{}
+	var labels []string
+	for _, item := range partyItems {
+		if n := g.party.ItemCount(item); n > 0 {
+			menu.items = append(menu.items, item)
+			labels = append(labels, fmt.Sprintf("%s (%d)", item.Name(), n))
+		}
+	}
+	if len(labels) == 0 {
+		g.setMessage("You have no items.")
+		return
+	}
+	menu.choice = ui.NewChoice(itemsTitle, labels...)
+	g.items = menu
+}
+
+// handleItemKey passes a key press to the open item menu.
+func (g *Game) handleItemKey(ctx context.Context, ev *tcell.EventKey) {
+	chosen, done := g.items.choice.HandleKey(ev)
+	if !done {
+		return
+	}
+	items := g.items.items
+	g.items = nil
+	if chosen >= 0 {
+		g.useItem(ctx, items[chosen])
+	}
+}
+
+// useItem spends one of the item for its effect. Returns false if the
+// party has none or it can't be used now.
+func (g *Game) useItem(ctx context.Context, item entity.Item) bool {
+	switch item {
+	case entity.ItemEscapeRope:
+		return g.useEscapeRope(ctx)
+	default:
+		return false
+	}
+}
+
+// useEscapeRope returns the party to the center of the floor's starting
+// room. Used in combat it costs the active member's turn and the enemies
+// still get their round before the party slips away, so the rope never
+// dodges a blow that fleeing would have taken. If that round ends the
+// fight either way, the fight's outcome stands and the party stays put.
+func (g *Game) useEscapeRope(ctx context.Context) bool {
+	inCombat := g.state == StateCombat
+	if inCombat && (g.combatState == nil || g.combatState.Phase != PhasePlayerTurn) {
+		return false
+	}
+	if g.party.ItemCount(entity.ItemEscapeRope) == 0 {
+		g.setMessage("You have no escape rope.")
+		return false
+	}
+	if inCombat {
+		// Pulling the rope is the member's action, so a rewind takes it back
+		g.captureTurn()
+	}
+	g.party.UseItem(entity.ItemEscapeRope)
+
+	tracer := telemetry.Tracer("game")
+	ctx, span := tracer.Start(ctx, "game.item")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("item", string(entity.ItemEscapeRope)),
+		attribute.Bool("in_combat", inCombat),
+		attribute.Int("floor", g.floor),
+		attribute.Int("remaining", g.party.ItemCount(entity.ItemEscapeRope)),
+	)
+
+	if inCombat {
+		name := "The party"
+		if member := g.getActiveMember(); member != nil {
+			name = member.GetName()
+		}
+		g.combatState.Phase = PhaseEnemyTurn
+		g.combatState.ActiveEnemyIndex = 0
+		g.executeEnemyTurns(ctx)
+		if g.combatState.Phase != PhasePlayerTurn {
+			span.SetAttributes(attribute.Bool("escaped", false))
+			return true
+		}
+		g.endCombat(ctx, "escaped")
+		g.transitionState(ctx, StateExplore, "escape_rope")
+		g.message = name + " pulls the escape rope, and the party vanishes from the fight."
+	} else {
+		g.message = "The escape rope whisks the party back to the start of the floor."
+	}
+	span.SetAttributes(attribute.Bool("escaped", true))
+
+	g.party.SetPosition(g.floorStart())
+	return true
+}
+
+// floorStart returns where the party started the floor: the start room's
+// center, or the entry tile on a floor without rooms like the tutorial.
+func (g *Game) floorStart() (int, int) {
+	if g.startRoom < len(g.dungeon.Rooms) {
+		return g.dungeon.Rooms[g.startRoom].Center()
+	}
+	return g.entry.x, g.entry.y
+}
+
+// setMessage shows a note in the combat log during a fight, or as the
+// explore message otherwise.
+func (g *Game) setMessage(text string) {
+	if g.state == StateCombat && g.combatState != nil {
+		g.combatState.LastMessage = text
+		return
+	}
+	g.message = text
+}
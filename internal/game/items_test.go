@@ -0,0 +1,129 @@
+package game
+
+import (
+	"context"
+	"testing"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+)
+
+// awayFromStart moves the party to the center of the second room.
+func awayFromStart(t *testing.T, g *Game) {
+	t.Helper()
+	if len(g.dungeon.Rooms) < 2 {
+		t.Fatal("test dungeon needs at least two rooms")
+	}
+	g.party.SetPosition(g.dungeon.Rooms[1].Center())
+}
+
+func TestEscapeRopeReturnsPartyToStartRoom(t *testing.T) {
+	recorder := recordSpans(t)
+	g := newTestGame(t)
+	ctx := context.Background()
+	awayFromStart(t, g)
+
+	g.handleKeyEvent(ctx, pressRune('i'))
+	if g.items == nil {
+		t.Fatal("pressing i should open the item menu")
+	}
+	g.handleKeyEvent(ctx, pressRune('1'))
+
+	startX, startY := g.dungeon.Rooms[0].Center()
+	if g.party.X != startX || g.party.Y != startY {
+		t.Errorf("party at (%d,%d), want the start room center (%d,%d)", g.party.X, g.party.Y, startX, startY)
+	}
+	if n := g.party.ItemCount(entity.ItemEscapeRope); n != 0 {
+		t.Errorf("escape ropes left = %d, want 0", n)
+	}
+	span := findSpan(recorder, "game.item")
+	if span == nil {
+		t.Fatal("game.item span not recorded")
+	}
+	if got := spanAttr(span, "item").AsString(); got != string(entity.ItemEscapeRope) {
+		t.Errorf("item = %q, want %q", got, entity.ItemEscapeRope)
+	}
+}
+
+func TestEscapeRopeOnTheTutorialFloor(t *testing.T) {
+	g := newTutorialGame(t)
+	g.instructions = nil
+	startX, startY := g.party.X, g.party.Y
+	g.party.SetPosition(startX+1, startY)
+	g.party.AddItem(entity.ItemEscapeRope, 1)
+
+	if !g.useEscapeRope(context.Background()) {
+		t.Fatal("the rope should work on a floor without rooms")
+	}
+	if g.party.X != startX || g.party.Y != startY {
+		t.Errorf("party at (%d,%d), want the tutorial start (%d,%d)", g.party.X, g.party.Y, startX, startY)
+	}
+}
+
+func TestEscapeRopeInCombatCostsARound(t *testing.T) {
+	g := newTestGame(t)
+	ctx := context.Background()
+	awayFromStart(t, g)
+	g.enemies = []*entity.Enemy{entity.NewEnemy(entity.EnemyGoblin, g.party.X+1, g.party.Y, 1)}
+	g.transitionState(ctx, StateCombat, "test")
+
+	g.handleKeyEvent(ctx, pressRune('i'))
+	g.handleKeyEvent(ctx, pressRune('1'))
+
+	if g.state != StateExplore {
+		t.Fatalf("state = %v, want explore after escaping", g.state)
+	}
+	if len(g.combatState.EnemyActions) == 0 {
+		t.Error("enemies should act before the party escapes")
+	}
+	startX, startY := g.dungeon.Rooms[0].Center()
+	if g.party.X != startX || g.party.Y != startY {
+		t.Errorf("party at (%d,%d), want the start room center (%d,%d)", g.party.X, g.party.Y, startX, startY)
+	}
+}
+
+func TestItemMenuWithNoItems(t *testing.T) {
+	g := newTestGame(t)
+	g.party.UseItem(entity.ItemEscapeRope)
+	x, y := g.party.X, g.party.Y
+
+	g.handleKeyEvent(context.Background(), pressRune('i'))
+
+	if g.items != nil {
+		t.Error("item menu should stay closed with nothing to use")
+	}
+	if g.message != "You have no items." {
+		t.Errorf("message = %q, want the empty-bag note", g.message)
+	}
+	if g.party.X != x || g.party.Y != y {
+		t.Error("party should not move")
+	}
+}
+
+func TestRewindAfterTheRopeTakesBackOnlyTheRope(t *testing.T) {
+	g := startLosingFight(t)
+	attackWithActive(g)
+	for _, m := range g.party.Members {
+		if m != g.getActiveMember() {
+			m.HP = 0
+		}
+	}
+	survivor := g.getActiveMember()
+	survivor.HP = 1
+	goblin := g.combatEnemies[0]
+	goblin.X, goblin.Y = survivor.X+1, survivor.Y
+	goblin.Abilities = []string{"attack"}
+	before := combatSnapshot(g)
+	ropes := g.party.ItemCount(entity.ItemEscapeRope)
+
+	g.useEscapeRope(context.Background())
+	if g.combatState.Phase != PhaseDefeat {
+		t.Fatalf("phase = %v, want the party to fall before it escapes", g.combatState.Phase)
+	}
+	press(g, 'u')
+	if got := combatSnapshot(g); got != before {
+		t.Errorf("after rewind:\n got %s\nwant %s", got, before)
+	}
+	if n := g.party.ItemCount(entity.ItemEscapeRope); n != ropes {
+		t.Errorf("escape ropes after rewind = %d, want %d", n, ropes)
+	}
+}
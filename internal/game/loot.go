@@ -0,0 +1,96 @@
+package game
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/seed"
+	"github.com/samdwyer/dungeonband/internal/telemetry"
+	"github.com/samdwyer/dungeonband/internal/world"
+)
+
+// leaveCorpse marks where an enemy fell. Loot is only ever rolled from the
+// corpse, so searching it is the one place rewards come from.
+func (g *Game) leaveCorpse(e *entity.Enemy) {
+	g.dungeon.AddCorpse(e.X, e.Y, e.ID())
+}
+
+// corpseName returns the name of the enemy a corpse was.
+func (g *Game) corpseName(c *world.Corpse) string {
+	if g.enemyRegistry != nil {
+		if def := g.enemyRegistry.GetByID(c.EnemyID); def != nil {
+			return def.Name
+		}
+	}
+	return c.EnemyID
+}
+
+// noteCorpse mentions a corpse the party stepped onto.
+func (g *Game) noteCorpse() {
+	c := g.dungeon.CorpseAt(g.party.X, g.party.Y)
+	if c == nil {
+		return
+	}
+	name := g.corpseName(c)
+	a := article(name)
+	corpse := strings.ToUpper(a[:1]) + a[1:] + " " + name + " corpse"
+	note := corpse + " lies here (g to search)."
+	if c.Looted {
+		note = corpse + " lies here, already searched."
+	}
+	g.message = strings.TrimSpace(g.message + " " + note)
+}
+
+// searchCorpse rolls the loot table of the corpse under the party into the
+// party's items and marks it searched. Returns false if there was no
+// unsearched corpse.
+func (g *Game) searchCorpse(ctx context.Context) bool {
+	c := g.dungeon.CorpseAt(g.party.X, g.party.Y)
+	if c == nil {
+		g.message = "There's nothing here to search."
+		return false
+	}
+	name := g.corpseName(c)
+	if c.Looted {
+		g.message = "The " + name + " corpse has already been searched."
+		return false
+	}
+	c.Looted = true
+	g.makeNoise(g.party.X, g.party.Y, noiseSearch)
+
+	rng := g.stream(seed.Loot, g.searches)
+	g.searches++
+
+	var found []string
+	if g.enemyRegistry != nil {
+		if def := g.enemyRegistry.GetByID(c.EnemyID); def != nil {
+			for _, drop := range def.Loot {
+				if rng.Float64() >= drop.Chance {
+					continue
+				}
+				item := entity.Item(drop.Item)
+				g.party.AddItem(item, drop.Amount())
+				found = append(found, item.Name())
+			}
+		}
+	}
+
+	tracer := telemetry.Tracer("game")
+	_, span := tracer.Start(ctx, "game.loot")
+	span.SetAttributes(
+		attribute.String("enemy", c.EnemyID),
+		attribute.StringSlice("items", found),
+		attribute.Int("floor", g.floor),
+	)
+	span.End()
+
+	if len(found) == 0 {
+		g.message = "You search the " + name + " corpse and find nothing."
+	} else {
+		g.message = "You search the " + name + " corpse and find " + strings.Join(found, ", ") + "."
+	}
+	return true
+}
@@ -0,0 +1,77 @@
+package game
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+)
+
+func TestVictoryLeavesCorpses(t *testing.T) {
+	g := newTestGame(t)
+	ctx := context.Background()
+	goblin := entity.NewEnemy(entity.EnemyGoblin, g.party.X+1, g.party.Y, 0)
+	g.enemies = []*entity.Enemy{goblin}
+	g.transitionState(ctx, StateCombat, "test")
+
+	goblin.TakeDamage(goblin.GetMaxHP())
+	g.endCombat(ctx, "victory")
+
+	if len(g.enemies) != 0 {
+		t.Errorf("%d enemies left on the map, want the dead removed", len(g.enemies))
+	}
+	c := g.dungeon.CorpseAt(goblin.X, goblin.Y)
+	if c == nil {
+		t.Fatal("no corpse where the goblin fell")
+	}
+	if c.EnemyID != goblin.ID() || c.Looted {
+		t.Errorf("corpse = %+v, want an unsearched %s", *c, goblin.ID())
+	}
+}
+
+func TestCorpseNoteUsesTheRightArticle(t *testing.T) {
+	g := newTestGame(t)
+	g.enemyRegistry = gamedata.NewEnemyRegistry([]gamedata.EnemyDef{{ID: "orc", Name: "Orc", HP: 5}})
+	g.dungeon.AddCorpse(g.party.X, g.party.Y, "orc")
+	g.message = ""
+
+	g.noteCorpse()
+	if want := "An Orc corpse lies here (g to search)."; g.message != want {
+		t.Errorf("message = %q, want %q", g.message, want)
+	}
+}
+
+func TestCorpseCanOnlyBeSearchedOnce(t *testing.T) {
+	recorder := recordSpans(t)
+	g := newTestGame(t)
+	ctx := context.Background()
+	g.enemyRegistry = gamedata.NewEnemyRegistry([]gamedata.EnemyDef{{
+		ID: "bandit", Name: "Bandit", HP: 5,
+		Loot: []gamedata.LootDrop{{Item: string(entity.ItemEscapeRope), Chance: 1, Count: 2}},
+	}})
+	g.dungeon.AddCorpse(g.party.X, g.party.Y, "bandit")
+	before := g.party.ItemCount(entity.ItemEscapeRope)
+
+	g.handleKeyEvent(ctx, pressRune('g'))
+	if got := g.party.ItemCount(entity.ItemEscapeRope); got != before+2 {
+		t.Fatalf("escape ropes = %d, want %d after searching", got, before+2)
+	}
+	if !strings.Contains(g.message, "Escape Rope") {
+		t.Errorf("message = %q, want the find named", g.message)
+	}
+	if span := findSpan(recorder, "game.loot"); span == nil {
+		t.Error("game.loot span not recorded")
+	}
+
+	if g.searchCorpse(ctx) {
+		t.Error("a searched corpse should not be searched again")
+	}
+	if got := g.party.ItemCount(entity.ItemEscapeRope); got != before+2 {
+		t.Errorf("escape ropes = %d after a second search, want %d", got, before+2)
+	}
+	if !strings.Contains(g.message, "already been searched") {
+		t.Errorf("message = %q, want an already-searched note", g.message)
+	}
+}
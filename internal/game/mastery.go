@@ -0,0 +1,123 @@
+package game
+
+import (
+	"strings"
+
+	"github.com/samdwyer/dungeonband/internal/combat"
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+)
+
+// masteryTitles name each mastery tier in announcements and on the
+// character sheet.
+var masteryTitles = []string{"", "mastered", "perfected"}
+
+// noteUse counts a party member's successful cast toward mastering the
+// ability. Returns an announcement when their next cast reaches a new tier,
+// or "" otherwise.
+func (g *Game) noteUse(user combat.Combatant, ability *gamedata.AbilityDef) string {
+	m, ok := user.(*entity.Member)
+	if !ok || !m.RecordUse(ability.ID) {
+		return ""
+	}
+	tier := min(m.Mastery(ability.ID), len(masteryTitles)-1)
+	return " " + m.GetName() + " has " + masteryTitles[tier] + " " + ability.Name + "!"
+}
+
+// canCast reports whether the combatant knows the ability and can cast it
+// right now, paying its cost after mastery, off cooldown and not silenced
+// out of it. MP already planned for this round isn't available.
+func (g *Game) canCast(c combat.Combatant, ability *gamedata.AbilityDef) bool {
+	if !g.effectResolver.CanUse(ability, c) || combat.BlockedBySilence(ability, c) {
+		return false
+	}
+	if g.combatState != nil && g.combatState.onCooldown(c, ability) {
+		return false
+	}
+	return !g.overPlannedMP(c, ability)
+}
+
+// usableBy returns the combat abilities c can cast right now, by the rules
+// of canCast, in c's ability order.
+func (g *Game) usableBy(c combat.Combatant) []*gamedata.AbilityDef {
+	var usable []*gamedata.AbilityDef
+	for _, a := range g.combatAbilities(c) {
+		if g.canCast(c, a) {
+			usable = append(usable, a)
+		}
+	}
+	return usable
+}
+
+// overPlannedMP returns true if the member's planned actions leave too
+// little MP for the ability.
+func (g *Game) overPlannedMP(c combat.Combatant, ability *gamedata.AbilityDef) bool {
+	m, ok := c.(*entity.Member)
+	if !ok || g.combatState == nil {
+		return false
+	}
+	return m.GetMP()-g.combatState.plannedMP(m) < combat.MPCost(ability, m)
+}
+
+// AbilityUses returns how many times the party has cast each ability since
+// the game started, summed over its members. Casts made before a resumed
+// save are left out, since the session that made them already counted them.
+func (g *Game) AbilityUses() map[string]int {
+	uses := g.partyAbilityUses()
+	for id, n := range g.resumedUses {
+		uses[id] -= n
+		if uses[id] <= 0 {
+			delete(uses, id)
+		}
+	}
+	return uses
+}
+
+// partyAbilityUses sums the members' ability use counts.
+func (g *Game) partyAbilityUses() map[string]int {
+	uses := make(map[string]int)
+	for _, m := range g.party.Members {
+		for id, n := range m.AbilityUses {
+			uses[id] += n
+		}
+	}
+	return uses
+}
+
+// characterSheetOverlay lists each member's combat totals for the run, and
+// their abilities with how often they have been cast and the mastery
+// bonuses earned.
+func (g *Game) characterSheetOverlay() Overlay {
+	var lines []string
+	for i, m := range g.party.Members {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, m.GetName()+" the "+m.Class.String())
+		s := g.stats.lifetimeFor(m)
+		kills := itoa(s.Kills) + " kill"
+		if s.Kills != 1 {
+			kills += "s"
+		}
+		lines = append(lines, "  "+itoa(s.DamageDealt)+" damage dealt, "+itoa(s.DamageTaken)+" taken, "+
+			itoa(s.HealingDone)+" healed, "+kills)
+		for _, id := range m.GetAbilityIDs() {
+			name := id
+			if a := g.abilityRegistry.GetByID(id); a != nil {
+				name = a.Name
+			}
+			tier := m.Mastery(id)
+			line := "  " + name + strings.Repeat("*", tier) + ": " + itoa(m.AbilityUses[id]) + " casts"
+			if tier > 0 {
+				mods := m.CastModifiers(id)
+				line += ", " + masteryTitles[min(tier, len(masteryTitles)-1)] +
+					" (-" + itoa(mods.MPCostReduction) + " MP, +" + itoa(mods.PowerBonus) + " power)"
+			}
+			if tier < len(entity.MasteryThresholds) {
+				line += ", next at " + itoa(entity.MasteryThresholds[tier]-1)
+			}
+			lines = append(lines, line)
+		}
+	}
+	return Overlay{Kind: OverlayInstruction, Title: "Character Sheet", Text: strings.Join(lines, "\n")}
+}
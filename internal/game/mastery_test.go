@@ -0,0 +1,121 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/samdwyer/dungeonband/internal/combat"
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+)
+
+// castFireball has the wizard cast fireball at a fresh orc, returning the
+// damage dealt and MP spent.
+func castFireball(g *Game, wizard *entity.Member) (damage, mp int) {
+	orc := entity.NewEnemy(entity.EnemyOrc, 5, 5, 1)
+	orc.MaxHP, orc.HP = 999, 999
+	wizard.RestoreMP(100)
+	before := wizard.GetMP()
+	g.executeCombatTurn(context.Background(), g.abilityRegistry.GetByID("fireball"), wizard, orc)
+	return orc.MaxHP - orc.HP, before - wizard.GetMP()
+}
+
+func TestTenthCastIsMastered(t *testing.T) {
+	g := startStatsCombat(t, entity.NewEnemy(entity.EnemyGoblin, 5, 5, 1))
+	wizard := g.party.Members[2]
+
+	var damage, mp int
+	for range 9 {
+		damage, mp = castFireball(g, wizard)
+	}
+	if !strings.Contains(g.combatState.LastMessage, "has mastered Fireball") {
+		t.Errorf("LastMessage = %q, want mastery announced", g.combatState.LastMessage)
+	}
+
+	masteredDamage, masteredMP := castFireball(g, wizard)
+	if masteredDamage != damage+1 {
+		t.Errorf("10th cast dealt %d damage, want %d", masteredDamage, damage+1)
+	}
+	if masteredMP != mp-1 {
+		t.Errorf("10th cast cost %d MP, want %d", masteredMP, mp-1)
+	}
+	if got := wizard.AbilityUses["fireball"]; got != 10 {
+		t.Errorf("AbilityUses[fireball] = %d, want 10", got)
+	}
+}
+
+func TestMasteryIsPerMember(t *testing.T) {
+	g := startStatsCombat(t, entity.NewEnemy(entity.EnemyGoblin, 5, 5, 1))
+	wizard := g.party.Members[2]
+	twin := entity.NewMember("Twin", wizard.Class)
+	twin.InitFromClassDef(g.classRegistry.GetByID("wizard"))
+
+	firstDamage, firstMP := castFireball(g, twin)
+	for range 20 {
+		castFireball(g, wizard)
+	}
+	damage, mp := castFireball(g, twin)
+	if damage != firstDamage || mp != firstMP {
+		t.Errorf("twin's fireball = %d damage for %d MP, want unchanged %d for %d",
+			damage, mp, firstDamage, firstMP)
+	}
+	if twin.Mastery("fireball") != 0 {
+		t.Errorf("twin's mastery = %d, want 0", twin.Mastery("fireball"))
+	}
+}
+
+func TestCanCastCountsMasteryAndSilence(t *testing.T) {
+	g := startStatsCombat(t, entity.NewEnemy(entity.EnemyGoblin, 5, 5, 1))
+	wizard := g.party.Members[2]
+	fireball := g.abilityRegistry.GetByID("fireball")
+
+	for range 9 {
+		castFireball(g, wizard)
+	}
+	wizard.MP = fireball.MPCost - 1
+	if !g.canCast(wizard, fireball) {
+		t.Errorf("canCast with %d MP after mastery = false, want true", wizard.MP)
+	}
+
+	apprentice := entity.NewMember("Apprentice", entity.ClassWizard)
+	apprentice.AbilityIDs = wizard.AbilityIDs
+	apprentice.MP = fireball.MPCost - 1
+	if g.canCast(apprentice, fireball) {
+		t.Errorf("canCast with %d MP before mastery = true, want false", apprentice.MP)
+	}
+
+	wizard.RestoreMP(100)
+	wizard.AddStatusEffect(combat.StatusEffect{Type: gamedata.StatusSilence, RemainingTurns: 2})
+	if g.canCast(wizard, fireball) {
+		t.Error("canCast while silenced = true, want false")
+	}
+	if !g.canCast(wizard, g.abilityRegistry.GetByID("attack")) {
+		t.Error("canCast(attack) while silenced = false, want true")
+	}
+}
+
+func TestUsableByLeavesOutWhatCannotBeCast(t *testing.T) {
+	g := startStatsCombat(t, entity.NewEnemy(entity.EnemyGoblin, 5, 5, 1))
+	rogue, wizard := g.party.Members[1], g.party.Members[2]
+	rogue.MP = 0
+	g.combatState.startCooldown(rogue, g.abilityRegistry.GetByID("trip"))
+	wizard.AddStatusEffect(combat.StatusEffect{Type: gamedata.StatusSilence, RemainingTurns: 2})
+
+	for _, tc := range []struct {
+		member *entity.Member
+		want   string
+	}{
+		{rogue, "[attack defend]"},
+		{wizard, "[attack defend]"},
+	} {
+		var ids []string
+		for _, a := range g.usableBy(tc.member) {
+			ids = append(ids, a.ID)
+		}
+		if got := fmt.Sprint(ids); got != tc.want {
+			t.Errorf("usableBy(%s) = %s, want %s", tc.member.Name, got, tc.want)
+		}
+	}
+}
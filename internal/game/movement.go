@@ -0,0 +1,186 @@
+package game
+
+import (
+	"context"
+	"slices"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/telemetry"
+	"github.com/samdwyer/dungeonband/internal/world"
+)
+
+// enemyMove is an enemy that spends its turn closing on a member it can't
+// reach.
+type enemyMove struct {
+	index  int // Index in the combat's enemy list
+	enemy  *entity.Enemy
+	target *entity.Member
+	step   position // Planned next tile; the enemy's own tile if it has none
+	done   bool
+}
+
+// occupancy maps tiles to the living combatants standing on them. Members
+// are stored as nil enemies.
+type occupancy map[position]*entity.Enemy
+
+// combatOccupancy indexes the tiles held by living combatants.
+func (g *Game) combatOccupancy() occupancy {
+	occ := make(occupancy)
+	for _, m := range g.party.Members {
+		if m.IsAlive() {
+			occ[position{m.X, m.Y}] = nil
+		}
+	}
+	for _, e := range g.combatState.Enemies {
+		if e.IsAlive() {
+			occ[position{e.X, e.Y}] = e
+		}
+	}
+	return occ
+}
+
+// move records an enemy leaving one tile for another.
+func (occ occupancy) move(e *entity.Enemy, to position) {
+	delete(occ, position{e.X, e.Y})
+	occ[to] = e
+	e.X, e.Y = to.x, to.y
+}
+
+// resolveEnemyMoves moves the enemies that couldn't reach their targets
+// this round, nearest to its target first so a line of enemies in a
+// corridor advances together instead of blocking on whoever is behind.
+//
+// Each enemy plans a step along its shortest path, routing around members
+// and enemies that stay put. In order, it takes that tile if it's free by
+// then; swaps places with a mover that has yet to move and planned to step
+// onto its tile; or tries a detour around everyone as they now stand
+// before giving up. The occupancy index is updated after every move, so
+// later movers see where earlier ones went.
+func (g *Game) resolveEnemyMoves(ctx context.Context, moves []*enemyMove) {
+	moves = g.retargetMoves(moves)
+	if len(moves) == 0 {
+		return
+	}
+	slices.SortStableFunc(moves, func(a, b *enemyMove) int {
+		return world.Distance(a.enemy.X, a.enemy.Y, a.target.X, a.target.Y) -
+			world.Distance(b.enemy.X, b.enemy.Y, b.target.X, b.target.Y)
+	})
+
+	occ := g.combatOccupancy()
+	moving := make(map[*entity.Enemy]*enemyMove, len(moves))
+	for _, m := range moves {
+		moving[m.enemy] = m
+	}
+	for _, m := range moves {
+		// Only members and enemies that aren't moving shape the plan
+		staying := func(x, y int) bool {
+			e, held := occ[position{x, y}]
+			return held && (e == nil || moving[e] == nil)
+		}
+		m.step = position{m.enemy.X, m.enemy.Y}
+		if x, y, ok := g.dungeon.NextStepToward(m.enemy.X, m.enemy.Y, m.target.X, m.target.Y, meleeReach, staying); ok {
+			m.step = position{x, y}
+		}
+	}
+
+	for _, m := range moves {
+		if m.done {
+			continue
+		}
+		g.combatState.ActiveEnemyIndex = m.index
+		g.moveEnemy(ctx, m, occ, moving)
+		g.combatState.EnemyActions = append(g.combatState.EnemyActions, g.combatState.LastMessage)
+	}
+}
+
+// retargetMoves re-checks each move's target before anyone moves: a member
+// killed earlier in the round is swapped for the nearest living member.
+// Moves whose new target is already in reach, or with nobody left to
+// chase, are dropped.
+func (g *Game) retargetMoves(moves []*enemyMove) []*enemyMove {
+	return slices.DeleteFunc(moves, func(m *enemyMove) bool {
+		if !m.enemy.IsAlive() {
+			return true
+		}
+		if m.target.IsAlive() {
+			return false
+		}
+		m.target = g.nearestMember(m.enemy)
+		return m.target == nil || inMeleeRange(m.enemy, m.target)
+	})
+}
+
+// nearestMember returns the living member closest to the enemy, or nil if
+// the whole party is down.
+func (g *Game) nearestMember(enemy *entity.Enemy) *entity.Member {
+	var nearest *entity.Member
+	best := 0
+	for _, m := range g.party.Members {
+		if !m.IsAlive() {
+			continue
+		}
+		if d := world.Distance(enemy.X, enemy.Y, m.X, m.Y); nearest == nil || d < best {
+			nearest, best = m, d
+		}
+	}
+	return nearest
+}
+
+// moveEnemy resolves one enemy's planned step against the current
+// occupancy and records the outcome.
+func (g *Game) moveEnemy(ctx context.Context, m *enemyMove, occ occupancy, moving map[*entity.Enemy]*enemyMove) {
+	tracer := telemetry.Tracer("combat")
+	_, span := tracer.Start(ctx, "combat.move")
+	span.SetAttributes(
+		g.combatState.encounterAttr(),
+		attribute.String("actor", m.enemy.GetName()),
+		attribute.String("target", m.target.GetName()),
+		attribute.Int("turn", g.combatState.TurnCount),
+	)
+	defer span.End()
+
+	here := position{m.enemy.X, m.enemy.Y}
+	resolution := "blocked"
+	holder, held := occ[m.step]
+	other := moving[holder]
+	switch {
+	case m.step == here:
+		// No path at all, even ignoring other movers
+	case !held:
+		occ.move(m.enemy, m.step)
+		resolution = "step"
+	case holder != nil && other != nil && !other.done && other.step == here:
+		// Two enemies heading opposite ways trade places
+		occ[m.step], occ[here] = m.enemy, other.enemy
+		m.enemy.X, m.enemy.Y = m.step.x, m.step.y
+		other.enemy.X, other.enemy.Y = here.x, here.y
+		other.done = true
+		resolution = "swap"
+	default:
+		// Try to get around the jam as everyone now stands
+		blocked := func(x, y int) bool { _, held := occ[position{x, y}]; return held }
+		if x, y, ok := g.dungeon.NextStepToward(here.x, here.y, m.target.X, m.target.Y, meleeReach, blocked); ok {
+			occ.move(m.enemy, position{x, y})
+			resolution = "sidestep"
+		}
+	}
+	m.done = true
+
+	switch resolution {
+	case "swap":
+		g.combatState.LastMessage = m.enemy.GetName() + " swaps places with " + other.enemy.GetName() + "."
+	case "blocked":
+		g.combatState.LastMessage = m.enemy.GetName() + " can't reach " + m.target.GetName() + "."
+	default:
+		g.combatState.LastMessage = m.enemy.GetName() + " moves toward " + m.target.GetName() + "."
+	}
+	span.SetAttributes(
+		attribute.String("resolution", resolution),
+		attribute.Bool("moved", resolution != "blocked"),
+		attribute.Int("distance", world.Distance(m.enemy.X, m.enemy.Y, m.target.X, m.target.Y)),
+	)
+
+	g.combatState.TurnCount++
+}
@@ -0,0 +1,143 @@
+package game
+
+import (
+	"context"
+	"testing"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+)
+
+// newMovementGame puts the warrior and rogue on the given tiles of the
+// layout, the rest of the party down, and starts a positional fight with
+// goblins at the given tiles.
+func newMovementGame(t *testing.T, layout string, warrior, rogue position, goblins ...position) *Game {
+	t.Helper()
+	g := newTestGame(t)
+	g.dungeon = dungeonFromMap(layout)
+	for i, m := range g.party.Members {
+		m.SetPosition(0, 0)
+		if i > 1 {
+			m.HP = 0
+		}
+	}
+	g.party.Members[0].SetPosition(warrior.x, warrior.y)
+	g.party.Members[1].SetPosition(rogue.x, rogue.y)
+
+	var enemies []*entity.Enemy
+	for _, p := range goblins {
+		enemies = append(enemies, entity.NewEnemy(entity.EnemyGoblin, p.x, p.y, 0))
+	}
+	g.state = StateCombat
+	g.combatEnemies = enemies
+	g.combatState = NewCombatState(enemies)
+	g.combatState.Positional = true
+	return g
+}
+
+// chase queues a move toward target for each enemy that can't reach it,
+// listed back to front so the resolver has to order them.
+func chase(g *Game, target *entity.Member) []*enemyMove {
+	var moves []*enemyMove
+	for i := len(g.combatState.Enemies) - 1; i >= 0; i-- {
+		e := g.combatState.Enemies[i]
+		if !inMeleeRange(e, target) {
+			moves = append(moves, &enemyMove{index: i, enemy: e, target: target})
+		}
+	}
+	return moves
+}
+
+func TestCongaLineAdvancesDownCorridor(t *testing.T) {
+	g := newMovementGame(t, `
+############
+#..........#
+############`, position{2, 1}, position{1, 1}, position{6, 1}, position{7, 1}, position{8, 1})
+	warrior := g.party.Members[0]
+
+	want := [][]int{{5, 6, 7}, {4, 5, 6}, {3, 4, 5}, {3, 4, 5}}
+	for turn, xs := range want {
+		g.resolveEnemyMoves(context.Background(), chase(g, warrior))
+		for i, e := range g.combatState.Enemies {
+			if e.X != xs[i] || e.Y != 1 {
+				t.Errorf("turn %d: goblin %d at (%d,%d), want (%d,1)", turn+1, i, e.X, e.Y, xs[i])
+			}
+		}
+	}
+}
+
+func TestEnemiesHeadingOppositeWaysSwap(t *testing.T) {
+	g := newMovementGame(t, `
+############
+#..........#
+############`, position{1, 1}, position{10, 1}, position{6, 1}, position{7, 1})
+	warrior, rogue := g.party.Members[0], g.party.Members[1]
+	east, west := g.combatState.Enemies[0], g.combatState.Enemies[1]
+
+	g.resolveEnemyMoves(context.Background(), []*enemyMove{
+		{index: 1, enemy: west, target: warrior},
+		{index: 0, enemy: east, target: rogue},
+	})
+
+	if east.X != 7 || west.X != 6 {
+		t.Errorf("east goblin at x=%d, west goblin at x=%d; want them swapped to 7 and 6", east.X, west.X)
+	}
+	if got := g.combatState.EnemyActions; len(got) != 1 || got[0] != "Goblin swaps places with Goblin." {
+		t.Errorf("EnemyActions = %q, want a single swap", got)
+	}
+}
+
+func TestMoverRetargetsWhenItsTargetDies(t *testing.T) {
+	g := newMovementGame(t, `
+############
+#..........#
+############`, position{1, 1}, position{10, 1}, position{6, 1})
+	warrior, rogue := g.party.Members[0], g.party.Members[1]
+	goblin := g.combatState.Enemies[0]
+
+	// The goblin set out for the rogue, who fell before it moved
+	rogue.HP = 0
+	g.resolveEnemyMoves(context.Background(), []*enemyMove{{index: 0, enemy: goblin, target: rogue}})
+
+	if goblin.X != 5 {
+		t.Errorf("goblin at x=%d, want x=5, a step toward the warrior", goblin.X)
+	}
+	if got := g.combatState.EnemyActions; len(got) != 1 || got[0] != "Goblin moves toward "+warrior.GetName()+"." {
+		t.Errorf("EnemyActions = %q, want a move toward the warrior", got)
+	}
+
+	// With nobody left standing it doesn't move at all
+	warrior.HP = 0
+	g.combatState.EnemyActions = nil
+	g.resolveEnemyMoves(context.Background(), []*enemyMove{{index: 0, enemy: goblin, target: rogue}})
+	if goblin.X != 5 || len(g.combatState.EnemyActions) != 0 {
+		t.Errorf("goblin at x=%d with actions %q, want it to stay put", goblin.X, g.combatState.EnemyActions)
+	}
+}
+
+func TestBlockedEnemySidestepsAroundStuckOne(t *testing.T) {
+	// The rogue is walled into a pocket behind a goblin that holds its
+	// ground, so the goblin chasing it is stuck. The goblin behind it,
+	// chasing the warrior, takes the long way round instead of waiting.
+	g := newMovementGame(t, `
+###########
+#.........#
+#.#######.#
+#.........#
+#####.#####
+#####.#####
+###########`, position{1, 3}, position{5, 5}, position{5, 4}, position{5, 3}, position{6, 3})
+	warrior, rogue := g.party.Members[0], g.party.Members[1]
+	stuck, behind := g.combatState.Enemies[1], g.combatState.Enemies[2]
+
+	g.resolveEnemyMoves(context.Background(), []*enemyMove{
+		{index: 2, enemy: behind, target: warrior},
+		{index: 1, enemy: stuck, target: rogue},
+	})
+
+	if stuck.X != 5 || stuck.Y != 3 {
+		t.Errorf("stuck goblin moved to (%d,%d)", stuck.X, stuck.Y)
+	}
+	if behind.X != 7 || behind.Y != 3 {
+		t.Errorf("goblin behind at (%d,%d), want the detour step (7,3)", behind.X, behind.Y)
+	}
+}
@@ -0,0 +1,142 @@
+package game
+
+import (
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/world"
+)
+
+// How loud each action is: how many tiles its noise carries before it
+// fades out. A noise that carries one tile is heard only beside it.
+const (
+	noiseCombatRound = 16 // Every round of a fight
+	noiseSearch      = 6  // Searching a corpse
+	noiseStep        = 2  // The party walking
+	noiseRogueStep   = 1  // A rogue scouting alone
+)
+
+// investigateTurns is how many steps a group takes toward a noise before
+// giving up. Groups step once for each step the party or a scout takes.
+const investigateTurns = 20
+
+// investigation is a group of enemies heading for a noise it heard.
+type investigation struct {
+	target position
+	turns  int // Steps left before the group gives up
+}
+
+// groupKey identifies an enemy group: the room its members spawned in, or
+// a lone enemy that spawned outside any room.
+type groupKey struct {
+	room  int
+	loner *entity.Enemy
+}
+
+// enemyGroup identifies the group an enemy belongs to: the enemies that
+// spawned in the same room. Enemies from corridors each go alone.
+func enemyGroup(e *entity.Enemy) groupKey {
+	if e.RoomIndex < 0 {
+		return groupKey{room: -1, loner: e}
+	}
+	return groupKey{room: e.RoomIndex}
+}
+
+// isInvestigating returns true if the enemy's group is heading for a noise.
+func (g *Game) isInvestigating(e *entity.Enemy) bool {
+	return g.investigations[enemyGroup(e)] != nil
+}
+
+// makeNoise spreads a noise from (x, y) that carries the given number of
+// tiles. Unaware groups with a member in earshot start investigating it.
+// Enemies in the current fight already know where the party is.
+func (g *Game) makeNoise(x, y, tiles int) {
+	if g.dungeon == nil {
+		return
+	}
+	// NoiseReach drops a tile once the noise has faded to nothing there
+	reach := g.dungeon.NoiseReach(x, y, tiles+1)
+	for _, e := range g.enemies {
+		if !e.IsAlive() || g.isInvestigating(e) || g.inCurrentFight(e) {
+			continue
+		}
+		if reach[world.Point{X: e.X, Y: e.Y}] == 0 {
+			continue
+		}
+		if g.investigations == nil {
+			g.investigations = make(map[groupKey]*investigation)
+		}
+		g.investigations[enemyGroup(e)] = &investigation{target: position{x, y}, turns: investigateTurns}
+	}
+}
+
+// inCurrentFight returns true if the enemy is fighting the party right now.
+func (g *Game) inCurrentFight(e *entity.Enemy) bool {
+	if g.state != StateCombat {
+		return false
+	}
+	for _, c := range g.combatEnemies {
+		if c == e {
+			return true
+		}
+	}
+	return false
+}
+
+// advanceInvestigations moves each investigating group one step toward the
+// noise it heard and counts the step against its turns. A group gives up once it runs out of turns or every
+// member has arrived or lost the way.
+func (g *Game) advanceInvestigations() {
+	if len(g.investigations) == 0 {
+		return
+	}
+	occupied := make(map[position]bool)
+	for _, e := range g.enemies {
+		if e.IsAlive() {
+			occupied[position{e.X, e.Y}] = true
+		}
+	}
+	party := func(x, y int) bool { return x == g.party.X && y == g.party.Y }
+
+	moved := make(map[groupKey]bool)
+	for _, e := range g.enemies {
+		inv := g.investigations[enemyGroup(e)]
+		if inv == nil || !e.IsAlive() {
+			continue
+		}
+		// Path around the party but not each other, so a group files
+		// through a corridor instead of stalling behind its leader
+		x, y, ok := g.dungeon.NextStepToward(e.X, e.Y, inv.target.x, inv.target.y, 1, party)
+		if !ok {
+			continue
+		}
+		moved[enemyGroup(e)] = true
+		if occupied[position{x, y}] {
+			continue
+		}
+		delete(occupied, position{e.X, e.Y})
+		e.X, e.Y = x, y
+		occupied[position{x, y}] = true
+	}
+
+	for group, inv := range g.investigations {
+		inv.turns--
+		if inv.turns <= 0 || !moved[group] {
+			delete(g.investigations, group)
+		}
+	}
+}
+
+// walkTurn makes the walker's noise at (x, y) and gives investigating
+// groups their turn.
+func (g *Game) walkTurn(x, y int) {
+	g.passTurn()
+	g.makeNoise(x, y, g.stepNoise())
+	g.advanceInvestigations()
+}
+
+// stepNoise is how loud the controlled party or scout is when it walks.
+func (g *Game) stepNoise() int {
+	if g.scoutControl && g.party.Scout != nil && g.party.Scout.Class == entity.ClassRogue {
+		return noiseRogueStep
+	}
+	return noiseStep
+}
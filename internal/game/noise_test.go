@@ -0,0 +1,134 @@
+package game
+
+import (
+	"context"
+	"testing"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/world"
+)
+
+// twoRoomFloor sets up two rooms joined by a corridor, with the party and a
+// goblin in the west room and a group of two in the east room. Only enemies
+// in the party's room are noticed.
+func twoRoomFloor(t *testing.T) (g *Game, goblin *entity.Enemy, east []*entity.Enemy) {
+	t.Helper()
+	g = newTestGame(t)
+	g.cfg.EnemyVisibility = world.VisibilityRoom
+	g.dungeon = dungeonFromMap(`
+#################
+#.....#####.....#
+#...............#
+#.....#####.....#
+#################`)
+	g.dungeon.Rooms = []world.Room{{X: 1, Y: 1, Width: 5, Height: 3}, {X: 11, Y: 1, Width: 5, Height: 3}}
+	g.party.SetPosition(2, 2)
+	goblin = entity.NewEnemy(entity.EnemyGoblin, 4, 2, 0)
+	east = []*entity.Enemy{
+		entity.NewEnemy(entity.EnemyOrc, 14, 1, 1),
+		entity.NewEnemy(entity.EnemySkeleton, 14, 3, 1),
+	}
+	g.enemies = append([]*entity.Enemy{goblin}, east...)
+	return g, goblin, east
+}
+
+// stepInPlace walks the party up and down for n steps.
+func stepInPlace(g *Game, n int) {
+	for i := range n {
+		if i%2 == 0 {
+			g.tryMove(context.Background(), 0, -1)
+		} else {
+			g.tryMove(context.Background(), 0, 1)
+		}
+	}
+}
+
+func TestFightDrawsNeighboringGroupThroughCorridor(t *testing.T) {
+	g, goblin, east := twoRoomFloor(t)
+	ctx := context.Background()
+
+	g.transitionState(ctx, StateCombat, "test")
+	if len(g.combatEnemies) != 1 {
+		t.Fatalf("combat enemies = %d, want only the goblin", len(g.combatEnemies))
+	}
+	for _, e := range east {
+		if !g.isInvestigating(e) {
+			t.Fatalf("%s didn't hear the fight", e.GetName())
+		}
+	}
+
+	goblin.HP = 0
+	g.combatState.Phase = PhaseVictory
+	g.handleCombatEnd(ctx)
+
+	stepInPlace(g, 6)
+	for _, e := range east {
+		if e.Y != 2 || e.X < 6 || e.X > 10 {
+			t.Errorf("%s at (%d,%d) after six turns, want it in the corridor", e.GetName(), e.X, e.Y)
+		}
+	}
+
+	stepInPlace(g, 8)
+	for _, e := range east {
+		if world.Distance(e.X, e.Y, 2, 2) > 2 {
+			t.Errorf("%s at (%d,%d) after fourteen turns, want it at the fight", e.GetName(), e.X, e.Y)
+		}
+	}
+}
+
+func TestWalkingDoesNotCarryToTheNextRoom(t *testing.T) {
+	g, _, east := twoRoomFloor(t)
+
+	stepInPlace(g, 4)
+	for _, e := range east {
+		if g.isInvestigating(e) || e.X != 14 {
+			t.Errorf("%s heard the party walking two rooms away", e.GetName())
+		}
+	}
+}
+
+// scoutTo sends a scout of the given class one step east onto (x, y).
+func scoutTo(t *testing.T, class entity.Class, x, y int) (*Game, []*entity.Enemy) {
+	t.Helper()
+	g, _, east := twoRoomFloor(t)
+	scout := g.party.Members[1]
+	scout.Class = class
+	scout.SetPosition(x-1, y)
+	g.party.Scout = scout
+	g.scoutControl = true
+	g.moveScout(1, 0)
+	return g, east
+}
+
+func TestRogueScoutSneaksCloserThanAWarrior(t *testing.T) {
+	// Two tiles from the orc
+	g, east := scoutTo(t, entity.ClassRogue, 12, 1)
+	if g.isInvestigating(east[0]) {
+		t.Error("the group heard a rogue step two tiles away")
+	}
+	g, east = scoutTo(t, entity.ClassWarrior, 12, 1)
+	if !g.isInvestigating(east[0]) {
+		t.Error("the group didn't hear a warrior step two tiles away")
+	}
+
+	// A rogue's steps still carry to the next tile
+	g, east = scoutTo(t, entity.ClassRogue, 13, 1)
+	if !g.isInvestigating(east[0]) {
+		t.Error("the group didn't hear a rogue step beside it")
+	}
+}
+
+func TestCorridorEnemiesInvestigateAlone(t *testing.T) {
+	g, _, _ := twoRoomFloor(t)
+	near := entity.NewEnemy(entity.EnemyGoblin, 7, 2, -1)
+	far := entity.NewEnemy(entity.EnemyGoblin, 10, 2, -1)
+	g.enemies = append(g.enemies, near, far)
+
+	g.makeNoise(6, 2, 1)
+	if !g.isInvestigating(near) {
+		t.Error("the goblin beside the noise didn't hear it")
+	}
+	if g.isInvestigating(far) {
+		t.Error("a corridor goblin out of earshot joined another's investigation")
+	}
+}
@@ -0,0 +1,100 @@
+package game
+
+import (
+	"context"
+	"strings"
+
+	"github.com/samdwyer/dungeonband/internal/combat"
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+)
+
+// plannedAction is a party member's choice for the round, held back until
+// the whole party has chosen when Config.PlanActions is set.
+type plannedAction struct {
+	member  *entity.Member
+	ability *gamedata.AbilityDef
+	targets []combat.Combatant
+}
+
+// planPlayerAction queues the active member's choice and hands the turn on.
+// Once the last member has chosen, the plan resolves.
+func (g *Game) planPlayerAction(ctx context.Context, ability *gamedata.AbilityDef, member *entity.Member, targets []combat.Combatant) {
+	cs := g.combatState
+	cs.planned = append(cs.planned, plannedAction{member: member, ability: ability, targets: targets})
+	cs.LastMessage = member.GetName() + " will use " + ability.Name + planTargetText(member, targets) + "."
+
+	g.advanceToNextPartyMember()
+	if cs.Phase == PhaseEnemyTurn {
+		g.executePlan(ctx)
+	}
+}
+
+// planTargetText describes who a planned action is aimed at.
+func planTargetText(member *entity.Member, targets []combat.Combatant) string {
+	switch {
+	case len(targets) != 1:
+		return ""
+	case targets[0] == member:
+		return ""
+	default:
+		return " on " + targets[0].GetName()
+	}
+}
+
+// executePlan resolves the party's planned actions in the order they were
+// chosen, then lets the enemies act. An action whose member has fallen is
+// dropped, as is a single-target one whose target is already down.
+func (g *Game) executePlan(ctx context.Context) {
+	cs := g.combatState
+	plan := cs.planned
+	cs.planned = nil
+
+	var messages []string
+	for _, action := range plan {
+		if !action.member.IsAlive() {
+			continue
+		}
+		targets := livingTargets(action.targets)
+		if len(targets) == 0 {
+			messages = append(messages, action.member.GetName()+"'s target is already down.")
+			continue
+		}
+		ended := g.resolvePlayerAction(ctx, action.ability, action.member, targets)
+		messages = append(messages, cs.LastMessage)
+		if ended {
+			// The victory or defeat message follows what led up to it
+			cs.LastMessage = strings.Join(messages, " ")
+			return
+		}
+	}
+	cs.LastMessage = strings.Join(messages, " ")
+
+	cs.Phase = PhaseEnemyTurn
+	cs.ActiveEnemyIndex = 0
+	g.executeEnemyTurns(ctx)
+}
+
+// plannedMP is the MP the member's planned actions will spend when the
+// plan resolves, so a member acting twice can't plan around MP it has
+// already promised.
+func (cs *CombatState) plannedMP(member *entity.Member) int {
+	mp := 0
+	for _, action := range cs.planned {
+		if action.member == member {
+			mp += combat.MPCost(action.ability, member)
+		}
+	}
+	return mp
+}
+
+// livingTargets returns the targets still alive.
+func livingTargets(targets []combat.Combatant) []combat.Combatant {
+	var alive []combat.Combatant
+	for _, t := range targets {
+		if t.IsAlive() {
+			alive = append(alive, t)
+		}
+	}
+	return alive
+}
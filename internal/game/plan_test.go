@@ -0,0 +1,91 @@
+package game
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/samdwyer/dungeonband/internal/combat"
+)
+
+func TestPlannedActionsResolveInOrderAfterLastMember(t *testing.T) {
+	g, goblin := startTurnOrderCombat(t)
+	g.cfg.PlanActions = true
+	recorder := recordSpans(t)
+	enter := tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)
+
+	// Everyone attacks the goblin
+	for i := range g.party.Members {
+		if g.combatState.ActiveMemberIndex != i {
+			t.Fatalf("member %d choosing, want %d", g.combatState.ActiveMemberIndex, i)
+		}
+		if findSpan(recorder, "combat.turn") != nil {
+			t.Fatalf("an action resolved before member %d chose", i)
+		}
+		press(g, '1')
+		g.handleKeyEvent(context.Background(), enter)
+	}
+
+	var actors []string
+	for _, span := range recorder.Ended() {
+		if span.Name() == "combat.turn" {
+			actors = append(actors, spanAttr(span, "actor").AsString())
+		}
+	}
+	want := []string{"Aldric", "Shade", "Zephyr", "Celeste", goblin.GetName()}
+	if len(actors) != len(want) {
+		t.Fatalf("turns = %v, want %v", actors, want)
+	}
+	for i := range want {
+		if actors[i] != want[i] {
+			t.Fatalf("turns = %v, want %v", actors, want)
+		}
+	}
+	if goblin.HP == goblin.MaxHP {
+		t.Error("planned attacks did no damage")
+	}
+	if g.combatState.Round != 2 || g.combatState.Phase != PhasePlayerTurn || len(g.combatState.planned) != 0 {
+		t.Errorf("round %d phase %v with %d planned, want a fresh round 2", g.combatState.Round, g.combatState.Phase, len(g.combatState.planned))
+	}
+}
+
+func TestPlannedCastsReserveMP(t *testing.T) {
+	g, goblin := startTurnOrderCombat(t)
+	g.cfg.PlanActions = true
+	wizard := g.party.Members[2]
+	fireball := g.abilityRegistry.GetByID("fireball")
+	wizard.MP = fireball.MPCost
+	if !g.canCast(wizard, fireball) {
+		t.Fatal("the wizard should afford one fireball")
+	}
+
+	// A hasted wizard's second pick can't spend the MP the first promised
+	g.combatState.planned = []plannedAction{{member: wizard, ability: fireball, targets: []combat.Combatant{goblin}}}
+	if g.canCast(wizard, fireball) {
+		t.Error("canCast = true with the only fireball's MP already planned")
+	}
+}
+
+func TestPlanKeepsActionMessagesWhenTheFightEnds(t *testing.T) {
+	g, goblin := startTurnOrderCombat(t)
+	g.cfg.PlanActions = true
+	goblin.HP = 1
+	defend := g.abilityRegistry.GetByID("defend")
+	attack := g.abilityRegistry.GetByID("attack")
+	aldric, shade := g.party.Members[0], g.party.Members[1]
+	g.combatState.planned = []plannedAction{
+		{member: aldric, ability: defend, targets: []combat.Combatant{aldric}},
+		{member: shade, ability: attack, targets: []combat.Combatant{goblin}},
+	}
+
+	g.executePlan(context.Background())
+	if g.combatState.Phase != PhaseVictory {
+		t.Fatalf("phase = %v, want victory", g.combatState.Phase)
+	}
+	msg := g.combatState.LastMessage
+	if !strings.Contains(msg, "Aldric") || !strings.Contains(msg, "Victory!") {
+		t.Errorf("LastMessage = %q, want Aldric's action before the victory", msg)
+	}
+}
@@ -0,0 +1,72 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/samdwyer/dungeonband/internal/audio"
+)
+
+// Profile is player progress that persists across runs.
+type Profile struct {
+	TutorialDone bool `json:"tutorialDone"` // Tutorial finished, so stop suggesting it
+	HideFlavor   bool `json:"hideFlavor"`   // Keep ambient flavor messages out of the log
+	ShowInitials bool `json:"showInitials"` // Draw the combat formation by member initials
+
+	// Audio configures sound cues; they are off unless enabled here.
+	Audio audio.Settings `json:"audio"`
+
+	// AbilityUses is how many times each ability has been cast across every run.
+	AbilityUses map[string]int `json:"abilityUses,omitempty"`
+}
+
+// AddAbilityUses adds a run's ability casts to the lifetime totals.
+func (p *Profile) AddAbilityUses(uses map[string]int) {
+	if len(uses) == 0 {
+		return
+	}
+	if p.AbilityUses == nil {
+		p.AbilityUses = make(map[string]int)
+	}
+	for id, n := range uses {
+		p.AbilityUses[id] += n
+	}
+}
+
+// ProfilePath returns where the profile is stored in the user's config directory.
+func ProfilePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "dungeonband", "profile.json"), nil
+}
+
+// LoadProfile reads the profile at path. A missing file is a fresh profile.
+func LoadProfile(path string) (Profile, error) {
+	var p Profile
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return p, err
+	}
+	err = json.Unmarshal(data, &p)
+	return p, err
+}
+
+// Save writes the profile to path, creating its directory if needed.
+func (p Profile) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,23 @@
+package game
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestProfileRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dungeonband", "profile.json")
+
+	fresh, err := LoadProfile(path)
+	if err != nil || fresh.TutorialDone {
+		t.Fatalf("missing profile = %+v, %v; want a fresh profile", fresh, err)
+	}
+
+	if err := (Profile{TutorialDone: true}).Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	loaded, err := LoadProfile(path)
+	if err != nil || !loaded.TutorialDone {
+		t.Errorf("loaded profile = %+v, %v; want tutorial done", loaded, err)
+	}
+}
@@ -0,0 +1,95 @@
+package game
+
+import (
+	"context"
+	"slices"
+
+	"github.com/samdwyer/dungeonband/internal/combat"
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+)
+
+// quickHeal casts the active member's cheapest heal on the most wounded
+// ally, skipping the menus. If the member has no heal they can cast or
+// nobody is hurt it says so and the turn isn't spent.
+func (g *Game) quickHeal(ctx context.Context) {
+	cs := g.combatState
+	if cs == nil || cs.Phase != PhasePlayerTurn {
+		return
+	}
+	member := g.getActiveMember()
+	if member == nil {
+		return
+	}
+	target := g.selectLowestHPWoundedMember()
+	if target == nil {
+		cs.LastMessage = "Nobody needs healing."
+		return
+	}
+	heal := g.quickHealAbility(member, target)
+	if heal == nil {
+		cs.LastMessage = member.GetName() + " has no heal to cast on " + target.GetName() + "."
+		return
+	}
+
+	if heal.TargetType == gamedata.TargetAllAllies {
+		g.performPlayerAction(ctx, heal, member, g.groupTargets(heal)...)
+		return
+	}
+	g.performPlayerAction(ctx, heal, member, target)
+}
+
+// quickHealAbility returns the member's cheapest heal that can reach the
+// target and that they can cast right now, by the same rules as the ability
+// menu, or nil if there is none. Ties go to the heal listed first in
+// abilities.json.
+func (g *Game) quickHealAbility(member, target *entity.Member) *gamedata.AbilityDef {
+	usable := g.usableBy(member)
+	var best *gamedata.AbilityDef
+	for _, a := range g.abilityRegistry.ByEffectType(gamedata.EffectHeal) {
+		if !slices.Contains(usable, a) || !healReaches(a, member, target) {
+			continue
+		}
+		if best == nil || combat.MPCost(a, member) < combat.MPCost(best, member) {
+			best = a
+		}
+	}
+	return best
+}
+
+// healReaches returns true if the heal, cast by member, would reach target.
+func healReaches(heal *gamedata.AbilityDef, member, target *entity.Member) bool {
+	switch heal.TargetType {
+	case gamedata.TargetSingleAlly, gamedata.TargetAllAllies:
+		return true
+	case gamedata.TargetSelf:
+		return member == target
+	default:
+		return false
+	}
+}
+
+// knowsHeal returns true if the member has any heal in their combat
+// abilities, usable now or not.
+func (g *Game) knowsHeal(m *entity.Member) bool {
+	for _, a := range g.combatAbilities(m) {
+		if a.EffectType == gamedata.EffectHeal {
+			return true
+		}
+	}
+	return false
+}
+
+// selectLowestHPWoundedMember returns the living member with the least HP
+// among those missing some, or nil if nobody is hurt.
+func (g *Game) selectLowestHPWoundedMember() *entity.Member {
+	var lowest *entity.Member
+	for _, m := range g.party.Members {
+		if m.IsAlive() && m.GetHP() < m.GetMaxHP() {
+			if lowest == nil || m.GetHP() < lowest.GetHP() {
+				lowest = m
+			}
+		}
+	}
+	return lowest
+}
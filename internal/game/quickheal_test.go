@@ -0,0 +1,82 @@
+package game
+
+import (
+	"context"
+	"testing"
+
+	"github.com/samdwyer/dungeonband/internal/combat"
+)
+
+func TestQuickHealHealsMostWoundedAlly(t *testing.T) {
+	g, _ := startTurnOrderCombat(t)
+	defend := g.abilityRegistry.GetByID("defend")
+	for _, m := range g.party.Members[:3] {
+		g.performPlayerAction(context.Background(), defend, m, m)
+	}
+	cleric := g.party.Members[3]
+	if g.getActiveMember() != cleric {
+		t.Fatalf("active member = %s, want the cleric", g.getActiveMember().GetName())
+	}
+	cleric.MP = cleric.MaxMP
+	g.party.Members[0].HP = 500
+	wounded := g.party.Members[1]
+	wounded.HP = 200
+
+	press(g, 'H')
+
+	if wounded.HP <= 200 {
+		t.Errorf("%s HP = %d, want the quick heal to land on them", wounded.GetName(), wounded.HP)
+	}
+	if g.party.Members[0].HP > 500 {
+		t.Errorf("%s HP = %d, want only the most wounded healed", g.party.Members[0].GetName(), g.party.Members[0].HP)
+	}
+	heal := g.abilityRegistry.GetByID("heal")
+	if spent := cleric.MaxMP - cleric.MP; spent != heal.MPCost {
+		t.Errorf("cleric spent %d MP, want %d for the cheaper single heal", spent, heal.MPCost)
+	}
+	if g.combatState.Round != 2 {
+		t.Errorf("round = %d, want the cleric's turn spent and round 2 begun", g.combatState.Round)
+	}
+}
+
+func TestQuickHealRespectsPlannedMP(t *testing.T) {
+	g, _ := startTurnOrderCombat(t)
+	g.cfg.PlanActions = true
+	cleric := g.party.Members[3]
+	heal := g.abilityRegistry.GetByID("heal")
+	cleric.MP = heal.MPCost
+	wounded := g.party.Members[1]
+	wounded.HP = 200
+
+	// A hasted cleric has already planned the one heal their MP covers
+	g.combatState.planned = []plannedAction{{member: cleric, ability: heal, targets: []combat.Combatant{wounded}}}
+	if got := g.quickHealAbility(cleric, wounded); got != nil {
+		t.Errorf("quick heal picked %s with its MP already planned", got.ID)
+	}
+}
+
+func TestQuickHealWithoutHealKeepsTurn(t *testing.T) {
+	g, _ := startTurnOrderCombat(t)
+	warrior := g.party.Members[0]
+	g.party.Members[2].HP = 100
+
+	press(g, 'H')
+	if g.getActiveMember() != warrior || g.combatState.LastMessage == "" {
+		t.Errorf("active = %s, message %q; want the warrior still choosing and told why",
+			g.getActiveMember().GetName(), g.combatState.LastMessage)
+	}
+
+	// Nobody hurt: the healer keeps their turn too
+	for _, m := range g.party.Members[:3] {
+		g.performPlayerAction(context.Background(), g.abilityRegistry.GetByID("defend"), m, m)
+	}
+	for _, m := range g.party.Members {
+		m.HP = m.MaxHP
+	}
+	cleric := g.getActiveMember()
+	press(g, 'H')
+	if g.getActiveMember() != cleric || g.combatState.LastMessage != "Nobody needs healing." {
+		t.Errorf("active = %s, message %q; want the cleric still choosing and told nobody needs healing",
+			g.getActiveMember().GetName(), g.combatState.LastMessage)
+	}
+}
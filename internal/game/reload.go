@@ -0,0 +1,85 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+	"github.com/samdwyer/dungeonband/internal/telemetry"
+)
+
+// registries is one loaded set of the game's data registries.
+type registries struct {
+	abilities *gamedata.AbilityRegistry
+	classes   *gamedata.ClassRegistry
+	enemies   *gamedata.EnemyRegistry
+}
+
+// loadRegistries builds the registries from the embedded data merged with
+// the overrides in dir. Data that fails validation is rejected whole.
+func loadRegistries(dir string) (registries, error) {
+	d, err := gamedata.LoadData(dir)
+	if err != nil {
+		return registries{}, err
+	}
+	for _, w := range d.Warnings {
+		log.Printf("Warning: %s", w)
+	}
+	if issues := gamedata.ValidateAll(d); len(issues) > 0 {
+		return registries{}, fmt.Errorf("invalid data (%d issues): %s", len(issues), issues[0])
+	}
+	return registries{
+		abilities: gamedata.NewAbilityRegistry(d.Abilities),
+		classes:   gamedata.NewClassRegistry(d.Classes),
+		enemies:   gamedata.NewEnemyRegistry(d.Enemies),
+	}, nil
+}
+
+// reloadData re-reads the data directory and swaps the result into the
+// running game, so edits to its JSON take effect without restarting. A
+// reload that fails keeps the current data.
+func (g *Game) reloadData(ctx context.Context) {
+	if g.cfg.DataDir == "" {
+		g.setMessage("No data directory to reload from.")
+		return
+	}
+
+	tracer := telemetry.Tracer("game")
+	_, span := tracer.Start(ctx, "game.reload_data")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("data_dir", g.cfg.DataDir),
+		attribute.String("state", g.state.String()),
+	)
+
+	r, err := loadRegistries(g.cfg.DataDir)
+	if err != nil {
+		span.SetAttributes(attribute.String("error", err.Error()))
+		g.setMessage("Reload failed: " + err.Error())
+		return
+	}
+	g.swapRegistries(r)
+	g.setMessage("Reloaded game data from " + g.cfg.DataDir + ".")
+}
+
+// swapRegistries replaces the game's registries. Enemies already on the
+// floor, including any in a fight, keep their HP but take their new
+// definitions; abilities are looked up afresh on every use.
+func (g *Game) swapRegistries(r registries) {
+	g.abilityRegistry = r.abilities
+	g.classRegistry = r.classes
+	g.enemyRegistry = r.enemies
+	g.effectResolver.SetAbilityRegistry(r.abilities)
+
+	for _, e := range g.enemies {
+		if e.Def == nil {
+			continue
+		}
+		if def := r.enemies.GetByID(e.Def.ID); def != nil {
+			e.Def = def
+		}
+	}
+}
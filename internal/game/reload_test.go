@@ -0,0 +1,86 @@
+package game
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+)
+
+// writeAttackOverride writes an abilities override giving "attack" the
+// base power.
+func writeAttackOverride(t *testing.T, dir, power string) {
+	t.Helper()
+	content := `{"schemaVersion": 2, "abilities": [{"id": "attack", "name": "Attack", "description": "A basic physical attack",
+		"effectType": "damage", "targetType": "single_enemy", "damageType": "physical", "basePower": ` + power + `}]}`
+	if err := os.WriteFile(filepath.Join(dir, gamedata.AbilitiesFileName), []byte(content), 0o644); err != nil {
+		t.Fatalf("write override: %v", err)
+	}
+}
+
+func pressReload(g *Game) {
+	g.handleKeyEvent(context.Background(), tcell.NewEventKey(tcell.KeyCtrlR, 0, tcell.ModCtrl))
+}
+
+func TestReloadSwapsInNewAbilityDefinitions(t *testing.T) {
+	g, goblin := startTurnOrderCombat(t)
+	g.cfg.DataDir = t.TempDir()
+	g.cfg.Debug = true
+	warrior := g.party.Members[0]
+	goblin.HP = 900
+
+	old := g.effectResolver.CalculateDamage(g.abilityRegistry.GetByID("attack"), warrior, goblin)
+	writeAttackOverride(t, g.cfg.DataDir, "200")
+	pressReload(g)
+
+	if goblin.HP != 900 {
+		t.Errorf("goblin HP = %d after reloading, want it kept at 900", goblin.HP)
+	}
+	attack := g.abilityRegistry.GetByID("attack")
+	if attack.BasePower != 200 {
+		t.Fatalf("attack base power = %d after reloading, want 200", attack.BasePower)
+	}
+	want := g.effectResolver.CalculateDamage(attack, warrior, goblin)
+	if want <= old {
+		t.Fatalf("reloaded attack damage = %d, want more than the old %d", want, old)
+	}
+
+	g.performPlayerAction(context.Background(), attack, warrior, goblin)
+	if got := 900 - goblin.HP; got != want {
+		t.Errorf("attack dealt %d after reloading, want %d", got, want)
+	}
+}
+
+func TestReloadKeepsCurrentDataWhenTheNewDataIsInvalid(t *testing.T) {
+	g := newTestGame(t)
+	g.cfg.DataDir = t.TempDir()
+	g.cfg.Debug = true
+	before := g.abilityRegistry
+
+	writeAttackOverride(t, g.cfg.DataDir, `"strong"`)
+	pressReload(g)
+
+	if g.abilityRegistry != before {
+		t.Error("a failed reload replaced the ability registry")
+	}
+	if g.message == "" {
+		t.Error("a failed reload left no message")
+	}
+}
+
+func TestReloadNeedsDebugMode(t *testing.T) {
+	g := newTestGame(t)
+	g.cfg.DataDir = t.TempDir()
+	before := g.abilityRegistry
+
+	writeAttackOverride(t, g.cfg.DataDir, "200")
+	pressReload(g)
+
+	if g.abilityRegistry != before {
+		t.Error("reloaded data outside debug mode")
+	}
+}
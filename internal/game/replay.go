@@ -0,0 +1,284 @@
+package game
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/samdwyer/dungeonband/internal/combat"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+	"github.com/samdwyer/dungeonband/internal/telemetry"
+)
+
+// replayUnit is one combatant as the replay sees it at some step.
+type replayUnit struct {
+	Name     string
+	HP       int
+	MaxHP    int
+	Statuses []gamedata.StatusEffectType
+}
+
+// combatEvent is one change to a combatant during an encounter. Units are
+// indices into the recording's unit list.
+type combatEvent struct {
+	Turn        int
+	Target      int
+	Damage      int
+	Healing     int
+	StatusAdded gamedata.StatusEffectType
+	Cleansed    bool // Negative statuses were removed
+	Tick        gamedata.StatusEffectType
+	TickEnded   bool
+	Spawn       *replayUnit // A combatant joining mid-fight; Target is its new index
+	Message     string
+}
+
+// combatRecording is the event log of one encounter: who was in it when it
+// started and everything that happened to them since.
+type combatRecording struct {
+	EncounterID string
+	Start       []replayUnit
+	Events      []combatEvent
+	units       map[combat.Combatant]int
+}
+
+// newCombatRecording snapshots the combatants, party first.
+func newCombatRecording(encounterID string, combatants []combat.Combatant) *combatRecording {
+	r := &combatRecording{EncounterID: encounterID, units: map[combat.Combatant]int{}}
+	for _, c := range combatants {
+		r.units[c] = len(r.Start)
+		r.Start = append(r.Start, snapshotUnit(c))
+	}
+	return r
+}
+
+// snapshotUnit captures a combatant's current HP and statuses.
+func snapshotUnit(c combat.Combatant) replayUnit {
+	u := replayUnit{Name: c.GetName(), HP: c.GetHP(), MaxHP: c.GetMaxHP()}
+	for _, e := range c.GetStatusEffects() {
+		u.Statuses = append(u.Statuses, e.Type)
+	}
+	return u
+}
+
+// unit returns a combatant's index, recording a spawn event for one that
+// joined after the start.
+func (r *combatRecording) unit(c combat.Combatant, turn int) int {
+	if i, ok := r.units[c]; ok {
+		return i
+	}
+	i := len(r.units)
+	r.units[c] = i
+	spawn := snapshotUnit(c)
+	r.Events = append(r.Events, combatEvent{
+		Turn:    turn,
+		Target:  i,
+		Spawn:   &spawn,
+		Message: c.GetName() + " joins the fight.",
+	})
+	return i
+}
+
+// action records the outcome of an ability or effect on one target.
+func (r *combatRecording) action(turn int, ability *gamedata.AbilityDef, target combat.Combatant, result combat.EffectResult) {
+	if !result.Success {
+		return
+	}
+	r.Events = append(r.Events, combatEvent{
+		Turn:        turn,
+		Target:      r.unit(target, turn),
+		Damage:      result.Damage,
+		Healing:     result.Healing,
+		StatusAdded: result.StatusAdded,
+		Cleansed:    ability != nil && ability.EffectType == gamedata.EffectCleanse,
+		Message:     eventMessage(result, target),
+	})
+}
+
+// tick records a status effect tick on a combatant.
+func (r *combatRecording) tick(turn int, target combat.Combatant, tick combat.StatusTick) {
+	message := ""
+	switch tick.Type {
+	case gamedata.StatusPoison:
+		message = target.GetName() + " takes " + itoa(tick.Amount) + " poison damage."
+	case gamedata.StatusRegen:
+		message = target.GetName() + " regenerates " + itoa(tick.Amount) + " HP."
+	}
+	if tick.Ended {
+		message = strings.TrimSpace(message + " " + target.GetName() + "'s " + string(tick.Type) + " wears off.")
+	}
+	if message == "" {
+		return
+	}
+	r.Events = append(r.Events, combatEvent{
+		Turn:      turn,
+		Target:    r.unit(target, turn),
+		Tick:      tick.Type,
+		Damage:    tickAmount(tick, gamedata.StatusPoison),
+		Healing:   tickAmount(tick, gamedata.StatusRegen),
+		TickEnded: tick.Ended,
+		Message:   message,
+	})
+}
+
+// tickAmount is the tick's amount if it is of the given status, else 0.
+func tickAmount(tick combat.StatusTick, status gamedata.StatusEffectType) int {
+	if tick.Type != status {
+		return 0
+	}
+	return tick.Amount
+}
+
+// eventMessage describes an action's effect on its target.
+func eventMessage(result combat.EffectResult, target combat.Combatant) string {
+	message := result.Message
+	switch {
+	case result.Damage > 0:
+		message += " " + target.GetName() + " takes " + itoa(result.Damage) + " damage."
+	case result.Healing > 0:
+		message += " " + target.GetName() + " recovers " + itoa(result.Healing) + " HP."
+	case result.StatusAdded != "" && message == "":
+		message = target.GetName() + " gains " + string(result.StatusAdded) + "."
+	}
+	return strings.TrimSpace(message)
+}
+
+// stateAt re-derives every combatant's HP and statuses after the first steps
+// events, from the starting snapshot alone.
+func (r *combatRecording) stateAt(steps int) []replayUnit {
+	units := make([]replayUnit, len(r.Start))
+	for i, u := range r.Start {
+		units[i] = u
+		units[i].Statuses = slices.Clone(u.Statuses)
+	}
+	for _, ev := range r.Events[:steps] {
+		if ev.Spawn != nil {
+			spawn := *ev.Spawn
+			spawn.Statuses = slices.Clone(spawn.Statuses)
+			units = append(units, spawn)
+			continue
+		}
+		u := &units[ev.Target]
+		u.HP = min(max(u.HP-ev.Damage+ev.Healing, 0), u.MaxHP)
+		if ev.Cleansed {
+			u.Statuses = slices.DeleteFunc(u.Statuses, gamedata.StatusEffectType.IsNegative)
+		}
+		if ev.StatusAdded != "" && !slices.Contains(u.Statuses, ev.StatusAdded) {
+			u.Statuses = append(u.Statuses, ev.StatusAdded)
+		}
+		if ev.TickEnded {
+			u.Statuses = slices.DeleteFunc(u.Statuses, func(s gamedata.StatusEffectType) bool { return s == ev.Tick })
+		}
+	}
+	return units
+}
+
+// truncate drops every event after the first n, forgetting any combatant
+// whose arrival was among them.
+func (r *combatRecording) truncate(n int) {
+	for _, ev := range r.Events[n:] {
+		if ev.Spawn == nil {
+			continue
+		}
+		for c, i := range r.units {
+			if i == ev.Target {
+				delete(r.units, c)
+			}
+		}
+	}
+	r.Events = r.Events[:n]
+}
+
+// startRecording begins the event log for the encounter that is starting.
+func (g *Game) startRecording() {
+	var combatants []combat.Combatant
+	for _, m := range g.party.Members {
+		combatants = append(combatants, m)
+	}
+	for _, e := range g.combatState.Enemies {
+		combatants = append(combatants, e)
+	}
+	g.recording = newCombatRecording(g.combatState.EncounterID, combatants)
+}
+
+// noteAction credits a resolved ability in the stats and adds it to the
+// encounter's event log. ability is nil for on-death effects.
+func (g *Game) noteAction(ability *gamedata.AbilityDef, user, target combat.Combatant, result combat.EffectResult, killed bool) {
+	g.stats.recordAction(user, target, result, killed)
+	if g.recording != nil {
+		g.recording.action(g.combatState.TurnCount, ability, target, result)
+	}
+}
+
+// noteTick credits a status effect tick in the stats and adds it to the
+// encounter's event log.
+func (g *Game) noteTick(target combat.Combatant, tick combat.StatusTick, killed bool) {
+	g.stats.recordTick(target, tick, killed)
+	if g.recording != nil {
+		g.recording.tick(g.combatState.TurnCount, target, tick)
+	}
+}
+
+// fullLogMarker ends a fight's summary line, pointing at the replay that
+// expands it into the full combat log.
+const fullLogMarker = " [v: full log]"
+
+// replayViewer steps through the last finished encounter.
+type replayViewer struct {
+	recording *combatRecording
+	step      int // Events shown so far
+}
+
+// openReplay starts replaying the last finished encounter from its start.
+func (g *Game) openReplay(ctx context.Context) {
+	if g.lastFight == nil {
+		g.setMessage("No fight to replay yet.")
+		return
+	}
+	g.replay = &replayViewer{recording: g.lastFight}
+
+	tracer := telemetry.Tracer("game")
+	_, span := tracer.Start(ctx, "game.replay")
+	span.SetAttributes(
+		attribute.String("encounter.id", g.lastFight.EncounterID),
+		attribute.Int("events", len(g.lastFight.Events)),
+	)
+	span.End()
+}
+
+// handleReplayKey advances the replay one event per key press; Escape or
+// a press past the last event closes it.
+func (g *Game) handleReplayKey(ev *tcell.EventKey) {
+	if ev.Key() == tcell.KeyEscape || g.replay.step >= len(g.replay.recording.Events) {
+		g.replay = nil
+		return
+	}
+	g.replay.step++
+}
+
+// replayOverlay draws the current replay frame.
+func (g *Game) replayOverlay() Overlay {
+	rec := g.replay.recording
+	step := g.replay.step
+	title := "Replay " + itoa(step) + "/" + itoa(len(rec.Events))
+
+	lines := []string{"The fight begins."}
+	if step > 0 {
+		ev := rec.Events[step-1]
+		lines = []string{"Turn " + itoa(ev.Turn+1) + ": " + ev.Message}
+	}
+	for _, u := range rec.stateAt(step) {
+		line := u.Name + " " + itoa(u.HP) + "/" + itoa(u.MaxHP)
+		for _, s := range u.Statuses {
+			line += " [" + string(s) + "]"
+		}
+		lines = append(lines, line)
+	}
+	if step == len(rec.Events) {
+		lines = append(lines, "End of fight.")
+	}
+	return Overlay{Kind: OverlayInstruction, Title: title, Text: strings.Join(lines, "\n")}
+}
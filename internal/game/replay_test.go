@@ -0,0 +1,81 @@
+package game
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/samdwyer/dungeonband/internal/combat"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+)
+
+func TestReplayReconstructsFinalHP(t *testing.T) {
+	g := newBenchGame(t)
+	startEncounter(g)
+	// Poison on the warrior puts status ticks in the log too
+	g.party.Members[0].AddStatusEffect(combat.StatusEffect{Type: gamedata.StatusPoison, RemainingTurns: 3, Power: 2})
+	g.startRecording()
+
+	if phase := runEncounter(g); phase != PhaseVictory && phase != PhaseDefeat {
+		t.Fatalf("encounter ended in phase %v", phase)
+	}
+	var live []combat.Combatant
+	for _, m := range g.party.Members {
+		live = append(live, m)
+	}
+	for _, e := range g.combatState.Enemies {
+		live = append(live, e)
+	}
+	g.endCombat(context.Background(), "victory")
+
+	rec := g.lastFight
+	if rec == nil || len(rec.Events) == 0 {
+		t.Fatal("no events recorded for the finished fight")
+	}
+	if !slices.ContainsFunc(rec.Events, func(ev combatEvent) bool { return ev.Tick == gamedata.StatusPoison }) {
+		t.Error("poison ticks missing from the log")
+	}
+	final := rec.stateAt(len(rec.Events))
+	if len(final) != len(live) {
+		t.Fatalf("replay has %d combatants, live fight had %d", len(final), len(live))
+	}
+	for i, c := range live {
+		if final[i].Name != c.GetName() || final[i].HP != c.GetHP() {
+			t.Errorf("replayed %s at %d HP, live fight left %s at %d", final[i].Name, final[i].HP, c.GetName(), c.GetHP())
+		}
+	}
+}
+
+func TestReplayStepsThroughEventsOnKeypress(t *testing.T) {
+	g := newBenchGame(t)
+	startEncounter(g)
+	runEncounter(g)
+	g.endCombat(context.Background(), "victory")
+	g.state = StateExplore
+	events := len(g.lastFight.Events)
+
+	press(g, 'v')
+	if g.replay == nil {
+		t.Fatal("'v' did not open the replay")
+	}
+	if o := g.replayOverlay(); o.Title != "Replay 0/"+itoa(events) || !strings.Contains(o.Text, "The fight begins.") {
+		t.Errorf("first frame = %q %q", o.Title, o.Text)
+	}
+
+	press(g, ' ')
+	if o := g.replayOverlay(); o.Title != "Replay 1/"+itoa(events) || !strings.Contains(o.Text, g.lastFight.Events[0].Message) {
+		t.Errorf("second frame = %q %q", o.Title, o.Text)
+	}
+
+	for i := 1; i < events; i++ {
+		press(g, ' ')
+	}
+	if !strings.Contains(g.replayOverlay().Text, "End of fight.") {
+		t.Error("last frame does not mark the end of the fight")
+	}
+	press(g, ' ')
+	if g.replay != nil {
+		t.Error("a key past the last event should close the replay")
+	}
+}
@@ -0,0 +1,175 @@
+package game
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/samdwyer/dungeonband/internal/combat"
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/telemetry"
+)
+
+// encounterSnapshot is the state a fight started from: the party's stats,
+// the enemies with their rolled variants, and how many encounters came
+// before it, which picks the fight's combat RNG stream. The room's theme
+// follows from the party's position. Restoring it replays the fight from
+// its first round.
+type encounterSnapshot struct {
+	encounters   int
+	party        *entity.Party
+	partyX       int
+	partyY       int
+	scout        *entity.Member
+	scoutControl bool
+	items        map[entity.Item]int
+	members      []memberSnapshot
+	enemies      []enemySnapshot
+	turns        int
+}
+
+// memberSnapshot is one member as the fight found them.
+type memberSnapshot struct {
+	member   *entity.Member
+	state    entity.Member
+	uses     map[string]int
+	statuses []combat.StatusEffect
+}
+
+// enemySnapshot is one enemy on the floor as the fight found it.
+type enemySnapshot struct {
+	enemy    *entity.Enemy
+	state    entity.Enemy
+	statuses []combat.StatusEffect
+}
+
+// captureEncounter records the state a fight is about to start from. It
+// runs before the party splits for a scout's fight, so a retry goes
+// through the same setup.
+func (g *Game) captureEncounter() *encounterSnapshot {
+	snap := &encounterSnapshot{
+		encounters:   g.encounters,
+		party:        g.party,
+		partyX:       g.party.X,
+		partyY:       g.party.Y,
+		scout:        g.party.Scout,
+		scoutControl: g.scoutControl,
+		items:        maps.Clone(g.party.Items),
+		members:      snapshotMembers(g.party.Members),
+		enemies:      snapshotEnemies(g.enemies),
+		turns:        g.turns,
+	}
+	return snap
+}
+
+// snapshotMembers records each member's stats, ability uses and statuses.
+func snapshotMembers(members []*entity.Member) []memberSnapshot {
+	snaps := make([]memberSnapshot, 0, len(members))
+	for _, m := range members {
+		snaps = append(snaps, memberSnapshot{
+			member:   m,
+			state:    *m,
+			uses:     maps.Clone(m.AbilityUses),
+			statuses: slices.Clone(m.GetStatusEffects()),
+		})
+	}
+	return snaps
+}
+
+// restoreMembers puts each member back as recorded and returns them in
+// order.
+func restoreMembers(snaps []memberSnapshot) []*entity.Member {
+	members := make([]*entity.Member, 0, len(snaps))
+	for _, s := range snaps {
+		m := s.member
+		*m = s.state
+		m.AbilityUses = maps.Clone(s.uses)
+		m.ClearStatusEffects()
+		for _, effect := range s.statuses {
+			m.AddStatusEffect(effect)
+		}
+		members = append(members, m)
+	}
+	return members
+}
+
+// snapshotEnemies records each enemy's stats and statuses.
+func snapshotEnemies(enemies []*entity.Enemy) []enemySnapshot {
+	snaps := make([]enemySnapshot, 0, len(enemies))
+	for _, e := range enemies {
+		snaps = append(snaps, enemySnapshot{
+			enemy:    e,
+			state:    *e,
+			statuses: slices.Clone(e.GetStatusEffects()),
+		})
+	}
+	return snaps
+}
+
+// restoreEnemies puts each enemy back as recorded and returns them in
+// order.
+func restoreEnemies(snaps []enemySnapshot) []*entity.Enemy {
+	enemies := make([]*entity.Enemy, 0, len(snaps))
+	for _, s := range snaps {
+		e := s.enemy
+		*e = s.state
+		e.ClearStatusEffects()
+		for _, effect := range s.statuses {
+			e.AddStatusEffect(effect)
+		}
+		enemies = append(enemies, e)
+	}
+	return enemies
+}
+
+// restoreEncounter puts the party and the floor's enemies back as the
+// snapshot found them.
+func (g *Game) restoreEncounter(snap *encounterSnapshot) {
+	g.encounters = snap.encounters
+	g.turns = snap.turns
+	g.party = snap.party
+	g.waitingParty = nil
+	g.party.SetPosition(snap.partyX, snap.partyY)
+	g.party.Scout = snap.scout
+	g.scoutControl = snap.scoutControl
+	g.party.Items = maps.Clone(snap.items)
+	g.party.Members = restoreMembers(snap.members)
+	g.enemies = restoreEnemies(snap.enemies)
+}
+
+// retryEncounter restores the fight the party just lost and starts it
+// again. The run is marked as practice from then on.
+//
+// Casts made in the lost attempt are dropped on purpose, from mastery and
+// from the lifetime totals alike: members go back in with the AbilityUses
+// they started the fight with, so the same choices replay the same fight.
+// Keeping them could cross a mastery tier and change costs and damage.
+func (g *Game) retryEncounter(ctx context.Context) {
+	snap := g.encounterStart
+	if snap == nil {
+		return
+	}
+
+	tracer := telemetry.Tracer("game")
+	ctx, span := tracer.Start(ctx, "game.retry_encounter")
+	defer span.End()
+	span.SetAttributes(
+		attribute.Int64("seed", g.seed),
+		attribute.Int("floor", g.floor),
+		attribute.Int("encounter", snap.encounters+1),
+	)
+
+	g.restoreEncounter(snap)
+	g.gameOver = false
+	g.practice = true
+	g.message = "Retrying the encounter. This run is now practice."
+	g.transitionState(ctx, StateCombat, "retry")
+}
+
+// Practice reports whether a lost fight was retried this run, so the run
+// doesn't count as a real attempt.
+func (g *Game) Practice() bool {
+	return g.practice
+}
@@ -0,0 +1,114 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/world"
+)
+
+// fightToDefeat has every member attack the goblin until the party falls,
+// logging both sides' HP and the goblin's actions after each action.
+func fightToDefeat(t *testing.T, g *Game) []string {
+	t.Helper()
+	attack := g.abilityRegistry.GetByID("attack")
+	var log []string
+	for i := 0; g.combatState.Phase == PhasePlayerTurn; i++ {
+		if i > 500 {
+			t.Fatal("party never fell")
+		}
+		member := g.getActiveMember()
+		g.performPlayerAction(context.Background(), attack, member, g.combatEnemies[0])
+		hp := fmt.Sprint(g.combatEnemies[0].HP)
+		for _, m := range g.party.Members {
+			hp += fmt.Sprintf(" %d", m.HP)
+		}
+		log = append(log, hp+fmt.Sprint(g.combatState.EnemyActions))
+	}
+	if g.combatState.Phase != PhaseDefeat {
+		t.Fatalf("phase = %v, want defeat", g.combatState.Phase)
+	}
+	g.handleCombatEnd(context.Background())
+	return log
+}
+
+// startLosingFight starts a fight the party can't win.
+func startLosingFight(t *testing.T) *Game {
+	t.Helper()
+	g := newLosingFight(t)
+	g.transitionState(context.Background(), StateCombat, "test")
+	return g
+}
+
+// newLosingFight sets up a fight the party can't win, ready to start.
+func newLosingFight(t *testing.T) *Game {
+	t.Helper()
+	g := newTestGame(t)
+	g.dungeon = dungeonFromMap(`
+#######
+#.....#
+#######`)
+	g.dungeon.Rooms = []world.Room{{X: 1, Y: 1, Width: 5, Height: 1}}
+	g.party.SetPosition(1, 1)
+	for _, m := range g.party.Members {
+		m.HP = 12
+	}
+	goblin := entity.NewEnemyFromDef(g.enemyRegistry.GetByID("goblin"), 4, 1, 0)
+	goblin.HP, goblin.MaxHP = 1000, 1000
+	g.enemies = []*entity.Enemy{goblin}
+	return g
+}
+
+func TestRetriedEncounterPlaysOutIdentically(t *testing.T) {
+	g := startLosingFight(t)
+	uses := g.AbilityUses()
+	first := fightToDefeat(t, g)
+	if !g.gameOver {
+		t.Fatal("game-over prompt not open after the party fell")
+	}
+	if g.gameOverPrompt() != encounterRetryPrompt {
+		t.Errorf("prompt = %q, want the encounter retry offered", g.gameOverPrompt())
+	}
+
+	g.handleKeyEvent(context.Background(), tcell.NewEventKey(tcell.KeyRune, 'e', tcell.ModNone))
+	if g.gameOver || g.state != StateCombat {
+		t.Fatalf("gameOver = %v, state = %v after retrying; want back in combat", g.gameOver, g.state)
+	}
+	if !g.Practice() {
+		t.Error("retried run not marked as practice")
+	}
+	if got := g.AbilityUses(); !maps.Equal(got, uses) {
+		t.Errorf("ability uses after retrying = %v, want the lost attempt's dropped: %v", got, uses)
+	}
+
+	second := fightToDefeat(t, g)
+	if fmt.Sprint(first) != fmt.Sprint(second) {
+		t.Errorf("retry played out differently:\nfirst:  %v\nsecond: %v", first, second)
+	}
+}
+
+func TestFallingOutsideAFightOffersNoEncounterRetry(t *testing.T) {
+	g := newTestGame(t)
+	g.dungeon = dungeonFromMap(`
+#####
+#...#
+#####`)
+	g.party.SetPosition(1, 1)
+	g.enemies = nil
+	g.encounterStart = g.captureEncounter()
+	for _, m := range g.party.Members {
+		m.HP = 0
+	}
+	g.tryMove(context.Background(), 1, 0)
+	if !g.gameOver {
+		t.Fatal("game-over prompt not open after the party fell")
+	}
+	if g.gameOverPrompt() != gameOverPrompt {
+		t.Errorf("prompt = %q, want no encounter retry", g.gameOverPrompt())
+	}
+}
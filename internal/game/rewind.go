@@ -0,0 +1,173 @@
+package game
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/seed"
+	"github.com/samdwyer/dungeonband/internal/telemetry"
+)
+
+// rewindsPerEncounter is how many turns the party can take back in one
+// fight. Hardcore runs get none.
+const rewindsPerEncounter = 3
+
+// turnSnapshot is the fight as it stood before a member's action resolved:
+// everyone's HP, MP and statuses, the combat phase and queue, and how far
+// the dice had rolled, so a rewound turn plays out the same if it is taken
+// again.
+type turnSnapshot struct {
+	combat        *CombatState
+	combatEnemies []*entity.Enemy
+	activeMember  int
+	members       []memberSnapshot // Every member, including any still on the way to a scout's fight
+	partyMembers  []*entity.Member
+	waitingX      int
+	waitingY      int
+	enemies       []enemySnapshot
+	items         map[entity.Item]int
+	diceDrawn     int
+	rngDraws      uint64
+	tally         combatTally
+	events        int
+	corpses       int
+	investigating map[groupKey]investigation
+	turns         int
+}
+
+// captureTurn records the fight before the active member's action
+// resolves, if the party can still rewind.
+func (g *Game) captureTurn() {
+	g.lastTurn = nil
+	if g.rewinds <= 0 || g.combatState == nil {
+		return
+	}
+	members := g.party.Members
+	if main := g.waitingParty; main != nil {
+		members = main.Members
+	}
+	snap := &turnSnapshot{
+		combat:        g.combatState.clone(),
+		combatEnemies: slices.Clone(g.combatEnemies),
+		activeMember:  g.activeMemberIndex,
+		members:       snapshotMembers(members),
+		partyMembers:  slices.Clone(g.party.Members),
+		enemies:       snapshotEnemies(g.enemies),
+		items:         maps.Clone(g.party.Items),
+		diceDrawn:     g.dice.Drawn(),
+		tally:         g.stats.saveCombat(),
+		corpses:       len(g.dungeon.Corpses),
+		investigating: make(map[groupKey]investigation, len(g.investigations)),
+		turns:         g.turns,
+	}
+	if main := g.waitingParty; main != nil {
+		snap.waitingX, snap.waitingY = main.X, main.Y
+	}
+	if g.rngSource != nil {
+		snap.rngDraws = g.rngSource.draws
+	}
+	if g.recording != nil {
+		snap.events = len(g.recording.Events)
+	}
+	for group, inv := range g.investigations {
+		snap.investigating[group] = *inv
+	}
+	g.lastTurn = snap
+}
+
+// canRewind returns true if the last turn can be taken back: the fight is
+// waiting on the party or has just been lost, and rewinds are left.
+func (g *Game) canRewind() bool {
+	if g.state != StateCombat || g.combatState == nil || g.lastTurn == nil || g.rewinds <= 0 {
+		return false
+	}
+	phase := g.combatState.Phase
+	return phase == PhasePlayerTurn || phase == PhaseSelectTarget || phase == PhaseDefeat
+}
+
+// rewindsOffered returns how many rewinds the combat panel offers: none
+// until there is a turn to take back.
+func (g *Game) rewindsOffered() int {
+	if g.lastTurn == nil {
+		return 0
+	}
+	return g.rewinds
+}
+
+// rewindTurn takes back the last resolved turn, putting the fight back as
+// it was before the member acted.
+func (g *Game) rewindTurn(ctx context.Context) {
+	if !g.canRewind() {
+		return
+	}
+	snap := g.lastTurn
+
+	tracer := telemetry.Tracer("combat")
+	_, span := tracer.Start(ctx, "combat.rewind")
+	defer span.End()
+
+	g.combatState = snap.combat
+	g.combatEnemies = snap.combatEnemies
+	g.activeMemberIndex = snap.activeMember
+	restoreMembers(snap.members)
+	g.party.Members = snap.partyMembers
+	if main := g.waitingParty; main != nil {
+		main.X, main.Y = snap.waitingX, snap.waitingY
+	}
+	g.enemies = restoreEnemies(snap.enemies)
+	clear(g.party.Items)
+	maps.Copy(g.party.Items, snap.items)
+
+	g.dice = g.dice.Rewind(g.stream(seed.Combat, g.encounters), snap.diceDrawn)
+	if g.rngSource != nil {
+		g.rngSource.Seed(g.seed)
+		g.rngSource.skip(snap.rngDraws)
+	}
+	g.stats.restoreCombat(snap.tally)
+	if g.recording != nil {
+		g.recording.truncate(snap.events)
+	}
+	g.dungeon.Corpses = g.dungeon.Corpses[:snap.corpses]
+	g.investigations = make(map[groupKey]*investigation, len(snap.investigating))
+	for group, inv := range snap.investigating {
+		g.investigations[group] = &inv
+	}
+
+	g.turns = snap.turns
+	g.rewinds--
+	g.lastTurn = nil
+	g.combatState.LastMessage = "The last turn is undone. Rewinds left: " + itoa(g.rewinds) + "."
+	span.SetAttributes(
+		g.combatState.encounterAttr(),
+		attribute.Int("turn", g.combatState.TurnCount),
+		attribute.Int("rewinds_left", g.rewinds),
+	)
+}
+
+// clone copies the combat state deeply enough that resolving more turns
+// leaves the copy as it was.
+func (cs *CombatState) clone() *CombatState {
+	c := *cs
+	c.Enemies = slices.Clone(cs.Enemies)
+	c.EnemyActions = slices.Clone(cs.EnemyActions)
+	c.deathsResolved = maps.Clone(cs.deathsResolved)
+	c.threat = make(threatTable, len(cs.threat))
+	for enemy, members := range cs.threat {
+		c.threat[enemy] = maps.Clone(members)
+	}
+	c.pendingRises = make([]*pendingRise, len(cs.pendingRises))
+	for i, rise := range cs.pendingRises {
+		r := *rise
+		c.pendingRises[i] = &r
+	}
+	c.rises = maps.Clone(cs.rises)
+	c.themeRows = maps.Clone(cs.themeRows)
+	c.partyQueue = slices.Clone(cs.partyQueue)
+	c.planned = slices.Clone(cs.planned)
+	c.cooldowns = cloneCooldowns(cs.cooldowns)
+	return &c
+}
@@ -0,0 +1,87 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+// combatSnapshot summarizes what a rewind must put back: the phase, round
+// and active member, and every combatant's HP, MP and statuses.
+func combatSnapshot(g *Game) string {
+	s := fmt.Sprintf("phase %v round %d active %d;", g.combatState.Phase, g.combatState.Round, g.combatState.ActiveMemberIndex)
+	for _, m := range g.party.Members {
+		s += fmt.Sprintf(" %s %d/%d %v", m.Name, m.HP, m.MP, m.GetStatusEffects())
+	}
+	for _, e := range g.combatEnemies {
+		s += fmt.Sprintf(" %s %d %v", e.GetName(), e.HP, e.GetStatusEffects())
+	}
+	return s
+}
+
+// attackWithActive has the active member attack the first enemy.
+func attackWithActive(g *Game) {
+	attack := g.abilityRegistry.GetByID("attack")
+	g.performPlayerAction(context.Background(), attack, g.getActiveMember(), g.combatEnemies[0])
+}
+
+func TestRewindRestoresThePreTurnFight(t *testing.T) {
+	g := startLosingFight(t)
+
+	// Act until an action hands the round to the enemies, who hit back
+	for g.combatState.Round == 1 {
+		before := combatSnapshot(g)
+		attackWithActive(g)
+		if g.combatState.Round == 1 {
+			continue
+		}
+		after := combatSnapshot(g)
+
+		press(g, 'u')
+		if got := combatSnapshot(g); got != before {
+			t.Fatalf("after rewind:\n got %s\nwant %s", got, before)
+		}
+		if g.rewinds != rewindsPerEncounter-1 {
+			t.Errorf("rewinds left = %d, want %d", g.rewinds, rewindsPerEncounter-1)
+		}
+
+		// The same action plays out the same way again
+		attackWithActive(g)
+		if got := combatSnapshot(g); got != after {
+			t.Errorf("retaken turn:\n got %s\nwant %s", got, after)
+		}
+		return
+	}
+}
+
+func TestRewindsAreLimitedPerEncounter(t *testing.T) {
+	g := startLosingFight(t)
+	for range rewindsPerEncounter {
+		attackWithActive(g)
+		press(g, 'u')
+	}
+	attackWithActive(g)
+	before := combatSnapshot(g)
+	press(g, 'u')
+	if got := combatSnapshot(g); got != before {
+		t.Errorf("rewound past the limit of %d per encounter", rewindsPerEncounter)
+	}
+}
+
+func TestHardcoreCannotRewindOrRetry(t *testing.T) {
+	g := newLosingFight(t)
+	g.cfg.Hardcore = true
+	g.transitionState(context.Background(), StateCombat, "test")
+
+	attackWithActive(g)
+	before := combatSnapshot(g)
+	press(g, 'u')
+	if got := combatSnapshot(g); got != before {
+		t.Error("hardcore run rewound a turn")
+	}
+
+	fightToDefeat(t, g)
+	if g.gameOverPrompt() != gameOverPrompt {
+		t.Errorf("prompt = %q, want no encounter retry in hardcore", g.gameOverPrompt())
+	}
+}
@@ -0,0 +1,101 @@
+package game
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+)
+
+// startRowCombat puts the test game into combat against a goblin (front)
+// and a skeleton (back) on the warrior's turn.
+func startRowCombat(t *testing.T) (*Game, *entity.Enemy, *entity.Enemy) {
+	t.Helper()
+	g := newTestGame(t)
+	goblin := entity.NewEnemyFromDef(g.enemyRegistry.GetByID("goblin"), 5, 5, 1)
+	skeleton := entity.NewEnemyFromDef(g.enemyRegistry.GetByID("skeleton"), 6, 5, 1)
+	g.state = StateCombat
+	g.combatEnemies = []*entity.Enemy{goblin, skeleton}
+	g.initCombatState(context.Background())
+	return g, goblin, skeleton
+}
+
+func TestSkeletonStartsInBackRow(t *testing.T) {
+	_, goblin, skeleton := startRowCombat(t)
+	if goblin.Row != gamedata.RowFront {
+		t.Errorf("goblin row = %q, want front", goblin.Row)
+	}
+	if skeleton.Row != gamedata.RowBack {
+		t.Errorf("skeleton row = %q, want back", skeleton.Row)
+	}
+}
+
+func TestMeleeCannotReachBackRow(t *testing.T) {
+	g, _, skeleton := startRowCombat(t)
+	ctx := context.Background()
+
+	// Warrior: attack, then move the cursor to the skeleton and confirm
+	g.handleCombatAbilitySelection(ctx, 0)
+	g.handleKeyEvent(ctx, tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone))
+	if g.targetedEnemy() != skeleton {
+		t.Fatal("cursor should be able to highlight the back-row skeleton")
+	}
+	g.handleKeyEvent(ctx, tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+
+	if !strings.Contains(g.combatState.LastMessage, "Can't reach") {
+		t.Errorf("LastMessage = %q, want can't reach feedback", g.combatState.LastMessage)
+	}
+	if g.combatState.Phase != PhaseSelectTarget {
+		t.Errorf("Phase = %v, want to remain in target selection", g.combatState.Phase)
+	}
+	if skeleton.HP != skeleton.MaxHP {
+		t.Error("skeleton should not have been hit")
+	}
+}
+
+func TestBackRowPromotedWhenFrontRowFalls(t *testing.T) {
+	g, goblin, skeleton := startRowCombat(t)
+	ctx := context.Background()
+
+	goblin.HP = 1
+
+	// Warrior attacks the goblin (preselected as the first reachable enemy)
+	g.handleCombatAbilitySelection(ctx, 0)
+	g.handleKeyEvent(ctx, tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+
+	if goblin.IsAlive() {
+		t.Fatal("goblin should have died")
+	}
+	if skeleton.Row != gamedata.RowFront {
+		t.Errorf("skeleton row = %q, want promoted to front", skeleton.Row)
+	}
+	if !strings.Contains(g.combatState.LastMessage, "back row steps forward") {
+		t.Errorf("LastMessage = %q, want promotion message", g.combatState.LastMessage)
+	}
+}
+
+func TestBackRowOnlyEncounterStartsPromoted(t *testing.T) {
+	g := newTestGame(t)
+	skeleton := entity.NewEnemyFromDef(g.enemyRegistry.GetByID("skeleton"), 6, 5, 1)
+	g.combatEnemies = []*entity.Enemy{skeleton}
+	g.initCombatState(context.Background())
+
+	if skeleton.InBackRow() {
+		t.Error("lone back-row enemy should be promoted at combat start")
+	}
+}
+
+func TestBackRowEnemyPrefersRangedAbility(t *testing.T) {
+	g, _, skeleton := startRowCombat(t)
+
+	for i := 0; i < 20; i++ {
+		ability := g.selectEnemyAbility(skeleton)
+		if ability == nil || !ability.ReachesBackRow() {
+			t.Fatalf("back-row skeleton chose %v, want a ranged ability", ability)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package game
+
+import (
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/samdwyer/dungeonband/internal/ui"
+)
+
+// runClock measures a run's play time: wall-clock time from the player's
+// first input, stopped while a menu is open. The game loop waits on input,
+// so the clock banks time at each state change instead of ticking.
+type runClock struct {
+	now     func() time.Time // nil uses time.Now
+	started bool             // The player has pressed a key this run
+	running bool             // Time is being counted now
+	since   time.Time        // When the clock last started running
+	banked  time.Duration    // Play time counted before since
+}
+
+// time returns the current wall-clock time.
+func (c *runClock) time() time.Time {
+	if c.now == nil {
+		return time.Now()
+	}
+	return c.now()
+}
+
+// start starts the clock on the run's first input. Later calls do nothing.
+func (c *runClock) start() {
+	if c.started {
+		return
+	}
+	c.started = true
+	c.resume()
+}
+
+// pause stops counting time until resume.
+func (c *runClock) pause() {
+	if !c.running {
+		return
+	}
+	c.banked += c.time().Sub(c.since)
+	c.running = false
+}
+
+// resume counts time again after pause, once the clock has started.
+func (c *runClock) resume() {
+	if c.running || !c.started {
+		return
+	}
+	c.since = c.time()
+	c.running = true
+}
+
+// elapsed returns the play time so far.
+func (c *runClock) elapsed() time.Duration {
+	if !c.running {
+		return c.banked
+	}
+	return c.banked + c.time().Sub(c.since)
+}
+
+// inMenu reports whether play is stopped for a menu or panel that isn't
+// part of playing: the pause menu and its save prompt, the seeds panel, the
+// character sheet, a replay, the game-over prompt, or a display too small to
+// play on.
+// In-game choices like the item menu count as play.
+func (g *Game) inMenu() bool {
+	return g.paused || g.confirmAbandon || g.seedsOpen || g.sheet || g.replay != nil || g.gameOver || g.displayTooSmall()
+}
+
+// syncClock stops the run clock while a menu is open and restarts it once
+// play resumes.
+func (g *Game) syncClock() {
+	if g.inMenu() {
+		g.clock.pause()
+	} else {
+		g.clock.resume()
+	}
+}
+
+// passTurn counts one turn of the run. Every step the party or a scout
+// takes and every combat round that starts is one turn.
+func (g *Game) passTurn() {
+	g.turns++
+}
+
+// runStatus returns the run's turn count and play time.
+func (g *Game) runStatus() ui.RunStatus {
+	return ui.RunStatus{Turns: g.turns, Elapsed: g.clock.elapsed()}
+}
+
+// runAttrs are the run's final turn count and play time, for the span
+// that ends it.
+func (g *Game) runAttrs() []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.Int("run.turns", g.turns),
+		attribute.Int64("run.elapsed_ms", g.clock.elapsed().Milliseconds()),
+	}
+}
+
+// renderRunStatus draws the turn count and play time on the party HUD's
+// row in explore mode. It is hidden while a menu has the clock stopped.
+func (g *Game) renderRunStatus() {
+	if g.state != StateExplore || g.inMenu() {
+		return
+	}
+	status := g.runStatus()
+	g.display.ShowOverlay(Overlay{Kind: OverlayRunStatus, Text: status.String(), Run: status})
+}
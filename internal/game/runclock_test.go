@@ -0,0 +1,75 @@
+package game
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+)
+
+func TestRunClockExcludesPauseMenu(t *testing.T) {
+	g, sim := newTestGameWithScreen(t)
+	now := time.Unix(1000, 0)
+	g.clock.now = func() time.Time { return now }
+
+	// Each key arrives after the given wait
+	script := []struct {
+		wait time.Duration
+		key  rune
+	}{
+		{10 * time.Second, 'l'}, // Idling on the first frame doesn't count
+		{5 * time.Second, 'p'},  // Five seconds of play, then pause
+		{time.Minute, 'r'},      // A minute in the pause menu
+		{3 * time.Second, 'h'},  // Three more seconds of play
+	}
+	for _, step := range script {
+		now = now.Add(step.wait)
+		sim.InjectKey(tcell.KeyRune, step.key, tcell.ModNone)
+		g.handleInput(context.Background())
+	}
+
+	if got := g.clock.elapsed(); got != 8*time.Second {
+		t.Errorf("play time = %v, want 8s", got)
+	}
+	now = now.Add(2 * time.Second)
+	if got := g.clock.elapsed(); got != 10*time.Second {
+		t.Errorf("play time = %v, want 10s while still playing", got)
+	}
+}
+
+func TestTurnsCountStepsAndCombatRounds(t *testing.T) {
+	g, sim := newTestGameWithScreen(t)
+	g.dungeon = dungeonFromMap(`
+#########
+#.......#
+#########`)
+	g.enemies = nil
+	g.party.SetPosition(1, 1)
+
+	press(g, 'l', 'l', 'l')
+	if g.turns != 3 {
+		t.Fatalf("turns after three steps = %d, want 3", g.turns)
+	}
+
+	goblin := entity.NewEnemy(entity.EnemyGoblin, 6, 1, 0)
+	goblin.HP, goblin.MaxHP = 1000, 1000
+	g.enemies = []*entity.Enemy{goblin}
+	g.transitionState(context.Background(), StateCombat, "test")
+	for _, m := range g.party.Members {
+		m.HP, m.MaxHP = 1000, 1000
+	}
+	playRounds(g, 2)
+	if want := 3 + g.combatState.Round; g.turns != want {
+		t.Errorf("turns after round %d started = %d, want %d", g.combatState.Round, g.turns, want)
+	}
+
+	g.state = StateExplore
+	g.render()
+	if row := strings.TrimSpace(strings.Split(screenText(sim), "\n")[g.dungeon.Height]); !strings.HasSuffix(row, "T:"+itoa(g.turns)+" 00:00.0") {
+		t.Errorf("HUD row = %q, want the turn count and play time at its end", row)
+	}
+}
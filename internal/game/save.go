@@ -0,0 +1,345 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/samdwyer/dungeonband/internal/combat"
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+	"github.com/samdwyer/dungeonband/internal/seed"
+	"github.com/samdwyer/dungeonband/internal/telemetry"
+	"github.com/samdwyer/dungeonband/internal/world"
+	"github.com/samdwyer/dungeonband/pkg/dungeon"
+)
+
+// Save is a snapshot of a run on the explore map, enough to resume it
+// exactly: the RNG is restored to the same position, so the rest of the
+// run plays out as it would have.
+type Save struct {
+	Seed       int64  `json:"seed"`
+	Draws      uint64 `json:"draws"` // Values drawn from the RNG so far
+	Floor      int    `json:"floor"`
+	Difficulty string `json:"difficulty,omitempty"`
+
+	Dungeon SavedDungeon  `json:"dungeon"`
+	Party   SavedParty    `json:"party"`
+	Enemies []SavedEnemy  `json:"enemies"`
+	Run     SavedProgress `json:"run"`
+}
+
+// SavedDungeon is the current floor's layout and what is left on it.
+type SavedDungeon struct {
+	Width     int              `json:"width"`
+	Height    int              `json:"height"`
+	Rows      []string         `json:"rows"` // One string of tile runes per row
+	Rooms     []world.Room     `json:"rooms"`
+	Corridors []world.Corridor `json:"corridors,omitempty"`
+	StairsX   int              `json:"stairsX"`
+	StairsY   int              `json:"stairsY"`
+	Shrines   []world.Shrine   `json:"shrines,omitempty"`
+	Corpses   []world.Corpse   `json:"corpses,omitempty"`
+	Draws     uint64           `json:"draws,omitempty"` // Values drawn from the floor's dungeon stream so far
+}
+
+// SavedParty is the party's position, supplies and members.
+type SavedParty struct {
+	X       int                 `json:"x"`
+	Y       int                 `json:"y"`
+	Items   map[entity.Item]int `json:"items,omitempty"`
+	Members []SavedMember       `json:"members"`
+	Scout   int                 `json:"scout"`           // Index of the scouting member, -1 while together
+	Trail   []entity.TrailPoint `json:"trail,omitempty"` // Tiles the followers are drawn on, most recent first
+}
+
+// SavedMember is one party member's stats and statuses.
+type SavedMember struct {
+	Name      string                `json:"name"`
+	Class     entity.Class          `json:"class"`
+	X         int                   `json:"x"`
+	Y         int                   `json:"y"`
+	HP        int                   `json:"hp"`
+	MaxHP     int                   `json:"maxHp"`
+	MP        int                   `json:"mp"`
+	MaxMP     int                   `json:"maxMp"`
+	Attack    int                   `json:"attack"`
+	Defense   int                   `json:"defense"`
+	Magic     int                   `json:"magic"`
+	Resist    int                   `json:"resist"`
+	Abilities []string              `json:"abilities"`
+	Uses      map[string]int        `json:"uses,omitempty"` // Casts of each ability, for mastery
+	Statuses  []combat.StatusEffect `json:"statuses,omitempty"`
+	Lifetime  memberStats           `json:"lifetime"` // Combat totals over the run
+}
+
+// SavedEnemy is one enemy left on the floor.
+type SavedEnemy struct {
+	ID        string                `json:"id"` // EnemyDef ID
+	X         int                   `json:"x"`
+	Y         int                   `json:"y"`
+	RoomIndex int                   `json:"roomIndex"`
+	HP        int                   `json:"hp"`
+	MaxHP     int                   `json:"maxHp"`
+	MP        int                   `json:"mp"`
+	MaxMP     int                   `json:"maxMp"`
+	Row       gamedata.Row          `json:"row"`
+	Abilities []string              `json:"abilities,omitempty"`
+	Statuses  []combat.StatusEffect `json:"statuses,omitempty"`
+}
+
+// SavedProgress is the run's bookkeeping outside the map.
+type SavedProgress struct {
+	StartRoom       int             `json:"startRoom"`
+	ExploreSteps    int             `json:"exploreSteps"`
+	FlavorSteps     int             `json:"flavorSteps"`
+	FlavorFired     map[string]bool `json:"flavorFired,omitempty"`
+	VisitedRooms    map[int]bool    `json:"visitedRooms,omitempty"`
+	Encounters      int             `json:"encounters"`
+	DifficultyShift int             `json:"difficultyShift"`
+	Searches        int             `json:"searches,omitempty"`   // Corpses searched, indexing the loot stream
+	Practice        bool            `json:"practice,omitempty"`   // A lost fight was retried
+	Turns           int             `json:"turns,omitempty"`      // Steps and combat rounds taken
+	PlayTimeMS      int64           `json:"playTimeMs,omitempty"` // Run clock, resumed from here
+	ShrinesUsed     int             `json:"shrinesUsed,omitempty"`
+}
+
+// AutosavePath returns where autosaves are kept in the user's config directory.
+func AutosavePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "dungeonband", "autosave.json"), nil
+}
+
+// LoadSave reads the save at path.
+func LoadSave(path string) (*Save, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s Save
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parse save %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+// Write stores the save at path, creating its directory if needed. The
+// file is replaced in one step, so a crash mid-write keeps the old save.
+func (s *Save) Write(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// snapshot captures the run as a Save.
+func (g *Game) snapshot() *Save {
+	s := &Save{
+		Seed:  g.seed,
+		Draws: g.rngSource.draws,
+		Floor: g.floor,
+		Run: SavedProgress{
+			StartRoom:       g.startRoom,
+			ExploreSteps:    g.exploreSteps,
+			FlavorSteps:     g.flavorSteps,
+			FlavorFired:     g.flavorFired,
+			Encounters:      g.encounters,
+			DifficultyShift: g.difficultyShift,
+			Searches:        g.searches,
+			Practice:        g.practice,
+			Turns:           g.turns,
+			PlayTimeMS:      g.clock.elapsed().Milliseconds(),
+			ShrinesUsed:     g.stats.run.ShrinesUsed,
+		},
+	}
+	if g.difficulty != nil {
+		s.Difficulty = g.difficulty.ID
+	}
+	if g.visitedFloor == g.floor {
+		s.Run.VisitedRooms = g.visitedRooms
+	}
+
+	d := g.dungeon
+	s.Dungeon = SavedDungeon{
+		Width:     d.Width,
+		Height:    d.Height,
+		Rooms:     d.Rooms,
+		Corridors: d.Corridors,
+		StairsX:   d.StairsX,
+		StairsY:   d.StairsY,
+		Shrines:   d.Shrines,
+		Corpses:   d.Corpses,
+	}
+	s.Dungeon.Rows = dungeon.Rows(d)
+	if g.dungeonSource != nil {
+		s.Dungeon.Draws = g.dungeonSource.draws
+	}
+
+	s.Party = SavedParty{X: g.party.X, Y: g.party.Y, Items: g.party.Items, Scout: -1, Trail: g.party.Trail}
+	for i, m := range g.party.Members {
+		if m == g.party.Scout {
+			s.Party.Scout = i
+		}
+		s.Party.Members = append(s.Party.Members, SavedMember{
+			Name: m.Name, Class: m.Class, X: m.X, Y: m.Y,
+			HP: m.HP, MaxHP: m.MaxHP, MP: m.MP, MaxMP: m.MaxMP,
+			Attack: m.Attack, Defense: m.Defense, Magic: m.Magic, Resist: m.Resist,
+			Abilities: m.AbilityIDs,
+			Uses:      m.AbilityUses,
+			Statuses:  m.GetStatusEffects(),
+			Lifetime:  *g.stats.lifetimeFor(m),
+		})
+	}
+
+	for _, e := range g.enemies {
+		s.Enemies = append(s.Enemies, SavedEnemy{
+			ID: e.ID(), X: e.X, Y: e.Y, RoomIndex: e.RoomIndex,
+			HP: e.HP, MaxHP: e.MaxHP, MP: e.MP, MaxMP: e.MaxMP,
+			Row:       e.Row,
+			Abilities: e.Abilities,
+			Statuses:  e.GetStatusEffects(),
+		})
+	}
+	return s
+}
+
+// restore replaces the run with the saved one. The game must have been
+// created with the save's seed.
+func (g *Game) restore(s *Save) {
+	g.rngSource.skip(s.Draws)
+	g.floor = s.Floor
+	g.startRoom = s.Run.StartRoom
+	g.exploreSteps = s.Run.ExploreSteps
+	g.flavorSteps = s.Run.FlavorSteps
+	g.flavorFired = s.Run.FlavorFired
+	g.visitedRooms = s.Run.VisitedRooms
+	g.visitedFloor = s.Floor
+	g.encounters = s.Run.Encounters
+	g.difficultyShift = s.Run.DifficultyShift
+	g.searches = s.Run.Searches
+	g.practice = s.Run.Practice
+	g.turns = s.Run.Turns
+	g.clock.banked = time.Duration(s.Run.PlayTimeMS) * time.Millisecond
+	g.stats.run.ShrinesUsed = s.Run.ShrinesUsed
+
+	rng, src := g.countedStream(seed.Dungeon, s.Floor)
+	src.skip(s.Dungeon.Draws)
+	g.dungeonSource = src
+	d := world.NewDungeon(s.Dungeon.Width, s.Dungeon.Height, rng)
+	d.Prefabs = g.prefabs
+	for y, row := range s.Dungeon.Rows {
+		for x, r := range []rune(row) {
+			if y < d.Height && x < d.Width {
+				d.Tiles[y][x] = world.Tile(r)
+			}
+		}
+	}
+	d.Rooms = s.Dungeon.Rooms
+	d.Corridors = s.Dungeon.Corridors
+	d.StairsX, d.StairsY = s.Dungeon.StairsX, s.Dungeon.StairsY
+	d.Shrines = s.Dungeon.Shrines
+	d.Corpses = s.Dungeon.Corpses
+	g.dungeon = d
+
+	g.party = entity.NewParty(s.Party.X, s.Party.Y)
+	g.party.Members = nil
+	g.party.Trail = s.Party.Trail
+	if s.Party.Items != nil {
+		g.party.Items = s.Party.Items
+	}
+	for i, sm := range s.Party.Members {
+		m := entity.NewMember(sm.Name, sm.Class)
+		m.SetPosition(sm.X, sm.Y)
+		m.HP, m.MaxHP, m.MP, m.MaxMP = sm.HP, sm.MaxHP, sm.MP, sm.MaxMP
+		m.Attack, m.Defense, m.Magic, m.Resist = sm.Attack, sm.Defense, sm.Magic, sm.Resist
+		m.AbilityIDs = sm.Abilities
+		m.AbilityUses = sm.Uses
+		for _, effect := range sm.Statuses {
+			m.AddStatusEffect(effect)
+		}
+		*g.stats.lifetimeFor(m) = sm.Lifetime
+		g.party.Members = append(g.party.Members, m)
+		if i == s.Party.Scout {
+			g.party.Scout = m
+		}
+	}
+
+	g.resumedUses = g.partyAbilityUses()
+
+	g.enemies = nil
+	for _, se := range s.Enemies {
+		var def *gamedata.EnemyDef
+		if g.enemyRegistry != nil {
+			def = g.enemyRegistry.GetByID(se.ID)
+		}
+		if def == nil {
+			log.Printf("Warning: saved enemy %q is no longer defined (leaving it out)", se.ID)
+			continue
+		}
+		e := entity.NewEnemyFromDef(def, se.X, se.Y, se.RoomIndex)
+		e.HP, e.MaxHP, e.MP, e.MaxMP = se.HP, se.MaxHP, se.MP, se.MaxMP
+		e.Row = se.Row
+		e.Abilities = se.Abilities
+		for _, effect := range se.Statuses {
+			e.AddStatusEffect(effect)
+		}
+		g.enemies = append(g.enemies, e)
+	}
+	g.message = "Resuming on floor " + itoa(g.floor) + "."
+}
+
+// save writes the run to the autosave slot, or to the default slot when
+// autosave is off, so it can be continued from the title screen.
+func (g *Game) save(ctx context.Context) error {
+	path := g.autosavePath
+	if path == "" {
+		var err error
+		if path, err = AutosavePath(); err != nil {
+			return err
+		}
+	}
+
+	tracer := telemetry.Tracer("game")
+	_, span := tracer.Start(ctx, "game.save")
+	defer span.End()
+	span.SetAttributes(attribute.Int("floor", g.floor))
+	return g.snapshot().Write(path)
+}
+
+// autosave writes the run to the autosave slot, if autosave is on. reason
+// names the event that triggered it. A failed save is logged and the run
+// carries on.
+func (g *Game) autosave(ctx context.Context, reason string) {
+	if g.autosavePath == "" || g.tutorial {
+		return
+	}
+	tracer := telemetry.Tracer("game")
+	_, span := tracer.Start(ctx, "game.autosave")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("reason", reason),
+		attribute.Int("floor", g.floor),
+	)
+
+	if err := g.snapshot().Write(g.autosavePath); err != nil {
+		span.SetAttributes(attribute.String("error", err.Error()))
+		log.Printf("Warning: autosave failed: %v", err)
+	}
+}
@@ -0,0 +1,178 @@
+package game
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+)
+
+// newSavingGame starts a run that autosaves to a temporary slot.
+func newSavingGame(t *testing.T) (*Game, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "autosave.json")
+	g, err := New(Config{Seed: 4242, SkipDescendConfirm: true, AutosavePath: path}, NullDisplay{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.setup(context.Background())
+	return g, path
+}
+
+func TestDescendAutosavesNewFloor(t *testing.T) {
+	g, path := newSavingGame(t)
+	if _, err := os.Stat(path); err == nil {
+		t.Fatal("saved before anything happened")
+	}
+
+	g.descend(context.Background())
+
+	save, err := LoadSave(path)
+	if err != nil {
+		t.Fatalf("descending did not autosave: %v", err)
+	}
+	if save.Floor != 2 || save.Seed != 4242 {
+		t.Errorf("save floor/seed = %d/%d, want 2/4242", save.Floor, save.Seed)
+	}
+	tiles, enemies := layoutOf(g)
+	var saved string
+	for _, row := range save.Dungeon.Rows {
+		saved += row + "\n"
+	}
+	if saved != tiles {
+		t.Error("saved tiles are not the new floor's")
+	}
+	if len(save.Enemies) != len(enemies) {
+		t.Errorf("saved %d enemies, floor has %d", len(save.Enemies), len(enemies))
+	}
+	if save.Party.X != g.party.X || save.Party.Y != g.party.Y {
+		t.Errorf("saved party at (%d,%d), party is at (%d,%d)", save.Party.X, save.Party.Y, g.party.X, g.party.Y)
+	}
+}
+
+func TestResumeRestoresSavedRun(t *testing.T) {
+	g, path := newSavingGame(t)
+	ctx := context.Background()
+	g.party.Members[0].HP = 7
+	g.descend(ctx)
+	g.party.Trail = []entity.TrailPoint{{X: g.party.X - 1, Y: g.party.Y}, {X: g.party.X - 2, Y: g.party.Y}}
+	g.autosave(ctx, "test")
+
+	save, err := LoadSave(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resumed, err := New(Config{Seed: save.Seed, Resume: save}, NullDisplay{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resumed.setup(ctx)
+
+	if got, want := resumed.StateHash(), g.StateHash(); got != want {
+		t.Fatalf("resumed hash %x, want %x", got, want)
+	}
+	if !slices.Equal(resumed.party.Trail, g.party.Trail) {
+		t.Errorf("resumed trail = %v, want %v", resumed.party.Trail, g.party.Trail)
+	}
+	// The RNG picks up where it left off, so both runs carry on identically
+	g.descend(ctx)
+	resumed.descend(ctx)
+	if got, want := resumed.StateHash(), g.StateHash(); got != want {
+		t.Errorf("runs drifted apart after resuming: %x vs %x", got, want)
+	}
+}
+
+func TestResumeKeepsLifetimeStats(t *testing.T) {
+	g, path := newSavingGame(t)
+	ctx := context.Background()
+	cleric := g.party.Members[3]
+	*g.stats.lifetimeFor(cleric) = memberStats{DamageDealt: 9, DamageTaken: 12, HealingDone: 30, Kills: 1}
+	g.stats.run.ShrinesUsed = 2
+	g.descend(ctx)
+
+	save, err := LoadSave(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resumed, err := New(Config{Seed: save.Seed, Resume: save}, NullDisplay{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resumed.setup(ctx)
+
+	if got, want := *resumed.stats.lifetimeFor(resumed.party.Members[3]), *g.stats.lifetimeFor(cleric); got != want {
+		t.Errorf("resumed cleric's lifetime stats = %+v, want %+v", got, want)
+	}
+	if got := resumed.stats.run.ShrinesUsed; got != 2 {
+		t.Errorf("resumed ShrinesUsed = %d, want 2", got)
+	}
+	sheet := resumed.characterSheetOverlay().Text
+	if want := "9 damage dealt, 12 taken, 30 healed, 1 kill"; !strings.Contains(sheet, want) {
+		t.Errorf("character sheet missing %q:\n%s", want, sheet)
+	}
+}
+
+func TestRetryAfterResumingStartsTheSeedOver(t *testing.T) {
+	g, path := newSavingGame(t)
+	ctx := context.Background()
+	g.descend(ctx)
+
+	save, err := LoadSave(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resumed, err := New(Config{Seed: save.Seed, Resume: save}, NullDisplay{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resumed.setup(ctx)
+	resumed.transitionState(ctx, StateCombat, "test")
+	for _, m := range resumed.party.Members {
+		m.TakeDamage(m.GetHP())
+	}
+	resumed.combatState.Phase = PhaseDefeat
+	resumed.handleKeyEvent(ctx, pressRune(' '))
+	press(resumed, 'r')
+
+	fresh, err := New(Config{Seed: save.Seed}, NullDisplay{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fresh.setup(ctx)
+	if resumed.floor != 1 {
+		t.Errorf("retry is on floor %d, want the seed's first floor", resumed.floor)
+	}
+	if got, want := resumed.StateHash(), fresh.StateHash(); got != want {
+		t.Errorf("retried run hash %x, want a fresh run of seed %d (%x)", got, save.Seed, want)
+	}
+}
+
+func TestResumeContinuesTheDungeonStream(t *testing.T) {
+	g, path := newSavingGame(t)
+	ctx := context.Background()
+	g.descend(ctx)
+	g.dungeon.OpenPointInRoom(0) // A later draw from the floor's stream
+	g.autosave(ctx, "test")
+
+	save, err := LoadSave(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resumed, err := New(Config{Seed: save.Seed, Resume: save}, NullDisplay{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resumed.setup(ctx)
+
+	for room := range g.dungeon.Rooms {
+		wantX, wantY, _ := g.dungeon.OpenPointInRoom(room)
+		gotX, gotY, _ := resumed.dungeon.OpenPointInRoom(room)
+		if gotX != wantX || gotY != wantY {
+			t.Fatalf("room %d point = (%d,%d) after resuming, want (%d,%d)", room, gotX, gotY, wantX, wantY)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/telemetry"
+	"github.com/samdwyer/dungeonband/internal/world"
+)
+
+// joinDistance is how close the rest of the party must get to a scout's
+// fight to join it.
+const joinDistance = 2
+
+// reinforcementSteps is how many tiles the rest of the party covers toward
+// a scout's fight each round.
+const reinforcementSteps = 2
+
+// chooseScout returns the member best suited to scout: the first living
+// rogue, else the last living member. Returns nil if fewer than two
+// members are alive, since someone has to stay behind.
+func (g *Game) chooseScout() *entity.Member {
+	if g.party.AliveMemberCount() < 2 {
+		return nil
+	}
+	var scout *entity.Member
+	for _, m := range g.party.Members {
+		if !m.IsAlive() {
+			continue
+		}
+		if m.Class == entity.ClassRogue {
+			return m
+		}
+		scout = m
+	}
+	return scout
+}
+
+// toggleScout sends a scout ahead, or switches the controls between the
+// scout and the rest of the party once one is out.
+func (g *Game) toggleScout(ctx context.Context) {
+	if scout := g.party.Scout; scout != nil {
+		g.scoutControl = !g.scoutControl
+		if g.scoutControl {
+			g.message = "Controlling " + scout.Name + "."
+		} else {
+			g.message = "Controlling the party."
+		}
+		return
+	}
+
+	scout := g.chooseScout()
+	if scout == nil {
+		g.message = "Nobody can be spared to scout."
+		return
+	}
+	scout.SetPosition(g.party.X, g.party.Y)
+	g.party.Scout = scout
+	g.scoutControl = true
+	g.message = scout.Name + " slips ahead to scout. (s: switch control)"
+
+	tracer := telemetry.Tracer("game")
+	_, span := tracer.Start(ctx, "game.scout")
+	span.SetAttributes(
+		attribute.String("scout", scout.Name),
+		attribute.Int("floor", g.floor),
+	)
+	span.End()
+}
+
+// moveScout moves the scout by the given delta. Stepping onto the party
+// rejoins it.
+func (g *Game) moveScout(dx, dy int) {
+	scout := g.party.Scout
+	x, y := scout.X+dx, scout.Y+dy
+	if !g.dungeon.IsPassable(x, y) {
+		return
+	}
+	scout.SetPosition(x, y)
+	g.message = ""
+	g.walkTurn(x, y)
+	g.rejoinScout()
+}
+
+// rejoinScout brings the scout back into the party once they share a tile.
+func (g *Game) rejoinScout() {
+	scout := g.party.Scout
+	if scout == nil || scout.X != g.party.X || scout.Y != g.party.Y {
+		return
+	}
+	g.party.Scout = nil
+	g.scoutControl = false
+	g.message = scout.Name + " rejoins the party."
+}
+
+// moveControlled moves whoever the movement keys control.
+func (g *Game) moveControlled(ctx context.Context, dx, dy int) {
+	if g.scoutControl && g.party.Scout != nil {
+		g.moveScout(dx, dy)
+		return
+	}
+	g.tryMove(ctx, dx, dy)
+}
+
+// splitForCombat prepares the party for a fight that starts while a scout
+// is out. A scout under control fights alone from where they stand, with
+// the rest of the party waiting in g.waitingParty until it arrives. A
+// fight started by the party calls the scout back to join it.
+func (g *Game) splitForCombat() {
+	scout := g.party.Scout
+	if scout == nil {
+		return
+	}
+	if !g.scoutControl {
+		g.party.Scout = nil
+		return
+	}
+	g.waitingParty = g.party
+	g.party = &entity.Party{
+		X:       scout.X,
+		Y:       scout.Y,
+		Symbol:  g.waitingParty.Symbol,
+		Members: []*entity.Member{scout},
+		Items:   g.waitingParty.Items,
+	}
+}
+
+// advanceReinforcements walks the rest of the party toward a scout's fight
+// at the start of each round, and brings them into it once they're close.
+// Members who found no room to stand keep trying each round.
+func (g *Game) advanceReinforcements(ctx context.Context) {
+	main := g.waitingParty
+	if main == nil || len(g.stillWaiting()) == 0 {
+		return
+	}
+	scout := main.Scout
+	for range reinforcementSteps {
+		if world.Distance(main.X, main.Y, scout.X, scout.Y) <= joinDistance {
+			break
+		}
+		x, y, ok := g.dungeon.NextStepToward(main.X, main.Y, scout.X, scout.Y, joinDistance, nil)
+		if !ok {
+			break
+		}
+		main.Move(x-main.X, y-main.Y)
+	}
+	if world.Distance(main.X, main.Y, scout.X, scout.Y) > joinDistance {
+		g.combatState.LastMessage += " The party hurries toward " + scout.Name + "."
+		return
+	}
+	g.joinScoutFight(ctx)
+}
+
+// stillWaiting returns the living members of the waiting party who haven't
+// joined the scout's fight yet.
+func (g *Game) stillWaiting() []*entity.Member {
+	var waiting []*entity.Member
+	for _, m := range g.waitingParty.Members {
+		if m.IsAlive() && !slices.Contains(g.party.Members, m) {
+			waiting = append(waiting, m)
+		}
+	}
+	return waiting
+}
+
+// joinScoutFight brings the rest of the party into the scout's fight,
+// placing arrivals on free tiles around where the party stands. Only as
+// many arrive as there are free tiles; the others wait for room.
+func (g *Game) joinScoutFight(ctx context.Context) {
+	main := g.waitingParty
+	waiting := g.stillWaiting()
+
+	occupied := map[position]bool{}
+	for _, m := range g.party.Members {
+		if m.IsAlive() {
+			occupied[position{m.X, m.Y}] = true
+		}
+	}
+	var free []position
+	for _, p := range g.findLineFormation(main.X, main.Y, len(waiting)+len(occupied)) {
+		if !occupied[p] {
+			free = append(free, p)
+		}
+	}
+	arrivals := waiting[:min(len(waiting), len(free))]
+	names := make([]string, len(arrivals))
+	for i, m := range arrivals {
+		m.SetPosition(free[i].x, free[i].y)
+		names[i] = m.Name
+	}
+	if len(arrivals) > 0 {
+		var members []*entity.Member
+		for _, m := range main.Members {
+			if !slices.Contains(waiting[len(arrivals):], m) {
+				members = append(members, m)
+			}
+		}
+		g.party.Members = members
+	}
+
+	tracer := telemetry.Tracer("combat")
+	_, span := tracer.Start(ctx, "combat.join")
+	span.SetAttributes(
+		g.combatState.encounterAttr(),
+		attribute.Int("joined", len(arrivals)),
+		attribute.Int("turn", g.combatState.TurnCount),
+	)
+	span.End()
+	if len(arrivals) > 0 {
+		g.combatState.LastMessage += " The party arrives! " + strings.Join(names, ", ") + " join the fight."
+	}
+	if held := len(waiting) - len(arrivals); held > 0 {
+		g.combatState.LastMessage += fmt.Sprintf(" %d can't reach the fight yet.", held)
+	}
+}
+
+// reuniteAfterCombat puts the whole party back under control when a scout's
+// fight ends. A scout the party reached rejoins it; one who fought alone
+// and lived stays out scouting, and one who fell is carried back.
+func (g *Game) reuniteAfterCombat() {
+	main := g.waitingParty
+	if main == nil {
+		return
+	}
+	g.waitingParty = nil
+	scout := main.Scout
+	joined := len(g.party.Members) > 1
+	g.party = main
+
+	switch {
+	case !scout.IsAlive():
+		g.party.Scout = nil
+		g.scoutControl = false
+		g.message = scout.Name + " fell while scouting."
+	case joined:
+		g.party.Scout = nil
+		g.scoutControl = false
+	}
+}
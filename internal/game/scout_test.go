@@ -0,0 +1,163 @@
+package game
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+)
+
+// scoutLayout is two rooms joined along the bottom row, with a wall hiding
+// the east room from the west one.
+const scoutLayout = `
+####################
+#......#...........#
+#......#...........#
+#..................#
+####################`
+
+// newScoutGame puts the party in the west room with a goblin in the east.
+func newScoutGame(t *testing.T) *Game {
+	t.Helper()
+	g := newTestGame(t)
+	g.dungeon = dungeonFromMap(scoutLayout)
+	g.party.SetPosition(2, 1)
+	g.enemies = nil
+	g.enemies = append(g.enemies, entity.NewEnemyFromDef(g.enemyRegistry.GetByID("goblin"), 16, 1, 0))
+	return g
+}
+
+func TestScoutMovesAloneAndRejoins(t *testing.T) {
+	g := newScoutGame(t)
+	rogue := g.party.Members[1]
+
+	press(g, 's')
+	if g.party.Scout != rogue || !g.scoutControl {
+		t.Fatalf("'s' should send the rogue scouting, got %v", g.party.Scout)
+	}
+	press(g, 'l', 'l')
+	if rogue.X != 4 || g.party.X != 2 {
+		t.Errorf("scout at x=%d, party at x=%d; want the scout to move alone", rogue.X, g.party.X)
+	}
+
+	press(g, 's') // Control the party
+	press(g, 'l')
+	if g.party.X != 3 || rogue.X != 4 {
+		t.Errorf("party at x=%d, scout at x=%d; want the party to move", g.party.X, rogue.X)
+	}
+	press(g, 'l') // Onto the scout
+	if g.party.Scout != nil || g.scoutControl {
+		t.Error("walking onto the scout should rejoin them")
+	}
+}
+
+func TestPartyJoinsScoutFight(t *testing.T) {
+	g := newScoutGame(t)
+	ctx := context.Background()
+	rogue := g.party.Members[1]
+	press(g, 's')
+	g.moveScout(0, 1)
+	g.moveScout(0, 1)
+	for range 10 {
+		g.moveScout(1, 0)
+	}
+	g.moveScout(0, -1) // Into the east room at (12,2)
+
+	press(g, 'c')
+	if g.state != StateCombat || len(g.party.Members) != 1 || g.party.Members[0] != rogue {
+		t.Fatalf("the scout should fight alone, party is %d members", len(g.party.Members))
+	}
+	if len(g.combatState.Enemies) != 1 {
+		t.Fatalf("scout's fight has %d enemies, want the goblin the scout sees", len(g.combatState.Enemies))
+	}
+
+	rounds := 0
+	for len(g.party.Members) == 1 && rounds < 20 {
+		g.advanceReinforcements(ctx)
+		rounds++
+	}
+	if len(g.party.Members) != 4 {
+		t.Fatal("the party never joined the fight")
+	}
+	if rounds < 2 {
+		t.Errorf("the party arrived after %d rounds, want it to take a while", rounds)
+	}
+	for _, m := range g.party.Members {
+		if !g.dungeon.IsPassable(m.X, m.Y) {
+			t.Errorf("%s placed on a wall at (%d,%d)", m.Name, m.X, m.Y)
+		}
+		if m != rogue && m.X == rogue.X && m.Y == rogue.Y {
+			t.Errorf("%s placed on the scout's tile", m.Name)
+		}
+	}
+
+	g.transitionState(ctx, StateExplore, "test")
+	if g.party.Scout != nil || g.waitingParty != nil || g.scoutControl {
+		t.Error("the party should be reunited after a fight it joined")
+	}
+}
+
+// closetLayout shuts the party in a two-tile closet, too small for everyone
+// to step out into the fight.
+const closetLayout = `
+##########
+#..#.....#
+####.....#
+##########`
+
+func TestCrampedArrivalsWaitForRoom(t *testing.T) {
+	g := newTestGame(t)
+	ctx := context.Background()
+	g.dungeon = dungeonFromMap(closetLayout)
+	g.party.SetPosition(1, 1)
+	g.enemies = []*entity.Enemy{entity.NewEnemyFromDef(g.enemyRegistry.GetByID("goblin"), 8, 1, 0)}
+	rogue := g.party.Members[1]
+	press(g, 's')
+	rogue.SetPosition(5, 1)
+	g.transitionState(ctx, StateCombat, "test")
+
+	g.joinScoutFight(ctx)
+	if len(g.party.Members) != 3 {
+		t.Fatalf("%d members in the fight, want the scout and the two who fit", len(g.party.Members))
+	}
+	tiles := map[position]string{}
+	for _, m := range g.party.Members {
+		p := position{m.X, m.Y}
+		if other, ok := tiles[p]; ok {
+			t.Errorf("%s and %s share (%d,%d)", m.Name, other, m.X, m.Y)
+		}
+		tiles[p] = m.Name
+	}
+	if !strings.Contains(g.combatState.LastMessage, "1 can't reach the fight yet.") {
+		t.Errorf("message = %q, want it to mention who's left waiting", g.combatState.LastMessage)
+	}
+
+	g.waitingParty.SetPosition(5, 2)
+	g.advanceReinforcements(ctx)
+	if len(g.party.Members) != 4 {
+		t.Errorf("%d members in the fight once there's room, want 4", len(g.party.Members))
+	}
+}
+
+func TestScoutFallingAloneIsNotGameOver(t *testing.T) {
+	g := newScoutGame(t)
+	ctx := context.Background()
+	rogue := g.party.Members[1]
+	press(g, 's')
+	g.transitionState(ctx, StateCombat, "test")
+
+	rogue.TakeDamage(rogue.GetHP())
+	g.combatState.Phase = PhaseDefeat
+	g.handleCombatEnd(ctx)
+
+	if g.gameOver {
+		t.Fatal("losing the scout alone ended the run")
+	}
+	if len(g.party.Members) != 4 || g.party.Scout != nil || g.state != StateExplore {
+		t.Errorf("party not restored: %d members, scout %v, state %v", len(g.party.Members), g.party.Scout, g.state)
+	}
+	if g.message != "Shade fell while scouting." {
+		t.Errorf("message = %q", g.message)
+	}
+}
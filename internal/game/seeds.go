@@ -0,0 +1,126 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/samdwyer/dungeonband/internal/seed"
+	"github.com/samdwyer/dungeonband/internal/telemetry"
+)
+
+// stream returns a generator for the index-th use of a seed stream and
+// logs its sub-seed for the seeds panel.
+func (g *Game) stream(s seed.Stream, index int) *rand.Rand {
+	return g.seedLog().Rand(s, index)
+}
+
+// countedStream is stream with the draws counted, so a save can put the
+// stream back where it was.
+func (g *Game) countedStream(s seed.Stream, index int) (*rand.Rand, *countingSource) {
+	src := newCountingSource(g.seedLog().Seed(s, index))
+	return rand.New(src), src
+}
+
+// seedLog returns the log of sub-seeds drawn from the current master seed.
+func (g *Game) seedLog() *seed.Log {
+	if g.seeds == nil || g.seeds.Master != g.seed {
+		g.seeds = seed.NewLog(g.seed)
+	}
+	return g.seeds
+}
+
+// SeedLog returns the sub-seeds the run has drawn from its master seed.
+func (g *Game) SeedLog() *seed.Log {
+	if g.seeds == nil {
+		return seed.NewLog(g.seed)
+	}
+	return g.seeds
+}
+
+// SeedLogPath returns where exported seed logs are kept in the user's
+// config directory.
+func SeedLogPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "dungeonband", "seeds.json"), nil
+}
+
+// seedLabel names a stream's index the way the player counts them.
+func seedLabel(s seed.Stream, index int) string {
+	switch s {
+	case seed.Dungeon:
+		return "dungeon, floor " + itoa(index)
+	case seed.Combat:
+		return "combat, encounter " + itoa(index)
+	case seed.Loot:
+		return "loot, search " + itoa(index+1)
+	}
+	return string(s) + " " + itoa(index)
+}
+
+// seedLine is one sub-seed's row in the seeds panel.
+func seedLine(s seed.Stream, index int, value int64) string {
+	return fmt.Sprintf("  %-22s %016x", seedLabel(s, index), uint64(value))
+}
+
+// seedsOverlay lists the sub-seeds the run has used, then the ones the
+// next floor, encounter and search will use.
+func (g *Game) seedsOverlay() Overlay {
+	log := g.SeedLog()
+	lines := []string{fmt.Sprintf("Master seed %d", log.Master), "", "Used:"}
+	if len(log.Uses) == 0 {
+		lines = append(lines, "  nothing yet")
+	}
+	for _, u := range log.Uses {
+		lines = append(lines, seedLine(u.Stream, u.Index, u.Seed))
+	}
+	lines = append(lines, "", "Next:")
+	for _, next := range []struct {
+		stream seed.Stream
+		index  int
+	}{
+		{seed.Dungeon, g.floor + 1},
+		{seed.Combat, g.encounters + 1},
+		{seed.Loot, g.searches},
+	} {
+		lines = append(lines, seedLine(next.stream, next.index, seed.Derive(log.Master, next.stream, next.index)))
+	}
+	lines = append(lines, "", "(e)xport for \"dungeonband seeds verify\", any other key closes")
+	return Overlay{Kind: OverlayInstruction, Title: "Seeds", Text: strings.Join(lines, "\n")}
+}
+
+// handleSeedsKey closes the seeds panel, exporting the log first on 'e'.
+func (g *Game) handleSeedsKey(ctx context.Context, ev *tcell.EventKey) {
+	g.seedsOpen = false
+	if ev.Key() == tcell.KeyRune && (ev.Rune() == 'e' || ev.Rune() == 'E') {
+		g.exportSeeds(ctx)
+	}
+}
+
+// exportSeeds writes the seed log to SeedLogPath and reports where it went.
+func (g *Game) exportSeeds(ctx context.Context) {
+	tracer := telemetry.Tracer("game")
+	_, span := tracer.Start(ctx, "game.seeds_export")
+	defer span.End()
+
+	path, err := SeedLogPath()
+	if err == nil {
+		err = g.SeedLog().Write(path)
+	}
+	span.SetAttributes(attribute.Int("seeds", len(g.SeedLog().Uses)))
+	if err != nil {
+		span.SetAttributes(attribute.String("error", err.Error()))
+		g.message = "Couldn't export seeds: " + err.Error()
+		return
+	}
+	g.message = "Seeds exported to " + path + "."
+}
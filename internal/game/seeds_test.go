@@ -0,0 +1,107 @@
+package game
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/seed"
+)
+
+func TestFloorLayoutDependsOnlyOnSeedAndFloor(t *testing.T) {
+	quiet, busy := newTestGame(t), newTestGame(t)
+	// Flavor, spawns and the like draw from the game's RNG along the way
+	for range 17 {
+		busy.rng.Intn(100)
+	}
+	for _, g := range []*Game{quiet, busy} {
+		g.floor = 2
+		g.generateDungeon(context.Background())
+	}
+	if !reflect.DeepEqual(quiet.dungeon.Tiles, busy.dungeon.Tiles) {
+		t.Error("floor 2 differs after extra draws from the game's RNG")
+	}
+}
+
+func TestEncounterDiceIgnoreExploreDraws(t *testing.T) {
+	rolls := func(extra int) []int {
+		g := newTestGame(t)
+		for range extra {
+			g.rng.Intn(100)
+		}
+		g.state = StateCombat
+		g.combatEnemies = []*entity.Enemy{entity.NewEnemy(entity.EnemyGoblin, 5, 5, 1)}
+		g.initCombatState(context.Background())
+		var out []int
+		for range 5 {
+			out = append(out, g.dice.Roll("test", 1000))
+		}
+		return out
+	}
+	if a, b := rolls(0), rolls(9); !slices.Equal(a, b) {
+		t.Errorf("encounter 1 rolled %v, then %v after extra explore draws", a, b)
+	}
+}
+
+func TestSeedsPanelNeedsDebug(t *testing.T) {
+	g := newTestGame(t)
+	press(g, 'p', 's')
+	if g.seedsOpen {
+		t.Error("seeds panel opened without -debug")
+	}
+
+	g.cfg.Debug = true
+	if !strings.Contains(g.pauseMenuPrompt(), "(s)eeds") {
+		t.Errorf("pause prompt = %q, want a seeds option", g.pauseMenuPrompt())
+	}
+	press(g, 'p', 's')
+	if !g.seedsOpen {
+		t.Fatal("seeds panel didn't open")
+	}
+	text := g.seedsOverlay().Text
+	for _, want := range []string{"Master seed 12345", "dungeon, floor 2", "combat, encounter 1", "loot, search 1"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("seeds panel missing %q:\n%s", want, text)
+		}
+	}
+	press(g, 'x')
+	if g.seedsOpen {
+		t.Error("any key should close the seeds panel")
+	}
+}
+
+func TestExportedSeedsVerify(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	g := startStatsCombat(t, entity.NewEnemy(entity.EnemyGoblin, 5, 5, 1))
+	g.cfg.Debug = true
+	g.floor = 2
+	g.generateDungeon(context.Background())
+
+	g.seedsOpen = true
+	press(g, 'e')
+	path, err := SeedLogPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("no seed log exported: %v", err)
+	}
+	log, err := seed.ReadLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := log.Verify(); err != nil {
+		t.Errorf("exported log doesn't verify: %v", err)
+	}
+	want := []seed.Use{
+		{Stream: seed.Combat, Index: 1, Seed: seed.Derive(12345, seed.Combat, 1)},
+		{Stream: seed.Dungeon, Index: 2, Seed: seed.Derive(12345, seed.Dungeon, 2)},
+	}
+	if len(log.Uses) != len(want) || log.Uses[0] != want[0] || log.Uses[1] != want[1] {
+		t.Errorf("exported uses = %+v, want %+v", log.Uses, want)
+	}
+}
@@ -0,0 +1,349 @@
+package game
+
+import (
+	"context"
+	"log"
+
+	"github.com/gdamore/tcell/v2"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+	"github.com/samdwyer/dungeonband/internal/telemetry"
+	"github.com/samdwyer/dungeonband/internal/ui"
+	combatapi "github.com/samdwyer/dungeonband/pkg/combat"
+)
+
+// pausePrompt is shown while the pause menu is open.
+const pausePrompt = "Paused: (r)esume, (a)bandon run, (q)uit"
+
+// debugPausePrompt is the pause menu in debug mode, which adds the seeds
+// panel.
+const debugPausePrompt = "Paused: (r)esume, (s)eeds, (a)bandon run, (q)uit"
+
+// pauseMenuPrompt returns the pause menu's prompt.
+func (g *Game) pauseMenuPrompt() string {
+	if g.cfg.Debug {
+		return debugPausePrompt
+	}
+	return pausePrompt
+}
+
+// handlePauseMenu resolves a key press while the pause menu is open.
+// Any key other than abandon or quit resumes play.
+func (g *Game) handlePauseMenu(ctx context.Context, ev *tcell.EventKey) {
+	g.paused = false
+	if ev.Key() != tcell.KeyRune {
+		return
+	}
+	switch ev.Rune() {
+	case 'a', 'A':
+		if g.canSave() {
+			g.confirmAbandon = true
+			return
+		}
+		g.abandon(ctx)
+	case 'q', 'Q':
+		g.running = false
+	case 's', 'S':
+		g.seedsOpen = g.cfg.Debug
+	}
+}
+
+// abandonPrompt asks whether to save a run before abandoning it.
+const abandonPrompt = "Save before abandoning? (y)es, (n)o, (c)ancel"
+
+// canSave reports whether the run can be saved to continue later. Saves
+// hold the floor as it is between fights, and the tutorial isn't saved.
+func (g *Game) canSave() bool {
+	return g.state == StateExplore && !g.tutorial
+}
+
+// handleAbandonPrompt resolves a key press on the save-before-abandoning
+// prompt. Any key other than yes or no keeps playing, as does a failed
+// save, so the run isn't lost.
+func (g *Game) handleAbandonPrompt(ctx context.Context, ev *tcell.EventKey) {
+	g.confirmAbandon = false
+	if ev.Key() != tcell.KeyRune {
+		return
+	}
+	switch ev.Rune() {
+	case 'y', 'Y':
+		if err := g.save(ctx); err != nil {
+			log.Printf("Warning: save failed: %v", err)
+			g.message = "Couldn't save the run."
+			return
+		}
+		g.abandon(ctx)
+	case 'n', 'N':
+		g.abandon(ctx)
+	}
+}
+
+// abandon ends the current run so the caller can return to the title screen.
+func (g *Game) abandon(ctx context.Context) {
+	tracer := telemetry.Tracer("game")
+	ctx, span := tracer.Start(ctx, "game.abandon")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("outcome", "abandoned"),
+		attribute.Int("floor", g.floor),
+		attribute.String("state", g.state.String()),
+	)
+	span.SetAttributes(g.runAttrs()...)
+
+	if g.state == StateCombat && g.combatState != nil {
+		g.endCombat(ctx, "abandoned")
+	}
+
+	g.abandoned = true
+	g.running = false
+}
+
+// gameOverPrompt is shown once the party has been defeated.
+const gameOverPrompt = "Your party has fallen: (r)etry same seed, (n)ew run, (q)uit"
+
+// encounterRetryPrompt is the game-over prompt when the fight that ended
+// the run can be retried.
+const encounterRetryPrompt = "Your party has fallen: (e)ncounter retry, (r)etry same seed, (n)ew run, (q)uit"
+
+// gameOverPrompt returns the game-over prompt.
+func (g *Game) gameOverPrompt() string {
+	if g.encounterStart != nil {
+		return encounterRetryPrompt
+	}
+	return gameOverPrompt
+}
+
+// endRun opens the game-over prompt after the party is defeated.
+func (g *Game) endRun(ctx context.Context) {
+	tracer := telemetry.Tracer("game")
+	_, span := tracer.Start(ctx, "game.over")
+	span.SetAttributes(
+		attribute.Int64("seed", g.seed),
+		attribute.Int("floor", g.floor),
+		attribute.Bool("practice", g.practice),
+	)
+	span.SetAttributes(g.runAttrs()...)
+	span.End()
+	g.gameOver = true
+}
+
+// handleGameOverKey resolves a key press on the game-over prompt. Other
+// keys leave it open.
+func (g *Game) handleGameOverKey(ctx context.Context, ev *tcell.EventKey) {
+	if ev.Key() == tcell.KeyCtrlC || ev.Key() == tcell.KeyEscape {
+		g.running = false
+		return
+	}
+	if ev.Key() != tcell.KeyRune {
+		return
+	}
+	switch ev.Rune() {
+	case 'e', 'E':
+		g.retryEncounter(ctx)
+	case 'r', 'R':
+		if err := g.retry(ctx); err != nil {
+			log.Printf("Warning: failed to retry seed %d: %v", g.seed, err)
+			g.running = false
+		}
+	case 'n', 'N':
+		g.newRun = true
+		g.running = false
+	case 'q', 'Q':
+		g.running = false
+	}
+}
+
+// retry starts the run over with the same configuration and seed, so the
+// party faces the identical dungeon and spawns. A run resumed from a save
+// starts over from the seed's first floor, not from the save. The display
+// carries over.
+func (g *Game) retry(ctx context.Context) error {
+	cfg := g.cfg
+	cfg.Resume = nil
+	fresh, err := New(cfg, g.display)
+	if err != nil {
+		return err
+	}
+
+	tracer := telemetry.Tracer("game")
+	_, span := tracer.Start(ctx, "game.retry")
+	span.SetAttributes(
+		attribute.Int64("seed", g.seed),
+		attribute.Int("floor", g.floor),
+	)
+	span.End()
+
+	*g = *fresh
+	g.setup(ctx)
+	return nil
+}
+
+// NewRunRequested reports whether the player asked for a fresh run with a
+// new seed from the game-over prompt.
+func (g *Game) NewRunRequested() bool {
+	return g.newRun
+}
+
+// Abandoned reports whether the run ended by abandoning it from the pause
+// menu, as opposed to quitting the game.
+func (g *Game) Abandoned() bool {
+	return g.abandoned
+}
+
+// TitleChoice is what the player picked on the title screen.
+type TitleChoice int
+
+const (
+	// TitleNewRun starts a fresh run with the chosen seed
+	TitleNewRun TitleChoice = iota
+	// TitleQuit exits the game
+	TitleQuit
+	// TitleTutorial starts the tutorial floor
+	TitleTutorial
+	// TitlePartySetup opens the party setup screen
+	TitlePartySetup
+	// TitleContinue resumes the run in the autosave slot
+	TitleContinue
+)
+
+// ShowTitle draws the title screen and waits for the player to start a new
+// run, start the tutorial or quit. Pressing 'r' replaces the offered seed with
+// one from reroll. suggestTutorial highlights the tutorial for new players,
+// and canContinue offers the saved run, which the caller loads with
+// LoadSave(AutosavePath()). Returns the choice and the seed for the new run.
+func ShowTitle(screen *ui.Screen, seed int64, reroll func() int64, suggestTutorial, canContinue bool) (TitleChoice, int64) {
+	renderer := ui.NewRenderer(screen)
+	screen.SetTitle("DungeonBand")
+
+	for {
+		renderer.RenderTitle(seed, suggestTutorial, canContinue)
+
+		switch ev := screen.PollEvent().(type) {
+		case *tcell.EventKey:
+			switch {
+			case ev.Key() == tcell.KeyEnter:
+				return TitleNewRun, seed
+			case ev.Key() == tcell.KeyEscape, ev.Key() == tcell.KeyCtrlC,
+				ev.Key() == tcell.KeyRune && (ev.Rune() == 'q' || ev.Rune() == 'Q'):
+				return TitleQuit, seed
+			case ev.Key() == tcell.KeyRune && (ev.Rune() == 'r' || ev.Rune() == 'R'):
+				seed = reroll()
+			case ev.Key() == tcell.KeyRune && (ev.Rune() == 't' || ev.Rune() == 'T'):
+				return TitleTutorial, seed
+			case ev.Key() == tcell.KeyRune && (ev.Rune() == 'p' || ev.Rune() == 'P'):
+				return TitlePartySetup, seed
+			case canContinue && ev.Key() == tcell.KeyRune && (ev.Rune() == 'c' || ev.Rune() == 'C'):
+				return TitleContinue, seed
+			}
+		case *tcell.EventResize:
+			screen.Sync()
+		case *tcell.EventInterrupt:
+			if _, ok := ev.Data().(shutdownRequest); ok {
+				return TitleQuit, seed
+			}
+		case nil:
+			// Screen was finalized
+			return TitleQuit, seed
+		}
+	}
+}
+
+// setupRegistries loads the classes and abilities the way New does: the
+// embedded data, or the data directory's overrides merged over it. The
+// ability registry is nil if only it failed to load.
+func setupRegistries(dataDir string) (*gamedata.ClassRegistry, *gamedata.AbilityRegistry, error) {
+	if dataDir != "" {
+		r, err := loadRegistries(dataDir)
+		return r.classes, r.abilities, err
+	}
+	classes, err := combatapi.DefaultClasses()
+	if err != nil {
+		return nil, nil, err
+	}
+	abilities, err := combatapi.DefaultAbilities()
+	if err != nil {
+		return classes, nil, nil
+	}
+	return classes, abilities, nil
+}
+
+// ShowPartySetup lets the player choose each member's class, comparing the
+// classes' stats and abilities as they go. Up and down pick a member, left
+// and right cycle their class, and Enter or Escape returns to the title
+// screen. classes is the current lineup; the chosen one is returned, with
+// quit set if the process was asked to shut down. dataDir is Config.DataDir,
+// so the classes compared are the ones the run will use.
+func ShowPartySetup(screen *ui.Screen, classes []entity.Class, dataDir string) (lineup []entity.Class, quit bool) {
+	registry, abilities, err := setupRegistries(dataDir)
+	if err != nil {
+		log.Printf("Warning: failed to load class registry: %v (party setup unavailable)", err)
+		return classes, false
+	}
+	abilityNames := make(map[string]string)
+	if abilities != nil {
+		for _, def := range registry.All() {
+			for _, id := range def.Abilities {
+				if a := abilities.GetByID(id); a != nil {
+					abilityNames[id] = a.Name
+				}
+			}
+		}
+	}
+
+	party := entity.NewParty(0, 0)
+	lineup = make([]entity.Class, len(party.Members))
+	for i, m := range party.Members {
+		lineup[i] = m.Class
+		if i < len(classes) {
+			lineup[i] = classes[i]
+		}
+	}
+
+	renderer := ui.NewRenderer(screen)
+	selected := 0
+	for {
+		setup := ui.PartySetup{Selected: selected, Classes: registry.All(), AbilityNames: abilityNames}
+		for i, m := range party.Members {
+			setup.Slots = append(setup.Slots, ui.PartySlot{Name: m.Name, Class: registry.GetByID(lineup[i].ID())})
+		}
+		renderer.RenderPartySetup(setup)
+
+		switch ev := screen.PollEvent().(type) {
+		case *tcell.EventKey:
+			switch ev.Key() {
+			case tcell.KeyEnter, tcell.KeyEscape, tcell.KeyCtrlC:
+				return lineup, false
+			case tcell.KeyUp:
+				selected = (selected + len(lineup) - 1) % len(lineup)
+			case tcell.KeyDown:
+				selected = (selected + 1) % len(lineup)
+			case tcell.KeyLeft:
+				lineup[selected] = cycleClass(lineup[selected], -1)
+			case tcell.KeyRight:
+				lineup[selected] = cycleClass(lineup[selected], 1)
+			}
+		case *tcell.EventResize:
+			screen.Sync()
+		case *tcell.EventInterrupt:
+			if _, ok := ev.Data().(shutdownRequest); ok {
+				return lineup, true
+			}
+		case nil:
+			return lineup, true
+		}
+	}
+}
+
+// cycleClass returns the class step places after class in entity.Classes,
+// wrapping around.
+func cycleClass(class entity.Class, step int) entity.Class {
+	n := len(entity.Classes)
+	for i, c := range entity.Classes {
+		if c == class {
+			return entity.Classes[((i+step)%n+n)%n]
+		}
+	}
+	return entity.Classes[0]
+}
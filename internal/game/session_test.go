@@ -0,0 +1,252 @@
+package game
+
+import (
+	"context"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/samdwyer/dungeonband/internal/ui"
+	"github.com/samdwyer/dungeonband/internal/world"
+)
+
+// pressRune builds a key event for a rune.
+func pressRune(r rune) *tcell.EventKey {
+	return tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone)
+}
+
+// newSharedScreen creates a simulation-backed screen shared by several runs.
+func newSharedScreen(t *testing.T) (*ui.Screen, tcell.SimulationScreen) {
+	t.Helper()
+	sim := tcell.NewSimulationScreen("UTF-8")
+	screen, err := ui.NewScreenFrom(sim)
+	if err != nil {
+		t.Fatalf("failed to create simulation screen: %v", err)
+	}
+	sim.SetSize(ui.MinScreenSize(world.DefaultWidth, world.DefaultHeight))
+	t.Cleanup(screen.Close)
+	return screen, sim
+}
+
+func TestPauseMenuResumes(t *testing.T) {
+	g := newTestGame(t)
+	ctx := context.Background()
+
+	g.handleKeyEvent(ctx, pressRune('p'))
+	if !g.paused {
+		t.Fatal("'p' should open the pause menu")
+	}
+	g.handleKeyEvent(ctx, pressRune('r'))
+	if g.paused || !g.running || g.Abandoned() {
+		t.Error("'r' should close the pause menu and keep playing")
+	}
+}
+
+func TestAbandonRunFromPauseMenu(t *testing.T) {
+	recorder := recordSpans(t)
+	g := newTestGame(t)
+	ctx := context.Background()
+	g.transitionState(ctx, StateCombat, "test")
+
+	g.handleKeyEvent(ctx, pressRune('p'))
+	g.handleKeyEvent(ctx, pressRune('a'))
+
+	if g.running || !g.Abandoned() {
+		t.Fatal("abandoning should stop the run and mark it abandoned")
+	}
+	span := findSpan(recorder, "game.abandon")
+	if span == nil {
+		t.Fatal("expected a game.abandon span")
+	}
+	if got := spanAttr(span, "outcome").AsString(); got != "abandoned" {
+		t.Errorf("game.abandon outcome = %q, want abandoned", got)
+	}
+	if end := findSpan(recorder, "combat.end"); end == nil || spanAttr(end, "outcome").AsString() != "abandoned" {
+		t.Error("abandoning mid-combat should end combat with outcome=abandoned")
+	}
+}
+
+func TestAbandonOffersToSave(t *testing.T) {
+	g, path := newSavingGame(t)
+	ctx := context.Background()
+
+	press(g, 'p', 'a')
+	if !g.confirmAbandon || !g.running {
+		t.Fatal("abandoning should ask about saving first")
+	}
+	press(g, 'c')
+	if g.confirmAbandon || !g.running || g.Abandoned() {
+		t.Fatal("'c' should cancel and keep playing")
+	}
+	if _, err := os.Stat(path); err == nil {
+		t.Fatal("saved without being asked to")
+	}
+
+	press(g, 'p', 'a', 'y')
+	if g.running || !g.Abandoned() {
+		t.Fatal("'y' should save and abandon the run")
+	}
+	save, err := LoadSave(path)
+	if err != nil {
+		t.Fatalf("abandoning with 'y' did not save: %v", err)
+	}
+	resumed, err := New(Config{Seed: save.Seed, Resume: save}, NullDisplay{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resumed.setup(ctx)
+	if got, want := resumed.StateHash(), g.StateHash(); got != want {
+		t.Errorf("continued run hash %x, want the abandoned run's %x", got, want)
+	}
+}
+
+func TestConsecutiveRunsShareScreen(t *testing.T) {
+	screen, sim := newSharedScreen(t)
+	ctx := context.Background()
+
+	// First run: pause and abandon
+	first, err := New(Config{Seed: 1}, NewTerminalDisplay(screen))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	_ = sim.PostEvent(pressRune('p'))
+	_ = sim.PostEvent(pressRune('a'))
+	_ = sim.PostEvent(pressRune('n'))
+	if err := first.Run(ctx); err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+	if !first.Abandoned() {
+		t.Fatal("first run should have been abandoned")
+	}
+
+	// Title screen picks a new seed on the same screen
+	rerolled := int64(99)
+	_ = sim.PostEvent(pressRune('r'))
+	_ = sim.PostEvent(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+	choice, seed := ShowTitle(screen, 2, func() int64 { return rerolled }, false, false)
+	if choice != TitleNewRun || seed != rerolled {
+		t.Fatalf("ShowTitle = (%v, %d), want new run with seed %d", choice, seed, rerolled)
+	}
+
+	// Second run on the same screen starts clean and quits normally
+	second, err := New(Config{Seed: seed}, NewTerminalDisplay(screen))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	_ = sim.PostEvent(pressRune('q'))
+	if err := second.Run(ctx); err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+	if second.Abandoned() {
+		t.Error("quitting should not count as abandoning")
+	}
+
+	for _, e := range second.enemies {
+		for _, old := range first.enemies {
+			if e == old {
+				t.Fatal("second run reused an enemy from the first run")
+			}
+		}
+	}
+	if second.party == first.party || second.stats == first.stats {
+		t.Error("second run should not share party or stats with the first")
+	}
+	if second.floor != 1 || second.seed != rerolled {
+		t.Errorf("second run floor/seed = %d/%d, want 1/%d", second.floor, second.seed, rerolled)
+	}
+}
+
+func TestTitleScreenContinue(t *testing.T) {
+	screen, sim := newSharedScreen(t)
+	_ = sim.PostEvent(pressRune('c'))
+	_ = sim.PostEvent(pressRune('q'))
+	if choice, _ := ShowTitle(screen, 5, func() int64 { return 6 }, false, false); choice != TitleQuit {
+		t.Errorf("choice without a save = %v, want 'c' ignored", choice)
+	}
+
+	_ = sim.PostEvent(pressRune('c'))
+	if choice, _ := ShowTitle(screen, 5, func() int64 { return 6 }, false, true); choice != TitleContinue {
+		t.Errorf("choice = %v, want TitleContinue", choice)
+	}
+}
+
+func TestTitleScreenQuit(t *testing.T) {
+	screen, sim := newSharedScreen(t)
+	_ = sim.PostEvent(pressRune('q'))
+
+	if choice, _ := ShowTitle(screen, 5, func() int64 { return 6 }, false, false); choice != TitleQuit {
+		t.Errorf("choice = %v, want TitleQuit", choice)
+	}
+}
+
+// layoutOf captures a floor's tiles and where each enemy stands.
+func layoutOf(g *Game) (tiles string, enemies []string) {
+	for _, row := range g.dungeon.Tiles {
+		for _, tile := range row {
+			tiles += string(rune(tile))
+		}
+		tiles += "\n"
+	}
+	for _, e := range g.enemies {
+		enemies = append(enemies, e.ID()+"@"+itoa(e.X)+","+itoa(e.Y))
+	}
+	return tiles, enemies
+}
+
+func TestRetryAfterDefeatReplaysSameSeed(t *testing.T) {
+	ctx := context.Background()
+	g, err := New(Config{Seed: 97}, NullDisplay{})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	g.setup(ctx)
+	tiles, enemies := layoutOf(g)
+	hash := g.StateHash()
+
+	// Wander off and lose a fight
+	for range 5 {
+		g.tryMove(ctx, 1, 0)
+		g.tryMove(ctx, 0, 1)
+	}
+	g.transitionState(ctx, StateCombat, "test")
+	for _, m := range g.party.Members {
+		m.TakeDamage(m.GetHP())
+	}
+	g.combatState.Phase = PhaseDefeat
+	g.handleKeyEvent(ctx, pressRune(' '))
+	if !g.gameOver {
+		t.Fatal("defeat did not open the game-over prompt")
+	}
+
+	press(g, 'r')
+
+	if g.gameOver || !g.running || g.seed != 97 {
+		t.Fatalf("after retry: gameOver=%v running=%v seed=%d", g.gameOver, g.running, g.seed)
+	}
+	gotTiles, gotEnemies := layoutOf(g)
+	if gotTiles != tiles {
+		t.Error("retry generated a different dungeon")
+	}
+	if !slices.Equal(gotEnemies, enemies) {
+		t.Errorf("retry spawned %v, want %v", gotEnemies, enemies)
+	}
+	if g.StateHash() != hash {
+		t.Error("retried run doesn't match the original run's starting state")
+	}
+}
+
+func TestNewRunFromGameOver(t *testing.T) {
+	g := newTestGame(t)
+	g.endRun(context.Background())
+
+	press(g, 'x') // Not an option; the prompt stays open
+	if !g.gameOver || !g.running {
+		t.Fatal("an unrelated key should leave the game-over prompt open")
+	}
+	press(g, 'n')
+	if g.running || !g.NewRunRequested() {
+		t.Error("'n' should end the run and ask for a new one")
+	}
+}
@@ -0,0 +1,33 @@
+//go:build unix
+
+package game
+
+import (
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestSIGTERMQuitsFromTitleScreen(t *testing.T) {
+	screen, _ := newSharedScreen(t)
+	stop := WatchSignals(NewTerminalDisplay(screen))
+	defer stop()
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatalf("failed to send SIGTERM: %v", err)
+	}
+
+	done := make(chan TitleChoice, 1)
+	go func() {
+		choice, _ := ShowTitle(screen, 5, func() int64 { return 6 }, false, false)
+		done <- choice
+	}()
+	select {
+	case choice := <-done:
+		if choice != TitleQuit {
+			t.Errorf("choice = %v, want TitleQuit", choice)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the title screen never saw the SIGTERM")
+	}
+}
@@ -0,0 +1,190 @@
+package game
+
+import (
+	"context"
+	"slices"
+
+	"github.com/gdamore/tcell/v2"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/telemetry"
+	"github.com/samdwyer/dungeonband/internal/ui"
+	"github.com/samdwyer/dungeonband/internal/world"
+)
+
+// shrineTitle heads the shrine menu.
+const shrineTitle = "A shrine hums with power. Choose a blessing:"
+
+// shrinePlacementAttempts bounds the search for a shrine tile free of
+// enemies before the floor goes without one.
+const shrinePlacementAttempts = 10
+
+// shrineBlessing is what a shrine can do for the party, once.
+type shrineBlessing int
+
+const (
+	blessingRestoreHP shrineBlessing = iota // Every living member to full HP
+	blessingRestoreMP                       // Every living member to full MP
+	blessingCleanse                         // Cure every living member's ailments
+)
+
+// shrineBlessings lists the blessings in menu order.
+var shrineBlessings = []shrineBlessing{blessingRestoreHP, blessingRestoreMP, blessingCleanse}
+
+// String returns the blessing's telemetry name.
+func (b shrineBlessing) String() string {
+	switch b {
+	case blessingRestoreHP:
+		return "restore_hp"
+	case blessingRestoreMP:
+		return "restore_mp"
+	case blessingCleanse:
+		return "cleanse"
+	default:
+		return "unknown"
+	}
+}
+
+// label returns the blessing's menu text.
+func (b shrineBlessing) label() string {
+	switch b {
+	case blessingRestoreHP:
+		return "Restore all HP"
+	case blessingRestoreMP:
+		return "Restore all MP"
+	default:
+		return "Cleanse all ailments"
+	}
+}
+
+// shrineMenu is the open shrine prompt.
+type shrineMenu struct {
+	shrine *world.Shrine
+	choice *ui.Choice
+}
+
+// placeShrine puts a shrine in one random room of the floor, never the
+// starting room or a room with a boss in it. It runs after spawning and
+// draws from the game's RNG, so a seed always places it the same way.
+func (g *Game) placeShrine() {
+	var rooms []int
+	for i := range g.dungeon.Rooms {
+		if i != g.startRoom && !g.bossInRoom(i) {
+			rooms = append(rooms, i)
+		}
+	}
+	if len(rooms) == 0 {
+		return
+	}
+	room := rooms[g.rng.Intn(len(rooms))]
+
+	for range shrinePlacementAttempts {
+		x, y, ok := g.dungeon.OpenPointInRoom(room)
+		if !ok {
+			return
+		}
+		if !enemyAt(g.enemies, x, y) {
+			g.dungeon.AddShrine(x, y)
+			return
+		}
+	}
+}
+
+// enemyAt returns true if a living enemy stands on the tile.
+func enemyAt(enemies []*entity.Enemy, x, y int) bool {
+	for _, e := range enemies {
+		if e.IsAlive() && e.X == x && e.Y == y {
+			return true
+		}
+	}
+	return false
+}
+
+// bossInRoom returns true if a boss spawned in the room.
+func (g *Game) bossInRoom(roomIndex int) bool {
+	for _, e := range g.enemies {
+		if e.RoomIndex == roomIndex && e.IsBoss() {
+			return true
+		}
+	}
+	return false
+}
+
+// checkShrine opens the shrine menu if the party stepped onto an unused
+// shrine.
+func (g *Game) checkShrine() {
+	shrine := g.dungeon.ShrineAt(g.party.X, g.party.Y)
+	if shrine == nil || shrine.Used {
+		return
+	}
+	labels := make([]string, len(shrineBlessings))
+	for i, b := range shrineBlessings {
+		labels[i] = b.label()
+	}
+	g.shrine = &shrineMenu{shrine: shrine, choice: ui.NewChoice(shrineTitle, labels...)}
+}
+
+// handleShrineKey passes a key press to the open shrine menu. Leaving the
+// menu keeps the shrine for later.
+func (g *Game) handleShrineKey(ctx context.Context, ev *tcell.EventKey) {
+	chosen, done := g.shrine.choice.HandleKey(ev)
+	if !done {
+		return
+	}
+	shrine := g.shrine.shrine
+	g.shrine = nil
+	if chosen < 0 {
+		g.message = "You leave the shrine for later."
+		return
+	}
+	g.useShrine(ctx, shrine, shrineBlessings[chosen])
+}
+
+// useShrine grants the blessing and spends the shrine. Returns false if the
+// shrine was already spent.
+func (g *Game) useShrine(ctx context.Context, shrine *world.Shrine, blessing shrineBlessing) bool {
+	if shrine.Used {
+		g.message = "The shrine is silent."
+		return false
+	}
+
+	restored := 0
+	for _, m := range g.aliveMembers() {
+		switch blessing {
+		case blessingRestoreHP:
+			restored += m.Heal(m.GetMaxHP())
+		case blessingRestoreMP:
+			restored += m.RestoreMP(m.GetMaxMP())
+		case blessingCleanse:
+			for _, e := range slices.Clone(m.GetStatusEffects()) {
+				if e.Type.IsNegative() {
+					m.RemoveStatusEffect(e.Type)
+					restored++
+				}
+			}
+		}
+	}
+	shrine.Used = true
+	g.stats.run.ShrinesUsed++
+
+	tracer := telemetry.Tracer("game")
+	_, span := tracer.Start(ctx, "game.shrine")
+	span.SetAttributes(
+		attribute.String("blessing", blessing.String()),
+		attribute.Int("restored", restored),
+		attribute.Int("floor", g.floor),
+		attribute.Int("run.shrines_used", g.stats.run.ShrinesUsed),
+	)
+	span.End()
+
+	switch blessing {
+	case blessingRestoreHP:
+		g.message = "The shrine's light mends the party's wounds."
+	case blessingRestoreMP:
+		g.message = "The shrine's light restores the party's focus."
+	case blessingCleanse:
+		g.message = "The shrine's light purges every ailment."
+	}
+	return true
+}
@@ -0,0 +1,161 @@
+package game
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/samdwyer/dungeonband/internal/combat"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+	"github.com/samdwyer/dungeonband/internal/world"
+)
+
+// shrineBesideParty puts an unused shrine just east of the party.
+func shrineBesideParty(t *testing.T, g *Game) *world.Shrine {
+	t.Helper()
+	x, y := g.party.X+1, g.party.Y
+	if !g.dungeon.IsPassable(x, y) {
+		t.Fatalf("no floor east of the party at (%d,%d)", x, y)
+	}
+	return g.dungeon.AddShrine(x, y)
+}
+
+func TestShrineBlessings(t *testing.T) {
+	tests := []struct {
+		key   rune
+		name  string
+		check func(t *testing.T, g *Game)
+	}{
+		{'1', "restore_hp", func(t *testing.T, g *Game) {
+			for _, m := range g.party.Members {
+				if m.GetHP() != m.GetMaxHP() {
+					t.Errorf("%s HP = %d/%d, want full", m.GetName(), m.GetHP(), m.GetMaxHP())
+				}
+			}
+		}},
+		{'2', "restore_mp", func(t *testing.T, g *Game) {
+			for _, m := range g.party.Members {
+				if m.GetMP() != m.GetMaxMP() {
+					t.Errorf("%s MP = %d/%d, want full", m.GetName(), m.GetMP(), m.GetMaxMP())
+				}
+			}
+		}},
+		{'3', "cleanse", func(t *testing.T, g *Game) {
+			wizard := g.party.Members[2]
+			if wizard.HasStatus(gamedata.StatusPoison) {
+				t.Error("poison should be cleansed")
+			}
+			if !wizard.HasStatus(gamedata.StatusRegen) {
+				t.Error("regen is not an ailment and should stay")
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := recordSpans(t)
+			g := newTestGame(t)
+			ctx := context.Background()
+			shrine := shrineBesideParty(t, g)
+			for _, m := range g.party.Members {
+				m.TakeDamage(m.GetMaxHP() / 2)
+				m.SpendMP(m.GetMP())
+			}
+			wizard := g.party.Members[2]
+			wizard.AddStatusEffect(combat.StatusEffect{Type: gamedata.StatusPoison, RemainingTurns: 5, Power: 1})
+			wizard.AddStatusEffect(combat.StatusEffect{Type: gamedata.StatusRegen, RemainingTurns: 5, Power: 1})
+
+			g.tryMove(ctx, 1, 0)
+			if g.shrine == nil {
+				t.Fatal("stepping on the shrine should open its menu")
+			}
+			g.handleKeyEvent(ctx, pressRune(tt.key))
+
+			if g.shrine != nil {
+				t.Error("choosing a blessing should close the menu")
+			}
+			if !shrine.Used {
+				t.Error("the shrine should be spent")
+			}
+			tt.check(t, g)
+			if got := g.stats.run.ShrinesUsed; got != 1 {
+				t.Errorf("run shrines used = %d, want 1", got)
+			}
+			span := findSpan(recorder, "game.shrine")
+			if span == nil {
+				t.Fatal("no game.shrine span")
+			}
+			if got := spanAttr(span, "blessing").AsString(); got != tt.name {
+				t.Errorf("blessing = %q, want %q", got, tt.name)
+			}
+		})
+	}
+}
+
+func TestShrineIsSingleUse(t *testing.T) {
+	g := newTestGame(t)
+	ctx := context.Background()
+	shrine := shrineBesideParty(t, g)
+
+	g.tryMove(ctx, 1, 0)
+	g.handleKeyEvent(ctx, pressRune('1'))
+	g.tryMove(ctx, -1, 0)
+	g.tryMove(ctx, 1, 0)
+
+	if g.shrine != nil {
+		t.Error("a spent shrine should not open its menu again")
+	}
+	if g.useShrine(ctx, shrine, blessingRestoreHP) {
+		t.Error("a spent shrine should refuse a second blessing")
+	}
+	if got := g.stats.run.ShrinesUsed; got != 1 {
+		t.Errorf("run shrines used = %d, want 1", got)
+	}
+}
+
+func TestLeavingShrineKeepsItForLater(t *testing.T) {
+	g := newTestGame(t)
+	ctx := context.Background()
+	shrine := shrineBesideParty(t, g)
+
+	g.tryMove(ctx, 1, 0)
+	g.handleKeyEvent(ctx, tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone))
+	if g.shrine != nil || shrine.Used {
+		t.Fatal("Esc should close the menu without spending the shrine")
+	}
+	if !g.running {
+		t.Fatal("Esc in the shrine menu should not quit")
+	}
+
+	g.tryMove(ctx, -1, 0)
+	g.tryMove(ctx, 1, 0)
+	if g.shrine == nil {
+		t.Error("an unspent shrine should offer its menu again")
+	}
+}
+
+func TestShrinePlacementFollowsTheSeed(t *testing.T) {
+	place := func(seed int64) *Game {
+		g, err := New(Config{Seed: seed}, NullDisplay{})
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		g.setup(context.Background())
+		return g
+	}
+
+	for seed := int64(1); seed <= 30; seed++ {
+		first, second := place(seed), place(seed)
+		shrines := first.dungeon.Shrines
+		if len(shrines) != 1 {
+			t.Errorf("seed %d: %d shrines, want 1", seed, len(shrines))
+			continue
+		}
+		if shrines[0] != second.dungeon.Shrines[0] {
+			t.Errorf("seed %d: shrine at %v then %v", seed, shrines[0], second.dungeon.Shrines[0])
+		}
+		if room := first.dungeon.RoomIndexAt(shrines[0].X, shrines[0].Y); room == first.startRoom || first.bossInRoom(room) {
+			t.Errorf("seed %d: shrine placed in room %d", seed, room)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package game
+
+import (
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/spectate"
+	"github.com/samdwyer/dungeonband/internal/ui"
+	"github.com/samdwyer/dungeonband/internal/world"
+)
+
+// Frame size for spectators when the local display has no size of its own.
+const (
+	spectatorWidth  = 100
+	spectatorHeight = 40
+)
+
+// FramePublisher receives each finished frame. *spectate.Server satisfies it.
+type FramePublisher interface {
+	Publish(grid *spectate.Grid, hud []string)
+}
+
+// spectatorDisplay draws to the local display and mirrors every frame into
+// an off-screen buffer, publishing the buffer once the frame is finished.
+type spectatorDisplay struct {
+	Display // Local display; input and housekeeping go here
+
+	buffer    *ui.Screen
+	mirror    *terminalDisplay
+	publisher FramePublisher
+
+	title string   // Window title, sent as the first HUD line
+	hud   []string // Text of this frame's overlays and combat message
+	dirty bool     // Drawn since the last publish
+}
+
+// NewSpectatorDisplay wraps a display so frames drawn to it are also sent to
+// the publisher. A frame counts as finished when the game next waits for
+// input.
+func NewSpectatorDisplay(local Display, publisher FramePublisher) (Display, error) {
+	width, height := local.Size()
+	if width == 0 || height == 0 {
+		width, height = spectatorWidth, spectatorHeight
+	}
+	buffer, err := ui.NewBufferScreen(width, height)
+	if err != nil {
+		return nil, err
+	}
+	return &spectatorDisplay{
+		Display:   local,
+		buffer:    buffer,
+		mirror:    &terminalDisplay{screen: buffer, renderer: ui.NewRenderer(buffer)},
+		publisher: publisher,
+	}, nil
+}
+
+// RenderExplore draws an explore-mode frame locally and for spectators.
+func (d *spectatorDisplay) RenderExplore(dungeon *world.Dungeon, party *entity.Party, enemies []*entity.Enemy, seed int64) {
+	d.Display.RenderExplore(dungeon, party, enemies, seed)
+	d.mirror.RenderExplore(dungeon, party, enemies, seed)
+	d.hud = nil
+	d.dirty = true
+}
+
+// RenderCombat draws a combat frame locally and for spectators.
+func (d *spectatorDisplay) RenderCombat(dungeon *world.Dungeon, party *entity.Party, enemies []*entity.Enemy, seed int64, info *ui.CombatInfo) {
+	d.Display.RenderCombat(dungeon, party, enemies, seed, info)
+	d.mirror.RenderCombat(dungeon, party, enemies, seed, info)
+	d.hud = nil
+	if info != nil && info.Message != "" {
+		d.hud = append(d.hud, info.Message)
+	}
+	d.dirty = true
+}
+
+// ShowOverlay draws the overlay locally and for spectators.
+func (d *spectatorDisplay) ShowOverlay(overlay Overlay) {
+	d.Display.ShowOverlay(overlay)
+	d.mirror.ShowOverlay(overlay)
+	if overlay.Kind == OverlayDebug {
+		d.dirty = true
+		return // The figures are for the local player's bug reports
+	}
+	text := overlay.Text
+	if overlay.Title != "" {
+		text = overlay.Title + ": " + text
+	}
+	d.hud = append(d.hud, text)
+	d.dirty = true
+}
+
+// SetTitle sets the local title and sends it to spectators.
+func (d *spectatorDisplay) SetTitle(title string) {
+	d.Display.SetTitle(title)
+	d.title = title
+	d.dirty = true
+}
+
+// SetEnemyVisibility sets the visibility rule locally and for spectators.
+func (d *spectatorDisplay) SetEnemyVisibility(rule world.Visibility) {
+	d.Display.SetEnemyVisibility(rule)
+	d.mirror.SetEnemyVisibility(rule)
+}
+
+// SetShowInitials sets how members are drawn locally and for spectators.
+func (d *spectatorDisplay) SetShowInitials(show bool) {
+	d.Display.SetShowInitials(show)
+	d.mirror.SetShowInitials(show)
+}
+
+// Sync redraws locally and keeps the spectator frame the local size.
+func (d *spectatorDisplay) Sync() {
+	d.Display.Sync()
+	if width, height := d.Display.Size(); width > 0 && height > 0 {
+		d.buffer.Resize(width, height)
+	}
+}
+
+// PollEvent publishes the finished frame, then waits for local input.
+func (d *spectatorDisplay) PollEvent() tcell.Event {
+	d.publish()
+	return d.Display.PollEvent()
+}
+
+// publish sends the buffer to spectators if anything was drawn since the
+// last publish.
+func (d *spectatorDisplay) publish() {
+	if !d.dirty {
+		return
+	}
+	d.dirty = false
+
+	cells, width, height, err := d.buffer.Contents()
+	if err != nil {
+		return
+	}
+	grid := spectate.NewGrid(width, height)
+	for i, cell := range cells {
+		r := ' '
+		if len(cell.Runes) > 0 {
+			r = cell.Runes[0]
+		}
+		grid.Cells[i] = spectate.Cell{Rune: r, Style: cell.Style}
+	}
+
+	var hud []string
+	if d.title != "" {
+		hud = append(hud, d.title)
+	}
+	d.publisher.Publish(grid, append(hud, d.hud...))
+}
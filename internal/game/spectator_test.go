@@ -0,0 +1,108 @@
+package game
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/samdwyer/dungeonband/internal/spectate"
+)
+
+// scriptedDisplay is a headless display that feeds the game a fixed list
+// of events, then reports itself closed.
+type scriptedDisplay struct {
+	NullDisplay
+	events []tcell.Event
+}
+
+func (d *scriptedDisplay) PollEvent() tcell.Event {
+	if len(d.events) == 0 {
+		return nil
+	}
+	ev := d.events[0]
+	d.events = d.events[1:]
+	return ev
+}
+
+func TestSpectatorReceivesCoherentFrames(t *testing.T) {
+	server, err := spectate.Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	conn, err := net.Dial("tcp", server.Addr().String())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	for deadline := time.Now().Add(2 * time.Second); server.Clients() == 0; time.Sleep(5 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("viewer never connected")
+		}
+	}
+
+	// Read frames as they arrive so the server never has to queue them
+	frames := make(chan *spectate.Frame)
+	go func() {
+		defer close(frames)
+		r := bufio.NewReader(conn)
+		for {
+			f, err := spectate.ReadFrame(r)
+			if err != nil {
+				return
+			}
+			frames <- f
+		}
+	}()
+
+	local := &scriptedDisplay{events: []tcell.Event{
+		tcell.NewEventKey(tcell.KeyRune, 'l', tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyRune, 'j', tcell.ModNone),
+	}}
+	display, err := NewSpectatorDisplay(local, server)
+	if err != nil {
+		t.Fatalf("NewSpectatorDisplay: %v", err)
+	}
+	g, err := New(Config{Seed: 42}, display)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := g.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	server.Close()
+
+	var v spectate.Viewer
+	count := 0
+	for f := range frames {
+		if count == 0 && !f.Key {
+			t.Fatal("the stream should open with a key frame")
+		}
+		if err := v.Apply(f); err != nil {
+			t.Fatalf("frame %d: %v", count, err)
+		}
+		count++
+	}
+	if count != 3 {
+		t.Errorf("received %d frames, want 3 (one per input wait)", count)
+	}
+
+	// The rebuilt grid matches what the spectator display last drew
+	buffer := display.(*spectatorDisplay).buffer
+	cells, width, height, _ := buffer.Contents()
+	grid := v.Grid()
+	if grid.Width != width || grid.Height != height {
+		t.Fatalf("viewer grid is %dx%d, want %dx%d", grid.Width, grid.Height, width, height)
+	}
+	for i, cell := range cells {
+		if len(cell.Runes) > 0 && grid.Cells[i].Rune != cell.Runes[0] {
+			t.Fatalf("cell %d = %q, want %q", i, grid.Cells[i].Rune, cell.Runes[0])
+		}
+	}
+	if hud := v.HUD(); len(hud) == 0 || hud[0] != windowTitle(g.floor, g.seed, g.state) {
+		t.Errorf("HUD = %q, want the window title first", hud)
+	}
+}
@@ -0,0 +1,90 @@
+package game
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/samdwyer/dungeonband/internal/combat"
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+)
+
+// loadDifficulty returns the named difficulty from the embedded data, or
+// the default one for "". If the data can't be loaded the run keeps the
+// default start; an unknown name is an error.
+func loadDifficulty(id string) (*gamedata.DifficultyDef, error) {
+	if id == "" {
+		id = gamedata.DefaultDifficulty
+	}
+	defs, err := gamedata.LoadDifficulties()
+	if err != nil {
+		log.Printf("Warning: failed to load difficulties: %v (using the default start)", err)
+		return nil, nil
+	}
+	items := make([]string, len(partyItems))
+	for i, item := range partyItems {
+		items[i] = string(item)
+	}
+	var names []string
+	for i := range defs {
+		def := &defs[i]
+		if err := def.Validate(items); err != nil {
+			log.Printf("Warning: skipping difficulty: %v", err)
+			continue
+		}
+		if def.ID == id {
+			return def, nil
+		}
+		names = append(names, def.ID)
+	}
+	return nil, fmt.Errorf("unknown difficulty %q (have %s)", id, strings.Join(names, ", "))
+}
+
+// applyPartyClasses gives the new party the classes chosen in party setup.
+func (g *Game) applyPartyClasses() {
+	for i, class := range g.cfg.PartyClasses {
+		var def *gamedata.ClassDef
+		if g.classRegistry != nil {
+			def = g.classRegistry.GetByID(class.ID())
+		}
+		g.party.Members[i].SetClass(class, def)
+	}
+}
+
+// applyDifficultyStart equips the new party for the run's difficulty and
+// says what it set out with. A difficulty without a start section leaves
+// the default party alone.
+func (g *Game) applyDifficultyStart() {
+	if g.difficulty == nil || g.difficulty.Start == nil {
+		return
+	}
+	start := g.difficulty.Start
+
+	g.party.Items = make(map[entity.Item]int)
+	var supplies []string
+	for _, grant := range start.Items {
+		item := entity.Item(grant.Item)
+		g.party.AddItem(item, grant.Count)
+		supplies = append(supplies, itoa(grant.Count)+" "+item.Name())
+	}
+
+	var afflictions []string
+	for _, s := range start.Statuses {
+		for _, m := range g.party.Members {
+			m.AddStatusEffect(combat.StatusEffect{Type: s.Status, RemainingTurns: s.Duration, Power: s.Power})
+		}
+		afflictions = append(afflictions, s.Label())
+	}
+
+	message := g.difficulty.Name + ": the party sets out with "
+	if len(supplies) == 0 {
+		message += "nothing."
+	} else {
+		message += strings.Join(supplies, ", ") + "."
+	}
+	if len(afflictions) > 0 {
+		message += " Everyone is " + strings.Join(afflictions, " and ") + "."
+	}
+	g.message = message
+}
@@ -0,0 +1,142 @@
+package game
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+)
+
+// startRun sets up a run on the given difficulty.
+func startRun(t *testing.T, difficulty string) *Game {
+	t.Helper()
+	g, err := New(Config{Seed: 42, Difficulty: difficulty}, NullDisplay{})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	g.setup(context.Background())
+	return g
+}
+
+func TestDifficultyStartingState(t *testing.T) {
+	tests := []struct {
+		difficulty string
+		ropes      int
+		fatigued   bool
+		message    string
+	}{
+		{"easy", 3, false, "Easy: the party sets out with 3 Escape Rope."},
+		{"normal", entity.StartingEscapeRopes, false, ""},
+		{"hard", 0, false, "Hard: the party sets out with nothing."},
+		{"nightmare", 0, true, "Nightmare: the party sets out with nothing. Everyone is Fatigued."},
+	}
+	for _, tt := range tests {
+		g := startRun(t, tt.difficulty)
+		if got := g.party.ItemCount(entity.ItemEscapeRope); got != tt.ropes {
+			t.Errorf("%s: %d escape ropes, want %d", tt.difficulty, got, tt.ropes)
+		}
+		for _, m := range g.party.Members {
+			if got := m.HasStatus(gamedata.StatusAttackDown); got != tt.fatigued {
+				t.Errorf("%s: %s attack_down = %v, want %v", tt.difficulty, m.Name, got, tt.fatigued)
+			}
+		}
+		if g.message != tt.message {
+			t.Errorf("%s: message = %q, want %q", tt.difficulty, g.message, tt.message)
+		}
+	}
+}
+
+func TestDefaultDifficultyKeepsDefaultStart(t *testing.T) {
+	g := startRun(t, "")
+	if g.difficulty == nil || g.difficulty.ID != gamedata.DefaultDifficulty || g.difficulty.Start != nil {
+		t.Fatalf("difficulty = %+v, want normal without a start section", g.difficulty)
+	}
+	if got := g.party.ItemCount(entity.ItemEscapeRope); got != entity.StartingEscapeRopes {
+		t.Errorf("%d escape ropes, want the default %d", got, entity.StartingEscapeRopes)
+	}
+	for _, m := range g.party.Members {
+		if len(m.GetStatusEffects()) != 0 {
+			t.Errorf("%s starts with %v, want no statuses", m.Name, m.GetStatusEffects())
+		}
+	}
+}
+
+func TestUnknownDifficultyIsAnError(t *testing.T) {
+	_, err := New(Config{Difficulty: "legendary"}, NullDisplay{})
+	if err == nil || !strings.Contains(err.Error(), "legendary") {
+		t.Errorf("New = %v, want an unknown difficulty error", err)
+	}
+}
+
+func TestPartySetupCyclesClasses(t *testing.T) {
+	screen, sim := newSharedScreen(t)
+	_ = sim.PostEvent(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone))
+	_ = sim.PostEvent(tcell.NewEventKey(tcell.KeyRight, 0, tcell.ModNone))
+	_ = sim.PostEvent(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+
+	got, quit := ShowPartySetup(screen, nil, "")
+	want := []entity.Class{entity.ClassWarrior, entity.ClassWizard, entity.ClassWizard, entity.ClassCleric}
+	if !slices.Equal(got, want) || quit {
+		t.Errorf("lineup = %v (quit %v), want %v", got, quit, want)
+	}
+}
+
+func TestPartyClassesSetMemberStats(t *testing.T) {
+	g, err := New(Config{Seed: 7, PartyClasses: []entity.Class{entity.ClassCleric}}, NullDisplay{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.setup(context.Background())
+
+	first := g.party.Members[0]
+	cleric := g.classRegistry.GetByID("cleric")
+	if first.Class != entity.ClassCleric || first.MaxHP != cleric.HP || !slices.Equal(first.AbilityIDs, cleric.Abilities) {
+		t.Errorf("first member is a %v with %d HP, want a cleric with %d", first.Class, first.MaxHP, cleric.HP)
+	}
+	if g.party.Members[1].Class != entity.ClassRogue {
+		t.Error("members past the chosen classes should keep their default class")
+	}
+
+	if _, err := New(Config{PartyClasses: make([]entity.Class, entity.PartySize+1)}, NullDisplay{}); err == nil {
+		t.Error("more classes than members should be rejected")
+	}
+}
+
+func TestPartyClassesWithoutDataResetStats(t *testing.T) {
+	g, err := New(Config{Seed: 7, PartyClasses: []entity.Class{entity.ClassCleric}}, NullDisplay{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.setup(context.Background())
+	g.classRegistry = nil
+	g.applyPartyClasses()
+
+	first, defaults := g.party.Members[0], entity.NewMember("", entity.ClassCleric)
+	if first.MaxHP != defaults.MaxHP || first.Attack != defaults.Attack || !slices.Equal(first.AbilityIDs, defaults.AbilityIDs) {
+		t.Errorf("cleric without class data has %d HP, %d attack and %v, want the defaults",
+			first.MaxHP, first.Attack, first.AbilityIDs)
+	}
+}
+
+func TestPartySetupUsesTheDataDirectory(t *testing.T) {
+	dir := t.TempDir()
+	content := `{"schemaVersion": 2, "abilities": [{"id": "attack", "name": "Cleave", "description": "A basic physical attack",
+		"effectType": "damage", "targetType": "single_enemy", "damageType": "physical", "basePower": 5}]}`
+	if err := os.WriteFile(filepath.Join(dir, gamedata.AbilitiesFileName), []byte(content), 0o644); err != nil {
+		t.Fatalf("write override: %v", err)
+	}
+	screen, sim := newSharedScreen(t)
+	_ = sim.PostEvent(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+
+	ShowPartySetup(screen, nil, dir)
+	if text := screenText(sim); !strings.Contains(text, "Cleave") {
+		t.Errorf("party setup doesn't show the overridden ability name:\n%s", text)
+	}
+}
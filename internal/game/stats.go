@@ -0,0 +1,301 @@
+package game
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/samdwyer/dungeonband/internal/combat"
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+)
+
+// memberStats tallies what one party member did in combat.
+type memberStats struct {
+	DamageDealt   int `json:"damageDealt,omitempty"`
+	DamageTaken   int `json:"damageTaken,omitempty"`
+	HealingDone   int `json:"healingDone,omitempty"`
+	Kills         int `json:"kills,omitempty"`
+	TurnsSilenced int `json:"turnsSilenced,omitempty"`
+}
+
+// add accumulates another tally into this one.
+func (s *memberStats) add(o *memberStats) {
+	s.DamageDealt += o.DamageDealt
+	s.DamageTaken += o.DamageTaken
+	s.HealingDone += o.HealingDone
+	s.Kills += o.Kills
+	s.TurnsSilenced += o.TurnsSilenced
+}
+
+// runStats tallies run-wide events that belong to no one member.
+type runStats struct {
+	ShrinesUsed int
+}
+
+// statusKey identifies a status effect on a specific combatant.
+type statusKey struct {
+	target combat.Combatant
+	status gamedata.StatusEffectType
+}
+
+// statsCollector aggregates per-member stats for the current combat and
+// lifetime totals across the run, plus run-wide tallies.
+type statsCollector struct {
+	combat   map[*entity.Member]*memberStats
+	lifetime map[*entity.Member]*memberStats
+	sources  map[statusKey]*entity.Member // Who applied each DoT/HoT, for tick attribution
+	run      runStats
+	dangers  []encounterDanger // Predicted vs realized danger of each fight, oldest first
+	defeated []string          // Names of enemies killed this combat, in order
+}
+
+// newStatsCollector creates an empty stats collector.
+func newStatsCollector() *statsCollector {
+	return &statsCollector{
+		combat:   make(map[*entity.Member]*memberStats),
+		lifetime: make(map[*entity.Member]*memberStats),
+		sources:  make(map[statusKey]*entity.Member),
+	}
+}
+
+// startCombat clears the per-combat tallies.
+func (c *statsCollector) startCombat() {
+	c.combat = make(map[*entity.Member]*memberStats)
+	c.sources = make(map[statusKey]*entity.Member)
+	c.defeated = nil
+}
+
+// finishCombat folds the per-combat tallies into the lifetime totals.
+func (c *statsCollector) finishCombat() {
+	for m, s := range c.combat {
+		c.lifetimeFor(m).add(s)
+	}
+	c.combat = make(map[*entity.Member]*memberStats)
+	c.sources = make(map[statusKey]*entity.Member)
+	c.defeated = nil
+}
+
+// combatTally is a copy of the current combat's tallies, for putting them
+// back after a rewind.
+type combatTally struct {
+	combat   map[*entity.Member]memberStats
+	sources  map[statusKey]*entity.Member
+	defeated []string
+}
+
+// saveCombat copies the current combat's tallies.
+func (c *statsCollector) saveCombat() combatTally {
+	t := combatTally{
+		combat:   make(map[*entity.Member]memberStats, len(c.combat)),
+		sources:  maps.Clone(c.sources),
+		defeated: slices.Clone(c.defeated),
+	}
+	for m, s := range c.combat {
+		t.combat[m] = *s
+	}
+	return t
+}
+
+// restoreCombat puts back tallies saved by saveCombat.
+func (c *statsCollector) restoreCombat(t combatTally) {
+	c.combat = make(map[*entity.Member]*memberStats, len(t.combat))
+	for m, s := range t.combat {
+		c.combat[m] = &s
+	}
+	c.sources = maps.Clone(t.sources)
+	c.defeated = slices.Clone(t.defeated)
+}
+
+// recordEncounter keeps a finished fight's predicted and realized danger.
+func (c *statsCollector) recordEncounter(danger encounterDanger) {
+	c.dangers = append(c.dangers, danger)
+}
+
+// statsFor returns the member's tally for the current combat.
+func (c *statsCollector) statsFor(m *entity.Member) *memberStats {
+	s, ok := c.combat[m]
+	if !ok {
+		s = &memberStats{}
+		c.combat[m] = s
+	}
+	return s
+}
+
+// lifetimeFor returns the member's lifetime totals.
+func (c *statsCollector) lifetimeFor(m *entity.Member) *memberStats {
+	s, ok := c.lifetime[m]
+	if !ok {
+		s = &memberStats{}
+		c.lifetime[m] = s
+	}
+	return s
+}
+
+// recordAction credits the outcome of one resolved ability.
+func (c *statsCollector) recordAction(user, target combat.Combatant, result combat.EffectResult, killed bool) {
+	if !result.Success {
+		return
+	}
+	if _, ok := target.(*entity.Enemy); ok && killed {
+		c.defeated = append(c.defeated, target.GetName())
+	}
+	if m, ok := target.(*entity.Member); ok {
+		c.statsFor(m).DamageTaken += result.Damage
+	}
+	if m, ok := user.(*entity.Member); ok {
+		s := c.statsFor(m)
+		s.DamageDealt += result.Damage
+		s.HealingDone += result.Healing
+		if killed {
+			s.Kills++
+		}
+	}
+	if result.StatusAdded != "" {
+		key := statusKey{target, result.StatusAdded}
+		if m, ok := user.(*entity.Member); ok {
+			c.sources[key] = m
+		} else {
+			delete(c.sources, key)
+		}
+	}
+}
+
+// recordTick credits a status effect tick to whoever applied the effect.
+func (c *statsCollector) recordTick(target combat.Combatant, tick combat.StatusTick, killed bool) {
+	key := statusKey{target, tick.Type}
+	source := c.sources[key]
+	if tick.Ended {
+		delete(c.sources, key)
+	}
+	if _, ok := target.(*entity.Enemy); ok && killed {
+		c.defeated = append(c.defeated, target.GetName())
+	}
+
+	switch tick.Type {
+	case gamedata.StatusPoison:
+		if m, ok := target.(*entity.Member); ok {
+			c.statsFor(m).DamageTaken += tick.Amount
+		}
+		if source != nil {
+			c.statsFor(source).DamageDealt += tick.Amount
+			if killed {
+				c.statsFor(source).Kills++
+			}
+		}
+	case gamedata.StatusRegen:
+		if source != nil {
+			c.statsFor(source).HealingDone += tick.Amount
+		}
+	}
+}
+
+// mvp returns the member who dealt the most damage this combat, falling back
+// to the biggest healer. Returns nil if nobody did either.
+func (c *statsCollector) mvp(party *entity.Party) (*entity.Member, *memberStats) {
+	var best *entity.Member
+	var bestStats *memberStats
+	for _, m := range party.Members {
+		s, ok := c.combat[m]
+		if !ok {
+			continue
+		}
+		if best == nil || s.DamageDealt > bestStats.DamageDealt ||
+			(s.DamageDealt == bestStats.DamageDealt && s.HealingDone > bestStats.HealingDone) {
+			best, bestStats = m, s
+		}
+	}
+	if best == nil || (bestStats.DamageDealt == 0 && bestStats.HealingDone == 0) {
+		return nil, nil
+	}
+	return best, bestStats
+}
+
+// mvpLine formats the MVP callout for the victory message, e.g.
+// "MVP: Zephyr — 64 damage". Returns "" when there is no MVP.
+func (c *statsCollector) mvpLine(party *entity.Party) string {
+	m, s := c.mvp(party)
+	if m == nil {
+		return ""
+	}
+	if s.DamageDealt > 0 {
+		return "MVP: " + m.GetName() + " — " + itoa(s.DamageDealt) + " damage"
+	}
+	return "MVP: " + m.GetName() + " — " + itoa(s.HealingDone) + " healing"
+}
+
+// hpDelta is the party's net HP change this combat: healing done by the
+// party less the damage it took.
+func (c *statsCollector) hpDelta() int {
+	delta := 0
+	for _, s := range c.combat {
+		delta += s.HealingDone - s.DamageTaken
+	}
+	return delta
+}
+
+// summaryLine condenses the current combat into one log line, e.g.
+// "Defeated 2 Goblins and an Orc in 6 turns (−14 HP)", where turns counts
+// the fight's rounds. Returns "" for a fight where nothing was killed.
+func (c *statsCollector) summaryLine(turns int) string {
+	if len(c.defeated) == 0 {
+		return ""
+	}
+	line := "Defeated " + countedNames(c.defeated) + " in " + itoa(turns) + " turn"
+	if turns != 1 {
+		line += "s"
+	}
+	switch delta := c.hpDelta(); {
+	case delta < 0:
+		line += " (−" + itoa(-delta) + " HP)"
+	case delta > 0:
+		line += " (+" + itoa(delta) + " HP)"
+	}
+	return line
+}
+
+// countedNames groups identical names with counts, in order of first
+// appearance: "2 Goblins and an Orc".
+func countedNames(names []string) string {
+	var order []string
+	counts := make(map[string]int)
+	for _, name := range names {
+		if counts[name] == 0 {
+			order = append(order, name)
+		}
+		counts[name]++
+	}
+
+	parts := make([]string, len(order))
+	for i, name := range order {
+		if n := counts[name]; n > 1 {
+			parts[i] = itoa(n) + " " + plural(name)
+		} else {
+			parts[i] = article(name) + " " + name
+		}
+	}
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	return strings.Join(parts[:len(parts)-1], ", ") + " and " + parts[len(parts)-1]
+}
+
+// plural returns the English plural of an enemy name.
+func plural(name string) string {
+	switch {
+	case strings.HasSuffix(name, "s"), strings.HasSuffix(name, "x"), strings.HasSuffix(name, "ch"):
+		return name + "es"
+	case strings.HasSuffix(name, "y") && len(name) > 1 && !strings.ContainsRune("aeiou", rune(name[len(name)-2])):
+		return name[:len(name)-1] + "ies"
+	}
+	return name + "s"
+}
+
+// article returns "an" for names starting with a vowel, else "a".
+func article(name string) string {
+	if name != "" && strings.ContainsRune("aeiou", unicode.ToLower(rune(name[0]))) {
+		return "an"
+	}
+	return "a"
+}
@@ -0,0 +1,164 @@
+package game
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+)
+
+// startStatsCombat puts the test game into combat against the given enemies.
+func startStatsCombat(t *testing.T, enemies ...*entity.Enemy) *Game {
+	t.Helper()
+	g := newTestGame(t)
+	g.state = StateCombat
+	g.combatEnemies = enemies
+	g.initCombatState(context.Background())
+	return g
+}
+
+func TestStatsAttributeMultiTargetDamage(t *testing.T) {
+	first := entity.NewEnemy(entity.EnemyGoblin, 5, 5, 1)
+	second := entity.NewEnemy(entity.EnemyOrc, 6, 5, 1)
+	g := startStatsCombat(t, first, second)
+	ctx := context.Background()
+	wizard := g.party.Members[2]
+	fireball := g.abilityRegistry.GetByID("fireball")
+	wizard.RestoreMP(100)
+
+	// Multi-target abilities resolve once per target
+	g.executeCombatTurn(ctx, fireball, wizard, first)
+	dealt := first.MaxHP - first.HP
+	g.executeCombatTurn(ctx, fireball, wizard, second)
+	dealt += second.MaxHP - second.HP
+
+	s := g.stats.statsFor(wizard)
+	if s.DamageDealt != dealt {
+		t.Errorf("DamageDealt = %d, want %d", s.DamageDealt, dealt)
+	}
+	if s.Kills != 2 {
+		t.Errorf("Kills = %d, want 2", s.Kills)
+	}
+}
+
+func TestStatsAttributeDoTToApplier(t *testing.T) {
+	goblin := entity.NewEnemy(entity.EnemyGoblin, 5, 5, 1)
+	g := startStatsCombat(t, goblin)
+	ctx := context.Background()
+	rogue := g.party.Members[1]
+
+	g.executeCombatTurn(ctx, g.abilityRegistry.GetByID("poison_strike"), rogue, goblin)
+	hit := g.stats.statsFor(rogue).DamageDealt
+
+	goblin.HP = 2 // The next poison tick (2 damage) finishes it off
+	g.tickCombatStatuses(context.Background())
+
+	s := g.stats.statsFor(rogue)
+	if s.DamageDealt != hit+2 {
+		t.Errorf("DamageDealt = %d, want %d (strike plus poison tick)", s.DamageDealt, hit+2)
+	}
+	if s.Kills != 1 {
+		t.Errorf("Kills = %d, want 1 from the poison tick", s.Kills)
+	}
+}
+
+func TestStatsDamageTaken(t *testing.T) {
+	goblin := entity.NewEnemy(entity.EnemyGoblin, 5, 5, 1)
+	g := startStatsCombat(t, goblin)
+	warrior := g.party.Members[0]
+
+	g.executeCombatTurn(context.Background(), g.abilityRegistry.GetByID("attack"), goblin, warrior)
+
+	if got, want := g.stats.statsFor(warrior).DamageTaken, warrior.MaxHP-warrior.HP; got != want {
+		t.Errorf("DamageTaken = %d, want %d", got, want)
+	}
+}
+
+func TestVictoryCallsOutMVP(t *testing.T) {
+	goblin := entity.NewEnemy(entity.EnemyGoblin, 5, 5, 1)
+	g := startStatsCombat(t, goblin)
+	wizard := g.party.Members[2]
+	g.stats.statsFor(g.party.Members[0]).DamageDealt = 5
+	g.stats.statsFor(wizard).DamageDealt = 64
+
+	goblin.TakeDamage(1000)
+	g.checkCombatEnd()
+
+	want := "MVP: " + wizard.GetName() + " — 64 damage"
+	if !strings.HasSuffix(g.combatState.LastMessage, want) {
+		t.Errorf("LastMessage = %q, want suffix %q", g.combatState.LastMessage, want)
+	}
+}
+
+func TestLifetimeStatsAccumulate(t *testing.T) {
+	g := startStatsCombat(t, entity.NewEnemy(entity.EnemyGoblin, 5, 5, 1))
+	ctx := context.Background()
+	cleric := g.party.Members[3]
+
+	for i := 0; i < 2; i++ {
+		g.stats.startCombat()
+		g.stats.statsFor(cleric).HealingDone = 7
+		g.endCombat(ctx, "victory")
+	}
+
+	if got := g.stats.lifetimeFor(cleric).HealingDone; got != 14 {
+		t.Errorf("lifetime HealingDone = %d, want 14", got)
+	}
+	if len(g.stats.combat) != 0 {
+		t.Error("per-combat stats should reset when combat ends")
+	}
+}
+
+func TestCountedNamesGroupsAndPluralizes(t *testing.T) {
+	tests := []struct {
+		names []string
+		want  string
+	}{
+		{[]string{"Goblin"}, "a Goblin"},
+		{[]string{"Orc"}, "an Orc"},
+		{[]string{"Goblin", "Orc", "Goblin"}, "2 Goblins and an Orc"},
+		{[]string{"Cultist", "Training Dummy", "Training Dummy", "Skeleton"}, "a Cultist, 2 Training Dummies and a Skeleton"},
+	}
+	for _, tt := range tests {
+		if got := countedNames(tt.names); got != tt.want {
+			t.Errorf("countedNames(%v) = %q, want %q", tt.names, got, tt.want)
+		}
+	}
+}
+
+func TestSummaryLineReportsTurnsAndHP(t *testing.T) {
+	c := newStatsCollector()
+	warrior := entity.NewMember("Aldric", entity.ClassWarrior)
+	c.statsFor(warrior).DamageTaken = 20
+	c.statsFor(warrior).HealingDone = 6
+	c.defeated = []string{"Goblin", "Goblin", "Orc"}
+
+	if got, want := c.summaryLine(6), "Defeated 2 Goblins and an Orc in 6 turns (−14 HP)"; got != want {
+		t.Errorf("summaryLine = %q, want %q", got, want)
+	}
+	c.statsFor(warrior).HealingDone = 20
+	if got, want := c.summaryLine(1), "Defeated 2 Goblins and an Orc in 1 turn"; got != want {
+		t.Errorf("summaryLine = %q, want %q", got, want)
+	}
+}
+
+func TestVictoryLeavesSummaryInExploreLog(t *testing.T) {
+	goblin := entity.NewEnemy(entity.EnemyGoblin, 5, 5, 1)
+	g := startStatsCombat(t, goblin)
+	ctx := context.Background()
+
+	// Three rounds in, with many more actions than rounds
+	g.combatState.Round, g.combatState.TurnCount = 3, 17
+	goblin.HP = 1
+	g.executeCombatTurn(ctx, g.abilityRegistry.GetByID("attack"), g.party.Members[0], goblin)
+	g.checkCombatEnd()
+	g.handleCombatEnd(ctx)
+
+	if g.state != StateExplore {
+		t.Fatalf("state = %v after victory", g.state)
+	}
+	if !strings.HasPrefix(g.message, "Defeated a Goblin in 3 turns") || !strings.HasSuffix(g.message, fullLogMarker) {
+		t.Errorf("explore message = %q, want the fight's summary", g.message)
+	}
+}
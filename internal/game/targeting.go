@@ -0,0 +1,341 @@
+package game
+
+import (
+	"context"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+	"github.com/samdwyer/dungeonband/internal/ui"
+)
+
+// beginTargeting enters target selection for a single-target ability.
+// Offensive abilities start on the first reachable enemy, or with FocusKills
+// on the toughest one the ability kills outright. Heals preselect the most
+// wounded ally and cleanses the first afflicted one; other ally abilities
+// start on the caster.
+func (g *Game) beginTargeting(ability *gamedata.AbilityDef) {
+	g.combatState.SelectedAbility = ability
+	g.combatState.Phase = PhaseSelectTarget
+	g.combatState.TargetOnAllies = !ability.IsOffensive()
+
+	if ability.IsOffensive() {
+		g.combatState.TargetIndex = 0
+		for i, e := range g.combatState.Enemies {
+			if e.IsAlive() && g.combatState.CanReach(ability, e) {
+				g.combatState.TargetIndex = i
+				break
+			}
+		}
+		if g.focusKills {
+			if i := g.killableEnemyIndex(ability); i >= 0 {
+				g.combatState.TargetIndex = i
+			}
+		}
+	} else {
+		g.combatState.TargetIndex = g.combatState.ActiveMemberIndex
+		switch ability.EffectType {
+		case gamedata.EffectHeal:
+			if i := g.mostWoundedMemberIndex(); i >= 0 {
+				g.combatState.TargetIndex = i
+			}
+		case gamedata.EffectCleanse:
+			if i := g.afflictedMemberIndex(); i >= 0 {
+				g.combatState.TargetIndex = i
+			}
+		}
+	}
+
+	g.combatState.LastMessage = "Choose a target for " + ability.Name
+}
+
+// handleTargetSelectionKey processes input while choosing a target.
+// Returns false for keys it does not handle so they fall through to normal handling.
+func (g *Game) handleTargetSelectionKey(ctx context.Context, ev *tcell.EventKey) bool {
+	switch ev.Key() {
+	case tcell.KeyUp, tcell.KeyLeft:
+		g.cycleTarget(-1)
+	case tcell.KeyDown, tcell.KeyRight:
+		g.cycleTarget(1)
+	case tcell.KeyTab:
+		g.switchTargetSide()
+	case tcell.KeyEnter:
+		g.confirmTarget(ctx)
+	case tcell.KeyEscape, tcell.KeyBackspace, tcell.KeyBackspace2:
+		g.undoSelection()
+	case tcell.KeyRune:
+		switch ev.Rune() {
+		case 'k', 'h':
+			g.cycleTarget(-1)
+		case 'j', 'l':
+			g.cycleTarget(1)
+		case ' ':
+			g.confirmTarget(ctx)
+		case '1', '2', '3', '4', '5', '6', '7', '8', '9':
+			g.targetEnemyByNumber(ctx, int(ev.Rune()-'1'))
+		default:
+			return false
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// targetEnemyByNumber aims an offensive ability at the index-th living
+// enemy in the order the enemy list shows them, counting from 0, and
+// confirms it. Numbers past the last living enemy, or while aiming a
+// non-offensive ability, are ignored.
+func (g *Game) targetEnemyByNumber(ctx context.Context, index int) {
+	cs := g.combatState
+	if cs.SelectedAbility == nil || !cs.SelectedAbility.IsOffensive() {
+		return
+	}
+	ordered := ui.EnemyDisplayOrder(cs.Enemies)
+	if index >= len(ordered) {
+		return
+	}
+	enemy := ordered[index]
+	for i, e := range cs.Enemies {
+		if e == enemy {
+			cs.TargetOnAllies, cs.TargetIndex = false, i
+		}
+	}
+	g.confirmTarget(ctx)
+}
+
+// targetingEnemies returns true if the target cursor is on the enemies.
+func (g *Game) targetingEnemies() bool {
+	return g.combatState.SelectedAbility != nil && !g.combatState.TargetOnAllies
+}
+
+// switchTargetSide moves the target cursor between the enemies and the
+// party, landing on the first selectable target on the other side. The
+// cursor stays put if the other side has nothing to select.
+func (g *Game) switchTargetSide() {
+	cs := g.combatState
+	if cs.TargetOnAllies {
+		for i, e := range cs.Enemies {
+			if e.IsAlive() {
+				cs.TargetOnAllies, cs.TargetIndex = false, i
+				return
+			}
+		}
+		return
+	}
+	for i, m := range g.party.Members {
+		if g.canTargetMember(m) {
+			cs.TargetOnAllies, cs.TargetIndex = true, i
+			return
+		}
+	}
+}
+
+// targetError returns why the target under the cursor can't receive the
+// selected ability, or "" if it can. Offensive abilities only land on
+// enemies and everything else only on allies.
+func (g *Game) targetError() string {
+	ability := g.combatState.SelectedAbility
+	if ability == nil || ability.IsOffensive() == g.targetingEnemies() {
+		return ""
+	}
+	if ability.IsOffensive() {
+		return "Invalid target — " + ability.Name + " targets enemies."
+	}
+	return "Invalid target — " + ability.Name + " targets allies."
+}
+
+// targetRange describes who the ability can be aimed at, for the targeting
+// panel.
+func targetRange(ability *gamedata.AbilityDef) string {
+	switch {
+	case !ability.IsOffensive():
+		return "allies"
+	case ability.ReachesBackRow():
+		return "any enemy"
+	default:
+		return "front-row enemies"
+	}
+}
+
+// cycleTarget moves the target cursor to the next (dir=1) or previous (dir=-1)
+// selectable target, wrapping around. Unreachable enemies can still be
+// highlighted so the player sees why they can't be chosen.
+func (g *Game) cycleTarget(dir int) {
+	var n int
+	var selectable func(i int) bool
+	if g.targetingEnemies() {
+		n = len(g.combatState.Enemies)
+		selectable = func(i int) bool { return g.combatState.Enemies[i].IsAlive() }
+	} else {
+		n = len(g.party.Members)
+		selectable = func(i int) bool { return g.canTargetMember(g.party.Members[i]) }
+	}
+
+	for step := 1; step <= n; step++ {
+		i := ((g.combatState.TargetIndex+dir*step)%n + n) % n
+		if selectable(i) {
+			g.combatState.TargetIndex = i
+			return
+		}
+	}
+}
+
+// confirmTarget uses the selected ability on the target under the cursor.
+func (g *Game) confirmTarget(ctx context.Context) {
+	ability := g.combatState.SelectedAbility
+	activeMember := g.getActiveMember()
+	if ability == nil || activeMember == nil {
+		g.cancelTargeting()
+		return
+	}
+	if msg := g.targetError(); msg != "" {
+		g.combatState.LastMessage = msg
+		return
+	}
+
+	if g.targetingEnemies() {
+		enemy := g.targetedEnemy()
+		if enemy == nil {
+			g.cancelTargeting()
+			return
+		}
+		if !g.combatState.CanReach(ability, enemy) {
+			g.combatState.LastMessage = "Can't reach " + enemy.Name + " in the back row with " + ability.Name + "!"
+			return
+		}
+		g.combatState.Phase = PhasePlayerTurn
+		g.combatState.SelectedAbility = nil
+		g.performPlayerAction(ctx, ability, activeMember, enemy)
+		return
+	}
+
+	target := g.targetedMember()
+	if target == nil {
+		g.cancelTargeting()
+		return
+	}
+	g.combatState.Phase = PhasePlayerTurn
+	g.combatState.SelectedAbility = nil
+	g.performPlayerAction(ctx, ability, activeMember, target)
+}
+
+// cancelTargeting returns to ability selection without spending the turn.
+func (g *Game) cancelTargeting() {
+	g.combatState.Phase = PhasePlayerTurn
+	g.combatState.SelectedAbility = nil
+	g.combatState.LastMessage = "Choose an ability"
+}
+
+// undoSelection steps back one level in combat input without consuming the
+// turn: from target selection to ability selection, and from ability
+// selection it clears any pending selection.
+func (g *Game) undoSelection() {
+	if g.combatState == nil {
+		return
+	}
+	switch g.combatState.Phase {
+	case PhaseSelectTarget:
+		g.cancelTargeting()
+	case PhasePlayerTurn:
+		if g.combatState.SelectedAbility != nil {
+			g.cancelTargeting()
+		}
+	}
+}
+
+// targetedEnemy returns the enemy under the target cursor, or nil.
+func (g *Game) targetedEnemy() *entity.Enemy {
+	i := g.combatState.TargetIndex
+	if i < 0 || i >= len(g.combatState.Enemies) || !g.combatState.Enemies[i].IsAlive() {
+		return nil
+	}
+	return g.combatState.Enemies[i]
+}
+
+// targetedMember returns the ally under the target cursor, or nil.
+func (g *Game) targetedMember() *entity.Member {
+	i := g.combatState.TargetIndex
+	if i < 0 || i >= len(g.party.Members) || !g.canTargetMember(g.party.Members[i]) {
+		return nil
+	}
+	return g.party.Members[i]
+}
+
+// allyTargetCandidates returns the allies the selected ability can target.
+func (g *Game) allyTargetCandidates() []*entity.Member {
+	var candidates []*entity.Member
+	for _, m := range g.party.Members {
+		if g.canTargetMember(m) {
+			candidates = append(candidates, m)
+		}
+	}
+	return candidates
+}
+
+// canTargetMember reports whether the selected ability may target the member.
+// Only living members are targetable until revive-type abilities exist.
+func (g *Game) canTargetMember(m *entity.Member) bool {
+	return m.IsAlive()
+}
+
+// killableEnemyIndex returns the index of the reachable enemy with the most
+// HP that the active member's ability would kill this turn, so the kill wastes
+// the least damage, or -1 if it can't kill anyone.
+func (g *Game) killableEnemyIndex(ability *gamedata.AbilityDef) int {
+	user := g.getActiveMember()
+	if user == nil {
+		return -1
+	}
+	best := -1
+	for i, e := range g.combatState.Enemies {
+		if !e.IsAlive() || !g.combatState.CanReach(ability, e) {
+			continue
+		}
+		if g.effectResolver.CalculateDamage(ability, user, e) < e.GetHP() {
+			continue
+		}
+		if best < 0 || e.GetHP() > g.combatState.Enemies[best].GetHP() {
+			best = i
+		}
+	}
+	return best
+}
+
+// mostWoundedMemberIndex returns the index of the living member with the
+// lowest HP fraction, or -1 if nobody is missing HP.
+func (g *Game) mostWoundedMemberIndex() int {
+	best := -1
+	for i, m := range g.party.Members {
+		if !m.IsAlive() || m.GetHP() >= m.GetMaxHP() {
+			continue
+		}
+		if best < 0 {
+			best = i
+			continue
+		}
+		b := g.party.Members[best]
+		// Compare HP/MaxHP without floating point
+		if m.GetHP()*b.GetMaxHP() < b.GetHP()*m.GetMaxHP() {
+			best = i
+		}
+	}
+	return best
+}
+
+// afflictedMemberIndex returns the index of the first living member with a
+// negative status effect, or -1 if there is none.
+func (g *Game) afflictedMemberIndex() int {
+	for i, m := range g.party.Members {
+		if !m.IsAlive() {
+			continue
+		}
+		for _, e := range m.GetStatusEffects() {
+			if e.Type.IsNegative() {
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,232 @@
+package game
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+)
+
+// startClericTurn puts the test game into combat on the cleric's turn.
+func startClericTurn(t *testing.T) *Game {
+	t.Helper()
+	g := newTestGame(t)
+	g.state = StateCombat
+	g.combatState = NewCombatState([]*entity.Enemy{entity.NewEnemy(entity.EnemyGoblin, 5, 5, 1)})
+	g.combatState.ActiveMemberIndex = 3 // Cleric
+	return g
+}
+
+func TestHealPreselectsMostWoundedAlly(t *testing.T) {
+	g := startClericTurn(t)
+	warrior, wizard := g.party.Members[0], g.party.Members[2]
+	warrior.TakeDamage(10) // 20/30
+	wizard.TakeDamage(10)  // 5/15, most wounded
+
+	// Cleric abilities: attack, defend, heal, group_heal, cleanse
+	g.handleCombatAbilitySelection(context.Background(), 2)
+
+	if g.combatState.Phase != PhaseSelectTarget {
+		t.Fatalf("Phase = %v, want PhaseSelectTarget", g.combatState.Phase)
+	}
+	if got := g.targetedMember(); got != wizard {
+		t.Errorf("preselected target = %v, want %s", got, wizard.Name)
+	}
+}
+
+func TestClericHealsChosenAlly(t *testing.T) {
+	g := startClericTurn(t)
+	ctx := context.Background()
+	cleric, rogue, wizard := g.party.Members[3], g.party.Members[1], g.party.Members[2]
+	rogue.TakeDamage(10)  // 10/20
+	wizard.TakeDamage(10) // 5/15, preselected
+	cleric.TakeDamage(5)
+
+	g.handleCombatAbilitySelection(ctx, 2)
+
+	// Move the cursor from the wizard back to the rogue and confirm
+	g.handleKeyEvent(ctx, tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone))
+	if got := g.targetedMember(); got != rogue {
+		t.Fatalf("target after cycling = %v, want %s", got, rogue.Name)
+	}
+	g.handleKeyEvent(ctx, tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+
+	if rogue.GetHP() != rogue.GetMaxHP() {
+		t.Errorf("rogue HP = %d, want %d (healed)", rogue.GetHP(), rogue.GetMaxHP())
+	}
+	if cleric.GetHP() != cleric.GetMaxHP()-5 {
+		t.Errorf("cleric should not have healed themselves, HP = %d", cleric.GetHP())
+	}
+	// The goblin acts after the cleric, so the wizard can only have lost HP
+	if wizard.GetHP() > 5 {
+		t.Errorf("wizard should not have been healed, HP = %d", wizard.GetHP())
+	}
+}
+
+func TestCancelTargetingKeepsTurn(t *testing.T) {
+	g := startClericTurn(t)
+	ctx := context.Background()
+	g.party.Members[0].TakeDamage(10)
+
+	g.handleCombatAbilitySelection(ctx, 2)
+	g.handleKeyEvent(ctx, tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone))
+
+	if g.state != StateCombat {
+		t.Fatal("Esc during targeting should not flee combat")
+	}
+	if g.combatState.Phase != PhasePlayerTurn || g.combatState.ActiveMemberIndex != 3 {
+		t.Errorf("after cancel: phase=%v active=%d, want player_turn/3", g.combatState.Phase, g.combatState.ActiveMemberIndex)
+	}
+}
+
+func TestUndoFromTargetSelection(t *testing.T) {
+	g := startClericTurn(t)
+	ctx := context.Background()
+	g.party.Members[0].TakeDamage(10)
+
+	g.handleCombatAbilitySelection(ctx, 2) // Heal
+	if g.combatState.Phase != PhaseSelectTarget {
+		t.Fatalf("Phase = %v, want PhaseSelectTarget", g.combatState.Phase)
+	}
+
+	g.handleKeyEvent(ctx, tcell.NewEventKey(tcell.KeyBackspace2, 0, tcell.ModNone))
+
+	if g.combatState.Phase != PhasePlayerTurn {
+		t.Errorf("Phase = %v, want PhasePlayerTurn", g.combatState.Phase)
+	}
+	if g.combatState.SelectedAbility != nil {
+		t.Errorf("SelectedAbility = %v, want nil", g.combatState.SelectedAbility.ID)
+	}
+	if g.combatState.ActiveMemberIndex != 3 || g.combatState.TurnCount != 0 {
+		t.Errorf("undo consumed the turn: active=%d turns=%d", g.combatState.ActiveMemberIndex, g.combatState.TurnCount)
+	}
+
+	// Undo again at ability selection is harmless
+	g.handleKeyEvent(ctx, tcell.NewEventKey(tcell.KeyBackspace2, 0, tcell.ModNone))
+	if g.state != StateCombat || g.combatState.Phase != PhasePlayerTurn {
+		t.Errorf("second undo changed state: state=%v phase=%v", g.state, g.combatState.Phase)
+	}
+}
+
+func TestOffensiveAbilityRejectsAllyTarget(t *testing.T) {
+	g := startClericTurn(t)
+	ctx := context.Background()
+	goblin := g.combatState.Enemies[0]
+
+	g.handleCombatAbilitySelection(ctx, 0) // Attack
+	g.handleKeyEvent(ctx, tcell.NewEventKey(tcell.KeyTab, 0, tcell.ModNone))
+	if g.targetedMember() == nil {
+		t.Fatal("Tab should move the cursor onto the party")
+	}
+	if info := g.buildCombatInfo(); !info.InvalidTarget {
+		t.Error("an ally under the cursor should be flagged as an invalid target for Attack")
+	}
+
+	g.handleKeyEvent(ctx, tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+	if g.combatState.Phase != PhaseSelectTarget {
+		t.Fatalf("Phase = %v, want to remain in target selection", g.combatState.Phase)
+	}
+	if !strings.HasPrefix(g.combatState.LastMessage, "Invalid target") {
+		t.Errorf("LastMessage = %q, want invalid target feedback", g.combatState.LastMessage)
+	}
+	for _, m := range g.party.Members {
+		if m.GetHP() != m.GetMaxHP() {
+			t.Errorf("%s was hit by their own side", m.GetName())
+		}
+	}
+
+	// Back on the enemies the attack goes through
+	g.handleKeyEvent(ctx, tcell.NewEventKey(tcell.KeyTab, 0, tcell.ModNone))
+	g.handleKeyEvent(ctx, tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+	if goblin.HP == goblin.MaxHP {
+		t.Error("the goblin should have been attacked")
+	}
+}
+
+func TestFocusKillsPreselectsKillableEnemy(t *testing.T) {
+	g := newTestGame(t)
+	goblin := g.enemyRegistry.GetByID("goblin")
+	tough := entity.NewEnemyFromDef(goblin, 5, 5, 1)
+	tough.HP, tough.MaxHP = 500, 500
+	weak := entity.NewEnemyFromDef(goblin, 6, 5, 1)
+	weak.HP = 1
+	g.state = StateCombat
+	g.combatState = NewCombatState([]*entity.Enemy{tough, weak})
+	g.combatState.ActiveMemberIndex = 0 // Warrior
+	attack := g.abilityRegistry.GetByID("attack")
+
+	g.beginTargeting(attack)
+	if g.combatState.TargetIndex != 0 {
+		t.Fatalf("without FocusKills TargetIndex = %d, want the first enemy", g.combatState.TargetIndex)
+	}
+
+	g.focusKills = true
+	g.beginTargeting(attack)
+	if g.combatState.TargetIndex != 1 {
+		t.Errorf("TargetIndex = %d, want the goblin attack can kill", g.combatState.TargetIndex)
+	}
+
+	// Nothing killable falls back to the first reachable enemy
+	weak.HP = 500
+	g.beginTargeting(attack)
+	if g.combatState.TargetIndex != 0 {
+		t.Errorf("TargetIndex = %d, want the first enemy when nothing dies", g.combatState.TargetIndex)
+	}
+}
+
+func TestNumberKeyTargetsNthAliveEnemy(t *testing.T) {
+	g := newTestGame(t)
+	goblin := g.enemyRegistry.GetByID("goblin")
+	var enemies []*entity.Enemy
+	for i := range 3 {
+		e := entity.NewEnemyFromDef(goblin, 5+i, 5, 1)
+		e.HP, e.MaxHP = 500, 500
+		enemies = append(enemies, e)
+	}
+	enemies[0].HP = 0 // Dead enemies aren't numbered
+	g.state = StateCombat
+	g.combatState = NewCombatState(enemies)
+	g.combatState.ActiveMemberIndex = 0 // Warrior
+	ctx := context.Background()
+
+	g.handleCombatAbilitySelection(ctx, 0) // Attack
+	if g.combatState.Phase != PhaseSelectTarget {
+		t.Fatalf("Phase = %v, want PhaseSelectTarget", g.combatState.Phase)
+	}
+	g.handleKeyEvent(ctx, pressRune('2'))
+
+	if enemies[2].HP == 500 {
+		t.Error("'2' should attack the second living enemy")
+	}
+	if enemies[1].HP != 500 {
+		t.Error("the first living enemy shouldn't be hit")
+	}
+	if g.combatState.Phase == PhaseSelectTarget {
+		t.Error("a number key should confirm the target")
+	}
+}
+
+func TestNumberKeysFollowTheEnemyList(t *testing.T) {
+	g := newTestGame(t)
+	goblin := g.enemyRegistry.GetByID("goblin")
+	back := entity.NewEnemyFromDef(goblin, 5, 5, 1)
+	front := entity.NewEnemyFromDef(goblin, 6, 5, 1)
+	back.Row, front.Row = gamedata.RowBack, gamedata.RowFront
+	back.HP, back.MaxHP = 500, 500
+	front.HP, front.MaxHP = 500, 500
+	g.state = StateCombat
+	g.combatState = NewCombatState([]*entity.Enemy{back, front})
+	g.combatState.ActiveMemberIndex = 0 // Warrior
+	ctx := context.Background()
+
+	g.handleCombatAbilitySelection(ctx, 0) // Attack
+	g.handleKeyEvent(ctx, pressRune('1'))
+
+	if front.HP == 500 || back.HP != 500 {
+		t.Error("'1' should attack the front-row enemy listed first, not the first one in the fight")
+	}
+}
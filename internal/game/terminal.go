@@ -0,0 +1,111 @@
+package game
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"strconv"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/samdwyer/dungeonband/internal/telemetry"
+	"github.com/samdwyer/dungeonband/internal/ui"
+)
+
+// shutdownRequest is posted to the event loop when the process is asked to
+// terminate, so shutdown follows the same path as quitting.
+type shutdownRequest struct{}
+
+// stopProcess suspends the process until it is continued by the shell.
+// It is a variable so tests can replace it without sending real signals.
+var stopProcess = suspendSelf
+
+// minDisplaySize returns the smallest display the current floor can be
+// played on.
+func (g *Game) minDisplaySize() (width, height int) {
+	return ui.MinScreenSize(g.dungeon.Width, g.dungeon.Height)
+}
+
+// displayTooSmall reports whether the display can't fit the current floor
+// and its panels. Headless displays, which report no size, always fit.
+func (g *Game) displayTooSmall() bool {
+	width, height := g.display.Size()
+	if width <= 0 || height <= 0 || g.dungeon == nil {
+		return false
+	}
+	minWidth, minHeight := g.minDisplaySize()
+	return width < minWidth || height < minHeight
+}
+
+// windowTitle builds the terminal window title for the current game state.
+func windowTitle(floor int, seed int64, state State) string {
+	title := "DungeonBand — Floor " + strconv.Itoa(floor) + " — Seed " + strconv.FormatInt(seed, 10)
+	if state == StateCombat {
+		title += " — Combat"
+	}
+	return title
+}
+
+// updateTitle refreshes the terminal window title.
+func (g *Game) updateTitle() {
+	g.display.SetTitle(windowTitle(g.floor, g.seed, g.state))
+}
+
+// suspend hands the terminal back to the shell (Ctrl+Z) and restores it
+// when the process is continued. Game state is left untouched.
+func (g *Game) suspend(ctx context.Context) {
+	tracer := telemetry.Tracer("game")
+	_, span := tracer.Start(ctx, "game.suspend")
+	defer span.End()
+
+	if err := g.display.Suspend(); err != nil {
+		span.SetAttributes(attribute.String("error", err.Error()))
+		return
+	}
+	g.suspended = true
+	g.clock.pause()
+
+	start := time.Now()
+	if err := stopProcess(); err != nil {
+		span.SetAttributes(attribute.String("error", err.Error()))
+	}
+
+	if err := g.display.Resume(); err != nil {
+		span.SetAttributes(attribute.String("error", err.Error()))
+		g.running = false
+		return
+	}
+	g.suspended = false
+	g.updateTitle()
+	g.display.Sync()
+
+	span.SetAttributes(attribute.Int64("suspend.duration_ms", time.Since(start).Milliseconds()))
+}
+
+// WatchSignals forwards termination signals to whatever is reading the
+// display's events: the title screen, party setup or a run's loop. Watch
+// for the whole session so a signal between runs isn't lost. Returns a
+// function that stops watching.
+func WatchSignals(display Display) func() {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, terminateSignals...)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ch:
+				_ = display.PostEvent(tcell.NewEventInterrupt(shutdownRequest{}))
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
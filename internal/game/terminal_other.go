@@ -0,0 +1,16 @@
+//go:build !unix
+
+package game
+
+import (
+	"errors"
+	"os"
+)
+
+// terminateSignals are the signals that trigger a clean shutdown.
+var terminateSignals = []os.Signal{os.Interrupt}
+
+// suspendSelf is unsupported on this platform; the screen is simply restored.
+func suspendSelf() error {
+	return errors.New("suspend not supported on this platform")
+}
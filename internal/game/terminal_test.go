@@ -0,0 +1,146 @@
+package game
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// screenText returns everything drawn on the simulation screen, one line
+// per row.
+func screenText(sim tcell.SimulationScreen) string {
+	_, width, height := sim.GetContents()
+	var b strings.Builder
+	for y := range height {
+		for x := range width {
+			b.WriteRune(cellAt(sim, x, y))
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func TestWindowTitle(t *testing.T) {
+	tests := []struct {
+		floor    int
+		seed     int64
+		state    State
+		expected string
+	}{
+		{1, 12345, StateExplore, "DungeonBand — Floor 1 — Seed 12345"},
+		{2, 12345, StateExplore, "DungeonBand — Floor 2 — Seed 12345"},
+		{3, -7, StateCombat, "DungeonBand — Floor 3 — Seed -7 — Combat"},
+	}
+
+	for _, tt := range tests {
+		got := windowTitle(tt.floor, tt.seed, tt.state)
+		if got != tt.expected {
+			t.Errorf("windowTitle(%d, %d, %v) = %q, want %q", tt.floor, tt.seed, tt.state, got, tt.expected)
+		}
+	}
+}
+
+func TestTitleFollowsCombatTransitions(t *testing.T) {
+	g, sim := newTestGameWithScreen(t)
+	ctx := context.Background()
+
+	g.updateTitle()
+	if got := sim.GetTitle(); got != "DungeonBand — Floor 1 — Seed 12345" {
+		t.Errorf("explore title = %q", got)
+	}
+
+	g.transitionState(ctx, StateCombat, "test")
+	if got := sim.GetTitle(); got != "DungeonBand — Floor 1 — Seed 12345 — Combat" {
+		t.Errorf("combat title = %q", got)
+	}
+
+	g.transitionState(ctx, StateExplore, "test")
+	if got := sim.GetTitle(); got != "DungeonBand — Floor 1 — Seed 12345" {
+		t.Errorf("title after combat = %q", got)
+	}
+}
+
+func TestSuspendPreservesGameState(t *testing.T) {
+	g, sim := newTestGameWithScreen(t)
+	ctx := context.Background()
+
+	x, y := g.party.Position()
+
+	var suspendedDuringStop bool
+	stopProcess = func() error {
+		suspendedDuringStop = g.suspended
+		return nil
+	}
+	t.Cleanup(func() { stopProcess = suspendSelf })
+
+	g.handleKeyEvent(ctx, tcell.NewEventKey(tcell.KeyCtrlZ, 0, tcell.ModCtrl))
+
+	if !suspendedDuringStop {
+		t.Error("game should be marked suspended while the process is stopped")
+	}
+	if g.suspended {
+		t.Error("game should not be suspended after resuming")
+	}
+	if !g.running {
+		t.Error("game should keep running after resume")
+	}
+	if nx, ny := g.party.Position(); nx != x || ny != y {
+		t.Errorf("party moved during suspend: (%d,%d) -> (%d,%d)", x, y, nx, ny)
+	}
+	if got := sim.GetTitle(); got != windowTitle(1, 12345, StateExplore) {
+		t.Errorf("title not restored after resume: %q", got)
+	}
+}
+
+func TestShutdownRequestStopsGame(t *testing.T) {
+	g := newTestGame(t)
+
+	if err := g.display.PostEvent(tcell.NewEventInterrupt(shutdownRequest{})); err != nil {
+		t.Fatalf("PostEvent failed: %v", err)
+	}
+	g.handleInput(context.Background())
+
+	if g.running {
+		t.Error("shutdown request should stop the game loop")
+	}
+}
+
+func TestTooSmallScreenAsksToEnlarge(t *testing.T) {
+	g, sim := newTestGameWithScreen(t)
+	sim.SetSize(60, 20)
+	g.render()
+
+	text := screenText(sim)
+	if !strings.Contains(text, "Please enlarge your terminal") || !strings.Contains(text, "(need at least 80x26)") {
+		t.Errorf("too-small screen should ask to be enlarged:\n%s", text)
+	}
+	if strings.ContainsRune(text, '#') {
+		t.Errorf("too-small screen should not draw the map:\n%s", text)
+	}
+
+	sim.SetSize(80, 26)
+	g.render()
+	if text := screenText(sim); strings.Contains(text, "enlarge") || !strings.ContainsRune(text, '#') {
+		t.Errorf("enlarged screen should draw the map again:\n%s", text)
+	}
+}
+
+func TestTooSmallScreenPausesInput(t *testing.T) {
+	g, sim := newTestGameWithScreen(t)
+	sim.SetSize(60, 20)
+	x := g.party.X
+
+	sim.InjectKey(tcell.KeyRune, 'p', tcell.ModNone)
+	g.handleInput(context.Background())
+	if g.paused || g.party.X != x {
+		t.Error("keys should be ignored while the screen is too small")
+	}
+
+	sim.InjectKey(tcell.KeyCtrlC, 0, tcell.ModNone)
+	g.handleInput(context.Background())
+	if g.running {
+		t.Error("Ctrl-C should still quit while the screen is too small")
+	}
+}
@@ -0,0 +1,17 @@
+//go:build unix
+
+package game
+
+import (
+	"os"
+	"syscall"
+)
+
+// terminateSignals are the signals that trigger a clean shutdown.
+var terminateSignals = []os.Signal{syscall.SIGTERM}
+
+// suspendSelf stops the process with SIGTSTP. The call returns once the
+// shell continues the process (e.g. via fg).
+func suspendSelf() error {
+	return syscall.Kill(syscall.Getpid(), syscall.SIGTSTP)
+}
@@ -0,0 +1,115 @@
+package game
+
+import (
+	"hash/fnv"
+	"log"
+
+	"github.com/samdwyer/dungeonband/internal/combat"
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+)
+
+// loadThemes loads and validates the room themes. Without them every room
+// is plain.
+func loadThemes(abilities *gamedata.AbilityRegistry) *gamedata.ThemesFile {
+	themes, err := gamedata.LoadThemes()
+	if err == nil {
+		err = themes.Validate(abilities)
+	}
+	if err != nil {
+		log.Printf("Warning: failed to load room themes: %v (every room is plain)", err)
+		return nil
+	}
+	return themes
+}
+
+// roomTheme returns the theme of a room on the current floor, or nil for
+// a plain room. A room with a shrine takes the shrine theme; any other room
+// rolls for the chance-based themes with a hash of the seed, floor and
+// room, so themes never draw from the run's RNG.
+func (g *Game) roomTheme(room int) *gamedata.ThemeDef {
+	if g.themes == nil || g.tutorial || room < 0 || room >= len(g.dungeon.Rooms) {
+		return nil
+	}
+	hasShrine := false
+	for _, s := range g.dungeon.Shrines {
+		hasShrine = hasShrine || g.dungeon.Rooms[room].Contains(s.X, s.Y)
+	}
+	if hasShrine {
+		for i := range g.themes.Themes {
+			if g.themes.Themes[i].Shrine {
+				return &g.themes.Themes[i]
+			}
+		}
+		return nil
+	}
+
+	h := stateHasher{fnv.New64a()}
+	h.str("theme")
+	h.ints(g.seed, int64(g.floor), int64(room))
+	roll := int(h.Sum64() % 100)
+	for i := range g.themes.Themes {
+		t := &g.themes.Themes[i]
+		if t.Shrine {
+			continue
+		}
+		if roll < t.Chance {
+			return t
+		}
+		roll -= t.Chance
+	}
+	return nil
+}
+
+// roomEnvironment applies a room theme's modifiers to every cast in a fight.
+type roomEnvironment struct {
+	theme *gamedata.ThemeDef
+}
+
+// CastModifiers implements combat.Environment.
+func (e roomEnvironment) CastModifiers(ability *gamedata.AbilityDef, _ combat.Combatant) combat.CastModifiers {
+	return combat.CastModifiers{
+		DamagePercent:  e.theme.Modifiers.DamagePercent(ability),
+		HealingPercent: e.theme.Modifiers.Healing,
+	}
+}
+
+// applyTheme sets up the fight for the theme of the room it starts in.
+// clearTheme undoes it when the fight ends.
+func (g *Game) applyTheme(theme *gamedata.ThemeDef) {
+	g.combatState.Theme = theme
+	if theme == nil {
+		g.effectResolver.SetEnvironment(nil)
+		return
+	}
+	g.effectResolver.SetEnvironment(roomEnvironment{theme: theme})
+	if theme.Modifiers.NoBackRow {
+		g.combatState.themeRows = make(map[*entity.Enemy]gamedata.Row)
+		for _, e := range g.combatState.Enemies {
+			g.combatState.themeRows[e] = e.Row
+			e.Row = gamedata.RowFront
+		}
+	}
+}
+
+// clearTheme lifts the room's modifiers once the fight is over, however it
+// ended, and puts enemies back in the rows they had before it.
+func (g *Game) clearTheme() {
+	g.effectResolver.SetEnvironment(nil)
+	if g.combatState == nil {
+		return
+	}
+	for e, row := range g.combatState.themeRows {
+		e.Row = row
+	}
+	g.combatState.themeRows = nil
+}
+
+// environmentBanner describes the fight's surroundings for the combat
+// panel, e.g. "Flooded chamber: fire damage halved, everyone slowed".
+func (cs *CombatState) environmentBanner() string {
+	if cs.Theme == nil {
+		return ""
+	}
+	return cs.Theme.Name + ": " + cs.Theme.Banner
+}
@@ -0,0 +1,156 @@
+package game
+
+import (
+	"context"
+	"testing"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+	"github.com/samdwyer/dungeonband/internal/world"
+)
+
+// loadTestThemes gives the game the room themes, which test games leave
+// out so their rooms stay plain.
+func loadTestThemes(t *testing.T, g *Game) {
+	t.Helper()
+	if g.themes = loadThemes(g.abilityRegistry); g.themes == nil {
+		t.Fatal("failed to load room themes")
+	}
+}
+
+// themeByID loads the room themes and returns the one with the ID.
+func themeByID(t *testing.T, g *Game, id string) *gamedata.ThemeDef {
+	t.Helper()
+	loadTestThemes(t, g)
+	for i := range g.themes.Themes {
+		if g.themes.Themes[i].ID == id {
+			return &g.themes.Themes[i]
+		}
+	}
+	t.Fatalf("no theme %q", id)
+	return nil
+}
+
+// fireballDamage has the wizard fireball a sturdy orc under the theme.
+func fireballDamage(t *testing.T, themeID string) int {
+	orc := entity.NewEnemy(entity.EnemyOrc, 5, 5, 1)
+	orc.HP, orc.MaxHP = 999, 999
+	g := startStatsCombat(t, orc)
+	if themeID != "" {
+		g.applyTheme(themeByID(t, g, themeID))
+	}
+	g.executeCombatTurn(context.Background(), g.abilityRegistry.GetByID("fireball"), g.party.Members[2], orc)
+	return orc.MaxHP - orc.HP
+}
+
+func TestFloodedRoomHalvesFireDamage(t *testing.T) {
+	plain, flooded := fireballDamage(t, ""), fireballDamage(t, "flooded")
+	if want := (plain + 1) / 2; flooded != want {
+		t.Errorf("flooded fireball = %d damage, want half of %d = %d", flooded, plain, want)
+	}
+}
+
+func TestFloodedRoomSlowsEveryone(t *testing.T) {
+	g, _ := startTurnOrderCombat(t)
+	g.applyTheme(themeByID(t, g, "flooded"))
+
+	for round, want := range []int{4, 4, 0} {
+		if got := len(roundOrder(g.party.Members, round+1, g.combatState.slowAll())); got != want {
+			t.Errorf("round %d: %d party actions, want %d", round+1, got, want)
+		}
+	}
+	for _, s := range g.buildCombatInfo().TurnOrder {
+		if !s.Slowed {
+			t.Errorf("%s isn't marked slowed", s.Name)
+		}
+	}
+}
+
+func TestCrampedRoomHasNoBackRow(t *testing.T) {
+	skeleton := entity.NewEnemyFromDef(newTestGame(t).enemyRegistry.GetByID("skeleton"), 5, 5, 1)
+	g := startStatsCombat(t, entity.NewEnemy(entity.EnemyGoblin, 6, 5, 1), skeleton)
+	g.applyTheme(themeByID(t, g, "cramped"))
+
+	if skeleton.Row != gamedata.RowFront {
+		t.Errorf("skeleton row = %q, want front", skeleton.Row)
+	}
+	g.fleeCombat(context.Background())
+	if skeleton.Row != gamedata.RowBack {
+		t.Errorf("skeleton row after the fight = %q, want back", skeleton.Row)
+	}
+}
+
+func TestFleeingLeavesTheRoomTheme(t *testing.T) {
+	orc := entity.NewEnemy(entity.EnemyOrc, 5, 5, 1)
+	g := startStatsCombat(t, orc)
+	fireball, wizard := g.abilityRegistry.GetByID("fireball"), g.party.Members[2]
+	plain := g.effectResolver.CalculateDamage(fireball, wizard, orc)
+	g.applyTheme(themeByID(t, g, "flooded"))
+
+	g.fleeCombat(context.Background())
+	if g.state != StateExplore {
+		t.Fatalf("state = %v, want explore after fleeing", g.state)
+	}
+	if got := g.effectResolver.CalculateDamage(fireball, wizard, orc); got != plain {
+		t.Errorf("fireball after fleeing = %d damage, want the plain %d", got, plain)
+	}
+}
+
+func TestShrineRoomBoostsHealing(t *testing.T) {
+	healed := func(themeID string) int {
+		g := startStatsCombat(t, entity.NewEnemy(entity.EnemyGoblin, 5, 5, 1))
+		if themeID != "" {
+			g.applyTheme(themeByID(t, g, themeID))
+		}
+		warrior := g.party.Members[0]
+		warrior.MaxHP, warrior.HP = 999, 1
+		g.executeCombatTurn(context.Background(), g.abilityRegistry.GetByID("heal"), g.party.Members[3], warrior)
+		return warrior.HP - 1
+	}
+	plain, boosted := healed(""), healed("shrine")
+	if want := (plain*3 + 1) / 2; boosted != want {
+		t.Errorf("shrine heal = %d, want 150%% of %d = %d", boosted, plain, want)
+	}
+}
+
+func TestRoomThemes(t *testing.T) {
+	g := newTestGame(t)
+	loadTestThemes(t, g)
+	g.dungeon.Rooms = []world.Room{{X: 1, Y: 1, Width: 4, Height: 4}, {X: 10, Y: 1, Width: 4, Height: 4}}
+	g.dungeon.Shrines = []world.Shrine{{X: 2, Y: 2}}
+
+	if theme := g.roomTheme(0); theme == nil || theme.ID != "shrine" {
+		t.Errorf("shrine room theme = %v, want shrine", theme)
+	}
+	if g.roomTheme(1) != g.roomTheme(1) {
+		t.Error("a room's theme should not change between lookups")
+	}
+	if g.roomTheme(-1) != nil {
+		t.Error("corridors have no theme")
+	}
+
+	// Across many rooms the chance-based themes all turn up
+	seen := make(map[string]bool)
+	for floor := 1; floor <= 50; floor++ {
+		g.floor = floor
+		if theme := g.roomTheme(1); theme != nil {
+			seen[theme.ID] = true
+		}
+	}
+	if !seen["flooded"] || !seen["cramped"] || seen["shrine"] {
+		t.Errorf("themes seen over 50 floors = %v, want flooded and cramped only", seen)
+	}
+}
+
+func TestThemeBannerInCombatPanel(t *testing.T) {
+	g := startStatsCombat(t, entity.NewEnemy(entity.EnemyGoblin, 5, 5, 1))
+	g.applyTheme(themeByID(t, g, "flooded"))
+
+	if got, want := g.buildCombatInfo().Environment, "Flooded chamber: fire damage halved, everyone slowed"; got != want {
+		t.Errorf("Environment = %q, want %q", got, want)
+	}
+	g.transitionState(context.Background(), StateExplore, "test")
+	if g.effectResolver.CalculateDamage(g.abilityRegistry.GetByID("fireball"), g.party.Members[2], entity.NewEnemy(entity.EnemyOrc, 0, 0, 0)) == 0 {
+		t.Error("fireball should still do damage after the fight")
+	}
+}
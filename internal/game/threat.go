@@ -0,0 +1,69 @@
+package game
+
+import (
+	"github.com/samdwyer/dungeonband/internal/combat"
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+)
+
+// threatPerDamage is how much threat each point of damage dealt to an enemy
+// builds against the member who dealt it. Target scoring subtracts the
+// target's HP, so with no threat enemies still go for the weakest member.
+const threatPerDamage = 2
+
+// threatTable tracks, per enemy, how much threat each member has built up.
+type threatTable map[*entity.Enemy]map[*entity.Member]int
+
+// add records damage dealt by a member to an enemy.
+func (t threatTable) add(enemy *entity.Enemy, member *entity.Member, damage int) {
+	if t[enemy] == nil {
+		t[enemy] = make(map[*entity.Member]int)
+	}
+	t[enemy][member] += damage * threatPerDamage
+}
+
+// of returns the threat a member has built against an enemy.
+func (t threatTable) of(enemy *entity.Enemy, member *entity.Member) int {
+	return t[enemy][member]
+}
+
+// recordThreat builds threat when a member damages an enemy.
+func (g *Game) recordThreat(user, target combat.Combatant, result combat.EffectResult) {
+	member, ok := user.(*entity.Member)
+	if !ok || result.Damage <= 0 {
+		return
+	}
+	if enemy, ok := target.(*entity.Enemy); ok {
+		g.combatState.threat.add(enemy, member, result.Damage)
+	}
+}
+
+// selectThreateningMember returns the living member the enemy most wants to
+// hit, blending threat against it with how low the member's HP is. With
+// inReach set, only members within melee reach are considered. Returns nil
+// if nobody qualifies.
+func (g *Game) selectThreateningMember(enemy *entity.Enemy, inReach bool) *entity.Member {
+	var best *entity.Member
+	bestScore := 0
+	for _, m := range g.party.Members {
+		if !m.IsAlive() || (inReach && !g.canMeleeReach(enemy, m)) {
+			continue
+		}
+		score := g.combatState.threat.of(enemy, m) - m.GetHP()
+		if best == nil || score > bestScore {
+			best, bestScore = m, score
+		}
+	}
+	return best
+}
+
+// taunter returns the living member who is taunting, or nil if nobody is.
+// Taunt overrides threat and HP when enemies pick a single target.
+func (g *Game) taunter() *entity.Member {
+	for _, m := range g.party.Members {
+		if m.IsAlive() && combat.HasStatus(m, gamedata.StatusTaunt) {
+			return m
+		}
+	}
+	return nil
+}
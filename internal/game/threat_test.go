@@ -0,0 +1,60 @@
+package game
+
+import (
+	"context"
+	"testing"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+)
+
+func TestEnemyFocusesHighestThreatMember(t *testing.T) {
+	g := newTestGame(t)
+	orc := entity.NewEnemyFromDef(g.enemyRegistry.GetByID("orc"), 5, 5, 0)
+	g.state = StateCombat
+	g.combatState = NewCombatState([]*entity.Enemy{orc})
+	attack := g.abilityRegistry.GetByID("attack")
+
+	// With no threat, the lowest-HP member is still the target
+	if got := g.selectEnemyTarget(orc, attack); got != g.party.Members[2] {
+		t.Fatalf("initial target = %s, want the wizard (lowest HP)", got.GetName())
+	}
+
+	warrior := g.party.Members[0]
+	g.executeCombatTurn(context.Background(), attack, warrior, orc)
+	g.executeCombatTurn(context.Background(), attack, warrior, orc)
+
+	if got := g.selectEnemyTarget(orc, attack); got != warrior {
+		t.Errorf("target = %s, want the warrior who has been hammering the orc", got.GetName())
+	}
+	if g.combatState.threat.of(orc, g.party.Members[1]) != 0 {
+		t.Error("bystanders should build no threat")
+	}
+}
+
+func TestTauntDrawsAttacksUntilItExpires(t *testing.T) {
+	g := newTestGame(t)
+	orc := entity.NewEnemyFromDef(g.enemyRegistry.GetByID("orc"), 5, 5, 0)
+	g.state = StateCombat
+	g.combatState = NewCombatState([]*entity.Enemy{orc})
+	attack := g.abilityRegistry.GetByID("attack")
+	taunt := g.abilityRegistry.GetByID("taunt")
+	if taunt == nil {
+		t.Fatal("taunt ability missing")
+	}
+
+	// The rogue builds threat, but the warrior's taunt overrides it
+	rogue, warrior := g.party.Members[1], g.party.Members[0]
+	g.executeCombatTurn(context.Background(), attack, rogue, orc)
+	g.executeCombatTurn(context.Background(), taunt, warrior, warrior)
+
+	for turn := 0; turn < taunt.StatusDuration; turn++ {
+		if got := g.selectEnemyTarget(orc, attack); got != warrior {
+			t.Fatalf("turn %d: target = %s, want the taunting warrior", turn, got.GetName())
+		}
+		g.tickCombatStatuses(context.Background())
+	}
+
+	if got := g.selectEnemyTarget(orc, attack); got == warrior {
+		t.Error("enemies should stop focusing the warrior once taunt expires")
+	}
+}
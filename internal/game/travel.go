@@ -0,0 +1,222 @@
+package game
+
+import (
+	"context"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/telemetry"
+	"github.com/samdwyer/dungeonband/internal/world"
+)
+
+// travelStepDelay paces queued movement so the player can watch the party
+// walk and press a key to stop it.
+const travelStepDelay = 60 * time.Millisecond
+
+// Travel kinds, for messages and telemetry.
+const (
+	travelClick   = "click"
+	travelStairs  = "stairs"
+	travelExplore = "explore"
+	travelRoom    = "room"
+)
+
+// travelTick is posted to the event loop to take the next queued step. seq
+// ties it to the plan that scheduled it, so a tick left over from a
+// cancelled plan can't move the party.
+type travelTick struct{ seq int }
+
+// travelPlan is a queued path the party walks one step per tick.
+type travelPlan struct {
+	seq          int
+	kind         string
+	destX, destY int
+	path         []world.Point
+	steps        int                    // Steps taken so far
+	seen         map[*entity.Enemy]bool // Enemies already in view, which don't interrupt
+	partyHP      int                    // Party HP before the latest step
+}
+
+// startTravel queues a path to (x, y) and takes its first step on the next
+// tick. The party walks around the stairs unless they are the destination.
+// Enemies already in view when travel starts don't stop it.
+func (g *Game) startTravel(kind string, x, y int) {
+	if g.scoutControl {
+		g.message = "The scout moves on foot. (s: control the party)"
+		return
+	}
+	path := g.travelPath(x, y)
+	if len(path) == 0 {
+		g.message = "There's no way there."
+		return
+	}
+
+	g.travelSeq++
+	plan := &travelPlan{
+		seq:     g.travelSeq,
+		kind:    kind,
+		destX:   x,
+		destY:   y,
+		path:    path,
+		seen:    make(map[*entity.Enemy]bool),
+		partyHP: g.totalPartyHP(),
+	}
+	for _, e := range g.visibleEnemies() {
+		plan.seen[e] = true
+	}
+	g.travel, g.resumable = plan, nil
+	g.scheduleTravelTick()
+}
+
+// travelPath returns the party's path to (x, y), around the stairs unless
+// they are the destination. Returns nil if there is no way there.
+func (g *Game) travelPath(x, y int) []world.Point {
+	blocked := func(bx, by int) bool {
+		return g.dungeon.IsStairs(bx, by) && (bx != x || by != y)
+	}
+	return g.dungeon.PathTo(g.party.X, g.party.Y, x, y, blocked)
+}
+
+// travelToStairs heads for the floor's stairs.
+func (g *Game) travelToStairs() {
+	g.startTravel(travelStairs, g.dungeon.StairsX, g.dungeon.StairsY)
+}
+
+// autoExplore heads for the center of the nearest room the party hasn't
+// entered this floor.
+func (g *Game) autoExplore() {
+	g.noteVisitedRooms()
+	i := g.nearestUnvisitedRoom()
+	if i < 0 {
+		g.message = "Every room has been explored."
+		return
+	}
+	x, y := g.dungeon.Rooms[i].Center()
+	g.startTravel(travelExplore, x, y)
+}
+
+// resumeTravel picks an interrupted trip back up, re-planning the path
+// from where the party now stands.
+func (g *Game) resumeTravel() {
+	plan := g.resumable
+	if plan == nil {
+		g.message = "There's no trip to resume."
+		return
+	}
+	g.startTravel(plan.kind, plan.destX, plan.destY)
+}
+
+// scheduleTravelTick asks for the next queued step after the step delay.
+func (g *Game) scheduleTravelTick() {
+	seq := g.travel.seq
+	time.AfterFunc(travelStepDelay, func() {
+		_ = g.display.PostEvent(tcell.NewEventInterrupt(travelTick{seq: seq}))
+	})
+}
+
+// travelStep takes the next queued step, unless something the player should
+// see first has happened since the last one: an enemy came into view or
+// the party got hurt. Anything that needs an answer after the step, like a
+// trap's trigger panel, a shrine or the stairs, ends the trip there.
+func (g *Game) travelStep(ctx context.Context) {
+	plan := g.travel
+	if plan == nil || g.state != StateExplore {
+		g.travel = nil
+		return
+	}
+	for _, e := range g.visibleEnemies() {
+		if !plan.seen[e] {
+			g.interruptTravel(ctx, e.GetName()+" comes into view!")
+			return
+		}
+	}
+	if hp := g.totalPartyHP(); hp < plan.partyHP {
+		g.interruptTravel(ctx, "The party is hurt.")
+		return
+	}
+
+	next := plan.path[0]
+	x, y := g.party.X, g.party.Y
+	g.tryMove(ctx, next.X-x, next.Y-y)
+	if g.party.X == x && g.party.Y == y {
+		g.interruptTravel(ctx, "The way is blocked.")
+		return
+	}
+	plan.path = plan.path[1:]
+	plan.steps++
+	plan.partyHP = g.totalPartyHP()
+
+	switch {
+	case g.travel != plan:
+		// The step itself ended the trip
+	case len(g.instructions) > 0 || g.shrine != nil || g.pendingDescend || g.state != StateExplore:
+		g.interruptTravel(ctx, "")
+	case len(plan.path) == 0:
+		g.finishTravel(ctx, "arrived")
+	default:
+		g.scheduleTravelTick()
+	}
+}
+
+// interruptTravel stops the trip and keeps it for resumeTravel. The reason,
+// if any, replaces the message line.
+func (g *Game) interruptTravel(ctx context.Context, reason string) {
+	plan := g.travel
+	if plan == nil {
+		return
+	}
+	if reason != "" {
+		g.message = reason + " (r resumes)"
+	}
+	g.finishTravel(ctx, "interrupted")
+	g.resumable = plan
+}
+
+// finishTravel ends the trip and records it.
+func (g *Game) finishTravel(ctx context.Context, outcome string) {
+	plan := g.travel
+	g.travel = nil
+
+	tracer := telemetry.Tracer("game")
+	_, span := tracer.Start(ctx, "game.travel")
+	span.SetAttributes(
+		attribute.String("kind", plan.kind),
+		attribute.String("outcome", outcome),
+		attribute.Int("steps", plan.steps),
+		attribute.Int("steps_left", len(plan.path)),
+	)
+	span.End()
+}
+
+// handleMouse starts travel to a clicked explore-mode tile. The explore
+// view draws the whole map from the screen's top-left corner, so screen
+// cells are map tiles.
+func (g *Game) handleMouse(ev *tcell.EventMouse) {
+	if ev.Buttons()&tcell.Button1 == 0 || g.state != StateExplore || g.demo != nil || g.modalOpen() {
+		return
+	}
+	x, y := ev.Position()
+	if !g.dungeon.IsPassable(x, y) {
+		return
+	}
+	g.startTravel(travelClick, x, y)
+}
+
+// modalOpen reports whether a prompt, menu or panel is waiting on a key.
+func (g *Game) modalOpen() bool {
+	return len(g.instructions) > 0 || g.paused || g.confirmAbandon || g.replay != nil || g.pendingDescend || g.shrine != nil || g.items != nil || g.casting != nil || g.rooms != nil
+}
+
+// visibleEnemies returns the living enemies the party notices.
+func (g *Game) visibleEnemies() []*entity.Enemy {
+	var visible []*entity.Enemy
+	for _, e := range g.enemies {
+		if e.IsAlive() && g.notices(e) {
+			visible = append(visible, e)
+		}
+	}
+	return visible
+}
@@ -0,0 +1,192 @@
+package game
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/world"
+)
+
+// travelLayout is a corridor bending south to the stairs, which lead on to
+// a passage that can't be seen from the corridor.
+const travelLayout = `
+##############
+#............#
+###########.##
+#..........>.#
+##############`
+
+// startTravelGame puts the party at the west end of travelLayout's top corridor.
+func startTravelGame(t *testing.T) *Game {
+	t.Helper()
+	g := newTestGame(t)
+	g.dungeon = dungeonFromMap(travelLayout)
+	g.dungeon.StairsX, g.dungeon.StairsY = 11, 3
+	g.party.SetPosition(1, 1)
+	g.confirmDescend = true
+	return g
+}
+
+// walk takes up to n queued steps, stopping when the trip ends.
+func walk(g *Game, n int) {
+	for i := 0; i < n && g.travel != nil; i++ {
+		g.travelStep(context.Background())
+	}
+}
+
+func TestTravelToStairs(t *testing.T) {
+	g := startTravelGame(t)
+
+	g.travelToStairs()
+	walk(g, 50)
+
+	if g.travel != nil {
+		t.Fatal("travel should end at the stairs")
+	}
+	if g.party.X != 11 || g.party.Y != 3 {
+		t.Errorf("party at (%d,%d), want the stairs at (11,3)", g.party.X, g.party.Y)
+	}
+	if !g.pendingDescend {
+		t.Error("arriving on the stairs should ask to descend")
+	}
+}
+
+func TestTravelHaltsWhenEnemyStepsIntoView(t *testing.T) {
+	g := startTravelGame(t)
+	// Out of sight down the side passage, behind the wall
+	goblin := entity.NewEnemy(entity.EnemyGoblin, 1, 3, -1)
+	g.enemies = []*entity.Enemy{goblin}
+
+	g.travelToStairs()
+	walk(g, 3)
+	if g.travel == nil {
+		t.Fatal("travel ended early")
+	}
+
+	// The goblin steps into the corridor mid-path
+	goblin.X, goblin.Y = 9, 1
+	x, y := g.party.X, g.party.Y
+	g.travelStep(context.Background())
+
+	if g.party.X != x || g.party.Y != y {
+		t.Errorf("party moved to (%d,%d) after the goblin came into view", g.party.X, g.party.Y)
+	}
+	if g.travel != nil {
+		t.Error("travel should stop")
+	}
+	if !strings.Contains(g.message, "comes into view") {
+		t.Errorf("message = %q, want the enemy called out", g.message)
+	}
+
+	// Resuming walks on with the goblin already in view
+	g.resumeTravel()
+	walk(g, 50)
+	if g.party.X != 11 || g.party.Y != 3 {
+		t.Errorf("resumed trip ended at (%d,%d), want the stairs", g.party.X, g.party.Y)
+	}
+}
+
+func TestTravelHaltsWhenPartyIsHurt(t *testing.T) {
+	g := startTravelGame(t)
+	g.travelToStairs()
+	walk(g, 2)
+
+	g.party.Members[0].TakeDamage(1)
+	x, y := g.party.X, g.party.Y
+	g.travelStep(context.Background())
+
+	if g.travel != nil || g.party.X != x || g.party.Y != y {
+		t.Error("travel should stop before the next step once the party is hurt")
+	}
+}
+
+func TestAnyKeyStopsTravel(t *testing.T) {
+	g := startTravelGame(t)
+	g.travelToStairs()
+	walk(g, 1)
+	x, y := g.party.X, g.party.Y
+
+	g.handleKeyEvent(context.Background(), pressRune('l'))
+
+	if g.travel != nil {
+		t.Error("a key press should stop travel")
+	}
+	if g.party.X != x || g.party.Y != y {
+		t.Error("the key that stops travel shouldn't also move the party")
+	}
+	if g.resumable == nil {
+		t.Error("a stopped trip should be resumable")
+	}
+}
+
+func TestStaleTravelTickIsIgnored(t *testing.T) {
+	g := startTravelGame(t)
+	g.travelToStairs()
+	stale := g.travel.seq
+	g.handleKeyEvent(context.Background(), pressRune('l'))
+	g.resumeTravel()
+
+	g.display = &queueDisplay{events: make(chan tcell.Event, 1)}
+	g.display.PostEvent(tcell.NewEventInterrupt(travelTick{seq: stale}))
+	g.handleInput(context.Background())
+
+	if g.party.X != 1 || g.party.Y != 1 {
+		t.Errorf("a tick from the cancelled trip moved the party to (%d,%d)", g.party.X, g.party.Y)
+	}
+}
+
+func TestClickTravelsToTile(t *testing.T) {
+	g := startTravelGame(t)
+
+	g.handleMouse(tcell.NewEventMouse(8, 1, tcell.Button1, tcell.ModNone))
+	if g.travel == nil {
+		t.Fatal("clicking a floor tile should start travel")
+	}
+	walk(g, 50)
+	if g.party.X != 8 || g.party.Y != 1 {
+		t.Errorf("party at (%d,%d), want the clicked tile (8,1)", g.party.X, g.party.Y)
+	}
+
+	// The far corridor is only reachable across the stairs, which a trip
+	// elsewhere never crosses
+	g.handleMouse(tcell.NewEventMouse(5, 3, tcell.Button1, tcell.ModNone))
+	if g.travel != nil {
+		t.Error("travel shouldn't route across the stairs")
+	}
+
+	g.handleMouse(tcell.NewEventMouse(0, 0, tcell.Button1, tcell.ModNone))
+	if g.travel != nil {
+		t.Error("clicking a wall shouldn't start travel")
+	}
+}
+
+func TestAutoExploreHeadsForNearestUnvisitedRoom(t *testing.T) {
+	g := newTestGame(t)
+	g.dungeon = dungeonFromMap(`
+#################
+#...#.....#.....#
+#...#.....#.....#
+#...............#
+#...#.....#.....#
+#################`)
+	g.dungeon.Rooms = []world.Room{
+		{X: 1, Y: 1, Width: 3, Height: 4},
+		{X: 11, Y: 1, Width: 5, Height: 4},
+		{X: 5, Y: 1, Width: 5, Height: 4},
+	}
+	g.party.SetPosition(2, 3)
+
+	g.autoExplore()
+	walk(g, 50)
+
+	if want := g.dungeon.Rooms[2]; !want.Contains(g.party.X, g.party.Y) {
+		t.Errorf("party at (%d,%d), want the middle room", g.party.X, g.party.Y)
+	}
+	if !g.visitedRooms[0] || !g.visitedRooms[2] || g.visitedRooms[1] {
+		t.Errorf("visited = %v, want the start and middle rooms", g.visitedRooms)
+	}
+}
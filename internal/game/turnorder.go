@@ -0,0 +1,137 @@
+package game
+
+import (
+	"github.com/samdwyer/dungeonband/internal/combat"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+	"github.com/samdwyer/dungeonband/internal/ui"
+)
+
+// turnSlot is one action in a side's turn order for a round.
+type turnSlot struct {
+	index int  // Index into the party's members or the encounter's enemies
+	extra bool // A hasted combatant's extra action
+}
+
+// hasteRound reports whether hasted combatants get an extra action in the
+// round: every other round, starting with the second.
+func hasteRound(round int) bool {
+	return round%2 == 0
+}
+
+// slowSkipRound reports whether slowed combatants lose their action in the
+// round: every third round.
+func slowSkipRound(round int) bool {
+	return round%3 == 0
+}
+
+// roundOrder returns one side's turn order for the round. Living combatants
+// act in order, then hasted ones take their extra actions, then slowed
+// ones act last. Delayed combatants don't act. slowAll treats everyone as
+// slowed. The order depends only on the side, its statuses and the round,
+// so it never draws from the RNG.
+func roundOrder[C combat.Combatant](side []C, round int, slowAll bool) []turnSlot {
+	var order, extras, slowed []turnSlot
+	for i, c := range side {
+		if !c.IsAlive() {
+			continue
+		}
+		switch {
+		case combat.HasStatus(c, gamedata.StatusDelay):
+			continue
+		case slowAll || combat.HasStatus(c, gamedata.StatusSlow):
+			if !slowSkipRound(round) {
+				slowed = append(slowed, turnSlot{index: i})
+			}
+		case combat.HasStatus(c, gamedata.StatusHaste):
+			order = append(order, turnSlot{index: i})
+			if hasteRound(round) {
+				extras = append(extras, turnSlot{index: i, extra: true})
+			}
+		default:
+			order = append(order, turnSlot{index: i})
+		}
+	}
+	order = append(order, extras...)
+	return append(order, slowed...)
+}
+
+// startPartyRound queues the party's actions for the current round and
+// hands the first to its member. Returns false if nobody in the party can
+// act this round.
+func (g *Game) startPartyRound() bool {
+	g.passTurn()
+	cs := g.combatState
+	cs.partyQueue = roundOrder(g.party.Members, cs.Round, cs.slowAll())
+	cs.queuedRound = cs.Round
+	cs.planned = nil
+	return g.nextQueuedMember()
+}
+
+// nextQueuedMember hands the turn to the next living member in the party's
+// queue. Returns false once the queue is empty.
+func (g *Game) nextQueuedMember() bool {
+	cs := g.combatState
+	if cs.queuedRound != cs.Round {
+		// The round began without queueing the party, so pick its order up
+		// after the member already acting
+		cs.partyQueue = roundOrder(g.party.Members, cs.Round, cs.slowAll())
+		cs.queuedRound = cs.Round
+		for i, slot := range cs.partyQueue {
+			if slot.index == cs.ActiveMemberIndex {
+				cs.partyQueue = cs.partyQueue[i+1:]
+				break
+			}
+		}
+	}
+	for len(cs.partyQueue) > 0 {
+		slot := cs.partyQueue[0]
+		cs.partyQueue = cs.partyQueue[1:]
+		if slot.index < len(g.party.Members) && g.party.Members[slot.index].IsAlive() {
+			cs.ActiveMemberIndex = slot.index
+			return true
+		}
+	}
+	return false
+}
+
+// delayedEnemyActions notes each living enemy that loses its turn to delay
+// this round, for the enemy phase's action list.
+func (cs *CombatState) delayedEnemyActions() []string {
+	var actions []string
+	for _, e := range cs.Enemies {
+		if e.IsAlive() && combat.HasStatus(e, gamedata.StatusDelay) {
+			actions = append(actions, e.GetName()+" is delayed and loses its turn.")
+		}
+	}
+	return actions
+}
+
+// turnOrder lists who acts after the active member this round, for the
+// combat panel: the rest of the party's queue, then the enemies.
+func (g *Game) turnOrder() []ui.TurnSlot {
+	cs := g.combatState
+	var slots []ui.TurnSlot
+	for _, s := range cs.partyQueue {
+		if m := g.party.Members[s.index]; m.IsAlive() {
+			slots = append(slots, cs.turnSlotInfo(m, s))
+		}
+	}
+	for _, s := range roundOrder(cs.Enemies, cs.Round, cs.slowAll()) {
+		slots = append(slots, cs.turnSlotInfo(cs.Enemies[s.index], s))
+	}
+	return slots
+}
+
+// turnSlotInfo describes a turn slot for the combat panel.
+func (cs *CombatState) turnSlotInfo(c combat.Combatant, s turnSlot) ui.TurnSlot {
+	return ui.TurnSlot{
+		Name:   c.GetName(),
+		Extra:  s.extra,
+		Slowed: cs.slowAll() || combat.HasStatus(c, gamedata.StatusSlow),
+	}
+}
+
+// slowAll reports whether the fight's surroundings slow everyone.
+func (cs *CombatState) slowAll() bool {
+	return cs.Theme != nil && cs.Theme.Modifiers.Slow
+}
@@ -0,0 +1,141 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/samdwyer/dungeonband/internal/combat"
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+)
+
+// playRounds has every party member defend until n rounds have passed,
+// returning who acted in each round.
+func playRounds(g *Game, n int) [][]int {
+	defend := g.abilityRegistry.GetByID("defend")
+	rounds := make([][]int, n)
+	for g.combatState.Round <= n && g.combatState.Phase == PhasePlayerTurn {
+		round := g.combatState.Round
+		i := g.combatState.ActiveMemberIndex
+		rounds[round-1] = append(rounds[round-1], i)
+		g.performPlayerAction(context.Background(), defend, g.party.Members[i], g.party.Members[i])
+	}
+	return rounds
+}
+
+// startTurnOrderCombat starts a fight against a goblin that neither side
+// can finish off.
+func startTurnOrderCombat(t *testing.T) (*Game, *entity.Enemy) {
+	t.Helper()
+	goblin := entity.NewEnemy(entity.EnemyGoblin, 5, 5, 1)
+	goblin.HP, goblin.MaxHP = 1000, 1000
+	g := startStatsCombat(t, goblin)
+	for _, m := range g.party.Members {
+		m.HP, m.MaxHP = 1000, 1000
+	}
+	return g, goblin
+}
+
+func TestHasteGrantsExtraActionEveryOtherRound(t *testing.T) {
+	g, _ := startTurnOrderCombat(t)
+	g.party.Members[1].AddStatusEffect(combat.StatusEffect{Type: gamedata.StatusHaste, RemainingTurns: 99})
+
+	got := fmt.Sprint(playRounds(g, 4))
+	want := "[[0 1 2 3] [0 1 2 3 1] [0 1 2 3] [0 1 2 3 1]]"
+	if got != want {
+		t.Errorf("rounds = %s, want %s", got, want)
+	}
+}
+
+func TestSlowActsLastAndSkipsEveryThirdRound(t *testing.T) {
+	g, _ := startTurnOrderCombat(t)
+	g.party.Members[0].AddStatusEffect(combat.StatusEffect{Type: gamedata.StatusSlow, RemainingTurns: 99})
+	g.startPartyRound() // Slowed before the first round's order is set
+
+	got := fmt.Sprint(playRounds(g, 4))
+	want := "[[1 2 3 0] [1 2 3 0] [1 2 3] [1 2 3 0]]"
+	if got != want {
+		t.Errorf("rounds = %s, want %s", got, want)
+	}
+}
+
+func TestHastedEnemyActsTwice(t *testing.T) {
+	g, goblin := startTurnOrderCombat(t)
+	goblin.AddStatusEffect(combat.StatusEffect{Type: gamedata.StatusHaste, RemainingTurns: 99})
+
+	counts := make([]int, 3)
+	for round := range counts {
+		playRounds(g, round+1)
+		counts[round] = len(g.combatState.EnemyActions)
+	}
+	if fmt.Sprint(counts) != "[1 2 1]" {
+		t.Errorf("goblin actions per round = %v, want [1 2 1]", counts)
+	}
+}
+
+func TestTurnOrderMarksHasteAndSlow(t *testing.T) {
+	g, goblin := startTurnOrderCombat(t)
+	g.combatState.Round = 2
+	g.party.Members[1].AddStatusEffect(combat.StatusEffect{Type: gamedata.StatusHaste, RemainingTurns: 99})
+	goblin.AddStatusEffect(combat.StatusEffect{Type: gamedata.StatusSlow, RemainingTurns: 99})
+	g.startPartyRound()
+
+	var got []string
+	for _, s := range g.buildCombatInfo().TurnOrder {
+		got = append(got, fmt.Sprintf("%s/%v/%v", s.Name, s.Extra, s.Slowed))
+	}
+	want := "[Shade/false/false Zephyr/false/false Celeste/false/false Shade/true/false Goblin/false/true]"
+	if fmt.Sprint(got) != want {
+		t.Errorf("turn order = %v, want %s", got, want)
+	}
+}
+
+func TestDelayRemovesEnemysTurnThisRound(t *testing.T) {
+	g, goblin := startTurnOrderCombat(t)
+	trip := g.abilityRegistry.GetByID("trip")
+	warrior, rogue := g.party.Members[0], g.party.Members[1]
+	g.performPlayerAction(context.Background(), g.abilityRegistry.GetByID("defend"), warrior, warrior)
+	rogue.MP = rogue.MaxMP
+	g.performPlayerAction(context.Background(), trip, rogue, goblin)
+	if !combat.HasStatus(goblin, gamedata.StatusDelay) {
+		t.Fatal("trip did not delay the goblin")
+	}
+
+	for _, s := range g.turnOrder() {
+		if s.Name == goblin.GetName() {
+			t.Errorf("turn order still lists the delayed goblin: %v", g.turnOrder())
+		}
+	}
+	playRounds(g, 1)
+	want := []string{"Goblin is delayed and loses its turn."}
+	if fmt.Sprint(g.combatState.EnemyActions) != fmt.Sprint(want) {
+		t.Errorf("round 1 enemy actions = %q, want %q", g.combatState.EnemyActions, want)
+	}
+
+	playRounds(g, 2)
+	if combat.HasStatus(goblin, gamedata.StatusDelay) || len(g.combatState.EnemyActions) != 1 ||
+		g.combatState.EnemyActions[0] == want[0] {
+		t.Errorf("goblin should act again in round 2, got %q", g.combatState.EnemyActions)
+	}
+}
+
+func TestTripCannotBeChained(t *testing.T) {
+	g, goblin := startTurnOrderCombat(t)
+	trip := g.abilityRegistry.GetByID("trip")
+	warrior, rogue := g.party.Members[0], g.party.Members[1]
+	rogue.MP = rogue.MaxMP
+	g.performPlayerAction(context.Background(), g.abilityRegistry.GetByID("defend"), warrior, warrior)
+	g.performPlayerAction(context.Background(), trip, rogue, goblin)
+
+	for round := 2; round <= 3; round++ {
+		playRounds(g, round-1)
+		if g.canCast(rogue, trip) {
+			t.Fatalf("the rogue can trip again in round %d", round)
+		}
+	}
+	playRounds(g, 3)
+	if !g.canCast(rogue, trip) {
+		t.Error("trip should be ready again in round 4")
+	}
+}
@@ -0,0 +1,128 @@
+package game
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+	"github.com/samdwyer/dungeonband/internal/telemetry"
+	"github.com/samdwyer/dungeonband/pkg/dungeon"
+)
+
+// trigger is a scripted one-shot instruction on the current floor, fired by
+// stepping on its tile or by a game event.
+type trigger struct {
+	x, y   int
+	onTile bool   // Fires when the party steps on (x, y)
+	event  string // Otherwise fires on this event
+	title  string
+	text   string
+	fired  bool
+}
+
+// setupTutorial builds the hand-authored tutorial floor: its layout, the
+// party, the training dummy and the floor's triggers.
+func (g *Game) setupTutorial() error {
+	def, err := gamedata.LoadTutorial()
+	if err != nil {
+		return err
+	}
+
+	g.dungeon = dungeon.FromLayout(def.Layout)
+	g.enemies = nil
+	markers := make(map[string]position)
+	startX, startY := 1, 1
+	for y, row := range def.Layout {
+		for x, ch := range row {
+			switch {
+			case ch == gamedata.TutorialPartyStart:
+				startX, startY = x, y
+			case ch == gamedata.TutorialDummy && g.enemyRegistry != nil:
+				if dummy := g.enemyRegistry.GetByID(gamedata.TutorialDummyID); dummy != nil {
+					g.enemies = append(g.enemies, entity.NewEnemyFromDef(dummy, x, y, -1))
+				}
+			case ch >= '1' && ch <= '9':
+				markers[string(ch)] = position{x, y}
+			}
+		}
+	}
+
+	if g.classRegistry != nil {
+		g.party = entity.NewPartyWithClassData(startX, startY, g.classRegistry)
+	} else {
+		g.party = entity.NewParty(startX, startY)
+	}
+
+	g.entry = position{startX, startY}
+	g.triggers = nil
+	for _, t := range def.Triggers {
+		trig := &trigger{event: t.Event, title: t.Title, text: t.Text}
+		if t.Marker != "" {
+			pos := markers[t.Marker]
+			trig.x, trig.y, trig.onTile = pos.x, pos.y, true
+		}
+		g.triggers = append(g.triggers, trig)
+	}
+	g.fireEvent(gamedata.TutorialEventStart)
+	return nil
+}
+
+// fireEvent shows the instructions of unfired triggers waiting on the event.
+func (g *Game) fireEvent(event string) {
+	for _, t := range g.triggers {
+		if !t.fired && !t.onTile && t.event == event {
+			g.showInstruction(t)
+		}
+	}
+}
+
+// fireTileTriggers shows the instructions of unfired triggers under the party.
+func (g *Game) fireTileTriggers() {
+	for _, t := range g.triggers {
+		if !t.fired && t.onTile && t.x == g.party.X && t.y == g.party.Y {
+			g.showInstruction(t)
+		}
+	}
+}
+
+// showInstruction queues a trigger's panel and marks it fired.
+func (g *Game) showInstruction(t *trigger) {
+	t.fired = true
+	g.instructions = append(g.instructions, t)
+}
+
+// dismissInstruction closes the open instruction panel, revealing the next
+// queued one if any.
+func (g *Game) dismissInstruction() {
+	g.instructions = g.instructions[1:]
+}
+
+// completeTutorial ends the tutorial run once the party reaches the stairs.
+func (g *Game) completeTutorial(ctx context.Context) {
+	tracer := telemetry.Tracer("game")
+	_, span := tracer.Start(ctx, "game.tutorial_complete")
+	span.SetAttributes(attribute.Int("enemies_alive", g.aliveEnemyCount()))
+	span.SetAttributes(g.runAttrs()...)
+	span.End()
+
+	g.tutorialComplete = true
+	g.running = false
+}
+
+// aliveEnemyCount returns the number of living enemies on the floor.
+func (g *Game) aliveEnemyCount() int {
+	count := 0
+	for _, e := range g.enemies {
+		if e.IsAlive() {
+			count++
+		}
+	}
+	return count
+}
+
+// TutorialCompleted reports whether the run was a tutorial the player finished.
+func (g *Game) TutorialCompleted() bool {
+	return g.tutorialComplete
+}
@@ -0,0 +1,117 @@
+package game
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+)
+
+// newTutorialGame sets up the tutorial floor on a test game.
+func newTutorialGame(t *testing.T) *Game {
+	t.Helper()
+	g := newTestGame(t)
+	g.tutorial = true
+	g.setup(context.Background())
+	if !g.tutorial {
+		t.Fatal("tutorial failed to load")
+	}
+	return g
+}
+
+// openInstruction returns the title of the open instruction panel, or "".
+func openInstruction(g *Game) string {
+	if len(g.instructions) == 0 {
+		return ""
+	}
+	return g.instructions[0].title
+}
+
+func TestTutorialFloorLayout(t *testing.T) {
+	g := newTutorialGame(t)
+
+	if g.dungeon.GetTile(g.party.X, g.party.Y) != '.' {
+		t.Errorf("party starts on %q, want floor", g.dungeon.GetTile(g.party.X, g.party.Y))
+	}
+	if len(g.enemies) != 1 || g.enemies[0].ID() != gamedata.TutorialDummyID {
+		t.Fatalf("enemies = %v, want a single training dummy", g.enemies)
+	}
+	if g.enemies[0].Attack() != 1 {
+		t.Errorf("dummy attack = %d, want 1", g.enemies[0].Attack())
+	}
+	if g.dungeon.StairsX < 0 {
+		t.Error("tutorial floor has no stairs")
+	}
+}
+
+func TestTutorialTriggersFireOnce(t *testing.T) {
+	ctx := context.Background()
+	g := newTutorialGame(t)
+
+	if got := openInstruction(g); got != "Welcome to DungeonBand" {
+		t.Fatalf("opening panel = %q, want the welcome", got)
+	}
+
+	// Any key dismisses the panel without moving the party
+	x := g.party.X
+	g.handleKeyEvent(ctx, tcell.NewEventKey(tcell.KeyRight, 0, tcell.ModNone))
+	if openInstruction(g) != "" || g.party.X != x {
+		t.Fatal("the first key should only dismiss the panel")
+	}
+
+	// Stepping onto the doorway marker fires its trigger
+	var door *trigger
+	for _, trig := range g.triggers {
+		if trig.onTile {
+			door = trig
+			break
+		}
+	}
+	g.party.SetPosition(door.x-1, door.y)
+	g.tryMove(ctx, 1, 0)
+	if got := openInstruction(g); got != door.title {
+		t.Fatalf("panel = %q, want %q", got, door.title)
+	}
+	g.dismissInstruction()
+
+	// Stepping back on doesn't fire it again
+	g.tryMove(ctx, -1, 0)
+	g.tryMove(ctx, 1, 0)
+	if got := openInstruction(g); got != "" {
+		t.Errorf("trigger fired twice: %q", got)
+	}
+}
+
+func TestTutorialCombatAndCompletion(t *testing.T) {
+	ctx := context.Background()
+	g := newTutorialGame(t)
+	g.dismissInstruction()
+
+	dummy := g.enemies[0]
+	g.party.SetPosition(dummy.X-3, dummy.Y)
+	g.transitionState(ctx, StateCombat, "manual")
+	if got := openInstruction(g); got != "Using abilities" {
+		t.Fatalf("combat panel = %q, want ability instructions", got)
+	}
+	g.dismissInstruction()
+
+	dummy.TakeDamage(dummy.GetHP())
+	g.declareVictory()
+	g.handleCombatEnd(ctx)
+	if got := openInstruction(g); got != "Healing" {
+		t.Fatalf("victory panel = %q, want healing instructions", got)
+	}
+	g.dismissInstruction()
+
+	// The stairs finish the tutorial instead of descending
+	g.party.SetPosition(g.dungeon.StairsX, g.dungeon.StairsY-1)
+	g.tryMove(ctx, 0, 1)
+	if !g.TutorialCompleted() || g.running {
+		t.Errorf("completed = %v, running = %v; want the tutorial to end", g.TutorialCompleted(), g.running)
+	}
+	if g.floor != 1 {
+		t.Errorf("floor = %d, tutorial stairs should not descend", g.floor)
+	}
+}
@@ -0,0 +1,95 @@
+package game
+
+import (
+	"context"
+	"slices"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/samdwyer/dungeonband/internal/combat"
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+	"github.com/samdwyer/dungeonband/internal/telemetry"
+)
+
+// pendingRise is a fallen undying enemy waiting to get back up.
+type pendingRise struct {
+	enemy  *entity.Enemy
+	rounds int // New rounds left before it rises
+}
+
+// markUndying decides whether a freshly killed enemy will rise again and,
+// if so, queues it. ability is what killed it, nil for status ticks.
+func (g *Game) markUndying(ctx context.Context, enemy *entity.Enemy, ability *gamedata.AbilityDef) {
+	undying := enemy.Def.Undying
+	if undying == nil {
+		return
+	}
+	cs := g.combatState
+	if cs.rises == nil {
+		cs.rises = make(map[*entity.Enemy]int)
+	}
+
+	tracer := telemetry.Tracer("combat")
+	_, span := tracer.Start(ctx, "combat.undying")
+	defer span.End()
+	span.SetAttributes(
+		cs.encounterAttr(),
+		attribute.String("enemy", enemy.ID()),
+		attribute.Int("rises", cs.rises[enemy]),
+	)
+
+	switch {
+	case undying.KeptDownBy(ability):
+		span.SetAttributes(attribute.String("outcome", "permakilled"))
+		g.telegraphDeath(enemy.GetName() + " crumbles to dust!")
+		return
+	case cs.rises[enemy] >= undying.MaxRises():
+		span.SetAttributes(attribute.String("outcome", "spent"))
+		return
+	case undying.Chance < 100 && g.dice.Roll("undying", 100) >= undying.Chance:
+		span.SetAttributes(attribute.String("outcome", "stays_down"))
+		return
+	}
+	span.SetAttributes(attribute.String("outcome", "pending"))
+	cs.pendingRises = append(cs.pendingRises, &pendingRise{enemy: enemy, rounds: undying.RoundsDown()})
+	g.telegraphDeath(enemy.GetName() + "'s bones twitch...")
+}
+
+// raiseUndead counts down the fallen undead at the start of a new round and
+// raises those whose time has come, with a share of their MaxHP.
+func (g *Game) raiseUndead(ctx context.Context) {
+	cs := g.combatState
+	var risen []*entity.Enemy
+	cs.pendingRises = slices.DeleteFunc(cs.pendingRises, func(p *pendingRise) bool {
+		p.rounds--
+		if p.rounds > 0 {
+			return false
+		}
+		risen = append(risen, p.enemy)
+		return true
+	})
+
+	tracer := telemetry.Tracer("combat")
+	for _, enemy := range risen {
+		hp := enemy.Heal(max(enemy.MaxHP*enemy.Def.Undying.HPPercent/100, 1))
+		for _, effect := range enemy.GetStatusEffects() {
+			enemy.RemoveStatusEffect(effect.Type)
+		}
+		cs.rises[enemy]++
+		delete(cs.deathsResolved, enemy)
+
+		message := enemy.GetName() + " rises again!"
+		g.noteAction(nil, enemy, enemy, combat.EffectResult{Success: true, Healing: hp, Message: message}, false)
+		cs.LastMessage += " " + message
+
+		_, span := tracer.Start(ctx, "combat.rise")
+		span.SetAttributes(
+			cs.encounterAttr(),
+			attribute.String("enemy", enemy.ID()),
+			attribute.Int("hp", enemy.HP),
+			attribute.Int("turn", cs.TurnCount),
+		)
+		span.End()
+	}
+}
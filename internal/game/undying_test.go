@@ -0,0 +1,74 @@
+package game
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestUndyingDefersVictoryUntilItRises(t *testing.T) {
+	g, revenant := startDeathTestCombat(t, "revenant")
+	revenant.HP = 1
+
+	warriorAttacks(g, revenant)
+
+	if g.combatState.Phase == PhaseVictory {
+		t.Fatal("victory declared while the revenant is still due to rise")
+	}
+	if !revenant.IsAlive() || revenant.GetHP() != 5 {
+		t.Fatalf("revenant at %d HP after the round, want it risen with 5", revenant.GetHP())
+	}
+	if g.combatState.Phase != PhasePlayerTurn {
+		t.Errorf("Phase = %v, want a new round once it rises", g.combatState.Phase)
+	}
+	if !strings.Contains(g.combatState.LastMessage, "Revenant rises again!") {
+		t.Errorf("LastMessage = %q, want the rise announced", g.combatState.LastMessage)
+	}
+
+	// It only rises once per fight
+	revenant.HP = 1
+	warriorAttacks(g, revenant)
+	if g.combatState.Phase != PhaseVictory {
+		t.Errorf("Phase = %v, want victory after the second kill", g.combatState.Phase)
+	}
+}
+
+func TestPermakillKeepsUndyingDown(t *testing.T) {
+	g, revenant := startDeathTestCombat(t, "revenant")
+	revenant.HP = 1
+	wizard := g.party.Members[2]
+	wizard.RestoreMP(100)
+
+	g.combatState.ActiveMemberIndex = 2
+	g.performPlayerAction(context.Background(), g.abilityRegistry.GetByID("fireball"), wizard, revenant)
+
+	if g.combatState.Phase != PhaseVictory {
+		t.Errorf("Phase = %v, want victory after a magical kill", g.combatState.Phase)
+	}
+	if len(g.combatState.pendingRises) != 0 {
+		t.Error("a permakilled enemy should not be queued to rise")
+	}
+	if !strings.Contains(g.combatState.LastMessage, "crumbles to dust") {
+		t.Errorf("LastMessage = %q", g.combatState.LastMessage)
+	}
+}
+
+func TestUndyingWaitsOutItsDelay(t *testing.T) {
+	g, revenant := startDeathTestCombat(t, "revenant")
+	undying := *revenant.Def.Undying
+	undying.Delay = 2
+	def := *revenant.Def
+	def.Undying = &undying
+	revenant.Def = &def
+	revenant.HP = 1
+
+	warriorAttacks(g, revenant)
+	if revenant.IsAlive() || g.combatState.Phase == PhaseVictory {
+		t.Fatalf("after one round: alive=%v phase=%v, want still down and no victory", revenant.IsAlive(), g.combatState.Phase)
+	}
+
+	g.executeEnemyTurns(context.Background())
+	if !revenant.IsAlive() {
+		t.Error("revenant should rise at the start of the second round")
+	}
+}
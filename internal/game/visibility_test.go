@@ -0,0 +1,92 @@
+package game
+
+import (
+	"context"
+	"testing"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/world"
+)
+
+// visibilityLayout is a room (west) joined to an L-shaped corridor (east).
+const visibilityLayout = `
+####################
+#.....##############
+#...................
+#.....#############.
+#.....#############.
+####################`
+
+func TestCorridorEnemyVisibleAndJoinsCombat(t *testing.T) {
+	g, sim := newTestGameWithScreen(t)
+	g.dungeon = dungeonFromMap(visibilityLayout)
+	g.party.X, g.party.Y = 3, 2
+
+	corridor := entity.NewEnemy(entity.EnemyGoblin, 8, 2, -1)   // In the corridor, in plain sight
+	aroundCorner := entity.NewEnemy(entity.EnemyOrc, 19, 4, -1) // Around the corridor's bend
+	g.enemies = []*entity.Enemy{corridor, aroundCorner}
+
+	g.display.RenderExplore(g.dungeon, g.party, g.enemies, g.seed)
+
+	if got := cellAt(sim, 8, 2); got != corridor.Symbol {
+		t.Errorf("corridor enemy not drawn: got %q at (8,2)", got)
+	}
+	if got := cellAt(sim, 19, 4); got == aroundCorner.Symbol {
+		t.Error("enemy around the corner should not be drawn")
+	}
+
+	g.transitionState(context.Background(), StateCombat, "test")
+
+	if len(g.combatEnemies) != 1 || g.combatEnemies[0] != corridor {
+		t.Errorf("combat should include only the visible corridor enemy, got %d enemies", len(g.combatEnemies))
+	}
+}
+
+func TestEnemyOutsideSightRadiusHidden(t *testing.T) {
+	g, sim := newTestGameWithScreen(t)
+	g.dungeon = dungeonFromMap(visibilityLayout)
+	g.party.X, g.party.Y = 1, 2
+
+	far := entity.NewEnemy(entity.EnemyGoblin, 18, 2, -1) // Straight down the corridor, but 17 tiles away
+	g.enemies = []*entity.Enemy{far}
+
+	g.display.RenderExplore(g.dungeon, g.party, g.enemies, g.seed)
+
+	if got := cellAt(sim, 18, 2); got == far.Symbol {
+		t.Error("enemy beyond sight radius should not be drawn")
+	}
+}
+
+func TestEnemyRoundCorridorBendOffPartyRoomJoinsCombat(t *testing.T) {
+	g, sim := newTestGameWithScreen(t)
+	g.dungeon = dungeonFromMap(`
+##############
+#.....########
+#.........####
+#.....###.####
+#.....###.####
+##############`)
+	g.dungeon.Rooms = []world.Room{{X: 1, Y: 1, Width: 5, Height: 4}, {X: 9, Y: 6, Width: 4, Height: 4}}
+	g.dungeon.Corridors = []world.Corridor{{
+		ID:    0,
+		Rooms: [2]int{0, 1},
+		Tiles: []world.Point{{X: 6, Y: 2}, {X: 7, Y: 2}, {X: 8, Y: 2}, {X: 9, Y: 2}, {X: 9, Y: 3}, {X: 9, Y: 4}},
+	}}
+	g.party.X, g.party.Y = 3, 2
+
+	lurker := entity.NewEnemy(entity.EnemyGoblin, 9, 4, -1)
+	g.enemies = []*entity.Enemy{lurker}
+	if g.dungeon.CanSee(3, 2, 9, 4, world.SightRadius) {
+		t.Fatal("test layout should hide the corridor's bend")
+	}
+
+	g.display.RenderExplore(g.dungeon, g.party, g.enemies, g.seed)
+	if got := cellAt(sim, 9, 4); got != lurker.Symbol {
+		t.Errorf("enemy in a corridor off the party's room not drawn: got %q at (9,4)", got)
+	}
+
+	g.transitionState(context.Background(), StateCombat, "test")
+	if len(g.combatEnemies) != 1 || g.combatEnemies[0] != lurker {
+		t.Errorf("combat should include the enemy in the corridor, got %d enemies", len(g.combatEnemies))
+	}
+}
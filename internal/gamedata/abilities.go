@@ -1,5 +1,7 @@
 package gamedata
 
+import "math"
+
 // =============================================================================
 // ABILITY SYSTEM DESIGN
 // =============================================================================
@@ -18,6 +20,7 @@ package gamedata
 //    - heal: Restores target HP
 //    - buff: Applies positive status effect
 //    - debuff: Applies negative status effect
+//    - cleanse: Removes negative status effects
 //
 // 2. TargetType - Who the ability affects:
 //    - self: The caster only
@@ -28,8 +31,10 @@ package gamedata
 //
 // 3. DamageType - For damage/heal abilities:
 //    - physical: Reduced by Defense stat
-//    - magical: Ignores Defense (or uses Magic Defense if we add it)
+//    - magical: Reduced by Resist (magic defense) instead of Defense
 //    - true: Cannot be reduced
+//    How power, offense and defense combine depends on the run's damage
+//    formula (difficulty.json's damageFormula, "additive" by default).
 //
 // 4. StatusEffect - For buff/debuff abilities:
 //    - poison: Damage over time
@@ -37,7 +42,14 @@ package gamedata
 //    - defense_up: Increased defense
 //    - defense_down: Decreased defense
 //    - attack_up: Increased attack
-//    - attack_down: Decreased attack
+//    - attack_down: Attack cut to three quarters for physical damage
+//    - silence: Cannot use abilities that cost MP. Only MP costs are
+//      blocked; abilities paid for with other resources are unaffected.
+//    - taunt: Enemies' single-target attacks must target the taunter.
+//    - haste: An extra action at the end of every other round.
+//    - slow: Acts last on its side and sits out every third round.
+//    - delay: Loses its remaining actions this round. Unlike slow it
+//      doesn't change the order, it only takes the next turn away.
 //
 // JSON Schema:
 // ------------
@@ -49,32 +61,70 @@ package gamedata
 //   "targetType": "single_enemy",
 //   "damageType": "magical",
 //   "basePower": 15,
+//   "attackScale": 1.0,
+//   "magicScale": 1.2,
+//   "levelScale": 2,
 //   "mpCost": 5,
 //   "cooldown": 0,
 //   "statusEffect": null,
-//   "statusDuration": 0
+//   "statusDuration": 0,
+//   "usableOutOfCombat": false
 // }
 //
+// usableOutOfCombat and preCastable are optional; see UsableOutOfCombat and
+// PreCastable for the defaults.
+// attackScale and magicScale default to 1.0 and levelScale to 0, which is
+// the flat basePower + stat formula.
+// A poison or regen can grow with its caster through statusMagicScale,
+// which defaults to 0 for a fixed statusPower:
+//
+//   "statusPower": 2, "statusMagicScale": 0.5
+//
+// Damage abilities may also scale with HP through hpScaling, e.g. an
+// execute that hits low-HP targets harder:
+//
+//   "hpScaling": {"of": "target", "power": 10, "threshold": 0.3}
+//
 // Damage Calculation:
 // -------------------
-// Physical: damage = basePower + attacker.Attack - target.Defense (min 1)
-// Magical:  damage = basePower + attacker.Magic (min 1)
-// True:     damage = basePower
+// power = basePower + levelScale * caster level + HP bonus (damage only)
+// Physical: damage = power + attackScale * attacker.Attack - target.Defense (min 1)
+// Magical:  damage = power + magicScale * attacker.Magic - target.Resist (min 1)
+// True:     damage = power
+//
+// Healing: heal = power + magicScale * caster.Magic (min 1)
 //
-// Healing: heal = basePower + caster.Magic (or flat basePower)
+// Status power: statusPower + statusMagicScale * caster.Magic, fixed when
+// the status is applied
+//
+// The HP bonus is hpScaling.power times the missing share of the user's or
+// target's max HP, or with a threshold, the full power once their HP is at
+// or below that share and nothing above it. Scaled terms are rounded to the
+// nearest whole number. Combatants without a level count as level 1.
 //
 // Integration Points:
 // -------------------
 // 1. EnemyDef.Abilities []string - list of ability IDs enemy can use
 // 2. ClassDef.Abilities []string - list of ability IDs class starts with
-// 3. Member gains HP, MP, Attack, Defense, Magic stats
-// 4. Enemy already has HP, Attack, Defense (add MP, Magic)
+// 3. Member gains HP, MP, Attack, Defense, Magic, Resist stats
+// 4. Enemy already has HP, Attack, Defense, Resist (add MP, Magic)
 // 5. Combat system resolves abilities turn by turn
 //
+// Rows:
+// -----
+// Enemies stand in a front or back row (EnemyDef.Row, default front).
+// Back-row enemies can only be targeted by abilities that ReachesBackRow()
+// and take reduced physical damage. When the front row falls, the back row
+// is promoted to the front.
+//
 // Turn Order:
 // -----------
-// Simple: Party members act first (in order), then enemies (in order)
-// Future: Speed-based initiative system
+// Party members act first (in order), then enemies (in order). Within each
+// side hasted combatants take an extra action at the end of every other
+// round, and slowed ones act last and sit out every third round. Delayed
+// combatants lose whatever actions they have left in the round. An ability
+// with a cooldown of N can't be used again by the same combatant for the
+// next N rounds.
 //
 // Combat Flow:
 // ------------
@@ -95,7 +145,7 @@ package gamedata
 //
 // Telemetry:
 // ----------
-// - combat.start: party_size, enemy_count, room_index
+// - combat.start: party_size, enemy_count, room_index, encounter.kind (boss/normal), encounter.boss_id
 // - combat.turn: actor_name, ability_id, target_name, damage/heal amount
 // - combat.end: outcome (victory/defeat/flee), turns_taken, party_hp_remaining
 
@@ -103,10 +153,11 @@ package gamedata
 type EffectType string
 
 const (
-	EffectDamage EffectType = "damage"
-	EffectHeal   EffectType = "heal"
-	EffectBuff   EffectType = "buff"
-	EffectDebuff EffectType = "debuff"
+	EffectDamage  EffectType = "damage"
+	EffectHeal    EffectType = "heal"
+	EffectBuff    EffectType = "buff"
+	EffectDebuff  EffectType = "debuff"
+	EffectCleanse EffectType = "cleanse"
 )
 
 // TargetType represents who an ability can target.
@@ -120,6 +171,15 @@ const (
 	TargetAllAllies   TargetType = "all_allies"
 )
 
+// Valid returns true for the target types the game knows how to resolve.
+func (t TargetType) Valid() bool {
+	switch t {
+	case TargetSelf, TargetSingleEnemy, TargetAllEnemies, TargetSingleAlly, TargetAllAllies:
+		return true
+	}
+	return false
+}
+
 // DamageType represents how damage is calculated.
 type DamageType string
 
@@ -129,6 +189,48 @@ const (
 	DamageTrue     DamageType = "true"
 )
 
+// DamageFormula names how damage combines an ability's power with the
+// attacker's offense and the target's defense.
+type DamageFormula string
+
+const (
+	// FormulaAdditive adds offense to power and subtracts defense.
+	FormulaAdditive DamageFormula = "additive"
+	// FormulaMultiplicative scales power up by offense and down by defense,
+	// so strong attackers get more out of strong abilities.
+	FormulaMultiplicative DamageFormula = "multiplicative"
+)
+
+// HPSubject is whose HP an ability's HPScaling reads.
+type HPSubject string
+
+const (
+	HPOfUser   HPSubject = "user"   // Desperation: stronger as the user is hurt
+	HPOfTarget HPSubject = "target" // Execute: stronger against hurt targets
+)
+
+// HPScaling adds damage based on how hurt the user or target is.
+type HPScaling struct {
+	Of        HPSubject `json:"of"`
+	Power     int       `json:"power"`               // Bonus at 0 HP
+	Threshold float64   `json:"threshold,omitempty"` // If set, the full bonus at or below this share of max HP and none above
+}
+
+// Bonus returns the extra power for a combatant at hp of maxHP.
+func (s *HPScaling) Bonus(hp, maxHP int) int {
+	if s == nil || maxHP <= 0 {
+		return 0
+	}
+	fraction := float64(max(hp, 0)) / float64(maxHP)
+	if s.Threshold > 0 {
+		if fraction <= s.Threshold {
+			return s.Power
+		}
+		return 0
+	}
+	return int(math.Round(float64(s.Power) * (1 - fraction)))
+}
+
 // StatusEffectType represents status effects that can be applied.
 type StatusEffectType string
 
@@ -140,22 +242,45 @@ const (
 	StatusDefenseDown StatusEffectType = "defense_down"
 	StatusAttackUp    StatusEffectType = "attack_up"
 	StatusAttackDown  StatusEffectType = "attack_down"
+	StatusSilence     StatusEffectType = "silence"
+	StatusTaunt       StatusEffectType = "taunt"
+	StatusHaste       StatusEffectType = "haste" // Extra action every other round
+	StatusSlow        StatusEffectType = "slow"  // Acts last, and not at all every third round
+	StatusDelay       StatusEffectType = "delay" // Loses its remaining actions this round
 )
 
+// IsNegative returns true for harmful status effects that cleanse removes.
+func (s StatusEffectType) IsNegative() bool {
+	switch s {
+	case StatusPoison, StatusDefenseDown, StatusAttackDown, StatusSilence, StatusSlow, StatusDelay:
+		return true
+	default:
+		return false
+	}
+}
+
 // AbilityDef defines an ability loaded from JSON.
 type AbilityDef struct {
-	ID             string           `json:"id"`
-	Name           string           `json:"name"`
-	Description    string           `json:"description"`
-	EffectType     EffectType       `json:"effectType"`
-	TargetType     TargetType       `json:"targetType"`
-	DamageType     DamageType       `json:"damageType,omitempty"`
-	BasePower      int              `json:"basePower"`
-	MPCost         int              `json:"mpCost"`
-	Cooldown       int              `json:"cooldown"`
-	StatusEffect   StatusEffectType `json:"statusEffect,omitempty"`
-	StatusDuration int              `json:"statusDuration,omitempty"`
-	StatusPower    int              `json:"statusPower,omitempty"` // For DoT/HoT effects
+	ID               string           `json:"id"`
+	Name             string           `json:"name"`
+	Description      string           `json:"description"`
+	EffectType       EffectType       `json:"effectType"`
+	TargetType       TargetType       `json:"targetType"`
+	DamageType       DamageType       `json:"damageType,omitempty"`
+	BasePower        int              `json:"basePower"`
+	AttackScale      *float64         `json:"attackScale,omitempty"` // Share of Attack added to damage (default 1)
+	MagicScale       *float64         `json:"magicScale,omitempty"`  // Share of Magic added to damage or healing (default 1)
+	LevelScale       float64          `json:"levelScale,omitempty"`  // Power added per caster level
+	HPScaling        *HPScaling       `json:"hpScaling,omitempty"`   // Damage added for missing HP
+	MPCost           int              `json:"mpCost"`
+	Cooldown         int              `json:"cooldown"`
+	StatusEffect     StatusEffectType `json:"statusEffect,omitempty"`
+	StatusDuration   int              `json:"statusDuration,omitempty"`
+	StatusPower      int              `json:"statusPower,omitempty"`       // For DoT/HoT effects
+	StatusMagicScale float64          `json:"statusMagicScale,omitempty"`  // Share of Magic added to statusPower
+	Ranged           bool             `json:"ranged,omitempty"`            // Can hit back-row targets
+	OutOfCombat      *bool            `json:"usableOutOfCombat,omitempty"` // Overrides the per-effect default
+	PreCast          *bool            `json:"preCastable,omitempty"`       // Overrides the buff/heal default
 }
 
 // NeedsTarget returns true if the ability requires target selection.
@@ -168,6 +293,58 @@ func (a *AbilityDef) IsOffensive() bool {
 	return a.TargetType == TargetSingleEnemy || a.TargetType == TargetAllEnemies
 }
 
+// PreCastable returns true if the ability can be cast before a fight, so its
+// effect carries into combat. Buffs and heals are unless preCastable says
+// otherwise.
+func (a *AbilityDef) PreCastable() bool {
+	if a.PreCast != nil {
+		return *a.PreCast
+	}
+	return !a.IsOffensive() && (a.EffectType == EffectBuff || a.EffectType == EffectHeal)
+}
+
+// UsableOutOfCombat returns true if the ability can be cast while exploring.
+// Unless the data says otherwise, cleanses and pre-castable heals and buffs
+// can be; offensive abilities and combat-only heals and buffs like taunt
+// can't.
+func (a *AbilityDef) UsableOutOfCombat() bool {
+	if a.OutOfCombat != nil {
+		return *a.OutOfCombat
+	}
+	if a.IsOffensive() {
+		return false
+	}
+	switch a.EffectType {
+	case EffectCleanse:
+		return true
+	case EffectHeal, EffectBuff:
+		return a.PreCastable()
+	}
+	return false
+}
+
+// AttackMultiplier returns the share of the user's Attack the ability adds.
+func (a *AbilityDef) AttackMultiplier() float64 {
+	if a.AttackScale == nil {
+		return 1
+	}
+	return *a.AttackScale
+}
+
+// MagicMultiplier returns the share of the user's Magic the ability adds.
+func (a *AbilityDef) MagicMultiplier() float64 {
+	if a.MagicScale == nil {
+		return 1
+	}
+	return *a.MagicScale
+}
+
+// ReachesBackRow returns true if the ability can target back-row enemies.
+// Ranged attacks, magical damage and debuffs reach the back row; melee does not.
+func (a *AbilityDef) ReachesBackRow() bool {
+	return a.Ranged || a.DamageType == DamageMagical || a.EffectType == EffectDebuff
+}
+
 // AbilitiesFile represents the structure of abilities.json.
 type AbilitiesFile struct {
 	Abilities []AbilityDef `json:"abilities"`
@@ -10,6 +10,7 @@ type ClassDef struct {
 	Attack    int      `json:"attack"`    // Base attack power
 	Defense   int      `json:"defense"`   // Base defense value
 	Magic     int      `json:"magic"`     // Base magic power
+	Resist    int      `json:"resist"`    // Base magic defense
 	Abilities []string `json:"abilities"` // List of ability IDs this class can use
 }
 
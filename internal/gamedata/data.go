@@ -0,0 +1,151 @@
+package gamedata
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Data file names, shared by the embedded data and override directories.
+const (
+	AbilitiesFileName = "abilities.json"
+	EnemiesFileName   = "enemies.json"
+	ClassesFileName   = "classes.json"
+)
+
+// Data is a complete set of definitions: the embedded data with any
+// overrides merged on top.
+type Data struct {
+	Abilities []AbilityDef
+	Enemies   []EnemyDef
+	Classes   []ClassDef
+
+	// Difficulties are always the embedded ones; there is no override file.
+	Difficulties []DifficultyDef
+
+	// Warnings lists what migrating old-format override files assumed.
+	Warnings []string
+
+	sources map[string]string // "file/id" -> path the definition came from
+}
+
+// LoadData loads the embedded definitions and merges overrides from dir, if
+// dir is non-empty. Override files use the same layout as the embedded ones
+// and are each optional. Entries replace embedded entries with the same ID;
+// new IDs are appended.
+func LoadData(dir string) (*Data, error) {
+	d := &Data{sources: make(map[string]string)}
+
+	abilities, err := LoadAbilities()
+	if err != nil {
+		return nil, err
+	}
+	enemies, err := LoadEnemies()
+	if err != nil {
+		return nil, err
+	}
+	classes, err := LoadClasses()
+	if err != nil {
+		return nil, err
+	}
+	difficulties, err := LoadDifficulties()
+	if err != nil {
+		return nil, err
+	}
+	d.Abilities = mergeByID(d, AbilitiesFileName, AbilitiesFileName, nil, abilities, abilityID)
+	d.Enemies = mergeByID(d, EnemiesFileName, EnemiesFileName, nil, enemies, enemyID)
+	d.Classes = mergeByID(d, ClassesFileName, ClassesFileName, nil, classes, classID)
+	d.Difficulties = difficulties
+
+	if dir == "" {
+		return d, nil
+	}
+
+	var abilityFile AbilitiesFile
+	path, err := d.readOverride(dir, AbilitiesFileName, &abilityFile)
+	if err != nil {
+		return nil, err
+	}
+	d.Abilities = mergeByID(d, AbilitiesFileName, path, d.Abilities, abilityFile.Abilities, abilityID)
+
+	var enemyFile EnemiesFile
+	path, err = d.readOverride(dir, EnemiesFileName, &enemyFile)
+	if err != nil {
+		return nil, err
+	}
+	d.Enemies = mergeByID(d, EnemiesFileName, path, d.Enemies, enemyFile.Enemies, enemyID)
+
+	var classFile ClassesFile
+	path, err = d.readOverride(dir, ClassesFileName, &classFile)
+	if err != nil {
+		return nil, err
+	}
+	d.Classes = mergeByID(d, ClassesFileName, path, d.Classes, classFile.Classes, classID)
+
+	return d, nil
+}
+
+// Source returns the file a definition was loaded from.
+func (d *Data) Source(file, id string) string {
+	if src, ok := d.sources[file+"/"+id]; ok {
+		return src
+	}
+	return file
+}
+
+// readOverride unmarshals dir/name into v, migrating it from an older
+// schema version if needed. A missing file is not an error. Returns the
+// path read, or "" if the file doesn't exist.
+func (d *Data) readOverride(dir, name string, v any) (string, error) {
+	path := filepath.Join(dir, name)
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read override %s: %w", path, err)
+	}
+	content, warnings, err := migrate(name, content)
+	if err != nil {
+		return "", fmt.Errorf("failed to load %s: %w", path, err)
+	}
+	for _, w := range warnings {
+		d.Warnings = append(d.Warnings, path+": "+w)
+	}
+	if err := json.Unmarshal(content, v); err != nil {
+		return "", fmt.Errorf("failed to parse JSON from %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// mergeByID overlays entries onto base: an entry replaces the base entry
+// with its ID in place, other entries are appended. Only the layers merge:
+// a second entry with the same ID in one file is appended too, so
+// ValidateAll reports it as a duplicate. Records path as the source of
+// each merged entry.
+func mergeByID[T any](d *Data, file, path string, base, entries []T, id func(*T) string) []T {
+	merged := append([]T(nil), base...)
+	index := make(map[string]int, len(merged))
+	for i := range merged {
+		index[id(&merged[i])] = i
+	}
+
+	for i := range entries {
+		key := id(&entries[i])
+		if j, ok := index[key]; ok {
+			merged[j] = entries[i]
+			delete(index, key)
+		} else {
+			merged = append(merged, entries[i])
+		}
+		d.sources[file+"/"+key] = path
+	}
+	return merged
+}
+
+func abilityID(a *AbilityDef) string { return a.ID }
+func enemyID(e *EnemyDef) string     { return e.ID }
+func classID(c *ClassDef) string     { return c.ID }
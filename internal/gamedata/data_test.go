@@ -0,0 +1,320 @@
+package gamedata
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeOverride writes a data override file into dir.
+func writeOverride(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+}
+
+func TestEmbeddedDataIsValid(t *testing.T) {
+	d, err := LoadData("")
+	if err != nil {
+		t.Fatalf("LoadData: %v", err)
+	}
+	for _, issue := range ValidateAll(d) {
+		t.Errorf("unexpected issue: %s", issue)
+	}
+}
+
+func TestLoadDataMergesOverrides(t *testing.T) {
+	dir := t.TempDir()
+	writeOverride(t, dir, AbilitiesFileName, `{"abilities": [
+		{"id": "fireball", "name": "Big Fireball", "effectType": "damage", "targetType": "single_enemy", "basePower": 30, "mpCost": 9},
+		{"id": "frostbolt", "name": "Frostbolt", "effectType": "damage", "targetType": "single_enemy", "basePower": 8, "mpCost": 4}
+	]}`)
+
+	embedded, _ := LoadData("")
+	d, err := LoadData(dir)
+	if err != nil {
+		t.Fatalf("LoadData: %v", err)
+	}
+
+	if len(d.Abilities) != len(embedded.Abilities)+1 {
+		t.Errorf("got %d abilities, want %d (one replaced, one added)", len(d.Abilities), len(embedded.Abilities)+1)
+	}
+	registry := NewAbilityRegistry(d.Abilities)
+	if fb := registry.GetByID("fireball"); fb == nil || fb.Name != "Big Fireball" || fb.BasePower != 30 {
+		t.Errorf("fireball not replaced by override: %+v", fb)
+	}
+	if registry.GetByID("frostbolt") == nil {
+		t.Error("new override ability not appended")
+	}
+	if registry.GetByID("heal") == nil {
+		t.Error("untouched embedded ability lost")
+	}
+	// Files without an override are left alone
+	if len(d.Enemies) != len(embedded.Enemies) || len(d.Classes) != len(embedded.Classes) {
+		t.Error("enemies/classes changed without override files")
+	}
+
+	if got := d.Source(AbilitiesFileName, "fireball"); got != filepath.Join(dir, AbilitiesFileName) {
+		t.Errorf("fireball source = %q, want the override file", got)
+	}
+	if got := d.Source(AbilitiesFileName, "heal"); got != AbilitiesFileName {
+		t.Errorf("heal source = %q, want %q", got, AbilitiesFileName)
+	}
+}
+
+func TestLoadDataRejectsMalformedOverride(t *testing.T) {
+	dir := t.TempDir()
+	writeOverride(t, dir, EnemiesFileName, `{"enemies": [`)
+
+	if _, err := LoadData(dir); err == nil || !strings.Contains(err.Error(), EnemiesFileName) {
+		t.Errorf("LoadData error = %v, want a parse error naming %s", err, EnemiesFileName)
+	}
+}
+
+func TestValidateAllReportsFileAndID(t *testing.T) {
+	dir := t.TempDir()
+	writeOverride(t, dir, EnemiesFileName, `{"enemies": [
+		{"id": "slime", "name": "Slime", "glyph": "s", "color": "#00FF00", "hp": 0, "spawnWeight": 5, "abilities": ["ooze"]}
+	]}`)
+	writeOverride(t, dir, ClassesFileName, `{"classes": [
+		{"id": "warrior", "name": "Warrior", "symbol": "W", "hp": 30, "abilities": ["attack", "fireball", "smite"]}
+	]}`)
+
+	d, err := LoadData(dir)
+	if err != nil {
+		t.Fatalf("LoadData: %v", err)
+	}
+	issues := ValidateAll(d)
+
+	want := []string{
+		filepath.Join(dir, EnemiesFileName) + `: slime: hp must be positive`,
+		filepath.Join(dir, EnemiesFileName) + `: slime: unknown ability "ooze"`,
+		filepath.Join(dir, ClassesFileName) + `: warrior: unknown ability "smite"`,
+	}
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.String())
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestValidateAllChecksDifficulties(t *testing.T) {
+	d, err := LoadData("")
+	if err != nil {
+		t.Fatalf("LoadData: %v", err)
+	}
+	d.Difficulties = append(d.Difficulties, DifficultyDef{ID: "cursed", Name: "Cursed", Start: &StartDef{
+		Items:    []ItemGrant{{Item: "escape_rope"}},
+		Statuses: []StartStatus{{Status: "sleepy", Duration: 3}},
+	}})
+
+	want := []string{
+		`difficulty.json: cursed: escape_rope count must be positive`,
+		`difficulty.json: cursed: unknown status "sleepy"`,
+	}
+	var got []string
+	for _, issue := range ValidateAll(d) {
+		got = append(got, issue.String())
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestValidateAllRejectsDuplicateIDsInOneFile(t *testing.T) {
+	dir := t.TempDir()
+	writeOverride(t, dir, AbilitiesFileName, `{"abilities": [
+		{"id": "fireball", "name": "Big Fireball", "effectType": "damage", "targetType": "single_enemy", "basePower": 30, "mpCost": 9},
+		{"id": "fireball", "name": "Bigger Fireball", "effectType": "damage", "targetType": "single_enemy", "basePower": 40, "mpCost": 9}
+	]}`)
+
+	d, err := LoadData(dir)
+	if err != nil {
+		t.Fatalf("LoadData: %v", err)
+	}
+	issues := ValidateAll(d)
+
+	want := filepath.Join(dir, AbilitiesFileName) + `: fireball: duplicate id`
+	if len(issues) != 1 || issues[0].String() != want {
+		t.Errorf("issues = %v, want [%s]", issues, want)
+	}
+}
+
+func TestValidateAllDuplicateAndEnumChecks(t *testing.T) {
+	d := &Data{
+		Abilities: []AbilityDef{
+			{ID: "zap", Name: "Zap", EffectType: "explode", TargetType: TargetSelf},
+			{ID: "zap", Name: "Zap Again", EffectType: EffectDamage, TargetType: "everyone"},
+		},
+	}
+
+	issues := ValidateAll(d)
+
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.String())
+	}
+	want := []string{
+		`abilities.json: zap: unknown effectType "explode"`,
+		`abilities.json: zap: duplicate id`,
+		`abilities.json: zap: unknown targetType "everyone"`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestValidateAllStatusAbilities(t *testing.T) {
+	d := &Data{
+		Abilities: []AbilityDef{
+			{ID: "brace", Name: "Brace", EffectType: EffectBuff, TargetType: TargetSelf, StatusEffect: StatusDefenseUp},
+			{ID: "glare", Name: "Glare", EffectType: EffectDebuff, TargetType: TargetSingleEnemy, StatusDuration: 2},
+		},
+	}
+
+	issues := ValidateAll(d)
+
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.String())
+	}
+	want := []string{
+		`abilities.json: brace: statusDuration must be positive when statusEffect is set`,
+		`abilities.json: glare: statusEffect is required for debuff abilities`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestValidateAllRejectsNegativeScales(t *testing.T) {
+	negative := -0.5
+	d := &Data{
+		Abilities: []AbilityDef{
+			{ID: "drain", Name: "Drain", EffectType: EffectDamage, TargetType: TargetSingleEnemy, MagicScale: &negative},
+			{ID: "wane", Name: "Wane", EffectType: EffectHeal, TargetType: TargetSelf, LevelScale: -1},
+		},
+	}
+
+	var got []string
+	for _, issue := range ValidateAll(d) {
+		got = append(got, issue.String())
+	}
+	want := []string{
+		`abilities.json: drain: attackScale, magicScale and levelScale must not be negative`,
+		`abilities.json: wane: attackScale, magicScale and levelScale must not be negative`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestValidateAllHPScaling(t *testing.T) {
+	d := &Data{
+		Abilities: []AbilityDef{
+			{ID: "execute", Name: "Execute", EffectType: EffectDamage, TargetType: TargetSingleEnemy,
+				HPScaling: &HPScaling{Of: HPOfTarget, Power: 10, Threshold: 0.3}},
+			{ID: "mend", Name: "Mend", EffectType: EffectHeal, TargetType: TargetSelf,
+				HPScaling: &HPScaling{Of: HPOfUser, Power: 5}},
+			{ID: "rage", Name: "Rage", EffectType: EffectDamage, TargetType: TargetSingleEnemy,
+				HPScaling: &HPScaling{Of: "ally", Power: -1}},
+		},
+	}
+
+	var got []string
+	for _, issue := range ValidateAll(d) {
+		got = append(got, issue.String())
+	}
+	want := []string{
+		`abilities.json: mend: hpScaling only applies to damage abilities`,
+		`abilities.json: rage: hpScaling.of must be "user" or "target", got "ally"`,
+		`abilities.json: rage: hpScaling.power must not be negative and threshold must be between 0 and 1`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestValidateAllOnDeathEffects(t *testing.T) {
+	d := &Data{
+		Abilities: []AbilityDef{{ID: "attack", Name: "Attack", EffectType: EffectDamage, TargetType: TargetSingleEnemy}},
+		Enemies: []EnemyDef{
+			{ID: "slime", Name: "Slime", Glyph: "s", Color: "#00FF00", HP: 5, Abilities: []string{"attack"},
+				OnDeath: []DeathEffect{
+					{Type: DeathSplit, Enemy: "slimeling", Count: 2},
+					{Type: DeathSplit, Enemy: "ooze", Count: 2},
+					{Type: DeathCurse, Status: StatusRegen, Duration: 2},
+					{Type: "vanish"},
+				}},
+			{ID: "slimeling", Name: "Slimeling", Glyph: "s", Color: "#00FF00", HP: 2, Abilities: []string{"attack"}},
+		},
+	}
+
+	issues := ValidateAll(d)
+
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.String())
+	}
+	want := []string{
+		`enemies.json: slime: split references unknown enemy "ooze"`,
+		`enemies.json: slime: curse status "regen" must be a debuff`,
+		`enemies.json: slime: unknown onDeath type "vanish"`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestValidateAllAbilityPool(t *testing.T) {
+	d := &Data{
+		Abilities: []AbilityDef{
+			{ID: "attack", Name: "Attack", EffectType: EffectDamage, TargetType: TargetSingleEnemy},
+			{ID: "claw", Name: "Claw", EffectType: EffectDamage, TargetType: TargetSingleEnemy},
+		},
+		Enemies: []EnemyDef{
+			{ID: "orc", Name: "Orc", Glyph: "o", Color: "#808080", HP: 5, Abilities: []string{"attack"},
+				AbilityPool: &AbilityPool{Abilities: []string{"claw", "attack", "gore"}, Count: 4}},
+		},
+	}
+
+	var got []string
+	for _, issue := range ValidateAll(d) {
+		got = append(got, issue.String())
+	}
+	want := []string{
+		`enemies.json: orc: unknown ability "gore"`,
+		`enemies.json: orc: abilityPool.count must be between 1 and the pool size`,
+		`enemies.json: orc: abilityPool repeats fixed ability "attack"`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestValidateAllUndying(t *testing.T) {
+	d := &Data{
+		Abilities: []AbilityDef{{ID: "attack", Name: "Attack", EffectType: EffectDamage, TargetType: TargetSingleEnemy}},
+		Enemies: []EnemyDef{
+			{ID: "lich", Name: "Lich", Glyph: "L", Color: "#FFFFFF", HP: 5, Abilities: []string{"attack"},
+				Undying: &Undying{Chance: 0, HPPercent: 150, Permakill: []string{"magical", "attack", "holy"}}},
+		},
+	}
+
+	var got []string
+	for _, issue := range ValidateAll(d) {
+		got = append(got, issue.String())
+	}
+	want := []string{
+		`enemies.json: lich: undying.chance must be between 1 and 100`,
+		`enemies.json: lich: undying.hpPercent must be between 1 and 100`,
+		`enemies.json: lich: undying.permakill "holy" is neither an ability nor a damage type`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
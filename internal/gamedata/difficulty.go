@@ -0,0 +1,112 @@
+package gamedata
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// DifficultiesFileName is the embedded list of difficulty settings.
+const DifficultiesFileName = "difficulty.json"
+
+// DefaultDifficulty is the difficulty a run uses unless told otherwise.
+const DefaultDifficulty = "normal"
+
+// DifficultiesFile represents the structure of difficulty.json.
+type DifficultiesFile struct {
+	Difficulties []DifficultyDef `json:"difficulties"`
+}
+
+// DifficultyDef is one difficulty setting.
+type DifficultyDef struct {
+	ID    string    `json:"id"`
+	Name  string    `json:"name"`
+	Start *StartDef `json:"start,omitempty"` // nil keeps the default starting party
+
+	// DamageFormula picks how combat damage is worked out ("" is additive).
+	DamageFormula DamageFormula `json:"damageFormula,omitempty"`
+}
+
+// StartDef is how a party sets out on a difficulty. Its items replace the
+// default starting inventory, so an empty list starts with nothing.
+//
+//	{"items": [{"item": "escape_rope", "count": 3}],
+//	 "statuses": [{"name": "Fatigued", "status": "attack_down", "duration": 7}]}
+type StartDef struct {
+	Items    []ItemGrant   `json:"items"`
+	Statuses []StartStatus `json:"statuses,omitempty"`
+}
+
+// ItemGrant gives the party some of an item.
+type ItemGrant struct {
+	Item  string `json:"item"`
+	Count int    `json:"count"`
+}
+
+// StartStatus is a status effect every member starts the run with.
+type StartStatus struct {
+	Name     string           `json:"name,omitempty"` // Shown when the run starts (default: the status)
+	Status   StatusEffectType `json:"status"`
+	Duration int              `json:"duration"`        // In turns; exploring ticks one turn every few steps
+	Power    int              `json:"power,omitempty"` // For poison and regen
+}
+
+// Label returns the name shown for the status when the run starts.
+func (s StartStatus) Label() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return string(s.Status)
+}
+
+// LoadDifficulties loads the embedded difficulty settings.
+func LoadDifficulties() ([]DifficultyDef, error) {
+	file, err := Load[DifficultiesFile](DifficultiesFileName)
+	if err != nil {
+		return nil, err
+	}
+	return file.Difficulties, nil
+}
+
+// Validate checks that the difficulty has an ID and a name, a known damage
+// formula, grants only the listed items in positive counts, and starts with
+// known, lasting statuses. Returns the first problem found.
+func (d *DifficultyDef) Validate(items []string) error {
+	if d.ID == "" || d.Name == "" {
+		return errors.New("difficulty needs an id and a name")
+	}
+	var err error
+	d.validateStart(items, func(format string, args ...any) {
+		if err == nil {
+			err = fmt.Errorf("%s: "+format, append([]any{d.ID}, args...)...)
+		}
+	})
+	return err
+}
+
+// validateStart reports problems with the difficulty's damage formula and
+// start section. A nil items list accepts any item that is named.
+func (d *DifficultyDef) validateStart(items []string, report func(format string, args ...any)) {
+	if !knownFormula(d.DamageFormula) {
+		report("unknown damage formula %q", d.DamageFormula)
+	}
+	if d.Start == nil {
+		return
+	}
+	for _, grant := range d.Start.Items {
+		if grant.Item == "" || items != nil && !slices.Contains(items, grant.Item) {
+			report("unknown item %q", grant.Item)
+		}
+		if grant.Count <= 0 {
+			report("%s count must be positive", grant.Item)
+		}
+	}
+	for _, s := range d.Start.Statuses {
+		if s.Status == StatusNone || !knownStatus(s.Status) {
+			report("unknown status %q", s.Status)
+		}
+		if s.Duration <= 0 {
+			report("%s duration must be positive", s.Status)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package gamedata
+
+import "testing"
+
+func TestLoadDifficulties(t *testing.T) {
+	defs, err := LoadDifficulties()
+	if err != nil {
+		t.Fatalf("LoadDifficulties: %v", err)
+	}
+	ids := make(map[string]bool)
+	for i := range defs {
+		if err := defs[i].Validate([]string{"escape_rope"}); err != nil {
+			t.Errorf("embedded difficulty invalid: %v", err)
+		}
+		ids[defs[i].ID] = true
+	}
+	for _, id := range []string{"easy", DefaultDifficulty, "hard", "nightmare"} {
+		if !ids[id] {
+			t.Errorf("missing difficulty %q", id)
+		}
+	}
+}
+
+func TestDifficultyValidate(t *testing.T) {
+	items := []string{"escape_rope"}
+	tests := map[string]DifficultyDef{
+		"no name":       {ID: "x"},
+		"unknown item":  {ID: "x", Name: "X", Start: &StartDef{Items: []ItemGrant{{Item: "potion", Count: 1}}}},
+		"zero count":    {ID: "x", Name: "X", Start: &StartDef{Items: []ItemGrant{{Item: "escape_rope"}}}},
+		"unknown state": {ID: "x", Name: "X", Start: &StartDef{Statuses: []StartStatus{{Status: "sleepy", Duration: 3}}}},
+		"no duration":   {ID: "x", Name: "X", Start: &StartDef{Statuses: []StartStatus{{Status: StatusAttackDown}}}},
+		"bad formula":   {ID: "x", Name: "X", DamageFormula: "exponential"},
+	}
+	for name, def := range tests {
+		if err := def.Validate(items); err == nil {
+			t.Errorf("%s: Validate() = nil, want an error", name)
+		}
+	}
+
+	ok := DifficultyDef{ID: "x", Name: "X", Start: &StartDef{
+		Items:    []ItemGrant{{Item: "escape_rope", Count: 2}},
+		Statuses: []StartStatus{{Status: StatusRegen, Duration: 3, Power: 1}},
+	}}
+	if err := ok.Validate(items); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+}
@@ -1,18 +1,143 @@
 package gamedata
 
-import "github.com/gdamore/tcell/v2"
+import (
+	"math/rand"
+	"slices"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Row is a combat formation row.
+type Row string
+
+const (
+	RowFront Row = "front"
+	RowBack  Row = "back"
+)
 
 // EnemyDef defines an enemy type loaded from JSON.
 type EnemyDef struct {
-	ID          string   `json:"id"`          // Unique identifier (e.g., "goblin")
-	Name        string   `json:"name"`        // Display name (e.g., "Goblin")
-	Glyph       string   `json:"glyph"`       // Single character for rendering (e.g., "g")
-	Color       string   `json:"color"`       // Hex color code (e.g., "#00FF00")
-	HP          int      `json:"hp"`          // Base hit points
-	Attack      int      `json:"attack"`      // Base attack power
-	Defense     int      `json:"defense"`     // Base defense value
-	SpawnWeight int      `json:"spawnWeight"` // Relative spawn frequency (higher = more common)
-	Abilities   []string `json:"abilities"`   // List of ability IDs this enemy can use
+	ID          string        `json:"id"`                    // Unique identifier (e.g., "goblin")
+	Name        string        `json:"name"`                  // Display name (e.g., "Goblin")
+	Glyph       string        `json:"glyph"`                 // Single character for rendering (e.g., "g")
+	Color       string        `json:"color"`                 // Hex color code (e.g., "#00FF00")
+	HP          int           `json:"hp"`                    // Base hit points
+	Attack      int           `json:"attack"`                // Base attack power
+	Defense     int           `json:"defense"`               // Base defense value
+	Resist      int           `json:"resist,omitempty"`      // Magic defense (default 0)
+	SpawnWeight int           `json:"spawnWeight"`           // Relative spawn frequency (higher = more common)
+	Abilities   []string      `json:"abilities"`             // List of ability IDs this enemy can use
+	AbilityPool *AbilityPool  `json:"abilityPool,omitempty"` // Extra abilities rolled per spawn
+	Boss        bool          `json:"boss"`                  // True for boss enemies (tracked separately in telemetry)
+	Row         Row           `json:"row"`                   // Formation row in combat ("front" or "back", default front)
+	OnDeath     []DeathEffect `json:"onDeath,omitempty"`     // Effects triggered when the enemy is killed
+	Loot        []LootDrop    `json:"loot,omitempty"`        // Rolled when the party searches the corpse
+	Undying     *Undying      `json:"undying,omitempty"`     // Rises again after being killed in combat
+}
+
+// Undying lets an enemy rise again during the fight it was killed in,
+// unless the killing blow came from something on its permakill list.
+//
+//	{"chance": 100, "hpPercent": 50, "permakill": ["magical", "fireball"]}
+type Undying struct {
+	Chance    int      `json:"chance"`              // Percent chance to rise after each death (100 = always)
+	HPPercent int      `json:"hpPercent"`           // Share of MaxHP it rises with
+	Rises     int      `json:"rises,omitempty"`     // Times it can rise per fight (default 1)
+	Delay     int      `json:"delay,omitempty"`     // Rounds it stays down before rising (default 1)
+	Permakill []string `json:"permakill,omitempty"` // Damage types or ability IDs that keep it down
+}
+
+// MaxRises returns how many times the enemy can rise in one fight.
+func (u *Undying) MaxRises() int {
+	return max(u.Rises, 1)
+}
+
+// RoundsDown returns how many rounds the enemy stays down before rising.
+func (u *Undying) RoundsDown() int {
+	return max(u.Delay, 1)
+}
+
+// KeptDownBy reports whether a killing blow from the ability keeps the
+// enemy dead. ability is nil for deaths from status ticks, which never do.
+func (u *Undying) KeptDownBy(ability *AbilityDef) bool {
+	if ability == nil {
+		return false
+	}
+	return slices.Contains(u.Permakill, ability.ID) ||
+		ability.DamageType != "" && slices.Contains(u.Permakill, string(ability.DamageType))
+}
+
+// AbilityPool is a set of abilities an enemy type may know. Each spawned
+// instance learns Count of them on top of the enemy's fixed abilities.
+//
+//	{"abilities": ["defend", "claw"], "count": 1}
+type AbilityPool struct {
+	Abilities []string `json:"abilities"` // Ability IDs to pick from
+	Count     int      `json:"count"`     // How many each instance learns
+}
+
+// RollAbilities returns the abilities for one spawned instance: the fixed
+// abilities plus a subset of the pool picked with rng. Without a pool it
+// returns the fixed abilities and draws nothing from rng.
+func (e *EnemyDef) RollAbilities(rng *rand.Rand) []string {
+	if e.AbilityPool == nil || e.AbilityPool.Count <= 0 {
+		return e.Abilities
+	}
+	pool := e.AbilityPool.Abilities
+	ids := append([]string(nil), e.Abilities...)
+	for _, i := range rng.Perm(len(pool))[:min(e.AbilityPool.Count, len(pool))] {
+		ids = append(ids, pool[i])
+	}
+	return ids
+}
+
+// LootDrop is one entry of an enemy's loot table. Each entry is rolled
+// on its own.
+//
+//	{"item": "escape_rope", "chance": 0.1}
+type LootDrop struct {
+	Item   string  `json:"item"`            // Item ID
+	Chance float64 `json:"chance"`          // Probability of dropping, 0 to 1
+	Count  int     `json:"count,omitempty"` // How many drop (default 1)
+}
+
+// Amount returns how many of the item the drop gives.
+func (l LootDrop) Amount() int {
+	if l.Count <= 0 {
+		return 1
+	}
+	return l.Count
+}
+
+// DeathEffectType is what happens when an enemy dies.
+type DeathEffectType string
+
+const (
+	DeathExplode DeathEffectType = "explode" // True damage to every party member
+	DeathSplit   DeathEffectType = "split"   // Spawn copies of another enemy at half HP
+	DeathCurse   DeathEffectType = "curse"   // Apply a debuff to the killer
+)
+
+// DeathEffect is one on-death effect of an enemy.
+//
+//	{"type": "explode", "power": 4}
+//	{"type": "split", "enemy": "slimeling", "count": 2}
+//	{"type": "curse", "status": "attack_down", "duration": 3}
+type DeathEffect struct {
+	Type     DeathEffectType  `json:"type"`
+	Power    int              `json:"power,omitempty"`    // Explode damage, or curse status power
+	Enemy    string           `json:"enemy,omitempty"`    // Split: ID of the enemy to spawn
+	Count    int              `json:"count,omitempty"`    // Split: number of copies
+	Status   StatusEffectType `json:"status,omitempty"`   // Curse: debuff applied to the killer
+	Duration int              `json:"duration,omitempty"` // Curse: status duration in turns
+}
+
+// StartingRow returns the row the enemy takes at the start of combat.
+func (e *EnemyDef) StartingRow() Row {
+	if e.Row == RowBack {
+		return RowBack
+	}
+	return RowFront
 }
 
 // GlyphRune returns the glyph as a rune for rendering.
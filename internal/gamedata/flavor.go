@@ -0,0 +1,94 @@
+package gamedata
+
+import (
+	"errors"
+	"fmt"
+)
+
+// FlavorFileName is the embedded table of ambient messages and flavor events.
+const FlavorFileName = "flavor.json"
+
+// FlavorFile represents the structure of flavor.json.
+type FlavorFile struct {
+	Ambient []AmbientFlavor `json:"ambient"`
+	Events  []FlavorEvent   `json:"events"`
+}
+
+// AmbientFlavor is a message that may drift into the log while the party
+// walks a floor between MinFloor and MaxFloor. Heavier messages come up
+// more often.
+type AmbientFlavor struct {
+	Text     string `json:"text"`
+	Weight   int    `json:"weight"`
+	MinFloor int    `json:"minFloor,omitempty"` // 0 means from the first floor
+	MaxFloor int    `json:"maxFloor,omitempty"` // 0 means no deepest floor
+}
+
+// FlavorEvent is a rare description that may fire when the party first
+// enters a room, at most once per run.
+type FlavorEvent struct {
+	ID        string `json:"id"`
+	Text      string `json:"text"`
+	Chance    int    `json:"chance"` // Percent per room entered
+	MinFloor  int    `json:"minFloor,omitempty"`
+	MaxFloor  int    `json:"maxFloor,omitempty"`
+	BossFloor bool   `json:"bossFloor,omitempty"` // Only on floors with a living boss, outside its room
+}
+
+// LoadFlavor loads the embedded flavor table.
+func LoadFlavor() (*FlavorFile, error) {
+	file, err := Load[FlavorFile](FlavorFileName)
+	if err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// OnFloor reports whether the message belongs on the floor.
+func (a AmbientFlavor) OnFloor(floor int) bool {
+	return inFloors(floor, a.MinFloor, a.MaxFloor)
+}
+
+// OnFloor reports whether the event can fire on the floor.
+func (e FlavorEvent) OnFloor(floor int) bool {
+	return inFloors(floor, e.MinFloor, e.MaxFloor)
+}
+
+// inFloors reports whether floor is within [minFloor, maxFloor], where 0
+// leaves that end open.
+func inFloors(floor, minFloor, maxFloor int) bool {
+	return floor >= minFloor && (maxFloor == 0 || floor <= maxFloor)
+}
+
+// Validate checks that every message has text and a positive weight, and
+// that events have unique IDs and a chance between 1 and 100.
+func (f *FlavorFile) Validate() error {
+	for _, a := range f.Ambient {
+		if a.Text == "" {
+			return errors.New("ambient message needs text")
+		}
+		if a.Weight <= 0 {
+			return fmt.Errorf("ambient %q: weight must be positive", a.Text)
+		}
+		if a.MaxFloor != 0 && a.MaxFloor < a.MinFloor {
+			return fmt.Errorf("ambient %q: maxFloor is below minFloor", a.Text)
+		}
+	}
+	seen := make(map[string]bool)
+	for _, e := range f.Events {
+		if e.ID == "" || e.Text == "" {
+			return errors.New("flavor event needs an id and text")
+		}
+		if seen[e.ID] {
+			return fmt.Errorf("duplicate flavor event %q", e.ID)
+		}
+		seen[e.ID] = true
+		if e.Chance < 1 || e.Chance > 100 {
+			return fmt.Errorf("%s: chance must be between 1 and 100", e.ID)
+		}
+		if e.MaxFloor != 0 && e.MaxFloor < e.MinFloor {
+			return fmt.Errorf("%s: maxFloor is below minFloor", e.ID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package gamedata
+
+import "testing"
+
+func TestLoadFlavor(t *testing.T) {
+	flavor, err := LoadFlavor()
+	if err != nil {
+		t.Fatalf("LoadFlavor: %v", err)
+	}
+	if err := flavor.Validate(); err != nil {
+		t.Errorf("embedded flavor invalid: %v", err)
+	}
+	for floor := 1; floor <= 6; floor++ {
+		found := false
+		for _, a := range flavor.Ambient {
+			found = found || a.OnFloor(floor)
+		}
+		if !found {
+			t.Errorf("no ambient messages on floor %d", floor)
+		}
+	}
+}
+
+func TestFlavorValidate(t *testing.T) {
+	tests := map[string]FlavorFile{
+		"no text":        {Ambient: []AmbientFlavor{{Weight: 1}}},
+		"zero weight":    {Ambient: []AmbientFlavor{{Text: "Drip."}}},
+		"floors flipped": {Ambient: []AmbientFlavor{{Text: "Drip.", Weight: 1, MinFloor: 4, MaxFloor: 2}}},
+		"no chance":      {Events: []FlavorEvent{{ID: "x", Text: "X."}}},
+		"duplicate": {Events: []FlavorEvent{
+			{ID: "x", Text: "X.", Chance: 5},
+			{ID: "x", Text: "Y.", Chance: 5},
+		}},
+	}
+	for name, f := range tests {
+		if err := f.Validate(); err == nil {
+			t.Errorf("%s: Validate() = nil, want an error", name)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package gamedata
 
 import (
 	"math/rand"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -11,12 +13,12 @@ func TestLoadEnemies(t *testing.T) {
 		t.Fatalf("Failed to load enemies: %v", err)
 	}
 
-	if len(enemies) != 3 {
-		t.Errorf("Expected 3 enemies, got %d", len(enemies))
+	if len(enemies) != 6 {
+		t.Errorf("Expected 6 enemies, got %d", len(enemies))
 	}
 
 	// Verify expected enemies exist
-	expectedIDs := map[string]bool{"goblin": false, "orc": false, "skeleton": false}
+	expectedIDs := map[string]bool{"goblin": false, "orc": false, "skeleton": false, "cultist": false, "training_dummy": false}
 	for _, e := range enemies {
 		if _, ok := expectedIDs[e.ID]; ok {
 			expectedIDs[e.ID] = true
@@ -36,8 +38,8 @@ func TestEnemyRegistry(t *testing.T) {
 		t.Fatalf("Failed to load registry: %v", err)
 	}
 
-	if registry.Count() != 3 {
-		t.Errorf("Expected 3 enemy types, got %d", registry.Count())
+	if registry.Count() != 6 {
+		t.Errorf("Expected 6 enemy types, got %d", registry.Count())
 	}
 
 	// Test GetByID
@@ -64,6 +66,9 @@ func TestEnemyRegistry(t *testing.T) {
 		if spawns1[i] != spawns2[i] {
 			t.Errorf("Spawn %d mismatch: %s != %s", i, spawns1[i], spawns2[i])
 		}
+		if spawns1[i] == TutorialDummyID {
+			t.Errorf("Spawn %d is the zero-weight training dummy", i)
+		}
 	}
 }
 
@@ -165,6 +170,50 @@ func TestEnemyAbilities(t *testing.T) {
 	}
 }
 
+func TestRollAbilities(t *testing.T) {
+	orc := *MustLoadEnemyRegistry().GetByID("orc")
+	orc.Abilities = []string{"attack"}
+	orc.AbilityPool = &AbilityPool{Abilities: []string{"power_attack", "defend", "claw"}, Count: 2}
+
+	roll := func(seed int64) [][]string {
+		rng := rand.New(rand.NewSource(seed))
+		var kits [][]string
+		for range 20 {
+			kits = append(kits, orc.RollAbilities(rng))
+		}
+		return kits
+	}
+	first, second := roll(7), roll(7)
+	kits := make(map[string]bool)
+	for i, kit := range first {
+		if strings.Join(kit, ",") != strings.Join(second[i], ",") {
+			t.Errorf("instance %d rolled %v then %v with the same seed", i, kit, second[i])
+		}
+		if len(kit) != len(orc.Abilities)+orc.AbilityPool.Count || kit[0] != "attack" {
+			t.Errorf("instance %d kit = %v, want attack plus %d from the pool", i, kit, orc.AbilityPool.Count)
+		}
+		for _, id := range kit[1:] {
+			if !slices.Contains(orc.AbilityPool.Abilities, id) {
+				t.Errorf("instance %d rolled %q, which isn't in the pool", i, id)
+			}
+		}
+		kits[strings.Join(kit, ",")] = true
+	}
+	if len(kits) < 2 {
+		t.Error("every orc rolled the same kit")
+	}
+
+	// Without a pool nothing is drawn
+	rng := rand.New(rand.NewSource(7))
+	goblin := MustLoadEnemyRegistry().GetByID("goblin")
+	if kit := goblin.RollAbilities(rng); strings.Join(kit, ",") != strings.Join(goblin.Abilities, ",") {
+		t.Errorf("goblin kit = %v, want %v", kit, goblin.Abilities)
+	}
+	if rng.Int63() != rand.New(rand.NewSource(7)).Int63() {
+		t.Error("rolling a poolless enemy advanced the RNG")
+	}
+}
+
 func TestLoadAbilities(t *testing.T) {
 	abilities, err := LoadAbilities()
 	if err != nil {
@@ -339,3 +388,91 @@ func TestClassRegistry(t *testing.T) {
 		t.Error("Cleric should have 'group_heal' ability")
 	}
 }
+
+func TestEnemyRowsAndRangedAbilities(t *testing.T) {
+	enemies := MustLoadEnemyRegistry()
+	abilities := MustLoadAbilityRegistry()
+
+	if got := enemies.GetByID("skeleton").StartingRow(); got != RowBack {
+		t.Errorf("Skeleton starting row = %q, want back", got)
+	}
+	if got := enemies.GetByID("goblin").StartingRow(); got != RowFront {
+		t.Errorf("Goblin starting row = %q, want front (default)", got)
+	}
+
+	if !abilities.GetByID("bone_throw").ReachesBackRow() {
+		t.Error("bone_throw should be ranged")
+	}
+	if !abilities.GetByID("fireball").ReachesBackRow() {
+		t.Error("fireball (magical) should reach the back row")
+	}
+	if abilities.GetByID("attack").ReachesBackRow() {
+		t.Error("attack (melee) should not reach the back row")
+	}
+}
+
+func TestUsableOutOfCombat(t *testing.T) {
+	abilities := MustLoadAbilityRegistry()
+	tests := map[string]bool{
+		"heal":       true,
+		"group_heal": true,
+		"cleanse":    true,
+		"attack":     false,
+		"fireball":   false,
+		"hex":        false,
+		"defend":     true,
+		"taunt":      false,
+	}
+	for id, want := range tests {
+		if got := abilities.GetByID(id).UsableOutOfCombat(); got != want {
+			t.Errorf("%s.UsableOutOfCombat() = %v, want %v", id, got, want)
+		}
+	}
+
+	regen := &AbilityDef{EffectType: EffectBuff, TargetType: TargetSingleAlly, StatusEffect: StatusRegen}
+	if !regen.UsableOutOfCombat() {
+		t.Error("regen buffs should be usable out of combat by default")
+	}
+	no := false
+	override := &AbilityDef{EffectType: EffectHeal, TargetType: TargetSingleAlly, OutOfCombat: &no}
+	if override.UsableOutOfCombat() {
+		t.Error("usableOutOfCombat: false should override the heal default")
+	}
+	combatOnly := &AbilityDef{EffectType: EffectBuff, TargetType: TargetSelf, StatusEffect: StatusDefenseUp, PreCast: &no}
+	if combatOnly.UsableOutOfCombat() {
+		t.Error("preCastable: false should keep a buff out of the explore cast menu")
+	}
+	combatHeal := &AbilityDef{EffectType: EffectHeal, TargetType: TargetSingleAlly, PreCast: &no}
+	if combatHeal.UsableOutOfCombat() {
+		t.Error("preCastable: false should keep a heal out of the explore cast menu")
+	}
+}
+
+// abilityIDs returns the IDs of the abilities, joined by commas.
+func abilityIDs(abilities []*AbilityDef) string {
+	ids := make([]string, len(abilities))
+	for i, a := range abilities {
+		ids[i] = a.ID
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestAbilityRegistryFilters(t *testing.T) {
+	registry := MustLoadAbilityRegistry()
+
+	for _, a := range registry.Offensive() {
+		if !a.IsOffensive() {
+			t.Errorf("Offensive() returned %s", a.ID)
+		}
+	}
+	if got := len(registry.Offensive()); got != 10 {
+		t.Errorf("len(Offensive()) = %d, want 10", got)
+	}
+
+	if got := abilityIDs(registry.ByEffectType(EffectHeal)); got != "heal,group_heal" {
+		t.Errorf("ByEffectType(heal) = %s, want heal,group_heal", got)
+	}
+	if got := registry.ByEffectType("teleport"); len(got) != 0 {
+		t.Errorf("ByEffectType(teleport) = %s, want none", abilityIDs(got))
+	}
+}
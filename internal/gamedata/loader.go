@@ -5,7 +5,8 @@ import (
 	"fmt"
 )
 
-// Load reads and unmarshals a JSON file from the embedded filesystem.
+// Load reads and unmarshals a JSON file from the embedded filesystem,
+// migrating it to the current schema version first.
 func Load[T any](filename string) (T, error) {
 	var result T
 
@@ -13,12 +14,26 @@ func Load[T any](filename string) (T, error) {
 	if err != nil {
 		return result, fmt.Errorf("failed to read embedded file %s: %w", filename, err)
 	}
+	result, _, err = Parse[T](filename, content)
+	return result, err
+}
+
+// Parse unmarshals content laid out as the named data file, e.g.
+// abilities.json, migrating it to the current schema version first.
+// Returns what the migration assumed about the old format.
+func Parse[T any](filename string, content []byte) (T, []string, error) {
+	var result T
+
+	content, warnings, err := migrate(filename, content)
+	if err != nil {
+		return result, nil, fmt.Errorf("failed to load %s: %w", filename, err)
+	}
 
 	if err := json.Unmarshal(content, &result); err != nil {
-		return result, fmt.Errorf("failed to parse JSON from %s: %w", filename, err)
+		return result, nil, fmt.Errorf("failed to parse JSON from %s: %w", filename, err)
 	}
 
-	return result, nil
+	return result, warnings, nil
 }
 
 // MustLoad reads and unmarshals a JSON file, panicking on error.
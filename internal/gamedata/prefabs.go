@@ -0,0 +1,23 @@
+package gamedata
+
+// PrefabDef is a hand-authored room layout. Layout rows use '#' for wall,
+// '.' for floor, 'E' for enemy spawns, 'C' for chest spots and 'T' for
+// trap spots; the world package parses and validates them.
+type PrefabDef struct {
+	ID     string   `json:"id"`
+	Layout []string `json:"layout"`
+}
+
+// PrefabsFile represents the structure of prefabs.json.
+type PrefabsFile struct {
+	Prefabs []PrefabDef `json:"prefabs"`
+}
+
+// LoadPrefabs loads prefab room layouts from the embedded prefabs.json file.
+func LoadPrefabs() ([]PrefabDef, error) {
+	file, err := Load[PrefabsFile]("prefabs.json")
+	if err != nil {
+		return nil, err
+	}
+	return file.Prefabs, nil
+}
@@ -157,6 +157,27 @@ func (r *AbilityRegistry) Count() int {
 	return len(r.all)
 }
 
+// Offensive returns the abilities that target enemies, in file order.
+func (r *AbilityRegistry) Offensive() []*AbilityDef {
+	return r.filter(func(a *AbilityDef) bool { return a.IsOffensive() })
+}
+
+// ByEffectType returns the abilities with the given effect type, in file order.
+func (r *AbilityRegistry) ByEffectType(effectType EffectType) []*AbilityDef {
+	return r.filter(func(a *AbilityDef) bool { return a.EffectType == effectType })
+}
+
+// filter returns the abilities matching keep, in file order.
+func (r *AbilityRegistry) filter(keep func(*AbilityDef) bool) []*AbilityDef {
+	var result []*AbilityDef
+	for i := range r.all {
+		if ability := &r.all[i]; keep(ability) {
+			result = append(result, ability)
+		}
+	}
+	return result
+}
+
 // =============================================================================
 // ClassRegistry
 // =============================================================================
@@ -0,0 +1,133 @@
+package gamedata
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Every data file carries a top-level "schemaVersion". Files without one
+// are version 1, the format before versioning. Loading an older file runs
+// it through the registered migrations one version at a time; a file newer
+// than this build understands is an error.
+
+// schemaVersions is the current schema version of each data file. Files
+// not listed are at version 1.
+var schemaVersions = map[string]int{
+	AbilitiesFileName: 2,
+	EnemiesFileName:   2,
+}
+
+// migration upgrades a decoded data file by one schema version in place.
+// Returns a warning for each thing it had to assume.
+type migration func(doc map[string]any) []string
+
+// migrations holds, per data file, the migration from each old version to
+// the next.
+var migrations = map[string]map[int]migration{
+	AbilitiesFileName: {1: migrateAbilitiesV1},
+	EnemiesFileName:   {1: migrateEnemiesV1},
+}
+
+// SchemaVersion returns the schema version this build reads and writes for
+// the named data file.
+func SchemaVersion(filename string) int {
+	if v, ok := schemaVersions[filename]; ok {
+		return v
+	}
+	return 1
+}
+
+// migrate brings a data file's JSON up to the current schema version.
+// content is returned unchanged if it is already current.
+func migrate(filename string, content []byte) ([]byte, []string, error) {
+	dec := json.NewDecoder(bytes.NewReader(content))
+	dec.UseNumber()
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, nil, fmt.Errorf("unexpected data after the top-level object at offset %d", dec.InputOffset())
+	}
+
+	version := 1
+	if raw, ok := doc["schemaVersion"]; ok {
+		n, isNumber := raw.(json.Number)
+		v, err := n.Int64()
+		if !isNumber || err != nil || v < 1 {
+			return nil, nil, fmt.Errorf("schemaVersion must be a positive integer, got %v", raw)
+		}
+		version = int(v)
+	}
+	current := SchemaVersion(filename)
+	if version > current {
+		return nil, nil, fmt.Errorf("schema version %d is newer than this build supports (%d); update the game", version, current)
+	}
+	if version == current {
+		return content, nil, nil
+	}
+
+	var warnings []string
+	for v := version; v < current; v++ {
+		step := migrations[filename][v]
+		if step == nil {
+			return nil, nil, fmt.Errorf("no migration from schema version %d to %d", v, v+1)
+		}
+		for _, w := range step(doc) {
+			warnings = append(warnings, fmt.Sprintf("schema v%d: %s", v, w))
+		}
+	}
+	doc["schemaVersion"] = current
+	migrated, err := json.Marshal(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return migrated, warnings, nil
+}
+
+// entries returns the objects in the doc's list under key, skipping
+// anything that isn't an object. Validation reports malformed entries.
+func entries(doc map[string]any, key string) []map[string]any {
+	list, _ := doc[key].([]any)
+	var out []map[string]any
+	for _, item := range list {
+		if m, ok := item.(map[string]any); ok {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// fillMissing sets key on every entry that lacks it and returns a warning
+// naming the entries it changed, or nothing if none needed it.
+func fillMissing(list []map[string]any, key string, value any, why string) []string {
+	var ids []string
+	for _, e := range list {
+		if _, ok := e[key]; ok {
+			continue
+		}
+		e[key] = value
+		id, _ := e["id"].(string)
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	slices.Sort(ids)
+	return []string{fmt.Sprintf("%s; set %s to %v for %s", why, key, value, strings.Join(ids, ", "))}
+}
+
+// migrateAbilitiesV1 adds cooldowns, which version 1 abilities predate.
+func migrateAbilitiesV1(doc map[string]any) []string {
+	return fillMissing(entries(doc, "abilities"), "cooldown", 0, "abilities had no cooldown")
+}
+
+// migrateEnemiesV1 gives every enemy an explicit formation row, which
+// version 1 enemies predate.
+func migrateEnemiesV1(doc map[string]any) []string {
+	return fillMissing(entries(doc, "enemies"), "row", string(RowFront), "enemies had no formation row")
+}
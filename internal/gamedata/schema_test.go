@@ -0,0 +1,96 @@
+package gamedata
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEmbeddedDataIsCurrentSchema(t *testing.T) {
+	files, err := dataFS.ReadDir(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range files {
+		content, _ := dataFS.ReadFile(f.Name())
+		var doc struct {
+			SchemaVersion int `json:"schemaVersion"`
+		}
+		if err := json.Unmarshal(content, &doc); err != nil {
+			t.Fatalf("%s: %v", f.Name(), err)
+		}
+		if want := SchemaVersion(f.Name()); doc.SchemaVersion != want {
+			t.Errorf("%s: schemaVersion = %d, want %d", f.Name(), doc.SchemaVersion, want)
+		}
+	}
+}
+
+func TestLoadDataMigratesV1Overrides(t *testing.T) {
+	dir := filepath.Join("testdata", "schema_v1")
+	d, err := LoadData(dir)
+	if err != nil {
+		t.Fatalf("LoadData: %v", err)
+	}
+	for _, issue := range ValidateAll(d) {
+		t.Errorf("unexpected issue: %s", issue)
+	}
+
+	want := []string{
+		filepath.Join(dir, AbilitiesFileName) + ": schema v1: abilities had no cooldown; set cooldown to 0 for frostbolt",
+		filepath.Join(dir, EnemiesFileName) + ": schema v1: enemies had no formation row; set row to front for kobold",
+	}
+	if strings.Join(d.Warnings, "\n") != strings.Join(want, "\n") {
+		t.Errorf("warnings:\n%s\nwant:\n%s", strings.Join(d.Warnings, "\n"), strings.Join(want, "\n"))
+	}
+
+	enemies := NewEnemyRegistry(d.Enemies)
+	if k := enemies.GetByID("kobold"); k == nil || k.Row != RowFront {
+		t.Errorf("kobold = %+v, want an explicit front row", k)
+	}
+	if s := enemies.GetByID("kobold_slinger"); s == nil || s.Row != RowBack {
+		t.Errorf("kobold_slinger = %+v, want its own back row kept", s)
+	}
+	if jab := NewAbilityRegistry(d.Abilities).GetByID("quick_jab"); jab == nil || jab.Cooldown != 1 {
+		t.Errorf("quick_jab = %+v, want its own cooldown kept", jab)
+	}
+}
+
+func TestLoadDataRejectsFutureSchema(t *testing.T) {
+	dir := t.TempDir()
+	writeOverride(t, dir, EnemiesFileName, `{"schemaVersion": 99, "enemies": []}`)
+
+	_, err := LoadData(dir)
+	if err == nil || !strings.Contains(err.Error(), "schema version 99 is newer than this build supports (2)") {
+		t.Errorf("LoadData error = %v, want a clear newer-schema error", err)
+	}
+}
+
+func TestMigrateRejectsBadSchemaVersion(t *testing.T) {
+	for _, v := range []string{`0`, `"2"`, `1.5`} {
+		_, _, err := migrate(ClassesFileName, []byte(`{"schemaVersion": `+v+`, "classes": []}`))
+		if err == nil {
+			t.Errorf("schemaVersion %s accepted", v)
+		}
+	}
+}
+
+func TestMigrateLeavesCurrentFileAlone(t *testing.T) {
+	content := []byte(`{"schemaVersion": 2, "abilities": [{"id": "zap"}]}`)
+	got, warnings, err := migrate(AbilitiesFileName, content)
+	if err != nil || len(warnings) != 0 || string(got) != string(content) {
+		t.Errorf("migrate = %s, %v, %v; want the file unchanged", got, warnings, err)
+	}
+}
+
+func TestMigrateRejectsTrailingData(t *testing.T) {
+	for _, tail := range []string{`}`, `{"schemaVersion": 2}`, `garbage`} {
+		_, _, err := migrate(AbilitiesFileName, []byte(`{"abilities": []} `+tail))
+		if err == nil {
+			t.Errorf("trailing %q accepted", tail)
+		}
+	}
+	if _, _, err := migrate(AbilitiesFileName, []byte("{\"abilities\": []}\n\n")); err != nil {
+		t.Errorf("trailing whitespace rejected: %v", err)
+	}
+}
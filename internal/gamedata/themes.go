@@ -0,0 +1,130 @@
+package gamedata
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ThemesFileName is the embedded list of room themes.
+const ThemesFileName = "themes.json"
+
+// ThemesFile represents the structure of themes.json.
+type ThemesFile struct {
+	Themes []ThemeDef `json:"themes"`
+}
+
+// ThemeDef is a kind of room that changes how fights in it play out.
+// Rooms with a shrine take the shrine theme, if there is one; any other
+// room takes at most one of the chance-based themes.
+//
+//	{"id": "flooded", "name": "Flooded chamber", "chance": 8,
+//	 "banner": "fire damage halved, everyone slowed",
+//	 "modifiers": {"damage": [{"abilities": ["fireball"], "percent": 50}], "slow": true}}
+type ThemeDef struct {
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Banner    string          `json:"banner"`           // What the modifiers do, shown in the combat panel
+	Chance    int             `json:"chance,omitempty"` // Percent of rooms given the theme
+	Shrine    bool            `json:"shrine,omitempty"` // Given to rooms with a shrine instead of by chance
+	Modifiers CombatModifiers `json:"modifiers"`
+}
+
+// CombatModifiers are a theme's effects on fights in its rooms.
+type CombatModifiers struct {
+	Damage    []DamageScale `json:"damage,omitempty"`    // Damage scaling for some abilities
+	Healing   int           `json:"healing,omitempty"`   // Percent scaling of all healing; 0 leaves it as is
+	Slow      bool          `json:"slow,omitempty"`      // Everyone fights as if slowed
+	NoBackRow bool          `json:"noBackRow,omitempty"` // Enemies all stand in the front row
+}
+
+// DamageScale scales the damage of the listed abilities, or of every
+// ability of a damage type.
+type DamageScale struct {
+	Abilities  []string   `json:"abilities,omitempty"`
+	DamageType DamageType `json:"damageType,omitempty"`
+	Percent    int        `json:"percent"`
+}
+
+// Matches reports whether the scaling applies to the ability.
+func (s DamageScale) Matches(ability *AbilityDef) bool {
+	if s.DamageType != "" && s.DamageType == ability.DamageType {
+		return true
+	}
+	for _, id := range s.Abilities {
+		if id == ability.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// DamagePercent returns the percent scaling of the ability's damage, or 0
+// if no scaling applies. The first matching scale wins.
+func (m CombatModifiers) DamagePercent(ability *AbilityDef) int {
+	for _, s := range m.Damage {
+		if s.Matches(ability) {
+			return s.Percent
+		}
+	}
+	return 0
+}
+
+// LoadThemes loads the embedded room themes.
+func LoadThemes() (*ThemesFile, error) {
+	file, err := Load[ThemesFile](ThemesFileName)
+	if err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// Validate checks that themes have unique IDs, a name and a banner, that
+// chances are between 0 and 100 and add up to at most 100, that only one
+// theme claims shrine rooms, and that damage scaling names known abilities
+// or damage types with a positive percent.
+func (f *ThemesFile) Validate(abilities *AbilityRegistry) error {
+	seen := make(map[string]bool)
+	total, shrines := 0, 0
+	for _, t := range f.Themes {
+		if t.ID == "" || t.Name == "" || t.Banner == "" {
+			return errors.New("theme needs an id, a name and a banner")
+		}
+		if seen[t.ID] {
+			return fmt.Errorf("duplicate theme %q", t.ID)
+		}
+		seen[t.ID] = true
+		if t.Chance < 0 || t.Chance > 100 {
+			return fmt.Errorf("%s: chance must be between 0 and 100", t.ID)
+		}
+		total += t.Chance
+		if t.Shrine {
+			shrines++
+		}
+		if t.Modifiers.Healing < 0 {
+			return fmt.Errorf("%s: healing percent is negative", t.ID)
+		}
+		for _, s := range t.Modifiers.Damage {
+			if s.Percent <= 0 {
+				return fmt.Errorf("%s: damage percent must be positive", t.ID)
+			}
+			if len(s.Abilities) == 0 && s.DamageType == "" {
+				return fmt.Errorf("%s: damage scaling needs abilities or a damage type", t.ID)
+			}
+			if s.DamageType != "" && !knownDamageType(s.DamageType) {
+				return fmt.Errorf("%s: unknown damage type %q", t.ID, s.DamageType)
+			}
+			for _, id := range s.Abilities {
+				if abilities != nil && abilities.GetByID(id) == nil {
+					return fmt.Errorf("%s: unknown ability %q", t.ID, id)
+				}
+			}
+		}
+	}
+	if total > 100 {
+		return fmt.Errorf("theme chances add up to %d%%, more than 100", total)
+	}
+	if shrines > 1 {
+		return errors.New("more than one theme claims shrine rooms")
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package gamedata
+
+import "testing"
+
+func TestLoadThemes(t *testing.T) {
+	themes, err := LoadThemes()
+	if err != nil {
+		t.Fatalf("LoadThemes: %v", err)
+	}
+	abilities := MustLoadAbilityRegistry()
+	if err := themes.Validate(abilities); err != nil {
+		t.Errorf("embedded themes invalid: %v", err)
+	}
+	if len(themes.Themes) == 0 {
+		t.Error("no room themes")
+	}
+}
+
+func TestThemesValidate(t *testing.T) {
+	abilities := MustLoadAbilityRegistry()
+	fire := CombatModifiers{Damage: []DamageScale{{Abilities: []string{"fireball"}, Percent: 50}}}
+	tests := map[string][]ThemeDef{
+		"no banner":       {{ID: "a", Name: "A"}},
+		"duplicate":       {{ID: "a", Name: "A", Banner: "a"}, {ID: "a", Name: "B", Banner: "b"}},
+		"chance over 100": {{ID: "a", Name: "A", Banner: "a", Chance: 101}},
+		"chances sum":     {{ID: "a", Name: "A", Banner: "a", Chance: 60}, {ID: "b", Name: "B", Banner: "b", Chance: 60}},
+		"two shrines":     {{ID: "a", Name: "A", Banner: "a", Shrine: true}, {ID: "b", Name: "B", Banner: "b", Shrine: true}},
+		"unknown ability": {{ID: "a", Name: "A", Banner: "a", Modifiers: CombatModifiers{Damage: []DamageScale{{Abilities: []string{"meteor"}, Percent: 50}}}}},
+		"zero percent":    {{ID: "a", Name: "A", Banner: "a", Modifiers: CombatModifiers{Damage: []DamageScale{{DamageType: DamageMagical}}}}},
+		"no target":       {{ID: "a", Name: "A", Banner: "a", Modifiers: CombatModifiers{Damage: []DamageScale{{Percent: 50}}}}},
+	}
+	for name, themes := range tests {
+		f := ThemesFile{Themes: themes}
+		if err := f.Validate(abilities); err == nil {
+			t.Errorf("%s: Validate() = nil, want an error", name)
+		}
+	}
+
+	ok := ThemesFile{Themes: []ThemeDef{{ID: "a", Name: "A", Banner: "a", Chance: 50, Modifiers: fire}}}
+	if err := ok.Validate(abilities); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+}
+
+func TestDamageScaleMatches(t *testing.T) {
+	abilities := MustLoadAbilityRegistry()
+	fire := CombatModifiers{Damage: []DamageScale{{Abilities: []string{"fireball"}, Percent: 50}}}
+	if got := fire.DamagePercent(abilities.GetByID("fireball")); got != 50 {
+		t.Errorf("fireball percent = %d, want 50", got)
+	}
+	if got := fire.DamagePercent(abilities.GetByID("attack")); got != 0 {
+		t.Errorf("attack percent = %d, want 0 (unscaled)", got)
+	}
+}
@@ -0,0 +1,101 @@
+package gamedata
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// TutorialFileName is the embedded hand-authored tutorial floor.
+const TutorialFileName = "tutorial.json"
+
+// Tutorial layout characters. Besides walls, floor and stairs, a layout
+// marks the party start, the training dummy and numbered trigger tiles.
+// Every marker stands on floor.
+const (
+	TutorialPartyStart = '@'
+	TutorialDummy      = 'D'
+)
+
+// TutorialDummyID is the enemy placed on the tutorial's dummy marker.
+const TutorialDummyID = "training_dummy"
+
+// TutorialDef is the tutorial floor: a fixed layout with scripted
+// instruction panels.
+type TutorialDef struct {
+	Layout   []string          `json:"layout"`
+	Triggers []TutorialTrigger `json:"triggers"`
+}
+
+// TutorialTrigger shows an instruction panel once, either when the party
+// steps on its marker tile or when a game event happens.
+type TutorialTrigger struct {
+	Marker string `json:"marker,omitempty"` // Digit in the layout that fires the trigger
+	Event  string `json:"event,omitempty"`  // Or an event: "start", "combat_start", "victory"
+	Title  string `json:"title"`
+	Text   string `json:"text"`
+}
+
+// Tutorial trigger events.
+const (
+	TutorialEventStart       = "start"
+	TutorialEventCombatStart = "combat_start"
+	TutorialEventVictory     = "victory"
+)
+
+// LoadTutorial loads and validates the embedded tutorial floor.
+func LoadTutorial() (*TutorialDef, error) {
+	def, err := Load[TutorialDef](TutorialFileName)
+	if err != nil {
+		return nil, err
+	}
+	if err := def.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", TutorialFileName, err)
+	}
+	return &def, nil
+}
+
+// Validate checks that the layout is a non-empty rectangle of known
+// characters with one party start and one stairs tile, and that every
+// trigger has a marker present in the layout or a known event.
+func (t *TutorialDef) Validate() error {
+	if len(t.Layout) == 0 {
+		return errors.New("empty layout")
+	}
+	counts := make(map[rune]int)
+	width := len(t.Layout[0])
+	for y, row := range t.Layout {
+		if len(row) != width {
+			return fmt.Errorf("layout row %d is %d wide, want %d", y, len(row), width)
+		}
+		for x, ch := range row {
+			if !strings.ContainsRune("#.>", ch) && ch != TutorialPartyStart && ch != TutorialDummy && (ch < '1' || ch > '9') {
+				return fmt.Errorf("unknown layout character %q at (%d,%d)", ch, x, y)
+			}
+			counts[ch]++
+		}
+	}
+	if counts[TutorialPartyStart] != 1 {
+		return fmt.Errorf("layout needs exactly one party start %q", TutorialPartyStart)
+	}
+	if counts['>'] != 1 {
+		return errors.New("layout needs exactly one stairs tile")
+	}
+
+	for i, trig := range t.Triggers {
+		switch {
+		case trig.Marker != "" && trig.Event != "":
+			return fmt.Errorf("trigger %d has both a marker and an event", i)
+		case trig.Marker != "":
+			if len(trig.Marker) != 1 || counts[rune(trig.Marker[0])] == 0 || trig.Marker[0] < '1' || trig.Marker[0] > '9' {
+				return fmt.Errorf("trigger %d marker %q is not a digit in the layout", i, trig.Marker)
+			}
+		case trig.Event != TutorialEventStart && trig.Event != TutorialEventCombatStart && trig.Event != TutorialEventVictory:
+			return fmt.Errorf("trigger %d has unknown event %q", i, trig.Event)
+		}
+		if trig.Text == "" {
+			return fmt.Errorf("trigger %d has no text", i)
+		}
+	}
+	return nil
+}
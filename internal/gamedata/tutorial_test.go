@@ -0,0 +1,44 @@
+package gamedata
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEmbeddedTutorialIsValid(t *testing.T) {
+	if _, err := LoadTutorial(); err != nil {
+		t.Fatalf("LoadTutorial: %v", err)
+	}
+	if MustLoadEnemyRegistry().GetByID(TutorialDummyID) == nil {
+		t.Errorf("tutorial dummy %q is not defined", TutorialDummyID)
+	}
+}
+
+func TestTutorialValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		def  TutorialDef
+		want string
+	}{
+		{"ragged", TutorialDef{Layout: []string{"####", "#@>"}}, "layout row 1 is 3 wide"},
+		{"unknown char", TutorialDef{Layout: []string{"#@>x"}}, `unknown layout character 'x'`},
+		{"no start", TutorialDef{Layout: []string{"#..>"}}, "exactly one party start"},
+		{"missing marker", TutorialDef{
+			Layout:   []string{"#@>1"},
+			Triggers: []TutorialTrigger{{Marker: "2", Text: "hi"}},
+		}, `marker "2" is not a digit in the layout`},
+		{"bad event", TutorialDef{
+			Layout:   []string{"#@>1"},
+			Triggers: []TutorialTrigger{{Event: "teleport", Text: "hi"}},
+		}, `unknown event "teleport"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.def.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() = %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package gamedata
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Issue is a single problem found while validating data.
+type Issue struct {
+	File    string // File the definition came from
+	ID      string // ID of the offending definition ("" if it has none)
+	Message string
+}
+
+// String formats the issue as "file: id: message".
+func (i Issue) String() string {
+	id := i.ID
+	if id == "" {
+		id = "(no id)"
+	}
+	return i.File + ": " + id + ": " + i.Message
+}
+
+// ValidateAll checks every definition for missing fields, unknown enum
+// values, duplicate IDs and references to abilities that don't exist.
+// Returns nil when the data is valid.
+func ValidateAll(d *Data) []Issue {
+	var issues []Issue
+	report := func(file, id, format string, args ...any) {
+		issues = append(issues, Issue{File: d.Source(file, id), ID: id, Message: fmt.Sprintf(format, args...)})
+	}
+
+	abilities := make(map[string]bool)
+	for _, a := range d.Abilities {
+		validateCommon(report, AbilitiesFileName, a.ID, a.Name, abilities)
+		if !knownEffectType(a.EffectType) {
+			report(AbilitiesFileName, a.ID, "unknown effectType %q", a.EffectType)
+		}
+		if !a.TargetType.Valid() {
+			report(AbilitiesFileName, a.ID, "unknown targetType %q", a.TargetType)
+		}
+		if a.DamageType != "" && a.DamageType != DamagePhysical && a.DamageType != DamageMagical && a.DamageType != DamageTrue {
+			report(AbilitiesFileName, a.ID, "unknown damageType %q", a.DamageType)
+		}
+		if !knownStatus(a.StatusEffect) {
+			report(AbilitiesFileName, a.ID, "unknown statusEffect %q", a.StatusEffect)
+		}
+		if (a.EffectType == EffectBuff || a.EffectType == EffectDebuff) && a.StatusEffect == StatusNone {
+			report(AbilitiesFileName, a.ID, "statusEffect is required for %s abilities", a.EffectType)
+		}
+		if a.StatusEffect != StatusNone && a.StatusDuration <= 0 {
+			report(AbilitiesFileName, a.ID, "statusDuration must be positive when statusEffect is set")
+		}
+		if a.MPCost < 0 || a.BasePower < 0 || a.Cooldown < 0 {
+			report(AbilitiesFileName, a.ID, "mpCost, basePower and cooldown must not be negative")
+		}
+		if a.AttackMultiplier() < 0 || a.MagicMultiplier() < 0 || a.LevelScale < 0 {
+			report(AbilitiesFileName, a.ID, "attackScale, magicScale and levelScale must not be negative")
+		}
+		if a.StatusMagicScale < 0 {
+			report(AbilitiesFileName, a.ID, "statusMagicScale must not be negative")
+		}
+		if s := a.HPScaling; s != nil {
+			if a.EffectType != EffectDamage {
+				report(AbilitiesFileName, a.ID, "hpScaling only applies to damage abilities")
+			}
+			if s.Of != HPOfUser && s.Of != HPOfTarget {
+				report(AbilitiesFileName, a.ID, "hpScaling.of must be %q or %q, got %q", HPOfUser, HPOfTarget, s.Of)
+			}
+			if s.Power < 0 || s.Threshold < 0 || s.Threshold > 1 {
+				report(AbilitiesFileName, a.ID, "hpScaling.power must not be negative and threshold must be between 0 and 1")
+			}
+		}
+	}
+
+	enemies := make(map[string]bool)
+	for _, e := range d.Enemies {
+		validateCommon(report, EnemiesFileName, e.ID, e.Name, enemies)
+		if e.HP <= 0 {
+			report(EnemiesFileName, e.ID, "hp must be positive")
+		}
+		if e.SpawnWeight < 0 {
+			report(EnemiesFileName, e.ID, "spawnWeight must not be negative")
+		}
+		if e.Resist < 0 {
+			report(EnemiesFileName, e.ID, "resist must not be negative")
+		}
+		for _, drop := range e.Loot {
+			if drop.Item == "" {
+				report(EnemiesFileName, e.ID, "loot entry is missing its item")
+			}
+			if drop.Chance <= 0 || drop.Chance > 1 || drop.Count < 0 {
+				report(EnemiesFileName, e.ID, "loot %q needs a chance above 0 and at most 1, and a count that isn't negative", drop.Item)
+			}
+		}
+		if len(e.Glyph) != 1 {
+			report(EnemiesFileName, e.ID, "glyph must be a single character, got %q", e.Glyph)
+		}
+		if _, err := ParseHexColor(e.Color); err != nil {
+			report(EnemiesFileName, e.ID, "invalid color %q", e.Color)
+		}
+		if e.Row != "" && e.Row != RowFront && e.Row != RowBack {
+			report(EnemiesFileName, e.ID, "unknown row %q", e.Row)
+		}
+		validateAbilityRefs(report, EnemiesFileName, e.ID, e.Abilities, abilities)
+		if pool := e.AbilityPool; pool != nil {
+			validateAbilityRefs(report, EnemiesFileName, e.ID, pool.Abilities, abilities)
+			if pool.Count <= 0 || pool.Count > len(pool.Abilities) {
+				report(EnemiesFileName, e.ID, "abilityPool.count must be between 1 and the pool size")
+			}
+			for _, id := range pool.Abilities {
+				if slices.Contains(e.Abilities, id) {
+					report(EnemiesFileName, e.ID, "abilityPool repeats fixed ability %q", id)
+				}
+			}
+		}
+		if u := e.Undying; u != nil {
+			if u.Chance <= 0 || u.Chance > 100 {
+				report(EnemiesFileName, e.ID, "undying.chance must be between 1 and 100")
+			}
+			if u.HPPercent <= 0 || u.HPPercent > 100 {
+				report(EnemiesFileName, e.ID, "undying.hpPercent must be between 1 and 100")
+			}
+			if u.Rises < 0 || u.Delay < 0 {
+				report(EnemiesFileName, e.ID, "undying rises and delay must not be negative")
+			}
+			for _, kill := range u.Permakill {
+				if !abilities[kill] && !knownDamageType(DamageType(kill)) {
+					report(EnemiesFileName, e.ID, "undying.permakill %q is neither an ability nor a damage type", kill)
+				}
+			}
+		}
+	}
+	// Split targets may be defined after the enemy that references them
+	for _, e := range d.Enemies {
+		for _, effect := range e.OnDeath {
+			validateDeathEffect(report, e.ID, effect, enemies)
+		}
+	}
+
+	classes := make(map[string]bool)
+	for _, c := range d.Classes {
+		validateCommon(report, ClassesFileName, c.ID, c.Name, classes)
+		if c.HP <= 0 {
+			report(ClassesFileName, c.ID, "hp must be positive")
+		}
+		if c.Resist < 0 {
+			report(ClassesFileName, c.ID, "resist must not be negative")
+		}
+		if len(c.Symbol) != 1 {
+			report(ClassesFileName, c.ID, "symbol must be a single character, got %q", c.Symbol)
+		}
+		validateAbilityRefs(report, ClassesFileName, c.ID, c.Abilities, abilities)
+	}
+
+	// Item IDs live with the game, so only empty item names are caught here
+	difficulties := make(map[string]bool)
+	for i := range d.Difficulties {
+		def := &d.Difficulties[i]
+		validateCommon(report, DifficultiesFileName, def.ID, def.Name, difficulties)
+		def.validateStart(nil, func(format string, args ...any) {
+			report(DifficultiesFileName, def.ID, format, args...)
+		})
+	}
+
+	return issues
+}
+
+// reportFunc records a validation issue.
+type reportFunc func(file, id, format string, args ...any)
+
+// validateCommon checks the ID and name shared by every definition type.
+func validateCommon(report reportFunc, file, id, name string, seen map[string]bool) {
+	if id == "" {
+		report(file, id, "missing id")
+	} else if seen[id] {
+		report(file, id, "duplicate id")
+	}
+	seen[id] = true
+	if name == "" {
+		report(file, id, "missing name")
+	}
+}
+
+// validateAbilityRefs checks that every listed ability exists.
+func validateAbilityRefs(report reportFunc, file, id string, refs []string, abilities map[string]bool) {
+	if len(refs) == 0 {
+		report(file, id, "no abilities")
+	}
+	for _, ref := range refs {
+		if !abilities[ref] {
+			report(file, id, "unknown ability %q", ref)
+		}
+	}
+}
+
+// validateDeathEffect checks one onDeath entry of an enemy.
+func validateDeathEffect(report reportFunc, id string, effect DeathEffect, enemies map[string]bool) {
+	switch effect.Type {
+	case DeathExplode:
+		if effect.Power <= 0 {
+			report(EnemiesFileName, id, "explode power must be positive")
+		}
+	case DeathSplit:
+		if !enemies[effect.Enemy] {
+			report(EnemiesFileName, id, "split references unknown enemy %q", effect.Enemy)
+		}
+		if effect.Count <= 0 {
+			report(EnemiesFileName, id, "split count must be positive")
+		}
+	case DeathCurse:
+		if !effect.Status.IsNegative() {
+			report(EnemiesFileName, id, "curse status %q must be a debuff", effect.Status)
+		}
+		if effect.Duration <= 0 {
+			report(EnemiesFileName, id, "curse duration must be positive")
+		}
+	default:
+		report(EnemiesFileName, id, "unknown onDeath type %q", effect.Type)
+	}
+}
+
+func knownDamageType(t DamageType) bool {
+	switch t {
+	case DamagePhysical, DamageMagical, DamageTrue:
+		return true
+	}
+	return false
+}
+
+func knownEffectType(t EffectType) bool {
+	switch t {
+	case EffectDamage, EffectHeal, EffectBuff, EffectDebuff, EffectCleanse:
+		return true
+	}
+	return false
+}
+
+func knownFormula(f DamageFormula) bool {
+	switch f {
+	case "", FormulaAdditive, FormulaMultiplicative:
+		return true
+	}
+	return false
+}
+
+func knownStatus(s StatusEffectType) bool {
+	switch s {
+	case StatusNone, StatusPoison, StatusRegen, StatusDefenseUp, StatusDefenseDown,
+		StatusAttackUp, StatusAttackDown, StatusSilence, StatusTaunt, StatusHaste, StatusSlow, StatusDelay:
+		return true
+	}
+	return false
+}
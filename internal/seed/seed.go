@@ -0,0 +1,132 @@
+// Package seed derives a run's independent random streams from its master
+// seed. Each subsystem draws from its own stream, keyed by what it is
+// generating (a floor, an encounter, a corpse search), so one subsystem
+// drawing more or fewer values never shifts another's rolls, and anyone
+// holding the master seed can recompute every sub-seed a run used.
+package seed
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"os"
+	"path/filepath"
+)
+
+// Stream names a subsystem with its own random stream.
+type Stream string
+
+const (
+	// Dungeon generates a floor's layout; indexed by floor
+	Dungeon Stream = "dungeon"
+	// Combat rolls an encounter's dice; indexed by encounter
+	Combat Stream = "combat"
+	// Loot rolls a corpse's drops; indexed by search
+	Loot Stream = "loot"
+)
+
+// Derive returns the sub-seed for the index-th use of the stream. It is a
+// keyed hash of the master seed, the stream name and the index, so it
+// never depends on what else the run has drawn.
+func Derive(master int64, stream Stream, index int) int64 {
+	h := fnv.New64a()
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], uint64(master))
+	h.Write(buf[:])
+	h.Write([]byte(stream))
+	binary.LittleEndian.PutUint64(buf[:], uint64(index))
+	h.Write(buf[:])
+	return int64(mix(h.Sum64()))
+}
+
+// mix is the splitmix64 finalizer. FNV spreads small changes in its last
+// bytes poorly, and neighbouring indices should give unrelated seeds.
+func mix(z uint64) uint64 {
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// Use is one sub-seed a run drew a stream from.
+type Use struct {
+	Stream Stream `json:"stream"`
+	Index  int    `json:"index"`
+	Seed   int64  `json:"seed"`
+}
+
+// Log records the sub-seeds a run used, so they can be checked against
+// the master seed afterwards.
+type Log struct {
+	Master int64 `json:"master"`
+	Uses   []Use `json:"uses"`
+}
+
+// NewLog starts an empty log for the master seed.
+func NewLog(master int64) *Log {
+	return &Log{Master: master}
+}
+
+// Rand derives the stream's index-th sub-seed, records it and returns a
+// generator seeded with it. Deriving the same stream and index again
+// returns a fresh generator without recording it twice.
+func (l *Log) Rand(stream Stream, index int) *rand.Rand {
+	return rand.New(rand.NewSource(l.Seed(stream, index)))
+}
+
+// Seed derives and records the stream's index-th sub-seed like Rand, for
+// callers that build their own source from it.
+func (l *Log) Seed(stream Stream, index int) int64 {
+	s := Derive(l.Master, stream, index)
+	if !l.used(stream, index) {
+		l.Uses = append(l.Uses, Use{Stream: stream, Index: index, Seed: s})
+	}
+	return s
+}
+
+// used reports whether the stream's index-th sub-seed is already logged.
+func (l *Log) used(stream Stream, index int) bool {
+	for _, u := range l.Uses {
+		if u.Stream == stream && u.Index == index {
+			return true
+		}
+	}
+	return false
+}
+
+// Verify recomputes every logged sub-seed from the master seed and returns
+// an error naming the first one that doesn't match.
+func (l *Log) Verify() error {
+	for _, u := range l.Uses {
+		if want := Derive(l.Master, u.Stream, u.Index); u.Seed != want {
+			return fmt.Errorf("%s #%d used seed %d, but master seed %d derives %d", u.Stream, u.Index, u.Seed, l.Master, want)
+		}
+	}
+	return nil
+}
+
+// ReadLog reads a log written by Write.
+func ReadLog(path string) (*Log, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var l Log
+	if err := json.Unmarshal(data, &l); err != nil {
+		return nil, fmt.Errorf("parse seed log %s: %w", path, err)
+	}
+	return &l, nil
+}
+
+// Write stores the log at path as JSON, creating its directory if needed.
+func (l *Log) Write(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,119 @@
+// Package spectate streams rendered frames to read-only viewers over TCP.
+//
+// A game publishes each frame as a cell grid to a Server, which sends
+// connected clients only the cells that changed since the previous frame.
+// Watch connects to a server and draws the frames it receives.
+package spectate
+
+import "github.com/gdamore/tcell/v2"
+
+// Cell is one character cell of a frame.
+type Cell struct {
+	Rune  rune
+	Style tcell.Style
+}
+
+// blank is the cell a fresh grid is filled with.
+var blank = Cell{Rune: ' ', Style: tcell.StyleDefault}
+
+// Grid is a frame's cells in row-major order.
+type Grid struct {
+	Width, Height int
+	Cells         []Cell
+}
+
+// NewGrid returns a blank grid of the given size.
+func NewGrid(width, height int) *Grid {
+	g := &Grid{Width: width, Height: height, Cells: make([]Cell, width*height)}
+	for i := range g.Cells {
+		g.Cells[i] = blank
+	}
+	return g
+}
+
+// At returns the cell at (x, y), or a blank cell outside the grid.
+func (g *Grid) At(x, y int) Cell {
+	if x < 0 || x >= g.Width || y < 0 || y >= g.Height {
+		return blank
+	}
+	return g.Cells[y*g.Width+x]
+}
+
+// Set changes the cell at (x, y). Positions outside the grid are ignored.
+func (g *Grid) Set(x, y int, c Cell) {
+	if x < 0 || x >= g.Width || y < 0 || y >= g.Height {
+		return
+	}
+	g.Cells[y*g.Width+x] = c
+}
+
+// Clone returns a copy of the grid.
+func (g *Grid) Clone() *Grid {
+	c := &Grid{Width: g.Width, Height: g.Height, Cells: make([]Cell, len(g.Cells))}
+	copy(c.Cells, g.Cells)
+	return c
+}
+
+// Run is a horizontal stretch of cells starting at (X, Y).
+type Run struct {
+	X, Y  int
+	Cells []Cell
+}
+
+// Frame is one update sent to viewers. A key frame carries every cell and
+// replaces the viewer's grid; other frames carry only changed cells.
+type Frame struct {
+	Seq           uint64
+	Key           bool
+	Width, Height int
+	Runs          []Run
+	HUD           []string // Status lines shown beside the grid, e.g. the game message
+}
+
+// KeyFrame returns a frame carrying the whole grid.
+func KeyFrame(seq uint64, g *Grid, hud []string) *Frame {
+	f := &Frame{Seq: seq, Key: true, Width: g.Width, Height: g.Height, HUD: hud}
+	for y := 0; y < g.Height; y++ {
+		row := make([]Cell, g.Width)
+		copy(row, g.Cells[y*g.Width:(y+1)*g.Width])
+		f.Runs = append(f.Runs, Run{X: 0, Y: y, Cells: row})
+	}
+	return f
+}
+
+// DiffFrame returns a frame turning prev into next. It is a key frame when
+// there is no previous grid or the size changed.
+func DiffFrame(seq uint64, prev, next *Grid, hud []string) *Frame {
+	if prev == nil || prev.Width != next.Width || prev.Height != next.Height {
+		return KeyFrame(seq, next, hud)
+	}
+	f := &Frame{Seq: seq, Width: next.Width, Height: next.Height, HUD: hud}
+	for y := 0; y < next.Height; y++ {
+		for x := 0; x < next.Width; {
+			if prev.At(x, y) == next.At(x, y) {
+				x++
+				continue
+			}
+			run := Run{X: x, Y: y}
+			for ; x < next.Width && prev.At(x, y) != next.At(x, y); x++ {
+				run.Cells = append(run.Cells, next.At(x, y))
+			}
+			f.Runs = append(f.Runs, run)
+		}
+	}
+	return f
+}
+
+// Apply updates g with the frame and returns the result. Key frames start
+// from a fresh grid of the frame's size.
+func (f *Frame) Apply(g *Grid) *Grid {
+	if f.Key || g == nil || g.Width != f.Width || g.Height != f.Height {
+		g = NewGrid(f.Width, f.Height)
+	}
+	for _, run := range f.Runs {
+		for i, c := range run.Cells {
+			g.Set(run.X+i, run.Y, c)
+		}
+	}
+	return g
+}
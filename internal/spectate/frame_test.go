@@ -0,0 +1,84 @@
+package spectate
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// gridOf builds a grid from rows of text in the default style.
+func gridOf(rows ...string) *Grid {
+	g := NewGrid(len(rows[0]), len(rows))
+	for y, row := range rows {
+		for x, r := range row {
+			g.Set(x, y, Cell{Rune: r, Style: tcell.StyleDefault})
+		}
+	}
+	return g
+}
+
+func TestDiffFrameSendsOnlyChangedRuns(t *testing.T) {
+	prev := gridOf("#....#", "#....#")
+	next := gridOf("#.@@.#", "#....@")
+
+	f := DiffFrame(2, prev, next, nil)
+	if f.Key {
+		t.Fatal("same-size grids should diff, not key frame")
+	}
+	if len(f.Runs) != 2 {
+		t.Fatalf("runs = %+v, want 2", f.Runs)
+	}
+	if r := f.Runs[0]; r.X != 2 || r.Y != 0 || len(r.Cells) != 2 {
+		t.Errorf("first run = %+v, want 2 cells at (2,0)", r)
+	}
+	if r := f.Runs[1]; r.X != 5 || r.Y != 1 || len(r.Cells) != 1 {
+		t.Errorf("second run = %+v, want 1 cell at (5,1)", r)
+	}
+
+	if got := f.Apply(prev.Clone()); !equalGrids(got, next) {
+		t.Error("applying the diff to the previous grid should give the next grid")
+	}
+}
+
+func TestDiffFrameKeysOnResize(t *testing.T) {
+	f := DiffFrame(1, gridOf("ab"), gridOf("abc"), nil)
+	if !f.Key || f.Width != 3 {
+		t.Errorf("frame = %+v, want a 3-wide key frame", f)
+	}
+	if f := DiffFrame(1, nil, gridOf("ab"), nil); !f.Key {
+		t.Error("the first frame should be a key frame")
+	}
+}
+
+func TestViewerRejectsOutOfOrderFrames(t *testing.T) {
+	a, b := gridOf("a."), gridOf("ab")
+	var v Viewer
+	if err := v.Apply(DiffFrame(1, a, b, nil)); err == nil {
+		t.Error("a diff before any key frame should be rejected")
+	}
+	if err := v.Apply(KeyFrame(1, a, []string{"hud"})); err != nil {
+		t.Fatalf("key frame: %v", err)
+	}
+	if err := v.Apply(DiffFrame(3, a, b, nil)); err == nil {
+		t.Error("a diff skipping frame 2 should be rejected")
+	}
+	if err := v.Apply(DiffFrame(2, a, b, nil)); err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	if !equalGrids(v.Grid(), b) {
+		t.Error("viewer grid should match the published grid")
+	}
+}
+
+// equalGrids reports whether two grids have the same size and cells.
+func equalGrids(a, b *Grid) bool {
+	if a.Width != b.Width || a.Height != b.Height {
+		return false
+	}
+	for i := range a.Cells {
+		if a.Cells[i] != b.Cells[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,166 @@
+package spectate
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+// clientBacklog is how many frames a client may fall behind before it is
+// dropped. Publishing never waits on a slow viewer.
+const clientBacklog = 32
+
+// writeTimeout bounds a single frame write to a client.
+const writeTimeout = 5 * time.Second
+
+// maxAcceptDelay caps the wait between retries while Accept keeps failing.
+const maxAcceptDelay = time.Second
+
+// Server streams published frames to every connected viewer. New viewers
+// get a key frame of the latest grid, then diffs.
+type Server struct {
+	ln net.Listener
+	wg sync.WaitGroup
+
+	mu      sync.Mutex
+	clients map[*client]struct{}
+	grid    *Grid // Last published grid
+	hud     []string
+	seq     uint64
+	closed  bool
+}
+
+// client is one connected viewer with its queue of encoded frames.
+type client struct {
+	conn   net.Conn
+	frames chan []byte
+}
+
+// Listen starts a server accepting viewers on the TCP address.
+func Listen(addr string) (*Server, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{ln: ln, clients: make(map[*client]struct{})}
+	s.wg.Add(1)
+	go s.acceptLoop()
+	return s, nil
+}
+
+// Addr returns the address the server is listening on.
+func (s *Server) Addr() net.Addr {
+	return s.ln.Addr()
+}
+
+// Clients returns how many viewers are connected.
+func (s *Server) Clients() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.clients)
+}
+
+// acceptLoop registers viewers until the listener closes. Other accept
+// errors, like running out of file descriptors, are retried with a
+// doubling delay so a persistent one doesn't spin.
+func (s *Server) acceptLoop() {
+	defer s.wg.Done()
+	var delay time.Duration
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			delay = min(max(2*delay, 5*time.Millisecond), maxAcceptDelay)
+			time.Sleep(delay)
+			continue
+		}
+		delay = 0
+
+		c := &client{conn: conn, frames: make(chan []byte, clientBacklog)}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return
+		}
+		if s.grid != nil {
+			c.frames <- MarshalFrame(KeyFrame(s.seq, s.grid, s.hud))
+		}
+		s.clients[c] = struct{}{}
+		s.wg.Add(1)
+		s.mu.Unlock()
+		go s.writeLoop(c)
+	}
+}
+
+// writeLoop sends a client its queued frames until its queue is closed or
+// a write fails.
+func (s *Server) writeLoop(c *client) {
+	defer s.wg.Done()
+	defer c.conn.Close()
+	for data := range c.frames {
+		c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if _, err := c.conn.Write(data); err != nil {
+			s.mu.Lock()
+			s.drop(c)
+			s.mu.Unlock()
+			return
+		}
+	}
+}
+
+// drop disconnects a client. Callers hold s.mu.
+func (s *Server) drop(c *client) {
+	if _, ok := s.clients[c]; !ok {
+		return
+	}
+	delete(s.clients, c)
+	close(c.frames)
+	c.conn.Close()
+}
+
+// Publish sends viewers the changes from the last published grid to g.
+// It never blocks: viewers whose queue is full are dropped. The grid is
+// copied, so the caller may keep drawing into it.
+func (s *Server) Publish(g *Grid, hud []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+
+	s.seq++
+	frame := DiffFrame(s.seq, s.grid, g, hud)
+	s.grid = g.Clone()
+	s.hud = append([]string(nil), hud...)
+	if len(s.clients) == 0 {
+		return
+	}
+
+	data := MarshalFrame(frame)
+	for c := range s.clients {
+		select {
+		case c.frames <- data:
+		default:
+			s.drop(c)
+		}
+	}
+}
+
+// Close stops accepting viewers, lets connected viewers receive the frames
+// already queued for them, then disconnects them.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	err := s.ln.Close()
+	for c := range s.clients {
+		delete(s.clients, c)
+		close(c.frames)
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	return err
+}
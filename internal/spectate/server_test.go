@@ -0,0 +1,92 @@
+package spectate
+
+import (
+	"bufio"
+	"net"
+	"testing"
+	"time"
+)
+
+// dial connects a viewer and waits for the server to register it.
+func dial(t *testing.T, s *Server, want int) net.Conn {
+	t.Helper()
+	conn, err := net.Dial("tcp", s.Addr().String())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	waitFor(t, func() bool { return s.Clients() == want })
+	return conn
+}
+
+// waitFor polls cond for up to two seconds.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestLateViewerStartsFromKeyFrame(t *testing.T) {
+	s, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer s.Close()
+
+	first, second := gridOf("#..#"), gridOf("#@.#")
+	s.Publish(first, nil)
+	conn := dial(t, s, 1)
+	s.Publish(second, []string{"moved"})
+
+	r := bufio.NewReader(conn)
+	var v Viewer
+	for range 2 {
+		f, err := ReadFrame(r)
+		if err != nil {
+			t.Fatalf("ReadFrame: %v", err)
+		}
+		if err := v.Apply(f); err != nil {
+			t.Fatalf("Apply: %v", err)
+		}
+	}
+	if !equalGrids(v.Grid(), second) || len(v.HUD()) != 1 || v.HUD()[0] != "moved" {
+		t.Errorf("viewer has %+v %q, want the second grid", v.Grid(), v.HUD())
+	}
+}
+
+func TestSlowViewerIsDropped(t *testing.T) {
+	s, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer s.Close()
+	dial(t, s, 1) // Never reads
+
+	// Alternate two big grids so every frame rewrites every cell, until the
+	// socket buffers and the client's queue are full
+	a, b := NewGrid(200, 200), NewGrid(200, 200)
+	for i := range b.Cells {
+		b.Cells[i].Rune = '#'
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000 && s.Clients() > 0; i++ {
+			s.Publish([]*Grid{a, b}[i%2], nil)
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Publish blocked on a slow viewer")
+	}
+	if s.Clients() != 0 {
+		t.Error("a viewer that never reads should be dropped")
+	}
+}
@@ -0,0 +1,161 @@
+package spectate
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Screen is what a viewer draws to. *ui.Screen satisfies it.
+type Screen interface {
+	SetContent(x, y int, r rune, style tcell.Style)
+	Clear()
+	Show()
+	Sync()
+	PollEvent() tcell.Event
+	PostEvent(ev tcell.Event) error
+}
+
+// Viewer rebuilds a game's frames from the stream.
+type Viewer struct {
+	grid *Grid
+	hud  []string
+	seq  uint64
+}
+
+// Apply updates the viewer with the next frame. Streams must open with a
+// key frame and diffs must follow in sequence, since a diff against the
+// wrong grid would draw garbage.
+func (v *Viewer) Apply(f *Frame) error {
+	switch {
+	case f.Key:
+	case v.grid == nil:
+		return fmt.Errorf("spectate: diff frame %d before any key frame", f.Seq)
+	case f.Seq != v.seq+1:
+		return fmt.Errorf("spectate: frame %d follows frame %d", f.Seq, v.seq)
+	}
+	v.grid = f.Apply(v.grid)
+	v.hud = f.HUD
+	v.seq = f.Seq
+	return nil
+}
+
+// Grid returns the current frame, or nil before the first key frame.
+func (v *Viewer) Grid() *Grid {
+	return v.grid
+}
+
+// HUD returns the current frame's status lines.
+func (v *Viewer) HUD() []string {
+	return v.hud
+}
+
+// Draw shows the current frame with its HUD lines underneath.
+func (v *Viewer) Draw(screen Screen) {
+	screen.Clear()
+	if v.grid == nil {
+		drawLine(screen, 0, "Waiting for the game…")
+		screen.Show()
+		return
+	}
+	for y := 0; y < v.grid.Height; y++ {
+		for x := 0; x < v.grid.Width; x++ {
+			c := v.grid.At(x, y)
+			screen.SetContent(x, y, c.Rune, c.Style)
+		}
+	}
+	for i, line := range v.hud {
+		drawLine(screen, v.grid.Height+i, line)
+	}
+	screen.Show()
+}
+
+// drawLine writes plain text at the start of row y.
+func drawLine(screen Screen, y int, text string) {
+	for x, r := range []rune(text) {
+		screen.SetContent(x, y, r, tcell.StyleDefault)
+	}
+}
+
+// Watch connects to a spectator server and draws the game on screen until
+// the viewer presses q or Escape, or the stream ends. Other keys are ignored;
+// watching is read-only.
+func Watch(addr string, screen Screen) error {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	in := &inbox{}
+	go in.fill(bufio.NewReader(conn), screen)
+
+	var v Viewer
+	v.Draw(screen)
+	for {
+		switch ev := screen.PollEvent().(type) {
+		case nil:
+			return nil
+		case *tcell.EventKey:
+			if ev.Key() == tcell.KeyEscape || ev.Key() == tcell.KeyCtrlC || ev.Rune() == 'q' {
+				return nil
+			}
+		case *tcell.EventResize:
+			screen.Sync()
+			v.Draw(screen)
+		case *tcell.EventInterrupt:
+			frames, err := in.take()
+			for _, f := range frames {
+				if err := v.Apply(f); err != nil {
+					return err
+				}
+			}
+			if len(frames) > 0 {
+				v.Draw(screen)
+			}
+			if err != nil {
+				return fmt.Errorf("stream from %s ended: %w", addr, err)
+			}
+		}
+	}
+}
+
+// inbox hands frames from the connection to the event loop. The screen's
+// event queue can drop posted events when full, so frames wait here and an
+// interrupt only wakes the loop up.
+type inbox struct {
+	mu     sync.Mutex
+	frames []*Frame
+	err    error
+}
+
+// fill reads frames until the stream fails, waking the screen for each.
+func (in *inbox) fill(r *bufio.Reader, screen Screen) {
+	for {
+		f, err := ReadFrame(r)
+		in.mu.Lock()
+		if err != nil {
+			in.err = err
+		} else {
+			in.frames = append(in.frames, f)
+		}
+		in.mu.Unlock()
+		screen.PostEvent(tcell.NewEventInterrupt(nil))
+		if err != nil {
+			return
+		}
+	}
+}
+
+// take returns the frames received since the last call and the read error,
+// once the stream has failed.
+func (in *inbox) take() ([]*Frame, error) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	frames := in.frames
+	in.frames = nil
+	return frames, in.err
+}
@@ -0,0 +1,218 @@
+package spectate
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Wire format
+//
+// Every frame is sent as:
+//
+//	magic   "DBSF"
+//	version 1 byte (Version)
+//	length  uvarint, bytes of payload
+//	payload
+//
+// Version 1 payload, with every integer a uvarint:
+//
+//	seq, flags (bit 0: key frame), width, height
+//	HUD line count, then each line as length + UTF-8 bytes
+//	run count, then each run as x, y, cell count and its cells
+//
+// A cell is its rune followed by a style marker byte: 0 reuses the previous
+// cell's style in the run, 1 is followed by foreground, background and
+// attribute mask. The first cell of every run carries its style.
+//
+// Readers must reject versions they don't know rather than guess.
+
+// Version is the wire format version written by WriteFrame.
+const Version = 1
+
+// magic starts every frame on the wire.
+const magic = "DBSF"
+
+// maxPayload bounds how much a reader will buffer for one frame.
+const maxPayload = 4 << 20
+
+// maxDimension bounds a frame's width and height, well past any terminal.
+const maxDimension = 4096
+
+const (
+	flagKey = 1 << 0
+
+	styleSame = 0
+	styleNew  = 1
+)
+
+// ErrVersion is returned for frames in a wire format version this build
+// doesn't understand.
+var ErrVersion = errors.New("spectate: unsupported wire format version")
+
+// MarshalFrame encodes a frame for the wire.
+func MarshalFrame(f *Frame) []byte {
+	var p []byte
+	p = binary.AppendUvarint(p, f.Seq)
+	var flags uint64
+	if f.Key {
+		flags |= flagKey
+	}
+	p = binary.AppendUvarint(p, flags)
+	p = binary.AppendUvarint(p, uint64(f.Width))
+	p = binary.AppendUvarint(p, uint64(f.Height))
+
+	p = binary.AppendUvarint(p, uint64(len(f.HUD)))
+	for _, line := range f.HUD {
+		p = binary.AppendUvarint(p, uint64(len(line)))
+		p = append(p, line...)
+	}
+
+	p = binary.AppendUvarint(p, uint64(len(f.Runs)))
+	for _, run := range f.Runs {
+		p = binary.AppendUvarint(p, uint64(run.X))
+		p = binary.AppendUvarint(p, uint64(run.Y))
+		p = binary.AppendUvarint(p, uint64(len(run.Cells)))
+		for i, c := range run.Cells {
+			p = binary.AppendUvarint(p, uint64(c.Rune))
+			if i > 0 && c.Style == run.Cells[i-1].Style {
+				p = append(p, styleSame)
+				continue
+			}
+			fg, bg, attrs := c.Style.Decompose()
+			p = append(p, styleNew)
+			p = binary.AppendUvarint(p, uint64(fg))
+			p = binary.AppendUvarint(p, uint64(bg))
+			p = binary.AppendUvarint(p, uint64(attrs))
+		}
+	}
+
+	out := make([]byte, 0, len(magic)+1+binary.MaxVarintLen64+len(p))
+	out = append(out, magic...)
+	out = append(out, Version)
+	out = binary.AppendUvarint(out, uint64(len(p)))
+	return append(out, p...)
+}
+
+// WriteFrame encodes a frame to w.
+func WriteFrame(w io.Writer, f *Frame) error {
+	_, err := w.Write(MarshalFrame(f))
+	return err
+}
+
+// ReadFrame reads the next frame from r.
+func ReadFrame(r *bufio.Reader) (*Frame, error) {
+	var header [len(magic) + 1]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	if string(header[:len(magic)]) != magic {
+		return nil, errors.New("spectate: not a frame stream")
+	}
+	if v := header[len(magic)]; v != Version {
+		return nil, fmt.Errorf("%w %d (want %d)", ErrVersion, v, Version)
+	}
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if n > maxPayload {
+		return nil, fmt.Errorf("spectate: frame of %d bytes exceeds %d", n, maxPayload)
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+	return decodePayload(payload)
+}
+
+// decoder reads uvarints from a payload, remembering the first error.
+type decoder struct {
+	buf []byte
+	err error
+}
+
+func (d *decoder) uint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		d.err = errors.New("spectate: truncated frame")
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+// count reads a length and checks the payload could hold that many items.
+func (d *decoder) count() int {
+	v := d.uint()
+	if v > uint64(len(d.buf)) {
+		d.err = errors.New("spectate: truncated frame")
+		return 0
+	}
+	return int(v)
+}
+
+func (d *decoder) bytes(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	b := d.buf[:n]
+	d.buf = d.buf[n:]
+	return b
+}
+
+// decodePayload decodes a version 1 payload.
+func decodePayload(p []byte) (*Frame, error) {
+	d := &decoder{buf: p}
+	f := &Frame{Seq: d.uint()}
+	f.Key = d.uint()&flagKey != 0
+	width, height := d.uint(), d.uint()
+	if d.err != nil {
+		return nil, d.err
+	}
+	if width == 0 || height == 0 || width > maxDimension || height > maxDimension {
+		return nil, fmt.Errorf("spectate: bad grid size %dx%d", width, height)
+	}
+	f.Width, f.Height = int(width), int(height)
+
+	lines := d.count()
+	for i := 0; i < lines && d.err == nil; i++ {
+		f.HUD = append(f.HUD, string(d.bytes(d.count())))
+	}
+
+	runs := d.count()
+	for i := 0; i < runs && d.err == nil; i++ {
+		run := Run{X: int(d.uint()), Y: int(d.uint())}
+		cells := d.count()
+		style := tcell.StyleDefault
+		for j := 0; j < cells && d.err == nil; j++ {
+			c := Cell{Rune: rune(d.uint())}
+			switch marker := d.bytes(min(1, len(d.buf))); {
+			case len(marker) == 0:
+				d.err = errors.New("spectate: truncated frame")
+			case marker[0] == styleNew:
+				fg, bg, attrs := d.uint(), d.uint(), d.uint()
+				style = tcell.StyleDefault.
+					Foreground(tcell.Color(fg)).
+					Background(tcell.Color(bg)).
+					Attributes(tcell.AttrMask(attrs))
+			case marker[0] != styleSame || j == 0:
+				d.err = fmt.Errorf("spectate: bad style marker %d", marker[0])
+			}
+			c.Style = style
+			run.Cells = append(run.Cells, c)
+		}
+		f.Runs = append(f.Runs, run)
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return f, nil
+}
@@ -0,0 +1,100 @@
+package spectate
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestFrameRoundTrip(t *testing.T) {
+	bold := tcell.StyleDefault.Foreground(tcell.NewRGBColor(255, 200, 0)).Bold(true)
+	g := gridOf("#..#", "#..#")
+	g.Set(1, 0, Cell{Rune: '@', Style: bold})
+	g.Set(2, 0, Cell{Rune: '—', Style: bold})
+	want := KeyFrame(7, g, []string{"DungeonBand — Floor 1", "You feel watched."})
+
+	var buf bytes.Buffer
+	if err := WriteFrame(&buf, want); err != nil {
+		t.Fatalf("WriteFrame: %v", err)
+	}
+	got, err := ReadFrame(bufio.NewReader(&buf))
+	if err != nil {
+		t.Fatalf("ReadFrame: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip:\n got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestReadFrameRejectsUnknownVersion(t *testing.T) {
+	data := MarshalFrame(KeyFrame(1, gridOf("ab"), nil))
+	data[len(magic)] = Version + 1
+
+	_, err := ReadFrame(bufio.NewReader(bytes.NewReader(data)))
+	if !errors.Is(err, ErrVersion) {
+		t.Errorf("err = %v, want ErrVersion", err)
+	}
+}
+
+func TestReadFrameRejectsTruncatedPayload(t *testing.T) {
+	data := MarshalFrame(KeyFrame(1, gridOf("abcd", "efgh"), nil))
+	// Re-frame the first half of the payload so the decoder runs out of
+	// bytes mid-grid. The payload is short enough for a one-byte length.
+	header := len(magic) + 1
+	payload := data[header+1:]
+	short := append(data[:header:header], byte(len(payload)/2))
+	short = append(short, payload[:len(payload)/2]...)
+
+	if _, err := ReadFrame(bufio.NewReader(bytes.NewReader(short))); err == nil {
+		t.Error("a truncated payload should fail to decode")
+	}
+}
+
+// payloadFrame wraps a raw payload in a version 1 frame header.
+func payloadFrame(payload []byte) []byte {
+	data := append([]byte(magic), Version)
+	data = binary.AppendUvarint(data, uint64(len(payload)))
+	return append(data, payload...)
+}
+
+func TestReadFrameRejectsBadGridSizes(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height uint64
+	}{
+		{"zero width", 0, 3},
+		{"zero height", 3, 0},
+		{"wraps negative", 1 << 63, 2},
+		{"both wrap", math.MaxUint64, math.MaxUint64},
+		{"too wide", maxDimension + 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p []byte
+			for _, v := range []uint64{1, flagKey, tt.width, tt.height, 0, 0} {
+				p = binary.AppendUvarint(p, v)
+			}
+			if _, err := ReadFrame(bufio.NewReader(bytes.NewReader(payloadFrame(p)))); err == nil {
+				t.Errorf("%dx%d grid should be rejected", tt.width, tt.height)
+			}
+		})
+	}
+}
+
+func FuzzReadFrame(f *testing.F) {
+	f.Add(MarshalFrame(KeyFrame(1, gridOf("ab", "cd"), []string{"hud"})))
+	f.Add(payloadFrame(binary.AppendUvarint([]byte{1, 1}, math.MaxUint64)))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		frame, err := ReadFrame(bufio.NewReader(bytes.NewReader(data)))
+		if err != nil {
+			return
+		}
+		frame.Apply(nil)
+	})
+}
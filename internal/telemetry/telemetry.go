@@ -5,6 +5,7 @@ import (
 	"context"
 	"os"
 	"runtime"
+	"sync/atomic"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -71,6 +72,19 @@ func Tracer(name string) trace.Tracer {
 	return otel.GetTracerProvider().Tracer("dungeonband/" + name)
 }
 
+// verbose enables fine-grained telemetry such as per-room generation events.
+var verbose atomic.Bool
+
+// SetVerbose turns fine-grained telemetry on or off.
+func SetVerbose(enabled bool) {
+	verbose.Store(enabled)
+}
+
+// Verbose reports whether fine-grained telemetry is enabled.
+func Verbose() bool {
+	return verbose.Load()
+}
+
 // NoopTracer returns a no-op tracer for use when telemetry is disabled.
 func NoopTracer() trace.Tracer {
 	return noop.NewTracerProvider().Tracer("dungeonband/noop")
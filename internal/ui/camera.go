@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/world"
+)
+
+// combatPanelHeight is how many rows combat keeps free below the map for
+// the combat panel when the whole map doesn't fit above it.
+const combatPanelHeight = 12
+
+// view is the window of the world the map area shows: world (X, Y) is
+// drawn at the screen's top-left, and Width by Height cells are shown.
+type view struct {
+	X, Y          int
+	Width, Height int
+}
+
+// MapSize returns the size of the map area drawn in the last frame, for
+// placing overlays over it.
+func (r *Renderer) MapSize() (width, height int) {
+	return r.view.Width, r.view.Height
+}
+
+// setWorld draws a rune at a world position, if it's inside the view.
+func (r *Renderer) setWorld(x, y int, ch rune, style tcell.Style) {
+	sx, sy := x-r.view.X, y-r.view.Y
+	if sx < 0 || sy < 0 || sx >= r.view.Width || sy >= r.view.Height {
+		return
+	}
+	r.screen.SetContent(sx, sy, ch, style)
+}
+
+// mapView picks the view for a frame. Explore mode and combat on a
+// screen tall enough for the map and the panel show the whole map at
+// world coordinates. Otherwise combat keeps combatPanelHeight rows for the
+// panel, or half the screen if that leaves less, and centers the rest on
+// the fight.
+func (r *Renderer) mapView(dungeon *world.Dungeon, party *entity.Party, state GameState, info *CombatInfo) view {
+	whole := view{Width: dungeon.Width, Height: dungeon.Height}
+	if state != StateCombat {
+		return whole
+	}
+	screenWidth, screenHeight := r.screen.Size()
+	height := max(screenHeight-combatPanelHeight, screenHeight/2)
+	if dungeon.Width <= screenWidth && dungeon.Height <= height {
+		return whole
+	}
+
+	var focus []world.Point
+	for _, m := range party.Members {
+		if m.IsAlive() {
+			focus = append(focus, world.Point{X: m.X, Y: m.Y})
+		}
+	}
+	if info != nil {
+		for _, e := range info.Enemies {
+			if e.IsAlive() {
+				focus = append(focus, world.Point{X: e.X, Y: e.Y})
+			}
+		}
+	}
+	if len(focus) == 0 {
+		focus = append(focus, world.Point{X: party.X, Y: party.Y})
+	}
+	return centerView(focus, screenWidth, height)
+}
+
+// centerView returns a width by height view with the bounding box of the
+// focus points in its middle. It may extend past the map's edges, which
+// draw blank, so a fight by the wall still sits mid-screen.
+func centerView(focus []world.Point, width, height int) view {
+	minX, minY := focus[0].X, focus[0].Y
+	maxX, maxY := minX, minY
+	for _, p := range focus[1:] {
+		minX, maxX = min(minX, p.X), max(maxX, p.X)
+		minY, maxY = min(minY, p.Y), max(maxY, p.Y)
+	}
+	return view{
+		X:      (minX+maxX)/2 - width/2,
+		Y:      (minY+maxY)/2 - height/2,
+		Width:  width,
+		Height: height,
+	}
+}
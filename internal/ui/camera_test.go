@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/world"
+)
+
+func TestCombatCameraCentersFormationNearMapEdge(t *testing.T) {
+	r, sim := newTestRenderer(t)
+	const screenWidth, screenHeight = 40, 30
+	sim.SetSize(screenWidth, screenHeight)
+
+	// A map too big for the screen, with the fight against its left wall
+	d := world.NewDungeon(world.DefaultWidth, world.DefaultHeight, nil)
+	for y := 1; y < d.Height-1; y++ {
+		for x := 1; x < d.Width-1; x++ {
+			d.Tiles[y][x] = world.TileFloor
+		}
+	}
+	party := entity.NewParty(2, 12)
+	for i, m := range party.Members {
+		m.SetPosition(1+i%2, 11+i/2)
+	}
+	goblin := entity.NewEnemy(entity.EnemyGoblin, 5, 12, 0)
+	info := &CombatInfo{Phase: PhasePlayerTurn, ActiveMember: party.Members[0], Enemies: []*entity.Enemy{goblin}, Message: "Fight!"}
+
+	r.RenderWithCombat(d, party, []*entity.Enemy{goblin}, StateCombat, 0, info)
+
+	mapWidth, mapHeight := r.MapSize()
+	if mapHeight >= screenHeight {
+		t.Fatalf("map area is %d rows, want room kept for the panel", mapHeight)
+	}
+	// The formation spans world x 1..5 and y 11..12
+	centerX, centerY := 3-r.view.X, 11-r.view.Y
+	if off := centerX - mapWidth/2; off < -1 || off > 1 {
+		t.Errorf("formation center at column %d, want about %d", centerX, mapWidth/2)
+	}
+	if off := centerY - mapHeight/2; off < -1 || off > 1 {
+		t.Errorf("formation center at row %d, want about %d", centerY, mapHeight/2)
+	}
+	for _, m := range party.Members {
+		if got := cellAt(sim, m.X-r.view.X, m.Y-r.view.Y); got != m.Symbol {
+			t.Errorf("%s not drawn on screen, got %q", m.Name, got)
+		}
+	}
+	if got := cellAt(sim, goblin.X-r.view.X, goblin.Y-r.view.Y); got != goblin.Symbol {
+		t.Errorf("goblin not drawn on screen, got %q", got)
+	}
+
+	found := false
+	for y := mapHeight; y < screenHeight; y++ {
+		found = found || rowText(sim, y) == "Fight!"
+	}
+	if !found {
+		t.Error("combat message should be drawn in the panel below the map")
+	}
+}
+
+func TestExploreDrawsWholeMapAtWorldCoordinates(t *testing.T) {
+	r, sim := newTestRenderer(t)
+	d := dungeonFromMap(trailLayout)
+	party := entity.NewParty(2, 2)
+
+	r.Render(d, party, nil, StateExplore, 0)
+
+	if w, h := r.MapSize(); w != d.Width || h != d.Height {
+		t.Errorf("map size = %dx%d, want the whole %dx%d map", w, h, d.Width, d.Height)
+	}
+	if got := cellAt(sim, 2, 2); got != '&' {
+		t.Errorf("party at (2,2) = %q, want '&'", got)
+	}
+}
@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Choice is a modal menu: a title over numbered options with a cursor.
+// The game owns the Choice, feeds it keys with HandleKey and draws it with
+// Renderer.RenderChoice.
+type Choice struct {
+	Title    string
+	Options  []string
+	Selected int // Option under the cursor
+}
+
+// NewChoice creates a menu with the cursor on the first option.
+func NewChoice(title string, options ...string) *Choice {
+	return &Choice{Title: title, Options: options}
+}
+
+// HandleKey applies a key press. Arrows and j/k move the cursor, Enter or
+// Space picks the option under it and a number picks that option directly.
+// Escape cancels. done is true once the menu should close, with chosen the
+// picked option or -1 if cancelled.
+func (c *Choice) HandleKey(ev *tcell.EventKey) (chosen int, done bool) {
+	switch ev.Key() {
+	case tcell.KeyUp:
+		c.move(-1)
+	case tcell.KeyDown, tcell.KeyTab:
+		c.move(1)
+	case tcell.KeyEnter:
+		return c.Selected, true
+	case tcell.KeyEscape:
+		return -1, true
+	case tcell.KeyRune:
+		switch r := ev.Rune(); {
+		case r == 'k':
+			c.move(-1)
+		case r == 'j':
+			c.move(1)
+		case r == ' ':
+			return c.Selected, true
+		case r >= '1' && r <= '9' && int(r-'1') < len(c.Options):
+			return int(r - '1'), true
+		}
+	}
+	return 0, false
+}
+
+// move steps the cursor, wrapping around.
+func (c *Choice) move(dir int) {
+	if n := len(c.Options); n > 0 {
+		c.Selected = ((c.Selected+dir)%n + n) % n
+	}
+}
+
+// Summary returns the numbered options as one line of text, for displays
+// that can't draw the menu itself.
+func (c *Choice) Summary() string {
+	options := make([]string, len(c.Options))
+	for i, o := range c.Options {
+		options[i] = fmt.Sprintf("%d) %s", i+1, o)
+	}
+	return strings.Join(options, "  ")
+}
+
+// RenderChoice draws the menu in a box centered on the map.
+func (r *Renderer) RenderChoice(c *Choice, screenWidth, screenHeight int) {
+	lines := make([]string, len(c.Options))
+	width := len([]rune(c.Title))
+	for i, o := range c.Options {
+		lines[i] = fmt.Sprintf("%d) %s", i+1, o)
+		width = max(width, len([]rune(lines[i]))+2)
+	}
+	const footer = "(Enter choose, Esc leave)"
+	width = max(width, len(footer))
+	boxWidth := width + 4
+	x := max((screenWidth-boxWidth)/2, 0)
+	y := max(screenHeight/2-(len(lines)+4)/2, 0)
+
+	border := tcell.StyleDefault.Foreground(tcell.ColorFuchsia)
+	edge := "+" + strings.Repeat("-", boxWidth-2) + "+"
+	blank := "|" + strings.Repeat(" ", boxWidth-2) + "|"
+
+	r.renderText(x, y, edge, border)
+	r.renderText(x, y+1, blank, border)
+	r.renderText(x+2, y+1, c.Title, tcell.StyleDefault.Foreground(tcell.ColorWhite).Bold(true))
+	for i, line := range lines {
+		cursor, style := "  ", tcell.StyleDefault.Foreground(tcell.ColorWhite)
+		if i == c.Selected {
+			cursor, style = "> ", style.Foreground(tcell.ColorYellow).Bold(true)
+		}
+		r.renderText(x, y+2+i, blank, border)
+		r.renderText(x+2, y+2+i, cursor+line, style)
+	}
+	bottom := y + 2 + len(lines)
+	r.renderText(x, bottom, blank, border)
+	r.renderText(x+2, bottom, footer, tcell.StyleDefault.Foreground(tcell.ColorGray))
+	r.renderText(x, bottom+1, edge, border)
+
+	r.screen.Show()
+}
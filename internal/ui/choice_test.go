@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestChoiceKeys(t *testing.T) {
+	key := func(k tcell.Key) *tcell.EventKey { return tcell.NewEventKey(k, 0, tcell.ModNone) }
+	rn := func(r rune) *tcell.EventKey { return tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone) }
+
+	c := NewChoice("Pick", "a", "b", "c")
+	if _, done := c.HandleKey(key(tcell.KeyUp)); done || c.Selected != 2 {
+		t.Errorf("Up from the top should wrap to the last option, got %d", c.Selected)
+	}
+	c.HandleKey(rn('j'))
+	if chosen, done := c.HandleKey(key(tcell.KeyEnter)); !done || chosen != 0 {
+		t.Errorf("Enter = (%d, %v), want (0, true)", chosen, done)
+	}
+	if chosen, done := c.HandleKey(rn('2')); !done || chosen != 1 {
+		t.Errorf("'2' = (%d, %v), want (1, true)", chosen, done)
+	}
+	if _, done := c.HandleKey(rn('4')); done {
+		t.Error("a number past the last option should be ignored")
+	}
+	if chosen, done := c.HandleKey(key(tcell.KeyEscape)); !done || chosen != -1 {
+		t.Errorf("Esc = (%d, %v), want (-1, true)", chosen, done)
+	}
+}
+
+func TestRenderChoiceMarksSelection(t *testing.T) {
+	r, sim := newTestRenderer(t)
+	c := NewChoice("Pick one", "Apples", "Pears")
+	c.Selected = 1
+
+	r.RenderChoice(c, 40, 10)
+
+	_, _, height := sim.GetContents()
+	var rows []string
+	for y := 0; y < height; y++ {
+		if row := rowText(sim, y); strings.Contains(row, "> 2) Pears") {
+			return
+		} else if row != "" {
+			rows = append(rows, row)
+		}
+	}
+	t.Errorf("selected option not marked:\n%s", strings.Join(rows, "\n"))
+}
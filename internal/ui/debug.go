@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/world"
+)
+
+// debugOverlayX is where the debug overlay starts on the top row, clear of
+// the state indicator.
+const debugOverlayX = 9
+
+// DebugInfo is what the debug overlay shows, for bug reports.
+type DebugInfo struct {
+	X, Y    int           // Party's tile
+	Room    int           // Room the party is in, or -1 outside every room
+	Enemies int           // Living enemies left on the floor
+	Draws   uint64        // Values drawn from the run's RNG so far
+	Frame   time.Duration // How long the last frame took to draw
+}
+
+// NewDebugInfo reads the party's position and the floor's living enemy
+// count. Enemies killed in a fight still in progress aren't counted. The
+// caller fills in the RNG and timing figures.
+func NewDebugInfo(dungeon *world.Dungeon, party *entity.Party, enemies []*entity.Enemy) DebugInfo {
+	living := 0
+	for _, e := range enemies {
+		if e.IsAlive() {
+			living++
+		}
+	}
+	return DebugInfo{
+		X:       party.X,
+		Y:       party.Y,
+		Room:    dungeon.RoomIndexAt(party.X, party.Y),
+		Enemies: living,
+	}
+}
+
+// String formats the overlay's line.
+func (d DebugInfo) String() string {
+	room := "-"
+	if d.Room >= 0 {
+		room = fmt.Sprint(d.Room)
+	}
+	return fmt.Sprintf("x:%d y:%d room:%s enemies:%d rng:%d frame:%s",
+		d.X, d.Y, room, d.Enemies, d.Draws, d.Frame.Round(10*time.Microsecond))
+}
+
+// RenderDebug draws the debug overlay along the top row, between the state
+// indicator and the seed.
+func (r *Renderer) RenderDebug(info DebugInfo) {
+	style := tcell.StyleDefault.Foreground(tcell.ColorDarkGray)
+	for i, ch := range info.String() {
+		r.screen.SetContent(debugOverlayX+i, 0, ch, style)
+	}
+	r.screen.Show()
+}
@@ -0,0 +1,179 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+)
+
+// hudMPPips is how many pips show a member's MP in the party HUD.
+const hudMPPips = 3
+
+// statusIcons are the one-character marks the party HUD shows for status
+// effects: buffs in capitals, debuffs in lower case or as a sigil.
+var statusIcons = map[gamedata.StatusEffectType]rune{
+	gamedata.StatusPoison:      '!',
+	gamedata.StatusRegen:       '+',
+	gamedata.StatusDefenseUp:   'D',
+	gamedata.StatusDefenseDown: 'd',
+	gamedata.StatusAttackUp:    'A',
+	gamedata.StatusAttackDown:  'a',
+	gamedata.StatusSilence:     'x',
+	gamedata.StatusTaunt:       'T',
+	gamedata.StatusHaste:       'H',
+	gamedata.StatusSlow:        's',
+	gamedata.StatusDelay:       'z',
+}
+
+// hudDetail is how much the party HUD shows of each member.
+type hudDetail struct {
+	bar      int  // HP bar width, 0 for no bar
+	mp       bool // MP pips
+	statuses bool // Status icons
+}
+
+// hudDetails are the HUD's layouts from most to least detailed. The first
+// that fits the screen is used, so a narrow terminal loses the MP pips and
+// status icons before the HP bars, and keeps the initials to the last.
+var hudDetails = []hudDetail{
+	{bar: 5, mp: true, statuses: true},
+	{bar: 3, mp: true, statuses: true},
+	{bar: 3, statuses: true},
+	{bar: 3},
+	{},
+}
+
+// hudSpan is a run of HUD text in one style.
+type hudSpan struct {
+	Text  string
+	Style tcell.Style
+}
+
+// renderPartyHUD draws every member's initial, HP bar, MP pips and status
+// icons on one row, at the most detail that fits the screen's width.
+func (r *Renderer) renderPartyHUD(y int, party *entity.Party) {
+	width, _ := r.screen.Size()
+	var spans []hudSpan
+	for _, detail := range hudDetails {
+		spans = partyHUD(party, detail)
+		if hudWidth(spans) <= width {
+			break
+		}
+	}
+	x := 0
+	for _, span := range spans {
+		r.renderText(x, y, span.Text, span.Style)
+		x += len([]rune(span.Text))
+	}
+	r.hudEnd = x
+}
+
+// RunStatus is the run's turn count and play time, shown at the right end
+// of the party HUD.
+type RunStatus struct {
+	Turns   int
+	Elapsed time.Duration
+}
+
+// String formats the status for speedrun timing, e.g. "T:142 03:07.4".
+func (s RunStatus) String() string {
+	return fmt.Sprintf("T:%d %s", s.Turns, FormatRunTime(s.Elapsed))
+}
+
+// FormatRunTime formats play time to the tenth of a second, as mm:ss.t
+// under an hour and h:mm:ss.t from then on, e.g. "03:07.4".
+func FormatRunTime(d time.Duration) string {
+	tenths := int64(d / (100 * time.Millisecond))
+	h, m := tenths/36000, tenths/600%60
+	s, t := tenths/10%60, tenths%10
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d.%d", h, m, s, t)
+	}
+	return fmt.Sprintf("%02d:%02d.%d", m, s, t)
+}
+
+// RenderRunStatus right-aligns the run status on the party HUD's row, y.
+// It is left out when it would run into the party's entries.
+func (r *Renderer) RenderRunStatus(status RunStatus, y int) {
+	text := status.String()
+	width, _ := r.screen.Size()
+	x := width - len(text)
+	if x <= r.hudEnd {
+		return
+	}
+	r.renderText(x, y, text, tcell.StyleDefault.Foreground(tcell.ColorDarkGray))
+	r.screen.Show()
+}
+
+// partyHUD lays out the HUD row at the given detail, members separated by
+// a space.
+func partyHUD(party *entity.Party, detail hudDetail) []hudSpan {
+	mpStyle := tcell.StyleDefault.Foreground(tcell.ColorBlue)
+	statusStyle := tcell.StyleDefault.Foreground(tcell.ColorFuchsia)
+
+	var spans []hudSpan
+	for i, m := range party.Members {
+		if i > 0 {
+			spans = append(spans, hudSpan{Text: " "})
+		}
+		text := string(memberInitial(m))
+		if detail.bar > 0 {
+			text += hpBar(m.HP, m.MaxHP, detail.bar)
+		}
+		spans = append(spans, hudSpan{Text: text, Style: memberStyle(m)})
+		if detail.mp && m.MaxMP > 0 {
+			spans = append(spans, hudSpan{Text: mpPips(m.MP, m.MaxMP, hudMPPips), Style: mpStyle})
+		}
+		if detail.statuses && m.IsAlive() {
+			if icons := statusIconText(m); icons != "" {
+				spans = append(spans, hudSpan{Text: icons, Style: statusStyle})
+			}
+		}
+	}
+	return spans
+}
+
+// hudWidth returns how many cells the spans take.
+func hudWidth(spans []hudSpan) int {
+	n := 0
+	for _, span := range spans {
+		n += len([]rune(span.Text))
+	}
+	return n
+}
+
+// memberInitial returns the first letter of the member's name, upper-cased.
+func memberInitial(m *entity.Member) rune {
+	for _, ch := range m.Name {
+		return unicode.ToUpper(ch)
+	}
+	return m.Symbol
+}
+
+// mpPips draws current/max MP as pips, e.g. "**.". Any MP left shows at
+// least one filled pip.
+func mpPips(current, maxMP, pips int) string {
+	filled := 0
+	if maxMP > 0 && current > 0 {
+		filled = min((current*pips+maxMP-1)/maxMP, pips)
+	}
+	return strings.Repeat("*", filled) + strings.Repeat(".", pips-filled)
+}
+
+// statusIconText returns an icon per active status effect, in the order
+// they were applied.
+func statusIconText(m *entity.Member) string {
+	var b strings.Builder
+	for _, e := range m.GetStatusEffects() {
+		if icon, ok := statusIcons[e.Type]; ok {
+			b.WriteRune(icon)
+		}
+	}
+	return b.String()
+}
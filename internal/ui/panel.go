@@ -0,0 +1,422 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+)
+
+// panelLine is one styled line of the combat panel.
+type panelLine struct {
+	Text  string
+	Style tcell.Style
+}
+
+// Styles shared by the panel's section headers and message lines.
+var (
+	headerStyle  = tcell.StyleDefault.Foreground(tcell.ColorGray)
+	messageStyle = tcell.StyleDefault.Foreground(tcell.ColorAqua)
+
+	environmentStyle = tcell.StyleDefault.Foreground(tcell.ColorTeal)
+)
+
+// blankLine separates panel sections when there's room.
+var blankLine = panelLine{}
+
+// combatPanelLines lays out the player-turn combat panel in at most height
+// rows of width cells. When the full layout doesn't fit it drops the
+// spacing and summarizes each row's enemies by kind, then packs the
+// abilities several to a line, and finally cuts what's left of the middle.
+// The banner and the combat message are always kept.
+func combatPanelLines(width, height int, info *CombatInfo) []panelLine {
+	if info == nil || info.ActiveMember == nil || height <= 0 {
+		return nil
+	}
+
+	banner := "YOUR TURN"
+	switch info.Phase {
+	case PhaseVictory:
+		banner = "VICTORY"
+	case PhaseDefeat:
+		banner = "DEFEAT"
+	}
+	top := panelLine{fmt.Sprintf("%s — %s | HP: %d/%d | MP: %d/%d",
+		banner, info.ActiveMember.Name,
+		info.ActiveMember.HP, info.ActiveMember.MaxHP,
+		info.ActiveMember.MP, info.ActiveMember.MaxMP,
+	), tcell.StyleDefault.Foreground(tcell.ColorYellow).Bold(true)}
+	var message []panelLine
+	if info.Message != "" {
+		message = []panelLine{{info.Message, messageStyle}}
+	}
+
+	actions := actionLines(info, false, width)
+	enemies := enemyLines(info, false)
+
+	// Roomy: the full layout, spaced out
+	full := []panelLine{blankLine, top}
+	if info.Environment != "" {
+		full = append(full, panelLine{info.Environment, environmentStyle})
+	}
+	if len(info.TurnOrder) > 0 {
+		full = append(full, panelLine{turnOrderText(info.TurnOrder), headerStyle})
+	}
+	full = append(full, actions...)
+	full = append(full, blankLine)
+	full = append(full, enemies...)
+	if message != nil {
+		full = append(full, blankLine, message[0])
+	}
+	if len(full) <= height {
+		return full
+	}
+
+	// Tight: no spacing, and each row's enemies summarized by kind
+	enemies = enemyLines(info, true)
+	if 1+len(actions)+len(enemies)+len(message) > height {
+		// Pack the abilities several to a line
+		actions = actionLines(info, true, width)
+	}
+	middle := append(actions, enemies...)
+
+	// Cramped: cut the middle, marking the cut
+	room := height - 1 - len(message)
+	if room < 0 {
+		// Only one row: the message wins over the banner
+		return message
+	}
+	if len(middle) > room {
+		middle = middle[:room]
+		if room > 0 {
+			middle[room-1] = panelLine{"…", headerStyle}
+		}
+	}
+	lines := append([]panelLine{top}, middle...)
+	return append(lines, message...)
+}
+
+// turnOrderText lists who acts next, e.g. "Next: Mira, Mira», Goblin«".
+// » marks a hasted extra action and « a slowed combatant.
+func turnOrderText(order []TurnSlot) string {
+	names := make([]string, len(order))
+	for i, s := range order {
+		names[i] = s.Name
+		if s.Extra {
+			names[i] += "»"
+		}
+		if s.Slowed {
+			names[i] += "«"
+		}
+	}
+	return "Next: " + strings.Join(names, ", ")
+}
+
+// actionLines returns the ability list, or the ally list while aiming an
+// ally ability, with its header. Packed puts several abilities to a line,
+// and while aiming at an enemy leaves them out for the enemy list.
+func actionLines(info *CombatInfo, packed bool, width int) []panelLine {
+	if info.TargetAllies != nil {
+		return allyTargetLines(info)
+	}
+	aiming := panelLine{fmt.Sprintf("--- %s → %s: choose target (arrows/jk or 1-9, Tab allies, Enter confirm, Backspace undo) ---", info.TargetAbility, info.TargetRange), headerStyle}
+	if packed && info.TargetAbility != "" {
+		// The ability is already chosen
+		return []panelLine{aiming}
+	}
+
+	pages := AbilityPages(len(info.Abilities))
+	keys := "press 1-9 to select"
+	if pages > 1 {
+		keys += ", 0 or Tab for next page"
+	}
+	if info.QuickHeal {
+		keys += ", H to quick heal"
+	}
+	if info.Rewinds > 0 {
+		keys += fmt.Sprintf(", u to rewind (%d left)", info.Rewinds)
+	}
+	header := fmt.Sprintf("--- Abilities (%s) ---", keys)
+	if pages > 1 {
+		header = fmt.Sprintf("--- Abilities page %d/%d (%s) ---", info.AbilityPage+1, pages, keys)
+	}
+	lines := []panelLine{{header, headerStyle}}
+	if packed {
+		lines = append(lines, packedAbilityLines(info, width)...)
+	} else {
+		for i, ability := range pageAbilities(info) {
+			lines = append(lines, panelLine{abilityText(i, ability), abilityStyle(ability)})
+		}
+	}
+	if info.TargetAbility != "" {
+		lines = append(lines, aiming)
+	}
+	return lines
+}
+
+// pageAbilities returns the abilities on the current page, the ones the
+// number keys select.
+func pageAbilities(info *CombatInfo) []AbilityInfo {
+	first := info.AbilityPage * AbilitiesPerPage
+	if first < 0 || first >= len(info.Abilities) {
+		return nil
+	}
+	return info.Abilities[first:min(first+AbilitiesPerPage, len(info.Abilities))]
+}
+
+// abilityText returns an ability's entry, e.g. "[3] Fireball* (4 MP)".
+func abilityText(i int, ability AbilityInfo) string {
+	text := fmt.Sprintf("[%d] %s%s", i+1, ability.Name, strings.Repeat("*", ability.Mastery))
+	if ability.MPCost > 0 {
+		text += fmt.Sprintf(" (%d MP)", ability.MPCost)
+	}
+	if ability.Reason != "" {
+		text += " - " + ability.Reason
+	}
+	return text
+}
+
+// abilityStyle dims abilities the member can't use.
+func abilityStyle(ability AbilityInfo) tcell.Style {
+	if !ability.CanUse {
+		return tcell.StyleDefault.Foreground(tcell.ColorDarkGray)
+	}
+	return tcell.StyleDefault.Foreground(tcell.ColorWhite)
+}
+
+// packedStyle draws packed ability lines, which mix usable and unusable
+// abilities.
+var packedStyle = tcell.StyleDefault.Foreground(tcell.ColorWhite)
+
+// packedAbilityLines fits as many ability entries on each line as the
+// width allows. Unusable abilities still show their reason.
+func packedAbilityLines(info *CombatInfo, width int) []panelLine {
+	var lines []panelLine
+	var line []string
+	used := 0
+	for i, ability := range pageAbilities(info) {
+		text := abilityText(i, ability)
+		n := len([]rune(text))
+		if len(line) > 0 && used+2+n > width {
+			lines = append(lines, panelLine{strings.Join(line, "  "), packedStyle})
+			line, used = nil, 0
+		}
+		if len(line) > 0 {
+			used += 2
+		}
+		line = append(line, text)
+		used += n
+	}
+	if len(line) > 0 {
+		lines = append(lines, panelLine{strings.Join(line, "  "), packedStyle})
+	}
+	return lines
+}
+
+// allyTargetLines lists the selectable allies for a single-ally ability.
+func allyTargetLines(info *CombatInfo) []panelLine {
+	header := fmt.Sprintf("--- %s → %s: choose target (arrows/jk, Tab enemies, Enter confirm, Backspace undo) ---", info.TargetAbility, info.TargetRange)
+	lines := []panelLine{{header, headerStyle}}
+	for _, member := range info.TargetAllies {
+		cursor := "  "
+		style := tcell.StyleDefault.Foreground(tcell.ColorWhite)
+		if member == info.TargetMember {
+			cursor = "> "
+			style = style.Foreground(tcell.ColorYellow).Bold(true)
+		}
+
+		line := fmt.Sprintf("%s%s HP: %d/%d", cursor, member.Name, member.HP, member.MaxHP)
+		if statuses := formatStatuses(member); statuses != "" {
+			line += " " + statuses
+		}
+		if member == info.TargetMember && info.InvalidTarget {
+			line += " (invalid target)"
+			style = invalidTargetStyle
+		}
+		lines = append(lines, panelLine{line, style})
+	}
+	return lines
+}
+
+// enemyRows are the formation rows in the order the enemy list shows them.
+var enemyRows = []struct {
+	row    gamedata.Row
+	header string
+}{
+	{gamedata.RowFront, "--- Enemies: Front ---"},
+	{gamedata.RowBack, "--- Enemies: Back ---"},
+}
+
+// rowEnemies returns the living enemies in the given row, in fight order.
+func rowEnemies(enemies []*entity.Enemy, row gamedata.Row) []*entity.Enemy {
+	var inRow []*entity.Enemy
+	for _, enemy := range enemies {
+		if enemy.IsAlive() && enemy.Row == row {
+			inRow = append(inRow, enemy)
+		}
+	}
+	return inRow
+}
+
+// EnemyDisplayOrder returns the living enemies in the order the enemy list
+// shows them: the front row, then the back row. Number keys pick enemies
+// in this order.
+func EnemyDisplayOrder(enemies []*entity.Enemy) []*entity.Enemy {
+	var ordered []*entity.Enemy
+	for _, row := range enemyRows {
+		ordered = append(ordered, rowEnemies(enemies, row.row)...)
+	}
+	return ordered
+}
+
+// enemyLines lists the living enemies by formation row. Summarized lists
+// group each row's enemies by name, e.g. "3x Goblin 5/8 avg", keeping the
+// enemy under the target cursor on its own line.
+func enemyLines(info *CombatInfo, summarize bool) []panelLine {
+	var lines []panelLine
+	for _, row := range enemyRows {
+		enemies := rowEnemies(info.Enemies, row.row)
+		if len(enemies) == 0 {
+			continue
+		}
+		lines = append(lines, panelLine{row.header, headerStyle})
+		if summarize {
+			lines = append(lines, enemySummaryLines(info, enemies)...)
+			continue
+		}
+		for _, enemy := range enemies {
+			lines = append(lines, enemyLine(info, enemy))
+		}
+	}
+	return lines
+}
+
+// enemyLine describes one enemy. While aiming it adds the target cursor and
+// the number key that picks the enemy.
+func enemyLine(info *CombatInfo, enemy *entity.Enemy) panelLine {
+	cursor := ""
+	if info.TargetEnemy != nil {
+		cursor = "  "
+		if enemy == info.TargetEnemy {
+			cursor = "> "
+		}
+		if n := aliveEnemyNumber(info, enemy); n <= 9 {
+			cursor += fmt.Sprintf("%d) ", n)
+		}
+	}
+	text := fmt.Sprintf("%s%s HP: %d/%d", cursor, enemy.Name, enemy.HP, enemy.MaxHP)
+	style := tcell.StyleDefault.Foreground(enemy.Color())
+	if info.UnreachableEnemies[enemy] {
+		text += " (can't reach)"
+		style = tcell.StyleDefault.Foreground(tcell.ColorDarkGray)
+	}
+	if enemy == info.TargetEnemy {
+		style = style.Bold(true).Reverse(true)
+		if info.InvalidTarget {
+			text += " (invalid target)"
+			style = invalidTargetStyle
+		}
+	}
+	return panelLine{text, style}
+}
+
+// aliveEnemyNumber returns the enemy's 1-based position in the enemy list,
+// which is the number key that targets it.
+func aliveEnemyNumber(info *CombatInfo, enemy *entity.Enemy) int {
+	return slices.Index(EnemyDisplayOrder(info.Enemies), enemy) + 1
+}
+
+// enemySummaryLines groups a row's enemies by name and reach, in order of
+// first appearance. The targeted enemy keeps its own line.
+func enemySummaryLines(info *CombatInfo, enemies []*entity.Enemy) []panelLine {
+	type group struct {
+		first     *entity.Enemy
+		count     int
+		hp, maxHP int
+	}
+	var groups []*group
+	index := make(map[string]*group)
+	var lines []panelLine
+	for _, enemy := range enemies {
+		if enemy == info.TargetEnemy {
+			lines = append(lines, enemyLine(info, enemy))
+			continue
+		}
+		key := fmt.Sprintf("%s/%t", enemy.Name, info.UnreachableEnemies[enemy])
+		g := index[key]
+		if g == nil {
+			g = &group{first: enemy}
+			index[key] = g
+			groups = append(groups, g)
+		}
+		g.count++
+		g.hp += enemy.HP
+		g.maxHP += enemy.MaxHP
+	}
+	for _, g := range groups {
+		if g.count == 1 {
+			lines = append(lines, enemyLine(info, g.first))
+			continue
+		}
+		text := fmt.Sprintf("%dx %s %d/%d avg", g.count, g.first.Name, g.hp/g.count, g.maxHP/g.count)
+		style := tcell.StyleDefault.Foreground(g.first.Color())
+		if info.UnreachableEnemies[g.first] {
+			text += " (can't reach)"
+			style = tcell.StyleDefault.Foreground(tcell.ColorDarkGray)
+		}
+		lines = append(lines, panelLine{text, style})
+	}
+	return lines
+}
+
+// enemyPhaseLines lays out the read-only enemy-phase panel in at most
+// height rows: a banner naming the acting enemy, its recent actions and
+// the party roster with HP bars. Short panels drop the spacing, then the
+// oldest actions, then the end of the roster.
+func enemyPhaseLines(height int, party *entity.Party, info *CombatInfo) []panelLine {
+	if height <= 0 {
+		return nil
+	}
+	banner := "ENEMY TURN"
+	if info.ActingEnemy != nil {
+		banner += " — " + info.ActingEnemy.Name + " is acting…"
+	}
+	top := panelLine{banner, tcell.StyleDefault.Foreground(tcell.ColorRed).Bold(true)}
+
+	actions := info.EnemyActions
+	if len(actions) > recentActionLimit {
+		actions = actions[len(actions)-recentActionLimit:]
+	}
+	roster := []panelLine{{"--- Party ---", headerStyle}}
+	for _, m := range party.Members {
+		line := fmt.Sprintf("%-8s %s %d/%d", m.Name, hpBar(m.HP, m.MaxHP, hpBarWidth), m.HP, m.MaxHP)
+		roster = append(roster, panelLine{line, memberStyle(m)})
+	}
+	actionLines := func(actions []string) []panelLine {
+		lines := []panelLine{{"--- Enemy actions ---", headerStyle}}
+		for _, action := range actions {
+			lines = append(lines, panelLine{action, messageStyle})
+		}
+		return lines
+	}
+
+	full := append([]panelLine{blankLine, top}, actionLines(actions)...)
+	full = append(full, blankLine)
+	full = append(full, roster...)
+	if len(full) <= height {
+		return full
+	}
+
+	// Keep the newest actions that fit beside the roster
+	keep := max(height-2-len(roster), 1)
+	if len(actions) > keep {
+		actions = actions[len(actions)-keep:]
+	}
+	lines := append([]panelLine{top}, actionLines(actions)...)
+	lines = append(lines, roster...)
+	return lines[:min(len(lines), height)]
+}
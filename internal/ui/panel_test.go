@@ -0,0 +1,163 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+)
+
+// bigEncounter is a 6-goblin fight for a member with 7 abilities.
+func bigEncounter() *CombatInfo {
+	party := entity.NewParty(0, 0)
+	info := &CombatInfo{Phase: PhasePlayerTurn, ActiveMember: party.Members[0], Message: "Aldric attacks!"}
+	for i := range 7 {
+		info.Abilities = append(info.Abilities, AbilityInfo{Name: fmt.Sprintf("Skill%d", i+1), CanUse: true})
+	}
+	for i := range 6 {
+		info.Enemies = append(info.Enemies, entity.NewEnemy(entity.EnemyGoblin, i, 0, 0))
+	}
+	return info
+}
+
+// texts returns the text of each line.
+func texts(lines []panelLine) []string {
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		out[i] = l.Text
+	}
+	return out
+}
+
+// countContaining returns how many lines contain the substring.
+func countContaining(lines []string, sub string) int {
+	n := 0
+	for _, l := range lines {
+		if strings.Contains(l, sub) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestCombatPanelLayout(t *testing.T) {
+	tests := []struct {
+		name   string
+		height int
+		check  func(t *testing.T, lines []string)
+	}{
+		{"tall terminal lists everything", 40, func(t *testing.T, lines []string) {
+			if n := countContaining(lines, "Goblin HP:"); n != 6 {
+				t.Errorf("%d goblin lines, want 6", n)
+			}
+			if n := countContaining(lines, "] Skill"); n != 7 {
+				t.Errorf("%d ability lines, want 7", n)
+			}
+		}},
+		{"cramped terminal summarizes and packs", 8, func(t *testing.T, lines []string) {
+			if n := countContaining(lines, "6x Goblin"); n != 1 {
+				t.Errorf("want one goblin summary line, got:\n%s", strings.Join(lines, "\n"))
+			}
+			if n := countContaining(lines, "[7] Skill7"); n != 1 {
+				t.Errorf("every ability should still show, got:\n%s", strings.Join(lines, "\n"))
+			}
+		}},
+		{"tiny terminal cuts the middle", 4, func(t *testing.T, lines []string) {
+			if lines[len(lines)-2] != "…" {
+				t.Errorf("want the cut marked before the message, got:\n%s", strings.Join(lines, "\n"))
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := texts(combatPanelLines(80, tt.height, bigEncounter()))
+			if len(lines) > tt.height {
+				t.Fatalf("%d lines for a %d-row panel", len(lines), tt.height)
+			}
+			if !strings.HasPrefix(strings.TrimSpace(strings.Join(lines, "\n")), "YOUR TURN") {
+				t.Errorf("banner should lead the panel, got:\n%s", strings.Join(lines, "\n"))
+			}
+			if lines[len(lines)-1] != "Aldric attacks!" {
+				t.Errorf("last line = %q, want the combat message", lines[len(lines)-1])
+			}
+			tt.check(t, lines)
+		})
+	}
+
+	if lines := texts(combatPanelLines(80, 1, bigEncounter())); len(lines) != 1 || lines[0] != "Aldric attacks!" {
+		t.Errorf("one-row panel = %q, want just the message", lines)
+	}
+}
+
+func TestCrampedPanelKeepsTargetOnItsOwnLine(t *testing.T) {
+	info := bigEncounter()
+	info.TargetEnemy = info.Enemies[2]
+	info.TargetAbility = "Skill1"
+
+	lines := texts(combatPanelLines(80, 8, info))
+
+	if n := countContaining(lines, "5x Goblin"); n != 1 {
+		t.Errorf("want the other five summarized, got:\n%s", strings.Join(lines, "\n"))
+	}
+	if n := countContaining(lines, "> 3) Goblin HP:"); n != 1 {
+		t.Errorf("want the target on its own line, got:\n%s", strings.Join(lines, "\n"))
+	}
+}
+
+func TestCrampedEnemyPhaseKeepsRosterAndNewestAction(t *testing.T) {
+	party := entity.NewParty(0, 0)
+	info := &CombatInfo{Phase: PhaseEnemyTurn}
+	for i := range 5 {
+		info.EnemyActions = append(info.EnemyActions, fmt.Sprintf("Goblin %d attacks!", i+1))
+	}
+
+	lines := texts(enemyPhaseLines(8, party, info))
+
+	if len(lines) != 8 {
+		t.Fatalf("%d lines for an 8-row panel:\n%s", len(lines), strings.Join(lines, "\n"))
+	}
+	if countContaining(lines, "Goblin 5 attacks!") != 1 || countContaining(lines, "Goblin 4 attacks!") != 0 {
+		t.Errorf("want only the newest action, got:\n%s", strings.Join(lines, "\n"))
+	}
+	for _, m := range party.Members {
+		if countContaining(lines, m.Name) != 1 {
+			t.Errorf("roster is missing %s", m.Name)
+		}
+	}
+}
+
+func TestAbilityPanelPagesPastNine(t *testing.T) {
+	info := bigEncounter()
+	info.Abilities = nil
+	for i := range 12 {
+		info.Abilities = append(info.Abilities, AbilityInfo{Name: fmt.Sprintf("Skill%d", i+1), CanUse: true})
+	}
+
+	for _, packed := range []bool{false, true} {
+		first := strings.Join(texts(actionLines(info, packed, 200)), "\n")
+		if !strings.Contains(first, "page 1/2") || !strings.Contains(first, "[9] Skill9") || strings.Contains(first, "Skill10") {
+			t.Errorf("packed=%v first page:\n%s", packed, first)
+		}
+	}
+
+	info.AbilityPage = 1
+	second := strings.Join(texts(actionLines(info, false, 200)), "\n")
+	if !strings.Contains(second, "page 2/2") || !strings.Contains(second, "[1] Skill10") || !strings.Contains(second, "[3] Skill12") {
+		t.Errorf("second page:\n%s", second)
+	}
+}
+
+func TestMasteredAbilitiesAreStarred(t *testing.T) {
+	got := abilityText(2, AbilityInfo{Name: "Fireball", MPCost: 4, Mastery: 1})
+	if got != "[3] Fireball* (4 MP)" {
+		t.Errorf("abilityText = %q, want %q", got, "[3] Fireball* (4 MP)")
+	}
+}
+
+func TestTurnOrderMarksHasteAndSlow(t *testing.T) {
+	got := turnOrderText([]TurnSlot{{Name: "Mira"}, {Name: "Mira", Extra: true}, {Name: "Goblin", Slowed: true}})
+	if want := "Next: Mira, Mira», Goblin«"; got != want {
+		t.Errorf("turnOrderText = %q, want %q", got, want)
+	}
+}
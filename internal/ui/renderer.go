@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/gdamore/tcell/v2"
 
@@ -17,24 +18,99 @@ const (
 	StateCombat
 )
 
+// CombatPhase mirrors the game's combat phases for rendering purposes.
+type CombatPhase int
+
+const (
+	PhasePlayerTurn CombatPhase = iota
+	PhaseEnemyTurn
+	PhaseVictory
+	PhaseDefeat
+	PhaseSelectTarget
+)
+
+// recentActionLimit is how many enemy actions the enemy-phase panel lists.
+const recentActionLimit = 5
+
+// hpBarWidth is the width of the roster's HP bars, in cells.
+const hpBarWidth = 10
+
+// lowHPFraction is the HP fraction under which a member is drawn in the
+// danger color.
+const lowHPFraction = 0.3
+
+// invalidTargetStyle highlights a target the aimed ability can't be used on.
+var invalidTargetStyle = tcell.StyleDefault.Foreground(tcell.ColorRed).Bold(true).Reverse(true)
+
+// shrineGlyph marks a shrine on the map.
+const shrineGlyph = '_'
+
+// corpseGlyph marks a slain enemy's corpse on the map.
+const corpseGlyph = '%'
+
+// deadGlyph marks a fallen member's tile in the combat formation.
+const deadGlyph = '%'
+
 // AbilityInfo holds display information for an ability in the combat UI.
 type AbilityInfo struct {
 	Name   string
 	MPCost int
-	CanUse bool // false if not enough MP
+	CanUse bool   // false if not enough MP
+	Reason string // Why the ability can't be used (e.g. "Silenced!"), if any
+
+	// Mastery is the member's mastery tier for the ability, shown as a star
+	// per tier after its name.
+	Mastery int
+}
+
+// TurnSlot is one upcoming action in the round's turn order.
+type TurnSlot struct {
+	Name   string
+	Extra  bool // A hasted combatant's extra action
+	Slowed bool // Pushed to the end of the round by slow
+}
+
+// AbilitiesPerPage is how many abilities fit on the 1-9 keys at once.
+const AbilitiesPerPage = 9
+
+// AbilityPages returns how many pages n abilities take, at least one.
+func AbilityPages(n int) int {
+	return max((n+AbilitiesPerPage-1)/AbilitiesPerPage, 1)
 }
 
 // CombatInfo holds all information needed to render the combat UI.
 type CombatInfo struct {
-	ActiveMember *entity.Member  // The party member whose turn it is
-	Abilities    []AbilityInfo   // Available abilities for the active member
-	Enemies      []*entity.Enemy // Enemies in combat
-	Message      string          // Current combat message
+	Phase        CombatPhase
+	ActiveMember *entity.Member       // The party member whose turn it is
+	ActingEnemy  *entity.Enemy        // The enemy acting during the enemy phase
+	EnemyActions []string             // What enemies have done this enemy phase, oldest first
+	Abilities    []AbilityInfo        // Available abilities for the active member
+	AbilityPage  int                  // Page of Abilities on the number keys
+	Enemies      []*entity.Enemy      // Enemies in combat
+	Message      string               // Current combat message
+	TurnOrder    []TurnSlot           // Who acts after the active member this round
+	Environment  string               // How the room changes the fight, e.g. "Flooded chamber: fire damage halved"
+	QuickHeal    bool                 // The active member has a heal for the quick-heal key
+	Rewinds      int                  // Turns the party can still take back with the rewind key
+	Threatened   map[world.Point]bool // Tiles next to a melee enemy, tinted in positional fights
+
+	// Target selection (only set while choosing a target)
+	TargetAbility      string                 // Name of the ability being aimed
+	TargetRange        string                 // Who the ability can be aimed at, e.g. "front-row enemies"
+	InvalidTarget      bool                   // The ability can't be used on the target under the cursor
+	TargetAllies       []*entity.Member       // Selectable allies (ally abilities)
+	TargetMember       *entity.Member         // Ally under the cursor
+	TargetEnemy        *entity.Enemy          // Enemy under the cursor (offensive abilities)
+	UnreachableEnemies map[*entity.Enemy]bool // Enemies the ability can't reach
 }
 
 // Renderer handles drawing the game to the screen.
 type Renderer struct {
-	screen *Screen
+	screen       *Screen
+	showInitials bool             // Draw members by their initial instead of class symbol
+	visibility   world.Visibility // Which enemies the party sees ("" = by sight)
+	view         view             // Part of the world the map area shows this frame
+	hudEnd       int              // Column after the party HUD's last entry
 }
 
 // NewRenderer creates a new renderer for the given screen.
@@ -42,6 +118,12 @@ func NewRenderer(screen *Screen) *Renderer {
 	return &Renderer{screen: screen}
 }
 
+// SetShowInitials chooses whether the combat formation draws each member
+// by the first letter of their name rather than their class symbol.
+func (r *Renderer) SetShowInitials(show bool) {
+	r.showInitials = show
+}
+
 // Render draws the dungeon and party to the screen based on game state.
 func (r *Renderer) Render(dungeon *world.Dungeon, party *entity.Party, enemies []*entity.Enemy, state GameState, seed int64) {
 	r.RenderWithCombat(dungeon, party, enemies, state, seed, nil)
@@ -50,27 +132,31 @@ func (r *Renderer) Render(dungeon *world.Dungeon, party *entity.Party, enemies [
 // RenderWithCombat draws the game with optional combat UI information.
 func (r *Renderer) RenderWithCombat(dungeon *world.Dungeon, party *entity.Party, enemies []*entity.Enemy, state GameState, seed int64, combatInfo *CombatInfo) {
 	r.screen.Clear()
-
-	// Determine which room the party is in (for visibility)
-	partyRoomIndex := dungeon.RoomIndexAt(party.X, party.Y)
+	r.view = r.mapView(dungeon, party, state, combatInfo)
 
 	// Draw dungeon tiles
 	for y := 0; y < dungeon.Height; y++ {
 		for x := 0; x < dungeon.Width; x++ {
 			tile := dungeon.GetTile(x, y)
 			style := r.getTileStyle(tile)
-			r.screen.SetContent(x, y, tile.Rune(), style)
+			r.setWorld(x, y, tile.Rune(), style)
 		}
 	}
+	if state == StateCombat && combatInfo != nil {
+		r.renderThreat(dungeon, combatInfo.Threatened)
+	}
+
+	r.renderCorpses(dungeon)
+	r.renderShrines(dungeon)
 
-	// Draw enemies (only those in the same room as party)
-	r.renderEnemies(enemies, partyRoomIndex)
+	// Draw enemies (only those the party can see)
+	r.renderEnemies(dungeon, party, enemies)
 
 	// Draw party based on state
 	if state == StateCombat {
-		r.renderCombatFormation(dungeon, party, combatInfo)
+		r.renderCombatFormation(party, combatInfo)
 	} else {
-		r.renderExploreParty(party)
+		r.renderExploreParty(dungeon, party, enemies)
 	}
 
 	// Draw state indicator in top-left
@@ -79,126 +165,130 @@ func (r *Renderer) RenderWithCombat(dungeon *world.Dungeon, party *entity.Party,
 	// Draw seed in top-right
 	r.renderSeed(dungeon.Width, seed)
 
-	// Draw combat UI panel if in combat
+	// Draw combat UI panel if in combat, otherwise the party HUD on the
+	// row under the map and any lingering statuses below the message line
 	if state == StateCombat && combatInfo != nil {
-		r.renderCombatUI(dungeon.Height, combatInfo)
+		r.renderCombatUI(min(dungeon.Height, r.view.Height), party, combatInfo)
+	} else if state != StateCombat {
+		r.renderPartyHUD(dungeon.Height, party)
+		r.renderExploreStatuses(dungeon.Height+2, party)
 	}
 
 	r.screen.Show()
 }
 
-// renderExploreParty draws the party as a single symbol in explore mode.
-func (r *Renderer) renderExploreParty(party *entity.Party) {
+// renderExploreParty draws the party as a lead symbol in explore mode, with
+// the remaining living members trailing behind on recently visited tiles.
+func (r *Renderer) renderExploreParty(dungeon *world.Dungeon, party *entity.Party, enemies []*entity.Enemy) {
+	r.renderPartyTrail(dungeon, party, enemies)
+
 	partyStyle := tcell.StyleDefault.
 		Foreground(tcell.ColorYellow).
 		Bold(true)
-	r.screen.SetContent(party.X, party.Y, party.Symbol, partyStyle)
+	r.setWorld(party.X, party.Y, party.Symbol, partyStyle)
+	if scout := party.Scout; scout != nil && scout.IsAlive() {
+		r.setWorld(scout.X, scout.Y, scout.Symbol, memberStyle(scout).Bold(true))
+	}
 }
 
-// renderCombatFormation draws individual party members spread on tiles.
-func (r *Renderer) renderCombatFormation(dungeon *world.Dungeon, party *entity.Party, combatInfo *CombatInfo) {
-	// Find valid positions for formation around party position
-	positions := r.findFormationPositions(dungeon, party.X, party.Y, len(party.Members))
-
-	// Place members at positions
-	for i, member := range party.Members {
-		if i < len(positions) {
-			pos := positions[i]
-			member.SetPosition(pos.x, pos.y)
-			style := r.getMemberStyle(member.Class)
-
-			// Highlight active member
-			if combatInfo != nil && combatInfo.ActiveMember == member {
-				style = style.Background(tcell.ColorDarkBlue)
-			}
-
-			// Dim dead members
-			if !member.IsAlive() {
-				style = tcell.StyleDefault.Foreground(tcell.ColorDarkGray)
-			}
+// renderPartyTrail draws follower glyphs on the party's trail.
+// Trail tiles that are not passable or hold an enemy the party can see are
+// skipped, so a gap in the trail never gives away a hidden enemy.
+func (r *Renderer) renderPartyTrail(dungeon *world.Dungeon, party *entity.Party, enemies []*entity.Enemy) {
+	// The lead is the first living member; the rest follow in order
+	var followers []*entity.Member
+	for _, m := range party.Members {
+		if m.IsAlive() && m != party.Scout {
+			followers = append(followers, m)
+		}
+	}
+	if len(followers) > 0 {
+		followers = followers[1:]
+	}
 
-			r.screen.SetContent(pos.x, pos.y, member.Symbol, style)
+	for i, pt := range party.Trail {
+		if i >= len(followers) {
+			break
+		}
+		if !dungeon.IsPassable(pt.X, pt.Y) || r.visibleEnemyAt(dungeon, party, enemies, pt.X, pt.Y) {
+			continue
 		}
+		r.setWorld(pt.X, pt.Y, followers[i].Symbol, memberStyle(followers[i]))
 	}
 }
 
-// position represents a coordinate pair.
-type position struct {
-	x, y int
+// visibleEnemyAt returns true if a living enemy the party can see occupies
+// the position.
+func (r *Renderer) visibleEnemyAt(dungeon *world.Dungeon, party *entity.Party, enemies []*entity.Enemy, x, y int) bool {
+	for _, e := range enemies {
+		if e.IsAlive() && e.X == x && e.Y == y && r.partySees(dungeon, party, x, y) {
+			return true
+		}
+	}
+	return false
 }
 
-// findFormationPositions finds valid tiles for party members around center.
-// Tries 2x2 formation first, falls back to line formation in corridors.
-func (r *Renderer) findFormationPositions(dungeon *world.Dungeon, centerX, centerY, count int) []position {
-	// Priority order for 2x2 formation (relative to center):
-	// [0][1]  = NW, NE (front row - Warrior, Rogue)
-	// [2][3]  = SW, SE (back row - Wizard, Cleric)
-	offsets2x2 := []position{
-		{-1, 0}, {0, 0}, // Front row (same Y as party, left and center)
-		{-1, 1}, {0, 1}, // Back row (below party)
+// renderCombatFormation draws individual party members on their combat tiles.
+// Tiles shared by several living members are underlined, with the first of
+// them drawn on top. Fallen members are drawn as a corpse glyph.
+func (r *Renderer) renderCombatFormation(party *entity.Party, combatInfo *CombatInfo) {
+	stacked := make(map[[2]int]int)
+	for _, member := range party.Members {
+		if member.IsAlive() {
+			stacked[[2]int{member.X, member.Y}]++
+		}
 	}
 
-	// Try 2x2 formation
-	positions := make([]position, 0, count)
-	for _, off := range offsets2x2 {
-		x, y := centerX+off.x, centerY+off.y
-		if dungeon.IsPassable(x, y) {
-			positions = append(positions, position{x, y})
-			if len(positions) >= count {
-				return positions
+	// Draw in reverse so the first member on a tile ends up on top
+	for i := len(party.Members) - 1; i >= 0; i-- {
+		member := party.Members[i]
+		if !member.IsAlive() {
+			if stacked[[2]int{member.X, member.Y}] == 0 {
+				// Don't cover a living member with a fallen one
+				r.setWorld(member.X, member.Y, deadGlyph, memberStyle(member))
 			}
+			continue
+		}
+		style := memberStyle(member)
+		if stacked[[2]int{member.X, member.Y}] > 1 {
+			style = style.Underline(true)
 		}
-	}
-
-	// If we got enough positions, return them
-	if len(positions) >= count {
-		return positions
-	}
-
-	// Fall back to line formation - search in expanding rings
-	positions = r.findLineFormation(dungeon, centerX, centerY, count)
-	return positions
-}
 
-// findLineFormation finds positions in a line or scattered pattern.
-func (r *Renderer) findLineFormation(dungeon *world.Dungeon, centerX, centerY, count int) []position {
-	positions := make([]position, 0, count)
-	visited := make(map[position]bool)
+		// Highlight active member and current target
+		if combatInfo != nil && combatInfo.ActiveMember == member {
+			style = style.Background(tcell.ColorDarkBlue)
+		}
+		if combatInfo != nil && combatInfo.TargetMember == member {
+			style = style.Background(tcell.ColorDarkGreen)
+		}
 
-	// Start with center
-	if dungeon.IsPassable(centerX, centerY) {
-		positions = append(positions, position{centerX, centerY})
-		visited[position{centerX, centerY}] = true
+		r.setWorld(member.X, member.Y, r.memberGlyph(member), style)
 	}
+}
 
-	// Expand outward in cardinal directions first, then diagonals
-	directions := []position{
-		{0, -1}, {0, 1}, {-1, 0}, {1, 0}, // Cardinals
-		{-1, -1}, {1, -1}, {-1, 1}, {1, 1}, // Diagonals
+// memberGlyph returns the rune a living member is drawn with in combat: the
+// class symbol, or the member's initial when initials are on.
+func (r *Renderer) memberGlyph(member *entity.Member) rune {
+	if r.showInitials {
+		return memberInitial(member)
 	}
+	return member.Symbol
+}
 
-	for radius := 1; radius <= 3 && len(positions) < count; radius++ {
-		for _, dir := range directions {
-			x, y := centerX+dir.x*radius, centerY+dir.y*radius
-			pos := position{x, y}
-			if !visited[pos] && dungeon.IsPassable(x, y) {
-				positions = append(positions, pos)
-				visited[pos] = true
-				if len(positions) >= count {
-					return positions
-				}
-			}
-		}
+// memberStyle returns the style for a party member: dimmed if dead, the
+// danger color below lowHPFraction, otherwise their class color.
+func memberStyle(member *entity.Member) tcell.Style {
+	switch {
+	case !member.IsAlive():
+		return tcell.StyleDefault.Foreground(tcell.ColorDarkGray)
+	case float64(member.HP) < lowHPFraction*float64(member.MaxHP):
+		return tcell.StyleDefault.Foreground(tcell.ColorRed).Bold(true)
 	}
 
-	return positions
-}
-
-// getMemberStyle returns the style for a party member based on class.
-func (r *Renderer) getMemberStyle(class entity.Class) tcell.Style {
-	switch class {
+	// Red is reserved for danger, so no class uses it
+	switch member.Class {
 	case entity.ClassWarrior:
-		return tcell.StyleDefault.Foreground(tcell.ColorRed).Bold(true)
+		return tcell.StyleDefault.Foreground(tcell.ColorOrange).Bold(true)
 	case entity.ClassRogue:
 		return tcell.StyleDefault.Foreground(tcell.ColorGreen).Bold(true)
 	case entity.ClassWizard:
@@ -251,6 +341,8 @@ func (r *Renderer) getTileStyle(tile world.Tile) tcell.Style {
 		return tcell.StyleDefault.Foreground(tcell.ColorDarkGray)
 	case world.TileFloor:
 		return tcell.StyleDefault.Foreground(tcell.ColorGray)
+	case world.TileStairsDown:
+		return tcell.StyleDefault.Foreground(tcell.ColorWhite).Bold(true)
 	default:
 		return tcell.StyleDefault
 	}
@@ -264,85 +356,259 @@ func (r *Renderer) RenderMessage(msg string, y int) {
 	}
 }
 
-// renderEnemies draws enemies that are visible to the party.
-// Only enemies in the same room as the party are rendered.
-func (r *Renderer) renderEnemies(enemies []*entity.Enemy, partyRoomIndex int) {
-	for _, enemy := range enemies {
-		// Only show enemies in the same room as the party
-		if enemy.RoomIndex == partyRoomIndex && partyRoomIndex >= 0 {
-			style := tcell.StyleDefault.Foreground(enemy.Color())
-			r.screen.SetContent(enemy.X, enemy.Y, enemy.Symbol, style)
-		}
+// exploreFooterRows is how many rows explore mode needs below the map: the
+// party HUD and the message line.
+const exploreFooterRows = 2
+
+// minCombatViewRows is the fewest map rows worth showing above the combat
+// panel.
+const minCombatViewRows = 5
+
+// MinScreenSize returns the smallest screen a map of the given size can be
+// played on: the whole map plus the message line in explore mode, and the
+// combat panel under a few rows of the fight in combat.
+func MinScreenSize(mapWidth, mapHeight int) (width, height int) {
+	return mapWidth, max(mapHeight+exploreFooterRows, combatPanelHeight+minCombatViewRows)
+}
+
+// RenderTooSmall replaces the frame with a centered request to enlarge the
+// terminal to at least width by height cells.
+func (r *Renderer) RenderTooSmall(width, height int) {
+	r.screen.Clear()
+	screenWidth, screenHeight := r.screen.Size()
+	lines := []string{
+		"Please enlarge your terminal",
+		fmt.Sprintf("(need at least %dx%d)", width, height),
+	}
+	style := tcell.StyleDefault.Foreground(tcell.ColorYellow)
+	y := max(screenHeight/2-len(lines)/2, 0)
+	for i, line := range lines {
+		r.renderText(max((screenWidth-len(line))/2, 0), y+i, line, style)
 	}
+	r.screen.Show()
 }
 
-// renderCombatUI draws the combat UI panel below the dungeon.
-func (r *Renderer) renderCombatUI(startY int, info *CombatInfo) {
-	if info == nil || info.ActiveMember == nil {
-		return
+// RenderTitle draws the title screen offering a new run with the given seed.
+// suggestTutorial adds a hint pointing new players at the tutorial, and
+// canContinue offers to continue the saved run.
+func (r *Renderer) RenderTitle(seed int64, suggestTutorial, canContinue bool) {
+	r.screen.Clear()
+	width, height := r.screen.Size()
+
+	type titleLine struct {
+		text  string
+		style tcell.Style
+	}
+	keys := "Enter: new run   t: tutorial   p: party   r: new seed   q: quit"
+	if canContinue {
+		keys = "Enter: new run   c: continue   t: tutorial   p: party   r: new seed   q: quit"
+	}
+	lines := []titleLine{
+		{"D U N G E O N B A N D", tcell.StyleDefault.Foreground(tcell.ColorYellow).Bold(true)},
+		{"", tcell.StyleDefault},
+		{fmt.Sprintf("Seed: %d", seed), tcell.StyleDefault.Foreground(tcell.ColorWhite)},
+		{"", tcell.StyleDefault},
+		{keys, tcell.StyleDefault.Foreground(tcell.ColorGray)},
+	}
+	if suggestTutorial {
+		lines = append(lines, titleLine{"New here? Press t for a short tutorial.", tcell.StyleDefault.Foreground(tcell.ColorGreen)})
 	}
 
-	y := startY + 1
+	y := height/2 - len(lines)/2
+	for i, line := range lines {
+		x := (width - len([]rune(line.text))) / 2
+		if x < 0 {
+			x = 0
+		}
+		r.renderText(x, y+i, line.text, line.style)
+	}
+
+	r.screen.Show()
+}
 
-	// Draw active member info
-	memberLine := fmt.Sprintf("%s's turn | HP: %d/%d | MP: %d/%d",
-		info.ActiveMember.Name,
-		info.ActiveMember.HP, info.ActiveMember.MaxHP,
-		info.ActiveMember.MP, info.ActiveMember.MaxMP,
-	)
-	r.renderText(0, y, memberLine, tcell.StyleDefault.Foreground(tcell.ColorYellow).Bold(true))
-	y++
+// instructionWidth is the text width of the tutorial instruction panel.
+const instructionWidth = 50
+
+// RenderInstruction draws a boxed instruction panel centred on the map,
+// with the text word-wrapped and a hint that any key dismisses it.
+func (r *Renderer) RenderInstruction(title, text string, screenWidth, screenHeight int) {
+	lines := wrapText(text, instructionWidth)
+	boxWidth := instructionWidth + 4
+	x := max((screenWidth-boxWidth)/2, 0)
+	y := max(screenHeight/2-(len(lines)+4)/2, 0)
+
+	border := tcell.StyleDefault.Foreground(tcell.ColorGreen)
+	edge := "+" + strings.Repeat("-", boxWidth-2) + "+"
+	blank := "|" + strings.Repeat(" ", boxWidth-2) + "|"
+
+	r.renderText(x, y, edge, border)
+	r.renderText(x, y+1, blank, border)
+	r.renderText(x+2, y+1, title, tcell.StyleDefault.Foreground(tcell.ColorYellow).Bold(true))
+	for i, line := range lines {
+		r.renderText(x, y+2+i, blank, border)
+		r.renderText(x+2, y+2+i, line, tcell.StyleDefault.Foreground(tcell.ColorWhite))
+	}
+	footer := y + 2 + len(lines)
+	r.renderText(x, footer, blank, border)
+	r.renderText(x+2, footer, "(press any key)", tcell.StyleDefault.Foreground(tcell.ColorGray))
+	r.renderText(x, footer+1, edge, border)
 
-	// Draw separator
-	r.renderText(0, y, "--- Abilities (press 1-9 to select) ---", tcell.StyleDefault.Foreground(tcell.ColorGray))
-	y++
+	r.screen.Show()
+}
 
-	// Draw abilities
-	for i, ability := range info.Abilities {
-		if i >= 9 {
-			break // Only show first 9 abilities
+// wrapText splits text into lines of at most width runes, breaking at spaces.
+// Newlines in the text always start a new line.
+func wrapText(text string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			if line != "" && len([]rune(line))+1+len([]rune(word)) > width {
+				lines = append(lines, line)
+				line = ""
+			}
+			if line != "" {
+				line += " "
+			}
+			line += word
 		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// RenderPrompt draws a centered, boxed prompt over the current frame.
+func (r *Renderer) RenderPrompt(text string, screenWidth, screenHeight int) {
+	boxWidth := len([]rune(text)) + 4
+	x := (screenWidth - boxWidth) / 2
+	y := screenHeight/2 - 1
+	if x < 0 {
+		x = 0
+	}
+
+	border := tcell.StyleDefault.Foreground(tcell.ColorYellow)
+	r.renderText(x, y, "+"+strings.Repeat("-", boxWidth-2)+"+", border)
+	r.renderText(x, y+1, "| "+text+" |", border)
+	r.renderText(x, y+2, "+"+strings.Repeat("-", boxWidth-2)+"+", border)
+	r.renderText(x+2, y+1, text, tcell.StyleDefault.Foreground(tcell.ColorWhite).Bold(true))
+
+	r.screen.Show()
+}
 
-		var line string
-		if ability.MPCost > 0 {
-			line = fmt.Sprintf("[%d] %s (%d MP)", i+1, ability.Name, ability.MPCost)
-		} else {
-			line = fmt.Sprintf("[%d] %s", i+1, ability.Name)
+// renderCorpses draws the floor's corpses, dimmed once searched.
+func (r *Renderer) renderCorpses(dungeon *world.Dungeon) {
+	for _, c := range dungeon.Corpses {
+		style := tcell.StyleDefault.Foreground(tcell.ColorMaroon)
+		if c.Looted {
+			style = tcell.StyleDefault.Foreground(tcell.ColorDarkGray)
 		}
+		r.setWorld(c.X, c.Y, corpseGlyph, style)
+	}
+}
 
-		style := tcell.StyleDefault.Foreground(tcell.ColorWhite)
-		if !ability.CanUse {
+// renderShrines draws the floor's shrines, dimmed once spent.
+func (r *Renderer) renderShrines(dungeon *world.Dungeon) {
+	for _, shrine := range dungeon.Shrines {
+		style := tcell.StyleDefault.Foreground(tcell.ColorFuchsia).Bold(true)
+		if shrine.Used {
 			style = tcell.StyleDefault.Foreground(tcell.ColorDarkGray)
 		}
-		r.renderText(0, y, line, style)
-		y++
+		r.setWorld(shrine.X, shrine.Y, shrineGlyph, style)
 	}
+}
 
-	y++
+// SetEnemyVisibility sets the rule for which enemies are drawn.
+func (r *Renderer) SetEnemyVisibility(rule world.Visibility) {
+	r.visibility = rule
+}
 
-	// Draw enemies in combat
-	if len(info.Enemies) > 0 {
-		r.renderText(0, y, "--- Enemies ---", tcell.StyleDefault.Foreground(tcell.ColorGray))
-		y++
-		for _, enemy := range info.Enemies {
-			if enemy.IsAlive() {
-				enemyLine := fmt.Sprintf("%s HP: %d/%d", enemy.Name, enemy.HP, enemy.MaxHP)
-				r.renderText(0, y, enemyLine, tcell.StyleDefault.Foreground(enemy.Color()))
-				y++
-			}
+// renderEnemies draws enemies that are visible to the party or its scout,
+// by the renderer's visibility rule.
+func (r *Renderer) renderEnemies(dungeon *world.Dungeon, party *entity.Party, enemies []*entity.Enemy) {
+	for _, enemy := range enemies {
+		if r.partySees(dungeon, party, enemy.X, enemy.Y) {
+			style := tcell.StyleDefault.Foreground(enemy.Color())
+			r.setWorld(enemy.X, enemy.Y, enemy.Symbol, style)
+		}
+	}
+}
+
+// partySees returns true if the party or its scout sees (x, y) by the
+// renderer's visibility rule.
+func (r *Renderer) partySees(dungeon *world.Dungeon, party *entity.Party, x, y int) bool {
+	if dungeon.Sees(r.visibility, party.X, party.Y, x, y) {
+		return true
+	}
+	scout := party.Scout
+	return scout != nil && dungeon.Sees(r.visibility, scout.X, scout.Y, x, y)
+}
+
+// renderCombatUI draws the combat UI panel below the dungeon, topped by a
+// banner saying whose turn it is. The panel is laid out to fit the rows
+// left on the screen.
+func (r *Renderer) renderCombatUI(startY int, party *entity.Party, info *CombatInfo) {
+	if info == nil {
+		return
+	}
+	width, screenHeight := r.screen.Size()
+	height := screenHeight - startY
+
+	var lines []panelLine
+	if info.Phase == PhaseEnemyTurn {
+		// Enemies act on their own; show what they did instead of abilities
+		lines = enemyPhaseLines(height, party, info)
+	} else {
+		lines = combatPanelLines(width, height, info)
+	}
+	for i, line := range lines {
+		r.renderText(0, startY+i, line.Text, line.Style)
+	}
+}
+
+// hpBar draws current/max HP as a bar of the given width, e.g. "[#####-----]".
+// Any living member shows at least one filled cell.
+func hpBar(current, maxHP, width int) string {
+	filled := 0
+	if maxHP > 0 && current > 0 {
+		filled = max(current*width/maxHP, 1)
+	}
+	filled = min(filled, width)
+	return "[" + strings.Repeat("#", filled) + strings.Repeat("-", width-filled) + "]"
+}
+
+// renderExploreStatuses lists each living member's active status effects,
+// one line per afflicted member, below the explore-mode message line.
+func (r *Renderer) renderExploreStatuses(y int, party *entity.Party) {
+	style := tcell.StyleDefault.Foreground(tcell.ColorFuchsia)
+	for _, m := range party.Members {
+		if !m.IsAlive() {
+			continue
+		}
+		if statuses := formatStatuses(m); statuses != "" {
+			r.renderText(0, y, m.Name+" "+statuses, style)
+			y++
 		}
 	}
+}
 
-	// Draw combat message
-	if info.Message != "" {
-		y++
-		r.renderText(0, y, info.Message, tcell.StyleDefault.Foreground(tcell.ColorAqua))
+// formatStatuses returns a compact list of active status effects, e.g. "[poison 2, regen 3]".
+func formatStatuses(member *entity.Member) string {
+	effects := member.GetStatusEffects()
+	if len(effects) == 0 {
+		return ""
+	}
+	parts := make([]string, len(effects))
+	for i, e := range effects {
+		parts[i] = fmt.Sprintf("%s %d", e.Type, e.RemainingTurns)
 	}
+	return "[" + strings.Join(parts, ", ") + "]"
 }
 
 // renderText draws a string at the given position.
 func (r *Renderer) renderText(x, y int, text string, style tcell.Style) {
-	for i, ch := range text {
+	for i, ch := range []rune(text) {
 		r.screen.SetContent(x+i, y, ch, style)
 	}
 }
@@ -0,0 +1,630 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/samdwyer/dungeonband/internal/combat"
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+	"github.com/samdwyer/dungeonband/internal/world"
+)
+
+// newTestRenderer creates a renderer drawing to a simulation screen.
+func newTestRenderer(t *testing.T) (*Renderer, tcell.SimulationScreen) {
+	t.Helper()
+	sim := tcell.NewSimulationScreen("UTF-8")
+	screen, err := NewScreenFrom(sim)
+	if err != nil {
+		t.Fatalf("failed to create simulation screen: %v", err)
+	}
+	sim.SetSize(world.DefaultWidth, world.DefaultHeight+20)
+	t.Cleanup(screen.Close)
+	return NewRenderer(screen), sim
+}
+
+// dungeonFromMap builds a dungeon from an ASCII layout using '#' and '.'.
+func dungeonFromMap(layout string) *world.Dungeon {
+	lines := strings.Split(strings.TrimSpace(layout), "\n")
+	d := world.NewDungeon(len(lines[0]), len(lines), nil)
+	for y, line := range lines {
+		for x, ch := range line {
+			d.Tiles[y][x] = world.Tile(ch)
+		}
+	}
+	return d
+}
+
+// cellAt returns the rune drawn at the given screen position.
+func cellAt(sim tcell.SimulationScreen, x, y int) rune {
+	cells, width, _ := sim.GetContents()
+	cell := cells[y*width+x]
+	if len(cell.Runes) == 0 {
+		return ' '
+	}
+	return cell.Runes[0]
+}
+
+// rowText returns the text drawn on a screen row, with trailing blanks trimmed.
+func rowText(sim tcell.SimulationScreen, y int) string {
+	_, width, _ := sim.GetContents()
+	var b strings.Builder
+	for x := 0; x < width; x++ {
+		b.WriteRune(cellAt(sim, x, y))
+	}
+	return strings.TrimRight(b.String(), " ")
+}
+
+const trailLayout = `
+##########
+#........#
+#........#
+#........#
+##########`
+
+func TestPartyTrailFollowsLead(t *testing.T) {
+	r, sim := newTestRenderer(t)
+	d := dungeonFromMap(trailLayout)
+	party := entity.NewParty(2, 2)
+
+	for i := 0; i < 4; i++ {
+		party.Move(1, 0)
+	}
+
+	r.Render(d, party, nil, StateExplore, 0)
+
+	if got := cellAt(sim, 6, 2); got != '&' {
+		t.Errorf("lead at (6,2) = %q, want '&'", got)
+	}
+	want := []rune{'R', 'Z', 'C'}
+	for i, sym := range want {
+		if got := cellAt(sim, 5-i, 2); got != sym {
+			t.Errorf("follower %d at (%d,2) = %q, want %q", i, 5-i, got, sym)
+		}
+	}
+	if got := cellAt(sim, 2, 2); got != '.' {
+		t.Errorf("trail should be capped at %d tiles, found %q at (2,2)", entity.TrailLength, got)
+	}
+}
+
+func TestPartyTrailCollapsesWhenBacktracking(t *testing.T) {
+	party := entity.NewParty(2, 2)
+	party.Move(1, 0)
+	party.Move(1, 0)
+	party.Move(-1, 0) // Step back onto the trail
+
+	for _, pt := range party.Trail {
+		if pt.X == party.X && pt.Y == party.Y {
+			t.Errorf("trail contains the lead position (%d,%d)", pt.X, pt.Y)
+		}
+	}
+
+	party.SetPosition(5, 5)
+	if len(party.Trail) != 0 {
+		t.Errorf("SetPosition should clear the trail, got %d points", len(party.Trail))
+	}
+}
+
+func TestPartyTrailNeverDrawsOverWallsOrEnemies(t *testing.T) {
+	r, sim := newTestRenderer(t)
+	d := dungeonFromMap(trailLayout)
+	party := entity.NewParty(2, 2)
+	party.Move(1, 0)
+	party.Move(1, 0)
+	party.Move(1, 0)
+
+	// A wall appears on the oldest trail tile and an enemy on the newest
+	d.Tiles[2][2] = world.TileWall
+	goblin := entity.NewEnemy(entity.EnemyGoblin, 4, 2, 0)
+
+	r.Render(d, party, []*entity.Enemy{goblin}, StateExplore, 0)
+
+	if got := cellAt(sim, 4, 2); got != goblin.Symbol {
+		t.Errorf("enemy tile (4,2) = %q, want %q", got, goblin.Symbol)
+	}
+	if got := cellAt(sim, 2, 2); got != '#' {
+		t.Errorf("wall tile (2,2) = %q, want '#'", got)
+	}
+	if got := cellAt(sim, 3, 2); got != 'Z' {
+		t.Errorf("follower at (3,2) = %q, want 'Z'", got)
+	}
+}
+
+func TestPartyTrailHidesUnseenEnemies(t *testing.T) {
+	r, sim := newTestRenderer(t)
+	r.SetEnemyVisibility(world.VisibilityRoom)
+	d := dungeonFromMap(trailLayout)
+	d.Rooms = []world.Room{{X: 1, Y: 1, Width: 3, Height: 3}, {X: 5, Y: 1, Width: 4, Height: 3}}
+	party := entity.NewParty(2, 2)
+	party.Move(1, 0)
+	party.Move(1, 0)
+	party.Move(1, 0) // Into the east room
+
+	// The goblin stands on the trail back in the west room, out of view
+	goblin := entity.NewEnemy(entity.EnemyGoblin, 3, 2, 0)
+
+	r.Render(d, party, []*entity.Enemy{goblin}, StateExplore, 0)
+
+	if got := cellAt(sim, 3, 2); got != 'Z' {
+		t.Errorf("follower at (3,2) = %q, want 'Z' drawn over the unseen goblin", got)
+	}
+}
+
+func TestStackedMembersAreUnderlined(t *testing.T) {
+	r, sim := newTestRenderer(t)
+	d := dungeonFromMap(trailLayout)
+	party := entity.NewParty(2, 2)
+	for i, m := range party.Members {
+		m.SetPosition(2+min(i, 2), 2) // Last two members share (4,2)
+	}
+
+	r.RenderWithCombat(d, party, nil, StateCombat, 1, nil)
+
+	cells, width, _ := sim.GetContents()
+	if _, _, attrs := cells[2*width+2].Style.Decompose(); attrs&tcell.AttrUnderline != 0 {
+		t.Error("a member alone on a tile should not be underlined")
+	}
+	stacked := cells[2*width+4]
+	if _, _, attrs := stacked.Style.Decompose(); attrs&tcell.AttrUnderline == 0 {
+		t.Error("stacked members should be underlined")
+	}
+	if got := stacked.Runes[0]; got != party.Members[2].Symbol {
+		t.Errorf("stacked tile shows %q, want the first member there (%q)", got, party.Members[2].Symbol)
+	}
+}
+
+func TestExploreHUDShowsActiveStatuses(t *testing.T) {
+	r, sim := newTestRenderer(t)
+	d := dungeonFromMap(trailLayout)
+	party := entity.NewParty(2, 2)
+	party.Members[1].AddStatusEffect(combat.StatusEffect{Type: gamedata.StatusRegen, RemainingTurns: 2, Power: 3})
+
+	r.Render(d, party, nil, StateExplore, 0)
+
+	want := party.Members[1].Name + " [regen 2]"
+	if got := rowText(sim, d.Height+2); got != want {
+		t.Errorf("status row = %q, want %q", got, want)
+	}
+	if got := rowText(sim, d.Height+3); got != "" {
+		t.Errorf("only afflicted members should be listed, found %q", got)
+	}
+}
+
+func TestPartyHUDShowsInjectedHealth(t *testing.T) {
+	r, sim := newTestRenderer(t)
+	d := dungeonFromMap(trailLayout)
+	party := entity.NewParty(2, 2)
+	for _, m := range party.Members {
+		m.MaxHP, m.HP = 20, 20
+		m.MaxMP, m.MP = 6, 6
+	}
+	party.Members[0].HP = 3 // Warrior badly hurt
+	party.Members[1].HP = 10
+	party.Members[2].MP = 1
+	party.Members[3].HP = 0
+	party.Members[1].AddStatusEffect(combat.StatusEffect{Type: gamedata.StatusPoison, RemainingTurns: 2, Power: 1})
+
+	r.Render(d, party, nil, StateExplore, 0)
+
+	want := "A[#----]*** S[##---]***! Z[#####]*.. C[-----]***"
+	if got := rowText(sim, d.Height); got != want {
+		t.Errorf("HUD row = %q, want %q", got, want)
+	}
+	cells, width, _ := sim.GetContents()
+	if fg, _, _ := cells[d.Height*width].Style.Decompose(); fg != tcell.ColorRed {
+		t.Errorf("low-HP warrior drawn in %v, want red", fg)
+	}
+
+	party.Members[0].HP = 20
+	r.Render(d, party, nil, StateExplore, 0)
+	if got := rowText(sim, d.Height); !strings.HasPrefix(got, "A[#####]") {
+		t.Errorf("HUD row after healing = %q, want a full bar for the warrior", got)
+	}
+}
+
+func TestPartyHUDNarrowsToFit(t *testing.T) {
+	r, sim := newTestRenderer(t)
+	d := dungeonFromMap(trailLayout)
+	party := entity.NewParty(2, 2)
+	for _, m := range party.Members {
+		m.MaxHP, m.HP = 20, 20
+		m.MaxMP, m.MP = 6, 6
+	}
+	party.Members[0].HP = 3
+
+	for _, tt := range []struct {
+		width int
+		want  string
+	}{
+		{40, "A[#--]*** S[###]*** Z[###]*** C[###]***"},
+		{36, "A[#--] S[###] Z[###] C[###]"}, // MP pips go first
+		{20, "A S Z C"},
+	} {
+		sim.SetSize(tt.width, d.Height+10)
+		r.Render(d, party, nil, StateExplore, 0)
+		if got := rowText(sim, d.Height); got != tt.want {
+			t.Errorf("width %d: HUD row = %q, want %q", tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestFormatRunTime(t *testing.T) {
+	for _, tt := range []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "00:00.0"},
+		{3*time.Minute + 7*time.Second + 480*time.Millisecond, "03:07.4"},
+		{time.Hour + 2*time.Minute + 5*time.Second, "1:02:05.0"},
+	} {
+		if got := FormatRunTime(tt.d); got != tt.want {
+			t.Errorf("FormatRunTime(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestInstructionPanelWrapsText(t *testing.T) {
+	r, sim := newTestRenderer(t)
+	text := "Each member acts in turn. Press a number to use one of their abilities, then pick a target."
+
+	r.RenderInstruction("Using abilities", text, 80, 24)
+
+	var body []string
+	for y := 0; y < 24; y++ {
+		row := rowText(sim, y)
+		if strings.HasPrefix(strings.TrimSpace(row), "| ") {
+			body = append(body, strings.TrimSpace(strings.Trim(strings.TrimSpace(row), "|")))
+		}
+	}
+	want := []string{
+		"Using abilities",
+		"Each member acts in turn. Press a number to use",
+		"one of their abilities, then pick a target.",
+		"(press any key)",
+	}
+	if strings.Join(body, "\n") != strings.Join(want, "\n") {
+		t.Errorf("panel body:\n%s\nwant:\n%s", strings.Join(body, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+// panelText returns the combat panel rows below the map, skipping blanks.
+func panelText(sim tcell.SimulationScreen, d *world.Dungeon) string {
+	_, _, height := sim.GetContents()
+	var rows []string
+	for y := d.Height; y < height; y++ {
+		if row := rowText(sim, y); row != "" {
+			rows = append(rows, row)
+		}
+	}
+	return strings.Join(rows, "\n")
+}
+
+func TestCombatBannerOnPlayerTurn(t *testing.T) {
+	r, sim := newTestRenderer(t)
+	d := dungeonFromMap(trailLayout)
+	party := entity.NewParty(2, 2)
+	active := party.Members[1]
+	orc := entity.NewEnemy(entity.EnemyOrc, 5, 2, 0)
+
+	r.RenderWithCombat(d, party, []*entity.Enemy{orc}, StateCombat, 1, &CombatInfo{
+		Phase:        PhasePlayerTurn,
+		ActiveMember: active,
+		Abilities:    []AbilityInfo{{Name: "Attack", CanUse: true}},
+		Enemies:      []*entity.Enemy{orc},
+	})
+
+	banner := rowText(sim, d.Height+1)
+	if !strings.HasPrefix(banner, "YOUR TURN — "+active.Name+" |") {
+		t.Errorf("banner = %q, want it to name %s", banner, active.Name)
+	}
+	if panel := panelText(sim, d); !strings.Contains(panel, "[1] Attack") {
+		t.Errorf("player turn should list abilities, panel:\n%s", panel)
+	}
+}
+
+func TestCombatBannerOnEnemyTurn(t *testing.T) {
+	r, sim := newTestRenderer(t)
+	d := dungeonFromMap(trailLayout)
+	party := entity.NewParty(2, 2)
+	party.Members[0].HP = party.Members[0].MaxHP / 2
+	orc := entity.NewEnemy(entity.EnemyOrc, 5, 2, 0)
+
+	r.RenderWithCombat(d, party, []*entity.Enemy{orc}, StateCombat, 1, &CombatInfo{
+		Phase:        PhaseEnemyTurn,
+		ActiveMember: party.Members[3], // Left over from the party's last turn
+		Abilities:    []AbilityInfo{{Name: "Heal", CanUse: true}},
+		ActingEnemy:  orc,
+		EnemyActions: []string{"Orc uses Attack on " + party.Members[0].Name + "!"},
+		Enemies:      []*entity.Enemy{orc},
+	})
+
+	if got, want := rowText(sim, d.Height+1), "ENEMY TURN — "+orc.Name+" is acting…"; got != want {
+		t.Errorf("banner = %q, want %q", got, want)
+	}
+	panel := panelText(sim, d)
+	if strings.Contains(panel, "Heal") || strings.Contains(panel, "Abilities") {
+		t.Errorf("enemy turn should hide the ability list, panel:\n%s", panel)
+	}
+	if !strings.Contains(panel, "Orc uses Attack") {
+		t.Errorf("enemy turn should list recent enemy actions, panel:\n%s", panel)
+	}
+	warrior := party.Members[0]
+	wantRoster := fmt.Sprintf("%-8s [#####-----] %d/%d", warrior.Name, warrior.HP, warrior.MaxHP)
+	if !strings.Contains(panel, wantRoster) {
+		t.Errorf("roster should show %q, panel:\n%s", wantRoster, panel)
+	}
+}
+
+func TestFormationShowsMemberHealth(t *testing.T) {
+	r, sim := newTestRenderer(t)
+	d := dungeonFromMap(trailLayout)
+	party := entity.NewParty(2, 2)
+	for i, m := range party.Members {
+		m.SetPosition(2+i, 2)
+	}
+	hurt, dead := party.Members[0], party.Members[1]
+	hurt.HP = hurt.MaxHP / 5
+	dead.HP = 0
+
+	r.RenderWithCombat(d, party, nil, StateCombat, 1, nil)
+
+	cells, width, _ := sim.GetContents()
+	if fg, _, _ := cells[2*width+2].Style.Decompose(); fg != tcell.ColorRed {
+		t.Errorf("low-HP member drawn in %v, want red", fg)
+	}
+	if got := cells[2*width+3]; got.Runes[0] != deadGlyph {
+		t.Errorf("dead member drawn as %q, want %q", got.Runes[0], deadGlyph)
+	}
+	if fg, _, _ := cells[2*width+3].Style.Decompose(); fg != tcell.ColorDarkGray {
+		t.Errorf("dead member drawn in %v, want dark gray", fg)
+	}
+	if fg, _, _ := cells[2*width+4].Style.Decompose(); fg == tcell.ColorRed {
+		t.Error("a healthy member should not be drawn in the danger color")
+	}
+}
+
+func TestFormationCanShowInitials(t *testing.T) {
+	r, sim := newTestRenderer(t)
+	d := dungeonFromMap(trailLayout)
+	party := entity.NewParty(2, 2)
+	for i, m := range party.Members {
+		m.SetPosition(2+i, 2)
+	}
+	party.Members[0].Name = "brom"
+
+	r.SetShowInitials(true)
+	r.RenderWithCombat(d, party, nil, StateCombat, 1, nil)
+
+	if got := cellAt(sim, 2, 2); got != 'B' {
+		t.Errorf("member drawn as %q, want their initial 'B'", got)
+	}
+}
+
+func TestThreatenedTilesArePassableNeighbors(t *testing.T) {
+	d := dungeonFromMap(`
+#######
+#..#..#
+#.....#
+#######`)
+	corner := entity.NewEnemy(entity.EnemyGoblin, 2, 1, 0)
+	dead := entity.NewEnemy(entity.EnemyGoblin, 5, 2, 0)
+	dead.HP = 0
+
+	got := ThreatenedTiles(d, []*entity.Enemy{corner, dead})
+	want := map[world.Point]bool{
+		{X: 1, Y: 1}: true,
+		{X: 1, Y: 2}: true, {X: 2, Y: 2}: true, {X: 3, Y: 2}: true,
+	}
+	if len(got) != len(want) {
+		t.Errorf("threatened %v, want %v", got, want)
+	}
+	for p := range want {
+		if !got[p] {
+			t.Errorf("tile %v next to the goblin is not threatened", p)
+		}
+	}
+}
+
+func TestThreatenedTilesAreTinted(t *testing.T) {
+	r, sim := newTestRenderer(t)
+	d := dungeonFromMap(trailLayout)
+	party := entity.NewParty(2, 2)
+	for i, m := range party.Members {
+		m.SetPosition(2+i, 2)
+	}
+	goblin := entity.NewEnemy(entity.EnemyGoblin, 7, 2, 0)
+	enemies := []*entity.Enemy{goblin}
+	info := &CombatInfo{Enemies: enemies, Threatened: ThreatenedTiles(d, enemies)}
+
+	r.RenderWithCombat(d, party, enemies, StateCombat, 1, info)
+
+	cells, width, _ := sim.GetContents()
+	if _, bg, _ := cells[1*width+6].Style.Decompose(); bg != threatBackground {
+		t.Errorf("tile next to the goblin has background %v, want %v", bg, threatBackground)
+	}
+	if _, bg, _ := cells[2*width+5].Style.Decompose(); bg == threatBackground {
+		t.Error("a tile out of the goblin's reach is tinted")
+	}
+}
+
+func TestInvalidTargetIsFlagged(t *testing.T) {
+	r, sim := newTestRenderer(t)
+	d := dungeonFromMap(trailLayout)
+	party := entity.NewParty(2, 2)
+	ally := party.Members[0]
+
+	r.RenderWithCombat(d, party, nil, StateCombat, 1, &CombatInfo{
+		Phase:         PhaseSelectTarget,
+		ActiveMember:  party.Members[1],
+		TargetAbility: "Attack",
+		TargetRange:   "front-row enemies",
+		TargetAllies:  party.Members,
+		TargetMember:  ally,
+		InvalidTarget: true,
+		Message:       "Invalid target — Attack targets enemies.",
+	})
+
+	panel := panelText(sim, d)
+	if !strings.Contains(panel, "Attack → front-row enemies") {
+		t.Errorf("targeting header should show the ability's range, panel:\n%s", panel)
+	}
+	if !strings.Contains(panel, fmt.Sprintf("> %s HP: %d/%d (invalid target)", ally.Name, ally.HP, ally.MaxHP)) {
+		t.Errorf("the ally under the cursor should be marked invalid, panel:\n%s", panel)
+	}
+}
+
+func TestWrapTextBreaksAtNewlines(t *testing.T) {
+	got := wrapText("Turn 2: Goblin attacks.\nAldric 10/30\nGoblin 0/15 [poison]", 50)
+	want := []string{"Turn 2: Goblin attacks.", "Aldric 10/30", "Goblin 0/15 [poison]"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("wrapText = %q, want %q", got, want)
+	}
+}
+
+func TestScoutSeesEnemiesThePartyCannot(t *testing.T) {
+	r, sim := newTestRenderer(t)
+	d := dungeonFromMap(`
+##########
+#....#...#
+#....#...#
+#........#
+##########`)
+	party := entity.NewParty(1, 1)
+	goblin := entity.NewEnemy(entity.EnemyGoblin, 8, 1, 0)
+
+	r.Render(d, party, []*entity.Enemy{goblin}, StateExplore, 0)
+	if got := cellAt(sim, 8, 1); got == goblin.Symbol {
+		t.Fatal("goblin behind the wall drawn without a scout")
+	}
+
+	scout := party.Members[1]
+	scout.SetPosition(7, 2)
+	party.Scout = scout
+	r.Render(d, party, []*entity.Enemy{goblin}, StateExplore, 0)
+
+	if got := cellAt(sim, 8, 1); got != goblin.Symbol {
+		t.Errorf("goblin in the scout's sight = %q, want %q", got, goblin.Symbol)
+	}
+	if got := cellAt(sim, 7, 2); got != scout.Symbol {
+		t.Errorf("scout tile = %q, want %q", got, scout.Symbol)
+	}
+}
+
+func TestEnemyVisibilityRules(t *testing.T) {
+	r, sim := newTestRenderer(t)
+	d := dungeonFromMap(`
+#....#....#...#
+#....#....#...#
+#.............#
+#....#....#...#
+###############`)
+	d.Rooms = []world.Room{{X: 1, Y: 1, Width: 4, Height: 3}, {X: 6, Y: 1, Width: 8, Height: 2}}
+	party := entity.NewParty(2, 2)
+	roommate := entity.NewEnemy(entity.EnemyGoblin, 4, 3, 0)  // Same room
+	nextDoor := entity.NewEnemy(entity.EnemyOrc, 8, 2, 1)     // Next room, through the doorway
+	hidden := entity.NewEnemy(entity.EnemySkeleton, 13, 1, 1) // Next room, behind the wall
+	enemies := []*entity.Enemy{roommate, nextDoor, hidden}
+
+	for _, tt := range []struct {
+		rule world.Visibility
+		want []bool
+	}{
+		{world.VisibilitySight, []bool{true, true, false}},
+		{world.VisibilityRoom, []bool{true, false, false}},
+		{world.VisibilityAll, []bool{true, true, true}},
+	} {
+		r.SetEnemyVisibility(tt.rule)
+		r.Render(d, party, enemies, StateExplore, 0)
+		for i, e := range enemies {
+			if drawn := cellAt(sim, e.X, e.Y) == e.Symbol; drawn != tt.want[i] {
+				t.Errorf("%s: %s drawn = %v, want %v", tt.rule, e.GetName(), drawn, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestPartySetupPreviewsSelectedClass(t *testing.T) {
+	r, sim := newTestRenderer(t)
+	classes := gamedata.MustLoadClassRegistry()
+	wizard := classes.GetByID("wizard")
+
+	r.RenderPartySetup(PartySetup{
+		Slots: []PartySlot{
+			{Name: "Aldric", Class: classes.GetByID("warrior")},
+			{Name: "Shade", Class: wizard},
+		},
+		Selected:     1,
+		Classes:      classes.All(),
+		AbilityNames: map[string]string{"fireball": "Fireball"},
+	})
+
+	_, height := sim.Size()
+	var screen []string
+	for y := 0; y < height; y++ {
+		screen = append(screen, rowText(sim, y))
+	}
+	text := strings.Join(screen, "\n")
+
+	if !strings.Contains(text, "> Shade    Wizard") {
+		t.Errorf("selected member not marked:\n%s", text)
+	}
+	row := fmt.Sprintf("%-11s %3d  %3d", wizard.Name, wizard.HP, wizard.MP)
+	if !strings.Contains(text, row) {
+		t.Errorf("missing the wizard's stats %q:\n%s", row, text)
+	}
+	if !strings.Contains(text, "Wizard abilities:") || !strings.Contains(text, "Fireball") {
+		t.Errorf("missing the wizard's abilities:\n%s", text)
+	}
+	for _, id := range wizard.Abilities {
+		if id != "fireball" && !strings.Contains(text, id) {
+			t.Errorf("ability %q not listed:\n%s", id, text)
+		}
+	}
+}
+
+func TestMinScreenSize(t *testing.T) {
+	tests := []struct {
+		mapWidth, mapHeight int
+		width, height       int
+	}{
+		{world.DefaultWidth, world.DefaultHeight, 80, 26},  // Map plus message line
+		{20, 6, 20, combatPanelHeight + minCombatViewRows}, // Combat panel needs more
+	}
+	for _, tt := range tests {
+		if w, h := MinScreenSize(tt.mapWidth, tt.mapHeight); w != tt.width || h != tt.height {
+			t.Errorf("MinScreenSize(%d, %d) = %dx%d, want %dx%d", tt.mapWidth, tt.mapHeight, w, h, tt.width, tt.height)
+		}
+	}
+}
+
+func TestDebugOverlayShowsPartyTileAndRoom(t *testing.T) {
+	r, sim := newTestRenderer(t)
+	d := dungeonFromMap(`
+###########
+#...#.....#
+#.........#
+#...#.....#
+###########`)
+	d.Rooms = []world.Room{{X: 1, Y: 1, Width: 3, Height: 3}, {X: 5, Y: 1, Width: 5, Height: 3}}
+	enemies := []*entity.Enemy{entity.NewEnemy(entity.EnemyGoblin, 8, 1, 1), entity.NewEnemy(entity.EnemyGoblin, 7, 1, 1)}
+	enemies[1].HP = 0 // Killed in a fight that hasn't ended yet
+
+	for _, tt := range []struct {
+		x, y int
+		want string
+	}{
+		{7, 3, "x:7 y:3 room:1 enemies:1"},
+		{4, 2, "x:4 y:2 room:- enemies:1"}, // The doorway between the rooms
+	} {
+		party := entity.NewParty(tt.x, tt.y)
+		r.Render(d, party, enemies, StateExplore, 0)
+		r.RenderDebug(NewDebugInfo(d, party, enemies))
+		if got := rowText(sim, 0); !strings.Contains(got, tt.want) {
+			t.Errorf("top row = %q, want it to contain %q", got, tt.want)
+		}
+	}
+}
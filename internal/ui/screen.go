@@ -14,6 +14,12 @@ func NewScreen() (*Screen, error) {
 	if err != nil {
 		return nil, err
 	}
+	return NewScreenFrom(s)
+}
+
+// NewScreenFrom initializes and wraps an existing tcell screen.
+// This allows a tcell.SimulationScreen to be used in tests without a real TTY.
+func NewScreenFrom(s tcell.Screen) (*Screen, error) {
 	if err := s.Init(); err != nil {
 		return nil, err
 	}
@@ -57,3 +63,30 @@ func (s *Screen) Size() (width, height int) {
 func (s *Screen) Sync() {
 	s.screen.Sync()
 }
+
+// SetTitle sets the terminal window title where supported.
+func (s *Screen) SetTitle(title string) {
+	s.screen.SetTitle(title)
+}
+
+// Suspend restores the terminal to its normal state so the process can be
+// backgrounded. Call Resume to take the terminal back.
+func (s *Screen) Suspend() error {
+	return s.screen.Suspend()
+}
+
+// Resume re-initializes the terminal after Suspend.
+func (s *Screen) Resume() error {
+	return s.screen.Resume()
+}
+
+// PostEvent injects an event into the event stream.
+// It is safe to call from other goroutines.
+func (s *Screen) PostEvent(ev tcell.Event) error {
+	return s.screen.PostEvent(ev)
+}
+
+// Beep rings the terminal bell.
+func (s *Screen) Beep() error {
+	return s.screen.Beep()
+}
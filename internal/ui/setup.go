@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+)
+
+// PartySlot is one member's place in the party setup screen.
+type PartySlot struct {
+	Name  string
+	Class *gamedata.ClassDef
+}
+
+// PartySetup is what the party setup screen shows: each member's class,
+// which member is selected, and every class to compare against.
+type PartySetup struct {
+	Slots    []PartySlot
+	Selected int
+	Classes  []gamedata.ClassDef
+
+	// AbilityNames maps ability IDs to display names. IDs missing from it
+	// are shown as they are.
+	AbilityNames map[string]string
+}
+
+// classColumns is the header of the class comparison table.
+const classColumns = "Class        HP   MP  Atk  Def  Mag  Res"
+
+// RenderPartySetup draws the party setup screen: the members and their
+// classes, a table comparing every class's stats with the selected
+// member's class highlighted, and that class's starting abilities.
+func (r *Renderer) RenderPartySetup(setup PartySetup) {
+	r.screen.Clear()
+	width, _ := r.screen.Size()
+	x := max((width-len(classColumns))/2, 0)
+	y := 1
+
+	title := tcell.StyleDefault.Foreground(tcell.ColorYellow).Bold(true)
+	text := tcell.StyleDefault.Foreground(tcell.ColorWhite)
+	dim := tcell.StyleDefault.Foreground(tcell.ColorGray)
+	highlight := tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(tcell.ColorYellow)
+
+	r.renderText(x, y, "Party Setup", title)
+	y += 2
+	for i, slot := range setup.Slots {
+		class := "?"
+		if slot.Class != nil {
+			class = slot.Class.Name
+		}
+		line := fmt.Sprintf("  %-8s %s", slot.Name, class)
+		style := text
+		if i == setup.Selected {
+			line = "> " + line[2:]
+			style = title
+		}
+		r.renderText(x, y, line, style)
+		y++
+	}
+
+	var selected *gamedata.ClassDef
+	if setup.Selected >= 0 && setup.Selected < len(setup.Slots) {
+		selected = setup.Slots[setup.Selected].Class
+	}
+
+	y++
+	r.renderText(x, y, classColumns, dim)
+	y++
+	for _, c := range setup.Classes {
+		line := fmt.Sprintf("%-11s %3d  %3d  %3d  %3d  %3d  %3d", c.Name, c.HP, c.MP, c.Attack, c.Defense, c.Magic, c.Resist)
+		style := text
+		if selected != nil && c.ID == selected.ID {
+			style = highlight
+		}
+		r.renderText(x, y, line, style)
+		y++
+	}
+
+	if selected != nil {
+		names := make([]string, len(selected.Abilities))
+		for i, id := range selected.Abilities {
+			names[i] = id
+			if name, ok := setup.AbilityNames[id]; ok {
+				names[i] = name
+			}
+		}
+		y++
+		r.renderText(x, y, selected.Name+" abilities:", title)
+		for _, line := range wrapText(strings.Join(names, ", "), len(classColumns)) {
+			y++
+			r.renderText(x, y, line, text)
+		}
+	}
+
+	y += 2
+	r.renderText(x, y, "Up/Down: member  Left/Right: class  Enter: done", dim)
+	r.screen.Show()
+}
@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// NewBufferScreen creates an off-screen buffer of the given size that can be
+// rendered to like a terminal and read back with Snapshot.
+func NewBufferScreen(width, height int) (*Screen, error) {
+	sim := tcell.NewSimulationScreen("UTF-8")
+	s, err := NewScreenFrom(sim)
+	if err != nil {
+		return nil, err
+	}
+	sim.SetSize(width, height)
+	return s, nil
+}
+
+// Snapshot returns the last shown frame as text, one line per row, with ANSI
+// escape codes for colors and bold. Trailing blanks on each row are dropped.
+// Only buffer screens support snapshots.
+func (s *Screen) Snapshot() (string, error) {
+	sim, ok := s.screen.(tcell.SimulationScreen)
+	if !ok {
+		return "", errors.New("snapshot requires a buffer screen")
+	}
+
+	cells, width, height := sim.GetContents()
+	var b strings.Builder
+	for y := 0; y < height; y++ {
+		row := cells[y*width : (y+1)*width]
+
+		// Drop trailing blank cells
+		end := len(row)
+		for end > 0 && isBlank(row[end-1]) {
+			end--
+		}
+
+		current := tcell.StyleDefault
+		styled := false
+		for _, cell := range row[:end] {
+			if cell.Style != current {
+				b.WriteString(ansiStyle(cell.Style))
+				current = cell.Style
+				styled = true
+			}
+			if len(cell.Runes) == 0 {
+				b.WriteRune(' ')
+			} else {
+				b.WriteString(string(cell.Runes))
+			}
+		}
+		if styled {
+			b.WriteString("\x1b[0m")
+		}
+		b.WriteByte('\n')
+	}
+	return b.String(), nil
+}
+
+// isBlank returns true if the cell shows nothing but a space.
+func isBlank(cell tcell.SimCell) bool {
+	return len(cell.Runes) == 0 || (len(cell.Runes) == 1 && cell.Runes[0] == ' ')
+}
+
+// ansiStyle returns the escape sequence that switches the terminal to style.
+// Black backgrounds are left as the terminal default.
+func ansiStyle(style tcell.Style) string {
+	fg, bg, attrs := style.Decompose()
+	codes := []string{"0"}
+	if attrs&tcell.AttrBold != 0 {
+		codes = append(codes, "1")
+	}
+	if attrs&tcell.AttrUnderline != 0 {
+		codes = append(codes, "4")
+	}
+	if fg != tcell.ColorDefault {
+		r, g, b := fg.RGB()
+		codes = append(codes, fmt.Sprintf("38;2;%d;%d;%d", r, g, b))
+	}
+	if bg != tcell.ColorDefault && bg != tcell.ColorBlack {
+		r, g, b := bg.RGB()
+		codes = append(codes, fmt.Sprintf("48;2;%d;%d;%d", r, g, b))
+	}
+	return "\x1b[" + strings.Join(codes, ";") + "m"
+}
+
+// Contents returns the cells of the last shown frame in row-major order.
+// Only buffer screens support it.
+func (s *Screen) Contents() (cells []tcell.SimCell, width, height int, err error) {
+	sim, ok := s.screen.(tcell.SimulationScreen)
+	if !ok {
+		return nil, 0, 0, errors.New("contents require a buffer screen")
+	}
+	cells, width, height = sim.GetContents()
+	return cells, width, height, nil
+}
+
+// Resize changes the size of a buffer screen. Other screens follow their
+// terminal and ignore it.
+func (s *Screen) Resize(width, height int) {
+	if sim, ok := s.screen.(tcell.SimulationScreen); ok {
+		sim.SetSize(width, height)
+	}
+}
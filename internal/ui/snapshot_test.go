@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestSnapshotTextAndColors(t *testing.T) {
+	s, err := NewBufferScreen(10, 2)
+	if err != nil {
+		t.Fatalf("NewBufferScreen: %v", err)
+	}
+	defer s.Close()
+
+	s.SetContent(0, 0, 'a', tcell.StyleDefault.Foreground(tcell.ColorRed).Bold(true))
+	s.SetContent(1, 0, 'b', tcell.StyleDefault.Foreground(tcell.ColorRed).Bold(true))
+	s.SetContent(2, 1, 'c', tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorBlack))
+	s.Show()
+
+	got, err := s.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+
+	// Same-style runs share one escape; trailing blanks are trimmed
+	// (blank cells carry the screen's white-on-black base style)
+	want := "\x1b[0;1;38;2;255;0;0mab\x1b[0m\n" +
+		"\x1b[0;38;2;255;255;255m  c\x1b[0m\n"
+	if got != want {
+		t.Errorf("Snapshot() = %q, want %q", got, want)
+	}
+}
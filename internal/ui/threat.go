@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/samdwyer/dungeonband/internal/entity"
+	"github.com/samdwyer/dungeonband/internal/world"
+)
+
+// threatBackground tints the tiles a melee enemy could strike next turn.
+const threatBackground = tcell.ColorMaroon
+
+// ThreatenedTiles returns the passable tiles next to each living enemy:
+// where a melee enemy can hit a member standing there.
+func ThreatenedTiles(dungeon *world.Dungeon, enemies []*entity.Enemy) map[world.Point]bool {
+	threatened := make(map[world.Point]bool)
+	for _, e := range enemies {
+		if !e.IsAlive() {
+			continue
+		}
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				x, y := e.X+dx, e.Y+dy
+				if (dx != 0 || dy != 0) && dungeon.IsPassable(x, y) {
+					threatened[world.Point{X: x, Y: y}] = true
+				}
+			}
+		}
+	}
+	return threatened
+}
+
+// renderThreat redraws the threatened tiles on a tinted background.
+func (r *Renderer) renderThreat(dungeon *world.Dungeon, threatened map[world.Point]bool) {
+	for p := range threatened {
+		tile := dungeon.GetTile(p.X, p.Y)
+		r.setWorld(p.X, p.Y, tile.Rune(), r.getTileStyle(tile).Background(threatBackground))
+	}
+}
@@ -0,0 +1,35 @@
+package world
+
+// Corpse is what's left of a slain enemy. The party can search it once
+// for the enemy's loot.
+type Corpse struct {
+	X       int    `json:"x"`
+	Y       int    `json:"y"`
+	EnemyID string `json:"enemyId"` // EnemyDef ID, for the loot table and name
+	Looted  bool   `json:"looted"`  // Already searched
+}
+
+// CorpseAt returns the first corpse at the position that hasn't been
+// searched, else the first searched one, or nil if there is none.
+func (d *Dungeon) CorpseAt(x, y int) *Corpse {
+	var searched *Corpse
+	for i := range d.Corpses {
+		c := &d.Corpses[i]
+		if c.X != x || c.Y != y {
+			continue
+		}
+		if !c.Looted {
+			return c
+		}
+		if searched == nil {
+			searched = c
+		}
+	}
+	return searched
+}
+
+// AddCorpse leaves an unsearched corpse on the tile.
+func (d *Dungeon) AddCorpse(x, y int, enemyID string) *Corpse {
+	d.Corpses = append(d.Corpses, Corpse{X: x, Y: y, EnemyID: enemyID})
+	return &d.Corpses[len(d.Corpses)-1]
+}
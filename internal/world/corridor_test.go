@@ -0,0 +1,118 @@
+package world
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// generate builds a default-sized floor from the seed.
+func generate(seed int64) *Dungeon {
+	d := NewDungeon(DefaultWidth, DefaultHeight, rand.New(rand.NewSource(seed)))
+	d.Generate(context.Background())
+	return d
+}
+
+func TestCorridorBookkeepingAcrossSeeds(t *testing.T) {
+	for seed := int64(1); seed <= 25; seed++ {
+		d := generate(seed)
+		if len(d.Corridors) != len(d.Rooms)-1 {
+			t.Errorf("seed %d: %d corridors for %d rooms, want a spanning tree", seed, len(d.Corridors), len(d.Rooms))
+		}
+		for i, c := range d.Corridors {
+			a, b, ok := d.RoomsConnectedBy(c.ID)
+			if c.ID != i || !ok || a < 0 || b < 0 || a == b || a >= len(d.Rooms) || b >= len(d.Rooms) {
+				t.Errorf("seed %d: corridor %d (ID %d) connects rooms %d and %d", seed, i, c.ID, a, b)
+			}
+			for _, p := range c.Tiles {
+				if !d.IsPassable(p.X, p.Y) || d.RoomIndexAt(p.X, p.Y) >= 0 || d.CorridorAt(p.X, p.Y) != c.ID {
+					t.Errorf("seed %d: corridor %d tile (%d,%d) is wall, in a room or looked up as %d", seed, c.ID, p.X, p.Y, d.CorridorAt(p.X, p.Y))
+				}
+			}
+		}
+		// Every floor tile outside the rooms belongs to some corridor
+		for y := range d.Height {
+			for x := range d.Width {
+				if d.IsPassable(x, y) && d.RoomIndexAt(x, y) < 0 && d.CorridorAt(x, y) < 0 {
+					t.Errorf("seed %d: floor tile (%d,%d) is in no room or corridor", seed, x, y)
+				}
+			}
+		}
+	}
+}
+
+func TestCorridorsAreReproducible(t *testing.T) {
+	if a, b := generate(99), generate(99); !reflect.DeepEqual(a.Corridors, b.Corridors) {
+		t.Error("the same seed carved different corridors")
+	}
+}
+
+func TestCorridorLookupRebuildsFromCorridors(t *testing.T) {
+	d := generate(7)
+	restored := NewDungeon(d.Width, d.Height, nil)
+	restored.Tiles, restored.Rooms, restored.Corridors = d.Tiles, d.Rooms, d.Corridors
+	for _, c := range d.Corridors {
+		for _, p := range c.Tiles {
+			if got := restored.CorridorAt(p.X, p.Y); got != c.ID {
+				t.Fatalf("restored CorridorAt(%d,%d) = %d, want %d", p.X, p.Y, got, c.ID)
+			}
+		}
+	}
+	if _, _, ok := restored.RoomsConnectedBy(len(d.Corridors)); ok {
+		t.Error("RoomsConnectedBy should reject an unknown corridor")
+	}
+}
+
+// bentCorridor is two rooms joined by a corridor that turns a corner, so
+// the far end of the corridor is out of line of sight of the rooms.
+func bentCorridor() *Dungeon {
+	d := NewDungeonFromLayout([]string{
+		"##############",
+		"#....#########",
+		"#....#########",
+		"#.........####",
+		"#....####.####",
+		"#########.####",
+		"#######.....##",
+		"#######.....##",
+		"##############",
+	})
+	d.Rooms = []Room{{X: 1, Y: 1, Width: 4, Height: 4}, {X: 7, Y: 6, Width: 5, Height: 2}}
+	d.Corridors = []Corridor{{
+		ID:    0,
+		Rooms: [2]int{0, 1},
+		Tiles: []Point{{5, 3}, {6, 3}, {7, 3}, {8, 3}, {9, 3}, {9, 4}, {9, 5}},
+	}}
+	return d
+}
+
+func TestInSightIncludesCorridorsOffTheRoom(t *testing.T) {
+	d := bentCorridor()
+	if d.CanSee(1, 1, 9, 5, SightRadius) {
+		t.Fatal("test layout should hide the corridor's bend")
+	}
+	if !d.InSight(1, 1, 9, 5) {
+		t.Error("an enemy round the bend of a corridor off the room should be in sight")
+	}
+	if d.InSight(9, 5, 1, 1) {
+		t.Error("sight from a corridor doesn't reach round bends into rooms")
+	}
+}
+
+func TestCorridorRecordsRoomsItPassesThrough(t *testing.T) {
+	d := NewDungeon(20, 5, rand.New(rand.NewSource(1)))
+	d.Rooms = []Room{{X: 1, Y: 1, Width: 3, Height: 3}, {X: 8, Y: 1, Width: 3, Height: 3}, {X: 15, Y: 1, Width: 3, Height: 3}}
+	for _, room := range d.Rooms {
+		d.carveRoom(room)
+	}
+	d.carveCorridor(d.Rooms[0], d.Rooms[2])
+
+	c := d.Corridors[0]
+	if c.Rooms != [2]int{0, 2} || !reflect.DeepEqual(c.Through, []int{1}) {
+		t.Fatalf("corridor joins %v through %v, want rooms 0 and 2 through 1", c.Rooms, c.Through)
+	}
+	if !d.CorridorOpensInto(12, 2, 1) {
+		t.Error("the corridor beyond the middle room should open into it")
+	}
+}
@@ -3,9 +3,11 @@ package world
 import (
 	"context"
 	"math/rand"
+	"slices"
 	"time"
 
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/samdwyer/dungeonband/internal/telemetry"
 )
@@ -27,11 +29,52 @@ type Dungeon struct {
 	Height int
 	Tiles  [][]Tile
 	Rooms  []Room
-	rng    *rand.Rand
+
+	// Prefabs are hand-authored rooms that may replace plain rectangles.
+	// Set them before calling Generate.
+	Prefabs []Prefab
+
+	// Position of the stairs down (-1 if the floor has none)
+	StairsX, StairsY int
+
+	// Shrines placed on this floor, spent or not
+	Shrines []Shrine
+
+	// Corpses of enemies slain on this floor, searched or not
+	Corpses []Corpse
+
+	// Corridors carved during generation, in carving order. A corridor's
+	// ID is its index.
+	Corridors []Corridor
+
+	// corridorAt maps corridor tiles to corridor IDs; built on first use
+	corridorAt map[Point]int
+
+	// reserved holds tiles nothing may be spawned or placed on: the
+	// stairs, doorways, chests and the party's entry. See Reserve.
+	reserved map[Point]bool
+
+	// rng is the caller's seeded source, the only randomness a dungeon
+	// uses, so a seed reproduces both the layout and what is placed in it.
+	// Hand-authored floors have none.
+	rng *rand.Rand
+}
+
+// Corridor is a hallway carved between the centers of two rooms. Its tiles
+// are the floor it carved outside every room; a tile two corridors cross
+// belongs to the one carved first. A corridor can cut through other rooms
+// on the way, which it opens into as well.
+type Corridor struct {
+	ID       int
+	Rooms    [2]int // Indices into Dungeon.Rooms
+	Through  []int  `json:",omitempty"` // Other rooms it passes through, in carving order
+	From, To Point  // The two rooms' centers
+	Tiles    []Point
 }
 
 // NewDungeon creates a new dungeon filled with walls.
-// The rng parameter is used for all random generation (BSP splits, room placement, etc.)
+// The rng parameter is used for all random generation (BSP splits, room
+// placement, random points in rooms); the dungeon never seeds its own.
 func NewDungeon(width, height int, rng *rand.Rand) *Dungeon {
 	tiles := make([][]Tile, height)
 	for y := range tiles {
@@ -42,16 +85,41 @@ func NewDungeon(width, height int, rng *rand.Rand) *Dungeon {
 	}
 
 	return &Dungeon{
-		Width:  width,
-		Height: height,
-		Tiles:  tiles,
-		Rooms:  make([]Room, 0),
-		rng:    rng,
+		Width:   width,
+		Height:  height,
+		Tiles:   tiles,
+		Rooms:   make([]Room, 0),
+		StairsX: -1,
+		StairsY: -1,
+		rng:     rng,
+	}
+}
+
+// NewDungeonFromLayout builds a hand-authored floor from rows of characters.
+// '#' is wall and '>' the stairs down; every other character is floor, so
+// callers can use their own markers. Rows must all be the same width.
+func NewDungeonFromLayout(rows []string) *Dungeon {
+	d := NewDungeon(len(rows[0]), len(rows), nil)
+	for y, row := range rows {
+		for x, ch := range row {
+			switch Tile(ch) {
+			case TileWall:
+			case TileStairsDown:
+				d.Tiles[y][x] = TileStairsDown
+				d.StairsX, d.StairsY = x, y
+			default:
+				d.Tiles[y][x] = TileFloor
+			}
+		}
 	}
+	d.reserveFeatures()
+	return d
 }
 
 // Generate creates the dungeon layout using BSP algorithm.
 func (d *Dungeon) Generate(ctx context.Context) {
+	d.requireRNG("Generate")
+
 	tracer := telemetry.Tracer("world")
 	ctx, span := tracer.Start(ctx, "dungeon.generate")
 	defer span.End()
@@ -75,13 +143,50 @@ func (d *Dungeon) Generate(ctx context.Context) {
 	// Connect rooms with corridors
 	d.connectRooms(root)
 
+	// Place stairs down in the last room (furthest from the start)
+	d.placeStairs()
+
+	// Keep spawns off the stairs, doorways and chests
+	d.reserveFeatures()
+
 	// Record telemetry
 	span.SetAttributes(
 		attribute.Int("dungeon.width", d.Width),
 		attribute.Int("dungeon.height", d.Height),
 		attribute.Int("dungeon.room_count", len(d.Rooms)),
+		attribute.Bool("dungeon.has_stairs", d.StairsX >= 0),
+		attribute.Int("dungeon.reserved_tiles", len(d.reserved)),
 		attribute.Int64("dungeon.generation_ms", time.Since(startTime).Milliseconds()),
 	)
+
+	// Per-room and per-corridor detail is opt-in to avoid bloating every trace
+	if telemetry.Verbose() {
+		d.recordLayoutEvents(span)
+	}
+}
+
+// recordLayoutEvents adds a span event for each room and corridor carved.
+func (d *Dungeon) recordLayoutEvents(span trace.Span) {
+	for i, room := range d.Rooms {
+		span.AddEvent("dungeon.room", trace.WithAttributes(
+			attribute.Int("room.index", i),
+			attribute.Int("room.x", room.X),
+			attribute.Int("room.y", room.Y),
+			attribute.Int("room.width", room.Width),
+			attribute.Int("room.height", room.Height),
+			attribute.String("room.prefab", room.Prefab),
+		))
+	}
+	for _, c := range d.Corridors {
+		span.AddEvent("dungeon.corridor", trace.WithAttributes(
+			attribute.Int("corridor.id", c.ID),
+			attribute.Int("corridor.from_x", c.From.X),
+			attribute.Int("corridor.from_y", c.From.Y),
+			attribute.Int("corridor.to_x", c.To.X),
+			attribute.Int("corridor.to_y", c.To.Y),
+			attribute.Int("corridor.tiles", len(c.Tiles)),
+		))
+	}
 }
 
 // IsPassable returns true if the given position can be walked on.
@@ -110,11 +215,21 @@ func (d *Dungeon) RoomIndexAt(x, y int) int {
 	return -1
 }
 
-// RandomPointInRoom returns a random passable point within the specified room.
+// requireRNG panics if the dungeon has no RNG to draw from. Making one up
+// would break reproducibility from the seed.
+func (d *Dungeon) requireRNG(op string) {
+	if d.rng == nil {
+		panic("world: " + op + " needs a dungeon created by NewDungeon with a seeded rng")
+	}
+}
+
+// RandomPointInRoom returns a random passable point within the specified
+// room, drawn from the dungeon's RNG.
 func (d *Dungeon) RandomPointInRoom(roomIndex int) (int, int) {
 	if roomIndex < 0 || roomIndex >= len(d.Rooms) {
 		return -1, -1
 	}
+	d.requireRNG("RandomPointInRoom")
 	room := d.Rooms[roomIndex]
 
 	// Try random points until we find a passable one (max 100 attempts)
@@ -130,6 +245,22 @@ func (d *Dungeon) RandomPointInRoom(roomIndex int) (int, int) {
 	return room.Center()
 }
 
+// IsStairs returns true if the position holds stairs down.
+func (d *Dungeon) IsStairs(x, y int) bool {
+	return d.GetTile(x, y) == TileStairsDown
+}
+
+// placeStairs puts the stairs down at the center of the last room.
+// Room 0 is the starting room, so stairs are skipped on single-room floors.
+func (d *Dungeon) placeStairs() {
+	if len(d.Rooms) < 2 {
+		return
+	}
+	x, y := d.Rooms[len(d.Rooms)-1].Center()
+	d.Tiles[y][x] = TileStairsDown
+	d.StairsX, d.StairsY = x, y
+}
+
 // bspNode represents a node in the BSP tree.
 type bspNode struct {
 	x, y          int
@@ -221,6 +352,18 @@ func (d *Dungeon) createRooms(node *bspNode) {
 	}
 
 	if node.isLeaf() {
+		// The starting room stays a plain rectangle so the party has space
+		if len(d.Rooms) > 0 {
+			if p := d.pickPrefab(node); p != nil {
+				room := d.stamp(*p,
+					node.x+1+d.rng.Intn(node.width-p.Width-1),
+					node.y+1+d.rng.Intn(node.height-p.Height-1))
+				node.room = &room
+				d.Rooms = append(d.Rooms, room)
+				return
+			}
+		}
+
 		// Create a room within this leaf
 		roomWidth := minRoomSize + d.rng.Intn(min(maxRoomSize-minRoomSize+1, node.width-minRoomSize+1))
 		roomHeight := minRoomSize + d.rng.Intn(min(maxRoomSize-minRoomSize+1, node.height-minRoomSize+1))
@@ -304,41 +447,115 @@ func (d *Dungeon) getRoom(node *bspNode) *Room {
 	return d.getRoom(node.right)
 }
 
-// carveCorridor creates a corridor between two rooms.
+// carveCorridor creates a corridor between two rooms and records which
+// rooms it connects and which tiles it carved outside them.
 func (d *Dungeon) carveCorridor(room1, room2 Room) {
 	x1, y1 := room1.Center()
 	x2, y2 := room2.Center()
+	c := &Corridor{
+		ID:    len(d.Corridors),
+		Rooms: [2]int{d.RoomIndexAt(x1, y1), d.RoomIndexAt(x2, y2)},
+		From:  Point{x1, y1},
+		To:    Point{x2, y2},
+	}
 
 	// Randomly choose to go horizontal-then-vertical or vertical-then-horizontal
 	if d.rng.Intn(2) == 0 {
-		d.carveHorizontalTunnel(x1, x2, y1)
-		d.carveVerticalTunnel(y1, y2, x2)
+		d.carveHorizontalTunnel(c, x1, x2, y1)
+		d.carveVerticalTunnel(c, y1, y2, x2)
 	} else {
-		d.carveVerticalTunnel(y1, y2, x1)
-		d.carveHorizontalTunnel(x1, x2, y2)
+		d.carveVerticalTunnel(c, y1, y2, x1)
+		d.carveHorizontalTunnel(c, x1, x2, y2)
 	}
+	d.Corridors = append(d.Corridors, *c)
 }
 
-// carveHorizontalTunnel carves a horizontal tunnel.
-func (d *Dungeon) carveHorizontalTunnel(x1, x2, y int) {
+// carveHorizontalTunnel carves a horizontal tunnel for the corridor.
+func (d *Dungeon) carveHorizontalTunnel(c *Corridor, x1, x2, y int) {
 	if x1 > x2 {
 		x1, x2 = x2, x1
 	}
 	for x := x1; x <= x2; x++ {
-		if x > 0 && x < d.Width-1 && y > 0 && y < d.Height-1 {
-			d.Tiles[y][x] = TileFloor
-		}
+		d.carveCorridorTile(c, x, y)
 	}
 }
 
-// carveVerticalTunnel carves a vertical tunnel.
-func (d *Dungeon) carveVerticalTunnel(y1, y2, x int) {
+// carveVerticalTunnel carves a vertical tunnel for the corridor.
+func (d *Dungeon) carveVerticalTunnel(c *Corridor, y1, y2, x int) {
 	if y1 > y2 {
 		y1, y2 = y2, y1
 	}
 	for y := y1; y <= y2; y++ {
-		if x > 0 && x < d.Width-1 && y > 0 && y < d.Height-1 {
-			d.Tiles[y][x] = TileFloor
+		d.carveCorridorTile(c, x, y)
+	}
+}
+
+// carveCorridorTile carves one tile of the corridor, claiming it unless it
+// lies in a room or an earlier corridor already has it. A room tile other
+// than the ends' records the room as one the corridor passes through.
+func (d *Dungeon) carveCorridorTile(c *Corridor, x, y int) {
+	if x <= 0 || x >= d.Width-1 || y <= 0 || y >= d.Height-1 {
+		return
+	}
+	d.Tiles[y][x] = TileFloor
+	p := Point{x, y}
+	if room := d.RoomIndexAt(x, y); room >= 0 {
+		if !slices.Contains(c.Rooms[:], room) && !slices.Contains(c.Through, room) {
+			c.Through = append(c.Through, room)
 		}
+		return
+	}
+	if slices.Contains(c.Tiles, p) {
+		return
+	}
+	if _, taken := d.corridorIndex()[p]; taken {
+		return
+	}
+	c.Tiles = append(c.Tiles, p)
+	d.corridorAt[p] = c.ID
+}
+
+// corridorIndex returns the tile to corridor lookup, building it from
+// Corridors if needed, e.g. after a floor is restored from a save.
+func (d *Dungeon) corridorIndex() map[Point]int {
+	if d.corridorAt == nil {
+		d.corridorAt = make(map[Point]int)
+		for _, c := range d.Corridors {
+			for _, p := range c.Tiles {
+				if _, taken := d.corridorAt[p]; !taken {
+					d.corridorAt[p] = c.ID
+				}
+			}
+		}
+	}
+	return d.corridorAt
+}
+
+// CorridorAt returns the ID of the corridor the tile belongs to, or -1 if
+// it is in a room, in solid rock or on a hand-authored floor.
+func (d *Dungeon) CorridorAt(x, y int) int {
+	if id, ok := d.corridorIndex()[Point{x, y}]; ok {
+		return id
+	}
+	return -1
+}
+
+// RoomsConnectedBy returns the indices of the two rooms a corridor joins.
+// ok is false if there is no such corridor.
+func (d *Dungeon) RoomsConnectedBy(id int) (a, b int, ok bool) {
+	if id < 0 || id >= len(d.Corridors) {
+		return -1, -1, false
+	}
+	rooms := d.Corridors[id].Rooms
+	return rooms[0], rooms[1], true
+}
+
+// CorridorOpensInto returns true if the tile is in a corridor leading into
+// the room, at either end or where it passes through.
+func (d *Dungeon) CorridorOpensInto(x, y, room int) bool {
+	a, b, ok := d.RoomsConnectedBy(d.CorridorAt(x, y))
+	if !ok || room < 0 {
+		return false
 	}
+	return a == room || b == room || slices.Contains(d.Corridors[d.CorridorAt(x, y)].Through, room)
 }
@@ -4,6 +4,12 @@ import (
 	"context"
 	"math/rand"
 	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/samdwyer/dungeonband/internal/telemetry"
 )
 
 func TestDungeonReproducibility(t *testing.T) {
@@ -80,3 +86,103 @@ func TestDungeonDifferentSeeds(t *testing.T) {
 		t.Error("Dungeons with different seeds should not be identical")
 	}
 }
+
+func TestVerboseGenerationEmitsRoomEvents(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	countEvents := func(verbose bool) (rooms, corridors, roomCount int) {
+		recorder.Reset()
+		telemetry.SetVerbose(verbose)
+		defer telemetry.SetVerbose(false)
+
+		d := NewDungeon(DefaultWidth, DefaultHeight, rand.New(rand.NewSource(12345)))
+		d.Generate(context.Background())
+
+		for _, span := range recorder.Ended() {
+			if span.Name() != "dungeon.generate" {
+				continue
+			}
+			for _, event := range span.Events() {
+				switch event.Name {
+				case "dungeon.room":
+					rooms++
+				case "dungeon.corridor":
+					corridors++
+				}
+			}
+		}
+		return rooms, corridors, len(d.Rooms)
+	}
+
+	rooms, corridors, roomCount := countEvents(true)
+	if rooms != roomCount {
+		t.Errorf("verbose mode emitted %d room events, want %d", rooms, roomCount)
+	}
+	if corridors == 0 {
+		t.Error("verbose mode should emit corridor events")
+	}
+
+	if rooms, corridors, _ := countEvents(false); rooms != 0 || corridors != 0 {
+		t.Errorf("normal mode emitted %d room and %d corridor events, want none", rooms, corridors)
+	}
+}
+
+func TestNewDungeonFromLayout(t *testing.T) {
+	d := NewDungeonFromLayout([]string{
+		"#####",
+		"#@.>#",
+		"#####",
+	})
+
+	if d.Width != 5 || d.Height != 3 {
+		t.Fatalf("size = %dx%d, want 5x3", d.Width, d.Height)
+	}
+	if d.GetTile(1, 1) != TileFloor {
+		t.Errorf("marker tile = %q, want floor", d.GetTile(1, 1))
+	}
+	if !d.IsStairs(3, 1) || d.StairsX != 3 || d.StairsY != 1 {
+		t.Errorf("stairs at (%d,%d), want (3,1)", d.StairsX, d.StairsY)
+	}
+	if d.IsPassable(0, 0) {
+		t.Error("walls should stay impassable")
+	}
+}
+
+func TestRandomPointInRoomFollowsTheSeed(t *testing.T) {
+	points := func() [][2]int {
+		d := NewDungeon(DefaultWidth, DefaultHeight, rand.New(rand.NewSource(7)))
+		d.Generate(context.Background())
+		var pts [][2]int
+		for i := range d.Rooms {
+			x, y := d.RandomPointInRoom(i)
+			pts = append(pts, [2]int{x, y})
+		}
+		return pts
+	}
+
+	first, second := points(), points()
+	for i := range first {
+		if first[i] != second[i] {
+			t.Errorf("room %d: point %v then %v with the same seed", i, first[i], second[i])
+		}
+	}
+}
+
+func TestRandomPointInRoomNeedsAnRNG(t *testing.T) {
+	d := NewDungeonFromLayout([]string{
+		"#####",
+		"#...#",
+		"#####",
+	})
+	d.Rooms = []Room{{X: 1, Y: 1, Width: 3, Height: 1}}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("a dungeon without an rng should refuse to pick random points")
+		}
+	}()
+	d.RandomPointInRoom(0)
+}
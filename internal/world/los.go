@@ -0,0 +1,121 @@
+package world
+
+import "fmt"
+
+// SightRadius is how far (in tiles) the party and monsters can see each other.
+const SightRadius = 12
+
+// HasLineOfSight returns true if no wall blocks the straight line between two
+// points. The endpoints themselves are not checked, so a creature standing in a
+// doorway can still be seen.
+func (d *Dungeon) HasLineOfSight(x0, y0, x1, y1 int) bool {
+	dx := abs(x1 - x0)
+	dy := -abs(y1 - y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+
+	// Bresenham's line algorithm
+	err := dx + dy
+	x, y := x0, y0
+	for {
+		if x == x1 && y == y1 {
+			return true
+		}
+		if (x != x0 || y != y0) && !d.IsPassable(x, y) {
+			return false
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y += sy
+		}
+	}
+}
+
+// CanSee returns true if the two points are within radius tiles of each other
+// and have an unobstructed line of sight.
+func (d *Dungeon) CanSee(x0, y0, x1, y1, radius int) bool {
+	dx, dy := x1-x0, y1-y0
+	if dx*dx+dy*dy > radius*radius {
+		return false
+	}
+	return d.HasLineOfSight(x0, y0, x1, y1)
+}
+
+// InSight returns true if a creature at (x0, y0) can see (x1, y1): within
+// SightRadius and either in line of sight, or standing in a corridor that
+// leads into the viewer's room, since anything lurking round the bend of
+// a hallway off the room is close enough to notice.
+func (d *Dungeon) InSight(x0, y0, x1, y1 int) bool {
+	if d.CanSee(x0, y0, x1, y1, SightRadius) {
+		return true
+	}
+	dx, dy := x1-x0, y1-y0
+	return dx*dx+dy*dy <= SightRadius*SightRadius && d.CorridorOpensInto(x1, y1, d.RoomIndexAt(x0, y0))
+}
+
+// Visibility is a rule for which enemies the party can see.
+type Visibility string
+
+const (
+	VisibilitySight Visibility = "sight" // Within SightRadius, by InSight (default)
+	VisibilityRoom  Visibility = "room"  // Only in the viewer's own room or corridor
+	VisibilityAll   Visibility = "all"   // Everything on the floor, for debugging
+)
+
+// ParseVisibility checks a visibility rule by name. "" is the default,
+// VisibilitySight.
+func ParseVisibility(name string) (Visibility, error) {
+	switch v := Visibility(name); v {
+	case "":
+		return VisibilitySight, nil
+	case VisibilitySight, VisibilityRoom, VisibilityAll:
+		return v, nil
+	default:
+		return "", fmt.Errorf("unknown enemy visibility %q (want sight, room or all)", name)
+	}
+}
+
+// Sees returns true if, under the rule, a viewer at (x0, y0) sees (x1, y1).
+// An empty rule is VisibilitySight. Floors without rooms, like the
+// hand-authored ones, fall back to sight under VisibilityRoom.
+func (d *Dungeon) Sees(rule Visibility, x0, y0, x1, y1 int) bool {
+	switch rule {
+	case VisibilityAll:
+		return true
+	case VisibilityRoom:
+		if len(d.Rooms) == 0 {
+			return d.InSight(x0, y0, x1, y1)
+		}
+		return d.SameArea(x0, y0, x1, y1)
+	default:
+		return d.InSight(x0, y0, x1, y1)
+	}
+}
+
+// SameArea returns true if both points are in the same room or the same
+// corridor.
+func (d *Dungeon) SameArea(x0, y0, x1, y1 int) bool {
+	if room := d.RoomIndexAt(x0, y0); room >= 0 {
+		return room == d.RoomIndexAt(x1, y1)
+	}
+	corridor := d.CorridorAt(x0, y0)
+	return corridor >= 0 && corridor == d.CorridorAt(x1, y1)
+}
+
+// abs returns the absolute value of an int.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,78 @@
+package world
+
+import (
+	"strings"
+	"testing"
+)
+
+// dungeonFromMap builds a dungeon from an ASCII layout using '#' and '.'.
+func dungeonFromMap(layout string) *Dungeon {
+	lines := strings.Split(strings.TrimSpace(layout), "\n")
+	d := NewDungeon(len(lines[0]), len(lines), nil)
+	for y, line := range lines {
+		for x, ch := range line {
+			d.Tiles[y][x] = Tile(ch)
+		}
+	}
+	return d
+}
+
+func TestHasLineOfSightCorridor(t *testing.T) {
+	d := dungeonFromMap(`
+##########
+#....#####
+#.........
+#....#####
+##########`)
+
+	// Room interior to corridor, straight line along row 2
+	if !d.HasLineOfSight(2, 2, 8, 2) {
+		t.Error("expected line of sight down an open corridor")
+	}
+	// Steep angle from the room's corner into the corridor clips the east wall
+	if d.HasLineOfSight(3, 1, 8, 2) {
+		t.Error("expected room's east wall to block line of sight")
+	}
+}
+
+func TestHasLineOfSightCorner(t *testing.T) {
+	// L-shaped corridor: the two arms cannot see each other around the corner
+	d := dungeonFromMap(`
+#######
+#.....#
+#####.#
+#####.#
+#####.#
+#######`)
+
+	if !d.HasLineOfSight(1, 1, 5, 1) {
+		t.Error("expected line of sight along the top arm")
+	}
+	if !d.HasLineOfSight(5, 1, 5, 4) {
+		t.Error("expected line of sight along the vertical arm")
+	}
+	if d.HasLineOfSight(1, 1, 5, 4) {
+		t.Error("expected corner wall to block line of sight")
+	}
+	// Visibility is symmetric for this layout
+	if d.HasLineOfSight(5, 4, 1, 1) {
+		t.Error("expected corner wall to block reverse line of sight")
+	}
+}
+
+func TestCanSeeRadius(t *testing.T) {
+	d := dungeonFromMap(`
+####################
+#..................#
+####################`)
+
+	if !d.CanSee(1, 1, 5, 1, 4) {
+		t.Error("expected target at distance 4 to be visible with radius 4")
+	}
+	if d.CanSee(1, 1, 6, 1, 4) {
+		t.Error("expected target at distance 5 to be out of range with radius 4")
+	}
+	if !d.CanSee(3, 1, 3, 1, 0) {
+		t.Error("a point should always see itself")
+	}
+}
@@ -0,0 +1,35 @@
+package world
+
+// NoiseReach spreads a noise of the given loudness from (x, y) through
+// passable tiles, one point quieter per step, and returns how loud it is on
+// each tile it reaches. Walls stop it, so a noise carries along a corridor
+// but not through the rock beside it. Tiles it can't be heard on are left
+// out.
+func (d *Dungeon) NoiseReach(x, y, loudness int) map[Point]int {
+	reach := make(map[Point]int)
+	if loudness <= 0 || !d.IsPassable(x, y) {
+		return reach
+	}
+	start := Point{x, y}
+	reach[start] = loudness
+	queue := []Point{start}
+	directions := []Point{{0, -1}, {0, 1}, {-1, 0}, {1, 0}}
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		volume := reach[cur] - 1
+		if volume <= 0 {
+			continue
+		}
+		for _, dir := range directions {
+			next := Point{cur.X + dir.X, cur.Y + dir.Y}
+			if _, heard := reach[next]; heard || !d.IsPassable(next.X, next.Y) {
+				continue
+			}
+			reach[next] = volume
+			queue = append(queue, next)
+		}
+	}
+	return reach
+}
@@ -0,0 +1,39 @@
+package world
+
+import "testing"
+
+func TestNoiseReachFallsOffAlongPassableTiles(t *testing.T) {
+	d := dungeonFromMap(`
+#######
+#.#...#
+#.#.#.#
+#...#.#
+#######`)
+
+	reach := d.NoiseReach(1, 1, 4)
+	for _, tt := range []struct {
+		p    Point
+		want int
+	}{
+		{Point{1, 1}, 4},
+		{Point{1, 2}, 3},
+		{Point{1, 3}, 2},
+		{Point{2, 3}, 1},
+		{Point{3, 3}, 0}, // Out of earshot
+		{Point{3, 1}, 0}, // Close, but only through the wall
+	} {
+		if got := reach[tt.p]; got != tt.want {
+			t.Errorf("volume at %v = %d, want %d", tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestNoiseReachFromAWallIsSilent(t *testing.T) {
+	d := dungeonFromMap(`
+###
+#.#
+###`)
+	if reach := d.NoiseReach(0, 0, 5); len(reach) != 0 {
+		t.Errorf("NoiseReach from a wall = %v, want nothing", reach)
+	}
+}
@@ -0,0 +1,78 @@
+package world
+
+// Distance returns the Chebyshev (king-move) distance between two tiles.
+func Distance(x0, y0, x1, y1 int) int {
+	dx, dy := abs(x1-x0), abs(y1-y0)
+	if dx > dy {
+		return dx
+	}
+	return dy
+}
+
+// NextStepToward returns the first step of a shortest path from (fromX, fromY)
+// to any tile within reach of (toX, toY). Steps are cardinal, matching party
+// movement. blocked reports tiles occupied by other creatures and may be nil.
+// ok is false if the start is already within reach or no path exists.
+func (d *Dungeon) NextStepToward(fromX, fromY, toX, toY, reach int, blocked func(x, y int) bool) (x, y int, ok bool) {
+	if Distance(fromX, fromY, toX, toY) <= reach {
+		return fromX, fromY, false
+	}
+	path := d.shortestPath(fromX, fromY, func(x, y int) bool {
+		return Distance(x, y, toX, toY) <= reach
+	}, blocked)
+	if len(path) == 0 {
+		return fromX, fromY, false
+	}
+	return path[0].X, path[0].Y, true
+}
+
+// PathTo returns the steps of a shortest cardinal path from (fromX, fromY)
+// to (toX, toY), excluding the start. blocked may be nil. Returns nil if
+// the party is already there or no path exists.
+func (d *Dungeon) PathTo(fromX, fromY, toX, toY int, blocked func(x, y int) bool) []Point {
+	if fromX == toX && fromY == toY {
+		return nil
+	}
+	return d.shortestPath(fromX, fromY, func(x, y int) bool {
+		return x == toX && y == toY
+	}, blocked)
+}
+
+// shortestPath searches breadth-first from the start for the nearest
+// passable, unblocked tile that satisfies goal and returns the steps to it.
+func (d *Dungeon) shortestPath(fromX, fromY int, goal func(x, y int) bool, blocked func(x, y int) bool) []Point {
+	start := Point{fromX, fromY}
+	parent := map[Point]Point{start: start}
+	queue := []Point{start}
+	directions := []Point{{0, -1}, {0, 1}, {-1, 0}, {1, 0}}
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		for _, dir := range directions {
+			next := Point{cur.X + dir.X, cur.Y + dir.Y}
+			if _, seen := parent[next]; seen {
+				continue
+			}
+			if !d.IsPassable(next.X, next.Y) || (blocked != nil && blocked(next.X, next.Y)) {
+				continue
+			}
+			parent[next] = cur
+
+			if goal(next.X, next.Y) {
+				// Walk back to the start, then reverse
+				var path []Point
+				for p := next; p != start; p = parent[p] {
+					path = append(path, p)
+				}
+				for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+					path[i], path[j] = path[j], path[i]
+				}
+				return path
+			}
+			queue = append(queue, next)
+		}
+	}
+	return nil
+}
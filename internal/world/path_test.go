@@ -0,0 +1,70 @@
+package world
+
+import "testing"
+
+func TestNextStepTowardAroundWall(t *testing.T) {
+	d := dungeonFromMap(`
+#######
+#.#...#
+#.#.#.#
+#...#.#
+#######`)
+
+	// From (1,1) the only way to (5,1) is down and around both walls
+	x, y, ok := d.NextStepToward(1, 1, 5, 1, 1, nil)
+	if !ok || x != 1 || y != 2 {
+		t.Errorf("NextStepToward = (%d,%d,%v), want (1,2,true)", x, y, ok)
+	}
+}
+
+func TestNextStepTowardAlreadyInReach(t *testing.T) {
+	d := dungeonFromMap(`
+#####
+#...#
+#####`)
+
+	if _, _, ok := d.NextStepToward(1, 1, 2, 1, 1, nil); ok {
+		t.Error("expected no step when already adjacent")
+	}
+}
+
+func TestNextStepTowardBlocked(t *testing.T) {
+	d := dungeonFromMap(`
+#######
+#.....#
+#######`)
+
+	// A creature filling the corridor leaves no path
+	blocked := func(x, y int) bool { return x == 3 && y == 1 }
+	if _, _, ok := d.NextStepToward(1, 1, 5, 1, 1, blocked); ok {
+		t.Error("expected no path through an occupied corridor")
+	}
+}
+
+func TestPathToAroundWall(t *testing.T) {
+	d := dungeonFromMap(`
+#######
+#.#...#
+#.#.#.#
+#...#.#
+#######`)
+
+	path := d.PathTo(1, 1, 5, 3, nil)
+	want := []Point{{1, 2}, {1, 3}, {2, 3}, {3, 3}, {3, 2}, {3, 1}, {4, 1}, {5, 1}, {5, 2}, {5, 3}}
+	if len(path) != len(want) {
+		t.Fatalf("PathTo = %v, want %v", path, want)
+	}
+	for i := range want {
+		if path[i] != want[i] {
+			t.Fatalf("PathTo = %v, want %v", path, want)
+		}
+	}
+
+	if path := d.PathTo(1, 1, 1, 1, nil); path != nil {
+		t.Errorf("PathTo own tile = %v, want nil", path)
+	}
+	blocked := func(x, y int) bool { return x == 3 && y == 2 }
+	if path := d.PathTo(1, 1, 5, 3, blocked); path != nil {
+		t.Errorf("PathTo through a blocked chokepoint = %v, want nil", path)
+	}
+}
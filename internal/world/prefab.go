@@ -0,0 +1,157 @@
+package world
+
+import "fmt"
+
+// Prefab layout characters. Markers stand on floor.
+const (
+	PrefabWall  = '#'
+	PrefabFloor = '.'
+	PrefabSpawn = 'E' // Enemy spawn point
+	PrefabChest = 'C' // Chest spot
+	PrefabTrap  = 'T' // Trap spot
+)
+
+// prefabOdds is the 1-in-N chance that a leaf big enough for a prefab gets
+// one instead of a plain rectangle.
+const prefabOdds = 3
+
+// Point is a map position.
+type Point struct {
+	X, Y int
+}
+
+// Prefab is a hand-authored room layout stamped into the dungeon in place
+// of a plain rectangle. Marker positions are relative to its top-left corner.
+type Prefab struct {
+	ID            string
+	Width, Height int
+	tiles         [][]Tile
+	spawns        []Point
+	chests        []Point
+	traps         []Point
+}
+
+// ParsePrefab builds a prefab from rows of layout characters. The layout
+// must be a rectangle at least minRoomSize in each dimension, use only known
+// characters, and have floor at its center with every floor tile reachable
+// from there, since corridors connect rooms center to center.
+func ParsePrefab(id string, rows []string) (Prefab, error) {
+	p := Prefab{ID: id}
+	if len(rows) == 0 {
+		return p, fmt.Errorf("prefab %q: empty layout", id)
+	}
+	p.Width, p.Height = len(rows[0]), len(rows)
+	if p.Width < minRoomSize || p.Height < minRoomSize {
+		return p, fmt.Errorf("prefab %q: %dx%d is smaller than %dx%d", id, p.Width, p.Height, minRoomSize, minRoomSize)
+	}
+	if p.Width > maxRoomSize || p.Height > maxRoomSize {
+		return p, fmt.Errorf("prefab %q: %dx%d is larger than %dx%d", id, p.Width, p.Height, maxRoomSize, maxRoomSize)
+	}
+
+	p.tiles = make([][]Tile, p.Height)
+	for y, row := range rows {
+		if len(row) != p.Width {
+			return p, fmt.Errorf("prefab %q: row %d is %d wide, want %d", id, y, len(row), p.Width)
+		}
+		p.tiles[y] = make([]Tile, p.Width)
+		for x, ch := range row {
+			p.tiles[y][x] = TileFloor
+			switch ch {
+			case PrefabWall:
+				p.tiles[y][x] = TileWall
+			case PrefabFloor:
+			case PrefabSpawn:
+				p.spawns = append(p.spawns, Point{x, y})
+			case PrefabChest:
+				p.chests = append(p.chests, Point{x, y})
+			case PrefabTrap:
+				p.traps = append(p.traps, Point{x, y})
+			default:
+				return p, fmt.Errorf("prefab %q: unknown character %q at (%d,%d)", id, ch, x, y)
+			}
+		}
+	}
+
+	cx, cy := p.Width/2, p.Height/2
+	if p.tiles[cy][cx] != TileFloor {
+		return p, fmt.Errorf("prefab %q: center (%d,%d) must be floor", id, cx, cy)
+	}
+	if x, y, ok := p.unreachableFloor(cx, cy); ok {
+		return p, fmt.Errorf("prefab %q: floor at (%d,%d) is not reachable from the center", id, x, y)
+	}
+	if len(p.spawns) == 0 {
+		return p, fmt.Errorf("prefab %q: no enemy spawn markers", id)
+	}
+	return p, nil
+}
+
+// unreachableFloor flood-fills from (sx, sy) and returns the first floor
+// tile it could not reach, if any.
+func (p *Prefab) unreachableFloor(sx, sy int) (int, int, bool) {
+	seen := make([][]bool, p.Height)
+	for y := range seen {
+		seen[y] = make([]bool, p.Width)
+	}
+	stack := []Point{{sx, sy}}
+	seen[sy][sx] = true
+	for len(stack) > 0 {
+		pt := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, d := range []Point{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+			x, y := pt.X+d.X, pt.Y+d.Y
+			if x < 0 || x >= p.Width || y < 0 || y >= p.Height || seen[y][x] || p.tiles[y][x] != TileFloor {
+				continue
+			}
+			seen[y][x] = true
+			stack = append(stack, Point{x, y})
+		}
+	}
+	for y := range p.tiles {
+		for x, tile := range p.tiles[y] {
+			if tile == TileFloor && !seen[y][x] {
+				return x, y, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// stamp carves the prefab with its top-left corner at (x, y) and returns
+// the resulting room, with its markers translated to map positions.
+func (d *Dungeon) stamp(p Prefab, x, y int) Room {
+	room := Room{X: x, Y: y, Width: p.Width, Height: p.Height, Prefab: p.ID}
+	for py, row := range p.tiles {
+		for px, tile := range row {
+			d.Tiles[y+py][x+px] = tile
+		}
+	}
+	for _, s := range p.spawns {
+		room.Spawns = append(room.Spawns, Point{x + s.X, y + s.Y})
+	}
+	for _, c := range p.chests {
+		room.Chests = append(room.Chests, Point{x + c.X, y + c.Y})
+	}
+	for _, t := range p.traps {
+		room.Traps = append(room.Traps, Point{x + t.X, y + t.Y})
+	}
+	return room
+}
+
+// pickPrefab returns a prefab that fits in the leaf, or nil if the leaf gets
+// a plain room. Consumes no randomness when no prefabs are loaded, so
+// prefab-free generation stays reproducible.
+func (d *Dungeon) pickPrefab(node *bspNode) *Prefab {
+	if len(d.Prefabs) == 0 {
+		return nil
+	}
+	var fits []int
+	for i, p := range d.Prefabs {
+		if p.Width <= node.width-2 && p.Height <= node.height-2 {
+			fits = append(fits, i)
+		}
+	}
+	if len(fits) == 0 || d.rng.Intn(prefabOdds) != 0 {
+		return nil
+	}
+	return &d.Prefabs[fits[d.rng.Intn(len(fits))]]
+}
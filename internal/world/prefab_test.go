@@ -0,0 +1,172 @@
+package world
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+var testPrefab = []string{
+	"..........",
+	".E......E.",
+	"..##..##..",
+	"..#....#..",
+	"....C.....",
+	"..#....#..",
+	"..##..##..",
+	".E..T...E.",
+}
+
+func TestParsePrefab(t *testing.T) {
+	p, err := ParsePrefab("test", testPrefab)
+	if err != nil {
+		t.Fatalf("ParsePrefab: %v", err)
+	}
+	if p.Width != 10 || p.Height != 8 {
+		t.Errorf("size = %dx%d, want 10x8", p.Width, p.Height)
+	}
+	if len(p.spawns) != 4 || len(p.chests) != 1 || len(p.traps) != 1 {
+		t.Errorf("markers = %d spawns, %d chests, %d traps; want 4, 1, 1", len(p.spawns), len(p.chests), len(p.traps))
+	}
+}
+
+func TestParsePrefabRejectsBadLayouts(t *testing.T) {
+	walled := append([]string(nil), testPrefab...)
+	walled[4] = "....C#...."
+	sealed := append([]string(nil), testPrefab...)
+	sealed[0] = "...#......"
+	sealed[1] = ".E.#....E."
+	sealed[2] = "####..##.."
+
+	tests := []struct {
+		name string
+		rows []string
+		want string
+	}{
+		{"empty", nil, "empty layout"},
+		{"too small", []string{".E.", "...", "..."}, "smaller than"},
+		{"too large", []string{strings.Repeat(".", 16), ".E", ".", ".", ".", ".", ".", "."}, "larger than"},
+		{"ragged", append(append([]string(nil), testPrefab[:7]...), "........."), "row 7 is 9 wide"},
+		{"unknown char", append(append([]string(nil), testPrefab[:7]...), ".E..X...E."), "unknown character 'X'"},
+		{"walled center", walled, "center (5,4) must be floor"},
+		{"sealed corner", sealed, "floor at (0,0) is not reachable"},
+		{"no spawns", []string{"........", "........", "........", "........", "........", "........", "........", "........"}, "no enemy spawn markers"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePrefab("bad", tt.rows)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParsePrefab() = %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func generateWithPrefabs(t *testing.T, seed int64) *Dungeon {
+	t.Helper()
+	p, err := ParsePrefab("test", testPrefab)
+	if err != nil {
+		t.Fatalf("ParsePrefab: %v", err)
+	}
+	d := NewDungeon(DefaultWidth, DefaultHeight, rand.New(rand.NewSource(seed)))
+	d.Prefabs = []Prefab{p}
+	d.Generate(context.Background())
+	return d
+}
+
+func TestPrefabGenerationIsDeterministic(t *testing.T) {
+	for seed := int64(1); seed <= 50; seed++ {
+		d1, d2 := generateWithPrefabs(t, seed), generateWithPrefabs(t, seed)
+		for y := range d1.Tiles {
+			if string(tileRow(d1.Tiles[y])) != string(tileRow(d2.Tiles[y])) {
+				t.Fatalf("seed %d: row %d differs between runs", seed, y)
+			}
+		}
+	}
+}
+
+func TestPrefabFloorsStayConnected(t *testing.T) {
+	stamped := 0
+	for seed := int64(1); seed <= 200; seed++ {
+		d := generateWithPrefabs(t, seed)
+		if len(d.Rooms) == 0 {
+			t.Fatalf("seed %d: no rooms", seed)
+		}
+		if d.Rooms[0].Prefab != "" {
+			t.Errorf("seed %d: the starting room should never be a prefab", seed)
+		}
+
+		// Every passable tile must be reachable from the starting room
+		sx, sy := d.Rooms[0].Center()
+		reached := floodFill(d, sx, sy)
+		for y := 0; y < d.Height; y++ {
+			for x := 0; x < d.Width; x++ {
+				if d.IsPassable(x, y) && !reached[y][x] {
+					t.Fatalf("seed %d: (%d,%d) is cut off from the start", seed, x, y)
+				}
+			}
+		}
+
+		for i, room := range d.Rooms {
+			if room.Prefab == "" {
+				continue
+			}
+			stamped++
+			for _, s := range room.Spawns {
+				if !room.Contains(s.X, s.Y) || !d.IsPassable(s.X, s.Y) {
+					t.Errorf("seed %d room %d: spawn (%d,%d) is not open floor in the room", seed, i, s.X, s.Y)
+				}
+			}
+		}
+	}
+	if stamped == 0 {
+		t.Error("no prefab was stamped in 200 seeds")
+	}
+}
+
+func TestNoPrefabsKeepsLayout(t *testing.T) {
+	plain := NewDungeon(DefaultWidth, DefaultHeight, rand.New(rand.NewSource(7)))
+	plain.Generate(context.Background())
+	empty := NewDungeon(DefaultWidth, DefaultHeight, rand.New(rand.NewSource(7)))
+	empty.Prefabs = []Prefab{}
+	empty.Generate(context.Background())
+
+	for y := range plain.Tiles {
+		if string(tileRow(plain.Tiles[y])) != string(tileRow(empty.Tiles[y])) {
+			t.Fatalf("row %d differs with an empty prefab list", y)
+		}
+	}
+}
+
+// tileRow converts a row of tiles to runes for comparison.
+func tileRow(row []Tile) []rune {
+	runes := make([]rune, len(row))
+	for i, tile := range row {
+		runes[i] = tile.Rune()
+	}
+	return runes
+}
+
+// floodFill returns which tiles are reachable from (sx, sy).
+func floodFill(d *Dungeon, sx, sy int) [][]bool {
+	reached := make([][]bool, d.Height)
+	for y := range reached {
+		reached[y] = make([]bool, d.Width)
+	}
+	stack := []Point{{sx, sy}}
+	reached[sy][sx] = true
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, step := range []Point{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+			x, y := p.X+step.X, p.Y+step.Y
+			if d.IsPassable(x, y) && !reached[y][x] {
+				reached[y][x] = true
+				stack = append(stack, Point{x, y})
+			}
+		}
+	}
+	return reached
+}
@@ -0,0 +1,101 @@
+package world
+
+import (
+	"cmp"
+	"slices"
+)
+
+// openPointAttempts bounds how many random tiles OpenPointInRoom tries
+// before giving up on a room.
+const openPointAttempts = 100
+
+// Reserve marks a tile that enemies and items must not be placed on.
+func (d *Dungeon) Reserve(x, y int) {
+	if d.reserved == nil {
+		d.reserved = make(map[Point]bool)
+	}
+	d.reserved[Point{x, y}] = true
+}
+
+// ReserveAround reserves a tile and its eight neighbors.
+func (d *Dungeon) ReserveAround(x, y int) {
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			d.Reserve(x+dx, y+dy)
+		}
+	}
+}
+
+// IsReserved returns true if nothing may be placed on the tile.
+func (d *Dungeon) IsReserved(x, y int) bool {
+	return d.reserved[Point{x, y}]
+}
+
+// Reserved returns the reserved tiles in row order.
+func (d *Dungeon) Reserved() []Point {
+	points := make([]Point, 0, len(d.reserved))
+	for p := range d.reserved {
+		points = append(points, p)
+	}
+	slices.SortFunc(points, func(a, b Point) int {
+		return cmp.Or(cmp.Compare(a.Y, b.Y), cmp.Compare(a.X, b.X))
+	})
+	return points
+}
+
+// reserveFeatures reserves the floor's fixed features: the stairs and the
+// tiles around them, each doorway where a corridor enters a room, and
+// prefab chest spots.
+func (d *Dungeon) reserveFeatures() {
+	if d.StairsX >= 0 {
+		d.ReserveAround(d.StairsX, d.StairsY)
+	}
+	for _, room := range d.Rooms {
+		for _, p := range d.doorways(room) {
+			d.Reserve(p.X, p.Y)
+		}
+		for _, c := range room.Chests {
+			d.Reserve(c.X, c.Y)
+		}
+	}
+}
+
+// doorways returns the room's edge tiles that open onto a passable tile
+// outside the room, which is where corridors come in.
+func (d *Dungeon) doorways(room Room) []Point {
+	var points []Point
+	for y := room.Y; y < room.Y+room.Height; y++ {
+		for x := room.X; x < room.X+room.Width; x++ {
+			if !d.IsPassable(x, y) {
+				continue
+			}
+			for _, n := range [4]Point{{x - 1, y}, {x + 1, y}, {x, y - 1}, {x, y + 1}} {
+				if !room.Contains(n.X, n.Y) && d.IsPassable(n.X, n.Y) {
+					points = append(points, Point{x, y})
+					break
+				}
+			}
+		}
+	}
+	return points
+}
+
+// OpenPointInRoom returns a random passable, unreserved point within the
+// room, drawn from the dungeon's RNG. ok is false if none turned up within
+// a bounded number of tries, and the caller should place nothing.
+func (d *Dungeon) OpenPointInRoom(roomIndex int) (x, y int, ok bool) {
+	if roomIndex < 0 || roomIndex >= len(d.Rooms) {
+		return -1, -1, false
+	}
+	d.requireRNG("OpenPointInRoom")
+	room := d.Rooms[roomIndex]
+
+	for range openPointAttempts {
+		x := room.X + d.rng.Intn(room.Width)
+		y := room.Y + d.rng.Intn(room.Height)
+		if d.IsPassable(x, y) && !d.IsStairs(x, y) && !d.IsReserved(x, y) {
+			return x, y, true
+		}
+	}
+	return -1, -1, false
+}
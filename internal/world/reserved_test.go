@@ -0,0 +1,84 @@
+package world
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+)
+
+// featureFloor is a room entered by a corridor on its west side, with the
+// stairs at (8,3). The room spans (2,1)-(8,5).
+var featureFloor = []string{
+	"###########",
+	"##.......##",
+	"##.......##",
+	"........>##",
+	"##.......##",
+	"##.......##",
+	"###########",
+}
+
+// featureDungeon builds featureFloor with a seeded rng and a chest spot.
+func featureDungeon(seed int64) *Dungeon {
+	layout := NewDungeonFromLayout(featureFloor)
+	d := NewDungeon(layout.Width, layout.Height, rand.New(rand.NewSource(seed)))
+	d.Tiles, d.StairsX, d.StairsY = layout.Tiles, layout.StairsX, layout.StairsY
+	d.Rooms = []Room{{X: 2, Y: 1, Width: 7, Height: 5, Chests: []Point{{7, 1}}}}
+	d.reserveFeatures()
+	return d
+}
+
+func TestFeaturesAreReserved(t *testing.T) {
+	d := featureDungeon(1)
+	for _, p := range []Point{
+		{8, 3}, {7, 2}, {7, 4}, // Stairs and around them
+		{2, 3}, // Where the corridor enters
+		{7, 1}, // Chest
+	} {
+		if !d.IsReserved(p.X, p.Y) {
+			t.Errorf("%v should be reserved", p)
+		}
+	}
+	if d.IsReserved(3, 1) {
+		t.Error("open floor should not be reserved")
+	}
+}
+
+func TestOpenPointInRoomAvoidsReservedTiles(t *testing.T) {
+	for seed := int64(1); seed <= 200; seed++ {
+		d := featureDungeon(seed)
+		d.ReserveAround(4, 3) // Party entry
+		for range 20 {
+			x, y, ok := d.OpenPointInRoom(0)
+			if !ok {
+				t.Fatalf("seed %d: no open point in a mostly open room", seed)
+			}
+			if d.IsReserved(x, y) || d.IsStairs(x, y) {
+				t.Fatalf("seed %d: picked reserved tile (%d,%d)", seed, x, y)
+			}
+		}
+	}
+}
+
+func TestOpenPointInRoomGivesUpWhenFull(t *testing.T) {
+	d := featureDungeon(1)
+	room := d.Rooms[0]
+	for y := room.Y; y < room.Y+room.Height; y++ {
+		for x := room.X; x < room.X+room.Width; x++ {
+			d.Reserve(x, y)
+		}
+	}
+	if _, _, ok := d.OpenPointInRoom(0); ok {
+		t.Error("a fully reserved room should have no open point")
+	}
+}
+
+func TestGenerateReservesStairs(t *testing.T) {
+	for seed := int64(1); seed <= 50; seed++ {
+		d := NewDungeon(DefaultWidth, DefaultHeight, rand.New(rand.NewSource(seed)))
+		d.Generate(context.Background())
+		if d.StairsX >= 0 && !d.IsReserved(d.StairsX, d.StairsY) {
+			t.Errorf("seed %d: stairs not reserved", seed)
+		}
+	}
+}
@@ -4,6 +4,12 @@ package world
 type Room struct {
 	X, Y          int // Top-left corner position
 	Width, Height int // Dimensions of the room
+
+	// Set when the room was stamped from a prefab
+	Prefab string
+	Spawns []Point // Enemy spawn points
+	Chests []Point // Chest spots
+	Traps  []Point // Trap spots
 }
 
 // Center returns the center coordinates of the room.
@@ -0,0 +1,26 @@
+package world
+
+// Shrine is a one-use blessing the party can take by stepping on it.
+type Shrine struct {
+	X    int  `json:"x"`
+	Y    int  `json:"y"`
+	Used bool `json:"used"` // Blessing taken; the shrine is spent
+}
+
+// ShrineAt returns the shrine at the position, or nil if there is none.
+func (d *Dungeon) ShrineAt(x, y int) *Shrine {
+	for i := range d.Shrines {
+		if d.Shrines[i].X == x && d.Shrines[i].Y == y {
+			return &d.Shrines[i]
+		}
+	}
+	return nil
+}
+
+// AddShrine puts an unused shrine on the tile and reserves it, so nothing
+// else is placed on top.
+func (d *Dungeon) AddShrine(x, y int) *Shrine {
+	d.Shrines = append(d.Shrines, Shrine{X: x, Y: y})
+	d.Reserve(x, y)
+	return &d.Shrines[len(d.Shrines)-1]
+}
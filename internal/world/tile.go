@@ -9,11 +9,13 @@ const (
 	TileWall Tile = '#'
 	// TileFloor represents a passable floor tile.
 	TileFloor Tile = '.'
+	// TileStairsDown represents stairs leading to the next floor.
+	TileStairsDown Tile = '>'
 )
 
 // IsPassable returns true if the tile can be walked on.
 func (t Tile) IsPassable() bool {
-	return t == TileFloor
+	return t == TileFloor || t == TileStairsDown
 }
 
 // Rune returns the tile's display character.
@@ -0,0 +1,155 @@
+// Package combat is DungeonBand's ability resolver for use outside the
+// game. It resolves data-driven abilities between any two Combatants,
+// with definitions loaded from JSON the caller provides or from the
+// game's own data.
+//
+// The types are aliases of the ones the game uses, so values pass freely
+// between this package and the game. That also exposes every field and
+// method the game gives them, but only part of it is the stable API: the
+// functions and constants declared here, the Combatant and DamageFormula
+// interfaces, EffectResolver's Resolve and ResolveAll, the registries'
+// GetByID and All, the definitions' fields that mirror the data files,
+// and EffectResult's and StatusEffect's fields. Those keep their meaning
+// across releases, new fields may be added, and data files in an older
+// schema version are migrated when read, with a logged warning for
+// anything the migration had to assume. Anything else reachable through
+// the aliases follows the game and may change in any release.
+package combat
+
+import (
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/samdwyer/dungeonband/internal/combat"
+	"github.com/samdwyer/dungeonband/internal/gamedata"
+)
+
+// Combatant is anything that can take part in a fight: it has stats,
+// takes damage and healing, spends MP and carries status effects.
+type Combatant = combat.Combatant
+
+// EffectResolver works out and applies the effects of abilities.
+type EffectResolver = combat.EffectResolver
+
+// EffectResult is the outcome of resolving an ability on one target.
+type EffectResult = combat.EffectResult
+
+// StatusEffect is a status active on a combatant.
+type StatusEffect = combat.StatusEffect
+
+// StatusTick is what one status did when a combatant's statuses ticked.
+type StatusTick = combat.StatusTick
+
+// StatusEffectType names a status, e.g. "poison".
+type StatusEffectType = gamedata.StatusEffectType
+
+// The statuses abilities can apply.
+const (
+	StatusNone        = gamedata.StatusNone
+	StatusPoison      = gamedata.StatusPoison
+	StatusRegen       = gamedata.StatusRegen
+	StatusDefenseUp   = gamedata.StatusDefenseUp
+	StatusDefenseDown = gamedata.StatusDefenseDown
+	StatusAttackUp    = gamedata.StatusAttackUp
+	StatusAttackDown  = gamedata.StatusAttackDown
+	StatusSilence     = gamedata.StatusSilence
+	StatusTaunt       = gamedata.StatusTaunt
+	StatusHaste       = gamedata.StatusHaste // Extra action every other round
+	StatusSlow        = gamedata.StatusSlow  // Acts last, and not at all every third round
+	StatusDelay       = gamedata.StatusDelay // Loses its remaining actions this round
+)
+
+// DamageFormula combines an ability's power with offense and defense.
+type DamageFormula = combat.DamageFormula
+
+// AbilityDef is one ability's definition.
+type AbilityDef = gamedata.AbilityDef
+
+// AbilityRegistry looks up ability definitions by ID.
+type AbilityRegistry = gamedata.AbilityRegistry
+
+// EnemyDef is one enemy's definition.
+type EnemyDef = gamedata.EnemyDef
+
+// EnemyRegistry looks up enemy definitions by ID and spawns them by weight.
+type EnemyRegistry = gamedata.EnemyRegistry
+
+// ClassDef is one party class's definition.
+type ClassDef = gamedata.ClassDef
+
+// ClassRegistry looks up class definitions by ID.
+type ClassRegistry = gamedata.ClassRegistry
+
+// NewEffectResolver returns a resolver that looks abilities up in the
+// registry and uses the additive damage formula.
+func NewEffectResolver(abilities *AbilityRegistry) *EffectResolver {
+	return combat.NewEffectResolver(abilities)
+}
+
+// FormulaFor returns the damage formula with the given name, "additive"
+// or "multiplicative". "" is additive.
+func FormulaFor(name string) (DamageFormula, error) {
+	return combat.FormulaFor(gamedata.DamageFormula(name))
+}
+
+// ReadAbilities reads ability definitions laid out like the game's
+// abilities.json: {"schemaVersion": 2, "abilities": [...]}.
+func ReadAbilities(r io.Reader) (*AbilityRegistry, error) {
+	file, err := read[gamedata.AbilitiesFile](r, gamedata.AbilitiesFileName)
+	if err != nil {
+		return nil, err
+	}
+	return gamedata.NewAbilityRegistry(file.Abilities), nil
+}
+
+// ReadEnemies reads enemy definitions laid out like the game's
+// enemies.json: {"schemaVersion": 2, "enemies": [...]}.
+func ReadEnemies(r io.Reader) (*EnemyRegistry, error) {
+	file, err := read[gamedata.EnemiesFile](r, gamedata.EnemiesFileName)
+	if err != nil {
+		return nil, err
+	}
+	return gamedata.NewEnemyRegistry(file.Enemies), nil
+}
+
+// ReadClasses reads class definitions laid out like the game's
+// classes.json: {"classes": [...]}.
+func ReadClasses(r io.Reader) (*ClassRegistry, error) {
+	file, err := read[gamedata.ClassesFile](r, gamedata.ClassesFileName)
+	if err != nil {
+		return nil, err
+	}
+	return gamedata.NewClassRegistry(file.Classes), nil
+}
+
+// read parses r as the named data file. What migrating an older schema
+// version assumed is logged as a warning, as the game does for its own
+// data.
+func read[T any](r io.Reader, filename string) (T, error) {
+	var file T
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return file, fmt.Errorf("failed to read %s: %w", filename, err)
+	}
+	file, warnings, err := gamedata.Parse[T](filename, content)
+	for _, w := range warnings {
+		log.Printf("Warning: %s: %s", filename, w)
+	}
+	return file, err
+}
+
+// DefaultAbilities returns the abilities the game ships with.
+func DefaultAbilities() (*AbilityRegistry, error) {
+	return gamedata.LoadAbilityRegistry()
+}
+
+// DefaultEnemies returns the enemies the game ships with.
+func DefaultEnemies() (*EnemyRegistry, error) {
+	return gamedata.LoadEnemyRegistry()
+}
+
+// DefaultClasses returns the party classes the game ships with.
+func DefaultClasses() (*ClassRegistry, error) {
+	return gamedata.LoadClassRegistry()
+}
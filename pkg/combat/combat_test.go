@@ -0,0 +1,28 @@
+package combat_test
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/samdwyer/dungeonband/pkg/combat"
+)
+
+func TestReadLogsMigrationWarnings(t *testing.T) {
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	_, err := combat.ReadAbilities(strings.NewReader(`{"abilities": [{
+		"id": "slash", "name": "Slash", "description": "A quick cut",
+		"effectType": "damage", "targetType": "single_enemy", "basePower": 6
+	}]}`))
+	if err != nil {
+		t.Fatalf("ReadAbilities: %v", err)
+	}
+	if !strings.Contains(logged.String(), "abilities.json: schema v1") {
+		t.Errorf("log = %q, want the schema v1 migration warning", logged.String())
+	}
+}
@@ -0,0 +1,106 @@
+package combat_test
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/samdwyer/dungeonband/pkg/combat"
+)
+
+// fighter is a minimal Combatant.
+type fighter struct {
+	name          string
+	hp, maxHP     int
+	mp            int
+	attack, armor int
+	abilities     []string
+	statuses      []combat.StatusEffect
+}
+
+func (f *fighter) GetName() string          { return f.name }
+func (f *fighter) IsAlive() bool            { return f.hp > 0 }
+func (f *fighter) GetHP() int               { return f.hp }
+func (f *fighter) GetMaxHP() int            { return f.maxHP }
+func (f *fighter) GetMP() int               { return f.mp }
+func (f *fighter) GetMaxMP() int            { return f.mp }
+func (f *fighter) GetAttack() int           { return f.attack }
+func (f *fighter) GetDefense() int          { return f.armor }
+func (f *fighter) GetMagic() int            { return 0 }
+func (f *fighter) GetResist() int           { return 0 }
+func (f *fighter) GetAbilityIDs() []string  { return f.abilities }
+func (f *fighter) RestoreMP(amount int) int { return 0 }
+
+func (f *fighter) TakeDamage(amount int) int {
+	amount = min(amount, f.hp)
+	f.hp -= amount
+	return amount
+}
+
+func (f *fighter) Heal(amount int) int {
+	amount = min(amount, f.maxHP-f.hp)
+	f.hp += amount
+	return amount
+}
+
+func (f *fighter) SpendMP(amount int) bool {
+	if f.mp < amount {
+		return false
+	}
+	f.mp -= amount
+	return true
+}
+
+func (f *fighter) GetStatusEffects() []combat.StatusEffect { return f.statuses }
+func (f *fighter) AddStatusEffect(effect combat.StatusEffect) {
+	f.statuses = append(f.statuses, effect)
+}
+func (f *fighter) RemoveStatusEffect(combat.StatusEffectType) {}
+func (f *fighter) TickStatusEffects() []combat.StatusTick     { return nil }
+
+func Example() {
+	abilities, err := combat.ReadAbilities(strings.NewReader(`{
+		"schemaVersion": 2,
+		"abilities": [{
+			"id": "slash", "name": "Slash", "description": "A quick cut",
+			"effectType": "damage", "targetType": "single_enemy",
+			"damageType": "physical", "basePower": 6
+		}]
+	}`))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	resolver := combat.NewEffectResolver(abilities)
+	knight := &fighter{name: "Knight", hp: 30, maxHP: 30, attack: 4, abilities: []string{"slash"}}
+	troll := &fighter{name: "Troll", hp: 40, maxHP: 40, armor: 2}
+
+	result := resolver.Resolve(abilities.GetByID("slash"), knight, troll)
+	fmt.Println(result.Damage, troll.GetHP())
+	// Output: 8 32
+}
+
+func ExampleReadEnemies() {
+	enemies, err := combat.ReadEnemies(strings.NewReader(`{
+		"schemaVersion": 2,
+		"enemies": [{
+			"id": "slime", "name": "Slime", "glyph": "s", "color": "#00FF00",
+			"hp": 12, "attack": 2, "spawnWeight": 1, "abilities": ["attack"]
+		}]
+	}`))
+	if err != nil {
+		log.Fatal(err)
+	}
+	slime := enemies.GetByID("slime")
+	fmt.Println(slime.Name, slime.HP)
+	// Output: Slime 12
+}
+
+func ExampleFormulaFor() {
+	formula, err := combat.FormulaFor("multiplicative")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(formula.Damage(10, 5, 5))
+	// Output: 10
+}
@@ -0,0 +1,123 @@
+// Package dungeon is DungeonBand's BSP dungeon generator for use outside
+// the game. Generate carves rooms and corridors into a grid from a seed,
+// and Rows and WriteText export the result as text.
+//
+// The types are aliases of the ones the game uses, so values pass freely
+// between this package and the game. That also exposes every field and
+// method the game gives them, but only part of it is the stable API: the
+// functions, constants and Options declared here, Dungeon's Width,
+// Height, Tiles, Rooms, Corridors and stairs position, and its GetTile and
+// IsPassable methods. Those keep their meaning across releases, and new
+// Options fields default to the current behavior when left zero. Anything
+// else reachable through the aliases follows the game and may change in
+// any release.
+package dungeon
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"strings"
+
+	"github.com/samdwyer/dungeonband/internal/world"
+)
+
+// Dungeon is a generated floor: its tiles, rooms, corridors and stairs.
+type Dungeon = world.Dungeon
+
+// Tile is one map cell, drawn as its rune.
+type Tile = world.Tile
+
+// Room is a rectangular room carved into the map.
+type Room = world.Room
+
+// Corridor is a hallway carved between two rooms.
+type Corridor = world.Corridor
+
+// Point is a map position.
+type Point = world.Point
+
+// Prefab is a hand-authored room layout that may replace a plain room.
+type Prefab = world.Prefab
+
+// Map tiles.
+const (
+	TileWall       = world.TileWall
+	TileFloor      = world.TileFloor
+	TileStairsDown = world.TileStairsDown
+)
+
+// The game's map size.
+const (
+	DefaultWidth  = world.DefaultWidth
+	DefaultHeight = world.DefaultHeight
+)
+
+// Options configures Generate. The zero value generates a default-sized
+// floor from seed 0.
+type Options struct {
+	// Width and Height are the map size in tiles; 0 uses the defaults.
+	Width, Height int
+
+	// Seed seeds the generator, so a seed always gives the same floor.
+	Seed int64
+
+	// Rand, if set, is used instead of a generator seeded from Seed.
+	Rand *rand.Rand
+
+	// Prefabs are hand-authored rooms that may stand in for plain ones.
+	Prefabs []Prefab
+}
+
+// Generate carves a new floor: rooms in a binary space partition of the
+// map, corridors joining them, and stairs down in the last room.
+func Generate(ctx context.Context, opts Options) *Dungeon {
+	width, height := opts.Width, opts.Height
+	if width == 0 {
+		width = DefaultWidth
+	}
+	if height == 0 {
+		height = DefaultHeight
+	}
+	rng := opts.Rand
+	if rng == nil {
+		rng = rand.New(rand.NewSource(opts.Seed))
+	}
+
+	d := world.NewDungeon(width, height, rng)
+	d.Prefabs = opts.Prefabs
+	d.Generate(ctx)
+	return d
+}
+
+// FromLayout builds a floor from rows of characters: '#' is wall, '>' the
+// stairs down and anything else floor. Rows must all be the same width.
+func FromLayout(rows []string) *Dungeon {
+	return world.NewDungeonFromLayout(rows)
+}
+
+// ParsePrefab builds a prefab room from rows of layout characters: '#'
+// wall, '.' floor, and 'E', 'C' and 'T' marking enemy, chest and trap
+// spots.
+func ParsePrefab(id string, rows []string) (Prefab, error) {
+	return world.ParsePrefab(id, rows)
+}
+
+// Rows returns the floor's tiles as one string per row.
+func Rows(d *Dungeon) []string {
+	rows := make([]string, 0, len(d.Tiles))
+	for _, row := range d.Tiles {
+		runes := make([]rune, len(row))
+		for x, tile := range row {
+			runes[x] = tile.Rune()
+		}
+		rows = append(rows, string(runes))
+	}
+	return rows
+}
+
+// WriteText writes the floor's tiles to w, one line per row.
+func WriteText(w io.Writer, d *Dungeon) error {
+	_, err := io.WriteString(w, strings.Join(Rows(d), "\n")+"\n")
+	return err
+}
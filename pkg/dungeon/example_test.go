@@ -0,0 +1,45 @@
+package dungeon_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/samdwyer/dungeonband/pkg/dungeon"
+)
+
+func ExampleGenerate() {
+	d := dungeon.Generate(context.Background(), dungeon.Options{Seed: 42})
+	fmt.Println(d.Width, d.Height, len(d.Rooms) > 1)
+	fmt.Println(d.GetTile(d.StairsX, d.StairsY) == dungeon.TileStairsDown)
+	// Output:
+	// 80 24 true
+	// true
+}
+
+func ExampleWriteText() {
+	d := dungeon.FromLayout([]string{
+		"#######",
+		"#.....#",
+		"#....>#",
+		"#######",
+	})
+	if err := dungeon.WriteText(os.Stdout, d); err != nil {
+		fmt.Println(err)
+	}
+	// Output:
+	// #######
+	// #.....#
+	// #....>#
+	// #######
+}
+
+func ExampleRows() {
+	d := dungeon.Generate(context.Background(), dungeon.Options{Width: 40, Height: 20, Seed: 7})
+	rows := dungeon.Rows(d)
+	fmt.Println(len(rows), len(rows[0]))
+	fmt.Println(rows[0])
+	// Output:
+	// 20 40
+	// ########################################
+}